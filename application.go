@@ -4,15 +4,16 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -20,7 +21,6 @@ import (
 
 	"github.com/go-errors/errors"
 	"github.com/go-zoox/cache"
-	"github.com/go-zoox/chalk"
 	"github.com/go-zoox/core-utils/cast"
 	"github.com/go-zoox/core-utils/regexp"
 	"github.com/go-zoox/i18n"
@@ -28,16 +28,33 @@ import (
 	"github.com/go-zoox/kv"
 	"github.com/go-zoox/logger"
 	"github.com/go-zoox/websocket"
+	"github.com/go-zoox/zoox/components/application/audit"
 	"github.com/go-zoox/zoox/components/application/cmd"
 	"github.com/go-zoox/zoox/components/application/cron"
 	"github.com/go-zoox/zoox/components/application/debug"
 	"github.com/go-zoox/zoox/components/application/env"
+	"github.com/go-zoox/zoox/components/application/errreport"
+	"github.com/go-zoox/zoox/components/application/events"
+	"github.com/go-zoox/zoox/components/application/flags"
+	"github.com/go-zoox/zoox/components/application/images"
 	"github.com/go-zoox/zoox/components/application/jobqueue"
+	"github.com/go-zoox/zoox/components/application/jsonrpcclient"
+	"github.com/go-zoox/zoox/components/application/keyring"
+	"github.com/go-zoox/zoox/components/application/lock"
+	"github.com/go-zoox/zoox/components/application/logsink"
+	"github.com/go-zoox/zoox/components/application/migrate"
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/go-zoox/zoox/components/application/redirect"
 	"github.com/go-zoox/zoox/components/application/runtime"
+	"github.com/go-zoox/zoox/components/application/scan"
+	"github.com/go-zoox/zoox/components/application/secrets"
+	"github.com/go-zoox/zoox/components/application/storage"
+	"github.com/go-zoox/zoox/components/application/tenancy"
+	"github.com/go-zoox/zoox/components/application/tiered"
+	"github.com/go-zoox/zoox/components/application/webhook"
 	"github.com/go-zoox/zoox/config"
 
 	"github.com/go-zoox/mq"
-	"github.com/go-zoox/pubsub"
 
 	"github.com/go-zoox/kv/redis"
 )
@@ -68,7 +85,9 @@ type Application struct {
 	//
 	notfound HandlerFunc
 	//
-	cache cache.Cache
+	errorHandler ErrorHandlerFunc
+	//
+	cache tiered.Cache
 	//
 	cron  cron.Cron
 	queue jobqueue.JobQueue
@@ -87,9 +106,67 @@ type Application struct {
 
 	//
 	jsonrpcRegistry jsonrpcServer.Server
+	// jsonrpcClients are the clients of app.JSONRPCClient, key: url
+	jsonrpcClients sync.Map
+	// jsonrpcMethods are described by app.DescribeJSONRPC
+	jsonrpcMethods []jsonrpcclient.Method
+	// services are registered by app.RegisterService, key: name
+	services sync.Map
+	//
+	jsonCodec    JSONCodec
+	msgpackCodec Codec
+	cborCodec    Codec
 	//
 	pubsub pubsub.PubSub
 	mq     mq.MQ
+	//
+	flags      flags.Flags
+	flagTarget func(ctx *Context) *flags.Target
+	//
+	audit    audit.Audit
+	webhooks webhook.Webhooks
+	tenancy  tenancy.Tenancy
+	locker   lock.Locker
+	events   events.Bus
+	bridge   *Bridge
+	secrets  secrets.Secrets
+	keyring  keyring.Keyring
+	images   images.Images
+	scanner  scan.Scanner
+	storage  storage.Storage
+	logsink  logsink.Writer
+	// keyringMu guards keyring, the errors of app.Keyring are not cached
+	keyringMu sync.Mutex
+	//
+	errorReporter errreport.Reporter
+	// errorReportScrubber scrubs the events before they are reported
+	errorReportScrubber func(ctx *Context, event *errreport.Event) *errreport.Event
+	//
+	database    *sql.DB
+	migrations  *migrate.Migrator
+	modelLoader ModelLoader
+	// examples are the route examples, key: METHOD path
+	examples map[string]*RouteExample
+	// rawResponses are the routes opted out of the response envelope, key: METHOD path
+	rawResponses map[string]bool
+	// redirects are the redirect and rewrite rules evaluated before routing
+	redirects *redirect.Table
+	// trustedProxies are parsed from config trusted_proxies
+	trustedProxies []*net.IPNet
+	//
+	maintenance atomic.Pointer[maintenance]
+	// logLevel is changed at runtime by app.SetLogLevel
+	logLevel  atomic.Pointer[LogLevelState]
+	logLevels logLevels
+	// contextPool recycles the contexts of ServeHTTP
+	contextPool sync.Pool
+	// groupMiddlewareCache caches the middleware chains by the matched groups (bitmask),
+	//	it is reset when the middlewares of any group are changed.
+	groupMiddlewareCache atomic.Pointer[sync.Map]
+	// groupMatchCache caches the matched groups (bitmask) of the path, it is reset when groups are added.
+	groupMatchCache *lru[string, uint64]
+	// conns tracks the server connection states
+	conns connTracker
 
 	//
 	Config config.Config
@@ -115,11 +192,49 @@ type Application struct {
 		mq     sync.Once
 		//
 		cmd sync.Once
+		//
+		flags    sync.Once
+		audit    sync.Once
+		webhooks sync.Once
+		tenancy  sync.Once
+		locker   sync.Once
+		events   sync.Once
+		bridge   sync.Once
+		secrets  sync.Once
+		images   sync.Once
+		scanner  sync.Once
+		storage  sync.Once
+		logsink  sync.Once
+		//
+		errorReporter sync.Once
+		//
+		database   sync.Once
+		migrations sync.Once
 	}
 
 	// tls cert loader
 	tlsCertLoader func(sni string) (key, cert string, err error)
 
+	// hooks are the request lifecycle hooks
+	hooks requestHooks
+
+	// isolated is set by NewIsolated
+	isolated bool
+
+	// workers are started by app.RunWorker
+	workers workers
+
+	// shutdown is the state of app.Shutdown
+	shutdown shutdownState
+
+	// defaults are the default middlewares and groups applied, see WithoutDefaults
+	defaults struct {
+		disabled    bool
+		skip        map[string]bool
+		middlewares []string
+		groups      []string
+	}
+
 	// @TODO
 	lifecycle struct {
 		// beforeReady
@@ -130,28 +245,28 @@ type Application struct {
 }
 
 // New is the constructor of zoox.Application.
-func New() *Application {
+func New(opts ...Option) *Application {
 	app := &Application{
 		router:        newRouter(),
 		templateFuncs: template.FuncMap{},
 		notfound:      NotFound(),
 	}
 
+	app.contextPool.New = func() any {
+		return &Context{}
+	}
+	app.resetGroupMiddlewareCache()
+	app.groupMatchCache = newLRU[string, uint64](DefaultGroupMatchCacheSize)
+
 	app.RouterGroup = newRouterGroup(app, "")
 	app.groups = []*RouterGroup{app.RouterGroup}
 
-	// global middlewares
-	for _, mf := range DefaultMiddlewares {
-		// fmt.Println("zoox: default middleware:", name)
-		mf(app)
+	for _, opt := range opts {
+		opt(app)
 	}
 
-	// global groups
-	for gprefix, gfn := range DefaultGroupsFns {
-		// fmt.Println("zoox: default group:", gprefix)
-		g := app.Group(gprefix)
-		gfn(g)
-	}
+	// default middlewares and groups, see RegisterDefault
+	app.applyDefaults()
 
 	return app
 }
@@ -161,6 +276,43 @@ func (app *Application) NotFound(h HandlerFunc) {
 	app.notfound = h
 }
 
+// OnError sets the app-level error handler, used by ctx.HandleError, typed handlers and recovery.
+func (app *Application) OnError(h ErrorHandlerFunc) {
+	app.errorHandler = h
+}
+
+// notFoundHandler returns the 404 handler of the path, the most specific group wins.
+func (app *Application) notFoundHandler(path string) HandlerFunc {
+	var matched *RouterGroup
+	for _, g := range app.groups {
+		if g.notfound != nil && g.hasPathPrefix(path) && (matched == nil || len(g.prefix) > len(matched.prefix)) {
+			matched = g
+		}
+	}
+
+	if matched != nil {
+		return matched.notfound
+	}
+
+	return app.notfound
+}
+
+// errorHandlerOf returns the error handler of the path, the most specific group wins, nil if not set.
+func (app *Application) errorHandlerOf(path string) ErrorHandlerFunc {
+	var matched *RouterGroup
+	for _, g := range app.groups {
+		if g.errorHandler != nil && g.hasPathPrefix(path) && (matched == nil || len(g.prefix) > len(matched.prefix)) {
+			matched = g
+		}
+	}
+
+	if matched != nil {
+		return matched.errorHandler
+	}
+
+	return app.errorHandler
+}
+
 // Fallback is the default handler for all requests.
 func (app *Application) Fallback(h HandlerFunc) {
 	app.NotFound(h)
@@ -172,10 +324,39 @@ func (app *Application) applyDefaultConfig() error {
 		return err
 	}
 
+	// resolve the secret references, such as secret://vault/app#secret_key
+	if err := app.Secrets().ResolveStruct(context.Background(), &app.Config); err != nil {
+		return err
+	}
+
 	if app.Config.SecretKey == "" {
 		app.Config.SecretKey = DefaultSecretKey
 	}
 
+	if err := app.checkEncryptionConfig(); err != nil {
+		return err
+	}
+
+	trustedProxies, err := parseTrustedProxies(app.Config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	app.trustedProxies = trustedProxies
+
+	for _, rule := range app.Config.Redirects {
+		if err := app.AddRedirect(&redirect.Rule{
+			From:          rule.From,
+			To:            rule.To,
+			Regex:         rule.Regex,
+			Status:        rule.Status,
+			Rewrite:       rule.Rewrite,
+			PreserveQuery: rule.PreserveQuery,
+			Host:          rule.Host,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if app.Config.Protocol == "" {
 		app.Config.Protocol = "http"
 	}
@@ -196,6 +377,30 @@ func (app *Application) applyDefaultConfig() error {
 		app.Config.Session.MaxAge = DefaultSessionMaxAge
 	}
 
+	if app.Config.ReadTimeout == 0 {
+		app.Config.ReadTimeout = DefaultReadTimeout
+	}
+
+	if app.Config.ReadHeaderTimeout == 0 {
+		app.Config.ReadHeaderTimeout = DefaultReadHeaderTimeout
+	}
+
+	if app.Config.WriteTimeout == 0 {
+		app.Config.WriteTimeout = DefaultWriteTimeout
+	}
+
+	if app.Config.IdleTimeout == 0 {
+		app.Config.IdleTimeout = DefaultIdleTimeout
+	}
+
+	if app.Config.MaxHeaderBytes == 0 {
+		app.Config.MaxHeaderBytes = DefaultMaxHeaderBytes
+	}
+
+	if app.Config.Name == "" {
+		app.Config.Name = DefaultName
+	}
+
 	if app.Config.Cache.Config == nil {
 		if app.Config.Redis.Host != "" {
 			app.Config.Cache = kv.Config{
@@ -205,7 +410,7 @@ func (app *Application) applyDefaultConfig() error {
 					Port:     app.Config.Redis.Port,
 					Password: app.Config.Redis.Password,
 					DB:       app.Config.Redis.DB,
-					Prefix:   "eunomia",
+					Prefix:   app.cachePrefix(),
 				},
 			}
 		}
@@ -215,6 +420,11 @@ func (app *Application) applyDefaultConfig() error {
 }
 
 func (app *Application) applyDefaultConfigFromEnv() error {
+	// the isolated apps do not read the environment variables, see NewIsolated
+	if app.isolated {
+		return nil
+	}
+
 	if app.Config.Port == 0 && os.Getenv(BuiltInEnvPort) != "" {
 		app.Config.Port = cast.ToInt(os.Getenv(BuiltInEnvPort))
 	}
@@ -223,10 +433,22 @@ func (app *Application) applyDefaultConfigFromEnv() error {
 		app.Config.HTTPSPort = cast.ToInt(os.Getenv(BuiltInEnvHTTPSPort))
 	}
 
+	if app.Config.Name == "" && os.Getenv(BuiltInEnvName) != "" {
+		app.Config.Name = os.Getenv(BuiltInEnvName)
+	}
+
+	if app.Config.Namespace == "" && os.Getenv(BuiltInEnvNamespace) != "" {
+		app.Config.Namespace = os.Getenv(BuiltInEnvNamespace)
+	}
+
 	if app.Config.LogLevel == "" && os.Getenv(BuiltInEnvLogLevel) != "" {
 		app.Config.LogLevel = os.Getenv(BuiltInEnvLogLevel)
 	}
 
+	if !app.Config.DisableBanner && os.Getenv(BuiltInEnvDisableBanner) == "true" {
+		app.Config.DisableBanner = true
+	}
+
 	if app.Config.SecretKey == "" && os.Getenv(BuiltInEnvSecretKey) != "" {
 		app.Config.SecretKey = os.Getenv(BuiltInEnvSecretKey)
 	}
@@ -255,6 +477,18 @@ func (app *Application) applyDefaultConfigFromEnv() error {
 		app.Config.Redis.DB = cast.ToInt(os.Getenv(BuiltInEnvRedisDB))
 	}
 
+	if app.Config.Database.Driver == "" && os.Getenv(BuiltInEnvDatabaseDriver) != "" {
+		app.Config.Database.Driver = os.Getenv(BuiltInEnvDatabaseDriver)
+	}
+
+	if app.Config.Database.DSN == "" && os.Getenv(BuiltInEnvDatabaseDSN) != "" {
+		app.Config.Database.DSN = os.Getenv(BuiltInEnvDatabaseDSN)
+	}
+
+	if !app.Config.Database.AutoMigrate && os.Getenv(BuiltInEnvDatabaseAutoMigrate) == "true" {
+		app.Config.Database.AutoMigrate = true
+	}
+
 	if !app.Config.Monitor.Prometheus.Enabled && os.Getenv(BuiltInEnvMonitorPrometheusEnabled) == "true" {
 		app.Config.Monitor.Prometheus.Enabled = true
 	}
@@ -295,8 +529,19 @@ func (app *Application) applyDefaultConfigFromEnv() error {
 //			Unix Domain Socket:
 //				/tmp/xxx.sock: Run("unix:///tmp/xxx.sock")
 func (app *Application) Run(addr ...string) (err error) {
-	// show banner
-	app.showBanner()
+	// print routes only, such as zoox routes
+	if file := os.Getenv(EnvRoutesOutput); file != "" {
+		return app.writeRoutes(file)
+	}
+
+	// run migrations only, such as zoox migrate
+	if command := os.Getenv(EnvMigrate); command != "" {
+		if err := app.applyDefaultConfig(); err != nil {
+			return fmt.Errorf("failed to apply default config: %v", err)
+		}
+
+		return app.runMigrateCommand(command, os.Getenv(EnvMigrateOutput))
+	}
 
 	// parse addr
 	if err := app.parseAddr(addr...); err != nil {
@@ -308,27 +553,85 @@ func (app *Application) Run(addr ...string) (err error) {
 		return fmt.Errorf("failed to apply default config: %v", err)
 	}
 
+	// show banner, after the config is applied for the template variables
+	app.showBanner()
+
 	// show app info in debug mode
 	app.showAppInfo()
 
 	// show runtime info
-	app.showRuntimeInfo()
+	if !app.Config.DisableBanner {
+		app.showRuntimeInfo()
+	}
 
 	// before ready
 	if app.lifecycle.beforeReady != nil {
 		app.lifecycle.beforeReady()
 	}
+
+	// refresh the secrets for rotation
+	if app.Config.Secrets.RefreshInterval > 0 {
+		go app.Secrets().Watch(context.Background(), app.Config.Secrets.RefreshInterval)
+	}
+
+	// apply pending migrations if database.auto_migrate is enabled
+	if err := app.AutoMigrate(); err != nil {
+		return err
+	}
+
+	// resolve middleware pipelines with the final config
+	if err := app.resolveMiddlewares(); err != nil {
+		return err
+	}
+
 	// before destroy
-	defer func() {
-		if app.lifecycle.beforeDestroy != nil {
-			app.lifecycle.beforeDestroy()
-		}
-	}()
+	defer app.destroy()
 
 	// serve
 	return app.serve()
 }
 
+// destroy stops the components and flushes the pending writes, after the servers (or workers) are stopped.
+func (app *Application) destroy() {
+	if app.lifecycle.beforeDestroy != nil {
+		app.lifecycle.beforeDestroy()
+	}
+
+	// stop cron, drain job queue, kill child processes and run shutdown hooks
+	app.stopComponents(context.Background())
+
+	// wait for running async event handlers, which may emit audit entries and webhooks
+	if app.events != nil {
+		app.events.Wait()
+	}
+
+	// flush pending audit entries
+	if app.audit != nil {
+		app.audit.Close()
+	}
+
+	// flush pending error reports
+	if app.errorReporter != nil {
+		app.errorReporter.Flush(5 * time.Second)
+	}
+
+	// flush pending request logs
+	if app.logsink != nil {
+		app.logsink.Close()
+	}
+
+	// stop pending webhook retries
+	if app.webhooks != nil {
+		app.webhooks.Close()
+	}
+
+	if app.database != nil {
+		app.database.Close()
+	}
+
+	app.stopped()
+}
+
 // Listen defines the method to start the server
 //
 //	just like Run, but the port is required
@@ -336,10 +639,6 @@ func (app *Application) Listen(port int) error {
 	return app.Run(fmt.Sprintf(":%d", port))
 }
 
-func (app *Application) createContext(w http.ResponseWriter, req *http.Request) *Context {
-	return newContext(app, w, req)
-}
-
 // SetTemplates set the template
 func (app *Application) SetTemplates(dir string, fns ...template.FuncMap) {
 	if len(fns) > 0 && fns[0] != nil {
@@ -349,7 +648,7 @@ func (app *Application) SetTemplates(dir string, fns ...template.FuncMap) {
 	app.templates = template.Must(template.New("").Funcs(app.templateFuncs).ParseGlob(dir + "/*"))
 }
 
-// SetBanner sets the banner
+// SetBanner sets the banner, it is a text/template with the variables, see BannerData.
 func (app *Application) SetBanner(banner string) {
 	app.Config.Banner = banner
 }
@@ -364,19 +663,114 @@ func (app *Application) SetBeforeDestroy(fn func()) {
 	app.lifecycle.beforeDestroy = fn
 }
 
+// ServeHTTP serves the request, the context is recycled after the request is handled,
+// so it must not be used by goroutines outliving the handler, use ctx.Copy() instead.
 func (app *Application) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	ctx := app.createContext(w, req)
+	ctx := app.contextPool.Get().(*Context)
+	ctx.reset(app, w, req)
+
+	app.dispatch(ctx)
+
+	app.contextPool.Put(ctx)
+}
+
+// dispatch dispatches the request, with the lifecycle hooks if any.
+func (app *Application) dispatch(ctx *Context) {
+	if !app.hooks.empty() {
+		defer app.finishHooks(ctx, time.Now())
+
+		if !app.runRequestHooks(ctx) {
+			return
+		}
+	}
+
+	if app.redirects != nil && app.handleRedirect(ctx) {
+		return
+	}
+
+	// copied, route handlers are appended by router
+	ctx.handlers = append(ctx.handlers, app.groupMiddlewares(ctx.Path)...)
+	app.router.handle(ctx)
+}
+
+// groupMiddlewares returns the middleware chain of the groups matching the path.
+func (app *Application) groupMiddlewares(path string) []HandlerFunc {
+	// too many groups for bitmask, no cache
+	if len(app.groups) > 64 {
+		var middlewares []HandlerFunc
+		for _, group := range app.groups {
+			if ok := group.matchPath(path); ok {
+				middlewares = append(middlewares, group.middlewares...)
+			}
+		}
+
+		// maintenance mode, after middlewares
+		return append(middlewares, app.maintenanceHandler)
+	}
+
+	matched, ok := app.groupMatchCache.Get(path)
+	if !ok {
+		for i, group := range app.groups {
+			if ok := group.matchPath(path); ok {
+				matched |= 1 << i
+			}
+		}
+
+		app.groupMatchCache.Set(path, matched)
+	}
+
+	cache := app.groupMiddlewareCache.Load()
+	if middlewares, ok := cache.Load(matched); ok {
+		return middlewares.([]HandlerFunc)
+	}
 
 	var middlewares []HandlerFunc
+	for i, group := range app.groups {
+		if matched&(1<<i) != 0 {
+			middlewares = append(middlewares, group.middlewares...)
+		}
+	}
 
+	// maintenance mode, after middlewares
+	middlewares = append(middlewares, app.maintenanceHandler)
+
+	cache.Store(matched, middlewares)
+	return middlewares
+}
+
+// resetGroupMiddlewareCache resets the cached middleware chains.
+func (app *Application) resetGroupMiddlewareCache() {
+	app.groupMiddlewareCache.Store(&sync.Map{})
+}
+
+// resolveMiddlewares resolves the middleware pipelines of all groups,
+// it returns the error of the ordering constraints, such as cycles or unknown names.
+func (app *Application) resolveMiddlewares() error {
 	for _, group := range app.groups {
-		if ok := group.matchPath(ctx.Path); ok {
-			middlewares = append(middlewares, group.middlewares...)
+		if err := group.checkMiddlewareNames(); err != nil {
+			return err
+		}
+
+		if err := group.resolveMiddlewares(); err != nil {
+			return err
 		}
 	}
 
-	ctx.handlers = middlewares
-	app.router.handle(ctx)
+	app.Logger().Debugf("[middleware] pipeline: %s", strings.Join(app.Middlewares(), " -> "))
+	return nil
+}
+
+// middlewareGroup returns the group registering the named middleware.
+func (app *Application) middlewareGroup(name string) *RouterGroup {
+	for _, group := range app.groups {
+		for _, e := range group.entries {
+			if e.name == name {
+				return group
+			}
+		}
+	}
+
+	return nil
 }
 
 // SetTLSCertLoader set the tls cert loader
@@ -384,6 +778,41 @@ func (app *Application) SetTLSCertLoader(loader func(sni string) (key, cert stri
 	app.tlsCertLoader = loader
 }
 
+// Namespace returns the namespaced key of shared storages, such as app.Namespace("cache") => myapp:production:cache,
+// the namespace is app.Config.Namespace, default: <name>:<mode>.
+func (app *Application) Namespace(keys ...string) string {
+	namespace := app.Config.Namespace
+	if namespace == "" {
+		name := app.Config.Name
+		if name == "" {
+			name = DefaultName
+		}
+
+		mode := app.Env().Get(BuiltInEnvMode)
+		if mode == "" {
+			mode = "development"
+		}
+
+		namespace = fmt.Sprintf("%s:%s", name, mode)
+	}
+
+	if len(keys) == 0 {
+		return namespace
+	}
+
+	return namespace + ":" + strings.Join(keys, ":")
+}
+
+// cachePrefix returns the redis key prefix of the cache, app.Namespace("cache") if the namespace is configured,
+// otherwise the legacy prefix, so that the cached entries survive the upgrade.
+func (app *Application) cachePrefix() string {
+	if app.Config.Namespace == "" {
+		return LegacyCachePrefix
+	}
+
+	return app.Namespace("cache")
+}
+
 // IsProd returns true if the app is in production mode.
 func (app *Application) IsProd() bool {
 	return app.Env().Get("MODE") == "production"
@@ -399,13 +828,22 @@ func (app *Application) JSONRPCRegistry() jsonrpcServer.Server {
 }
 
 // PubSub get a new PubSub handler.
+//
+//	redis backend is used if redis config is provided, otherwise in-process memory backend is used.
+//	other backends (NATS, Kafka, ...) can be set by app.SetPubSub.
 func (app *Application) PubSub() pubsub.PubSub {
-	if app.Config.Redis.Host == "" {
-		panic("redis config is required for pubsub in application")
-	}
-
 	app.once.pubsub.Do(func() {
-		app.pubsub = pubsub.New(&pubsub.Config{
+		if app.pubsub != nil {
+			return
+		}
+
+		if app.Config.Redis.Host == "" {
+			app.Logger().Debugf("[pubsub] redis config is not provided, use in-process memory backend")
+			app.pubsub = pubsub.NewMemory()
+			return
+		}
+
+		app.pubsub = pubsub.NewRedis(&pubsub.RedisConfig{
 			RedisHost:     app.Config.Redis.Host,
 			RedisPort:     app.Config.Redis.Port,
 			RedisDB:       app.Config.Redis.DB,
@@ -417,6 +855,11 @@ func (app *Application) PubSub() pubsub.PubSub {
 	return app.pubsub
 }
 
+// SetPubSub sets the pubsub backend, such as NATS or Kafka adapters.
+func (app *Application) SetPubSub(ps pubsub.PubSub) {
+	app.pubsub = ps
+}
+
 // MQ get a new MQ handler.
 func (app *Application) MQ() mq.MQ {
 	if app.Config.Redis.Host == "" {
@@ -436,15 +879,200 @@ func (app *Application) MQ() mq.MQ {
 	return app.mq
 }
 
-// Cache ...
+// Cache returns the cache, with the process-local tier in front if local_cache is enabled,
+// local tiers of instances are invalidated via pubsub if redis is configured.
+//
+// The cache implements tiered.Cache, the tags and GetOrSet are used with tiered.From(app.Cache()).
 func (app *Application) Cache() cache.Cache {
+	return app.tieredCache()
+}
+
+func (app *Application) tieredCache() tiered.Cache {
 	app.once.cache.Do(func() {
-		app.cache = cache.New(&app.Config.Cache)
+		if app.cache != nil {
+			return
+		}
+
+		cfg := &tiered.Config{
+			Local:     app.Config.LocalCache.Enabled,
+			LocalSize: app.Config.LocalCache.Size,
+			LocalTTL:  app.Config.LocalCache.TTL,
+			Topic:     app.Namespace("cache", "invalidate"),
+		}
+		if cfg.Local && app.Config.Redis.Host != "" {
+			cfg.PubSub = app.PubSub()
+		}
+
+		app.cache = tiered.New(cache.New(&app.Config.Cache), cfg)
+		if namespaces := app.Config.Encryption.CacheNamespaces; len(namespaces) != 0 {
+			app.cache = tiered.Encrypted(app.cache, &appKeyring{app: app}, namespaces...)
+		}
 	})
 
 	return app.cache
 }
 
+// Flags returns the feature flags, stored in cache (redis) if redis is configured, otherwise in memory.
+func (app *Application) Flags() flags.Flags {
+	app.once.flags.Do(func() {
+		if app.flags != nil {
+			return
+		}
+
+		if app.Config.Redis.Host == "" {
+			app.flags = flags.New(flags.NewMemory())
+			return
+		}
+
+		app.flags = flags.New(flags.NewCache(app.Cache()))
+	})
+
+	return app.flags
+}
+
+// SetFlags sets the feature flags, such as a custom store.
+func (app *Application) SetFlags(f flags.Flags) {
+	app.flags = f
+}
+
+// SetFlagTarget sets the function to resolve the flag target of the request, such as the current user.
+// Default: the client ip.
+func (app *Application) SetFlagTarget(fn func(ctx *Context) *flags.Target) {
+	app.flagTarget = fn
+}
+
+// Audit returns the audit logger, entries are written to the app logger if not set by app.SetAudit.
+func (app *Application) Audit() audit.Audit {
+	app.once.audit.Do(func() {
+		if app.audit != nil {
+			return
+		}
+
+		app.audit = audit.New(&audit.Config{
+			Sinks: []audit.Sink{
+				audit.SinkFunc(func(entries []*audit.Entry) error {
+					for _, entry := range entries {
+						app.Logger().Infof("[audit] %s %s (actor: %v, ip: %s, request_id: %s) %v", entry.Action, entry.Target, entry.Actor, entry.IP, entry.RequestID, entry.Metadata)
+					}
+
+					return nil
+				}),
+			},
+			OnError: func(sink audit.Sink, entries []*audit.Entry, err error) {
+				app.Logger().Errorf("[audit] failed to write %d entries: %s", len(entries), err)
+			},
+		})
+	})
+
+	return app.audit
+}
+
+// SetAudit sets the audit logger with sinks, such as:
+//
+//	app.SetAudit(audit.New(&audit.Config{
+//		Sinks: []audit.Sink{audit.NewWebhookSink(&audit.WebhookConfig{URL: "https://audit.example.com"})},
+//	}))
+func (app *Application) SetAudit(a audit.Audit) {
+	app.audit = a
+}
+
+// Webhooks returns the outbound webhooks, deliveries are stored in cache (redis) if redis is configured, otherwise in memory.
+func (app *Application) Webhooks() webhook.Webhooks {
+	app.once.webhooks.Do(func() {
+		if app.webhooks != nil {
+			return
+		}
+
+		store := webhook.NewMemory()
+		if app.Config.Redis.Host != "" {
+			store = webhook.NewCache(app.Cache())
+		}
+
+		app.webhooks = webhook.New(&webhook.Config{
+			Store: store,
+			OnFailed: func(delivery *webhook.Delivery) {
+				app.Logger().Errorf("[webhook] delivery(%s) of event(%s) to endpoint(%s) failed after %d attempts: %s", delivery.ID, delivery.Event, delivery.EndpointID, delivery.Attempts, delivery.LastError)
+			},
+		})
+	})
+
+	return app.webhooks
+}
+
+// SetWebhooks sets the outbound webhooks, such as with custom retry config.
+func (app *Application) SetWebhooks(w webhook.Webhooks) {
+	app.webhooks = w
+}
+
+// Tenancy returns the tenant resolver, no tenant is resolved unless set by app.SetTenancy.
+func (app *Application) Tenancy() tenancy.Tenancy {
+	app.once.tenancy.Do(func() {
+		if app.tenancy != nil {
+			return
+		}
+
+		app.tenancy = tenancy.New()
+	})
+
+	return app.tenancy
+}
+
+// SetTenancy sets the tenant resolver, such as resolving from subdomain with lazily loaded tenant config:
+//
+//	app.SetTenancy(tenancy.New(&tenancy.Config{
+//		Resolvers: []tenancy.Resolver{tenancy.FromSubdomain("example.com")},
+//		Loader:    loadTenantConfig,
+//	}))
+func (app *Application) SetTenancy(t tenancy.Tenancy) {
+	app.tenancy = t
+}
+
+// Locker returns the distributed locker, locks are stored in redis if redis is configured, otherwise in memory.
+func (app *Application) Locker() lock.Locker {
+	app.once.locker.Do(func() {
+		if app.locker != nil {
+			return
+		}
+
+		backend := lock.NewMemory()
+		if app.Config.Redis.Host != "" {
+			backend = lock.NewRedis(&lock.RedisConfig{
+				Host:     app.Config.Redis.Host,
+				Port:     app.Config.Redis.Port,
+				DB:       app.Config.Redis.DB,
+				Username: app.Config.Redis.Username,
+				Password: app.Config.Redis.Password,
+			})
+		}
+
+		app.locker = lock.New(&lock.Config{
+			Backend:   backend,
+			Namespace: app.Namespace("lock"),
+		})
+	})
+
+	return app.locker
+}
+
+// SetLocker sets the distributed locker, such as with a custom backend.
+func (app *Application) SetLocker(l lock.Locker) {
+	app.locker = l
+}
+
+// Lock acquires the distributed lock, blocks until acquired, the lock is renewed until released,
+// such as serializing cron jobs, migrations and cache rebuilds across instances:
+//
+//	l, err := app.Lock("rebuild-cache", 30*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer l.Release()
+//
+//	rebuild(l.Context())
+func (app *Application) Lock(name string, ttl time.Duration) (lock.Lock, error) {
+	return app.Locker().Acquire(context.Background(), name, ttl)
+}
+
 // Cron ...
 func (app *Application) Cron() cron.Cron {
 	app.once.cron.Do(func() {
@@ -466,7 +1094,8 @@ func (app *Application) JobQueue() jobqueue.JobQueue {
 // Cmd ...
 func (app *Application) Cmd() cmd.Cmd {
 	app.once.cmd.Do(func() {
-		app.cmd = cmd.New(context.Background())
+		// the child processes are killed on shutdown
+		app.cmd = cmd.New(app.lifecycleContext())
 	})
 
 	return app.cmd
@@ -474,7 +1103,7 @@ func (app *Application) Cmd() cmd.Cmd {
 
 // I18n ...
 func (app *Application) I18n() i18n.I18n {
-	app.once.queue.Do(func() {
+	app.once.i18n.Do(func() {
 		app.i18n = i18n.New()
 	})
 
@@ -493,6 +1122,10 @@ func (app *Application) Env() env.Env {
 // Logger ...
 func (app *Application) Logger() *logger.Logger {
 	app.once.logger.Do(func() {
+		if app.logger != nil {
+			return
+		}
+
 		app.logger = logger.New(func(opt *logger.Option) {
 			// fmt.Println("app.Config.LogLevel:", app.Config.LogLevel)
 			opt.Level = app.Config.LogLevel
@@ -564,18 +1197,6 @@ func (app *Application) AddressHTTPSForLog() string {
 	return fmt.Sprintf("%s:%d", app.Config.Host, app.Config.HTTPSPort)
 }
 
-// showBanner ...
-func (app *Application) showBanner() {
-	// allow custom banner
-	if app.Config.Banner != "" {
-		log.Println(app.Config.Banner)
-		return
-	}
-
-	// banner
-	log.Printf(banner, chalk.Green("v"+Version), chalk.Blue(website))
-}
-
 // parseAddr ...
 func (app *Application) parseAddr(addr ...string) error {
 	var addrX string
@@ -634,35 +1255,97 @@ func (app *Application) showRuntimeInfo() {
 
 // serve ...
 func (app *Application) serve() error {
-	g, ctx := errgroup.WithContext(context.Background())
+	// windows service
+	if ok, err := app.runService(app.serveWithContext); ok {
+		return err
+	}
+
+	return app.serveWithContext(context.Background(), nil)
+}
+
+// serveWithContext serves until ctx is done, ready is called once all the servers are listening.
+func (app *Application) serveWithContext(parent context.Context, ready func()) error {
+	g, ctx := errgroup.WithContext(app.withShutdown(parent))
+
+	servers := int32(1)
+	if app.Config.HTTPSPort != 0 {
+		servers++
+	}
+	listening := readiness(ctx, servers, func() {
+		// systemd
+		app.notifyReady(ctx)
+
+		if ready != nil {
+			ready()
+		}
+	})
 
 	g.Go(func() error {
-		return app.serveHTTP(ctx)
+		return app.serveHTTP(ctx, listening)
 	})
 
 	g.Go(func() error {
-		return app.serveHTTPS(ctx)
+		return app.serveHTTPS(ctx, listening)
 	})
 
-	return g.Wait()
+	defer app.notifyStopping()
+	if err := g.Wait(); err != nil && !(errors.Is(err, http.ErrServerClosed) && app.isShutdownRequested()) {
+		return err
+	}
+
+	return nil
+}
+
+// newServer creates the http server with the timeouts of config.
+func (app *Application) newServer(addr string) *http.Server {
+	server := &http.Server{
+		ReadTimeout:       app.Config.ReadTimeout,
+		ReadHeaderTimeout: app.Config.ReadHeaderTimeout,
+		WriteTimeout:      app.Config.WriteTimeout,
+		IdleTimeout:       app.Config.IdleTimeout,
+		MaxHeaderBytes:    app.Config.MaxHeaderBytes,
+		ConnState:         app.conns.track,
+		//
+		Addr:    addr,
+		Handler: app,
+	}
+	server.SetKeepAlivesEnabled(!app.Config.DisableKeepAlives)
+
+	return server
+}
+
+// newListener listens the address, or uses the listener inherited with the fd env,
+// connections per client ip are capped if configured.
+func (app *Application) newListener(addr string, fdEnv string) (net.Listener, error) {
+	listener, err := inheritListener(fdEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if listener == nil {
+		listener, err = net.Listen(app.Config.NetworkType, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if app.Config.MaxConnsPerIP > 0 {
+		listener = newPerIPLimitListener(listener, app.Config.MaxConnsPerIP)
+	}
+
+	return listener, nil
 }
 
 // serveHTTP ...
-func (app *Application) serveHTTP(ctx context.Context) error {
-	listener, err := net.Listen(app.Config.NetworkType, app.Address())
+func (app *Application) serveHTTP(ctx context.Context, listening func()) error {
+	listener, err := app.newListener(app.Address(), EnvListenFD)
 	if err != nil {
 		return err
 	}
 	defer listener.Close()
+	listening()
 
-	server := &http.Server{
-		ReadTimeout:  300 * time.Second,
-		WriteTimeout: 300 * time.Second,
-		IdleTimeout:  300 * time.Second,
-		//
-		Addr:    app.Address(),
-		Handler: app,
-	}
+	server := app.newServer(app.Address())
 
 	go func() {
 		<-ctx.Done() // 当上下文被取消时，停止服务器
@@ -680,26 +1363,20 @@ func (app *Application) serveHTTP(ctx context.Context) error {
 }
 
 // serveHTTPS ...
-func (app *Application) serveHTTPS(ctx context.Context) error {
+func (app *Application) serveHTTPS(ctx context.Context, listening func()) error {
 	// if HTTPSPort is not set, ignore set https
 	if app.Config.HTTPSPort == 0 {
 		return nil
 	}
 
-	listener, err := net.Listen(app.Config.NetworkType, app.AddressHTTPS())
+	listener, err := app.newListener(app.AddressHTTPS(), EnvListenFDHTTPS)
 	if err != nil {
 		return err
 	}
 	defer listener.Close()
+	listening()
 
-	server := &http.Server{
-		ReadTimeout:  300 * time.Second,
-		WriteTimeout: 300 * time.Second,
-		IdleTimeout:  300 * time.Second,
-		//
-		Addr:    app.AddressHTTPS(),
-		Handler: app,
-	}
+	server := app.newServer(app.AddressHTTPS())
 
 	go func() {
 		<-ctx.Done() // 当上下文被取消时，停止服务器
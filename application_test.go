@@ -0,0 +1,180 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/cache"
+	"github.com/go-zoox/zoox/components/application/tiered"
+)
+
+type benchmarkWriter struct {
+	header http.Header
+}
+
+func (w *benchmarkWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *benchmarkWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *benchmarkWriter) WriteHeader(code int) {}
+
+func (w *benchmarkWriter) Flush() {}
+
+func newBenchmarkApp() *Application {
+	app := New()
+	app.Use(func(ctx *Context) {
+		ctx.Next()
+	})
+
+	api := app.Group("/api")
+	api.Use(func(ctx *Context) {
+		ctx.Next()
+	})
+	api.Get("/ping", func(ctx *Context) {
+		ctx.String(http.StatusOK, "pong")
+	})
+	api.Get("/users/:id", func(ctx *Context) {
+		ctx.String(http.StatusOK, ctx.Param().Get("id").String())
+	})
+
+	return app
+}
+
+func benchmarkServeHTTP(b *testing.B, path string) {
+	app := newBenchmarkApp()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := &benchmarkWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkServeHTTPStatic(b *testing.B) {
+	benchmarkServeHTTP(b, "/api/ping")
+}
+
+func BenchmarkServeHTTPParam(b *testing.B) {
+	benchmarkServeHTTP(b, "/api/users/1")
+}
+
+func BenchmarkServeHTTPNotFound(b *testing.B) {
+	benchmarkServeHTTP(b, "/not/found")
+}
+
+func TestServeHTTPGroupMiddlewareCache(t *testing.T) {
+	app := New()
+
+	calls := []string{}
+	api := app.Group("/api")
+	api.Get("/ping", func(ctx *Context) {
+		calls = append(calls, "handler")
+		ctx.String(http.StatusOK, "pong")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+
+	// middlewares added later invalidate the cached chains
+	api.Use(func(ctx *Context) {
+		calls = append(calls, "middleware")
+		ctx.Next()
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+
+	if w.Body.String() != "pong" {
+		t.Fatalf("expected pong, got %s", w.Body.String())
+	}
+
+	expected := []string{"handler", "middleware", "handler"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("expected calls %v, got %v", expected, calls)
+		}
+	}
+}
+
+func TestServeHTTPHooksKeepPanicBehavior(t *testing.T) {
+	for _, withHooks := range []bool{false, true} {
+		app := New()
+		app.Get("/panic", func(ctx *Context) {
+			panic("boom")
+		})
+
+		var panicked any
+		var info *ResponseInfo
+		if withHooks {
+			app.OnPanic(func(ctx *Context, err any) {
+				panicked = err
+			})
+			app.OnResponse(func(ctx *Context, i *ResponseInfo) {
+				info = i
+			})
+		}
+
+		func() {
+			defer func() {
+				if err := recover(); err != "boom" {
+					t.Fatalf("hooks(%v): expected the panic to escape, got %v", withHooks, err)
+				}
+			}()
+
+			app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+		}()
+
+		if withHooks {
+			if panicked != "boom" || info == nil || !info.Panicked || info.Status != http.StatusInternalServerError {
+				t.Fatalf("expected the hooks to see the panic, got %v %+v", panicked, info)
+			}
+		}
+	}
+}
+
+func TestCacheTieredFeaturesByTypeAssertion(t *testing.T) {
+	cases := []struct {
+		name string
+		app  *Application
+	}{
+		{"default cache", New()},
+		{"plain cache by WithCache", New(WithCache(cache.New()))},
+	}
+
+	for _, c := range cases {
+		var plain cache.Cache = c.app.Cache()
+		if _, ok := plain.(tiered.Cache); !ok {
+			t.Fatalf("%s: expected app.Cache() to implement tiered.Cache", c.name)
+		}
+
+		calls := 0
+		produce := func() (any, error) {
+			calls++
+			return "value", nil
+		}
+		for i := 0; i < 2; i++ {
+			var value string
+			if err := tiered.From(c.app.Cache()).GetOrSet("key", &value, time.Minute, produce); err != nil || value != "value" {
+				t.Fatalf("%s: expected value, got %q %v", c.name, value, err)
+			}
+		}
+		if calls != 1 {
+			t.Fatalf("%s: expected the producer to be called once, got %d", c.name, calls)
+		}
+
+		ctx := NewContext(c.app, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		if _, ok := ctx.Cache().(tiered.Cache); !ok {
+			t.Fatalf("%s: expected ctx.Cache() to implement tiered.Cache", c.name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package zoox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/go-zoox/chalk"
+)
+
+// BannerData is the variables of the banner template.
+type BannerData struct {
+	Name string
+	// Version is the zoox version.
+	Version string
+	// Addr is the address of the http server, such as http://127.0.0.1:8080.
+	Addr string
+	// Env is the MODE, such as development or production.
+	Env     string
+	Website string
+}
+
+// SetBannerWriter sets where the banner is written, such as a CLI embedding zoox redirects it to its own output,
+// io.Discard silences it, see also config disable_banner.
+func (app *Application) SetBannerWriter(w io.Writer) {
+	app.Config.BannerWriter = w
+}
+
+// showBanner ...
+func (app *Application) showBanner() {
+	if app.Config.DisableBanner {
+		return
+	}
+
+	w := app.Config.BannerWriter
+	if w == nil {
+		w = os.Stderr
+	}
+
+	// allow custom banner
+	if app.Config.Banner != "" {
+		fmt.Fprintln(w, app.renderBanner(app.Config.Banner))
+		return
+	}
+
+	// banner
+	fmt.Fprintf(w, banner, chalk.Green("v"+Version), chalk.Blue(website))
+}
+
+// renderBanner renders the custom banner template, the banner is written as is if it is not a valid template.
+func (app *Application) renderBanner(text string) string {
+	tpl, err := template.New("banner").Parse(text)
+	if err != nil {
+		return text
+	}
+
+	env := app.Env().Get(BuiltInEnvMode)
+	if env == "" {
+		env = "development"
+	}
+
+	addr := "http://" + app.AddressForLog()
+	if app.Config.NetworkType == "unix" {
+		addr = "unix://" + app.Config.UnixDomainSocket
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, &BannerData{
+		Name:    app.Config.Name,
+		Version: Version,
+		Addr:    addr,
+		Env:     env,
+		Website: website,
+	}); err != nil {
+		return text
+	}
+
+	return buf.String()
+}
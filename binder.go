@@ -0,0 +1,33 @@
+package zoox
+
+import (
+	"reflect"
+
+	"github.com/go-zoox/zoox/components/context/binding"
+)
+
+// RegisterBinder registers the parser of the custom type T used in the request structs,
+// such as uuid.UUID, decimal.Decimal and enums, which binds T from query, param, header and form,
+// and validates T decoded from json, yaml and msgpack bodies (formatted by fmt.Stringer or its string kind).
+//
+// It should be called before the app is running.
+//
+// Example:
+//
+//	type Status string
+//
+//	zoox.RegisterBinder(func(raw string) (Status, error) {
+//		switch Status(raw) {
+//		case "active", "disabled":
+//			return Status(raw), nil
+//		}
+//
+//		return "", fmt.Errorf("invalid status: %s", raw)
+//	})
+//
+//	zoox.RegisterBinder(uuid.Parse)
+func RegisterBinder[T any](fn func(raw string) (T, error)) {
+	binding.Register(reflect.TypeOf((*T)(nil)).Elem(), func(raw string) (any, error) {
+		return fn(raw)
+	})
+}
@@ -0,0 +1,303 @@
+package zoox
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultBridgeBufferSize is the buffered messages of each bridged client, the messages are dropped for slow clients.
+var DefaultBridgeBufferSize = 64
+
+// DefaultBridgeKeepAlive is the interval of the keep-alive comments of SSE, and the pings of websocket.
+var DefaultBridgeKeepAlive = 15 * time.Second
+
+var bridgeTopicParamRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// Bridge delivers the messages published on app.PubSub() topics to the connected http clients (SSE or websocket),
+// one subscription of each topic is shared by the clients.
+type Bridge struct {
+	app *Application
+
+	mu     sync.Mutex
+	topics map[string]*bridgeTopic
+}
+
+// BridgeOption is the option of the bridged route.
+type BridgeOption struct {
+	// Auth authorizes the client before subscribing, the error is responded, such as NewHTTPError(401, ...).
+	Auth func(ctx *Context) error
+	// Filter selects the messages delivered to the client, all by default.
+	Filter func(ctx *Context, msg *pubsub.Message) bool
+	// Event is the SSE event name, default: message.
+	Event string
+	// Middlewares are applied before the bridge handler.
+	Middlewares []HandlerFunc
+}
+
+type bridgeTopic struct {
+	cancel  context.CancelFunc
+	clients map[chan *pubsub.Message]struct{}
+}
+
+// Bridge returns the pubsub bridge of the app.
+//
+// Example:
+//
+//	app.Bridge().
+//		TopicToSSE("notifications", "/events/notifications").
+//		TopicToWebSocketRoom("chat:{room}", "/ws/rooms/:room", func(opt *zoox.BridgeOption) {
+//			opt.Auth = authorizeRoom
+//		})
+func (app *Application) Bridge() *Bridge {
+	app.once.bridge.Do(func() {
+		app.bridge = &Bridge{
+			app:    app,
+			topics: map[string]*bridgeTopic{},
+		}
+	})
+
+	return app.bridge
+}
+
+// TopicToSSE streams the messages of the topic as SSE events on the path,
+// the topic can contain route params, such as users:{id} with /events/users/:id.
+func (b *Bridge) TopicToSSE(topic, path string, opts ...func(opt *BridgeOption)) *Bridge {
+	opt := b.option(opts...)
+
+	handlers := append(append([]HandlerFunc{}, opt.Middlewares...), func(ctx *Context) {
+		if !b.authorize(ctx, opt) {
+			return
+		}
+
+		name := resolveBridgeTopic(ctx, topic)
+		messages := b.join(name)
+		defer b.leave(name, messages)
+
+		ticker := time.NewTicker(DefaultBridgeKeepAlive)
+		defer ticker.Stop()
+
+		sse := ctx.SSE()
+		for {
+			select {
+			case <-ctx.Request.Context().Done():
+				return
+			case <-ticker.C:
+				sse.Comment("keep-alive")
+			case msg, ok := <-messages:
+				// the subscription failed
+				if !ok {
+					return
+				}
+
+				if opt.Filter != nil && !opt.Filter(ctx, msg) {
+					continue
+				}
+
+				sse.Event(opt.Event, string(msg.Body))
+			}
+		}
+	})
+
+	b.app.Get(path, handlers...)
+	return b
+}
+
+// TopicToWebSocketRoom delivers the messages of the topic as websocket text frames to the clients on the path,
+// the topic can contain route params for rooms, such as chat:{room} with /ws/rooms/:room.
+// The bridge is one-way, the messages from clients are discarded.
+func (b *Bridge) TopicToWebSocketRoom(topic, path string, opts ...func(opt *BridgeOption)) *Bridge {
+	opt := b.option(opts...)
+
+	handlers := append(append([]HandlerFunc{}, opt.Middlewares...), func(ctx *Context) {
+		if !b.authorize(ctx, opt) {
+			return
+		}
+
+		// the origin is checked to be the same host by default
+		upgrader := &websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+		if err != nil {
+			ctx.RequestLogger().Errorf("[bridge] failed to upgrade: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		name := resolveBridgeTopic(ctx, topic)
+		messages := b.join(name)
+		defer b.leave(name, messages)
+
+		// read until the client is gone
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(DefaultBridgeKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+					return
+				}
+			case msg, ok := <-messages:
+				if !ok {
+					conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to subscribe"))
+					return
+				}
+
+				if opt.Filter != nil && !opt.Filter(ctx, msg) {
+					continue
+				}
+
+				conn.SetWriteDeadline(time.Now().Add(DefaultBridgeKeepAlive))
+				if err := conn.WriteMessage(websocket.TextMessage, msg.Body); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	b.app.Get(path, handlers...)
+	return b
+}
+
+func (b *Bridge) option(opts ...func(opt *BridgeOption)) *BridgeOption {
+	opt := &BridgeOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+	if opt.Event == "" {
+		opt.Event = "message"
+	}
+
+	return opt
+}
+
+func (b *Bridge) authorize(ctx *Context, opt *BridgeOption) bool {
+	if opt.Auth == nil {
+		return true
+	}
+
+	if err := opt.Auth(ctx); err != nil {
+		if _, ok := err.(HTTPError); !ok {
+			err = NewHTTPError(http.StatusUnauthorized, err.Error(), err)
+		}
+
+		ctx.HandleError(err)
+		return false
+	}
+
+	return true
+}
+
+// join adds the client of the topic, the topic is subscribed by the first client.
+// The channel is closed if the subscription fails.
+func (b *Bridge) join(topic string) chan *pubsub.Message {
+	messages := make(chan *pubsub.Message, DefaultBridgeBufferSize)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		t = &bridgeTopic{
+			cancel:  cancel,
+			clients: map[chan *pubsub.Message]struct{}{},
+		}
+		b.topics[topic] = t
+
+		// the redis subscriber blocks until ctx is done, which must not hold the lock of dispatch
+		go func() {
+			err := b.app.PubSub().Subscribe(ctx, topic, func(msg *pubsub.Message) error {
+				b.dispatch(topic, msg)
+				return nil
+			})
+			if err != nil && ctx.Err() == nil {
+				b.app.Logger().Errorf("[bridge] failed to subscribe topic(%s): %s", topic, err)
+				b.fail(topic, t)
+			}
+		}()
+	}
+
+	t.clients[messages] = struct{}{}
+	return messages
+}
+
+// fail closes the clients of the failed subscription, so that they are disconnected.
+func (b *Bridge) fail(topic string, t *bridgeTopic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.topics[topic] == t {
+		delete(b.topics, topic)
+	}
+	t.cancel()
+
+	for messages := range t.clients {
+		close(messages)
+	}
+	t.clients = map[chan *pubsub.Message]struct{}{}
+}
+
+// leave removes the client, the topic is unsubscribed after the last client.
+func (b *Bridge) leave(topic string, messages chan *pubsub.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return
+	}
+
+	if _, ok := t.clients[messages]; !ok {
+		return
+	}
+
+	delete(t.clients, messages)
+	if len(t.clients) == 0 {
+		t.cancel()
+		delete(b.topics, topic)
+	}
+}
+
+func (b *Bridge) dispatch(topic string, msg *pubsub.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return
+	}
+
+	for messages := range t.clients {
+		select {
+		case messages <- msg:
+		default:
+			// slow clients can't block the others
+			b.app.Logger().Warnf("[bridge] drop message of topic(%s) for slow client", topic)
+		}
+	}
+}
+
+// resolveBridgeTopic replaces the {param} of the topic with the route params.
+func resolveBridgeTopic(ctx *Context, topic string) string {
+	return bridgeTopicParamRe.ReplaceAllStringFunc(topic, func(match string) string {
+		return ctx.Param().Get(match[1 : len(match)-1]).String()
+	})
+}
@@ -0,0 +1,119 @@
+package zoox
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/cookie"
+)
+
+// Canary variants
+const (
+	CanaryStable = "stable"
+	CanaryCanary = "canary"
+)
+
+// CanaryOption is the option of canary route.
+type CanaryOption struct {
+	// Header forces the variant, such as X-Canary: always (or true, canary) / never (or false, stable), default: X-Canary.
+	Header string
+	// Cookie is the cookie of sticky assignment, default: zoox_canary.
+	Cookie string
+	// CookieMaxAge is the max age of sticky cookie, default: 24h.
+	CookieMaxAge time.Duration
+	// DisableSticky disables the sticky assignment, each request is assigned by weight.
+	DisableSticky bool
+	// Selector selects the variant by custom rules, such as tenant or user, return empty to fallback to weight.
+	Selector func(ctx *Context) string
+}
+
+// Canary splits the traffic of the path (and sub paths) between stable and canary handlers by weight (0-1),
+// with header override and cookie sticky assignment, for gradual rollouts, the sticky cookie is cleared when weight is 0 or 1.
+// The assigned variant is exposed in the X-Canary response header.
+//
+// Example:
+//
+//	app.Canary("/api", zoox.WrapH(stableProxy), zoox.WrapH(canaryProxy), 0.05)
+func (g *RouterGroup) Canary(path string, stable, canary HandlerFunc, weight float64, opts ...func(opt *CanaryOption)) *RouterGroup {
+	opt := &CanaryOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+	if opt.Header == "" {
+		opt.Header = "X-Canary"
+	}
+	if opt.Cookie == "" {
+		opt.Cookie = "zoox_canary"
+	}
+	if opt.CookieMaxAge == 0 {
+		opt.CookieMaxAge = 24 * time.Hour
+	}
+
+	handler := func(ctx *Context) {
+		variant := selectCanaryVariant(ctx, opt, weight)
+		ctx.SetHeader("X-Canary", variant)
+
+		if variant == CanaryCanary {
+			canary(ctx)
+			return
+		}
+
+		stable(ctx)
+	}
+
+	path = strings.TrimSuffix(path, "/")
+	g.Any(path, handler)
+	g.Any(path+"/*path", handler)
+
+	return g
+}
+
+func selectCanaryVariant(ctx *Context, opt *CanaryOption, weight float64) string {
+	// forced by header, not sticky
+	switch strings.ToLower(ctx.Header().Get(opt.Header)) {
+	case "always", "true", "1", CanaryCanary:
+		return CanaryCanary
+	case "never", "false", "0", CanaryStable:
+		return CanaryStable
+	}
+
+	if opt.Selector != nil {
+		if variant := opt.Selector(ctx); variant == CanaryCanary || variant == CanaryStable {
+			return variant
+		}
+	}
+
+	// the rollout is stopped (0) or completed (1), the sticky assignments are outdated
+	if weight <= 0 || weight >= 1 {
+		if !opt.DisableSticky && ctx.Cookie().Get(opt.Cookie) != "" {
+			ctx.Cookie().Del(opt.Cookie)
+		}
+
+		if weight <= 0 {
+			return CanaryStable
+		}
+		return CanaryCanary
+	}
+
+	if !opt.DisableSticky {
+		if variant := ctx.Cookie().Get(opt.Cookie); variant == CanaryCanary || variant == CanaryStable {
+			return variant
+		}
+	}
+
+	variant := CanaryStable
+	if rand.Float64() < weight {
+		variant = CanaryCanary
+	}
+
+	if !opt.DisableSticky {
+		ctx.Cookie().Set(opt.Cookie, variant, &cookie.Config{
+			MaxAge:   opt.CookieMaxAge,
+			Path:     "/",
+			HTTPOnly: true,
+		})
+	}
+
+	return variant
+}
@@ -0,0 +1,31 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryStickyCookieIgnoredAtZeroWeight(t *testing.T) {
+	app := New()
+	app.Canary("/api", func(ctx *Context) {
+		ctx.String(http.StatusOK, "stable")
+	}, func(ctx *Context) {
+		ctx.String(http.StatusOK, "canary")
+	}, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	req.AddCookie(&http.Cookie{Name: "zoox_canary", Value: CanaryCanary})
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "stable", recorder.Body.String())
+
+	cookies := recorder.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "zoox_canary", cookies[0].Name)
+		assert.Equal(t, "", cookies[0].Value)
+	}
+}
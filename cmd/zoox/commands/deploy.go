@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-zoox/chalk"
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/logger"
+)
+
+// deployScaffold is the data of deploy templates.
+type deployScaffold struct {
+	Name      string
+	Entry     string
+	Image     string
+	GoVersion string
+	Port      int
+	//
+	HealthPath    string
+	ReadinessPath string
+	//
+	Replicas    int
+	MaxReplicas int
+	TargetCPU   int
+	//
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// Deploy is the deployment command
+func Deploy(app *cli.MultipleProgram) {
+	app.Register("deploy", &cli.Command{
+		Name:  "deploy",
+		Usage: "Deployment tools of zoox application",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "init",
+				Usage: "Generate Dockerfile and kubernetes manifests (Deployment, Service, HPA)",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "name",
+						Usage:   "The application name, default: the directory name",
+						Aliases: []string{"n"},
+					},
+					&cli.StringFlag{
+						Name:    "entry",
+						Usage:   "The entry of the application",
+						Aliases: []string{"e"},
+						EnvVars: []string{"ZOOX_ENTRY"},
+						Value:   ".",
+					},
+					&cli.StringFlag{
+						Name:  "image",
+						Usage: "The image of the deployment, default: <name>:latest",
+					},
+					&cli.IntFlag{
+						Name:    "port",
+						Usage:   "The port of the application",
+						Aliases: []string{"p"},
+						Value:   8080,
+					},
+					&cli.StringFlag{
+						Name:  "health-path",
+						Usage: "The liveness probe path, served by the healthcheck middleware of zoox.Default()",
+						Value: "/health",
+					},
+					&cli.StringFlag{
+						Name:  "readiness-path",
+						Usage: "The readiness probe path, default: --health-path",
+					},
+					&cli.IntFlag{
+						Name:  "replicas",
+						Usage: "The min replicas",
+						Value: 2,
+					},
+					&cli.IntFlag{
+						Name:  "max-replicas",
+						Usage: "The max replicas of the HPA",
+						Value: 10,
+					},
+					&cli.IntFlag{
+						Name:  "target-cpu",
+						Usage: "The target cpu utilization (percent) of the HPA",
+						Value: 70,
+					},
+					&cli.StringFlag{
+						Name:  "cpu",
+						Usage: "The cpu request and limit, such as 250m/1 (request/limit)",
+						Value: "250m/1",
+					},
+					&cli.StringFlag{
+						Name:  "memory",
+						Usage: "The memory request and limit, such as 128Mi/512Mi (request/limit)",
+						Value: "128Mi/512Mi",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Usage:   "The output directory",
+						Aliases: []string{"o"},
+						Value:   ".",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite the existing files",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					output := ctx.String("output")
+					name := ctx.String("name")
+					if name == "" {
+						abs, err := filepath.Abs(output)
+						if err != nil {
+							return err
+						}
+						name = strings.ToLower(filepath.Base(abs))
+					}
+
+					data := &deployScaffold{
+						Name:          name,
+						Entry:         ctx.String("entry"),
+						Image:         ctx.String("image"),
+						GoVersion:     goMinorVersion(),
+						Port:          ctx.Int("port"),
+						HealthPath:    ctx.String("health-path"),
+						ReadinessPath: ctx.String("readiness-path"),
+						Replicas:      ctx.Int("replicas"),
+						MaxReplicas:   ctx.Int("max-replicas"),
+						TargetCPU:     ctx.Int("target-cpu"),
+					}
+					if data.Image == "" {
+						data.Image = name + ":latest"
+					}
+					if data.ReadinessPath == "" {
+						data.ReadinessPath = data.HealthPath
+					}
+
+					var err error
+					if data.CPURequest, data.CPULimit, err = requestLimit("cpu", ctx.String("cpu")); err != nil {
+						return err
+					}
+					if data.MemoryRequest, data.MemoryLimit, err = requestLimit("memory", ctx.String("memory")); err != nil {
+						return err
+					}
+
+					source, err := fs.Sub(templates, "templates/deploy")
+					if err != nil {
+						return err
+					}
+
+					files, err := renderTemplates([]fs.FS{source}, data)
+					if err != nil {
+						return err
+					}
+
+					if err := writeFiles(output, files, ctx.Bool("force")); err != nil {
+						return err
+					}
+
+					logger.Infof("succeed to generate, apply with: %s", chalk.Green(fmt.Sprintf("kubectl apply -f %s", filepath.Join(output, "deploy", "k8s"))))
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// requestLimit parses request/limit, such as 250m/1, the limit is the request if omitted.
+func requestLimit(name, value string) (request, limit string, err error) {
+	request, limit, ok := strings.Cut(value, "/")
+	if !ok {
+		limit = request
+	}
+
+	if request == "" || limit == "" {
+		return "", "", fmt.Errorf("invalid %s: %s, expected request/limit, such as 250m/1", name, value)
+	}
+
+	return request, limit, nil
+}
+
+// goMinorVersion returns the go version of the build image, such as 1.22.
+func goMinorVersion() string {
+	parts := strings.Split(strings.TrimPrefix(runtime.Version(), "go"), ".")
+	if len(parts) < 2 {
+		return "1.22"
+	}
+
+	return parts[0] + "." + parts[1]
+}
@@ -1,11 +1,13 @@
 package commands
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/go-zoox/cli"
-	"github.com/go-zoox/core-utils/fmt"
 	"github.com/go-zoox/fs"
-	"github.com/go-zoox/logger"
-	"github.com/go-zoox/watch"
 )
 
 // Dev is the dev command
@@ -30,25 +32,73 @@ func Dev(app *cli.MultipleProgram) {
 				Name:  "ignore",
 				Usage: "the ignored files",
 			},
+			&cli.IntFlag{
+				Name:    "port",
+				Usage:   "The port of the dev server, requests are proxied to the application",
+				Aliases: []string{"p"},
+				EnvVars: []string{"PORT"},
+				Value:   8080,
+			},
+			&cli.StringSliceFlag{
+				Name:  "ext",
+				Usage: "The watched file extensions, go files are rebuilt, others restart the application, default: .go, .html, .tmpl, .tpl, .yaml, .yml",
+			},
+			&cli.StringFlag{
+				Name:    "frontend",
+				Usage:   "The frontend dev server url to proxy, such as http://127.0.0.1:5173",
+				EnvVars: []string{"ZOOX_FRONTEND"},
+			},
+			&cli.StringSliceFlag{
+				Name:  "backend-prefix",
+				Usage: "The path prefixes proxied to the application when frontend is set, default: /api, /ws",
+			},
+			&cli.StringFlag{
+				Name:  "interval",
+				Usage: "The interval of polling file changes",
+				Value: "500ms",
+			},
 		},
 		Action: func(ctx *cli.Context) error {
 			context := ctx.String("context")
-			tmpBin := fs.TmpFilePath()
-			cmdText := fmt.Sprintf("go build -o %s %s && %s", tmpBin, ctx.String("entry"), tmpBin)
-
-			logger.Debugf("Running command: %s", cmdText)
-
 			if err := install(context); err != nil {
 				return err
 			}
 
-			watcher := watch.New(&watch.Config{
-				Context:  context,
-				Commands: []string{cmdText},
-				Ignores:  ctx.StringSlice("ignore"),
+			entry := ctx.String("entry")
+			if !strings.HasPrefix(entry, ".") && !strings.HasPrefix(entry, string(os.PathSeparator)) {
+				entry = "./" + entry
+			}
+
+			interval, err := time.ParseDuration(ctx.String("interval"))
+			if err != nil {
+				return fmt.Errorf("invalid interval: %v", err)
+			}
+
+			extensions := ctx.StringSlice("ext")
+			if len(extensions) == 0 {
+				extensions = []string{".go", ".html", ".tmpl", ".tpl", ".yaml", ".yml"}
+			}
+
+			backendPrefixes := ctx.StringSlice("backend-prefix")
+			if len(backendPrefixes) == 0 {
+				backendPrefixes = []string{"/api", "/ws"}
+			}
+
+			server, err := newDevServer(&devServerConfig{
+				Context:         context,
+				Entry:           entry,
+				Port:            ctx.Int("port"),
+				Extensions:      extensions,
+				Ignores:         ctx.StringSlice("ignore"),
+				Frontend:        ctx.String("frontend"),
+				BackendPrefixes: backendPrefixes,
+				Interval:        interval,
 			})
+			if err != nil {
+				return err
+			}
 
-			return watcher.Watch()
+			return server.Run()
 		},
 	})
 }
@@ -0,0 +1,496 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/go-zoox/chalk"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox"
+)
+
+// devPathPrefix is the path prefix of the dev server endpoints, such as the build error overlay.
+const devPathPrefix = "/__zoox"
+
+// devSkipDirs are the directories never watched.
+var devSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"bin":          true,
+	"dist":         true,
+}
+
+var buildErrorRe = regexp.MustCompile(`^(.+?\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// BuildError is the structured go build error.
+type BuildError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// devBuildState is the state of the latest build.
+type devBuildState struct {
+	Status string       `json:"status"`
+	Errors []BuildError `json:"errors"`
+	Output string       `json:"output,omitempty"`
+	At     time.Time    `json:"at"`
+}
+
+type devServerConfig struct {
+	Context    string
+	Entry      string
+	Port       int
+	Extensions []string
+	Ignores    []string
+	//
+	Frontend        string
+	BackendPrefixes []string
+	//
+	Interval time.Duration
+}
+
+// devServer owns the public port, proxies to the app (and frontend),
+// and rebuilds the app on changes.
+//
+// The app listens on a socket inherited from the dev server (zoox.EnvListenFD),
+// the socket stays open across restarts, so requests wait instead of connection refused.
+type devServer struct {
+	cfg *devServerConfig
+	//
+	appListener *net.TCPListener
+	appFile     *os.File
+	//
+	binary string
+	// buildMu serializes rebuilds
+	buildMu sync.Mutex
+	process *exec.Cmd
+	exited  chan struct{}
+	running atomic.Bool
+	//
+	sync.RWMutex
+	state devBuildState
+	//
+	subscribers sync.Map
+	//
+	backend  *httputil.ReverseProxy
+	frontend *httputil.ReverseProxy
+}
+
+func newDevServer(cfg *devServerConfig) (*devServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen app socket: %v", err)
+	}
+
+	appListener := listener.(*net.TCPListener)
+	appFile, err := appListener.File()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app socket file: %v", err)
+	}
+
+	backendURL, _ := url.Parse("http://" + appListener.Addr().String())
+	s := &devServer{
+		cfg:         cfg,
+		appListener: appListener,
+		appFile:     appFile,
+		binary:      filepath.Join(os.TempDir(), fmt.Sprintf("zoox-dev-%d", os.Getpid())),
+		backend:     httputil.NewSingleHostReverseProxy(backendURL),
+	}
+	s.backend.ErrorHandler = s.proxyError
+
+	if cfg.Frontend != "" {
+		frontendURL, err := url.Parse(cfg.Frontend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frontend url %s: %v", cfg.Frontend, err)
+		}
+
+		s.frontend = httputil.NewSingleHostReverseProxy(frontendURL)
+		s.frontend.ErrorHandler = s.proxyError
+	}
+
+	return s, nil
+}
+
+// Run builds and starts the app, then serves the public port until failed.
+func (s *devServer) Run() error {
+	defer func() {
+		s.buildMu.Lock()
+		defer s.buildMu.Unlock()
+
+		s.stop()
+		os.Remove(s.binary)
+	}()
+
+	go s.watch()
+	go s.rebuild(true)
+
+	logger.Infof("dev server started at %s", chalk.Green(fmt.Sprintf("http://127.0.0.1:%d", s.cfg.Port)))
+	return http.ListenAndServe(fmt.Sprintf(":%d", s.cfg.Port), s)
+}
+
+func (s *devServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case devPathPrefix + "/errors":
+		s.serveErrors(w, r)
+		return
+	case devPathPrefix + "/events":
+		s.serveEvents(w, r)
+		return
+	}
+
+	if s.frontend != nil && !s.isBackend(r.URL.Path) {
+		s.frontend.ServeHTTP(w, r)
+		return
+	}
+
+	state := s.State()
+	if state.Status == "error" && (!s.running.Load() || strings.Contains(r.Header.Get("Accept"), "text/html")) {
+		s.serveOverlay(w, r, state)
+		return
+	}
+
+	s.backend.ServeHTTP(w, r)
+}
+
+// State returns the latest build state.
+func (s *devServer) State() devBuildState {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.state
+}
+
+func (s *devServer) setState(state devBuildState) {
+	state.At = time.Now()
+
+	s.Lock()
+	s.state = state
+	s.Unlock()
+
+	data, _ := json.Marshal(state)
+	s.subscribers.Range(func(key, _ any) bool {
+		select {
+		case key.(chan []byte) <- data:
+		default:
+		}
+
+		return true
+	})
+}
+
+func (s *devServer) isBackend(path string) bool {
+	for _, prefix := range s.cfg.BackendPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *devServer) proxyError(w http.ResponseWriter, r *http.Request, err error) {
+	logger.Warnf("[dev] failed to proxy %s %s: %s", r.Method, r.URL.Path, err)
+	http.Error(w, fmt.Sprintf("zoox dev: %s", err), http.StatusBadGateway)
+}
+
+// serveErrors responds the latest build state with the structured errors.
+func (s *devServer) serveErrors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.State())
+}
+
+// serveEvents streams the build states as server-sent events.
+func (s *devServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ch := make(chan []byte, 16)
+	s.subscribers.Store(ch, true)
+	defer s.subscribers.Delete(ch)
+
+	data, _ := json.Marshal(s.State())
+	fmt.Fprintf(w, "event: build\ndata: %s\n\n", data)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			fmt.Fprintf(w, "event: build\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// serveOverlay responds the build errors, html pages reload once the build succeeds.
+func (s *devServer) serveOverlay(w http.ResponseWriter, r *http.Request, state devBuildState) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(state)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	var list bytes.Buffer
+	for _, e := range state.Errors {
+		fmt.Fprintf(&list, "<li><code>%s:%d:%d</code> %s</li>", htmlEscape(e.File), e.Line, e.Column, htmlEscape(e.Message))
+	}
+
+	fmt.Fprintf(w, devOverlayHTML, list.String(), htmlEscape(state.Output), devPathPrefix+"/events")
+}
+
+// watch polls the files of context, rebuilds on changes.
+func (s *devServer) watch() {
+	snapshot := s.scan()
+	for {
+		time.Sleep(s.cfg.Interval)
+
+		current := s.scan()
+		changed, goChanged := diffSnapshot(snapshot, current)
+		snapshot = current
+		if len(changed) == 0 {
+			continue
+		}
+
+		logger.Infof("[dev] changed: %s", strings.Join(changed, ", "))
+		// templates and configs only need a restart, go build cache keeps go rebuilds incremental
+		s.rebuild(goChanged)
+	}
+}
+
+func (s *devServer) scan() map[string]time.Time {
+	files := map[string]time.Time{}
+	filepath.Walk(s.cfg.Context, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(s.cfg.Context, path)
+		if info.IsDir() {
+			if rel != "." && (devSkipDirs[info.Name()] || s.isIgnored(rel)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if s.isIgnored(rel) || !s.isWatched(path) {
+			return nil
+		}
+
+		files[rel] = info.ModTime()
+		return nil
+	})
+
+	return files
+}
+
+func (s *devServer) isWatched(path string) bool {
+	ext := filepath.Ext(path)
+	for _, one := range s.cfg.Extensions {
+		if ext == one {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *devServer) isIgnored(rel string) bool {
+	for _, pattern := range s.cfg.Ignores {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func diffSnapshot(previous, current map[string]time.Time) (changed []string, goChanged bool) {
+	for file, modTime := range current {
+		if before, ok := previous[file]; !ok || !before.Equal(modTime) {
+			changed = append(changed, file)
+		}
+	}
+
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+
+	for _, file := range changed {
+		if strings.HasSuffix(file, ".go") {
+			goChanged = true
+		}
+	}
+
+	return changed, goChanged
+}
+
+// rebuild builds the app if build is true, then restarts it,
+// the running app is kept if the build failed.
+func (s *devServer) rebuild(build bool) {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	if build {
+		s.setState(devBuildState{Status: "building"})
+		logger.Infof("[dev] start to build ...")
+
+		output := s.binary + ".new"
+		cmd := exec.Command("go", "build", "-o", output, s.cfg.Entry)
+		cmd.Dir = s.cfg.Context
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			errors := parseBuildErrors(s.cfg.Context, string(out))
+			s.setState(devBuildState{Status: "error", Errors: errors, Output: string(out)})
+			logger.Errorf("[dev] failed to build:\n%s", out)
+			return
+		}
+
+		if err := os.Rename(output, s.binary); err != nil {
+			s.setState(devBuildState{Status: "error", Output: err.Error()})
+			return
+		}
+	}
+
+	s.stop()
+	if err := s.start(); err != nil {
+		s.setState(devBuildState{Status: "error", Output: err.Error()})
+		logger.Errorf("[dev] failed to start: %s", err)
+		return
+	}
+
+	s.setState(devBuildState{Status: "ready"})
+}
+
+func (s *devServer) start() error {
+	cmd := exec.Command(s.binary)
+	cmd.Dir = s.cfg.Context
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// fd 3
+	cmd.ExtraFiles = []*os.File{s.appFile}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=3", zoox.EnvListenFD))
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	s.process = cmd
+	s.exited = exited
+	s.running.Store(true)
+	return nil
+}
+
+func (s *devServer) stop() {
+	if s.process == nil {
+		return
+	}
+
+	s.running.Store(false)
+	if err := s.process.Process.Signal(syscall.SIGTERM); err != nil {
+		// such as windows
+		s.process.Process.Kill()
+	}
+
+	select {
+	case <-s.exited:
+	case <-time.After(5 * time.Second):
+		s.process.Process.Kill()
+		<-s.exited
+	}
+
+	s.process = nil
+}
+
+// parseBuildErrors parses the go build output into structured errors.
+func parseBuildErrors(context, output string) []BuildError {
+	errors := []BuildError{}
+	for _, line := range strings.Split(output, "\n") {
+		matches := buildErrorRe.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		e := BuildError{File: matches[1], Message: matches[4]}
+		e.Line, _ = strconv.Atoi(matches[2])
+		e.Column, _ = strconv.Atoi(matches[3])
+		if !filepath.IsAbs(e.File) {
+			e.File = filepath.Join(context, e.File)
+		}
+
+		errors = append(errors, e)
+	}
+
+	return errors
+}
+
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+const devOverlayHTML = `<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8" />
+    <title>Build Failed - zoox dev</title>
+    <style>
+      body { margin: 0; padding: 24px; background: #1e1e1e; color: #eee; font-family: Menlo, Consolas, monospace; }
+      h1 { color: #ff5555; font-size: 20px; }
+      li { margin: 8px 0; }
+      code { color: #8be9fd; }
+      pre { padding: 12px; background: #111; overflow: auto; }
+    </style>
+  </head>
+  <body>
+    <h1>Build Failed</h1>
+    <ul>%s</ul>
+    <pre>%s</pre>
+    <script>
+      new EventSource(%q).addEventListener('build', (event) => {
+        if (JSON.parse(event.data).status === 'ready') location.reload();
+      });
+    </script>
+  </body>
+</html>
+`
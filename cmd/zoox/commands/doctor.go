@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-zoox/chalk"
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/zoox/components/health"
+	"github.com/go-zoox/zoox/config"
+)
+
+// Doctor is the environment and config diagnostics command
+func Doctor(app *cli.MultipleProgram) {
+	app.Register("doctor", &cli.Command{
+		Name:  "doctor",
+		Usage: "Diagnose the environment and config of zoox application",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "The config file (yaml or json)",
+				Aliases: []string{"c"},
+				EnvVars: []string{"ZOOX_CONFIG"},
+				Value:   "config.yaml",
+			},
+			&cli.IntFlag{
+				Name:    "port",
+				Usage:   "The port to check, default: port of config or 8080",
+				Aliases: []string{"p"},
+				EnvVars: []string{"PORT"},
+			},
+			&cli.StringFlag{
+				Name:  "tls-expiry-warning",
+				Usage: "Warn if the tls certificate expires in the duration",
+				Value: "720h",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Usage:   "The output format (table, json)",
+				Aliases: []string{"f"},
+				Value:   "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			cfg := &config.Config{}
+			checks := []health.Check{}
+
+			// config file is optional, validated if exists
+			configFile := ctx.String("config")
+			if _, err := os.Stat(configFile); err == nil {
+				// load first, the following checks depend on the config
+				health.LoadConfig(configFile, cfg)
+				checks = append(checks, health.ConfigFile(configFile, &config.Config{}))
+			}
+
+			port := ctx.Int("port")
+			if port == 0 {
+				port = cfg.Port
+			}
+			if port == 0 {
+				port = 8080
+			}
+			checks = append(checks, health.Port(cfg.Host, port))
+			if cfg.HTTPSPort != 0 {
+				checks = append(checks, health.Port(cfg.Host, cfg.HTTPSPort))
+			}
+
+			if cfg.Redis.Host != "" {
+				if cfg.Redis.Port == 0 {
+					cfg.Redis.Port = 6379
+				}
+
+				checks = append(checks, health.Redis(&cfg.Redis))
+			}
+
+			if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+				expiryWarning, err := time.ParseDuration(ctx.String("tls-expiry-warning"))
+				if err != nil {
+					return fmt.Errorf("invalid tls expiry warning: %v", err)
+				}
+
+				checks = append(checks, health.TLSCertificate(cfg.TLSCertFile, cfg.TLSKeyFile, expiryWarning))
+			}
+			if cfg.TLSCaCertFile != "" {
+				checks = append(checks, health.File("tls ca", cfg.TLSCaCertFile))
+			}
+
+			results := health.Run(context.Background(), checks...)
+
+			switch ctx.String("format") {
+			case "json":
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(string(data))
+			case "table":
+				printHealthResults(results)
+			default:
+				return fmt.Errorf("unknown format %s, available: table, json", ctx.String("format"))
+			}
+
+			if !health.Healthy(results) {
+				return fmt.Errorf("doctor found problems, see the fixes above")
+			}
+
+			return nil
+		},
+	})
+}
+
+func printHealthResults(results []health.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tCHECK\tMESSAGE")
+	for _, result := range results {
+		status := chalk.Green("✔ ok")
+		switch result.Status {
+		case health.StatusWarn:
+			status = chalk.Yellow("! warn")
+		case health.StatusFail:
+			status = chalk.Red("✘ fail")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status, result.Name, result.Message)
+	}
+	w.Flush()
+
+	fixes := []string{}
+	for _, result := range results {
+		if result.Fix != "" {
+			fixes = append(fixes, fmt.Sprintf("  - %s: %s", result.Name, result.Fix))
+		}
+	}
+	if len(fixes) > 0 {
+		fmt.Printf("\nFixes:\n%s\n", strings.Join(fixes, "\n"))
+	}
+}
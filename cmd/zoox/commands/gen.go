@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-zoox/chalk"
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox/components/openapi"
+)
+
+// Gen is the code generation command
+func Gen(app *cli.MultipleProgram) {
+	app.Register("gen", &cli.Command{
+		Name:  "gen",
+		Usage: "Generate code from openapi document",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "server",
+				Usage: "Generate zoox server stub (routes, typed structs and handler interface)",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "spec",
+						Usage:    "The openapi document file (yaml or json)",
+						Aliases:  []string{"s"},
+						EnvVars:  []string{"ZOOX_OPENAPI_SPEC"},
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Usage:   "The output file of the generated code",
+						Aliases: []string{"o"},
+						Value:   "./api/api.gen.go",
+					},
+					&cli.StringFlag{
+						Name:    "package",
+						Usage:   "The package name of the generated code",
+						Aliases: []string{"p"},
+						Value:   "api",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					doc, err := openapi.Load(ctx.String("spec"))
+					if err != nil {
+						return err
+					}
+
+					code, err := openapi.GenerateServer(doc, &openapi.GenerateConfig{
+						Package: ctx.String("package"),
+					})
+					if err != nil {
+						return err
+					}
+
+					return writeGenerated(ctx.String("output"), code)
+				},
+			},
+			{
+				Name:  "client",
+				Usage: "Generate typed api client (go or typescript) with auth helpers and typed errors",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "spec",
+						Usage:    "The openapi document file (yaml or json), or the url of the running app spec",
+						Aliases:  []string{"s"},
+						EnvVars:  []string{"ZOOX_OPENAPI_SPEC"},
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "lang",
+						Usage:   "The language of the client (go, typescript)",
+						Aliases: []string{"l"},
+						Value:   "go",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Usage:   "The output file of the generated code, default: ./client/client.gen.go or ./client/client.gen.ts",
+						Aliases: []string{"o"},
+					},
+					&cli.StringFlag{
+						Name:    "package",
+						Usage:   "The package name of the generated go code",
+						Aliases: []string{"p"},
+						Value:   "client",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					doc, err := openapi.Load(ctx.String("spec"))
+					if err != nil {
+						return err
+					}
+
+					var code []byte
+					output := ctx.String("output")
+					switch ctx.String("lang") {
+					case "go":
+						if output == "" {
+							output = "./client/client.gen.go"
+						}
+
+						code, err = openapi.GenerateClient(doc, &openapi.GenerateConfig{
+							Package: ctx.String("package"),
+						})
+					case "typescript", "ts":
+						if output == "" {
+							output = "./client/client.gen.ts"
+						}
+
+						code, err = openapi.GenerateTypeScriptClient(doc)
+					default:
+						return fmt.Errorf("unknown language %s, available: go, typescript", ctx.String("lang"))
+					}
+					if err != nil {
+						return err
+					}
+
+					return writeGenerated(output, code)
+				},
+			},
+		},
+	})
+}
+
+func writeGenerated(output string, code []byte) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("failed to create output dir: %v", err)
+	}
+
+	if err := os.WriteFile(output, code, 0644); err != nil {
+		return fmt.Errorf("failed to write generated code: %v", err)
+	}
+
+	logger.Infof("succeed to generate, output: %s", chalk.Green(output))
+	return nil
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/fs"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/migrate"
+)
+
+// Migrate is the database migrations command
+func Migrate(app *cli.MultipleProgram) {
+	appFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "entry",
+			Usage:   "The entry of the application source, run with go run",
+			Aliases: []string{"e"},
+			EnvVars: []string{"ZOOX_ENTRY"},
+			Value:   ".",
+		},
+		&cli.StringFlag{
+			Name:    "binary",
+			Usage:   "The built binary of the application, overrides --entry",
+			Aliases: []string{"b"},
+		},
+		&cli.StringFlag{
+			Name:  "context",
+			Usage: "the command context",
+			Value: fs.CurrentDir(),
+		},
+	}
+
+	stepsFlag := func(value int, usage string) []cli.Flag {
+		return append([]cli.Flag{
+			&cli.IntFlag{
+				Name:    "steps",
+				Usage:   usage,
+				Aliases: []string{"n"},
+				Value:   value,
+			},
+		}, appFlags...)
+	}
+
+	app.Register("migrate", &cli.Command{
+		Name:  "migrate",
+		Usage: "Run the database migrations of zoox application",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "Apply the pending migrations",
+				Flags: stepsFlag(0, "The number of migrations to apply, 0 means all"),
+				Action: func(ctx *cli.Context) error {
+					return runMigrate(ctx, fmt.Sprintf("up:%d", ctx.Int("steps")))
+				},
+			},
+			{
+				Name:  "down",
+				Usage: "Roll back the applied migrations",
+				Flags: stepsFlag(1, "The number of migrations to roll back"),
+				Action: func(ctx *cli.Context) error {
+					return runMigrate(ctx, fmt.Sprintf("down:%d", ctx.Int("steps")))
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "Print the status of migrations",
+				Flags: appFlags,
+				Action: func(ctx *cli.Context) error {
+					return runMigrate(ctx, "status")
+				},
+			},
+			{
+				Name:      "create",
+				Usage:     "Create the up and down sql migration files",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "dir",
+						Usage:   "The directory of migrations",
+						Aliases: []string{"d"},
+						Value:   zoox.DefaultMigrationsDir,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					return createMigration(ctx.String("dir"), ctx.Args().First())
+				},
+			},
+		},
+	})
+}
+
+// runMigrate runs the application with zoox.EnvMigrate, which runs the migrations instead of serving,
+// so that the migrations share the database driver and config of the application.
+func runMigrate(ctx *cli.Context, command string) error {
+	output, err := os.CreateTemp("", "zoox-migrate-*.json")
+	if err != nil {
+		return err
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	var cmd *exec.Cmd
+	if binary := ctx.String("binary"); binary != "" {
+		if binary, err = filepath.Abs(binary); err != nil {
+			return err
+		}
+
+		cmd = exec.Command(binary)
+	} else {
+		cmd = exec.Command("go", "run", ctx.String("entry"))
+	}
+
+	cmd.Dir = ctx.String("context")
+	cmd.Env = append(
+		os.Environ(),
+		fmt.Sprintf("%s=%s", zoox.EnvMigrate, command),
+		fmt.Sprintf("%s=%s", zoox.EnvMigrateOutput, output.Name()),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	logger.Debugf("Running command: %s", strings.Join(cmd.Args, " "))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run migrations: %s", err)
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("failed to run migrations: the application exited before app.Run")
+	}
+
+	statuses := []*migrate.Status{}
+	if err := json.Unmarshal(data, &statuses); err != nil {
+		return fmt.Errorf("failed to parse migration status: %v", err)
+	}
+
+	printMigrations(statuses)
+	return nil
+}
+
+func printMigrations(statuses []*migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, at := "pending", ""
+		if status.Applied {
+			state = "applied"
+		}
+		if status.AppliedAt != nil {
+			at = status.AppliedAt.Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, at)
+	}
+	w.Flush()
+}
+
+var migrationNameRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// createMigration creates <timestamp>_<name>.up.sql and <timestamp>_<name>.down.sql in the dir.
+func createMigration(dir, name string) error {
+	name = strings.Trim(migrationNameRe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return fmt.Errorf("migration name is required, such as zoox migrate create create_users")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		if err := os.WriteFile(file, []byte(fmt.Sprintf("-- %s migration of %s\n", direction, name)), 0644); err != nil {
+			return err
+		}
+
+		logger.Infof("created %s", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/zoox/components/openapi"
+	"github.com/go-zoox/zoox/components/openapi/mock"
+	"github.com/go-zoox/zoox/middleware"
+)
+
+// Mock is the mock server command
+func Mock(app *cli.MultipleProgram) {
+	app.Register("mock", &cli.Command{
+		Name:      "mock",
+		Usage:     "Serve mock responses of openapi document",
+		ArgsUsage: "<spec>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "port",
+				Usage:   "The port of the mock server",
+				Aliases: []string{"p"},
+				EnvVars: []string{"PORT"},
+				Value:   8080,
+			},
+			&cli.StringFlag{
+				Name:  "latency",
+				Usage: "The delay of each response, such as 200ms",
+				Value: "0s",
+			},
+			&cli.StringFlag{
+				Name:  "jitter",
+				Usage: "The max random delay added to latency, such as 100ms",
+				Value: "0s",
+			},
+			&cli.Float64Flag{
+				Name:  "error-rate",
+				Usage: "The ratio (0-1) of injected error responses",
+			},
+			&cli.IntFlag{
+				Name:  "error-status",
+				Usage: "The status of injected errors",
+				Value: 500,
+			},
+			&cli.BoolFlag{
+				Name:  "dynamic",
+				Usage: "Respond random values instead of the examples of the document",
+			},
+			&cli.Int64Flag{
+				Name:  "seed",
+				Usage: "The seed of random values, default: current time",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			spec := ctx.Args().First()
+			if spec == "" {
+				return fmt.Errorf("spec is required, usage: zoox mock <spec>")
+			}
+
+			doc, err := openapi.Load(spec)
+			if err != nil {
+				return err
+			}
+
+			errorRate := ctx.Float64("error-rate")
+			if errorRate < 0 || errorRate > 1 {
+				return fmt.Errorf("invalid error rate %v, expected 0-1", errorRate)
+			}
+
+			latency, err := time.ParseDuration(ctx.String("latency"))
+			if err != nil {
+				return fmt.Errorf("invalid latency: %v", err)
+			}
+
+			jitter, err := time.ParseDuration(ctx.String("jitter"))
+			if err != nil {
+				return fmt.Errorf("invalid jitter: %v", err)
+			}
+
+			app := mock.New(doc, &mock.Config{
+				Latency:     latency,
+				Jitter:      jitter,
+				ErrorRate:   errorRate,
+				ErrorStatus: ctx.Int("error-status"),
+				Dynamic:     ctx.Bool("dynamic"),
+				Seed:        ctx.Int64("seed"),
+			})
+			// the frontend dev server is on another origin
+			app.Use(middleware.CORS())
+
+			return app.Run(fmt.Sprintf(":%d", ctx.Int("port")))
+		},
+	})
+}
@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-zoox/chalk"
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox"
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+// layouts are the built-in starter layouts.
+var layouts = []string{"api", "html", "websocket"}
+
+// scaffold is the data of project templates.
+type scaffold struct {
+	Name        string
+	Module      string
+	Layout      string
+	SecretKey   string
+	ZooxVersion string
+}
+
+// New is the project scaffolding command
+func New(app *cli.MultipleProgram) {
+	app.Register("new", &cli.Command{
+		Name:      "new",
+		Usage:     "Create a new zoox project",
+		ArgsUsage: "<name>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "module",
+				Usage:   "The go module path of the project, default: <name>",
+				Aliases: []string{"m"},
+			},
+			&cli.StringFlag{
+				Name:    "layout",
+				Usage:   fmt.Sprintf("The built-in starter layout (%s)", strings.Join(layouts, ", ")),
+				Aliases: []string{"l"},
+				Value:   "api",
+			},
+			&cli.BoolFlag{
+				Name:  "api",
+				Usage: "Use the api-only layout, alias of --layout api",
+			},
+			&cli.BoolFlag{
+				Name:  "html",
+				Usage: "Use the html layout, alias of --layout html",
+			},
+			&cli.BoolFlag{
+				Name:  "websocket",
+				Usage: "Use the websocket layout, alias of --layout websocket",
+			},
+			&cli.StringFlag{
+				Name:    "template",
+				Usage:   "The remote template, a git repository url or local directory, overrides --layout",
+				Aliases: []string{"t"},
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite the existing files",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-install",
+				Usage: "Skip installing dependencies",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			name := ctx.Args().First()
+			if name == "" {
+				return fmt.Errorf("project name is required, usage: zoox new <name>")
+			}
+
+			layout := ctx.String("layout")
+			for _, one := range layouts {
+				if ctx.Bool(one) {
+					layout = one
+				}
+			}
+
+			module := ctx.String("module")
+			if module == "" {
+				module = name
+			}
+
+			data := &scaffold{
+				Name:        path.Base(name),
+				Module:      module,
+				Layout:      layout,
+				SecretKey:   randomSecret(),
+				ZooxVersion: "v" + strings.TrimPrefix(zoox.Version, "v"),
+			}
+
+			sources, cleanup, err := templateSources(ctx.String("template"), layout)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			files, err := renderTemplates(sources, data)
+			if err != nil {
+				return err
+			}
+
+			dir := name
+			logger.Infof("start to create project %s (layout: %s) ...", chalk.Green(data.Name), layout)
+			if err := writeFiles(dir, files, ctx.Bool("force")); err != nil {
+				return err
+			}
+
+			if !ctx.Bool("skip-install") {
+				cmd := exec.Command("go", "mod", "tidy")
+				cmd.Dir = dir
+				cmd.Stdout = os.Stdout
+				cmd.Stderr = os.Stderr
+				if err := cmd.Run(); err != nil {
+					return fmt.Errorf("failed to install dependencies: %s", err.Error())
+				}
+			}
+
+			logger.Infof("succeed to create project, run: %s", chalk.Green(fmt.Sprintf("cd %s && zoox dev", dir)))
+			return nil
+		},
+	})
+}
+
+// templateSources returns the template file systems, files of later ones override the former.
+func templateSources(remote, layout string) (sources []fs.FS, cleanup func(), err error) {
+	cleanup = func() {}
+
+	if remote != "" {
+		// local directory
+		if info, err := os.Stat(remote); err == nil && info.IsDir() {
+			return []fs.FS{os.DirFS(remote)}, cleanup, nil
+		}
+
+		tmp, err := os.MkdirTemp("", "zoox-template-")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() {
+			os.RemoveAll(tmp)
+		}
+
+		logger.Infof("start to download template %s ...", remote)
+		cmd := exec.Command("git", "clone", "--depth", "1", remote, tmp)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("failed to download template %s: %s", remote, err.Error())
+		}
+
+		return []fs.FS{os.DirFS(tmp)}, cleanup, nil
+	}
+
+	valid := false
+	for _, one := range layouts {
+		if one == layout {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, cleanup, fmt.Errorf("unknown layout %s, available: %s", layout, strings.Join(layouts, ", "))
+	}
+
+	for _, dir := range []string{"templates/common", "templates/" + layout} {
+		sub, err := fs.Sub(templates, dir)
+		if err != nil {
+			return nil, cleanup, err
+		}
+
+		sources = append(sources, sub)
+	}
+
+	return sources, cleanup, nil
+}
+
+// renderTemplates renders the files of sources, files of later sources override the former ones,
+// files with .tmpl suffix are rendered with data and the suffix is trimmed.
+func renderTemplates(sources []fs.FS, data any) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, source := range sources {
+		err := fs.WalkDir(source, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if name == ".git" {
+					return fs.SkipDir
+				}
+
+				return nil
+			}
+
+			content, err := fs.ReadFile(source, name)
+			if err != nil {
+				return err
+			}
+
+			if strings.HasSuffix(name, ".tmpl") {
+				tpl, err := template.New(name).Parse(string(content))
+				if err != nil {
+					return fmt.Errorf("failed to parse template %s: %v", name, err)
+				}
+
+				var buf bytes.Buffer
+				if err := tpl.Execute(&buf, data); err != nil {
+					return fmt.Errorf("failed to render template %s: %v", name, err)
+				}
+
+				name = strings.TrimSuffix(name, ".tmpl")
+				content = buf.Bytes()
+			}
+
+			files[name] = content
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// writeFiles writes the files into dir, existing files are not overwritten unless force.
+func writeFiles(dir string, files map[string][]byte, force bool) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		output := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("file %s already exists, use --force to overwrite", output)
+		}
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		output := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return fmt.Errorf("failed to create dir: %v", err)
+		}
+
+		if err := os.WriteFile(output, files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", output, err)
+		}
+
+		logger.Infof("create %s", output)
+	}
+
+	return nil
+}
+
+func randomSecret() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "change-me"
+	}
+
+	return hex.EncodeToString(b)
+}
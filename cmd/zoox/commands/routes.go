@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-zoox/cli"
+	"github.com/go-zoox/fs"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox"
+)
+
+// Routes is the route table command
+func Routes(app *cli.MultipleProgram) {
+	app.Register("routes", &cli.Command{
+		Name:  "routes",
+		Usage: "Print the route table of zoox application",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "entry",
+				Usage:   "The entry of the application source, run with go run",
+				Aliases: []string{"e"},
+				EnvVars: []string{"ZOOX_ENTRY"},
+				Value:   ".",
+			},
+			&cli.StringFlag{
+				Name:    "binary",
+				Usage:   "The built binary of the application, overrides --entry",
+				Aliases: []string{"b"},
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Usage:   "The output format (table, json)",
+				Aliases: []string{"f"},
+				Value:   "table",
+			},
+			&cli.StringFlag{
+				Name:  "check",
+				Usage: "The json route table to compare with, fails if the routes changed, used in CI",
+			},
+			&cli.StringFlag{
+				Name:  "context",
+				Usage: "the command context",
+				Value: fs.CurrentDir(),
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			routes, err := loadRoutes(ctx.String("context"), ctx.String("entry"), ctx.String("binary"))
+			if err != nil {
+				return err
+			}
+
+			if ctx.String("check") != "" {
+				return checkRoutes(ctx.String("check"), routes)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				data, err := json.MarshalIndent(routes, "", "  ")
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(string(data))
+			case "table":
+				printRoutes(routes)
+			default:
+				return fmt.Errorf("unknown format %s, available: table, json", ctx.String("format"))
+			}
+
+			return nil
+		},
+	})
+}
+
+// loadRoutes runs the application with zoox.EnvRoutesOutput, which writes the routes instead of serving.
+func loadRoutes(context, entry, binary string) ([]zoox.RouteInfo, error) {
+	output, err := os.CreateTemp("", "zoox-routes-*.json")
+	if err != nil {
+		return nil, err
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	var cmd *exec.Cmd
+	if binary != "" {
+		if binary, err = filepath.Abs(binary); err != nil {
+			return nil, err
+		}
+
+		cmd = exec.Command(binary)
+	} else {
+		cmd = exec.Command("go", "run", entry)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Dir = context
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", zoox.EnvRoutesOutput, output.Name()))
+	cmd.Stderr = &stderr
+	logger.Debugf("Running command: %s", strings.Join(cmd.Args, " "))
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to load routes: %s\n%s", err, stderr.String())
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("failed to load routes: the application exited before app.Run")
+	}
+
+	routes := []zoox.RouteInfo{}
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse routes: %v", err)
+	}
+
+	return routes, nil
+}
+
+func printRoutes(routes []zoox.RouteInfo) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tLOCATION\tMIDDLEWARES")
+	for _, route := range routes {
+		location := ""
+		if route.File != "" {
+			location = fmt.Sprintf("%s:%d", route.File, route.Line)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Handler, location, strings.Join(route.Middlewares, ","))
+	}
+	w.Flush()
+}
+
+// checkRoutes compares the method and path of routes with the json file.
+func checkRoutes(file string, routes []zoox.RouteInfo) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	expected := []zoox.RouteInfo{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", file, err)
+	}
+
+	keys := func(routes []zoox.RouteInfo) map[string]bool {
+		m := map[string]bool{}
+		for _, route := range routes {
+			m[route.Method+" "+route.Path] = true
+		}
+
+		return m
+	}
+
+	current, previous := keys(routes), keys(expected)
+	diff := []string{}
+	for _, route := range routes {
+		if key := route.Method + " " + route.Path; !previous[key] {
+			diff = append(diff, "+ "+key)
+		}
+	}
+	for _, route := range expected {
+		if key := route.Method + " " + route.Path; !current[key] {
+			diff = append(diff, "- "+key)
+		}
+	}
+
+	if len(diff) > 0 {
+		return fmt.Errorf("routes changed, update %s with zoox routes --format json:\n%s", file, strings.Join(diff, "\n"))
+	}
+
+	logger.Infof("routes are up to date (%d routes)", len(routes))
+	return nil
+}
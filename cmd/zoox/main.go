@@ -13,9 +13,16 @@ func main() {
 		Version: zoox.Version,
 	})
 
+	commands.New(app)
 	commands.Install(app)
 	commands.Dev(app)
 	commands.Build(app)
+	commands.Gen(app)
+	commands.Routes(app)
+	commands.Doctor(app)
+	commands.Deploy(app)
+	commands.Mock(app)
+	commands.Migrate(app)
 
 	app.Run()
 }
@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Entry is the audit log entry.
+type Entry struct {
+	Action    string         `json:"action"`
+	Target    string         `json:"target"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Actor     any            `json:"actor,omitempty"`
+	IP        string         `json:"ip,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	Method    string         `json:"method,omitempty"`
+	Path      string         `json:"path,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// Sink writes the batch of entries, such as file, redis stream or webhook.
+type Sink interface {
+	Write(entries []*Entry) error
+}
+
+// SinkFunc is the function adapter of Sink.
+type SinkFunc func(entries []*Entry) error
+
+// Write writes the entries.
+func (fn SinkFunc) Write(entries []*Entry) error {
+	return fn(entries)
+}
+
+// ErrClosed is returned when logging after closed.
+var ErrClosed = errors.New("audit is closed")
+
+// Audit is the audit logger, entries are written to sinks in order by batches.
+type Audit interface {
+	Log(entry *Entry) error
+	// Flush writes the pending entries.
+	Flush()
+	// Close flushes the pending entries and stops.
+	Close() error
+}
+
+// Config is the config of audit.
+type Config struct {
+	Sinks []Sink
+	// BatchSize is the max entries of a batch, default: 100.
+	BatchSize int
+	// FlushInterval is the max interval of flushing, default: 1s.
+	FlushInterval time.Duration
+	// BufferSize is the size of pending entries, Log blocks if full, default: 1024.
+	BufferSize int
+	// MaxRetries is the retries of failed sink writes, default: 3.
+	MaxRetries int
+	// OnError is called when the sink fails after retries.
+	OnError func(sink Sink, entries []*Entry, err error)
+}
+
+type audit struct {
+	cfg *Config
+	//
+	entries chan *Entry
+	flush   chan chan struct{}
+	done    chan struct{}
+	//
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// New creates an audit logger.
+func New(cfg *Config) Audit {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1024
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+
+	a := &audit{
+		cfg:     cfg,
+		entries: make(chan *Entry, cfg.BufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *audit) Log(entry *Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	select {
+	case <-a.closed:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case a.entries <- entry:
+		return nil
+	case <-a.closed:
+		return ErrClosed
+	}
+}
+
+func (a *audit) Flush() {
+	ack := make(chan struct{})
+	select {
+	case a.flush <- ack:
+		<-ack
+	case <-a.done:
+	}
+}
+
+func (a *audit) Close() error {
+	a.closeOnce.Do(func() {
+		close(a.closed)
+	})
+
+	<-a.done
+	return nil
+}
+
+// run is the single writer, so entries are written in the order of Log.
+func (a *audit) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Entry, 0, a.cfg.BatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		a.write(batch)
+		batch = make([]*Entry, 0, a.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case entry := <-a.entries:
+			batch = append(batch, entry)
+			if len(batch) >= a.cfg.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-a.flush:
+			a.drain(&batch)
+			write()
+			close(ack)
+		case <-a.closed:
+			a.drain(&batch)
+			write()
+			return
+		}
+	}
+}
+
+// drain moves the buffered entries to batch, written in batches.
+func (a *audit) drain(batch *[]*Entry) {
+	for {
+		select {
+		case entry := <-a.entries:
+			*batch = append(*batch, entry)
+			if len(*batch) >= a.cfg.BatchSize {
+				a.write(*batch)
+				*batch = make([]*Entry, 0, a.cfg.BatchSize)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (a *audit) write(entries []*Entry) {
+	for _, sink := range a.cfg.Sinks {
+		var err error
+		for i := 0; i <= a.cfg.MaxRetries; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(i) * 100 * time.Millisecond)
+			}
+
+			if err = sink.Write(entries); err == nil {
+				break
+			}
+		}
+
+		if err != nil && a.cfg.OnError != nil {
+			a.cfg.OnError(sink, entries, err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type fileSink struct {
+	sync.Mutex
+	file *os.File
+}
+
+// NewFileSink creates a sink appending entries as json lines to the file.
+func NewFileSink(path string) (Sink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{file: f}, nil
+}
+
+func (s *fileSink) Write(entries []*Entry) error {
+	s.Lock()
+	defer s.Unlock()
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.file.Write(buf.Bytes())
+	return err
+}
+
+// RedisStreamConfig is the config of redis stream sink.
+type RedisStreamConfig struct {
+	Host     string
+	Port     int
+	DB       int
+	Username string
+	Password string
+	// Stream is the stream key, default: audit.
+	Stream string
+	// MaxLen caps the stream length approximately, 0 means unlimited.
+	MaxLen int64
+}
+
+type redisStreamSink struct {
+	client *redis.Client
+	cfg    *RedisStreamConfig
+}
+
+// NewRedisStreamSink creates a sink adding entries to the redis stream, field "entry" is the json entry.
+func NewRedisStreamSink(cfg *RedisStreamConfig) Sink {
+	if cfg.Stream == "" {
+		cfg.Stream = "audit"
+	}
+
+	return &redisStreamSink{
+		cfg: cfg,
+		client: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			DB:       cfg.DB,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}),
+	}
+}
+
+func (s *redisStreamSink) Write(entries []*Entry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// pipelined in order
+	pipe := s.client.Pipeline()
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.cfg.Stream,
+			MaxLen: s.cfg.MaxLen,
+			Approx: s.cfg.MaxLen > 0,
+			Values: map[string]any{"entry": string(data)},
+		})
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// WebhookConfig is the config of webhook sink.
+type WebhookConfig struct {
+	URL     string
+	Headers map[string]string
+	// Timeout is the request timeout, default: 10s.
+	Timeout time.Duration
+}
+
+type webhookSink struct {
+	cfg    *WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting the batch of entries as json array to the url.
+func NewWebhookSink(cfg *WebhookConfig) Sink {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &webhookSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *webhookSink) Write(entries []*Entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
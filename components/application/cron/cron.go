@@ -1,7 +1,9 @@
 package cron
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 
 	gocron "github.com/go-zoox/cron"
 )
@@ -21,11 +23,24 @@ type Cron interface {
 	AddYearlyJob(id string, cmd func() error) (err error)
 }
 
+// Stopper is implemented by the cron stopped on app shutdown.
+type Stopper interface {
+	// Stop removes all the jobs, so no job is run later, the jobs can not be added after stopped.
+	Stop() error
+}
+
+// ErrStopped is returned when adding jobs after stopped.
+var ErrStopped = errors.New("cron is stopped")
+
 type cron struct {
+	sync.Mutex
 	isStarted bool
+	isStopped bool
 	core      *gocron.Cron
 }
 
+var _ Stopper = (*cron)(nil)
+
 // New creates a cron.
 func New() Cron {
 	core, err := gocron.New()
@@ -40,8 +55,8 @@ func New() Cron {
 
 // AddJob ...
 func (c *cron) AddJob(id string, spec string, job func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddJob(id, spec, job)
@@ -76,8 +91,8 @@ func (c *cron) ClearJobs() error {
 
 // AddSecondlyJob adds a schedule job run in every second.
 func (c *cron) AddSecondlyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddSecondlyJob(id, cmd)
@@ -85,8 +100,8 @@ func (c *cron) AddSecondlyJob(id string, cmd func() error) (err error) {
 
 // AddMinutelyJob adds a schedule job run in every minute.
 func (c *cron) AddMinutelyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddMinutelyJob(id, cmd)
@@ -94,8 +109,8 @@ func (c *cron) AddMinutelyJob(id string, cmd func() error) (err error) {
 
 // AddHourlyJob adds a schedule job run in every hour.
 func (c *cron) AddHourlyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddHourlyJob(id, cmd)
@@ -103,8 +118,8 @@ func (c *cron) AddHourlyJob(id string, cmd func() error) (err error) {
 
 // AddDailyJob adds a schedule job run in every day.
 func (c *cron) AddDailyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddDailyJob(id, cmd)
@@ -112,8 +127,8 @@ func (c *cron) AddDailyJob(id string, cmd func() error) (err error) {
 
 // AddWeeklyJob adds a schedule job run in every week.
 func (c *cron) AddWeeklyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddWeeklyJob(id, cmd)
@@ -121,8 +136,8 @@ func (c *cron) AddWeeklyJob(id string, cmd func() error) (err error) {
 
 // AddMonthlyJob adds a schedule job run in every month.
 func (c *cron) AddMonthlyJob(id string, cmd func() error) (err error) {
-	if !c.isStarted {
-		c.core.Start()
+	if err := c.start(); err != nil {
+		return err
 	}
 
 	return c.core.AddMonthlyJob(id, cmd)
@@ -130,9 +145,39 @@ func (c *cron) AddMonthlyJob(id string, cmd func() error) (err error) {
 
 // AddYearlyJob adds a schedule job run in every year.
 func (c *cron) AddYearlyJob(id string, cmd func() error) (err error) {
+	if err := c.start(); err != nil {
+		return err
+	}
+
+	return c.core.AddYearlyJob(id, cmd)
+}
+
+// Stop removes all the jobs, so no job is run later.
+func (c *cron) Stop() error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.isStopped = true
+	if !c.isStarted {
+		return nil
+	}
+
+	return c.core.ClearJobs()
+}
+
+// start starts the scheduler on the first job.
+func (c *cron) start() error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.isStopped {
+		return ErrStopped
+	}
+
 	if !c.isStarted {
 		c.core.Start()
+		c.isStarted = true
 	}
 
-	return c.core.AddYearlyJob(id, cmd)
+	return nil
 }
@@ -0,0 +1,158 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ConsulConfig is the config of the consul resolver.
+type ConsulConfig struct {
+	// Address is the consul http api, default: http://127.0.0.1:8500.
+	Address string
+	// Service is the service name.
+	Service string
+	// Tag filters the service instances by tag.
+	Tag        string
+	Datacenter string
+	Token      string
+	// OnlyPassing skips the instances failing the checks instead of returning them as unhealthy.
+	OnlyPassing bool
+	// WaitTime is the max wait of the blocking query, default: 5m.
+	WaitTime time.Duration
+	// Client is the http client, default: http.DefaultClient.
+	Client *http.Client
+}
+
+type consul struct {
+	cfg *ConsulConfig
+}
+
+// NewConsul creates the resolver of the consul service, updates are watched by the blocking queries.
+func NewConsul(cfg *ConsulConfig) Resolver {
+	if cfg.Address == "" {
+		cfg.Address = "http://127.0.0.1:8500"
+	}
+	if cfg.WaitTime == 0 {
+		cfg.WaitTime = 5 * time.Minute
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	return &consul{cfg: cfg}
+}
+
+type consulEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+		Meta    map[string]string
+	}
+	Checks []struct {
+		Status string
+	}
+}
+
+func (c *consul) query(ctx context.Context, index string) ([]Endpoint, string, error) {
+	query := url.Values{}
+	if c.cfg.Tag != "" {
+		query.Set("tag", c.cfg.Tag)
+	}
+	if c.cfg.Datacenter != "" {
+		query.Set("dc", c.cfg.Datacenter)
+	}
+	if c.cfg.OnlyPassing {
+		query.Set("passing", "true")
+	}
+	if index != "" {
+		query.Set("index", index)
+		query.Set("wait", fmt.Sprintf("%ds", int(c.cfg.WaitTime.Seconds())))
+	}
+
+	u := fmt.Sprintf("%s/v1/health/service/%s?%s", c.cfg.Address, url.PathEscape(c.cfg.Service), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.cfg.Token != "" {
+		req.Header.Set("X-Consul-Token", c.cfg.Token)
+	}
+
+	res, err := c.cfg.Client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("discovery: consul responded %s", res.Status)
+	}
+
+	entries := []consulEntry{}
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, "", err
+	}
+
+	endpoints := []Endpoint{}
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+
+		healthy := true
+		for _, check := range entry.Checks {
+			if check.Status != "passing" {
+				healthy = false
+			}
+		}
+
+		endpoints = append(endpoints, Endpoint{
+			Address:  net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)),
+			Healthy:  healthy,
+			Metadata: entry.Service.Meta,
+		})
+	}
+
+	return endpoints, res.Header.Get("X-Consul-Index"), nil
+}
+
+func (c *consul) Resolve(ctx context.Context) ([]Endpoint, error) {
+	endpoints, _, err := c.query(ctx, "")
+	return endpoints, err
+}
+
+func (c *consul) Watch(ctx context.Context, fn func(endpoints []Endpoint)) error {
+	index := ""
+	for {
+		endpoints, next, err := c.query(ctx, index)
+		if err != nil {
+			return err
+		}
+
+		// the blocking query returns on timeout with the same index
+		if next != index || index == "" {
+			fn(endpoints)
+		}
+
+		// reset the index if it goes backwards, see consul blocking queries
+		n, _ := strconv.ParseUint(next, 10, 64)
+		i, _ := strconv.ParseUint(index, 10, 64)
+		switch {
+		case n == 0:
+			next = "1"
+		case n < i:
+			next = ""
+		}
+		index = next
+	}
+}
@@ -0,0 +1,208 @@
+// Package discovery resolves the endpoints of services (static list, DNS SRV, Consul, Kubernetes endpoints)
+// for the proxy targets and outbound clients, with watch-based updates and health filtering.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoEndpoints is returned when the service has no healthy endpoints.
+var ErrNoEndpoints = errors.New("discovery: no healthy endpoints")
+
+// Endpoint is the endpoint of the service.
+type Endpoint struct {
+	// Address is host:port.
+	Address string `json:"address"`
+	// Healthy is false if the endpoint fails the health checks of the registry, such as consul checks or kubernetes readiness.
+	Healthy  bool              `json:"healthy"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Resolver resolves the endpoints of the service.
+type Resolver interface {
+	// Resolve returns the current endpoints.
+	Resolve(ctx context.Context) ([]Endpoint, error)
+	// Watch calls fn with the endpoints when they are changed, it blocks until ctx is done.
+	Watch(ctx context.Context, fn func(endpoints []Endpoint)) error
+}
+
+// Config is the config of the target.
+type Config struct {
+	// FailureCooldown is the duration the failed endpoint is skipped, see Target.MarkFailed, default: 10s.
+	FailureCooldown time.Duration
+	// OnError is called when the watch fails, the watch is retried after 1s.
+	OnError func(err error)
+}
+
+// Target is the load balanced (round robin) endpoints of the service, updated by watching the resolver.
+type Target struct {
+	cfg      *Config
+	resolver Resolver
+	//
+	endpoints atomic.Pointer[[]Endpoint]
+	next      atomic.Uint64
+	//
+	mu     sync.Mutex
+	failed map[string]time.Time
+	//
+	cancel context.CancelFunc
+	ready  chan struct{}
+	once   sync.Once
+}
+
+// NewTarget creates the target of the resolver, the endpoints are watched until ctx is done or Close.
+func NewTarget(ctx context.Context, resolver Resolver, cfg ...*Config) *Target {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.FailureCooldown == 0 {
+		cfgX.FailureCooldown = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	t := &Target{
+		cfg:      cfgX,
+		resolver: resolver,
+		failed:   map[string]time.Time{},
+		cancel:   cancel,
+		ready:    make(chan struct{}),
+	}
+
+	go t.watch(ctx)
+	return t
+}
+
+func (t *Target) watch(ctx context.Context) {
+	for {
+		err := t.resolver.Watch(ctx, t.update)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil && t.cfg.OnError != nil {
+			t.cfg.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (t *Target) update(endpoints []Endpoint) {
+	sorted := append([]Endpoint{}, endpoints...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	t.endpoints.Store(&sorted)
+	t.once.Do(func() {
+		close(t.ready)
+	})
+}
+
+// Endpoints returns the endpoints, including the unhealthy ones.
+func (t *Target) Endpoints() []Endpoint {
+	if endpoints := t.endpoints.Load(); endpoints != nil {
+		return *endpoints
+	}
+
+	return nil
+}
+
+// Next returns the next healthy endpoint (round robin), the failed endpoints are skipped during the cooldown,
+// unless all the endpoints are failed. It waits for the first resolution until ctx is done.
+func (t *Target) Next(ctx context.Context) (Endpoint, error) {
+	select {
+	case <-t.ready:
+	case <-ctx.Done():
+		return Endpoint{}, ctx.Err()
+	}
+
+	healthy := []Endpoint{}
+	for _, e := range t.Endpoints() {
+		if e.Healthy {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		return Endpoint{}, ErrNoEndpoints
+	}
+
+	available := healthy
+	t.mu.Lock()
+	if len(t.failed) != 0 {
+		available = []Endpoint{}
+		now := time.Now()
+		for _, e := range healthy {
+			if until, ok := t.failed[e.Address]; ok && now.Before(until) {
+				continue
+			}
+
+			available = append(available, e)
+		}
+	}
+	t.mu.Unlock()
+
+	// all failed, try them anyway
+	if len(available) == 0 {
+		available = healthy
+	}
+
+	return available[int(t.next.Add(1)-1)%len(available)], nil
+}
+
+// MarkFailed skips the endpoint during the cooldown, such as the proxy got 502 from it.
+func (t *Target) MarkFailed(address string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for addr, until := range t.failed {
+		if now.After(until) {
+			delete(t.failed, addr)
+		}
+	}
+
+	t.failed[address] = now.Add(t.cfg.FailureCooldown)
+}
+
+// Close stops watching the resolver.
+func (t *Target) Close() {
+	t.cancel()
+}
+
+// Poll watches by resolving in every interval, fn is called on changes,
+// used by the resolvers without watch api, such as DNS SRV.
+func Poll(ctx context.Context, interval time.Duration, resolve func(ctx context.Context) ([]Endpoint, error), fn func(endpoints []Endpoint)) error {
+	var last []Endpoint
+	for {
+		endpoints, err := resolve(ctx)
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(endpoints, func(i, j int) bool {
+			return endpoints[i].Address < endpoints[j].Address
+		})
+		if last == nil || !reflect.DeepEqual(last, endpoints) {
+			fn(endpoints)
+			last = endpoints
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
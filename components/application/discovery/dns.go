@@ -0,0 +1,56 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNSConfig is the config of the DNS SRV resolver.
+type DNSConfig struct {
+	// Name is the SRV record, such as _http._tcp.orders.default.svc.cluster.local.
+	Name string
+	// Interval is the interval of polling, default: 30s.
+	Interval time.Duration
+	// Resolver is the dns resolver, default: net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+type dnsResolver struct {
+	cfg *DNSConfig
+}
+
+// NewDNS creates the resolver of the DNS SRV record, the record is polled for updates.
+func NewDNS(cfg *DNSConfig) Resolver {
+	if cfg.Interval == 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+
+	return &dnsResolver{cfg: cfg}
+}
+
+func (r *dnsResolver) Resolve(ctx context.Context) ([]Endpoint, error) {
+	_, records, err := r.cfg.Resolver.LookupSRV(ctx, "", "", r.cfg.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []Endpoint{}
+	for _, record := range records {
+		endpoints = append(endpoints, Endpoint{
+			Address: net.JoinHostPort(strings.TrimSuffix(record.Target, "."), fmt.Sprintf("%d", record.Port)),
+			Healthy: true,
+		})
+	}
+
+	return endpoints, nil
+}
+
+func (r *dnsResolver) Watch(ctx context.Context, fn func(endpoints []Endpoint)) error {
+	return Poll(ctx, r.cfg.Interval, r.Resolve, fn)
+}
@@ -0,0 +1,229 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// KubernetesConfig is the config of the kubernetes endpoints resolver.
+type KubernetesConfig struct {
+	// APIServer is the kubernetes api server, default: in cluster https://$KUBERNETES_SERVICE_HOST:$KUBERNETES_SERVICE_PORT.
+	APIServer string
+	// Namespace is the namespace of the service, default: the namespace of the service account.
+	Namespace string
+	// Service is the service name.
+	Service string
+	// Port is the port name of the endpoints, default: the first port.
+	Port string
+	// Token is the bearer token, default: the token of the service account.
+	Token string
+	// Client is the http client, default: trusting the ca of the service account.
+	Client *http.Client
+}
+
+type kubernetes struct {
+	cfg *KubernetesConfig
+}
+
+// NewKubernetes creates the resolver of the kubernetes service endpoints, updates are watched by the watch api,
+// the not ready addresses are unhealthy.
+func NewKubernetes(cfg *KubernetesConfig) (Resolver, error) {
+	if cfg.APIServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" {
+			return nil, fmt.Errorf("discovery: kubernetes api server is required out of cluster")
+		}
+
+		cfg.APIServer = "https://" + net.JoinHostPort(host, port)
+	}
+	if cfg.Namespace == "" {
+		namespace, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("discovery: kubernetes namespace is required: %v", err)
+		}
+
+		cfg.Namespace = strings.TrimSpace(string(namespace))
+	}
+	if cfg.Token == "" {
+		if token, err := os.ReadFile(serviceAccountDir + "/token"); err == nil {
+			cfg.Token = strings.TrimSpace(string(token))
+		}
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+		if ca, err := os.ReadFile(serviceAccountDir + "/ca.crt"); err == nil {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(ca)
+
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+			cfg.Client = &http.Client{Transport: transport}
+		}
+	}
+
+	return &kubernetes{cfg: cfg}, nil
+}
+
+type kubernetesEndpoints struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Subsets []struct {
+		Addresses         []kubernetesAddress `json:"addresses"`
+		NotReadyAddresses []kubernetesAddress `json:"notReadyAddresses"`
+		Ports             []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"ports"`
+	} `json:"subsets"`
+}
+
+type kubernetesAddress struct {
+	IP        string `json:"ip"`
+	TargetRef *struct {
+		Name string `json:"name"`
+	} `json:"targetRef"`
+}
+
+func (k *kubernetes) endpoints(e *kubernetesEndpoints) []Endpoint {
+	endpoints := []Endpoint{}
+	for _, subset := range e.Subsets {
+		port := 0
+		for _, p := range subset.Ports {
+			if k.cfg.Port == "" || p.Name == k.cfg.Port {
+				port = p.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+
+		add := func(addresses []kubernetesAddress, healthy bool) {
+			for _, address := range addresses {
+				endpoint := Endpoint{
+					Address: net.JoinHostPort(address.IP, strconv.Itoa(port)),
+					Healthy: healthy,
+				}
+				if address.TargetRef != nil {
+					endpoint.Metadata = map[string]string{"pod": address.TargetRef.Name}
+				}
+
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+		add(subset.Addresses, true)
+		add(subset.NotReadyAddresses, false)
+	}
+
+	return endpoints
+}
+
+func (k *kubernetes) request(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	u := fmt.Sprintf("%s/api/v1/namespaces/%s/endpoints%s", k.cfg.APIServer, url.PathEscape(k.cfg.Namespace), path)
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if k.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+k.cfg.Token)
+	}
+
+	res, err := k.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("discovery: kubernetes responded %s", res.Status)
+	}
+
+	return res, nil
+}
+
+func (k *kubernetes) get(ctx context.Context) (*kubernetesEndpoints, error) {
+	res, err := k.request(ctx, "/"+url.PathEscape(k.cfg.Service), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	e := &kubernetesEndpoints{}
+	if err := json.NewDecoder(res.Body).Decode(e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (k *kubernetes) Resolve(ctx context.Context) ([]Endpoint, error) {
+	e, err := k.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.endpoints(e), nil
+}
+
+func (k *kubernetes) Watch(ctx context.Context, fn func(endpoints []Endpoint)) error {
+	e, err := k.get(ctx)
+	if err != nil {
+		return err
+	}
+	fn(k.endpoints(e))
+
+	res, err := k.request(ctx, "", url.Values{
+		"watch":           {"true"},
+		"fieldSelector":   {"metadata.name=" + k.cfg.Service},
+		"resourceVersion": {e.Metadata.ResourceVersion},
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		event := struct {
+			Type   string              `json:"type"`
+			Object kubernetesEndpoints `json:"object"`
+		}{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case "ADDED", "MODIFIED":
+			fn(k.endpoints(&event.Object))
+		case "DELETED":
+			fn([]Endpoint{})
+		case "ERROR":
+			// such as the resource version is too old, watch again
+			return fmt.Errorf("discovery: kubernetes watch error")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// the server closes the watch on timeout, watch again
+	return fmt.Errorf("discovery: kubernetes watch closed")
+}
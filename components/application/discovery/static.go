@@ -0,0 +1,28 @@
+package discovery
+
+import "context"
+
+type static struct {
+	endpoints []Endpoint
+}
+
+// NewStatic creates the resolver of the fixed addresses (host:port).
+func NewStatic(addresses ...string) Resolver {
+	endpoints := []Endpoint{}
+	for _, address := range addresses {
+		endpoints = append(endpoints, Endpoint{Address: address, Healthy: true})
+	}
+
+	return &static{endpoints: endpoints}
+}
+
+func (s *static) Resolve(ctx context.Context) ([]Endpoint, error) {
+	return append([]Endpoint{}, s.endpoints...), nil
+}
+
+func (s *static) Watch(ctx context.Context, fn func(endpoints []Endpoint)) error {
+	fn(s.endpoints)
+
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,142 @@
+package errreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// DefaultBugsnagEndpoint is the notify api of Bugsnag.
+const DefaultBugsnagEndpoint = "https://notify.bugsnag.com/"
+
+// BugsnagConfig is the config of the Bugsnag reporter.
+type BugsnagConfig struct {
+	// APIKey is the notifier api key of the project.
+	APIKey string
+	// Endpoint is the notify api, default: DefaultBugsnagEndpoint.
+	Endpoint string
+	// Client is the http client, default timeout: 10s.
+	Client *http.Client
+}
+
+type bugsnagReporter struct {
+	cfg      *BugsnagConfig
+	hostname string
+}
+
+// NewBugsnag creates the Bugsnag reporter with the notify api (payload version 5).
+func NewBugsnag(cfg *BugsnagConfig) Reporter {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultBugsnagEndpoint
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	hostname, _ := os.Hostname()
+	return &bugsnagReporter{cfg: cfg, hostname: hostname}
+}
+
+func (r *bugsnagReporter) Report(event *Event) error {
+	stacktrace := make([]map[string]any, 0, len(event.Stack))
+	for _, frame := range event.Stack {
+		stacktrace = append(stacktrace, map[string]any{
+			"file":       frame.File,
+			"lineNumber": frame.Line,
+			"method":     frame.Function,
+			"inProject":  true,
+		})
+	}
+
+	e := map[string]any{
+		"exceptions": []any{
+			map[string]any{
+				"errorClass": ErrorClass(event.Error),
+				"message":    event.Error.Error(),
+				"stacktrace": stacktrace,
+			},
+		},
+		"severity":  "error",
+		"unhandled": event.Unhandled,
+		"severityReason": map[string]any{
+			"type": severityReason(event.Unhandled),
+		},
+		"app": map[string]any{
+			"version":      event.Release,
+			"releaseStage": event.Environment,
+		},
+		"device": map[string]any{
+			"hostname": r.hostname,
+			"osName":   runtime.GOOS,
+			"time":     event.Timestamp.UTC().Format(time.RFC3339),
+		},
+		"metaData": map[string]any{
+			"tags":  event.Tags,
+			"extra": event.Extra,
+		},
+	}
+
+	if event.Request != nil {
+		e["request"] = map[string]any{
+			"clientIp":   event.Request.IP,
+			"headers":    event.Request.Headers,
+			"httpMethod": event.Request.Method,
+			"url":        event.Request.URL + queryString(event.Request.Query),
+		}
+	}
+
+	if event.User != nil && event.User.ID != "" {
+		user := map[string]any{"id": event.User.ID}
+		if event.User.Username != "" {
+			user["name"] = event.User.Username
+		}
+		e["user"] = user
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"apiKey": r.cfg.APIKey,
+		"notifier": map[string]any{
+			"name":    "zoox",
+			"version": "1.0.0",
+			"url":     "https://github.com/go-zoox/zoox",
+		},
+		"events": []any{e},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Bugsnag-Api-Key", r.cfg.APIKey)
+	req.Header.Set("Bugsnag-Payload-Version", "5")
+	req.Header.Set("Bugsnag-Sent-At", time.Now().UTC().Format(time.RFC3339))
+
+	return send(r.cfg.Client, req, "bugsnag")
+}
+
+func (r *bugsnagReporter) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func queryString(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	return "?" + query
+}
+
+func severityReason(unhandled bool) string {
+	if unhandled {
+		return "unhandledPanic"
+	}
+
+	return "handledError"
+}
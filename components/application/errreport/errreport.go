@@ -0,0 +1,255 @@
+// Package errreport reports the errors and panics of requests to the error tracking services,
+// such as Sentry, Rollbar and Bugsnag, with the sanitized request, release and user context.
+package errreport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the level of the event.
+const (
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+// Filtered is the replacement of the sensitive values.
+const Filtered = "[Filtered]"
+
+// DefaultSensitiveKeys are the header and query keys (case-insensitive, substring) filtered from the events.
+var DefaultSensitiveKeys = []string{"authorization", "cookie", "token", "secret", "password", "passwd", "api-key", "api_key", "apikey", "signature", "session"}
+
+// Event is the error event.
+type Event struct {
+	Timestamp time.Time
+	// Level is error (handled) or fatal (panic).
+	Level string
+	Error error
+	// Unhandled is true for the panics.
+	Unhandled bool
+	// Stack is the stack where the error is reported or the panic is recovered.
+	Stack   []Frame
+	Request *Request
+	User    *User
+	//
+	Release     string
+	Environment string
+	//
+	Tags  map[string]string
+	Extra map[string]any
+}
+
+// Frame is the stack frame.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Request is the sanitized request of the event.
+type Request struct {
+	Method  string
+	URL     string
+	Query   string
+	Headers map[string]string
+	IP      string
+}
+
+// User is the user of the event, only the identifiers are reported, never the whole user object.
+type User struct {
+	ID       string
+	Username string
+	IP       string
+}
+
+// Reporter reports the events.
+type Reporter interface {
+	Report(event *Event) error
+	// Flush waits for the pending events, returns false if timed out.
+	Flush(timeout time.Duration) bool
+}
+
+// ErrorClass returns the class of the error, such as *errors.errorString or *fs.PathError.
+func ErrorClass(err error) string {
+	if p, ok := err.(*PanicError); ok {
+		if e, ok := p.Value.(error); ok {
+			return fmt.Sprintf("%T", e)
+		}
+
+		return "panic"
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// PanicError is the error of the recovered panic value.
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// Callers returns the stack frames of the caller, skip 0 is the caller of Callers.
+// The frames of runtime (such as runtime.gopanic) are omitted.
+func Callers(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := []Frame{}
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// NewRequest creates the request of the event, the sensitive headers and query values are filtered.
+func NewRequest(r *http.Request, ip string, sensitiveKeys ...string) *Request {
+	keys := append(append([]string{}, DefaultSensitiveKeys...), sensitiveKeys...)
+
+	headers := map[string]string{}
+	for key, values := range r.Header {
+		if isSensitive(key, keys) {
+			headers[key] = Filtered
+			continue
+		}
+
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	query := r.URL.Query()
+	for key := range query {
+		if isSensitive(key, keys) {
+			query[key] = []string{Filtered}
+		}
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	u := url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path}
+
+	return &Request{
+		Method:  r.Method,
+		URL:     u.String(),
+		Query:   query.Encode(),
+		Headers: headers,
+		IP:      ip,
+	}
+}
+
+func isSensitive(key string, sensitiveKeys []string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, strings.ToLower(k)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefaultAsyncQueueSize is the max pending events of Async, the events over it are dropped.
+var DefaultAsyncQueueSize = 1024
+
+// DefaultAsyncWorkers is the number of goroutines reporting the events of Async.
+var DefaultAsyncWorkers = 4
+
+// ErrQueueFull is passed to onError of Async when the event is dropped because the queue is full.
+var ErrQueueFull = errors.New("errreport: queue is full, event dropped")
+
+type async struct {
+	reporter Reporter
+	onError  func(event *Event, err error)
+	queue    chan *Event
+	wg       sync.WaitGroup
+}
+
+// Async reports the events in background, so the requests are not blocked by the reporting I/O.
+//
+// The events are reported by DefaultAsyncWorkers workers from a queue of DefaultAsyncQueueSize,
+// when the reporting can't keep up, the new events are dropped and onError is called with ErrQueueFull.
+func Async(reporter Reporter, onError func(event *Event, err error)) Reporter {
+	a := &async{
+		reporter: reporter,
+		onError:  onError,
+		queue:    make(chan *Event, DefaultAsyncQueueSize),
+	}
+
+	for i := 0; i < DefaultAsyncWorkers; i++ {
+		go a.work()
+	}
+
+	return a
+}
+
+func (a *async) work() {
+	for event := range a.queue {
+		if err := a.reporter.Report(event); err != nil && a.onError != nil {
+			a.onError(event, err)
+		}
+
+		a.wg.Done()
+	}
+}
+
+func (a *async) Report(event *Event) error {
+	a.wg.Add(1)
+	select {
+	case a.queue <- event:
+	default:
+		a.wg.Done()
+		if a.onError != nil {
+			a.onError(event, ErrQueueFull)
+		}
+	}
+
+	return nil
+}
+
+func (a *async) Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	deadline := time.Now().Add(timeout)
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		return false
+	}
+
+	return a.reporter.Flush(time.Until(deadline))
+}
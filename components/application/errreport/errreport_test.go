@@ -0,0 +1,68 @@
+package errreport
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingReporter struct {
+	release  chan struct{}
+	reported int64
+}
+
+func (r *blockingReporter) Report(event *Event) error {
+	<-r.release
+	atomic.AddInt64(&r.reported, 1)
+	return nil
+}
+
+func (r *blockingReporter) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func TestAsyncBoundedQueue(t *testing.T) {
+	queueSize, workers := DefaultAsyncQueueSize, DefaultAsyncWorkers
+	DefaultAsyncQueueSize, DefaultAsyncWorkers = 2, 1
+	defer func() {
+		DefaultAsyncQueueSize, DefaultAsyncWorkers = queueSize, workers
+	}()
+
+	var mu sync.Mutex
+	dropped := 0
+	reporter := &blockingReporter{release: make(chan struct{})}
+	a := Async(reporter, func(event *Event, err error) {
+		if errors.Is(err, ErrQueueFull) {
+			mu.Lock()
+			dropped++
+			mu.Unlock()
+		}
+	})
+
+	// 1 in the worker, 2 in the queue, the others are dropped
+	a.Report(&Event{})
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 9; i++ {
+		a.Report(&Event{})
+	}
+
+	mu.Lock()
+	if dropped != 7 {
+		t.Errorf("expected 7 dropped events, got %d", dropped)
+	}
+	mu.Unlock()
+
+	if a.Flush(50 * time.Millisecond) {
+		t.Error("expected Flush to time out while reporting is blocked")
+	}
+
+	close(reporter.release)
+	if !a.Flush(time.Second) {
+		t.Error("expected Flush to succeed")
+	}
+	if n := atomic.LoadInt64(&reporter.reported); n != 3 {
+		t.Errorf("expected 3 reported events, got %d", n)
+	}
+}
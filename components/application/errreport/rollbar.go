@@ -0,0 +1,126 @@
+package errreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultRollbarEndpoint is the item api of Rollbar.
+const DefaultRollbarEndpoint = "https://api.rollbar.com/api/1/item/"
+
+// RollbarConfig is the config of the Rollbar reporter.
+type RollbarConfig struct {
+	// Token is the post_server_item access token.
+	Token string
+	// Endpoint is the item api, default: DefaultRollbarEndpoint.
+	Endpoint string
+	// Client is the http client, default timeout: 10s.
+	Client *http.Client
+}
+
+type rollbarReporter struct {
+	cfg *RollbarConfig
+}
+
+// NewRollbar creates the Rollbar reporter with the item api.
+func NewRollbar(cfg *RollbarConfig) Reporter {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultRollbarEndpoint
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &rollbarReporter{cfg: cfg}
+}
+
+func (r *rollbarReporter) Report(event *Event) error {
+	// rollbar expects the frames from the outermost to the innermost
+	frames := make([]map[string]any, 0, len(event.Stack))
+	for i := len(event.Stack) - 1; i >= 0; i-- {
+		frame := event.Stack[i]
+		frames = append(frames, map[string]any{
+			"filename": frame.File,
+			"lineno":   frame.Line,
+			"method":   frame.Function,
+		})
+	}
+
+	data := map[string]any{
+		"environment":  event.Environment,
+		"level":        event.Level,
+		"timestamp":    event.Timestamp.Unix(),
+		"code_version": event.Release,
+		"platform":     "go",
+		"language":     "go",
+		"framework":    "zoox",
+		"body": map[string]any{
+			"trace": map[string]any{
+				"frames": frames,
+				"exception": map[string]any{
+					"class":   ErrorClass(event.Error),
+					"message": event.Error.Error(),
+				},
+			},
+		},
+		"custom": map[string]any{
+			"tags":  event.Tags,
+			"extra": event.Extra,
+		},
+	}
+
+	if event.Request != nil {
+		data["request"] = map[string]any{
+			"url":          event.Request.URL,
+			"method":       event.Request.Method,
+			"headers":      event.Request.Headers,
+			"query_string": event.Request.Query,
+			"user_ip":      event.Request.IP,
+		}
+	}
+
+	if event.User != nil && event.User.ID != "" {
+		person := map[string]any{"id": event.User.ID}
+		if event.User.Username != "" {
+			person["username"] = event.User.Username
+		}
+		data["person"] = person
+	}
+
+	body, err := json.Marshal(map[string]any{"data": data})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Rollbar-Access-Token", r.cfg.Token)
+
+	return send(r.cfg.Client, req, "rollbar")
+}
+
+func (r *rollbarReporter) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func send(client *http.Client, req *http.Request, name string) error {
+	response, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("%s: failed to report: %s", name, response.Status)
+	}
+
+	return nil
+}
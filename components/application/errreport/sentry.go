@@ -0,0 +1,90 @@
+package errreport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// SentryConfig is the config of the Sentry reporter.
+type SentryConfig struct {
+	DSN string
+}
+
+type sentryReporter struct {
+	hub *sentry.Hub
+}
+
+// NewSentry creates the Sentry reporter, with its own client, so it does not conflict with middleware.InitSentry.
+func NewSentry(cfg *SentryConfig) (Reporter, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn: cfg.DSN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sentryReporter{
+		hub: sentry.NewHub(client, sentry.NewScope()),
+	}, nil
+}
+
+func (r *sentryReporter) Report(event *Event) error {
+	e := sentry.NewEvent()
+	e.Timestamp = event.Timestamp
+	e.Level = sentry.LevelError
+	if event.Level == LevelFatal {
+		e.Level = sentry.LevelFatal
+	}
+	e.Release = event.Release
+	e.Environment = event.Environment
+	e.Tags = event.Tags
+	e.Extra = event.Extra
+	e.Message = event.Error.Error()
+
+	// sentry expects the frames from the outermost to the innermost
+	frames := make([]sentry.Frame, 0, len(event.Stack))
+	for i := len(event.Stack) - 1; i >= 0; i-- {
+		frame := event.Stack[i]
+		frames = append(frames, sentry.Frame{
+			Function: frame.Function,
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    true,
+		})
+	}
+	e.Exception = []sentry.Exception{{
+		Type:       ErrorClass(event.Error),
+		Value:      event.Error.Error(),
+		Stacktrace: &sentry.Stacktrace{Frames: frames},
+	}}
+
+	if event.Request != nil {
+		e.Request = &sentry.Request{
+			URL:         event.Request.URL,
+			Method:      event.Request.Method,
+			QueryString: event.Request.Query,
+			Headers:     event.Request.Headers,
+			Env:         map[string]string{"REMOTE_ADDR": event.Request.IP},
+		}
+	}
+
+	if event.User != nil {
+		e.User = sentry.User{
+			ID:        event.User.ID,
+			Username:  event.User.Username,
+			IPAddress: event.User.IP,
+		}
+	}
+
+	if id := r.hub.CaptureEvent(e); id == nil {
+		return fmt.Errorf("sentry: event is dropped")
+	}
+
+	return nil
+}
+
+func (r *sentryReporter) Flush(timeout time.Duration) bool {
+	return r.hub.Flush(timeout)
+}
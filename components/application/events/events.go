@@ -0,0 +1,192 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Handler handles the typed event.
+type Handler[T any] func(ctx context.Context, event T) error
+
+// ErrorPolicy is the policy of errors returned by sync handlers.
+type ErrorPolicy int
+
+const (
+	// StopOnError stops dispatching at the first error of sync handlers and returns it.
+	StopOnError ErrorPolicy = iota
+	// ContinueOnError runs all sync handlers and returns the joined errors.
+	ContinueOnError
+)
+
+// Named is implemented by events with a custom name, default: the type name, such as UserCreated.
+type Named interface {
+	EventName() string
+}
+
+// Name returns the name of the event.
+func Name(event any) string {
+	if named, ok := event.(Named); ok {
+		return named.EventName()
+	}
+
+	t := reflect.TypeOf(event)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// Config is the config of Bus.
+type Config struct {
+	// ErrorPolicy is the policy of errors returned by sync handlers, default: StopOnError.
+	ErrorPolicy ErrorPolicy
+	// OnError is called with the errors (and panics) of async handlers, which are not returned by Emit.
+	OnError func(event any, err error)
+}
+
+// Option is the option of subscriptions.
+type Option func(s *subscription)
+
+// Async runs the handler in a goroutine, Emit does not wait for it,
+// errors are reported to Config.OnError.
+func Async() Option {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+type subscription struct {
+	handler func(ctx context.Context, event any) error
+	async   bool
+}
+
+// Bus is the in-process event bus, handlers are subscribed by the event type with On.
+type Bus interface {
+	// Emit dispatches the event to the handlers of its type.
+	Emit(event any) error
+	// EmitContext dispatches the event with the context, such as the request context.
+	EmitContext(ctx context.Context, event any) error
+	// Wait waits for the running async handlers, such as on shutdown.
+	Wait()
+	//
+	subscribe(t reflect.Type, s *subscription) (unsubscribe func())
+}
+
+type bus struct {
+	sync.RWMutex
+	cfg           *Config
+	subscriptions map[reflect.Type][]*subscription
+	wg            sync.WaitGroup
+}
+
+// New creates an event bus.
+func New(cfg ...*Config) Bus {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.OnError == nil {
+		cfgX.OnError = func(event any, err error) {}
+	}
+
+	return &bus{
+		cfg:           cfgX,
+		subscriptions: map[reflect.Type][]*subscription{},
+	}
+}
+
+// On subscribes the handler to the events of type T, returns the function to unsubscribe.
+//
+//	events.On(bus, func(ctx context.Context, event UserCreated) error {
+//		return sendWelcomeEmail(ctx, event.Email)
+//	}, events.Async())
+func On[T any](b Bus, handler Handler[T], opts ...Option) (unsubscribe func()) {
+	s := &subscription{
+		handler: func(ctx context.Context, event any) error {
+			return handler(ctx, event.(T))
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return b.subscribe(reflect.TypeOf((*T)(nil)).Elem(), s)
+}
+
+func (b *bus) subscribe(t reflect.Type, s *subscription) func() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.subscriptions[t] = append(b.subscriptions[t], s)
+
+	return func() {
+		b.Lock()
+		defer b.Unlock()
+
+		subscriptions := b.subscriptions[t]
+		for i, one := range subscriptions {
+			if one == s {
+				b.subscriptions[t] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *bus) Emit(event any) error {
+	return b.EmitContext(context.Background(), event)
+}
+
+func (b *bus) EmitContext(ctx context.Context, event any) error {
+	if event == nil {
+		return errors.New("events: event is nil")
+	}
+
+	b.RLock()
+	subscriptions := b.subscriptions[reflect.TypeOf(event)]
+	b.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if s.async {
+			b.wg.Add(1)
+			// the emitter may be cancelled (such as the request is done), async handlers outlive it
+			go func(s *subscription) {
+				defer b.wg.Done()
+				if err := call(context.WithoutCancel(ctx), s, event); err != nil {
+					b.cfg.OnError(event, err)
+				}
+			}(s)
+			continue
+		}
+
+		if err := call(ctx, s, event); err != nil {
+			if b.cfg.ErrorPolicy == StopOnError {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *bus) Wait() {
+	b.wg.Wait()
+}
+
+// call runs the handler, panics are recovered as errors.
+func call(ctx context.Context, s *subscription, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("events: handler of %s panicked: %v", Name(event), r)
+		}
+	}()
+
+	return s.handler(ctx, event)
+}
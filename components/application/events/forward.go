@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+)
+
+// Sink receives the forwarded events, such as a message queue producer.
+type Sink func(ctx context.Context, name string, payload []byte) error
+
+// Forward forwards the events of type T as json to the sink asynchronously,
+// so that selected events reach other services via pubsub or message queues.
+func Forward[T any](b Bus, sink Sink) (unsubscribe func()) {
+	return On(b, func(ctx context.Context, event T) error {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		return sink(ctx, Name(event), payload)
+	}, Async())
+}
+
+// PubSubSink publishes the events to the topic <prefix><name>, such as events:UserCreated.
+func PubSubSink(ps pubsub.PubSub, prefix string) Sink {
+	return func(ctx context.Context, name string, payload []byte) error {
+		return ps.Publish(ctx, &pubsub.Message{
+			Topic: prefix + name,
+			Body:  payload,
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Flag types
+const (
+	// TypeBoolean is on or off for all.
+	TypeBoolean = "boolean"
+	// TypePercentage is on for a stable percentage of targets, bucketed by target id.
+	TypePercentage = "percentage"
+	// TypeTargeted is on for the listed target ids or attributes.
+	TypeTargeted = "targeted"
+)
+
+// ErrNotFound is returned when the flag is not found.
+var ErrNotFound = errors.New("flag not found")
+
+// Flag is the feature flag.
+type Flag struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Enabled is the master switch of the flag.
+	Enabled bool `json:"enabled"`
+	// Percentage is the rollout percentage (0-100) of percentage flag.
+	Percentage int `json:"percentage,omitempty"`
+	// IDs are the target ids of targeted flag.
+	IDs []string `json:"ids,omitempty"`
+	// Attributes are the target attributes of targeted flag, matched if any value equals.
+	//	such as: {"country": ["CN", "US"], "plan": ["pro"]}
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+// Validate validates the flag.
+func (f *Flag) Validate() error {
+	if f.Key == "" {
+		return errors.New("flag key is required")
+	}
+
+	switch f.Type {
+	case "":
+		f.Type = TypeBoolean
+	case TypeBoolean, TypeTargeted:
+	case TypePercentage:
+		if f.Percentage < 0 || f.Percentage > 100 {
+			return fmt.Errorf("flag(%s) percentage must be between 0 and 100", f.Key)
+		}
+	default:
+		return fmt.Errorf("flag(%s) unknown type: %s", f.Key, f.Type)
+	}
+
+	return nil
+}
+
+// Target is the subject of flag evaluation, such as user or tenant.
+type Target struct {
+	ID         string
+	Attributes map[string]string
+}
+
+// Evaluate returns true if the flag is on for the target.
+func (f *Flag) Evaluate(target *Target) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if target == nil {
+		target = &Target{}
+	}
+
+	switch f.Type {
+	case TypePercentage:
+		return bucket(f.Key, target.ID) < f.Percentage
+	case TypeTargeted:
+		for _, id := range f.IDs {
+			if id == target.ID && id != "" {
+				return true
+			}
+		}
+
+		for name, values := range f.Attributes {
+			value, ok := target.Attributes[name]
+			if !ok {
+				continue
+			}
+
+			for _, v := range values {
+				if strings.EqualFold(v, value) {
+					return true
+				}
+			}
+		}
+
+		return false
+	default:
+		return true
+	}
+}
+
+// bucket returns the stable bucket (0-99) of the target for the flag.
+func bucket(key, id string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + id))
+	return int(h.Sum32() % 100)
+}
+
+// Flags is the feature flag manager.
+type Flags interface {
+	// IsEnabled returns true if the flag is on for the target, false if not found.
+	IsEnabled(key string, target *Target) bool
+	//
+	Get(key string) (*Flag, error)
+	Set(flag *Flag) error
+	Del(key string) error
+	List() ([]*Flag, error)
+}
+
+type flags struct {
+	store Store
+}
+
+// New creates a feature flag manager with the store.
+func New(store Store) Flags {
+	return &flags{
+		store: store,
+	}
+}
+
+func (f *flags) IsEnabled(key string, target *Target) bool {
+	flag, err := f.store.Get(key)
+	if err != nil {
+		return false
+	}
+
+	return flag.Evaluate(target)
+}
+
+func (f *flags) Get(key string) (*Flag, error) {
+	return f.store.Get(key)
+}
+
+func (f *flags) Set(flag *Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+
+	return f.store.Set(flag)
+}
+
+func (f *flags) Del(key string) error {
+	return f.store.Del(key)
+}
+
+func (f *flags) List() ([]*Flag, error) {
+	return f.store.List()
+}
@@ -0,0 +1,165 @@
+package flags
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-zoox/cache"
+)
+
+// Store is the storage of flags.
+type Store interface {
+	Get(key string) (*Flag, error)
+	Set(flag *Flag) error
+	Del(key string) error
+	List() ([]*Flag, error)
+}
+
+type memoryStore struct {
+	sync.RWMutex
+	flags map[string]*Flag
+}
+
+// NewMemory creates an in-process memory store.
+func NewMemory() Store {
+	return &memoryStore{
+		flags: map[string]*Flag{},
+	}
+}
+
+func (s *memoryStore) Get(key string) (*Flag, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	flag, ok := s.flags[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	copied := *flag
+	return &copied, nil
+}
+
+func (s *memoryStore) Set(flag *Flag) error {
+	s.Lock()
+	defer s.Unlock()
+
+	copied := *flag
+	s.flags[flag.Key] = &copied
+	return nil
+}
+
+func (s *memoryStore) Del(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.flags, key)
+	return nil
+}
+
+func (s *memoryStore) List() ([]*Flag, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	flags := make([]*Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		copied := *flag
+		flags = append(flags, &copied)
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Key < flags[j].Key
+	})
+
+	return flags, nil
+}
+
+// cacheKeys is the key of flag keys index in cache store.
+const cacheKeys = "flags:keys"
+
+type cacheStore struct {
+	sync.Mutex
+	cache cache.Cache
+}
+
+// NewCache creates a store backed by cache, such as redis, so flags are shared across instances.
+func NewCache(c cache.Cache) Store {
+	return &cacheStore{
+		cache: c,
+	}
+}
+
+func (s *cacheStore) Get(key string) (*Flag, error) {
+	if !s.cache.Has("flags:" + key) {
+		return nil, ErrNotFound
+	}
+
+	flag := &Flag{}
+	if err := s.cache.Get("flags:"+key, flag); err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+func (s *cacheStore) Set(flag *Flag) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.cache.Set("flags:"+flag.Key, flag); err != nil {
+		return err
+	}
+
+	keys := s.keys()
+	for _, key := range keys {
+		if key == flag.Key {
+			return nil
+		}
+	}
+
+	return s.cache.Set(cacheKeys, append(keys, flag.Key))
+}
+
+func (s *cacheStore) Del(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.cache.Del("flags:" + key); err != nil {
+		return err
+	}
+
+	keys := []string{}
+	for _, one := range s.keys() {
+		if one != key {
+			keys = append(keys, one)
+		}
+	}
+
+	return s.cache.Set(cacheKeys, keys)
+}
+
+func (s *cacheStore) List() ([]*Flag, error) {
+	keys := s.keys()
+	sort.Strings(keys)
+
+	flags := make([]*Flag, 0, len(keys))
+	for _, key := range keys {
+		flag, err := s.Get(key)
+		if err != nil {
+			continue
+		}
+
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
+
+func (s *cacheStore) keys() []string {
+	keys := []string{}
+	if s.cache.Has(cacheKeys) {
+		s.cache.Get(cacheKeys, &keys)
+	}
+
+	return keys
+}
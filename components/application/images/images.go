@@ -0,0 +1,287 @@
+// Package images transforms images on the fly or at upload time, such as resizing, thumbnails and format conversion,
+// with the derived images cached.
+//
+// jpeg, png and gif are builtin, other formats can be added with image.RegisterFormat (decoding, such as golang.org/x/image/webp)
+// and RegisterEncoder (encoding).
+package images
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/tiered"
+)
+
+// ErrUnsupportedFormat is returned if the output format has no encoder.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Encoder encodes the image, quality is 1-100 for lossy formats.
+type Encoder func(w io.Writer, img image.Image, quality int) error
+
+var encoders = sync.Map{}
+
+func init() {
+	RegisterEncoder("jpeg", "image/jpeg", func(w io.Writer, img image.Image, quality int) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	})
+	RegisterEncoder("png", "image/png", func(w io.Writer, img image.Image, quality int) error {
+		return png.Encode(w, img)
+	})
+	RegisterEncoder("gif", "image/gif", func(w io.Writer, img image.Image, quality int) error {
+		return gif.Encode(w, img, nil)
+	})
+}
+
+type encoder struct {
+	contentType string
+	encode      Encoder
+}
+
+// RegisterEncoder registers the encoder of the format, such as webp:
+//
+//	images.RegisterEncoder("webp", "image/webp", func(w io.Writer, img image.Image, quality int) error {
+//		return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+//	})
+func RegisterEncoder(format string, contentType string, encode Encoder) {
+	encoders.Store(normalizeFormat(format), &encoder{contentType: contentType, encode: encode})
+}
+
+func normalizeFormat(format string) string {
+	format = strings.ToLower(format)
+	if format == "jpg" {
+		return "jpeg"
+	}
+
+	return format
+}
+
+// Image is the processed image.
+type Image struct {
+	Data        []byte `json:"data"`
+	ContentType string `json:"content_type"`
+	Format      string `json:"format"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// Options are the transform options.
+type Options struct {
+	// Width and Height of Resize, 0 keeps the aspect ratio.
+	Width  int
+	Height int
+	// Crop fills Width x Height, cropping the overflow from the center (thumbnail), both are required.
+	Crop bool
+	// Format is the output format, default the source format.
+	Format string
+	// Quality of lossy formats, default 85.
+	Quality int
+}
+
+// Option is the transform option.
+type Option func(opts *Options)
+
+// Resize resizes the image into width x height, 0 keeps the aspect ratio, images are never upscaled.
+func Resize(width, height int) Option {
+	return func(opts *Options) {
+		opts.Width = width
+		opts.Height = height
+		opts.Crop = false
+	}
+}
+
+// Thumbnail resizes the image to fill width x height, cropping the overflow from the center.
+func Thumbnail(width, height int) Option {
+	return func(opts *Options) {
+		opts.Width = width
+		opts.Height = height
+		opts.Crop = true
+	}
+}
+
+// Format converts the image into the format, such as jpeg, png, webp.
+func Format(format string) Option {
+	return func(opts *Options) {
+		opts.Format = normalizeFormat(format)
+	}
+}
+
+// Quality sets the quality of lossy formats, 1-100.
+func Quality(quality int) Option {
+	return func(opts *Options) {
+		opts.Quality = quality
+	}
+}
+
+func applyOptions(opts []Option) *Options {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Quality <= 0 || o.Quality > 100 {
+		o.Quality = 85
+	}
+
+	return o
+}
+
+// Key returns the cache key of the transforms, such as for the derived images of the source version.
+func Key(source string, opts ...Option) string {
+	o := applyOptions(opts)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%t|%s|%d", source, o.Width, o.Height, o.Crop, o.Format, o.Quality)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Process decodes the image, applies the transforms and encodes it,
+// the metadata (such as EXIF with GPS location) is stripped, as only pixels are re-encoded.
+func Process(r io.Reader, opts ...Option) (*Image, error) {
+	src, format, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+
+	return Encode(Transform(src, opts...), format, opts...)
+}
+
+// Transform applies the resize transforms to the image.
+func Transform(src image.Image, opts ...Option) image.Image {
+	o := applyOptions(opts)
+	if o.Width <= 0 && o.Height <= 0 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	if o.Crop && o.Width > 0 && o.Height > 0 {
+		return resize(src, cropToRatio(bounds, o.Width, o.Height), o.Width, o.Height)
+	}
+
+	width, height := fit(bounds.Dx(), bounds.Dy(), o.Width, o.Height)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return src
+	}
+
+	return resize(src, bounds, width, height)
+}
+
+// Encode encodes the image in the format of the options, or the source format.
+func Encode(img image.Image, sourceFormat string, opts ...Option) (*Image, error) {
+	o := applyOptions(opts)
+
+	format := o.Format
+	if format == "" {
+		format = normalizeFormat(sourceFormat)
+	}
+
+	v, ok := encoders.Load(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	enc := v.(*encoder)
+
+	buf := &bytes.Buffer{}
+	if err := enc.encode(buf, img, o.Quality); err != nil {
+		return nil, fmt.Errorf("failed to encode image as %s: %v", format, err)
+	}
+
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: enc.contentType,
+		Format:      format,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+// Derive generates the variants of the image at upload time, such as thumbnails, the image is decoded once.
+//
+//	variants, err := images.Derive(file, map[string][]images.Option{
+//		"thumbnail": {images.Thumbnail(200, 200), images.Format("jpeg")},
+//		"large":     {images.Resize(1600, 0)},
+//	})
+func Derive(r io.Reader, variants map[string][]Option) (map[string]*Image, error) {
+	src, format, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+
+	derived := make(map[string]*Image, len(variants))
+	for name, opts := range variants {
+		img, err := Encode(Transform(src, opts...), format, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s: %v", name, err)
+		}
+
+		derived[name] = img
+	}
+
+	return derived, nil
+}
+
+// Images processes the images with the derived images cached.
+type Images interface {
+	// Get returns the derived image of the source, which is the cache key with the version (such as modtime) of the source,
+	//	open is called to read the source on cache miss.
+	Get(source string, open func() (io.ReadCloser, error), opts ...Option) (*Image, error)
+}
+
+// Config ...
+type Config struct {
+	// Cache caches the derived images, nil means no cache.
+	Cache tiered.Cache
+	// TTL is the ttl of the cached images, default 24h.
+	TTL time.Duration
+	// Prefix is the cache key prefix, default images:.
+	Prefix string
+}
+
+type images struct {
+	cfg *Config
+}
+
+// New creates the images.
+func New(cfg *Config) Images {
+	if cfg.TTL == 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "images:"
+	}
+
+	return &images{cfg: cfg}
+}
+
+func (i *images) Get(source string, open func() (io.ReadCloser, error), opts ...Option) (*Image, error) {
+	process := func() (*Image, error) {
+		r, err := open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		return Process(r, opts...)
+	}
+
+	if i.cfg.Cache == nil {
+		return process()
+	}
+
+	img := &Image{}
+	err := i.cfg.Cache.GetOrSet(i.cfg.Prefix+Key(source, opts...), img, i.cfg.TTL, func() (any, error) {
+		return process()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
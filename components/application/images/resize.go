@@ -0,0 +1,83 @@
+package images
+
+import (
+	"image"
+	"image/draw"
+)
+
+// fit returns the size fitting into width x height with the aspect ratio kept, never upscaled.
+func fit(srcWidth, srcHeight, width, height int) (int, int) {
+	if width <= 0 || width > srcWidth {
+		width = srcWidth
+	}
+	if height <= 0 || height > srcHeight {
+		height = srcHeight
+	}
+
+	// scale by the smaller ratio
+	if width*srcHeight < height*srcWidth {
+		height = max(1, width*srcHeight/srcWidth)
+	} else {
+		width = max(1, height*srcWidth/srcHeight)
+	}
+
+	return width, height
+}
+
+// cropToRatio returns the centered rect of the bounds with the ratio width:height.
+func cropToRatio(bounds image.Rectangle, width, height int) image.Rectangle {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w*height > h*width {
+		cropped := h * width / height
+		x := bounds.Min.X + (w-cropped)/2
+		return image.Rect(x, bounds.Min.Y, x+cropped, bounds.Max.Y)
+	}
+
+	cropped := w * height / width
+	y := bounds.Min.Y + (h-cropped)/2
+	return image.Rect(bounds.Min.X, y, bounds.Max.X, y+cropped)
+}
+
+// resize scales the rect of the image into width x height, averaging the source pixels (box filter).
+func resize(img image.Image, rect image.Rectangle, width, height int) image.Image {
+	src := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(src, src.Bounds(), img, rect.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max(y0+1, (y+1)*sh/height)
+
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max(x0+1, (x+1)*sw/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					pa := uint64(src.Pix[offset+3])
+					// premultiplied, so transparent pixels do not bleed
+					r += uint64(src.Pix[offset]) * pa
+					g += uint64(src.Pix[offset+1]) * pa
+					b += uint64(src.Pix[offset+2]) * pa
+					a += pa
+					n++
+					offset += 4
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			if a > 0 {
+				dst.Pix[i] = uint8(r / a)
+				dst.Pix[i+1] = uint8(g / a)
+				dst.Pix[i+2] = uint8(b / a)
+			}
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
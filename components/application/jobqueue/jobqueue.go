@@ -1,7 +1,10 @@
 package jobqueue
 
 import (
+	"context"
+	"errors"
 	"runtime"
+	"sync"
 
 	jq "github.com/go-zoox/jobqueue"
 )
@@ -12,11 +15,26 @@ type JobQueue interface {
 	AddJobFunc(task func(), callback func(status int, err error)) error
 }
 
+// Shutdowner is implemented by the job queue drained on app shutdown.
+type Shutdowner interface {
+	// Shutdown stops accepting jobs and waits for the pending jobs added by AddJobFunc until ctx is done.
+	Shutdown(ctx context.Context) error
+}
+
+// ErrStopped is returned when adding jobs after shutdown.
+var ErrStopped = errors.New("job queue is stopped")
+
 type jobqueue struct {
+	sync.Mutex
 	isStarted bool
+	isStopped bool
 	core      *jq.JobQueue
+	// pending are the running and queued jobs of AddJobFunc
+	pending sync.WaitGroup
 }
 
+var _ Shutdowner = (*jobqueue)(nil)
+
 // New creates a job queue.
 func New() JobQueue {
 	core := jq.New(runtime.NumCPU())
@@ -28,8 +46,8 @@ func New() JobQueue {
 
 // AddJob ...
 func (q *jobqueue) AddJob(job jq.Job) error {
-	if !q.isStarted {
-		q.core.Start()
+	if err := q.start(); err != nil {
+		return err
 	}
 
 	q.core.AddJob(job)
@@ -38,9 +56,62 @@ func (q *jobqueue) AddJob(job jq.Job) error {
 
 // AddJobFunc ...
 func (q *jobqueue) AddJobFunc(task func(), callback func(status int, err error)) error {
+	if err := q.start(); err != nil {
+		return err
+	}
+
+	q.pending.Add(1)
+	var once sync.Once
+	done := func() {
+		once.Do(q.pending.Done)
+	}
+
+	q.core.AddJob(jq.NewJob(func() {
+		defer done()
+		task()
+	}, func(status int, err error) {
+		// the task may not run, such as timeout
+		defer done()
+		if callback != nil {
+			callback(status, err)
+		}
+	}))
+	return nil
+}
+
+// Shutdown stops accepting jobs and drains the pending jobs.
+func (q *jobqueue) Shutdown(ctx context.Context) error {
+	q.Lock()
+	q.isStopped = true
+	q.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start starts the workers on the first job.
+func (q *jobqueue) start() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.isStopped {
+		return ErrStopped
+	}
+
 	if !q.isStarted {
 		q.core.Start()
+		q.isStarted = true
 	}
 
-	return q.AddJob(jq.NewJob(task, callback))
+	return nil
 }
@@ -0,0 +1,252 @@
+// Package jsonrpcclient is the typed client of zoox JSON-RPC 2.0 services (app.JSONRPC),
+// with connection pooling, retries, timeout and tracing propagation.
+//
+// Example:
+//
+//	client := jsonrpcclient.New("http://calc.internal/rpc")
+//
+//	var reply int
+//	err := client.Call(ctx, "Add", map[string]int{"a": 1, "b": 2}, &reply)
+package jsonrpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/discovery"
+)
+
+// Config is the config of the client.
+type Config struct {
+	// Timeout is the timeout of a call, including the retries, default: 10s.
+	Timeout time.Duration
+	// Retries is the max retries of the connection errors and 502, 503, 504 responses, default: 2, -1 disables.
+	Retries int
+	// RetryBackoff is the backoff of the first retry, doubled for each retry, default: 100ms.
+	RetryBackoff time.Duration
+	// MaxIdleConnsPerHost is the size of the connection pool, default: 64.
+	MaxIdleConnsPerHost int
+	// Headers are sent with the requests, such as authorization.
+	Headers map[string]string
+	// HTTPClient overrides the pooled http client.
+	HTTPClient *http.Client
+	// Discovery picks the host (host:port) of each attempt, replacing the host of the url,
+	// the endpoints failed with connection errors or 502, 503, 504 are skipped by the retries.
+	Discovery *discovery.Target
+	// Hedge sends the second attempt of the slow idempotent calls, improving the tail latency, default: disabled.
+	Hedge *HedgeConfig
+}
+
+// Error is the error response of JSON-RPC.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// ErrHTTPStatus is returned when the server responds with non-200 status.
+var ErrHTTPStatus = errors.New("jsonrpc: unexpected http status")
+
+// Client is the JSON-RPC client.
+type Client struct {
+	url    string
+	cfg    *Config
+	client *http.Client
+	hedger *hedger
+	id     atomic.Uint64
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// New creates the client of the JSON-RPC endpoint, the client is safe for concurrent use and should be reused.
+func New(url string, cfg ...*Config) *Client {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	if cfgX.Timeout == 0 {
+		cfgX.Timeout = 10 * time.Second
+	}
+	if cfgX.Retries == 0 {
+		cfgX.Retries = 2
+	}
+	if cfgX.RetryBackoff == 0 {
+		cfgX.RetryBackoff = 100 * time.Millisecond
+	}
+	if cfgX.MaxIdleConnsPerHost == 0 {
+		cfgX.MaxIdleConnsPerHost = 64
+	}
+
+	client := cfgX.HTTPClient
+	if client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = cfgX.MaxIdleConnsPerHost * 2
+		transport.MaxIdleConnsPerHost = cfgX.MaxIdleConnsPerHost
+		client = &http.Client{Transport: transport}
+	}
+
+	var h *hedger
+	if cfgX.Hedge != nil {
+		h = newHedger(cfgX.Hedge)
+	}
+
+	return &Client{
+		url:    url,
+		cfg:    cfgX,
+		client: client,
+		hedger: h,
+	}
+}
+
+// Call calls the method with the params, the result is decoded into reply (pointer), nil reply discards the result.
+// The JSON-RPC error responses are returned as *Error.
+func (c *Client) Call(ctx context.Context, method string, params any, reply any) error {
+	id := c.id.Add(1)
+	body, err := json.Marshal(&request{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("jsonrpc: failed to encode params: %v", err)
+	}
+
+	data, err := c.do(ctx, body, c.hedger.hedgeable(ctx, method))
+	if err != nil {
+		return err
+	}
+
+	var res response
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("jsonrpc: failed to decode response: %v", err)
+	}
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if reply == nil || len(res.Result) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(res.Result, reply); err != nil {
+		return fmt.Errorf("jsonrpc: failed to decode result: %v", err)
+	}
+
+	return nil
+}
+
+// Notify sends the notification, which has no response.
+func (c *Client) Notify(ctx context.Context, method string, params any) error {
+	body, err := json.Marshal(&request{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("jsonrpc: failed to encode params: %v", err)
+	}
+
+	// notifications have no response to hedge
+	_, err = c.do(ctx, body, false)
+	return err
+}
+
+// do posts the body with retries, within the timeout, the hedged attempts are sent if hedge is true.
+func (c *Client) do(ctx context.Context, body []byte, hedge bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	backoff := c.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		var data []byte
+		var retryable bool
+		var err error
+		if hedge {
+			data, retryable, err = c.hedged(ctx, body)
+		} else {
+			data, retryable, err = c.post(ctx, body)
+		}
+		if err == nil || !retryable || attempt >= c.cfg.Retries {
+			return data, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) post(ctx context.Context, body []byte) (data []byte, retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	if c.cfg.Discovery != nil {
+		endpoint, err := c.cfg.Discovery.Next(ctx)
+		if err != nil {
+			return nil, errors.Is(err, discovery.ErrNoEndpoints), err
+		}
+
+		req.URL.Host = endpoint.Address
+		req.Host = endpoint.Address
+		defer func() {
+			if retryable {
+				c.cfg.Discovery.MarkFailed(endpoint.Address)
+			}
+		}()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+	propagate(ctx, req.Header)
+
+	start := time.Now()
+	res, err := c.client.Do(req)
+	if err != nil {
+		// the connection errors are retried, unless the call is cancelled or timed out
+		var netErr net.Error
+		return nil, ctx.Err() == nil && (errors.As(err, &netErr) || errors.Is(err, io.EOF)), err
+	}
+	defer res.Body.Close()
+
+	data, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		switch res.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			retryable = true
+		}
+
+		return nil, retryable, fmt.Errorf("%w: %s", ErrHTTPStatus, res.Status)
+	}
+
+	if c.hedger != nil {
+		c.hedger.observe(time.Since(start))
+	}
+
+	return data, false, nil
+}
@@ -0,0 +1,187 @@
+package jsonrpcclient
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const importPath = "github.com/go-zoox/zoox/components/application/jsonrpcclient"
+
+// Method describes the method of the service for generating the typed client.
+type Method struct {
+	Name string
+	// Params is the value of params type, such as AddParams{}, nil means no params.
+	Params any
+	// Result is the value of result type, such as AddResult{}, nil means no result.
+	Result any
+}
+
+// GenerateConfig is the config of Generate.
+type GenerateConfig struct {
+	// Package is the package name of the generated code, default: client.
+	Package string
+	// Name is the type name of the typed client, default: Client.
+	Name string
+}
+
+// Generate generates the typed client of the methods, such as the methods described by app.DescribeJSONRPC.
+func Generate(cfg *GenerateConfig, methods ...Method) ([]byte, error) {
+	pkg := cfg.Package
+	if pkg == "" {
+		pkg = "client"
+	}
+	name := cfg.Name
+	if name == "" {
+		name = "Client"
+	}
+
+	g := &generator{imports: map[string]string{importPath: "jsonrpcclient"}}
+	body := &bytes.Buffer{}
+
+	fmt.Fprintf(body, "// %s is the typed client of the JSON-RPC service.\n", name)
+	fmt.Fprintf(body, "type %s struct {\n\tclient *jsonrpcclient.Client\n}\n\n", name)
+	fmt.Fprintf(body, "// New%s creates the typed client of the JSON-RPC endpoint.\n", name)
+	fmt.Fprintf(body, "func New%s(url string, cfg ...*jsonrpcclient.Config) *%s {\n\treturn &%s{client: jsonrpcclient.New(url, cfg...)}\n}\n", name, name, name)
+
+	sorted := append([]Method{}, methods...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, m := range sorted {
+		fn := identifier(m.Name)
+		if fn == "" {
+			return nil, fmt.Errorf("jsonrpc: invalid method name: %q", m.Name)
+		}
+
+		args := "ctx context.Context"
+		params := "nil"
+		if m.Params != nil {
+			args += ", params " + g.typeExpr(reflect.TypeOf(m.Params))
+			params = "params"
+		}
+
+		fmt.Fprintf(body, "\n// %s calls %s.\n", fn, m.Name)
+		if m.Result == nil {
+			fmt.Fprintf(body, "func (c *%s) %s(%s) error {\n", name, fn, args)
+			fmt.Fprintf(body, "\treturn c.client.Call(ctx, %q, %s, nil)\n}\n", m.Name, params)
+			continue
+		}
+
+		result := g.typeExpr(reflect.TypeOf(m.Result))
+		fmt.Fprintf(body, "func (c *%s) %s(%s) (%s, error) {\n", name, fn, args, result)
+		fmt.Fprintf(body, "\tvar result %s\n", result)
+		fmt.Fprintf(body, "\terr := c.client.Call(ctx, %q, %s, &result)\n", m.Name, params)
+		fmt.Fprintf(body, "\treturn result, err\n}\n")
+	}
+
+	code := &bytes.Buffer{}
+	fmt.Fprintf(code, "// Code generated by zoox. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	fmt.Fprintf(code, "\t\"context\"\n\n\t%q\n", importPath)
+	paths := []string{}
+	for p := range g.imports {
+		if p != importPath {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if alias := g.imports[p]; alias != path.Base(p) {
+			fmt.Fprintf(code, "\t%s %q\n", alias, p)
+		} else {
+			fmt.Fprintf(code, "\t%q\n", p)
+		}
+	}
+	fmt.Fprintf(code, ")\n\n")
+	code.Write(body.Bytes())
+
+	formatted, err := format.Source(code.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: failed to format generated code: %v", err)
+	}
+
+	return formatted, nil
+}
+
+type generator struct {
+	// imports are the aliases of the package paths
+	imports map[string]string
+}
+
+// typeExpr returns the go expression of the type, importing the packages of the named types.
+func (g *generator) typeExpr(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+
+		return g.alias(t.PkgPath()) + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + g.typeExpr(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeExpr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeExpr(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", g.typeExpr(t.Key()), g.typeExpr(t.Elem()))
+	case reflect.Interface:
+		return "any"
+	default:
+		// anonymous structs and others are kept as is
+		return t.String()
+	}
+}
+
+func (g *generator) alias(pkgPath string) string {
+	if alias, ok := g.imports[pkgPath]; ok {
+		return alias
+	}
+
+	base := identifier(path.Base(pkgPath))
+	alias := strings.ToLower(base)
+	taken := map[string]bool{"context": true, "jsonrpcclient": true}
+	for _, a := range g.imports {
+		taken[a] = true
+	}
+	for i := 2; taken[alias]; i++ {
+		alias = fmt.Sprintf("%s%d", strings.ToLower(base), i)
+	}
+
+	g.imports[pkgPath] = alias
+	return alias
+}
+
+// identifier converts the method name to the exported go identifier, such as user.get_by_id => UserGetByID.
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	b := &strings.Builder{}
+	for _, word := range words {
+		if strings.EqualFold(word, "id") {
+			b.WriteString("ID")
+			continue
+		}
+
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "M" + s
+	}
+
+	return s
+}
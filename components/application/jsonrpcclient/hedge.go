@@ -0,0 +1,177 @@
+package jsonrpcclient
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HedgeConfig is the config of the hedged requests, the second attempt is sent if the first one
+// does not respond within the latency percentile, the first response wins and the other is cancelled.
+type HedgeConfig struct {
+	// Methods are the idempotent methods to hedge, the other methods are hedged only with WithIdempotent.
+	Methods []string
+	// Delay is the fixed delay of the second attempt, default: the Percentile latency of the recent calls.
+	Delay time.Duration
+	// Percentile is the latency percentile of the delay, default: 0.95.
+	Percentile float64
+	// MinSamples is the min calls observed before hedging by the percentile, default: 20.
+	MinSamples int
+	// Budget is the max ratio of the hedged requests to the calls, default: 0.1 (10%).
+	Budget float64
+	// MaxBurst is the max hedged requests on a burst of slow calls, default: 10.
+	MaxBurst float64
+}
+
+const latencyWindow = 256
+
+type hedger struct {
+	cfg     *HedgeConfig
+	methods map[string]bool
+	//
+	mu        sync.Mutex
+	latencies [latencyWindow]time.Duration
+	count     int
+	tokens    float64
+}
+
+func newHedger(cfg *HedgeConfig) *hedger {
+	if cfg.Percentile == 0 {
+		cfg.Percentile = 0.95
+	}
+	if cfg.MinSamples == 0 {
+		cfg.MinSamples = 20
+	}
+	if cfg.Budget == 0 {
+		cfg.Budget = 0.1
+	}
+	if cfg.MaxBurst == 0 {
+		cfg.MaxBurst = 10
+	}
+
+	methods := map[string]bool{}
+	for _, method := range cfg.Methods {
+		methods[method] = true
+	}
+
+	return &hedger{cfg: cfg, methods: methods}
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent returns the context marking the calls idempotent, which are safe to hedge.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func (h *hedger) hedgeable(ctx context.Context, method string) bool {
+	if h == nil {
+		return false
+	}
+
+	idempotent, _ := ctx.Value(idempotentKey{}).(bool)
+	return idempotent || h.methods[method]
+}
+
+// observe records the latency of the succeeded request.
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.latencies[h.count%latencyWindow] = latency
+	h.count++
+}
+
+// delay returns the delay of the second attempt, false if there are not enough samples,
+// and deposits the budget of the call.
+func (h *hedger) delay() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tokens += h.cfg.Budget
+	if h.tokens > h.cfg.MaxBurst {
+		h.tokens = h.cfg.MaxBurst
+	}
+
+	if h.cfg.Delay != 0 {
+		return h.cfg.Delay, true
+	}
+
+	n := h.count
+	if n > latencyWindow {
+		n = latencyWindow
+	}
+	if n < h.cfg.MinSamples {
+		return 0, false
+	}
+
+	latencies := make([]time.Duration, n)
+	copy(latencies, h.latencies[:n])
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	return latencies[int(float64(n-1)*h.cfg.Percentile)], true
+}
+
+// take takes the budget of a hedged request.
+func (h *hedger) take() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tokens < 1 {
+		return false
+	}
+
+	h.tokens--
+	return true
+}
+
+type result struct {
+	data      []byte
+	retryable bool
+	err       error
+}
+
+// hedged posts the body, and posts it again after the delay within the budget,
+// the first succeeded (or not retryable) response wins.
+func (c *Client) hedged(ctx context.Context, body []byte) ([]byte, bool, error) {
+	delay, ok := c.hedger.delay()
+	if !ok {
+		return c.post(ctx, body)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan result, 2)
+	send := func() {
+		go func() {
+			data, retryable, err := c.post(ctx, body)
+			results <- result{data: data, retryable: retryable, err: err}
+		}()
+	}
+
+	send()
+	inflight := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if c.hedger.take() {
+				send()
+				inflight++
+			}
+		case r := <-results:
+			inflight--
+			// the failed attempt waits for the other in flight
+			if r.err == nil || !r.retryable || inflight == 0 {
+				return r.data, r.retryable, r.err
+			}
+		}
+	}
+}
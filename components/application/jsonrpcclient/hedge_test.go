@@ -0,0 +1,124 @@
+package jsonrpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newHedgeServer responds the first attempt of each call after slow, and the others immediately.
+func newHedgeServer(slow time.Duration) (*httptest.Server, func(id int64) int) {
+	var mu sync.Mutex
+	attempts := map[int64]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID int64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		attempts[req.ID]++
+		first := attempts[req.ID] == 1
+		mu.Unlock()
+
+		if first {
+			select {
+			case <-time.After(slow):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"ok"}`))
+	}))
+
+	return server, func(id int64) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts[id]
+	}
+}
+
+func TestHedgeIdempotentGating(t *testing.T) {
+	server, attempts := newHedgeServer(200 * time.Millisecond)
+	defer server.Close()
+
+	client := New(server.URL, &Config{
+		Hedge: &HedgeConfig{Methods: []string{"get"}, Delay: 20 * time.Millisecond, Budget: 1},
+	})
+
+	cases := []struct {
+		ctx      context.Context
+		method   string
+		attempts int
+	}{
+		{context.Background(), "get", 2},
+		{context.Background(), "set", 1},
+		{WithIdempotent(context.Background()), "set", 2},
+	}
+
+	for i, c := range cases {
+		start := time.Now()
+		var reply string
+		if err := client.Call(c.ctx, c.method, nil, &reply); err != nil {
+			t.Fatal(err)
+		}
+		elapsed := time.Since(start)
+
+		id := int64(i + 1)
+		if n := attempts(id); n != c.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", c.method, c.attempts, n)
+		}
+		if hedged := elapsed < 150*time.Millisecond; hedged != (c.attempts == 2) {
+			t.Errorf("%s: unexpected latency %s", c.method, elapsed)
+		}
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	server, attempts := newHedgeServer(50 * time.Millisecond)
+	defer server.Close()
+
+	// half a token per call, at most one hedged request in a burst
+	client := New(server.URL, &Config{
+		Hedge: &HedgeConfig{Methods: []string{"get"}, Delay: 10 * time.Millisecond, Budget: 0.5, MaxBurst: 1},
+	})
+
+	hedged := 0
+	for i := 1; i <= 6; i++ {
+		if err := client.Call(context.Background(), "get", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		// the loser may still be in flight
+		time.Sleep(5 * time.Millisecond)
+		if attempts(int64(i)) == 2 {
+			hedged++
+		}
+	}
+
+	if hedged != 3 {
+		t.Errorf("expected 3 hedged calls within the budget, got %d", hedged)
+	}
+}
+
+func TestHedgePercentileNeedsSamples(t *testing.T) {
+	h := newHedger(&HedgeConfig{MinSamples: 3, Percentile: 0.5})
+	if _, ok := h.delay(); ok {
+		t.Fatal("expected no delay without samples")
+	}
+
+	for _, latency := range []time.Duration{30, 10, 20} {
+		h.observe(latency * time.Millisecond)
+	}
+
+	delay, ok := h.delay()
+	if !ok || delay != 20*time.Millisecond {
+		t.Errorf("expected the median 20ms, got %s (%v)", delay, ok)
+	}
+}
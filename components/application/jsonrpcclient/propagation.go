@@ -0,0 +1,60 @@
+package jsonrpcclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// PropagatedHeaders are the headers propagated from the incoming request to the calls,
+// such as the W3C trace context and request id.
+var PropagatedHeaders = []string{"Traceparent", "Tracestate", "Baggage", "X-Request-Id"}
+
+// TimeoutHeader is the header of the remaining time (milliseconds) of the call deadline,
+// the zoox JSON-RPC server (app.JSONRPC) stops the method when it is exceeded.
+const TimeoutHeader = "X-Request-Timeout"
+
+type propagationKey struct{}
+
+// WithPropagation returns the context carrying the PropagatedHeaders of the incoming request headers,
+// they are sent with the calls of the context, see zoox ctx.OutgoingContext.
+func WithPropagation(ctx context.Context, header http.Header) context.Context {
+	propagated := http.Header{}
+	for _, key := range PropagatedHeaders {
+		if value := header.Get(key); value != "" {
+			propagated.Set(key, value)
+		}
+	}
+
+	return context.WithValue(ctx, propagationKey{}, propagated)
+}
+
+// ParseTimeout parses the TimeoutHeader, false if absent or invalid.
+func ParseTimeout(header http.Header) (time.Duration, bool) {
+	ms, err := strconv.ParseInt(header.Get(TimeoutHeader), 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+func propagate(ctx context.Context, header http.Header) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline).Milliseconds(); remaining > 0 {
+			header.Set(TimeoutHeader, strconv.FormatInt(remaining, 10))
+		}
+	}
+
+	propagated, ok := ctx.Value(propagationKey{}).(http.Header)
+	if !ok {
+		return
+	}
+
+	for key, values := range propagated {
+		if header.Get(key) == "" {
+			header[key] = values
+		}
+	}
+}
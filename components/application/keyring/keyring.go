@@ -0,0 +1,165 @@
+// Package keyring encrypts (AES-256-GCM) and signs (HMAC-SHA256) payloads, with multiple active keys for rotation:
+// the primary key encrypts and signs, and all keys decrypt and verify, so the payloads of old keys are still valid.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix is the prefix of the encrypted payloads: enc:v1:<key id>:<base64 nonce+ciphertext>.
+const Prefix = "enc:v1:"
+
+// ErrNotEncrypted is returned by Decrypt if the payload is not encrypted by the keyring.
+var ErrNotEncrypted = errors.New("payload is not encrypted")
+
+// ErrUnknownKey is returned by Decrypt if the key of the payload is not in the keyring, such as removed after rotation.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring encrypts and decrypts the payloads.
+type Keyring interface {
+	// Encrypt encrypts the plaintext with the primary key.
+	Encrypt(plaintext []byte) (string, error)
+	// Decrypt decrypts the payload with the key it was encrypted by.
+	Decrypt(payload string) ([]byte, error)
+	// IsEncrypted reports whether the payload is encrypted by a keyring.
+	IsEncrypted(payload string) bool
+	// Sign signs the data with the primary key (HMAC-SHA256), the signature is <key id>.<base64 mac>.
+	Sign(data []byte) string
+	// Verify verifies the signature with the key it was signed by.
+	Verify(data []byte, signature string) bool
+}
+
+type key struct {
+	id   string
+	aead cipher.AEAD
+	// mac is the signing key, derived separately from the encryption key
+	mac []byte
+}
+
+type keyring struct {
+	primary *key
+	keys    map[string]*key
+}
+
+// New creates the keyring of the secrets, the first is the primary key,
+// the secrets are derived into 256-bit keys with sha256, the key id is derived from the key.
+//
+// To rotate, prepend the new secret and keep the old ones until their payloads expire.
+func New(secrets ...string) (Keyring, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("keyring: at least one key is required")
+	}
+
+	k := &keyring{
+		keys: map[string]*key{},
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			return nil, errors.New("keyring: empty key")
+		}
+
+		material := sha256.Sum256([]byte(secret))
+		block, err := aes.NewCipher(material[:])
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		id := sha256.Sum256(material[:])
+		mac := sha256.Sum256([]byte("sign:" + secret))
+		one := &key{id: hex.EncodeToString(id[:4]), aead: aead, mac: mac[:]}
+		if k.primary == nil {
+			k.primary = one
+		}
+		k.keys[one.id] = one
+	}
+
+	return k, nil
+}
+
+func (k *keyring) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, k.primary.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// the key id is authenticated as additional data
+	sealed := k.primary.aead.Seal(nonce, nonce, plaintext, []byte(k.primary.id))
+	return Prefix + k.primary.id + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (k *keyring) Decrypt(payload string) ([]byte, error) {
+	if !k.IsEncrypted(payload) {
+		return nil, ErrNotEncrypted
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(payload, Prefix), ":")
+	if !ok {
+		return nil, ErrNotEncrypted
+	}
+
+	one, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("keyring: invalid payload: %v", err)
+	}
+
+	size := one.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, errors.New("keyring: invalid payload")
+	}
+
+	return one.aead.Open(nil, sealed[:size], sealed[size:], []byte(id))
+}
+
+func (k *keyring) IsEncrypted(payload string) bool {
+	return strings.HasPrefix(payload, Prefix)
+}
+
+func (k *keyring) Sign(data []byte) string {
+	return k.primary.id + "." + base64.RawURLEncoding.EncodeToString(k.primary.sign(data))
+}
+
+func (k *keyring) Verify(data []byte, signature string) bool {
+	id, encoded, ok := strings.Cut(signature, ".")
+	if !ok {
+		return false
+	}
+
+	one, ok := k.keys[id]
+	if !ok {
+		return false
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(mac, one.sign(data))
+}
+
+func (k *key) sign(data []byte) []byte {
+	h := hmac.New(sha256.New, k.mac)
+	h.Write([]byte(k.id))
+	h.Write(data)
+	return h.Sum(nil)
+}
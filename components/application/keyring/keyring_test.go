@@ -0,0 +1,172 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustNew(t *testing.T, secrets ...string) Keyring {
+	t.Helper()
+
+	k, err := New(secrets...)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	return k
+}
+
+func mustEncrypt(t *testing.T, k Keyring) string {
+	t.Helper()
+
+	payload, err := k.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+
+	return payload
+}
+
+func TestNewRequiresKeys(t *testing.T) {
+	cases := [][]string{
+		nil,
+		{""},
+		{"key_2024_06", ""},
+	}
+
+	for _, secrets := range cases {
+		if _, err := New(secrets...); err == nil {
+			t.Errorf("%q: expected error", secrets)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	k := mustNew(t, "key_2024_06")
+
+	a, err := k.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	b, _ := k.Encrypt([]byte("secret"))
+	if a == b {
+		t.Errorf("expected the nonces to differ")
+	}
+	if !k.IsEncrypted(a) || strings.Contains(a, "secret") {
+		t.Errorf("expected the payload to be encrypted, got %s", a)
+	}
+
+	plaintext, err := k.Decrypt(a)
+	if err != nil || string(plaintext) != "secret" {
+		t.Errorf("expected secret, got %q %v", plaintext, err)
+	}
+}
+
+func TestDecryptRotation(t *testing.T) {
+	old := mustNew(t, "key_2024_01")
+	payload, _ := old.Encrypt([]byte("secret"))
+
+	cases := []struct {
+		name    string
+		keyring Keyring
+		err     error
+	}{
+		{"same key", old, nil},
+		{"rotated, the old key is kept", mustNew(t, "key_2024_06", "key_2024_01"), nil},
+		{"rotated, the old key is removed", mustNew(t, "key_2024_06"), ErrUnknownKey},
+		{"other key", mustNew(t, "other"), ErrUnknownKey},
+	}
+
+	for _, c := range cases {
+		plaintext, err := c.keyring.Decrypt(payload)
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+			continue
+		}
+		if c.err == nil && string(plaintext) != "secret" {
+			t.Errorf("%s: expected secret, got %q", c.name, plaintext)
+		}
+	}
+
+	// the primary key encrypts after rotation
+	rotated := mustNew(t, "key_2024_06", "key_2024_01")
+	payload, _ = rotated.Encrypt([]byte("secret"))
+	if _, err := old.Decrypt(payload); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected the new primary key to encrypt, got %v", err)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	k := mustNew(t, "key_2024_06", "key_2024_01")
+	other := mustNew(t, "key_2024_01")
+	payload, _ := k.Encrypt([]byte("secret"))
+
+	id, encoded, _ := strings.Cut(strings.TrimPrefix(payload, Prefix), ":")
+	sealed, _ := base64.RawURLEncoding.DecodeString(encoded)
+	flipped := append([]byte{}, sealed...)
+	flipped[len(flipped)-1] ^= 1
+	otherID, _, _ := strings.Cut(strings.TrimPrefix(mustEncrypt(t, other), Prefix), ":")
+
+	cases := []struct {
+		name    string
+		payload string
+		err     error
+	}{
+		{"plaintext", "secret", ErrNotEncrypted},
+		{"without key id", Prefix + encoded, ErrNotEncrypted},
+		{"flipped ciphertext", Prefix + id + ":" + base64.RawURLEncoding.EncodeToString(flipped), nil},
+		{"truncated", Prefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed[:4]), nil},
+		{"invalid base64", Prefix + id + ":" + encoded + "!", nil},
+		// the key id is authenticated, the payload can't be moved to another key
+		{"swapped key id", Prefix + otherID + ":" + encoded, nil},
+		{"unknown key id", Prefix + "deadbeef:" + encoded, ErrUnknownKey},
+	}
+
+	for _, c := range cases {
+		plaintext, err := k.Decrypt(c.payload)
+		if err == nil {
+			t.Errorf("%s: expected error, got %q", c.name, plaintext)
+			continue
+		}
+		if c.err != nil && !errors.Is(err, c.err) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	old := mustNew(t, "key_2024_01")
+	rotated := mustNew(t, "key_2024_06", "key_2024_01")
+	signature := old.Sign([]byte("data"))
+
+	id, mac, _ := strings.Cut(signature, ".")
+	rotatedID, _, _ := strings.Cut(rotated.Sign([]byte("data")), ".")
+	decoded, _ := base64.RawURLEncoding.DecodeString(mac)
+	decoded[0] ^= 1
+
+	cases := []struct {
+		name      string
+		keyring   Keyring
+		data      string
+		signature string
+		valid     bool
+	}{
+		{"same key", old, "data", signature, true},
+		{"rotated, the old key is kept", rotated, "data", signature, true},
+		{"rotated, the old key is removed", mustNew(t, "key_2024_06"), "data", signature, false},
+		{"tampered data", old, "date", signature, false},
+		{"tampered mac", old, "data", id + "." + base64.RawURLEncoding.EncodeToString(decoded), false},
+		{"swapped key id", rotated, "data", rotatedID + "." + mac, false},
+		{"without key id", old, "data", mac, false},
+		{"invalid base64", old, "data", id + ".!", false},
+		{"empty", old, "data", "", false},
+	}
+
+	for _, c := range cases {
+		if valid := c.keyring.Verify([]byte(c.data), c.signature); valid != c.valid {
+			t.Errorf("%s: expected %v, got %v", c.name, c.valid, valid)
+		}
+	}
+}
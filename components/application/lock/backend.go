@@ -0,0 +1,129 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type memoryEntry struct {
+	token    string
+	expireAt time.Time
+}
+
+type memoryBackend struct {
+	sync.Mutex
+	locks map[string]*memoryEntry
+}
+
+// NewMemory creates an in-process backend, locks are not shared across instances.
+func NewMemory() Backend {
+	return &memoryBackend{
+		locks: map[string]*memoryEntry{},
+	}
+}
+
+func (b *memoryBackend) Acquire(name, token string, ttl time.Duration) (bool, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if entry, ok := b.locks[name]; ok && time.Now().Before(entry.expireAt) {
+		return false, nil
+	}
+
+	b.locks[name] = &memoryEntry{token: token, expireAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (b *memoryBackend) Renew(name, token string, ttl time.Duration) (bool, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	entry, ok := b.locks[name]
+	if !ok || entry.token != token || time.Now().After(entry.expireAt) {
+		return false, nil
+	}
+
+	entry.expireAt = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (b *memoryBackend) Release(name, token string) (bool, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	entry, ok := b.locks[name]
+	if !ok || entry.token != token {
+		return false, nil
+	}
+
+	delete(b.locks, name)
+	return time.Now().Before(entry.expireAt), nil
+}
+
+// RedisConfig is the config of redis backend.
+type RedisConfig struct {
+	Host     string
+	Port     int
+	DB       int
+	Username string
+	Password string
+}
+
+// renew and release only if the lock is still held by the token
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type redisBackend struct {
+	client *redis.Client
+}
+
+// NewRedis creates a redis backend (SET NX PX with token, compare-and-delete on release),
+// locks are shared across instances.
+func NewRedis(cfg *RedisConfig) Backend {
+	return &redisBackend{
+		client: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			DB:       cfg.DB,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}),
+	}
+}
+
+func (b *redisBackend) Acquire(name, token string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return b.client.SetNX(ctx, name, token, ttl).Result()
+}
+
+func (b *redisBackend) Renew(name, token string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := renewScript.Run(ctx, b.client, []string{name}, token, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+func (b *redisBackend) Release(name, token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	n, err := releaseScript.Run(ctx, b.client, []string{name}, token).Int()
+	return n == 1, err
+}
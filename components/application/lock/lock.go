@@ -0,0 +1,187 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/random"
+)
+
+// ErrLocked is returned by TryAcquire if the lock is held by others.
+var ErrLocked = errors.New("lock is held by others")
+
+// ErrNotHeld is returned by Release if the lock has expired or is held by others.
+var ErrNotHeld = errors.New("lock is not held")
+
+// DefaultTTL is the default ttl of locks.
+const DefaultTTL = 30 * time.Second
+
+// DefaultRetryInterval is the default interval to retry acquiring the held lock.
+const DefaultRetryInterval = 100 * time.Millisecond
+
+// Backend stores the locks, the token identifies the holder.
+type Backend interface {
+	// Acquire sets the lock if not held, returns false if held by others.
+	Acquire(name, token string, ttl time.Duration) (bool, error)
+	// Renew extends the ttl if held by the token, returns false if lost.
+	Renew(name, token string, ttl time.Duration) (bool, error)
+	// Release deletes the lock if held by the token, returns false if lost.
+	Release(name, token string) (bool, error)
+}
+
+// Lock is an acquired lock, renewed automatically until released.
+type Lock interface {
+	Name() string
+	// Context is done when the lock is released or lost (such as renewal failed),
+	//	work under the lock should stop then.
+	Context() context.Context
+	// Release releases the lock.
+	Release() error
+}
+
+// Locker acquires the locks.
+type Locker interface {
+	// Acquire blocks until the lock is acquired or ctx is done.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (Lock, error)
+	// TryAcquire acquires the lock without waiting, returns ErrLocked if held by others.
+	TryAcquire(ctx context.Context, name string, ttl time.Duration) (Lock, error)
+}
+
+// Config is the config of Locker.
+type Config struct {
+	// Backend stores the locks, default: memory.
+	Backend Backend
+	// Namespace is the key prefix of locks.
+	Namespace string
+	// RetryInterval is the interval to retry acquiring the held lock, with jitter, default: 100ms.
+	RetryInterval time.Duration
+}
+
+type locker struct {
+	cfg *Config
+}
+
+// New creates a locker.
+func New(cfg ...*Config) Locker {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Backend == nil {
+		cfgX.Backend = NewMemory()
+	}
+	if cfgX.RetryInterval == 0 {
+		cfgX.RetryInterval = DefaultRetryInterval
+	}
+
+	return &locker{cfg: cfgX}
+}
+
+func (l *locker) Acquire(ctx context.Context, name string, ttl time.Duration) (Lock, error) {
+	for {
+		lock, err := l.TryAcquire(ctx, name, ttl)
+		if !errors.Is(err, ErrLocked) {
+			return lock, err
+		}
+
+		wait := l.cfg.RetryInterval + time.Duration(rand.Int63n(int64(l.cfg.RetryInterval)))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (l *locker) TryAcquire(ctx context.Context, name string, ttl time.Duration) (Lock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key := name
+	if l.cfg.Namespace != "" {
+		key = l.cfg.Namespace + ":" + name
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	token := random.String(24)
+	ok, err := l.cfg.Backend.Acquire(key, token, ttl)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrLocked
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	lk := &lock{
+		name:    name,
+		key:     key,
+		token:   token,
+		ttl:     ttl,
+		backend: l.cfg.Backend,
+		ctx:     lockCtx,
+		cancel:  cancel,
+	}
+	go lk.renew()
+
+	return lk, nil
+}
+
+type lock struct {
+	name    string
+	key     string
+	token   string
+	ttl     time.Duration
+	backend Backend
+	//
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func (l *lock) Name() string {
+	return l.name
+}
+
+func (l *lock) Context() context.Context {
+	return l.ctx
+}
+
+func (l *lock) Release() (err error) {
+	l.once.Do(func() {
+		l.cancel()
+
+		var ok bool
+		if ok, err = l.backend.Release(l.key, l.token); err == nil && !ok {
+			err = ErrNotHeld
+		}
+	})
+
+	return err
+}
+
+// renew extends the ttl every 1/3 ttl, the lock context is cancelled if it is lost,
+// the lock is released if the parent context is done.
+func (l *lock) renew() {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			l.Release()
+			return
+		case <-ticker.C:
+			if ok, err := l.backend.Renew(l.key, l.token, l.ttl); err != nil || !ok {
+				l.cancel()
+				return
+			}
+		}
+	}
+}
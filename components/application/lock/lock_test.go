@@ -0,0 +1,105 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// lossyBackend loses the locks on renewal once lost is set, such as expired during a network partition.
+type lossyBackend struct {
+	Backend
+	lost atomic.Bool
+}
+
+func (b *lossyBackend) Renew(name, token string, ttl time.Duration) (bool, error) {
+	if b.lost.Load() {
+		return false, nil
+	}
+
+	return b.Backend.Renew(name, token, ttl)
+}
+
+func TestLockRenewal(t *testing.T) {
+	locker := New()
+
+	lk, err := locker.TryAcquire(context.Background(), "job", 60*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// held longer than the ttl, renewed in background
+	time.Sleep(200 * time.Millisecond)
+	if err := lk.Context().Err(); err != nil {
+		t.Fatalf("expected the lock to be held, got %s", err)
+	}
+	if _, err := locker.TryAcquire(context.Background(), "job", time.Second); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+
+	if err := lk.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if lk.Context().Err() == nil {
+		t.Error("expected the lock context to be done after release")
+	}
+
+	other, err := locker.TryAcquire(context.Background(), "job", time.Second)
+	if err != nil {
+		t.Fatalf("expected the released lock to be acquired, got %v", err)
+	}
+	other.Release()
+}
+
+func TestLockLoss(t *testing.T) {
+	backend := &lossyBackend{Backend: NewMemory()}
+	locker := New(&Config{Backend: backend})
+
+	lk, err := locker.TryAcquire(context.Background(), "job", 30*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend.lost.Store(true)
+	select {
+	case <-lk.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the lock context to be done when the renewal fails")
+	}
+
+	// the lock may be taken by others already
+	time.Sleep(40 * time.Millisecond)
+	if err := lk.Release(); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("expected ErrNotHeld, got %v", err)
+	}
+}
+
+func TestLockParentContext(t *testing.T) {
+	locker := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := locker.TryAcquire(ctx, "job", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+
+	acquired := make(chan error, 1)
+	go func() {
+		lk, err := locker.Acquire(waitCtx, "job", time.Second)
+		if err == nil {
+			lk.Release()
+		}
+		acquired <- err
+	}()
+
+	// the lock is released when the parent context is done
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-acquired; err != nil {
+		t.Errorf("expected the waiter to acquire the lock, got %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+// Package logsink writes the structured logs (such as request logs) to sinks (stdout json, file, syslog, OTLP)
+// behind an async buffered writer, so high-QPS services do not block on log I/O.
+package logsink
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Record is the structured log record.
+type Record struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Sink writes the batch of records.
+type Sink interface {
+	Write(records []*Record) error
+	Close() error
+}
+
+// SinkFunc is the function adapter of Sink.
+type SinkFunc func(records []*Record) error
+
+// Write writes the records.
+func (fn SinkFunc) Write(records []*Record) error {
+	return fn(records)
+}
+
+// Close does nothing.
+func (fn SinkFunc) Close() error {
+	return nil
+}
+
+// Policy is the policy when the buffer is full.
+type Policy int
+
+const (
+	// DropNewest drops the record being written.
+	DropNewest Policy = iota
+	// DropOldest drops the oldest buffered record.
+	DropOldest
+	// Block blocks the writer until the buffer has room.
+	Block
+)
+
+// ErrClosed is returned when writing after closed.
+var ErrClosed = errors.New("log writer is closed")
+
+// Stats is the writer statistics.
+type Stats struct {
+	Written uint64 `json:"written"`
+	Dropped uint64 `json:"dropped"`
+	Failed  uint64 `json:"failed"`
+	Pending int    `json:"pending"`
+}
+
+// Writer is the async buffered writer of the sinks.
+type Writer interface {
+	// Write buffers the record, it never blocks unless the policy is Block.
+	Write(record *Record) error
+	// Flush writes the buffered records.
+	Flush()
+	// Close flushes the buffered records and closes the sinks.
+	Close() error
+	Stats() Stats
+}
+
+// Config is the config of the writer.
+type Config struct {
+	Sinks []Sink
+	// BufferSize is the size of the buffered records, default: 8192.
+	BufferSize int
+	// BatchSize is the max records of a batch, default: 256.
+	BatchSize int
+	// FlushInterval is the max interval of flushing, default: 1s.
+	FlushInterval time.Duration
+	// Policy is the policy when the buffer is full, default: DropNewest.
+	Policy Policy
+	// OnError is called when the sink fails.
+	OnError func(sink Sink, records []*Record, err error)
+}
+
+type writer struct {
+	cfg *Config
+	//
+	records chan *Record
+	flush   chan chan struct{}
+	done    chan struct{}
+	//
+	closeOnce sync.Once
+	closed    chan struct{}
+	//
+	written atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// New creates the async writer of the sinks.
+func New(cfg *Config) Writer {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 8192
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 256
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	w := &writer{
+		cfg:     cfg,
+		records: make(chan *Record, cfg.BufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+func (w *writer) Write(record *Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	select {
+	case <-w.closed:
+		return ErrClosed
+	default:
+	}
+
+	switch w.cfg.Policy {
+	case Block:
+		select {
+		case w.records <- record:
+		case <-w.closed:
+			return ErrClosed
+		}
+	case DropOldest:
+		for {
+			select {
+			case w.records <- record:
+				return nil
+			default:
+			}
+
+			// makes room, the writer may take it concurrently
+			select {
+			case <-w.records:
+				w.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case w.records <- record:
+		default:
+			w.dropped.Add(1)
+		}
+	}
+
+	return nil
+}
+
+func (w *writer) Flush() {
+	ack := make(chan struct{})
+	select {
+	case w.flush <- ack:
+		<-ack
+	case <-w.done:
+	}
+}
+
+func (w *writer) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.closed)
+	})
+
+	<-w.done
+
+	var errs []error
+	for _, sink := range w.cfg.Sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *writer) Stats() Stats {
+	return Stats{
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+		Pending: len(w.records),
+	}
+}
+
+// run is the single writer, so records are written in order.
+func (w *writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Record, 0, w.cfg.BatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		w.write(batch)
+		batch = make([]*Record, 0, w.cfg.BatchSize)
+	}
+
+	drain := func() {
+		for {
+			select {
+			case record := <-w.records:
+				batch = append(batch, record)
+				if len(batch) >= w.cfg.BatchSize {
+					write()
+				}
+			default:
+				write()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case record := <-w.records:
+			batch = append(batch, record)
+			if len(batch) >= w.cfg.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-w.flush:
+			drain()
+			close(ack)
+		case <-w.closed:
+			drain()
+			return
+		}
+	}
+}
+
+func (w *writer) write(records []*Record) {
+	for _, sink := range w.cfg.Sinks {
+		if err := sink.Write(records); err != nil {
+			w.failed.Add(uint64(len(records)))
+			if w.cfg.OnError != nil {
+				w.cfg.OnError(sink, records, err)
+			}
+		}
+	}
+
+	w.written.Add(uint64(len(records)))
+}
@@ -0,0 +1,377 @@
+package logsink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type jsonSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSON creates the sink writing the records as json lines.
+func NewJSON(w io.Writer) Sink {
+	return &jsonSink{w: w}
+}
+
+// NewStdout creates the sink writing the records as json lines to stdout.
+func NewStdout() Sink {
+	return NewJSON(os.Stdout)
+}
+
+func (s *jsonSink) Write(records []*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := bufio.NewWriter(s.w)
+	encoder := json.NewEncoder(buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return buf.Flush()
+}
+
+func (s *jsonSink) Close() error {
+	return nil
+}
+
+// FileConfig is the config of the file sink.
+type FileConfig struct {
+	// Path is the log file, such as /var/log/app/access.log.
+	Path string
+	// MaxSize is the size in bytes to rotate the file, default: 100MB.
+	MaxSize int64
+	// MaxBackups is the count of rotated files kept (access.log.1, access.log.2, ...), default: 5.
+	MaxBackups int
+}
+
+type fileSink struct {
+	cfg  *FileConfig
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFile creates the sink writing the records as json lines to the file, rotated by size.
+func NewFile(cfg *FileConfig) (Sink, error) {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 100 * 1024 * 1024
+	}
+	if cfg.MaxBackups <= 0 {
+		cfg.MaxBackups = 5
+	}
+
+	s := &fileSink{cfg: cfg}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.cfg.Path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	s.file = f
+	s.size = stat.Size()
+	return nil
+}
+
+// rotate shifts access.log.N-1 to access.log.N, and access.log to access.log.1.
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	for i := s.cfg.MaxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", s.cfg.Path, i), fmt.Sprintf("%s.%d", s.cfg.Path, i+1))
+	}
+	if err := os.Rename(s.cfg.Path, s.cfg.Path+".1"); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *fileSink) Write(records []*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	if s.size > 0 && s.size+int64(buf.Len()) > s.cfg.MaxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(buf.Bytes())
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// SyslogConfig is the config of the syslog sink.
+type SyslogConfig struct {
+	// Network is udp, tcp or unix, default: udp.
+	Network string
+	// Address is the syslog server, such as 127.0.0.1:514 or /dev/log.
+	Address string
+	// Tag is the app name of the messages.
+	Tag string
+	// Facility is the syslog facility, default: 1 (user).
+	Facility int
+}
+
+type syslogSink struct {
+	cfg      *SyslogConfig
+	hostname string
+	mu       sync.Mutex
+	conn     net.Conn
+}
+
+// NewSyslog creates the sink sending the records to syslog in RFC 5424 format,
+// the fields are sent as json in the message.
+func NewSyslog(cfg *SyslogConfig) Sink {
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = 1
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = filepath.Base(os.Args[0])
+	}
+
+	hostname, _ := os.Hostname()
+	return &syslogSink{cfg: cfg, hostname: hostname}
+}
+
+func (s *syslogSink) Write(records []*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.cfg.Network, s.cfg.Address, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	for _, record := range records {
+		message := record.Message
+		if len(record.Fields) > 0 {
+			fields, _ := json.Marshal(record.Fields)
+			message += " " + string(fields)
+		}
+
+		line := fmt.Sprintf(
+			"<%d>1 %s %s %s %d - - %s",
+			s.cfg.Facility*8+syslogSeverity(record.Level),
+			record.Time.Format(time.RFC3339Nano),
+			s.hostname, s.cfg.Tag, os.Getpid(), message,
+		)
+		// octet counting framing of stream transports (RFC 6587)
+		if s.cfg.Network == "tcp" {
+			line = fmt.Sprintf("%d %s", len(line), line)
+		}
+
+		if _, err := s.conn.Write([]byte(line)); err != nil {
+			// reconnects on the next write
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.Close()
+}
+
+func syslogSeverity(level string) int {
+	switch strings.ToLower(level) {
+	case "debug":
+		return 7
+	case "warn", "warning":
+		return 4
+	case "error":
+		return 3
+	case "fatal":
+		return 2
+	default:
+		return 6
+	}
+}
+
+// OTLPConfig is the config of the OTLP logs sink.
+type OTLPConfig struct {
+	// Endpoint is the OTLP/HTTP endpoint, such as http://127.0.0.1:4318, the logs are sent to /v1/logs.
+	Endpoint string
+	// ServiceName is the service.name resource attribute.
+	ServiceName string
+	// Headers are sent with the requests, such as authorization.
+	Headers map[string]string
+	// Client is the http client, default timeout: 10s.
+	Client *http.Client
+}
+
+type otlpSink struct {
+	cfg *OTLPConfig
+}
+
+// NewOTLP creates the sink exporting the records with OTLP/HTTP (json encoding).
+func NewOTLP(cfg *OTLPConfig) Sink {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &otlpSink{cfg: cfg}
+}
+
+func (s *otlpSink) Write(records []*Record) error {
+	logRecords := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		attributes := make([]map[string]any, 0, len(record.Fields))
+		for key, value := range record.Fields {
+			attributes = append(attributes, map[string]any{"key": key, "value": otlpValue(value)})
+		}
+
+		logRecords = append(logRecords, map[string]any{
+			"timeUnixNano":   fmt.Sprintf("%d", record.Time.UnixNano()),
+			"severityNumber": otlpSeverity(record.Level),
+			"severityText":   strings.ToUpper(record.Level),
+			"body":           map[string]any{"stringValue": record.Message},
+			"attributes":     attributes,
+		})
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"resourceLogs": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": []any{
+						map[string]any{"key": "service.name", "value": otlpValue(s.cfg.ServiceName)},
+					},
+				},
+				"scopeLogs": []any{
+					map[string]any{
+						"scope":      map[string]any{"name": "zoox"},
+						"logRecords": logRecords,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(s.cfg.Endpoint, "/")+"/v1/logs", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	response, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("otlp: export logs: %s", response.Status)
+	}
+
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	return nil
+}
+
+func otlpValue(value any) map[string]any {
+	switch v := value.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		// int64 is a string in the json encoding of protobuf
+		return map[string]any{"intValue": fmt.Sprintf("%d", v)}
+	case float32, float64:
+		return map[string]any{"doubleValue": v}
+	case time.Duration:
+		return map[string]any{"intValue": fmt.Sprintf("%d", v)}
+	default:
+		return map[string]any{"stringValue": fmt.Sprint(v)}
+	}
+}
+
+func otlpSeverity(level string) int {
+	switch strings.ToLower(level) {
+	case "debug":
+		return 5
+	case "warn", "warning":
+		return 13
+	case "error":
+		return 17
+	case "fatal":
+		return 21
+	default:
+		return 9
+	}
+}
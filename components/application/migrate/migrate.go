@@ -0,0 +1,331 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTable is the default table recording the applied migrations.
+const DefaultTable = "schema_migrations"
+
+// Migration is a versioned schema change, written in sql (Up/Down) or go (UpFunc/DownFunc).
+type Migration struct {
+	Version int64
+	Name    string
+	//
+	Up   string
+	Down string
+	//
+	UpFunc   func(ctx context.Context, tx *sql.Tx) error
+	DownFunc func(ctx context.Context, tx *sql.Tx) error
+}
+
+// Status is the status of a migration.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Config is the config of Migrator.
+type Config struct {
+	// Table is the table recording the applied migrations, default: schema_migrations.
+	Table string
+	// Logf logs the applied migrations.
+	Logf func(format string, args ...any)
+}
+
+// Migrator applies and rolls back the migrations, each migration runs in a transaction.
+type Migrator struct {
+	db         *sql.DB
+	cfg        *Config
+	migrations map[int64]*Migration
+}
+
+// New creates a migrator of the database.
+func New(db *sql.DB, cfg ...*Config) *Migrator {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Table == "" {
+		cfgX.Table = DefaultTable
+	}
+	if cfgX.Logf == nil {
+		cfgX.Logf = func(format string, args ...any) {}
+	}
+
+	return &Migrator{
+		db:         db,
+		cfg:        cfgX,
+		migrations: map[int64]*Migration{},
+	}
+}
+
+// Register registers the migrations, the version must be unique.
+func (m *Migrator) Register(migrations ...*Migration) error {
+	for _, migration := range migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("migration %s: version must be positive", migration.Name)
+		}
+		if existed, ok := m.migrations[migration.Version]; ok {
+			return fmt.Errorf("migration %d is duplicated: %s and %s", migration.Version, existed.Name, migration.Name)
+		}
+
+		m.migrations[migration.Version] = migration
+	}
+
+	return nil
+}
+
+var filenameRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// RegisterFS registers the sql migrations in the dir of fsys,
+// named <version>_<name>.up.sql and <version>_<name>.down.sql, such as 20240101120000_create_users.up.sql.
+func (m *Migrator) RegisterFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	migrations := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := filenameRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration %s: %v", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrations[version] = migration
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	for _, migration := range migrations {
+		if err := m.Register(migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status returns the status of all migrations, sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []*Status{}
+	for _, migration := range m.sorted() {
+		status := &Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+			delete(applied, migration.Version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	// applied but missing in the source, such as migrations of another branch
+	for version, at := range applied {
+		at := at
+		statuses = append(statuses, &Status{Version: version, Name: "(missing)", Applied: true, AppliedAt: &at})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Up applies the pending migrations in version order, steps <= 0 means all.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []*Migration{}
+	for _, migration := range m.sorted() {
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.run(ctx, migration, migration.Up, migration.UpFunc,
+			fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%d, '%s', '%s')", m.cfg.Table, migration.Version, quote(migration.Name), time.Now().UTC().Format(time.RFC3339)))
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+
+		m.cfg.Logf("[migrate] applied %d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the applied migrations in reverse version order, steps <= 0 means 1.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []int64{}
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] > versions[j]
+	})
+
+	done := []*Migration{}
+	for _, version := range versions {
+		if len(done) >= steps {
+			break
+		}
+
+		migration, ok := m.migrations[version]
+		if !ok {
+			return done, fmt.Errorf("failed to roll back migration %d: not found in the source", version)
+		}
+		if migration.Down == "" && migration.DownFunc == nil {
+			return done, fmt.Errorf("failed to roll back migration %d_%s: no down migration", migration.Version, migration.Name)
+		}
+
+		err := m.run(ctx, migration, migration.Down, migration.DownFunc,
+			fmt.Sprintf("DELETE FROM %s WHERE version = %d", m.cfg.Table, migration.Version))
+		if err != nil {
+			return done, fmt.Errorf("failed to roll back migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+
+		m.cfg.Logf("[migrate] rolled back %d_%s", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// run executes the migration and records it in one transaction.
+func (m *Migrator) run(ctx context.Context, migration *Migration, query string, fn func(ctx context.Context, tx *sql.Tx) error, record string) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if !isBlankSQL(query) {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	if fn != nil {
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// applied returns the applied versions with the applied time, the table is created if not exists.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if m.db == nil {
+		return nil, errors.New("database is not configured")
+	}
+
+	// portable across postgres, mysql and sqlite
+	_, err := m.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at VARCHAR(64) NOT NULL)", m.cfg.Table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %v", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf("SELECT version, applied_at FROM %s", m.cfg.Table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+
+		applied[version], _ = time.Parse(time.RFC3339, at)
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) sorted() []*Migration {
+	migrations := make([]*Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations
+}
+
+// isBlankSQL returns true if the query has only comments and spaces, some drivers reject empty queries.
+func isBlankSQL(query string) bool {
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func quote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
@@ -0,0 +1,107 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-zoox/logger"
+)
+
+// DefaultMemoryQueueSize is the buffered message size of each memory subscriber.
+var DefaultMemoryQueueSize = 256
+
+type memory struct {
+	sync.RWMutex
+	//
+	subscribers map[string][]*memorySubscriber
+}
+
+type memorySubscriber struct {
+	handler Handler
+	queue   chan *Message
+	done    chan struct{}
+}
+
+// NewMemory creates an in-process pubsub backend for single node applications.
+//
+// Messages of one subscriber are handled in order, slow subscribers apply backpressure to publishers.
+// A subscription is removed when its context is done.
+func NewMemory() PubSub {
+	return &memory{
+		subscribers: map[string][]*memorySubscriber{},
+	}
+}
+
+func (m *memory) Publish(ctx context.Context, msg *Message) error {
+	if msg == nil || msg.Topic == "" {
+		return fmt.Errorf("pubsub: message topic is required")
+	}
+
+	m.RLock()
+	subscribers := append([]*memorySubscriber{}, m.subscribers[msg.Topic]...)
+	m.RUnlock()
+
+	for _, s := range subscribers {
+		select {
+		case s.queue <- msg:
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (m *memory) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	s := &memorySubscriber{
+		handler: handler,
+		queue:   make(chan *Message, DefaultMemoryQueueSize),
+		done:    make(chan struct{}),
+	}
+
+	m.Lock()
+	m.subscribers[topic] = append(m.subscribers[topic], s)
+	m.Unlock()
+
+	go func() {
+		for {
+			select {
+			case msg := <-s.queue:
+				if err := s.handler(msg); err != nil {
+					logger.Errorf("[pubsub][memory] failed to handle message of topic(%s): %s", topic, err)
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			m.unsubscribe(topic, s)
+		}()
+	}
+
+	return nil
+}
+
+func (m *memory) unsubscribe(topic string, s *memorySubscriber) {
+	m.Lock()
+	defer m.Unlock()
+
+	subscribers := m.subscribers[topic]
+	for i, one := range subscribers {
+		if one == s {
+			m.subscribers[topic] = append(subscribers[:i:i], subscribers[i+1:]...)
+			close(s.done)
+			break
+		}
+	}
+
+	if len(m.subscribers[topic]) == 0 {
+		delete(m.subscribers, topic)
+	}
+}
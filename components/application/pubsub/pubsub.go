@@ -0,0 +1,32 @@
+package pubsub
+
+import (
+	"context"
+
+	gopubsub "github.com/go-zoox/pubsub"
+)
+
+// Message is the pubsub message.
+type Message = gopubsub.Message
+
+// Handler is the pubsub message handler.
+type Handler = gopubsub.Handler
+
+// PubSub is the pubsub backend used by the application.
+// Implement it to adapt other brokers, such as NATS or Kafka,
+// and set it with app.SetPubSub.
+type PubSub interface {
+	Publish(ctx context.Context, msg *Message) error
+	// Subscribe handles the messages of the topic until ctx is done.
+	// It may block until ctx is done (redis) or return at once (memory), so call it in a goroutine
+	// if the caller continues, and never with a lock held by the handler.
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+}
+
+// RedisConfig is the config of redis backend.
+type RedisConfig = gopubsub.Config
+
+// NewRedis creates a redis pubsub backend.
+func NewRedis(cfg *RedisConfig) PubSub {
+	return gopubsub.New(cfg)
+}
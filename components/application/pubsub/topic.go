@@ -0,0 +1,63 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Topic is a typed topic, values are (de)serialized as JSON.
+//
+// Example:
+//
+//	type UserCreated struct {
+//		ID string `json:"id"`
+//	}
+//
+//	topic := pubsub.NewTopic[UserCreated](app.PubSub(), "user.created")
+//	topic.Subscribe(context.Background(), func(ctx context.Context, event UserCreated) error {
+//		return nil
+//	})
+//	topic.Publish(context.Background(), UserCreated{ID: "1"})
+type Topic[T any] struct {
+	ps   PubSub
+	name string
+}
+
+// NewTopic creates a typed topic on the pubsub backend.
+func NewTopic[T any](ps PubSub, name string) *Topic[T] {
+	return &Topic[T]{
+		ps:   ps,
+		name: name,
+	}
+}
+
+// Name returns the topic name.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish publishes the value to the topic.
+func (t *Topic[T]) Publish(ctx context.Context, value T) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("pubsub: failed to encode message of topic(%s): %v", t.name, err)
+	}
+
+	return t.ps.Publish(ctx, &Message{
+		Topic: t.name,
+		Body:  body,
+	})
+}
+
+// Subscribe subscribes the topic with the typed handler.
+func (t *Topic[T]) Subscribe(ctx context.Context, handler func(ctx context.Context, value T) error) error {
+	return t.ps.Subscribe(ctx, t.name, func(msg *Message) error {
+		var value T
+		if err := json.Unmarshal(msg.Body, &value); err != nil {
+			return fmt.Errorf("pubsub: failed to decode message of topic(%s): %v", t.name, err)
+		}
+
+		return handler(ctx, value)
+	})
+}
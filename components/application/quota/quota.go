@@ -0,0 +1,266 @@
+package quota
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is the accounting window of quotas.
+type Period string
+
+const (
+	// Daily resets at 00:00 UTC every day.
+	Daily Period = "daily"
+	// Monthly resets at 00:00 UTC on the first day of every month.
+	Monthly Period = "monthly"
+)
+
+// Periods are the supported periods.
+var Periods = []Period{Daily, Monthly}
+
+// window returns the id and the reset time of the window containing now.
+func (p Period) window(now time.Time) (string, time.Time) {
+	now = now.UTC()
+	if p == Monthly {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("200601"), start.AddDate(0, 1, 0)
+	}
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start.Format("20060102"), start.AddDate(0, 0, 1)
+}
+
+// Limits are the max costs of one key in the periods, 0 means unlimited.
+type Limits struct {
+	Daily   int64 `json:"daily"`
+	Monthly int64 `json:"monthly"`
+}
+
+func (l *Limits) of(p Period) int64 {
+	if p == Monthly {
+		return l.Monthly
+	}
+
+	return l.Daily
+}
+
+// PeriodUsage is the usage of one key in the current window of the period.
+type PeriodUsage struct {
+	Period  Period    `json:"period"`
+	Used    int64     `json:"used"`
+	Limit   int64     `json:"limit"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// Remaining returns the remaining cost, -1 means unlimited.
+func (u *PeriodUsage) Remaining() int64 {
+	if u.Limit <= 0 {
+		return -1
+	}
+
+	if u.Used >= u.Limit {
+		return 0
+	}
+
+	return u.Limit - u.Used
+}
+
+// Usage is the usage of one key.
+type Usage struct {
+	Key     string         `json:"key"`
+	Limits  *Limits        `json:"limits"`
+	Periods []*PeriodUsage `json:"periods"`
+	// Exhausted is the period exhausted by this consumption, empty if none or already reported in the window.
+	Exhausted Period `json:"exhausted,omitempty"`
+}
+
+// Tightest returns the limited period with the least remaining cost, nil if unlimited.
+func (u *Usage) Tightest() *PeriodUsage {
+	var tightest *PeriodUsage
+	for _, p := range u.Periods {
+		if p.Limit <= 0 {
+			continue
+		}
+
+		if tightest == nil || p.Remaining() < tightest.Remaining() {
+			tightest = p
+		}
+	}
+
+	return tightest
+}
+
+// Config is the config of Quota.
+type Config struct {
+	// Namespace is the key prefix in the store.
+	Namespace string
+	// Limits are the default limits, overridden per key by SetLimits.
+	Limits Limits
+	// Store is the counter store, default: memory.
+	Store Store
+}
+
+// Quota accounts the cost consumed by keys (such as api keys) in daily and monthly windows.
+type Quota struct {
+	cfg   *Config
+	store Store
+	// now is replaceable in tests
+	now func() time.Time
+}
+
+// New creates a quota.
+func New(cfg *Config) *Quota {
+	store := cfg.Store
+	if store == nil {
+		store = NewMemory()
+	}
+
+	return &Quota{
+		cfg:   cfg,
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Consume charges the cost to the key, ok is false if any period would be exceeded,
+// in which case nothing is charged.
+func (q *Quota) Consume(key string, cost int64) (usage *Usage, ok bool, err error) {
+	limits, err := q.Limits(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	usage = &Usage{Key: key, Limits: limits}
+	now := q.now()
+	charged := []string{}
+	refund := func() {
+		for _, counter := range charged {
+			q.store.IncrBy(counter, -cost, time.Time{})
+		}
+	}
+
+	for _, period := range Periods {
+		id, resetAt := period.window(now)
+		counter := q.counterKey(key, period, id)
+		limit := limits.of(period)
+
+		used, err := q.store.IncrBy(counter, cost, resetAt)
+		if err != nil {
+			refund()
+			return nil, false, err
+		}
+		charged = append(charged, counter)
+
+		if limit > 0 && used > limit {
+			refund()
+
+			usage, _, err := q.usage(key, limits, now)
+			if err != nil {
+				return nil, false, err
+			}
+			usage.Exhausted = q.exhausted(key, period, id, resetAt)
+			return usage, false, nil
+		}
+
+		if limit > 0 && used == limit && usage.Exhausted == "" {
+			usage.Exhausted = q.exhausted(key, period, id, resetAt)
+		}
+
+		usage.Periods = append(usage.Periods, &PeriodUsage{
+			Period:  period,
+			Used:    used,
+			Limit:   limit,
+			ResetAt: resetAt,
+		})
+	}
+
+	return usage, true, nil
+}
+
+// Usage returns the usage of the key.
+func (q *Quota) Usage(key string) (*Usage, error) {
+	limits, err := q.Limits(key)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, _, err := q.usage(key, limits, q.now())
+	return usage, err
+}
+
+func (q *Quota) usage(key string, limits *Limits, now time.Time) (*Usage, bool, error) {
+	usage := &Usage{Key: key, Limits: limits}
+	for _, period := range Periods {
+		id, resetAt := period.window(now)
+		used, err := q.store.Get(q.counterKey(key, period, id))
+		if err != nil {
+			return nil, false, err
+		}
+
+		usage.Periods = append(usage.Periods, &PeriodUsage{
+			Period:  period,
+			Used:    used,
+			Limit:   limits.of(period),
+			ResetAt: resetAt,
+		})
+	}
+
+	return usage, false, nil
+}
+
+// Limits returns the limits of the key, the default limits if not overridden.
+func (q *Quota) Limits(key string) (*Limits, error) {
+	limits, err := q.store.GetLimits(q.limitsKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if limits == nil {
+		copied := q.cfg.Limits
+		return &copied, nil
+	}
+
+	return limits, nil
+}
+
+// SetLimits overrides the limits of the key, nil restores the default limits.
+func (q *Quota) SetLimits(key string, limits *Limits) error {
+	return q.store.SetLimits(q.limitsKey(key), limits)
+}
+
+// Reset clears the usage of the key in the current windows.
+func (q *Quota) Reset(key string) error {
+	now := q.now()
+	for _, period := range Periods {
+		id, _ := period.window(now)
+		if err := q.store.Del(q.counterKey(key, period, id)); err != nil {
+			return err
+		}
+		if err := q.store.Del(q.exhaustedKey(key, period, id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exhausted returns the period only the first time it is exhausted in the window.
+func (q *Quota) exhausted(key string, period Period, window string, resetAt time.Time) Period {
+	if n, err := q.store.IncrBy(q.exhaustedKey(key, period, window), 1, resetAt); err != nil || n != 1 {
+		return ""
+	}
+
+	return period
+}
+
+func (q *Quota) counterKey(key string, period Period, window string) string {
+	return fmt.Sprintf("%s:usage:%s:%s:%s", q.cfg.Namespace, key, period, window)
+}
+
+func (q *Quota) exhaustedKey(key string, period Period, window string) string {
+	return fmt.Sprintf("%s:exhausted:%s:%s:%s", q.cfg.Namespace, key, period, window)
+}
+
+func (q *Quota) limitsKey(key string) string {
+	return fmt.Sprintf("%s:limits:%s", q.cfg.Namespace, key)
+}
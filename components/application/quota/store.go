@@ -0,0 +1,212 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store persists the counters and the limits overrides.
+type Store interface {
+	// IncrBy adds the value to the counter and returns the new value,
+	// the counter expires at expireAt if it is not zero.
+	IncrBy(key string, value int64, expireAt time.Time) (int64, error)
+	// Get returns the counter, 0 if not found.
+	Get(key string) (int64, error)
+	Del(key string) error
+	// GetLimits returns the limits, nil if not found.
+	GetLimits(key string) (*Limits, error)
+	// SetLimits sets the limits, nil deletes them.
+	SetLimits(key string, limits *Limits) error
+}
+
+type memoryCounter struct {
+	value    int64
+	expireAt time.Time
+}
+
+type memoryStore struct {
+	sync.Mutex
+	counters map[string]*memoryCounter
+	limits   map[string]*Limits
+}
+
+// NewMemory creates an in-process memory store, counters are not shared across instances.
+func NewMemory() Store {
+	return &memoryStore{
+		counters: map[string]*memoryCounter{},
+		limits:   map[string]*Limits{},
+	}
+}
+
+func (s *memoryStore) IncrBy(key string, value int64, expireAt time.Time) (int64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || (!counter.expireAt.IsZero() && time.Now().After(counter.expireAt)) {
+		counter = &memoryCounter{}
+		s.counters[key] = counter
+
+		// expired windows are cleaned on write
+		for k, c := range s.counters {
+			if !c.expireAt.IsZero() && time.Now().After(c.expireAt) {
+				delete(s.counters, k)
+			}
+		}
+	}
+
+	counter.value += value
+	if !expireAt.IsZero() {
+		counter.expireAt = expireAt
+	}
+
+	return counter.value, nil
+}
+
+func (s *memoryStore) Get(key string) (int64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || (!counter.expireAt.IsZero() && time.Now().After(counter.expireAt)) {
+		return 0, nil
+	}
+
+	return counter.value, nil
+}
+
+func (s *memoryStore) Del(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+func (s *memoryStore) GetLimits(key string) (*Limits, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	limits, ok := s.limits[key]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *limits
+	return &copied, nil
+}
+
+func (s *memoryStore) SetLimits(key string, limits *Limits) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if limits == nil {
+		delete(s.limits, key)
+		return nil
+	}
+
+	copied := *limits
+	s.limits[key] = &copied
+	return nil
+}
+
+// RedisConfig is the config of redis store.
+type RedisConfig struct {
+	Host     string
+	Port     int
+	DB       int
+	Username string
+	Password string
+}
+
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedis creates a redis store, counters are shared across instances.
+func NewRedis(cfg *RedisConfig) Store {
+	return &redisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			DB:       cfg.DB,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}),
+	}
+}
+
+func (s *redisStore) IncrBy(key string, value int64, expireAt time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := s.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, key, value)
+	if !expireAt.IsZero() {
+		pipe.ExpireAt(ctx, key, expireAt)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (s *redisStore) Get(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := s.client.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+func (s *redisStore) Del(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *redisStore) GetLimits(key string) (*Limits, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	limits := &Limits{}
+	if err := json.Unmarshal(data, limits); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (s *redisStore) SetLimits(key string, limits *Limits) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if limits == nil {
+		return s.client.Del(ctx, key).Err()
+	}
+
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, key, data, 0).Err()
+}
@@ -0,0 +1,206 @@
+// Package redirect is the declarative redirect and rewrite table evaluated before routing,
+// for vanity urls and migrations without code changes.
+package redirect
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Rule is the redirect (or rewrite) rule.
+type Rule struct {
+	// From is the path pattern, such as /old/:id or /docs/*path, or the regular expression if Regex is true.
+	From string
+	// To is the target, params of the pattern (:id, *path) or groups of the regex ($1, ${name}) are substituted,
+	//	such as /new/:id, https://docs.example.com/*path or /posts/$1.
+	To string
+	// Regex matches the path with From as regular expression.
+	Regex bool
+	// Status of the redirect, 301, 302, 303, 307 or 308, default 301.
+	Status int
+	// Rewrite rewrites the request path internally instead of redirecting, To must be a path.
+	Rewrite bool
+	// PreserveQuery appends the query of the request to the target.
+	PreserveQuery bool
+	// Host matches the request host only if set, such as old.example.com.
+	Host string
+
+	re     *regexp.Regexp
+	params []string
+}
+
+// Result is the matched rule result.
+type Result struct {
+	// Target is the url (redirect) or path (rewrite).
+	Target  string
+	Status  int
+	Rewrite bool
+}
+
+var paramPattern = regexp.MustCompile(`[:*][A-Za-z_][A-Za-z0-9_]*`)
+
+// compile compiles the rule.
+func (r *Rule) compile() error {
+	if r.From == "" || r.To == "" {
+		return fmt.Errorf("redirect: from and to are required")
+	}
+
+	if r.Status == 0 {
+		r.Status = http.StatusMovedPermanently
+	}
+	if !r.Rewrite && (r.Status < 300 || r.Status > 308) {
+		return fmt.Errorf("redirect: invalid status %d of %s", r.Status, r.From)
+	}
+
+	if r.Regex {
+		re, err := regexp.Compile(r.From)
+		if err != nil {
+			return fmt.Errorf("redirect: invalid regex %s: %v", r.From, err)
+		}
+
+		r.re = re
+		return nil
+	}
+
+	// pattern: :name matches a segment, *name matches the rest
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range paramPattern.FindAllStringIndex(r.From, -1) {
+		expr.WriteString(regexp.QuoteMeta(r.From[last:loc[0]]))
+
+		param := r.From[loc[0]:loc[1]]
+		r.params = append(r.params, param)
+		if param[0] == ':' {
+			expr.WriteString("([^/]+)")
+		} else {
+			expr.WriteString("(.*)")
+		}
+
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(r.From[last:]))
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return fmt.Errorf("redirect: invalid pattern %s: %v", r.From, err)
+	}
+
+	r.re = re
+	return nil
+}
+
+// match returns the target of the path, false if not matched.
+func (r *Rule) match(host, path string) (string, bool) {
+	if r.Host != "" && !strings.EqualFold(r.Host, host) {
+		return "", false
+	}
+
+	submatches := r.re.FindStringSubmatchIndex(path)
+	if submatches == nil {
+		return "", false
+	}
+
+	var target string
+	if r.Regex {
+		target = string(r.re.ExpandString(nil, r.To, path, submatches))
+	} else {
+		values := map[string]string{}
+		for i, param := range r.params {
+			values[param] = path[submatches[2*i+2]:submatches[2*i+3]]
+		}
+
+		target = paramPattern.ReplaceAllStringFunc(r.To, func(param string) string {
+			if value, ok := values[param]; ok {
+				return value
+			}
+
+			return param
+		})
+	}
+
+	// the relative target stays on the host, such as /*path matched by //evil.com or /\evil.com
+	if isRelative(r.To) && !isRelative(target) {
+		return "", false
+	}
+
+	return target, true
+}
+
+// isRelative reports whether the target is a path on the same host,
+// the targets starting with // or /\ are protocol-relative urls for browsers.
+func isRelative(target string) bool {
+	return strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") && !strings.HasPrefix(target, "/\\")
+}
+
+// Table is the ordered rules, the first matched rule wins.
+type Table struct {
+	mu    sync.RWMutex
+	rules []*Rule
+}
+
+// New creates the table of the rules.
+func New(rules ...*Rule) (*Table, error) {
+	t := &Table{}
+	for _, rule := range rules {
+		if err := t.Add(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Add compiles and appends the rule.
+func (t *Table) Add(rule *Rule) error {
+	if err := rule.compile(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.rules = append(t.rules, rule)
+	t.mu.Unlock()
+	return nil
+}
+
+// Len returns the count of the rules.
+func (t *Table) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.rules)
+}
+
+// Match returns the result of the first rule matching the url, false if none.
+func (t *Table) Match(host string, u *url.URL) (*Result, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, rule := range t.rules {
+		target, ok := rule.match(host, u.Path)
+		if !ok {
+			continue
+		}
+
+		if rule.PreserveQuery && u.RawQuery != "" {
+			if strings.Contains(target, "?") {
+				target += "&" + u.RawQuery
+			} else {
+				target += "?" + u.RawQuery
+			}
+		}
+
+		return &Result{
+			Target:  target,
+			Status:  rule.Status,
+			Rewrite: rule.Rewrite,
+		}, true
+	}
+
+	return nil, false
+}
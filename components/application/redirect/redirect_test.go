@@ -0,0 +1,95 @@
+package redirect
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	table, err := New(
+		&Rule{From: "/blog/:slug", To: "/posts/:slug", Status: http.StatusPermanentRedirect},
+		&Rule{From: "/docs/*path", To: "https://docs.example.com/*path", Status: http.StatusFound, PreserveQuery: true},
+		&Rule{From: `^/p/(\d+)$`, To: "/products/$1", Regex: true, Rewrite: true},
+		&Rule{From: `^/u/(?P<name>[a-z]+)$`, To: "/users/${name}?tab=profile", Regex: true, PreserveQuery: true},
+		&Rule{From: "/go/*path", To: "/*path"},
+		&Rule{From: "/to/:id", To: "/:id"},
+		&Rule{From: "/a.b", To: "/ab"},
+		&Rule{From: "/old", To: "/new", Host: "old.example.com"},
+		&Rule{From: "/first", To: "/first-wins"},
+		&Rule{From: "/first", To: "/second"},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		host     string
+		url      string
+		expected *Result
+	}{
+		{"param", "", "/blog/hello", &Result{Target: "/posts/hello", Status: http.StatusPermanentRedirect}},
+		{"param does not span segments", "", "/blog/a/b", nil},
+		{"param is required", "", "/blog/", nil},
+		{"wildcard", "", "/docs/guide/intro", &Result{Target: "https://docs.example.com/guide/intro", Status: http.StatusFound}},
+		{"wildcard preserves query", "", "/docs/api?v=2", &Result{Target: "https://docs.example.com/api?v=2", Status: http.StatusFound}},
+		{"wildcard can't change the absolute host", "", "/docs/@evil.com", &Result{Target: "https://docs.example.com/@evil.com", Status: http.StatusFound}},
+		{"regex rewrite", "", "/p/42", &Result{Target: "/products/42", Status: http.StatusMovedPermanently, Rewrite: true}},
+		{"regex not matched", "", "/p/abc", nil},
+		{"named group and query", "", "/u/zero?ref=mail", &Result{Target: "/users/zero?tab=profile&ref=mail", Status: http.StatusMovedPermanently}},
+		{"relative wildcard", "", "/go/home", &Result{Target: "/home", Status: http.StatusMovedPermanently}},
+		{"protocol-relative open redirect", "", "/go//evil.com", nil},
+		{"backslash open redirect", "", `/go/\evil.com`, nil},
+		{"backslash open redirect by param", "", `/to/\evil.com`, nil},
+		{"literal dot", "", "/a.b", &Result{Target: "/ab", Status: http.StatusMovedPermanently}},
+		{"literal dot is not a wildcard", "", "/aXb", nil},
+		{"host", "OLD.example.com", "/old", &Result{Target: "/new", Status: http.StatusMovedPermanently}},
+		{"other host", "new.example.com", "/old", nil},
+		{"first rule wins", "", "/first", &Result{Target: "/first-wins", Status: http.StatusMovedPermanently}},
+		{"no rule", "", "/unknown", nil},
+	}
+
+	for _, c := range cases {
+		u, _ := url.Parse(c.url)
+		result, ok := table.Match(c.host, u)
+		if c.expected == nil {
+			if ok {
+				t.Errorf("%s: expected no match, got %+v", c.name, result)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: expected %+v, got no match", c.name, c.expected)
+			continue
+		}
+		if *result != *c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	cases := []struct {
+		name string
+		rule *Rule
+	}{
+		{"missing from", &Rule{To: "/new"}},
+		{"missing to", &Rule{From: "/old"}},
+		{"not a redirect status", &Rule{From: "/old", To: "/new", Status: http.StatusOK}},
+		{"status out of range", &Rule{From: "/old", To: "/new", Status: 309}},
+		{"invalid regex", &Rule{From: `^/p/(\d+$`, To: "/products/$1", Regex: true}},
+	}
+
+	for _, c := range cases {
+		if _, err := New(c.rule); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+
+	table := &Table{}
+	if err := table.Add(&Rule{From: "/old"}); err == nil || table.Len() != 0 {
+		t.Errorf("expected the invalid rule not to be added")
+	}
+}
@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ClamAVConfig is the config of the clamd scanner.
+type ClamAVConfig struct {
+	// Network is unix or tcp, default tcp, or unix if Address is a path.
+	Network string
+	// Address is the clamd address, such as 127.0.0.1:3310 or /var/run/clamav/clamd.ctl.
+	Address string
+	// Timeout of the scan, default 1m.
+	Timeout time.Duration
+	// ChunkSize of the stream, must be below StreamMaxLength of clamd, default 64KB.
+	ChunkSize int
+}
+
+type clamav struct {
+	cfg *ClamAVConfig
+}
+
+// NewClamAV creates the scanner of clamd, the content is streamed with the INSTREAM command.
+func NewClamAV(cfg *ClamAVConfig) Scanner {
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+		if strings.HasPrefix(cfg.Address, "/") {
+			cfg.Network = "unix"
+		}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Minute
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = 64 * 1024
+	}
+
+	return &clamav{cfg: cfg}
+}
+
+func (c *clamav) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	dialer := &net.Dialer{Timeout: c.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, c.cfg.Network, c.cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect clamd: %v", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("failed to send clamd command: %v", err)
+	}
+
+	buf := make([]byte, c.cfg.ChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, fmt.Errorf("failed to stream to clamd: %v", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, fmt.Errorf("failed to stream to clamd: %v", err)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// zero length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return nil, fmt.Errorf("failed to stream to clamd: %v", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read clamd reply: %v", err)
+	}
+
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamAVReply parses the reply, such as stream: OK, stream: Eicar-Test-Signature FOUND.
+func parseClamAVReply(reply string) (*Result, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case reply == "OK":
+		return &Result{Clean: true}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &Result{Threat: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd error: %s", reply)
+	}
+}
@@ -0,0 +1,56 @@
+// Package scan scans the uploaded files for viruses or unwanted content before they are persisted,
+// such as with ClamAV or a custom callback.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Scan status
+const (
+	StatusPending  = "pending"
+	StatusClean    = "clean"
+	StatusInfected = "infected"
+	StatusFailed   = "failed"
+)
+
+// Result is the scan result.
+type Result struct {
+	// Clean is true if no threat is found.
+	Clean bool `json:"clean"`
+	// Threat is the name of the found threat, such as Eicar-Test-Signature.
+	Threat string `json:"threat,omitempty"`
+}
+
+// Status returns the status of the result.
+func (r *Result) Status() string {
+	if r.Clean {
+		return StatusClean
+	}
+
+	return StatusInfected
+}
+
+// InfectedError is returned when the file is infected.
+type InfectedError struct {
+	Threat string
+}
+
+func (e *InfectedError) Error() string {
+	return fmt.Sprintf("file is infected: %s", e.Threat)
+}
+
+// Scanner scans the content.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// ScannerFunc is the function scanner, such as calling a scanning service.
+type ScannerFunc func(ctx context.Context, r io.Reader) (*Result, error)
+
+// Scan calls the function.
+func (f ScannerFunc) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	return f(ctx, r)
+}
@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewFile creates the provider reading the files, such as mounted kubernetes secrets,
+// the key is looked up in the json object of the file, the whole file (trimmed) is returned without key.
+func NewFile() Provider {
+	return ProviderFunc(func(ctx context.Context, path, key string) (string, error) {
+		// secret://file//etc/secrets/app.json keeps the absolute path
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		if key == "" {
+			return strings.TrimSpace(string(data)), nil
+		}
+
+		return lookupKey(data, key)
+	})
+}
+
+// VaultConfig is the config of the vault provider.
+type VaultConfig struct {
+	// Address is the vault address, default: VAULT_ADDR.
+	Address string
+	// Token is the vault token, default: VAULT_TOKEN.
+	Token string
+	// Mount is the kv v2 mount, default: secret.
+	Mount string
+	// Client is the http client, default: timeout 10s.
+	Client *http.Client
+}
+
+// NewVault creates the provider of the vault kv v2 engine, secret://vault/<path>#key.
+func NewVault(cfg ...*VaultConfig) Provider {
+	cfgX := &VaultConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Address == "" {
+		cfgX.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfgX.Token == "" {
+		cfgX.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfgX.Mount == "" {
+		cfgX.Mount = "secret"
+	}
+	if cfgX.Client == nil {
+		cfgX.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return ProviderFunc(func(ctx context.Context, path, key string) (string, error) {
+		url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(cfgX.Address, "/"), cfgX.Mount, strings.TrimPrefix(path, "/"))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-Vault-Token", cfgX.Token)
+
+		response, err := cfgX.Client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode == http.StatusNotFound {
+			return "", ErrNotFound
+		}
+		if response.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("vault: status %d", response.StatusCode)
+		}
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var secret struct {
+			Data struct {
+				Data json.RawMessage `json:"data"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &secret); err != nil {
+			return "", fmt.Errorf("vault: invalid response: %v", err)
+		}
+
+		if key == "" {
+			return string(secret.Data.Data), nil
+		}
+
+		return lookupKey(secret.Data.Data, key)
+	})
+}
+
+// lookupKey returns the value of key in the json object.
+func lookupKey(data []byte, key string) (string, error) {
+	object := map[string]any{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return "", fmt.Errorf("secret is not a json object: %v", err)
+	}
+
+	value, ok := object[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %s", ErrNotFound, key)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	return fmt.Sprint(value), nil
+}
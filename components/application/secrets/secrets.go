@@ -0,0 +1,285 @@
+// Package secrets resolves the secret references of config values through pluggable providers,
+// so TLS keys and API secrets are not in plaintext config.
+//
+// References:
+//
+//	env://NAME                      the environment variable
+//	secret://<provider>/<path>#key  the key of the secret at path of the provider, such as secret://vault/app/db#password
+//	secret://file/<path>#key        the key of the json file, or the whole file without key
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when the secret or its key is missing.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider fetches the secrets, such as Vault or AWS Secrets Manager (ProviderFunc with the aws sdk).
+type Provider interface {
+	// Get returns the value of key in the secret at path, the whole secret if key is empty.
+	Get(ctx context.Context, path, key string) (string, error)
+}
+
+// ProviderFunc is the function adapter of Provider.
+type ProviderFunc func(ctx context.Context, path, key string) (string, error)
+
+// Get implements Provider.
+func (fn ProviderFunc) Get(ctx context.Context, path, key string) (string, error) {
+	return fn(ctx, path, key)
+}
+
+// Config is the config of Secrets.
+type Config struct {
+	// TTL is the cache ttl of the resolved values, 0 means cached until Refresh.
+	TTL time.Duration
+}
+
+// Secrets resolves the references with the registered providers.
+type Secrets interface {
+	// Register registers the provider of secret://<name>/...
+	Register(name string, provider Provider)
+	// Resolve returns the value of the reference, or the value itself if it is not a reference.
+	Resolve(ctx context.Context, value string) (string, error)
+	// ResolveStruct replaces the references in the string fields of obj (pointer to struct), recursively.
+	ResolveStruct(ctx context.Context, obj any) error
+	// OnRotate registers the callback of the reference, called by Refresh when the value changes.
+	OnRotate(ref string, fn func(value string))
+	// Refresh fetches the resolved references again, and calls the rotation callbacks of the changed.
+	Refresh(ctx context.Context) error
+	// Watch refreshes in the interval until ctx is done.
+	Watch(ctx context.Context, interval time.Duration)
+}
+
+type entry struct {
+	value     string
+	fetchedAt time.Time
+}
+
+type secrets struct {
+	cfg *Config
+
+	mu        sync.RWMutex
+	providers map[string]Provider
+	cache     map[string]*entry
+	callbacks map[string][]func(value string)
+}
+
+// New creates the secrets with the builtin providers: env and file.
+func New(cfg ...*Config) Secrets {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	s := &secrets{
+		cfg:       cfgX,
+		providers: map[string]Provider{},
+		cache:     map[string]*entry{},
+		callbacks: map[string][]func(value string){},
+	}
+	s.Register("file", NewFile())
+
+	return s
+}
+
+// IsReference reports whether the value is a secret reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, "secret://") || strings.HasPrefix(value, "env://")
+}
+
+func (s *secrets) Register(name string, provider Provider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.providers[name] = provider
+}
+
+func (s *secrets) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	s.mu.RLock()
+	cached, ok := s.cache[value]
+	s.mu.RUnlock()
+	if ok && (s.cfg.TTL == 0 || time.Since(cached.fetchedAt) < s.cfg.TTL) {
+		return cached.value, nil
+	}
+
+	resolved, err := s.fetch(ctx, value)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.cache[value] = &entry{value: resolved, fetchedAt: time.Now()}
+	s.mu.Unlock()
+
+	return resolved, nil
+}
+
+func (s *secrets) fetch(ctx context.Context, ref string) (string, error) {
+	if name, ok := strings.CutPrefix(ref, "env://"); ok {
+		value, exists := os.LookupEnv(name)
+		if !exists {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, ref)
+		}
+
+		return value, nil
+	}
+
+	rest := strings.TrimPrefix(ref, "secret://")
+	rest, key, _ := strings.Cut(rest, "#")
+	name, path, _ := strings.Cut(rest, "/")
+
+	s.mu.RLock()
+	provider, ok := s.providers[name]
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("secrets: unknown provider %q of %s", name, ref)
+	}
+
+	value, err := provider.Get(ctx, path, key)
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to resolve %s: %w", ref, err)
+	}
+
+	return value, nil
+}
+
+func (s *secrets) ResolveStruct(ctx context.Context, obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("secrets: expect a non-nil pointer, but got %T", obj)
+	}
+
+	return s.resolveValue(ctx, v.Elem())
+}
+
+func (s *secrets) resolveValue(ctx context.Context, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() || !IsReference(v.String()) {
+			return nil
+		}
+
+		value, err := s.Resolve(ctx, v.String())
+		if err != nil {
+			return err
+		}
+
+		v.SetString(value)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		if v.Kind() == reflect.Ptr {
+			return s.resolveValue(ctx, v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+
+			if err := s.resolveValue(ctx, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := s.resolveValue(ctx, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			if !IsReference(iter.Value().String()) {
+				continue
+			}
+
+			value, err := s.Resolve(ctx, iter.Value().String())
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(value).Convert(v.Type().Elem()))
+		}
+	}
+
+	return nil
+}
+
+func (s *secrets) OnRotate(ref string, fn func(value string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.callbacks[ref] = append(s.callbacks[ref], fn)
+}
+
+func (s *secrets) Refresh(ctx context.Context) error {
+	s.mu.RLock()
+	refs := make([]string, 0, len(s.cache)+len(s.callbacks))
+	for ref := range s.cache {
+		refs = append(refs, ref)
+	}
+	for ref := range s.callbacks {
+		if _, ok := s.cache[ref]; !ok {
+			refs = append(refs, ref)
+		}
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, ref := range refs {
+		value, err := s.fetch(ctx, ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		s.mu.Lock()
+		previous, existed := s.cache[ref]
+		s.cache[ref] = &entry{value: value, fetchedAt: time.Now()}
+		callbacks := append([]func(value string){}, s.callbacks[ref]...)
+		s.mu.Unlock()
+
+		if existed && previous.value == value {
+			continue
+		}
+
+		for _, fn := range callbacks {
+			fn(value)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *secrets) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Refresh(ctx)
+		}
+	}
+}
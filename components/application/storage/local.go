@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/keyring"
+)
+
+// LocalConfig is the config of the local disk storage.
+type LocalConfig struct {
+	// Root is the directory of the objects.
+	Root string
+	// BaseURL is the url serving the objects, such as https://example.com/files, for SignedURL.
+	BaseURL string
+	// Keyring signs the urls of SignedURL, verified by VerifySignedURL,
+	//	the urls signed by the rotated keys are still valid while the keys are in the keyring.
+	Keyring keyring.Keyring
+}
+
+type local struct {
+	cfg *LocalConfig
+}
+
+// NewLocal creates the local disk storage.
+func NewLocal(cfg *LocalConfig) Storage {
+	return &local{cfg: cfg}
+}
+
+func (s *local) path(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.cfg.Root, filepath.FromSlash(key)), nil
+}
+
+func (s *local) Put(ctx context.Context, key string, r io.Reader, opts ...*PutOption) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// written to a temp file first, so readers never see partial objects
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return f, err
+}
+
+func (s *local) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *local) SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if s.cfg.BaseURL == "" || s.cfg.Keyring == nil {
+		return "", errors.New("storage: base url and keyring are required for signed urls of local storage")
+	}
+
+	expiresAt := time.Now().Add(expires).Unix()
+	signature := s.cfg.Keyring.Sign(localSignedData(method, key, expiresAt))
+	if signature == "" {
+		return "", errors.New("storage: failed to sign the url, the keyring is not configured")
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", signature)
+
+	return fmt.Sprintf("%s/%s?%s", s.cfg.BaseURL, (&url.URL{Path: key}).EscapedPath(), query.Encode()), nil
+}
+
+// VerifySignedURL verifies the signed url of the local storage, with the key (path relative to BaseURL) and the query,
+// kr is the keyring of LocalConfig.
+func VerifySignedURL(kr keyring.Keyring, method, key string, query url.Values) bool {
+	if kr == nil {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	key, err = CleanKey(key)
+	if err != nil {
+		return false
+	}
+
+	return kr.Verify(localSignedData(method, key, expiresAt), query.Get("signature"))
+}
+
+// localSignedData is the data signed by the keyring, prefixed so that it is never valid as an app.SignURL signature.
+func localSignedData(method, key string, expiresAt int64) []byte {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	return []byte(fmt.Sprintf("storage:local\n%s\n%s\n%d", method, key, expiresAt))
+}
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config is the config of the S3 compatible storage, such as AWS S3, MinIO, Cloudflare R2,
+// or Google Cloud Storage (endpoint https://storage.googleapis.com with HMAC keys).
+type S3Config struct {
+	// Endpoint is the service url, default https://s3.<region>.amazonaws.com.
+	Endpoint string
+	// Region is the signing region, default us-east-1 (auto for R2, GCS).
+	Region string
+	Bucket string
+	// AccessKeyID and SecretAccessKey are the credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle uses endpoint/bucket/key instead of bucket.endpoint/key, such as MinIO.
+	PathStyle bool
+	// Prefix is prepended to the keys, such as uploads/.
+	Prefix string
+	// Client is the http client, default http.DefaultClient.
+	Client *http.Client
+}
+
+type s3 struct {
+	cfg      *S3Config
+	endpoint *url.URL
+}
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// NewS3 creates the S3 compatible storage, requests are signed with AWS Signature Version 4.
+func NewS3(cfg *S3Config) (Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage: s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint: %v", err)
+	}
+
+	return &s3{cfg: cfg, endpoint: endpoint}, nil
+}
+
+// url returns the object url, without query.
+func (s *s3) url(key string) (*url.URL, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path = u.Path + "/" + s.cfg.Bucket + "/" + s.cfg.Prefix + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + s.cfg.Prefix + key
+	}
+	// the path is sent as signed
+	u.RawPath = escapePath(u.Path)
+
+	return &u, nil
+}
+
+func (s *s3) Put(ctx context.Context, key string, r io.Reader, opts ...*PutOption) error {
+	opt := putOption(opts)
+
+	// the content length is required, the content is buffered if the size is unknown
+	payloadHash := unsignedPayload
+	size := opt.Size
+	if size <= 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		payloadHash = hex.EncodeToString(sum[:])
+		size = int64(len(data))
+		r = bytes.NewReader(data)
+	}
+
+	u, err := s.url(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if opt.ContentType != "" {
+		req.Header.Set("Content-Type", opt.ContentType)
+	}
+
+	_, err = s.do(req, payloadHash)
+	return err
+}
+
+func (s *s3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	u, err := s.url(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (s *s3) Delete(ctx context.Context, key string) error {
+	u, err := s.url(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.do(req, emptyPayloadHash)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *s3) SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error) {
+	u, err := s.url(key)
+	if err != nil {
+		return "", err
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.cfg.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		escapePath(u.Path),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonicalRequest))
+	u.RawQuery = canonicalQuery(query)
+
+	return u.String(), nil
+}
+
+var emptyPayloadHash = hex.EncodeToString(sha256.New().Sum(nil))
+
+// do signs the request with the Authorization header and sends it.
+func (s *s3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signed = append(signed, "content-type")
+		sort.Strings(signed)
+	}
+
+	var headers strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		headers.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, strings.Join(signed, ";"), s.signature(now, amzDate, scope, canonicalRequest),
+	))
+
+	response, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		defer response.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+
+		if response.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, req.URL.Path)
+		}
+
+		return nil, fmt.Errorf("storage: s3 %s %s: %d %s", req.Method, req.URL.Path, response.StatusCode, body)
+	}
+
+	return response, nil
+}
+
+func (s *s3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *s3) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escape encodes the string as RFC 3986 (unreserved characters are kept), as required by sigv4.
+func escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func escapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	return escape(p, true)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, escape(key, false)+"="+escape(value, false))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,67 @@
+// Package storage stores the files (such as uploads) in the local disk or object storage (S3 compatible),
+// so apps can switch the backends without handler changes.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Get if the object is not found.
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey is returned if the key is empty or escapes the root, such as ../etc/passwd.
+var ErrInvalidKey = errors.New("invalid object key")
+
+// PutOption is the option of Put.
+type PutOption struct {
+	// ContentType of the object, such as image/png.
+	ContentType string
+	// Size of the content, -1 or 0 means unknown (the content is buffered if the backend requires it).
+	Size int64
+}
+
+// Storage stores the objects by keys, such as avatars/1.png.
+type Storage interface {
+	// Put stores the content as the key.
+	Put(ctx context.Context, key string, r io.Reader, opts ...*PutOption) error
+	// Get opens the object of the key, ErrNotFound if not found.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the object of the key, no error if not found.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns the url accessing the object with the method (GET or PUT) until expires.
+	SignedURL(ctx context.Context, key string, method string, expires time.Duration) (string, error)
+}
+
+// CleanKey cleans the key, ErrInvalidKey if it is empty or escapes the root.
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+
+	cleaned := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if cleaned == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return cleaned, nil
+}
+
+func putOption(opts []*PutOption) *PutOption {
+	if len(opts) > 0 && opts[0] != nil {
+		return opts[0]
+	}
+
+	return &PutOption{}
+}
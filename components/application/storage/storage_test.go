@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/keyring"
+)
+
+func TestCleanKey(t *testing.T) {
+	valid := map[string]string{
+		"avatars/1.png":   "avatars/1.png",
+		"/avatars/1.png":  "avatars/1.png",
+		"avatars//1.png":  "avatars/1.png",
+		"avatars/./1.png": "avatars/1.png",
+		"avatars/..png":   "avatars/..png",
+		"avatars/1.png/":  "avatars/1.png",
+	}
+	for key, expected := range valid {
+		cleaned, err := CleanKey(key)
+		if err != nil || cleaned != expected {
+			t.Errorf("%q: expected %q, got %q %v", key, expected, cleaned, err)
+		}
+	}
+
+	for _, key := range []string{
+		"",
+		"/",
+		".",
+		"..",
+		"../etc/passwd",
+		"avatars/../../etc/passwd",
+		"/../etc/passwd",
+		"avatars/..",
+		"a/b/../c.png",
+		"..\\etc\\passwd",
+		"avatars\\1.png",
+	} {
+		if _, err := CleanKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
+
+func TestLocalStaysInRoot(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	s := NewLocal(&LocalConfig{Root: root})
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "../outside.txt", strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside.txt")); !os.IsNotExist(err) {
+		t.Fatal("expected no file written outside the root")
+	}
+
+	if err := s.Put(ctx, "/avatars/1.png", strings.NewReader("avatar")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "avatars", "1.png")); err != nil {
+		t.Fatalf("expected the object in the root: %v", err)
+	}
+
+	r, err := s.Get(ctx, "avatars/1.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "avatar" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	if _, err := s.Get(ctx, "avatars/../../root/avatars/1.png"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := s.Get(ctx, "avatars/2.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.Delete(ctx, "../root"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func newKeyring(t *testing.T, secrets ...string) keyring.Keyring {
+	kr, err := keyring.New(secrets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return kr
+}
+
+func signLocalURL(t *testing.T, kr keyring.Keyring, key, method string, expires time.Duration) (string, url.Values) {
+	s := NewLocal(&LocalConfig{Root: t.TempDir(), BaseURL: "https://example.com/files", Keyring: kr})
+	link, err := s.SignedURL(context.Background(), key, method, expires)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.TrimPrefix(u.Path, "/files/"), u.Query()
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	old := newKeyring(t, "key_2024_01")
+	rotated := newKeyring(t, "key_2024_06", "key_2024_01")
+
+	key, query := signLocalURL(t, old, "avatars/1.png", "GET", time.Minute)
+	_, expired := signLocalURL(t, old, "avatars/1.png", "GET", -time.Minute)
+	tampered := url.Values{"expires": query["expires"], "signature": {query.Get("signature") + "x"}}
+	extended := url.Values{"expires": {"9999999999"}, "signature": query["signature"]}
+
+	cases := []struct {
+		name   string
+		kr     keyring.Keyring
+		method string
+		key    string
+		query  url.Values
+		valid  bool
+	}{
+		{"valid", old, "GET", key, query, true},
+		{"rotated keys", rotated, "GET", key, query, true},
+		{"removed key", newKeyring(t, "key_2024_06"), "GET", key, query, false},
+		{"other key", old, "GET", "avatars/2.png", query, false},
+		{"other method", old, "PUT", key, query, false},
+		{"tampered signature", old, "GET", key, tampered, false},
+		{"extended expiry", old, "GET", key, extended, false},
+		{"expired", old, "GET", key, expired, false},
+		{"traversal", old, "GET", "../avatars/1.png", query, false},
+		{"no keyring", nil, "GET", key, query, false},
+	}
+
+	for _, c := range cases {
+		if valid := VerifySignedURL(c.kr, c.method, c.key, c.query); valid != c.valid {
+			t.Errorf("%s: expected %v, got %v", c.name, c.valid, valid)
+		}
+	}
+}
+
+func TestLocalSignedURLRequiresKeyring(t *testing.T) {
+	s := NewLocal(&LocalConfig{Root: t.TempDir(), BaseURL: "https://example.com/files"})
+	if _, err := s.SignedURL(context.Background(), "avatars/1.png", "GET", time.Minute); err == nil {
+		t.Error("expected error without keyring")
+	}
+}
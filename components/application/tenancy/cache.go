@@ -0,0 +1,73 @@
+package tenancy
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/tiered"
+)
+
+// ErrClearNotSupported is returned by Clear of the scoped cache, keys of other tenants share the store.
+var ErrClearNotSupported = errors.New("clear is not supported by tenant scoped cache")
+
+type scopedCache struct {
+	cache  tiered.Cache
+	tenant *Tenant
+}
+
+// Cache scopes the keys of the cache with the tenant.
+func Cache(c tiered.Cache, tenant *Tenant) tiered.Cache {
+	return &scopedCache{cache: c, tenant: tenant}
+}
+
+func (c *scopedCache) Get(key string, value interface{}) error {
+	return c.cache.Get(c.tenant.Key(key), value)
+}
+
+func (c *scopedCache) Set(key string, value interface{}, ttl ...time.Duration) error {
+	return c.cache.Set(c.tenant.Key(key), value, ttl...)
+}
+
+func (c *scopedCache) Del(key string) error {
+	return c.cache.Del(c.tenant.Key(key))
+}
+
+func (c *scopedCache) Has(key string) bool {
+	return c.cache.Has(c.tenant.Key(key))
+}
+
+func (c *scopedCache) SetWith(key string, value interface{}, ttl time.Duration, opts ...tiered.SetOption) error {
+	return c.cache.SetWith(c.tenant.Key(key), value, ttl, c.scopeTags(opts)...)
+}
+
+func (c *scopedCache) GetOrSet(key string, value interface{}, ttl time.Duration, producer tiered.Producer, opts ...tiered.SetOption) error {
+	return c.cache.GetOrSet(c.tenant.Key(key), value, ttl, producer, c.scopeTags(opts)...)
+}
+
+func (c *scopedCache) InvalidateTag(tags ...string) error {
+	scoped := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		scoped = append(scoped, c.tenant.Key(tag))
+	}
+
+	return c.cache.InvalidateTag(scoped...)
+}
+
+// scopeTags scopes the tags of options with the tenant.
+func (c *scopedCache) scopeTags(opts []tiered.SetOption) []tiered.SetOption {
+	options := &tiered.SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	scoped := make([]string, 0, len(options.Tags))
+	for _, tag := range options.Tags {
+		scoped = append(scoped, c.tenant.Key(tag))
+	}
+
+	return []tiered.SetOption{tiered.WithTags(scoped...)}
+}
+
+func (c *scopedCache) Clear() error {
+	return ErrClearNotSupported
+}
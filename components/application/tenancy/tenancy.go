@@ -0,0 +1,242 @@
+package tenancy
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by the loader if the tenant does not exist.
+var ErrNotFound = errors.New("tenant not found")
+
+// DefaultHeader is the default request header carrying the tenant id.
+const DefaultHeader = "X-Tenant-ID"
+
+// DefaultTTL is the default duration the loaded tenant config is cached.
+const DefaultTTL = 5 * time.Minute
+
+// DefaultMaxEntries is the default max tenant configs cached.
+const DefaultMaxEntries = 10000
+
+// MaxIDLength is the max length of the tenant id.
+const MaxIDLength = 64
+
+// ValidID reports whether the id is a valid tenant id,
+// which consists of at most 64 letters, digits, '-', '_' or '.'.
+func ValidID(id string) bool {
+	if id == "" || len(id) > MaxIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Resolver resolves the tenant id of the request, empty if not found.
+type Resolver func(r *http.Request) string
+
+// FromHeader resolves the tenant id from the request header, such as X-Tenant-ID.
+// The header is supplied by the client, so the tenant must be checked against
+// the authenticated user before it grants any access.
+func FromHeader(name string) Resolver {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// FromSubdomain resolves the tenant id from the subdomain of the base domain,
+// such as acme.example.com => acme with base domain example.com.
+func FromSubdomain(domain string) Resolver {
+	suffix := "." + strings.TrimPrefix(domain, ".")
+	return func(r *http.Request) string {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		sub, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return ""
+		}
+
+		return sub
+	}
+}
+
+// FromPath resolves the tenant id from the path segment at the index,
+// such as /t/acme/users => acme with index 1.
+func FromPath(index int) Resolver {
+	return func(r *http.Request) string {
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if index < 0 || index >= len(segments) {
+			return ""
+		}
+
+		return segments[index]
+	}
+}
+
+// Loader loads the config of the tenant, returns ErrNotFound if the tenant does not exist.
+type Loader func(id string) (any, error)
+
+// Config is the config of tenancy.
+type Config struct {
+	// Resolvers are tried in order, no tenant is resolved without resolvers.
+	Resolvers []Resolver
+	// Loader loads the tenant config lazily on the first tenant.Config() call,
+	//	the tenants it does not know are not verified, see Tenant.Verified.
+	Loader Loader
+	// TTL is the duration the loaded config is cached, default: 5 minutes.
+	TTL time.Duration
+	// MaxEntries is the max tenant configs cached, default: 10000.
+	MaxEntries int
+}
+
+// Tenancy resolves the tenants of requests.
+type Tenancy interface {
+	// Resolve returns the tenant of the request, nil if not resolved.
+	Resolve(r *http.Request) *Tenant
+	// Invalidate drops the cached config of the tenant, it is loaded again on next access.
+	Invalidate(id string)
+}
+
+type entry struct {
+	once     sync.Once
+	config   any
+	err      error
+	loadedAt time.Time
+}
+
+type tenancy struct {
+	cfg     *Config
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New creates a tenancy.
+func New(cfg ...*Config) Tenancy {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.TTL == 0 {
+		cfgX.TTL = DefaultTTL
+	}
+	if cfgX.MaxEntries == 0 {
+		cfgX.MaxEntries = DefaultMaxEntries
+	}
+
+	return &tenancy{
+		cfg:     cfgX,
+		entries: map[string]*entry{},
+	}
+}
+
+func (t *tenancy) Resolve(r *http.Request) *Tenant {
+	for _, resolve := range t.cfg.Resolvers {
+		// invalid ids are ignored, they can't be used in keys safely
+		if id := resolve(r); ValidID(id) {
+			return &Tenant{ID: id, tenancy: t}
+		}
+	}
+
+	return nil
+}
+
+func (t *tenancy) Invalidate(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, id)
+}
+
+func (t *tenancy) load(id string) (any, error) {
+	if t.cfg.Loader == nil {
+		return nil, nil
+	}
+
+	t.mu.Lock()
+	e, ok := t.entries[id]
+	if !ok || time.Since(e.loadedAt) > t.cfg.TTL {
+		if !ok && len(t.entries) >= t.cfg.MaxEntries {
+			t.evict()
+		}
+
+		e = &entry{loadedAt: time.Now()}
+		t.entries[id] = e
+	}
+	t.mu.Unlock()
+
+	// concurrent requests of the same tenant share one load
+	e.once.Do(func() {
+		e.config, e.err = t.cfg.Loader(id)
+		if e.err != nil && !errors.Is(e.err, ErrNotFound) {
+			// transient errors are not cached
+			t.Invalidate(id)
+		}
+	})
+
+	return e.config, e.err
+}
+
+// evict drops the expired entries, or a random one if none expired, the caller must hold the lock.
+func (t *tenancy) evict() {
+	for id, e := range t.entries {
+		if time.Since(e.loadedAt) > t.cfg.TTL {
+			delete(t.entries, id)
+		}
+	}
+
+	for id := range t.entries {
+		if len(t.entries) < t.cfg.MaxEntries {
+			return
+		}
+
+		delete(t.entries, id)
+	}
+}
+
+// Tenant is the tenant of the request.
+type Tenant struct {
+	ID string `json:"id"`
+	//
+	tenancy *tenancy
+}
+
+// Config returns the tenant config loaded lazily by the loader, nil if no loader.
+func (t *Tenant) Config() (any, error) {
+	if t.tenancy == nil {
+		return nil, nil
+	}
+
+	return t.tenancy.load(t.ID)
+}
+
+// Verified reports whether the tenant is known by the loader, false if no loader.
+func (t *Tenant) Verified() bool {
+	if t.tenancy == nil || t.tenancy.cfg.Loader == nil {
+		return false
+	}
+
+	_, err := t.Config()
+	return err == nil
+}
+
+// Key scopes the key with the tenant, such as tenant:acme:users:1.
+// The id is escaped, so that ids containing ':' never collide with the keys of other tenants.
+func (t *Tenant) Key(keys ...string) string {
+	return "tenant:" + idEscaper.Replace(t.ID) + ":" + strings.Join(keys, ":")
+}
+
+var idEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
@@ -0,0 +1,99 @@
+package tenancy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidID(t *testing.T) {
+	cases := []struct {
+		id    string
+		valid bool
+	}{
+		{"acme", true},
+		{"acme-corp_1.eu", true},
+		{"", false},
+		{"a:b", false},
+		{"a b", false},
+		{"a/b", false},
+		{"acme%3A", false},
+		{string(make([]byte, MaxIDLength+1)), false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.valid, ValidID(c.id), c.id)
+	}
+}
+
+func TestResolveIsOptIn(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultHeader, "acme")
+
+	assert.Nil(t, New().Resolve(req))
+
+	tenant := New(&Config{Resolvers: []Resolver{FromHeader(DefaultHeader)}}).Resolve(req)
+	assert.Equal(t, "acme", tenant.ID)
+}
+
+func TestResolveIgnoresInvalidID(t *testing.T) {
+	tn := New(&Config{Resolvers: []Resolver{FromHeader(DefaultHeader), FromPath(1)}})
+
+	req := httptest.NewRequest(http.MethodGet, "/t/acme", nil)
+	req.Header.Set(DefaultHeader, "evil:acme")
+	assert.Equal(t, "acme", tn.Resolve(req).ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultHeader, "evil:acme")
+	assert.Nil(t, tn.Resolve(req))
+}
+
+func TestKeyDoesNotCollide(t *testing.T) {
+	// a:b + c and a + b:c would both be tenant:a:b:c without escaping
+	a := &Tenant{ID: "a:b"}
+	b := &Tenant{ID: "a"}
+	assert.NotEqual(t, a.Key("c"), b.Key("b:c"))
+	assert.Equal(t, "tenant:a%3Ab:c", a.Key("c"))
+	assert.Equal(t, "tenant:acme:users:1", (&Tenant{ID: "acme"}).Key("users", "1"))
+}
+
+func TestVerified(t *testing.T) {
+	resolvers := []Resolver{FromHeader(DefaultHeader)}
+	req := func(id string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(DefaultHeader, id)
+		return r
+	}
+
+	assert.False(t, New(&Config{Resolvers: resolvers}).Resolve(req("acme")).Verified())
+
+	tn := New(&Config{
+		Resolvers: resolvers,
+		Loader: func(id string) (any, error) {
+			if id != "acme" {
+				return nil, ErrNotFound
+			}
+
+			return map[string]string{"plan": "pro"}, nil
+		},
+	})
+	assert.True(t, tn.Resolve(req("acme")).Verified())
+	assert.False(t, tn.Resolve(req("spoofed")).Verified())
+}
+
+func TestLoaderCacheIsBounded(t *testing.T) {
+	tn := New(&Config{
+		MaxEntries: 2,
+		Loader: func(id string) (any, error) {
+			return nil, ErrNotFound
+		},
+	}).(*tenancy)
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		(&Tenant{ID: id, tenancy: tn}).Config()
+	}
+
+	assert.LessOrEqual(t, len(tn.entries), 2)
+}
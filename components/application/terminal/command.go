@@ -0,0 +1,59 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"syscall"
+
+	"github.com/go-zoox/command"
+	cterminal "github.com/go-zoox/command/terminal"
+)
+
+type commandSession struct {
+	cterminal.Terminal
+	cmd  command.Command
+	once sync.Once
+}
+
+// NewCommand attaches the terminal of the command, such as the commands of app.Cmd(),
+// which run on the engines of go-zoox/command (host, docker, ssh ...) and are killed on app shutdown.
+func NewCommand(cmd command.Command, rows, cols uint16) (Session, error) {
+	t, err := cmd.Terminal()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Resize(int(rows), int(cols)); err != nil {
+		t.Close()
+		cmd.Cancel()
+		return nil, err
+	}
+
+	return &commandSession{Terminal: t, cmd: cmd}, nil
+}
+
+// Read returns io.EOF instead of EIO after the command exits.
+func (s *commandSession) Read(p []byte) (int, error) {
+	n, err := s.Terminal.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func (s *commandSession) Resize(rows, cols uint16) error {
+	return s.Terminal.Resize(int(rows), int(cols))
+}
+
+func (s *commandSession) Close() error {
+	var err error
+	s.once.Do(func() {
+		// the terminal of go-zoox/command does not stop the command on close
+		s.cmd.Cancel()
+		err = s.Terminal.Close()
+	})
+
+	return err
+}
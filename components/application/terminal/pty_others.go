@@ -0,0 +1,67 @@
+//go:build !windows
+
+package terminal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/creack/pty"
+)
+
+type ptySession struct {
+	*os.File
+	cmd  *exec.Cmd
+	once sync.Once
+}
+
+// NewPTY starts the command in a pty with the window size, such as exec.Command("bash").
+func NewPTY(cmd *exec.Cmd, rows, cols uint16) (Session, error) {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: rows, Cols: cols})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ptySession{File: f, cmd: cmd}, nil
+}
+
+// Read returns io.EOF instead of EIO after the command exits.
+func (s *ptySession) Read(p []byte) (int, error) {
+	n, err := s.File.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func (s *ptySession) Resize(rows, cols uint16) error {
+	return pty.Setsize(s.File, &pty.Winsize{Rows: rows, Cols: cols})
+}
+
+func (s *ptySession) Wait() error {
+	return s.cmd.Wait()
+}
+
+func (s *ptySession) Close() error {
+	var err error
+	s.once.Do(func() {
+		// the exited process is killed with error ignored
+		if s.cmd.Process != nil {
+			s.cmd.Process.Kill()
+		}
+
+		err = s.File.Close()
+	})
+
+	return err
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package terminal
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// NewPTY is not supported on windows.
+func NewPTY(cmd *exec.Cmd, rows, cols uint16) (Session, error) {
+	return nil, errors.New("terminal: pty is not supported on windows")
+}
@@ -0,0 +1,56 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type asciicast struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAsciicastRecorder creates the recorder writing the asciicast v2 format, which can be replayed by asciinema.
+func NewAsciicastRecorder(w io.Writer, rows, cols uint16) (Recorder, error) {
+	header, err := json.Marshal(map[string]any{
+		"version":   2,
+		"width":     cols,
+		"height":    rows,
+		"timestamp": time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", header); err != nil {
+		return nil, err
+	}
+
+	return &asciicast{w: w}, nil
+}
+
+func (r *asciicast) Output(elapsed time.Duration, data []byte) {
+	r.event(elapsed, "o", string(data))
+}
+
+func (r *asciicast) Input(elapsed time.Duration, data []byte) {
+	r.event(elapsed, "i", string(data))
+}
+
+func (r *asciicast) Resize(elapsed time.Duration, rows, cols uint16) {
+	r.event(elapsed, "r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+func (r *asciicast) event(elapsed time.Duration, typ, data string) {
+	line, err := json.Marshal([]any{elapsed.Seconds(), typ, data})
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.w, "%s\n", line)
+}
@@ -0,0 +1,81 @@
+package terminal
+
+import (
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type sshSession struct {
+	session *ssh.Session
+	stdin   io.WriteCloser
+	stdout  io.Reader
+}
+
+// NewSSH starts the ssh session with a pty of the window size,
+// the command is run, or the login shell if empty.
+func NewSSH(client *ssh.Client, command string, rows, cols uint16) (Session, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty("xterm-256color", int(rows), int(cols), modes); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	// stderr is merged into stdout by the remote pty
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	if command == "" {
+		err = session.Shell()
+	} else {
+		err = session.Start(command)
+	}
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	return &sshSession{
+		session: session,
+		stdin:   stdin,
+		stdout:  stdout,
+	}, nil
+}
+
+func (s *sshSession) Read(p []byte) (int, error) {
+	return s.stdout.Read(p)
+}
+
+func (s *sshSession) Write(p []byte) (int, error) {
+	return s.stdin.Write(p)
+}
+
+func (s *sshSession) Resize(rows, cols uint16) error {
+	return s.session.WindowChange(int(rows), int(cols))
+}
+
+func (s *sshSession) Wait() error {
+	return s.session.Wait()
+}
+
+func (s *sshSession) Close() error {
+	return s.session.Close()
+}
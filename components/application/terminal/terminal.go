@@ -0,0 +1,237 @@
+// Package terminal attaches a terminal session (local pty or ssh) to a websocket,
+// with resize messages, idle timeout and recording hooks.
+//
+// Protocol:
+//
+//	client => server  binary frame: stdin
+//	client => server  text frame:   {"type":"input","data":"ls\r"} or {"type":"resize","rows":24,"cols":80}
+//	server => client  binary frame: stdout (and stderr)
+package terminal
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/wslimit"
+	"github.com/gorilla/websocket"
+)
+
+// ErrIdleTimeout is returned by Serve when the session is idle longer than Config.IdleTimeout.
+var ErrIdleTimeout = errors.New("terminal idle timeout")
+
+// Session is the attached terminal, such as a local pty or an ssh session.
+type Session interface {
+	io.ReadWriter
+	// Resize changes the window size.
+	Resize(rows, cols uint16) error
+	// Wait waits for the session to exit.
+	Wait() error
+	// Close terminates the session.
+	Close() error
+}
+
+// Recorder records the session, such as for auditing or replaying.
+type Recorder interface {
+	// Output records the output at the elapsed time since started.
+	Output(elapsed time.Duration, data []byte)
+	// Input records the input.
+	Input(elapsed time.Duration, data []byte)
+	// Resize records the window size.
+	Resize(elapsed time.Duration, rows, cols uint16)
+}
+
+// Config is the config of Serve.
+type Config struct {
+	// IdleTimeout closes the session without input and output for the duration, 0 means no timeout.
+	IdleTimeout time.Duration
+	// Recorder records the session, optional.
+	Recorder Recorder
+	// RateLimit limits the input messages, optional.
+	RateLimit *wslimit.Config
+}
+
+// Message is the control message of the text frames.
+type Message struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+}
+
+// Message types.
+const (
+	MessageTypeInput  = "input"
+	MessageTypeResize = "resize"
+)
+
+// Serve pipes the session and the websocket until either ends, the session is closed when returned.
+func Serve(conn *websocket.Conn, session Session, cfg ...*Config) error {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	t := &terminal{
+		conn:      conn,
+		reader:    wslimit.Wrap(conn, cfgX.RateLimit),
+		session:   session,
+		cfg:       cfgX,
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+	t.touch()
+
+	defer session.Close()
+
+	// the output ends (EOF) after the session exits and the rest output is sent
+	go session.Wait()
+
+	errCh := make(chan error, 3)
+	go func() { errCh <- t.output() }()
+	go func() { errCh <- t.input() }()
+	if cfgX.IdleTimeout > 0 {
+		go func() { errCh <- t.watchIdle() }()
+	}
+
+	err := <-errCh
+	close(t.done)
+
+	code, reason := websocket.CloseNormalClosure, "exit"
+	if errors.Is(err, ErrIdleTimeout) {
+		code, reason = websocket.CloseGoingAway, "idle timeout"
+	}
+	// the connection is closed by the limiter
+	if !errors.Is(err, wslimit.ErrRateLimited) {
+		t.mu.Lock()
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+		t.mu.Unlock()
+	}
+
+	if err == io.EOF {
+		return nil
+	}
+
+	return err
+}
+
+type terminal struct {
+	conn      *websocket.Conn
+	reader    *wslimit.Conn
+	session   Session
+	cfg       *Config
+	startedAt time.Time
+	done      chan struct{}
+
+	mu           sync.Mutex
+	lastActiveAt time.Time
+	activeMu     sync.Mutex
+}
+
+func (t *terminal) touch() {
+	t.activeMu.Lock()
+	t.lastActiveAt = time.Now()
+	t.activeMu.Unlock()
+}
+
+func (t *terminal) idle() time.Duration {
+	t.activeMu.Lock()
+	defer t.activeMu.Unlock()
+	return time.Since(t.lastActiveAt)
+}
+
+func (t *terminal) output() error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := t.session.Read(buf)
+		if n > 0 {
+			t.touch()
+			if t.cfg.Recorder != nil {
+				t.cfg.Recorder.Output(time.Since(t.startedAt), buf[:n])
+			}
+
+			t.mu.Lock()
+			werr := t.conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+			t.mu.Unlock()
+			if werr != nil {
+				return werr
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (t *terminal) input() error {
+	for {
+		typ, data, err := t.reader.ReadMessage()
+		if err != nil {
+			return err
+		}
+		t.touch()
+
+		if typ == websocket.BinaryMessage {
+			if err := t.write(data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		message := &Message{}
+		if err := json.Unmarshal(data, message); err != nil {
+			// plain text is the input
+			if err := t.write(data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch message.Type {
+		case MessageTypeInput:
+			if err := t.write([]byte(message.Data)); err != nil {
+				return err
+			}
+		case MessageTypeResize:
+			if message.Rows == 0 || message.Cols == 0 {
+				continue
+			}
+
+			if err := t.session.Resize(message.Rows, message.Cols); err != nil {
+				return err
+			}
+
+			if t.cfg.Recorder != nil {
+				t.cfg.Recorder.Resize(time.Since(t.startedAt), message.Rows, message.Cols)
+			}
+		}
+	}
+}
+
+func (t *terminal) write(data []byte) error {
+	if t.cfg.Recorder != nil {
+		t.cfg.Recorder.Input(time.Since(t.startedAt), data)
+	}
+
+	_, err := t.session.Write(data)
+	return err
+}
+
+func (t *terminal) watchIdle() error {
+	ticker := time.NewTicker(t.cfg.IdleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return nil
+		case <-ticker.C:
+			if t.idle() > t.cfg.IdleTimeout {
+				return ErrIdleTimeout
+			}
+		}
+	}
+}
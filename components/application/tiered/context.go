@@ -0,0 +1,76 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-zoox/cache"
+)
+
+type contextCache struct {
+	Cache
+	ctx context.Context
+}
+
+// WithContext binds the cache to the context, Get, Set, SetWith and GetOrSet return the error of the context
+// once it is cancelled or timed out, so that a cancelled request stops the downstream work, such as the producer.
+// Del, Clear and InvalidateTag are not stopped, so that the cleanups still run.
+func WithContext(c cache.Cache, ctx context.Context) Cache {
+	tc := From(c)
+	// rebind instead of nesting
+	if cc, ok := tc.(*contextCache); ok {
+		tc = cc.Cache
+	}
+
+	return &contextCache{Cache: tc, ctx: ctx}
+}
+
+func (c *contextCache) Get(key string, value any) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	return c.Cache.Get(key, value)
+}
+
+func (c *contextCache) Set(key string, value any, ttl ...time.Duration) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	return c.Cache.Set(key, value, ttl...)
+}
+
+func (c *contextCache) SetWith(key string, value any, ttl time.Duration, opts ...SetOption) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	return c.Cache.SetWith(key, value, ttl, opts...)
+}
+
+func (c *contextCache) GetOrSet(key string, value any, ttl time.Duration, producer Producer, opts ...SetOption) error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+
+	getOrSet := func() error {
+		return c.Cache.GetOrSet(key, value, ttl, func() (any, error) {
+			// the producer is skipped if cancelled while waiting for the cache
+			if err := c.ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			return producer()
+		}, opts...)
+	}
+
+	err := getOrSet()
+	// the shared producer of the concurrent misses was cancelled by another request
+	if err != nil && c.ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		err = getOrSet()
+	}
+
+	return err
+}
@@ -0,0 +1,114 @@
+package tiered
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/keyring"
+)
+
+type encryptedCache struct {
+	Cache
+	keyring    keyring.Keyring
+	namespaces []string
+}
+
+// Encrypted encrypts the values of keys in the namespaces (key prefixes, such as user:) with the keyring,
+// the namespaces also match after other prefixes, such as tenant:<id>:user:1 of user:.
+// Values are encoded as json before encryption.
+func Encrypted(c Cache, kr keyring.Keyring, namespaces ...string) Cache {
+	return &encryptedCache{
+		Cache:      c,
+		keyring:    kr,
+		namespaces: namespaces,
+	}
+}
+
+func (c *encryptedCache) match(key string) bool {
+	for _, namespace := range c.namespaces {
+		if strings.HasPrefix(key, namespace) || strings.Contains(key, ":"+namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *encryptedCache) encrypt(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return c.keyring.Encrypt(data)
+}
+
+func (c *encryptedCache) decrypt(payload string, value any) error {
+	data, err := c.keyring.Decrypt(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+func (c *encryptedCache) Get(key string, value interface{}) error {
+	if !c.match(key) {
+		return c.Cache.Get(key, value)
+	}
+
+	var payload string
+	if err := c.Cache.Get(key, &payload); err != nil {
+		return err
+	}
+
+	return c.decrypt(payload, value)
+}
+
+func (c *encryptedCache) Set(key string, value interface{}, ttl ...time.Duration) error {
+	if !c.match(key) {
+		return c.Cache.Set(key, value, ttl...)
+	}
+
+	payload, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.Set(key, payload, ttl...)
+}
+
+func (c *encryptedCache) SetWith(key string, value interface{}, ttl time.Duration, opts ...SetOption) error {
+	if !c.match(key) {
+		return c.Cache.SetWith(key, value, ttl, opts...)
+	}
+
+	payload, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.SetWith(key, payload, ttl, opts...)
+}
+
+func (c *encryptedCache) GetOrSet(key string, value interface{}, ttl time.Duration, producer Producer, opts ...SetOption) error {
+	if !c.match(key) {
+		return c.Cache.GetOrSet(key, value, ttl, producer, opts...)
+	}
+
+	var payload string
+	err := c.Cache.GetOrSet(key, &payload, ttl, func() (any, error) {
+		produced, err := producer()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.encrypt(produced)
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	return c.decrypt(payload, value)
+}
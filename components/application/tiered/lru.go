@@ -0,0 +1,104 @@
+package tiered
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key      string
+	value    []byte
+	tags     []string
+	expireAt time.Time
+}
+
+// lru is the process-local tier, values are the encoded json with ttl.
+type lru struct {
+	sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func (c *lru) Get(key string) ([]byte, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expireAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *lru) Set(key string, value []byte, tags []string, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	expireAt := time.Now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value, entry.tags, entry.expireAt = value, tags, expireAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, tags: tags, expireAt: expireAt})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) Del(key string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// DelTag deletes the keys with the tag.
+func (c *lru) DelTag(tag string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for key, el := range c.items {
+		for _, t := range el.Value.(*lruEntry).tags {
+			if t == tag {
+				c.ll.Remove(el)
+				delete(c.items, key)
+				break
+			}
+		}
+	}
+}
+
+func (c *lru) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+}
@@ -0,0 +1,375 @@
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-zoox/cache"
+	"github.com/go-zoox/random"
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultLocalSize is the default max keys of the local tier.
+const DefaultLocalSize = 10000
+
+// DefaultLocalTTL is the default max ttl of keys in the local tier.
+const DefaultLocalTTL = 10 * time.Second
+
+// ErrNotFound is returned by Get if the key is invalidated by its tags.
+var ErrNotFound = errors.New("cache: key not found")
+
+// Producer produces the value of a missing key.
+type Producer func() (any, error)
+
+// SetOptions are the options of SetWith.
+type SetOptions struct {
+	Tags []string
+}
+
+// SetOption is the option of SetWith.
+type SetOption func(opts *SetOptions)
+
+// WithTags tags the key, so that it can be purged together with related keys by InvalidateTag.
+func WithTags(tags ...string) SetOption {
+	return func(opts *SetOptions) {
+		opts.Tags = append(opts.Tags, tags...)
+	}
+}
+
+// Cache is the cache with tags, GetOrSet and the optional process-local tier.
+type Cache interface {
+	cache.Cache
+	// SetWith sets the value with options, such as tags:
+	//	cache.SetWith("user:42:profile", profile, time.Hour, tiered.WithTags("user:42"))
+	SetWith(key string, value any, ttl time.Duration, opts ...SetOption) error
+	// GetOrSet gets the value of key into value, or calls producer, stores the produced value with ttl and decodes it into value.
+	// Concurrent misses of the same key in the process share one producer call (stampede protection).
+	GetOrSet(key string, value any, ttl time.Duration, producer Producer, opts ...SetOption) error
+	// InvalidateTag purges the keys tagged with any of the tags.
+	InvalidateTag(tags ...string) error
+}
+
+// Config is the config of tiered cache.
+type Config struct {
+	// Local enables the process-local lru tier in front of the backing cache (redis),
+	//	so that hot keys are not read from redis on every request.
+	Local bool
+	// LocalSize is the max keys of the local tier, default: 10000.
+	LocalSize int
+	// LocalTTL is the max ttl of keys in the local tier, default: 10s.
+	//	It bounds the staleness if an invalidation message is lost.
+	LocalTTL time.Duration
+	// PubSub broadcasts the invalidations of Set/Del/Clear to the local tiers of other instances.
+	PubSub pubsub.PubSub
+	// Topic is the invalidation topic, default: cache:invalidate.
+	Topic string
+}
+
+type invalidation struct {
+	ID  string `json:"id"`
+	Key string `json:"key,omitempty"`
+	Tag string `json:"tag,omitempty"`
+	// All clears the local tier
+	All bool `json:"all,omitempty"`
+}
+
+type tiered struct {
+	id     string
+	remote cache.Cache
+	local  *lru
+	cfg    *Config
+	group  singleflight.Group
+}
+
+// From returns the tiered features of c, such as app.Cache() or ctx.Cache():
+// c itself if it implements Cache, otherwise c wrapped by New without the local tier.
+//
+//	err := tiered.From(ctx.Cache()).GetOrSet(key, &value, time.Minute, produce)
+func From(c cache.Cache) Cache {
+	if tc, ok := c.(Cache); ok {
+		return tc
+	}
+
+	return New(c)
+}
+
+// New creates the cache backed by the remote cache (such as redis), with the optional local tier.
+//
+// Tags are versioned: the key records the versions of its tags, InvalidateTag bumps the versions,
+// so keys with outdated versions are misses without listing the keys of tags.
+func New(remote cache.Cache, cfg ...*Config) Cache {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.LocalSize == 0 {
+		cfgX.LocalSize = DefaultLocalSize
+	}
+	if cfgX.LocalTTL == 0 {
+		cfgX.LocalTTL = DefaultLocalTTL
+	}
+	if cfgX.Topic == "" {
+		cfgX.Topic = "cache:invalidate"
+	}
+
+	c := &tiered{
+		id:     random.String(16),
+		remote: remote,
+		cfg:    cfgX,
+	}
+
+	if cfgX.Local {
+		c.local = newLRU(cfgX.LocalSize)
+
+		if cfgX.PubSub != nil {
+			go cfgX.PubSub.Subscribe(context.Background(), cfgX.Topic, func(msg *pubsub.Message) error {
+				message := &invalidation{}
+				if err := json.Unmarshal(msg.Body, message); err != nil || message.ID == c.id {
+					return err
+				}
+
+				switch {
+				case message.All:
+					c.local.Clear()
+				case message.Tag != "":
+					c.local.DelTag(message.Tag)
+				default:
+					c.local.Del(message.Key)
+				}
+				return nil
+			})
+		}
+	}
+
+	return c
+}
+
+func (c *tiered) Get(key string, value any) error {
+	if c.local != nil {
+		if data, ok := c.local.Get(key); ok {
+			return json.Unmarshal(data, value)
+		}
+	}
+
+	if err := c.remote.Get(key, value); err != nil {
+		return err
+	}
+
+	tags, err := c.validate(key)
+	if err != nil {
+		return err
+	}
+
+	if c.local != nil {
+		if data, err := json.Marshal(value); err == nil {
+			c.local.Set(key, data, tags, c.cfg.LocalTTL)
+		}
+	}
+
+	return nil
+}
+
+func (c *tiered) Set(key string, value any, ttl ...time.Duration) error {
+	if err := c.remote.Set(key, ref(value), ttl...); err != nil {
+		return err
+	}
+
+	// the key is not tagged anymore
+	c.remote.Del(c.metaKey(key))
+
+	c.invalidateLocal(&invalidation{Key: key})
+	return nil
+}
+
+func (c *tiered) SetWith(key string, value any, ttl time.Duration, opts ...SetOption) error {
+	options := &SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if len(options.Tags) == 0 {
+		return c.Set(key, value, ttl)
+	}
+
+	versions := map[string]string{}
+	for _, tag := range options.Tags {
+		version, err := c.tagVersion(tag)
+		if err != nil {
+			return err
+		}
+
+		versions[tag] = version
+	}
+
+	if err := c.remote.Set(c.metaKey(key), &versions, ttl); err != nil {
+		return err
+	}
+	if err := c.remote.Set(key, ref(value), ttl); err != nil {
+		return err
+	}
+
+	c.invalidateLocal(&invalidation{Key: key})
+	return nil
+}
+
+func (c *tiered) Del(key string) error {
+	c.invalidateLocal(&invalidation{Key: key})
+	c.remote.Del(c.metaKey(key))
+
+	return c.remote.Del(key)
+}
+
+func (c *tiered) Has(key string) bool {
+	if c.local != nil {
+		if _, ok := c.local.Get(key); ok {
+			return true
+		}
+	}
+
+	if !c.remote.Has(key) {
+		return false
+	}
+
+	_, err := c.validate(key)
+	return err == nil
+}
+
+func (c *tiered) Clear() error {
+	c.invalidateLocal(&invalidation{All: true})
+
+	return c.remote.Clear()
+}
+
+func (c *tiered) GetOrSet(key string, value any, ttl time.Duration, producer Producer, opts ...SetOption) error {
+	if err := c.Get(key, value); err == nil {
+		return nil
+	}
+
+	data, err, _ := c.group.Do(key, func() (any, error) {
+		// produced by the previous flight
+		if err := c.Get(key, value); err == nil {
+			return json.Marshal(value)
+		}
+
+		produced, err := producer()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.SetWith(key, produced, ttl, opts...); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(produced)
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data.([]byte), value)
+}
+
+func (c *tiered) InvalidateTag(tags ...string) error {
+	for _, tag := range tags {
+		if err := c.remote.Set(c.tagKey(tag), ref(c.newVersion())); err != nil {
+			return err
+		}
+
+		c.invalidateLocal(&invalidation{Tag: tag})
+	}
+
+	return nil
+}
+
+// validate returns the tags of the key, ErrNotFound (and the key is deleted) if any tag is invalidated.
+func (c *tiered) validate(key string) ([]string, error) {
+	versions := map[string]string{}
+	if err := c.remote.Get(c.metaKey(key), &versions); err != nil || len(versions) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]string, 0, len(versions))
+	for tag, version := range versions {
+		var current string
+		if err := c.remote.Get(c.tagKey(tag), &current); err != nil || current != version {
+			c.remote.Del(key)
+			c.remote.Del(c.metaKey(key))
+			return nil, ErrNotFound
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// tagVersion returns the current version of the tag, created if not exists.
+func (c *tiered) tagVersion(tag string) (string, error) {
+	var version string
+	if err := c.remote.Get(c.tagKey(tag), &version); err == nil && version != "" {
+		return version, nil
+	}
+
+	version = c.newVersion()
+	return version, c.remote.Set(c.tagKey(tag), &version)
+}
+
+func (c *tiered) newVersion() string {
+	return c.id + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func (c *tiered) metaKey(key string) string {
+	return "cache:tags-of:" + key
+}
+
+func (c *tiered) tagKey(tag string) string {
+	return "cache:tag:" + tag
+}
+
+// invalidateLocal deletes the local tier and broadcasts the invalidation to other instances.
+func (c *tiered) invalidateLocal(message *invalidation) {
+	if c.local == nil {
+		return
+	}
+
+	switch {
+	case message.All:
+		c.local.Clear()
+	case message.Tag != "":
+		c.local.DelTag(message.Tag)
+	default:
+		c.local.Del(message.Key)
+	}
+
+	if c.cfg.PubSub == nil {
+		return
+	}
+
+	message.ID = c.id
+	body, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	c.cfg.PubSub.Publish(context.Background(), &pubsub.Message{
+		Topic: c.cfg.Topic,
+		Body:  body,
+	})
+}
+
+// ref returns the value as a pointer, the memory cache stores values as is and dereferences them on Get.
+func ref(value any) any {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() == reflect.Ptr {
+		return value
+	}
+
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
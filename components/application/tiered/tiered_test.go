@@ -0,0 +1,312 @@
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/cache"
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+var errMissing = errors.New("missing")
+
+// remoteCache is the shared remote tier of the instances, values are stored as json like redis.
+type remoteCache struct {
+	sync.Mutex
+	values map[string][]byte
+}
+
+func newRemoteCache() *remoteCache {
+	return &remoteCache{values: map[string][]byte{}}
+}
+
+func (c *remoteCache) Get(key string, value any) error {
+	c.Lock()
+	data, ok := c.values[key]
+	c.Unlock()
+	if !ok {
+		return errMissing
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+func (c *remoteCache) Set(key string, value any, ttl ...time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.values[key] = data
+	return nil
+}
+
+func (c *remoteCache) Del(key string) error {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *remoteCache) Has(key string) bool {
+	c.Lock()
+	defer c.Unlock()
+	_, ok := c.values[key]
+	return ok
+}
+
+func (c *remoteCache) Clear() error {
+	c.Lock()
+	defer c.Unlock()
+	c.values = map[string][]byte{}
+	return nil
+}
+
+// subscribedPubSub signals once the subscription is registered,
+// so that the invalidations are not published before the instance subscribes.
+type subscribedPubSub struct {
+	pubsub.PubSub
+	subscribed chan struct{}
+}
+
+func (p *subscribedPubSub) Subscribe(ctx context.Context, topic string, handler pubsub.Handler) error {
+	err := p.PubSub.Subscribe(ctx, topic, handler)
+	p.subscribed <- struct{}{}
+	return err
+}
+
+// newInstances creates n instances with the local tier, sharing the remote cache and the pubsub.
+func newInstances(t *testing.T, n int, remote *remoteCache) []Cache {
+	ps := &subscribedPubSub{PubSub: pubsub.NewMemory(), subscribed: make(chan struct{}, n)}
+
+	instances := make([]Cache, n)
+	for i := range instances {
+		instances[i] = New(remote, &Config{Local: true, LocalTTL: time.Minute, PubSub: ps})
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-ps.subscribed:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the invalidation subscriptions")
+		}
+	}
+
+	return instances
+}
+
+// eventually asserts the value of key is expected, the invalidations are delivered asynchronously.
+func eventually(t *testing.T, c Cache, key string, expected string) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		var value string
+		return c.Get(key, &value) == nil && value == expected
+	}, time.Second, 5*time.Millisecond, "expected %s of %s", expected, key)
+}
+
+func TestTieredLocalTier(t *testing.T) {
+	remote := newRemoteCache()
+	c := New(remote, &Config{Local: true, LocalTTL: time.Minute})
+
+	assert.NoError(t, c.Set("key", "v1"))
+
+	var value string
+	assert.NoError(t, c.Get("key", &value))
+	assert.Equal(t, "v1", value)
+
+	// served by the local tier, without reading the remote
+	remote.Set("key", "changed")
+	assert.NoError(t, c.Get("key", &value))
+	assert.Equal(t, "v1", value)
+
+	// the own writes invalidate the local tier
+	assert.NoError(t, c.Set("key", "v2"))
+	assert.NoError(t, c.Get("key", &value))
+	assert.Equal(t, "v2", value)
+}
+
+func TestTieredInvalidationAcrossInstances(t *testing.T) {
+	remote := newRemoteCache()
+	instances := newInstances(t, 2, remote)
+	a, b := instances[0], instances[1]
+
+	assert.NoError(t, a.Set("key", "v1"))
+	assert.NoError(t, a.Set("other", "o1"))
+
+	// cached in the local tier of b
+	var value string
+	assert.NoError(t, b.Get("key", &value))
+	assert.Equal(t, "v1", value)
+	assert.NoError(t, b.Get("other", &value))
+	assert.Equal(t, "o1", value)
+
+	// Set
+	assert.NoError(t, a.Set("key", "v2"))
+	eventually(t, b, "key", "v2")
+
+	// Del
+	assert.NoError(t, a.Del("key"))
+	assert.Eventually(t, func() bool {
+		return b.Get("key", &value) != nil
+	}, time.Second, 5*time.Millisecond)
+
+	// Clear
+	assert.NoError(t, b.Get("other", &value))
+	assert.NoError(t, a.Clear())
+	assert.NoError(t, remote.Set("other", "o2"))
+	eventually(t, b, "other", "o2")
+}
+
+func TestTieredMemoryCache(t *testing.T) {
+	// the memory cache stores the values as is, instead of the encoded json
+	c := New(cache.New(), &Config{Local: true})
+
+	var value string
+	assert.NoError(t, c.GetOrSet("key", &value, time.Minute, func() (any, error) {
+		return "produced", nil
+	}, WithTags("tag")))
+	assert.Equal(t, "produced", value)
+
+	assert.NoError(t, New(cache.New()).Set("plain", "value"))
+
+	assert.NoError(t, c.InvalidateTag("tag"))
+	assert.ErrorIs(t, c.Get("key", &value), ErrNotFound)
+}
+
+func TestTieredGetOrSetStampede(t *testing.T) {
+	c := New(newRemoteCache(), &Config{Local: true})
+
+	var calls atomic.Int32
+	producer := func() (any, error) {
+		calls.Add(1)
+		// slow enough for the concurrent misses to wait for it
+		time.Sleep(50 * time.Millisecond)
+		return "produced", nil
+	}
+
+	start := make(chan struct{})
+	values := make([]string, 50)
+	errs := make([]error, len(values))
+
+	var wg sync.WaitGroup
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = c.GetOrSet("key", &values[i], time.Minute, producer)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for i := range values {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "produced", values[i])
+	}
+
+	// stored
+	var value string
+	assert.NoError(t, c.Get("key", &value))
+	assert.Equal(t, "produced", value)
+}
+
+func TestTieredGetOrSetError(t *testing.T) {
+	c := New(newRemoteCache())
+
+	var calls atomic.Int32
+	failed := errors.New("failed")
+
+	var value string
+	err := c.GetOrSet("key", &value, time.Minute, func() (any, error) {
+		calls.Add(1)
+		return nil, failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.False(t, c.Has("key"))
+
+	// the error is not cached
+	assert.NoError(t, c.GetOrSet("key", &value, time.Minute, func() (any, error) {
+		calls.Add(1)
+		return "produced", nil
+	}))
+	assert.Equal(t, "produced", value)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestTieredInvalidateTag(t *testing.T) {
+	remote := newRemoteCache()
+	c := New(remote)
+
+	assert.NoError(t, c.SetWith("user:42:profile", "profile", time.Minute, WithTags("user:42")))
+	assert.NoError(t, c.SetWith("user:42:orders", "orders", time.Minute, WithTags("user:42", "orders")))
+	assert.NoError(t, c.SetWith("user:7:orders", "orders of 7", time.Minute, WithTags("user:7", "orders")))
+	assert.NoError(t, c.Set("untagged", "value"))
+
+	var value string
+	assert.NoError(t, c.Get("user:42:profile", &value))
+	assert.Equal(t, "profile", value)
+
+	assert.NoError(t, c.InvalidateTag("user:42"))
+	assert.ErrorIs(t, c.Get("user:42:profile", &value), ErrNotFound)
+	assert.ErrorIs(t, c.Get("user:42:orders", &value), ErrNotFound)
+	assert.False(t, c.Has("user:42:profile"))
+	// the outdated keys are deleted on read
+	assert.False(t, remote.Has("user:42:profile"))
+
+	// other tags are kept
+	assert.NoError(t, c.Get("user:7:orders", &value))
+	assert.Equal(t, "orders of 7", value)
+	assert.NoError(t, c.Get("untagged", &value))
+	assert.Equal(t, "value", value)
+
+	// set again with the new version
+	assert.NoError(t, c.SetWith("user:42:profile", "profile v2", time.Minute, WithTags("user:42")))
+	assert.NoError(t, c.Get("user:42:profile", &value))
+	assert.Equal(t, "profile v2", value)
+
+	// any tag invalidates the key
+	assert.NoError(t, c.InvalidateTag("orders"))
+	assert.ErrorIs(t, c.Get("user:7:orders", &value), ErrNotFound)
+	assert.NoError(t, c.Get("user:42:profile", &value))
+
+	// the key is not tagged after Set
+	assert.NoError(t, c.Set("user:42:profile", "plain"))
+	assert.NoError(t, c.InvalidateTag("user:42"))
+	assert.NoError(t, c.Get("user:42:profile", &value))
+	assert.Equal(t, "plain", value)
+}
+
+func TestTieredInvalidateTagAcrossInstances(t *testing.T) {
+	instances := newInstances(t, 2, newRemoteCache())
+	a, b := instances[0], instances[1]
+
+	assert.NoError(t, a.SetWith("user:42:profile", "profile", time.Minute, WithTags("user:42")))
+
+	// cached in the local tier of b with the tags
+	var value string
+	assert.NoError(t, b.Get("user:42:profile", &value))
+	assert.Equal(t, "profile", value)
+
+	assert.NoError(t, a.InvalidateTag("user:42"))
+	assert.Eventually(t, func() bool {
+		return errors.Is(b.Get("user:42:profile", &value), ErrNotFound)
+	}, time.Second, 5*time.Millisecond)
+
+	// GetOrSet produces the value again
+	assert.NoError(t, b.GetOrSet("user:42:profile", &value, time.Minute, func() (any, error) {
+		return "profile v2", nil
+	}, WithTags("user:42")))
+	assert.Equal(t, "profile v2", value)
+	eventually(t, a, "user:42:profile", "profile v2")
+}
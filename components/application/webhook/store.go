@@ -0,0 +1,232 @@
+package webhook
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/cache"
+)
+
+// Store persists the deliveries.
+type Store interface {
+	Save(delivery *Delivery) error
+	Get(id string) (*Delivery, error)
+	// List returns the deliveries of the status, all if status is empty, newest first.
+	List(status string) ([]*Delivery, error)
+}
+
+// DefaultMaxDeliveries is the default max deliveries kept by the stores.
+const DefaultMaxDeliveries = 10000
+
+// DefaultRetention is the default time the stores keep a delivery.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// StoreConfig is the retention of the stores.
+type StoreConfig struct {
+	// MaxDeliveries is the max deliveries kept, the oldest are pruned on save, default: 10000.
+	MaxDeliveries int
+	// Retention is how long a delivery is kept after it was created, default: 7 days.
+	Retention time.Duration
+}
+
+func newStoreConfig(cfg ...*StoreConfig) *StoreConfig {
+	cfgX := &StoreConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		*cfgX = *cfg[0]
+	}
+
+	if cfgX.MaxDeliveries <= 0 {
+		cfgX.MaxDeliveries = DefaultMaxDeliveries
+	}
+	if cfgX.Retention <= 0 {
+		cfgX.Retention = DefaultRetention
+	}
+
+	return cfgX
+}
+
+type memoryStore struct {
+	sync.RWMutex
+	cfg        *StoreConfig
+	deliveries map[string]*Delivery
+}
+
+// NewMemory creates an in-process memory store.
+func NewMemory(cfg ...*StoreConfig) Store {
+	return &memoryStore{
+		cfg:        newStoreConfig(cfg...),
+		deliveries: map[string]*Delivery{},
+	}
+}
+
+func (s *memoryStore) Save(delivery *Delivery) error {
+	s.Lock()
+	defer s.Unlock()
+
+	copied := *delivery
+	s.deliveries[delivery.ID] = &copied
+
+	s.prune()
+	return nil
+}
+
+// prune removes the expired deliveries and the oldest ones over max.
+func (s *memoryStore) prune() {
+	expireAt := time.Now().Add(-s.cfg.Retention)
+	for id, delivery := range s.deliveries {
+		if delivery.CreatedAt.Before(expireAt) {
+			delete(s.deliveries, id)
+		}
+	}
+
+	if len(s.deliveries) <= s.cfg.MaxDeliveries {
+		return
+	}
+
+	deliveries := make([]*Delivery, 0, len(s.deliveries))
+	for _, delivery := range s.deliveries {
+		deliveries = append(deliveries, delivery)
+	}
+	sortDeliveries(deliveries)
+	for _, delivery := range deliveries[s.cfg.MaxDeliveries:] {
+		delete(s.deliveries, delivery.ID)
+	}
+}
+
+func (s *memoryStore) Get(id string) (*Delivery, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	copied := *delivery
+	return &copied, nil
+}
+
+func (s *memoryStore) List(status string) ([]*Delivery, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	deliveries := []*Delivery{}
+	for _, delivery := range s.deliveries {
+		if status == "" || delivery.Status == status {
+			copied := *delivery
+			deliveries = append(deliveries, &copied)
+		}
+	}
+
+	sortDeliveries(deliveries)
+	return deliveries, nil
+}
+
+// cacheKeys is the key of delivery ids index in cache store.
+const cacheKeys = "webhook:deliveries"
+
+// cacheIndex is an entry of the delivery ids index, oldest first.
+type cacheIndex struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type cacheStore struct {
+	sync.Mutex
+	cfg   *StoreConfig
+	cache cache.Cache
+}
+
+// NewCache creates a store backed by cache, such as redis, so deliveries survive restarts.
+func NewCache(c cache.Cache, cfg ...*StoreConfig) Store {
+	return &cacheStore{
+		cfg:   newStoreConfig(cfg...),
+		cache: c,
+	}
+}
+
+func (s *cacheStore) Save(delivery *Delivery) error {
+	s.Lock()
+	defer s.Unlock()
+
+	ttl := time.Until(delivery.CreatedAt.Add(s.cfg.Retention))
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := s.cache.Set("webhook:delivery:"+delivery.ID, delivery, ttl); err != nil {
+		return err
+	}
+
+	index := s.index()
+	for _, entry := range index {
+		if entry.ID == delivery.ID {
+			return nil
+		}
+	}
+
+	index = append(index, cacheIndex{ID: delivery.ID, CreatedAt: delivery.CreatedAt})
+
+	// prune the expired and the oldest over max, the index is bounded by max deliveries,
+	// so rewriting it on save stays cheap.
+	expireAt := time.Now().Add(-s.cfg.Retention)
+	start := 0
+	for start < len(index) && index[start].CreatedAt.Before(expireAt) {
+		start++
+	}
+	if len(index)-start > s.cfg.MaxDeliveries {
+		start = len(index) - s.cfg.MaxDeliveries
+	}
+	for _, entry := range index[:start] {
+		s.cache.Del("webhook:delivery:" + entry.ID)
+	}
+
+	index = index[start:]
+	return s.cache.Set(cacheKeys, &index)
+}
+
+func (s *cacheStore) Get(id string) (*Delivery, error) {
+	if !s.cache.Has("webhook:delivery:" + id) {
+		return nil, ErrNotFound
+	}
+
+	delivery := &Delivery{}
+	if err := s.cache.Get("webhook:delivery:"+id, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (s *cacheStore) List(status string) ([]*Delivery, error) {
+	deliveries := []*Delivery{}
+	for _, entry := range s.index() {
+		delivery, err := s.Get(entry.ID)
+		if err != nil {
+			continue
+		}
+
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sortDeliveries(deliveries)
+	return deliveries, nil
+}
+
+func (s *cacheStore) index() []cacheIndex {
+	index := []cacheIndex{}
+	if s.cache.Has(cacheKeys) {
+		s.cache.Get(cacheKeys, &index)
+	}
+
+	return index
+}
+
+func sortDeliveries(deliveries []*Delivery) {
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+}
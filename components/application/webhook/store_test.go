@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/cache"
+)
+
+func TestMemoryStoreRetention(t *testing.T) {
+	testStoreRetention(t, NewMemory(&StoreConfig{MaxDeliveries: 3, Retention: time.Hour}))
+}
+
+func TestCacheStoreRetention(t *testing.T) {
+	testStoreRetention(t, NewCache(cache.New(), &StoreConfig{MaxDeliveries: 3, Retention: time.Hour}))
+}
+
+func testStoreRetention(t *testing.T, store Store) {
+	now := time.Now()
+	if err := store.Save(&Delivery{ID: "expired", CreatedAt: now.Add(-2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := store.Save(&Delivery{ID: fmt.Sprintf("d%d", i), CreatedAt: now.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deliveries, err := store.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].ID != "d4" || deliveries[2].ID != "d2" {
+		t.Errorf("expected newest deliveries d4..d2, got %s..%s", deliveries[0].ID, deliveries[2].ID)
+	}
+
+	for _, id := range []string{"expired", "d0", "d1"} {
+		if _, err := store.Get(id); err != ErrNotFound {
+			t.Errorf("expected %s to be pruned, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/random"
+)
+
+// Delivery status
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Headers of the webhook request.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is sha256=hex(hmac_sha256(secret, timestamp + "." + body)).
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// ErrNotFound is returned when the endpoint or delivery is not found.
+var ErrNotFound = errors.New("not found")
+
+// Endpoint is the outbound webhook endpoint.
+type Endpoint struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"-"`
+	// Events are the subscribed events, empty or "*" means all.
+	Events []string `json:"events,omitempty"`
+}
+
+func (e *Endpoint) subscribes(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+
+	for _, one := range e.Events {
+		if one == "*" || one == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is a delivery of event to the endpoint.
+type Delivery struct {
+	ID             string          `json:"id"`
+	EndpointID     string          `json:"endpoint_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Sign returns the signature of the body with the secret and timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify verifies the signature of the webhook request body, used by receivers.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Webhooks is the outbound webhook manager.
+type Webhooks interface {
+	Register(endpoint *Endpoint) error
+	Unregister(id string) error
+	Endpoints() []*Endpoint
+	// Emit delivers the event to the subscribed endpoints asynchronously.
+	Emit(event string, payload any) error
+	// Deliveries returns the deliveries of the status, all if status is empty.
+	Deliveries(status string) ([]*Delivery, error)
+	// Redrive delivers the failed delivery again.
+	Redrive(id string) error
+	// Close stops the pending retries.
+	Close() error
+}
+
+// Config is the config of webhooks.
+type Config struct {
+	// Store persists the deliveries, default: memory.
+	Store Store
+	// MaxAttempts is the max attempts of a delivery, default: 5.
+	MaxAttempts int
+	// Backoff returns the delay before the attempt (starts from 2), default: exponential from 1s, max 1m.
+	Backoff func(attempt int) time.Duration
+	// Timeout is the request timeout, default: 10s.
+	Timeout time.Duration
+	// OnFailed is called when the delivery fails after max attempts.
+	OnFailed func(delivery *Delivery)
+}
+
+type webhooks struct {
+	cfg    *Config
+	client *http.Client
+	//
+	sync.RWMutex
+	endpoints map[string]*Endpoint
+	//
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a webhook manager.
+func New(cfg *Config) Webhooks {
+	if cfg.Store == nil {
+		cfg.Store = NewMemory()
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = func(attempt int) time.Duration {
+			delay := time.Second << (attempt - 2)
+			if delay > time.Minute || delay <= 0 {
+				delay = time.Minute
+			}
+
+			return delay
+		}
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhooks{
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		endpoints: map[string]*Endpoint{},
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+func (w *webhooks) Register(endpoint *Endpoint) error {
+	if endpoint.URL == "" {
+		return errors.New("webhook endpoint url is required")
+	}
+	if endpoint.ID == "" {
+		endpoint.ID = random.String(16)
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	w.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (w *webhooks) Unregister(id string) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if _, ok := w.endpoints[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(w.endpoints, id)
+	return nil
+}
+
+func (w *webhooks) Endpoints() []*Endpoint {
+	w.RLock()
+	defer w.RUnlock()
+
+	endpoints := make([]*Endpoint, 0, len(w.endpoints))
+	for _, endpoint := range w.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+func (w *webhooks) endpoint(id string) (*Endpoint, bool) {
+	w.RLock()
+	defer w.RUnlock()
+
+	endpoint, ok := w.endpoints[id]
+	return endpoint, ok
+}
+
+func (w *webhooks) Emit(event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %s", err)
+	}
+
+	for _, endpoint := range w.Endpoints() {
+		if !endpoint.subscribes(event) {
+			continue
+		}
+
+		now := time.Now()
+		delivery := &Delivery{
+			ID:         random.String(16),
+			EndpointID: endpoint.ID,
+			Event:      event,
+			Payload:    data,
+			Status:     StatusPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := w.cfg.Store.Save(delivery); err != nil {
+			return err
+		}
+
+		w.dispatch(endpoint, delivery)
+	}
+
+	return nil
+}
+
+func (w *webhooks) Deliveries(status string) ([]*Delivery, error) {
+	return w.cfg.Store.List(status)
+}
+
+func (w *webhooks) Redrive(id string) error {
+	delivery, err := w.cfg.Store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if delivery.Status != StatusFailed {
+		return fmt.Errorf("delivery(%s) is %s, only failed delivery can be redriven", id, delivery.Status)
+	}
+
+	endpoint, ok := w.endpoint(delivery.EndpointID)
+	if !ok {
+		return fmt.Errorf("endpoint(%s) of delivery(%s): %w", delivery.EndpointID, id, ErrNotFound)
+	}
+
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.UpdatedAt = time.Now()
+	if err := w.cfg.Store.Save(delivery); err != nil {
+		return err
+	}
+
+	w.dispatch(endpoint, delivery)
+	return nil
+}
+
+func (w *webhooks) Close() error {
+	w.cancel()
+	w.wg.Wait()
+	return nil
+}
+
+// dispatch delivers with retries in background.
+func (w *webhooks) dispatch(endpoint *Endpoint, delivery *Delivery) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		for delivery.Attempts < w.cfg.MaxAttempts {
+			if delivery.Attempts > 0 {
+				select {
+				case <-time.After(w.cfg.Backoff(delivery.Attempts + 1)):
+				case <-w.ctx.Done():
+					// kept pending, can be redriven after restart
+					return
+				}
+			}
+
+			delivery.Attempts++
+			status, err := w.send(endpoint, delivery)
+			delivery.ResponseStatus = status
+			delivery.UpdatedAt = time.Now()
+			if err == nil {
+				delivery.Status = StatusSucceeded
+				delivery.LastError = ""
+				w.cfg.Store.Save(delivery)
+				return
+			}
+
+			delivery.LastError = err.Error()
+			w.cfg.Store.Save(delivery)
+		}
+
+		delivery.Status = StatusFailed
+		w.cfg.Store.Save(delivery)
+		if w.cfg.OnFailed != nil {
+			w.cfg.OnFailed(delivery)
+		}
+	}()
+}
+
+func (w *webhooks) send(endpoint *Endpoint, delivery *Delivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
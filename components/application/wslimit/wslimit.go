@@ -0,0 +1,156 @@
+// Package wslimit limits the inbound messages of websocket connections (messages/sec and bytes/sec),
+// protecting chat and rpc servers from abusive clients.
+package wslimit
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrRateLimited is returned by Conn.ReadMessage after the connection is closed with 1008 (policy violation).
+var ErrRateLimited = errors.New("websocket rate limited")
+
+// Action is the action on the limited message.
+type Action int
+
+const (
+	// Drop discards the message.
+	Drop Action = iota
+	// Warn passes the message, OnLimited is still called.
+	Warn
+	// Close closes the connection with 1008 (policy violation).
+	Close
+)
+
+// Config is the config of the limiter, shared by the connections of a route, each connection has its own buckets.
+type Config struct {
+	// MessagesPerSecond is the max inbound messages per second, 0 means no limit.
+	MessagesPerSecond float64
+	// BytesPerSecond is the max inbound bytes per second, 0 means no limit.
+	BytesPerSecond float64
+	// Burst is the seconds of the rate allowed in burst, default 1.
+	Burst float64
+	// Action is the action on the limited message, default Drop.
+	Action Action
+	// OnLimited is called on the limited message, such as logging and metrics.
+	OnLimited func(size int, action Action)
+}
+
+// Limiter is the limiter of a connection.
+type Limiter struct {
+	cfg *Config
+
+	mu       sync.Mutex
+	messages *bucket
+	bytes    *bucket
+}
+
+// New creates the limiter of a connection.
+func New(cfg *Config) *Limiter {
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	l := &Limiter{cfg: cfg}
+	if cfg.MessagesPerSecond > 0 {
+		l.messages = newBucket(cfg.MessagesPerSecond, burst)
+	}
+	if cfg.BytesPerSecond > 0 {
+		l.bytes = newBucket(cfg.BytesPerSecond, burst)
+	}
+
+	return l
+}
+
+// Allow reports whether the message of size is allowed.
+func (l *Limiter) Allow(size int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.messages != nil && !l.messages.allow(now, 1) {
+		return false
+	}
+
+	// the message token is not refunded, the abusive client is limited by either
+	if l.bytes != nil && !l.bytes.allow(now, float64(size)) {
+		return false
+	}
+
+	return true
+}
+
+// Conn is the websocket connection with the inbound limiter.
+type Conn struct {
+	*websocket.Conn
+	limiter *Limiter
+}
+
+// Wrap wraps the connection with the limiter of cfg, cfg nil means no limit.
+func Wrap(conn *websocket.Conn, cfg *Config) *Conn {
+	c := &Conn{Conn: conn}
+	if cfg != nil {
+		c.limiter = New(cfg)
+	}
+
+	return c
+}
+
+// ReadMessage reads the next allowed message, the limited messages are handled by the action.
+func (c *Conn) ReadMessage() (messageType int, p []byte, err error) {
+	for {
+		messageType, p, err = c.Conn.ReadMessage()
+		if err != nil || c.limiter == nil || c.limiter.Allow(len(p)) {
+			return
+		}
+
+		cfg := c.limiter.cfg
+		if cfg.OnLimited != nil {
+			cfg.OnLimited(len(p), cfg.Action)
+		}
+
+		switch cfg.Action {
+		case Warn:
+			return
+		case Close:
+			c.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limited"), time.Now().Add(time.Second))
+			c.Conn.Close()
+			return 0, nil, ErrRateLimited
+		}
+	}
+}
+
+type bucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(rate, burst float64) *bucket {
+	return &bucket{
+		rate:     rate,
+		capacity: rate * burst,
+		tokens:   rate * burst,
+		last:     time.Now(),
+	}
+}
+
+func (b *bucket) allow(now time.Time, n float64) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < n {
+		return false
+	}
+
+	b.tokens -= n
+	return true
+}
@@ -0,0 +1,263 @@
+// Package wsqueue is the buffered write queue of websocket connections,
+// with overflow policies and write timeouts, so slow clients can't wedge broadcasts.
+//
+// Example:
+//
+//	q := wsqueue.New(func(m *wsqueue.Message) error {
+//		return conn.WriteTextMessage(m.Data)
+//	}, conn.Close, &wsqueue.Config{
+//		Size:         256,
+//		Policy:       wsqueue.CloseSlowConsumer,
+//		WriteTimeout: 5 * time.Second,
+//	})
+//
+//	// broadcasts never block on the slow client
+//	q.SendText(message)
+package wsqueue
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Message types, same as RFC 6455 (and gorilla/websocket).
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// ErrClosed is returned by Send after the queue is closed.
+var ErrClosed = errors.New("websocket queue is closed")
+
+// ErrWriteTimeout is the close reason of the write exceeding Config.WriteTimeout.
+var ErrWriteTimeout = errors.New("websocket write timeout")
+
+// ErrSlowConsumer is the close reason of the full queue with the CloseSlowConsumer policy.
+var ErrSlowConsumer = errors.New("websocket slow consumer")
+
+// Policy is the overflow policy of the full queue.
+type Policy int
+
+const (
+	// DropOldest drops the oldest queued message for the new one.
+	DropOldest Policy = iota
+	// DropNewest drops the new message.
+	DropNewest
+	// CloseSlowConsumer closes the connection.
+	CloseSlowConsumer
+)
+
+// Message is the queued message.
+type Message struct {
+	Type int
+	Data []byte
+}
+
+// Config is the config of the queue.
+type Config struct {
+	// Size is the max queued messages, default 256.
+	Size int
+	// Policy is the overflow policy, default DropOldest.
+	Policy Policy
+	// WriteTimeout closes the connection if a write takes longer, 0 means no timeout.
+	WriteTimeout time.Duration
+	// OnClose is called once when the queue is closed, with the reason (nil if closed by Close).
+	OnClose func(err error)
+}
+
+// Stats is the metrics of the queue.
+type Stats struct {
+	// Depth is the current queued messages.
+	Depth int
+	// Sent is the total written messages.
+	Sent int64
+	// Dropped is the total dropped messages by the overflow policy.
+	Dropped int64
+}
+
+// Queue is the buffered write queue of a connection, writes run in its own goroutine.
+type Queue struct {
+	cfg   *Config
+	write func(m *Message) error
+	close func() error
+
+	mu      sync.Mutex
+	items   []*Message
+	notify  chan struct{}
+	done    chan struct{}
+	closed  bool
+	sent    atomic.Int64
+	dropped atomic.Int64
+}
+
+// New creates the queue with the write and close functions of the connection.
+func New(write func(m *Message) error, close func() error, cfg ...*Config) *Queue {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Size <= 0 {
+		cfgX.Size = 256
+	}
+
+	q := &Queue{
+		cfg:    cfgX,
+		write:  write,
+		close:  close,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	go q.loop()
+
+	return q
+}
+
+// SendText queues the text message.
+func (q *Queue) SendText(data []byte) error {
+	return q.Send(&Message{Type: TextMessage, Data: data})
+}
+
+// SendBinary queues the binary message.
+func (q *Queue) SendBinary(data []byte) error {
+	return q.Send(&Message{Type: BinaryMessage, Data: data})
+}
+
+// Send queues the message without blocking, the full queue is handled by the overflow policy.
+func (q *Queue) Send(m *Message) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrClosed
+	}
+
+	if len(q.items) >= q.cfg.Size {
+		switch q.cfg.Policy {
+		case DropNewest:
+			q.mu.Unlock()
+			q.dropped.Add(1)
+			return nil
+		case CloseSlowConsumer:
+			q.mu.Unlock()
+			q.shutdown(ErrSlowConsumer)
+			return ErrSlowConsumer
+		default:
+			q.items[0] = nil
+			q.items = q.items[1:]
+			q.dropped.Add(1)
+		}
+	}
+
+	q.items = append(q.items, m)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Stats returns the metrics of the queue.
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	depth := len(q.items)
+	q.mu.Unlock()
+
+	return Stats{
+		Depth:   depth,
+		Sent:    q.sent.Load(),
+		Dropped: q.dropped.Load(),
+	}
+}
+
+// Done is closed when the queue is closed.
+func (q *Queue) Done() <-chan struct{} {
+	return q.done
+}
+
+// Close closes the queue and the connection, the queued messages are discarded.
+func (q *Queue) Close() error {
+	q.shutdown(nil)
+	return nil
+}
+
+func (q *Queue) shutdown(reason error) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.items = nil
+	q.mu.Unlock()
+
+	close(q.done)
+	if q.close != nil {
+		q.close()
+	}
+
+	if q.cfg.OnClose != nil {
+		q.cfg.OnClose(reason)
+	}
+}
+
+func (q *Queue) pop() *Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return nil
+	}
+
+	m := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	return m
+}
+
+func (q *Queue) loop() {
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-q.notify:
+		}
+
+		for m := q.pop(); m != nil; m = q.pop() {
+			if err := q.writeWithTimeout(m); err != nil {
+				q.shutdown(err)
+				return
+			}
+
+			q.sent.Add(1)
+		}
+	}
+}
+
+func (q *Queue) writeWithTimeout(m *Message) error {
+	if q.cfg.WriteTimeout <= 0 {
+		return q.write(m)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- q.write(m)
+	}()
+
+	timer := time.NewTimer(q.cfg.WriteTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		// the blocked write is released by closing the connection
+		return ErrWriteTimeout
+	case <-q.done:
+		return ErrClosed
+	}
+}
@@ -0,0 +1,896 @@
+package binding
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrRequired is returned when the field with the required option is missing.
+var ErrRequired = errors.New("is required")
+
+// InTag is the combined tag declaring the source of the field, such as `in:"path=id"`,
+// which is used when the field has no tag of the source.
+const InTag = "in"
+
+// inSources maps the source names of InTag to the tags.
+var inSources = map[string]string{
+	"path":   "param",
+	"param":  "param",
+	"query":  "query",
+	"header": "header",
+	"form":   "form",
+}
+
+// binders is the registered parsers of custom types, reflect.Type => func(raw string) (any, error).
+var binders sync.Map
+
+// Register registers the parser of the custom type, such as uuid.UUID, decimal.Decimal and enums,
+// which is used by all sources, and by Check for the values decoded by others (such as json).
+func Register(typ reflect.Type, fn func(raw string) (any, error)) {
+	binders.Store(typ, fn)
+}
+
+func lookupBinder(typ reflect.Type) (func(raw string) (any, error), bool) {
+	fn, ok := binders.Load(typ)
+	if !ok {
+		return nil, false
+	}
+
+	return fn.(func(raw string) (any, error)), true
+}
+
+// Source is the raw values to bind, such as the form, query and header.
+type Source struct {
+	Values map[string][]string
+	// Files is the multipart files, bound into *multipart.FileHeader and []*multipart.FileHeader.
+	Files map[string][]*multipart.FileHeader
+	// Key normalizes the key before lookup, such as http.CanonicalHeaderKey.
+	Key func(key string) string
+}
+
+// Decode binds the source into the struct fields of obj tagged with tag.
+//
+// Tag syntax: `form:"name[,option]...[,default=<value>]"`, default must be the last option:
+//
+//   - required: the value must be present and not empty
+//   - omitempty: the missing value is not checked by the constraints below
+//   - min=<n>, max=<n>: the range of numbers, or the length range of strings
+//   - enum=<a|b|c>: the allowed values
+//   - regexp=/<pattern>/: the pattern the value must match
+//   - separator=<sep>: splits each value of slices, such as ids=1,2 (seperator is accepted as well)
+//   - env=<NAME>: the environment variable used if the value is missing
+//   - layout=<time layout>: the layout of time.Time, default time.RFC3339
+//
+// The missing value is an error if it has constraints (min, max, enum or regexp), unless omitempty or default is set.
+// Unknown options are errors.
+//
+//   - slices bind the repeated keys (tags=a&tags=b, tags[]=a)
+//   - nested structs bind the prefixed keys (address.city), embedded structs are flattened
+//   - maps bind the keys like meta[key] or meta.key
+//
+// The fields without the tag can declare the source with InTag, such as `in:"query=page,default=1"`,
+// the fields without any of them are bound by the field name.
+func Decode(tag string, src *Source, obj any) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("binding: expect a non-nil pointer, but got %T", obj)
+	}
+
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("binding: expect a pointer to struct, but got %T", obj)
+	}
+
+	d := &decoder{tag: tag, src: src}
+	return d.decodeStruct(v, "")
+}
+
+// Options is the options of the field tag.
+type Options struct {
+	Name       string
+	Required   bool
+	OmitEmpty  bool
+	Layout     string
+	Default    string
+	HasDefault bool
+	// Min and Max are the range of numbers, or the length range of strings, nil if not set.
+	Min *float64
+	Max *float64
+	// Enum is the allowed values.
+	Enum []string
+	// RegExp is the pattern the value must match.
+	RegExp *regexp.Regexp
+	// Separator splits each value of slices.
+	Separator string
+	// Env is the environment variable used if the value is missing.
+	Env string
+}
+
+// parsedTags caches the options of tag values, tag value => *Options.
+var parsedTags sync.Map
+
+// ParseTag parses the field tag value, it returns the error of unknown or invalid options.
+func ParseTag(value string) (*Options, error) {
+	if opts, ok := parsedTags.Load(value); ok {
+		return opts.(*Options), nil
+	}
+
+	parts := strings.Split(value, ",")
+	opts := &Options{Name: strings.TrimSpace(parts[0])}
+
+	for i := 1; i < len(parts); i++ {
+		option := strings.TrimSpace(parts[i])
+		key, arg, hasArg := strings.Cut(option, "=")
+
+		switch {
+		case option == "":
+		case option == "required":
+			opts.Required = true
+		case option == "omitempty":
+			opts.OmitEmpty = true
+		case key == "default" && hasArg:
+			// the rest is the default value, which may contain commas
+			opts.Default = strings.TrimPrefix(strings.Join(parts[i:], ","), "default=")
+			opts.HasDefault = true
+			parsedTags.Store(value, opts)
+			return opts, nil
+		case key == "layout" && hasArg:
+			opts.Layout = arg
+		case (key == "min" || key == "max") && hasArg:
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid option %q: %v", option, err)
+			}
+
+			if key == "min" {
+				opts.Min = &n
+			} else {
+				opts.Max = &n
+			}
+		case key == "enum" && hasArg:
+			opts.Enum = strings.Split(arg, "|")
+		case key == "regexp" && hasArg:
+			pattern := arg
+			if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+				pattern = pattern[1 : len(pattern)-1]
+			}
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid option %q: %v", option, err)
+			}
+			opts.RegExp = re
+		case (key == "separator" || key == "seperator") && hasArg && arg != "":
+			opts.Separator = arg
+		case key == "env" && hasArg && arg != "":
+			opts.Env = arg
+		default:
+			return nil, fmt.Errorf("unknown option %q", option)
+		}
+	}
+
+	parsedTags.Store(value, opts)
+	return opts, nil
+}
+
+// constrained reports whether the value is checked by min, max, enum or regexp.
+func (o *Options) constrained() bool {
+	return o.Min != nil || o.Max != nil || len(o.Enum) > 0 || o.RegExp != nil
+}
+
+// constraint describes the first constraint of the options, such as in range(1, 100).
+func (o *Options) constraint() string {
+	switch {
+	case len(o.Enum) > 0:
+		return fmt.Sprintf("in enum(%s)", strings.Join(o.Enum, "|"))
+	case o.Min != nil || o.Max != nil:
+		return o.describeRange()
+	case o.RegExp != nil:
+		return fmt.Sprintf("matched with regexp(%s)", o.RegExp)
+	}
+
+	return ""
+}
+
+func (o *Options) describeRange() string {
+	switch {
+	case o.Min != nil && o.Max != nil:
+		return fmt.Sprintf("in range(%s, %s)", formatFloat(*o.Min), formatFloat(*o.Max))
+	case o.Min != nil:
+		return "at least " + formatFloat(*o.Min)
+	default:
+		return "at most " + formatFloat(*o.Max)
+	}
+}
+
+// validate checks the raw value of typ with the constraints.
+func (o *Options) validate(key, raw string, typ reflect.Type) error {
+	if len(o.Enum) > 0 {
+		found := false
+		for _, value := range o.Enum {
+			if value == raw {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%s must be %s, but %s", key, o.constraint(), raw)
+		}
+	}
+
+	if o.Min != nil || o.Max != nil {
+		var n float64
+		name := key
+
+		switch indirectType(typ).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("%s: invalid number %q", key, raw)
+			}
+			n = f
+		case reflect.String:
+			n = float64(utf8.RuneCountInString(raw))
+			name = "length of " + key
+		default:
+			return fmt.Errorf("%s: min and max are not supported by %s", key, typ)
+		}
+
+		if (o.Min != nil && n < *o.Min) || (o.Max != nil && n > *o.Max) {
+			return fmt.Errorf("%s must be %s, but %s", name, o.describeRange(), formatFloat(n))
+		}
+	}
+
+	if o.RegExp != nil && !o.RegExp.MatchString(raw) {
+		return fmt.Errorf("%s is invalid with regexp(%s)", key, o.RegExp)
+	}
+
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type decoder struct {
+	tag string
+	src *Source
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+	unmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func (d *decoder) decodeStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		tagValue, ok := d.lookupTag(field)
+		if tagValue == "-" {
+			continue
+		}
+
+		if !ok {
+			// embedded structs are flattened
+			if field.Anonymous {
+				if indirectType(field.Type).Kind() == reflect.Struct && !isValueType(indirectType(field.Type)) {
+					if err := d.decodeStruct(allocate(fv), prefix); err != nil {
+						return err
+					}
+				}
+
+				continue
+			}
+
+			// declared for another source
+			if _, ok := field.Tag.Lookup(InTag); ok {
+				continue
+			}
+		}
+
+		parsed, err := ParseTag(tagValue)
+		if err != nil {
+			return fmt.Errorf("binding: tag of field %s: %v", field.Name, err)
+		}
+
+		// the parsed options are shared
+		opts := *parsed
+		if opts.Name == "" {
+			opts.Name = field.Name
+		}
+
+		if err := d.decodeField(fv, prefix+opts.Name, &opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupTag returns the tag of the source, or the InTag targeting the source.
+func (d *decoder) lookupTag(field reflect.StructField) (string, bool) {
+	if value, ok := field.Tag.Lookup(d.tag); ok {
+		return value, true
+	}
+
+	if value, ok := field.Tag.Lookup(InTag); ok {
+		source, rest, _ := strings.Cut(value, "=")
+		if inSources[strings.TrimSpace(source)] == d.tag {
+			return rest, true
+		}
+	}
+
+	return "", false
+}
+
+func (d *decoder) decodeField(fv reflect.Value, key string, opts *Options) error {
+	typ := indirectType(fv.Type())
+	if typ.Kind() == reflect.Struct && !isValueType(typ) && !d.hasPrefix(key+".") {
+		return d.missing(key, opts)
+	}
+
+	switch {
+	case fv.Type() == fileHeaderType:
+		files := d.files(key)
+		if len(files) == 0 {
+			return d.missing(key, opts)
+		}
+
+		fv.Set(reflect.ValueOf(files[0]))
+		return nil
+	case fv.Kind() == reflect.Slice && fv.Type().Elem() == fileHeaderType:
+		files := d.files(key)
+		if len(files) == 0 {
+			return d.missing(key, opts)
+		}
+
+		fv.Set(reflect.ValueOf(files))
+		return nil
+	case typ.Kind() == reflect.Struct && !isValueType(typ):
+		return d.decodeStruct(allocate(fv), key+".")
+	case fv.Kind() == reflect.Map:
+		return d.decodeMap(fv, key, opts)
+	case fv.Kind() == reflect.Slice && !isValueType(fv.Type()):
+		values := d.values(key)
+		if len(values) == 0 {
+			values = d.values(key + "[]")
+		}
+		if len(values) == 0 {
+			if env := opts.env(); env != "" {
+				values = []string{env}
+			} else if opts.HasDefault && fv.IsZero() {
+				values = []string{opts.Default}
+			} else {
+				return d.missing(key, opts)
+			}
+		}
+
+		if opts.Separator != "" {
+			var split []string
+			for _, value := range values {
+				split = append(split, strings.Split(value, opts.Separator)...)
+			}
+			values = split
+		}
+
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := SetValue(slice.Index(i), value, opts); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+
+			if err := opts.validate(key, value, fv.Type().Elem()); err != nil {
+				return err
+			}
+		}
+
+		fv.Set(slice)
+		return nil
+	}
+
+	values := d.values(key)
+	if len(values) == 0 || values[0] == "" {
+		if env := opts.env(); env != "" {
+			values = []string{env}
+		} else if !opts.HasDefault || !fv.IsZero() {
+			// the default does not override the value bound from other sources
+			return d.missing(key, opts)
+		} else {
+			values = []string{opts.Default}
+		}
+	}
+
+	if err := SetValue(fv, values[0], opts); err != nil {
+		return fmt.Errorf("%s: %v", key, err)
+	}
+
+	return opts.validate(key, values[0], fv.Type())
+}
+
+// env returns the value of the environment variable of the options, empty if not set.
+func (o *Options) env() string {
+	if o.Env == "" {
+		return ""
+	}
+
+	return os.Getenv(o.Env)
+}
+
+// decodeMap binds the keys like meta[key] and meta.key.
+func (d *decoder) decodeMap(fv reflect.Value, key string, opts *Options) error {
+	if fv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	m := reflect.MakeMap(fv.Type())
+	for k, values := range d.src.Values {
+		var name string
+		switch {
+		case strings.HasPrefix(k, key+"[") && strings.HasSuffix(k, "]"):
+			name = k[len(key)+1 : len(k)-1]
+		case strings.HasPrefix(k, key+"."):
+			name = k[len(key)+1:]
+		default:
+			continue
+		}
+		if name == "" || len(values) == 0 {
+			continue
+		}
+
+		elem := reflect.New(fv.Type().Elem()).Elem()
+		if elem.Kind() == reflect.Slice && !isValueType(elem.Type()) {
+			elem = reflect.MakeSlice(elem.Type(), len(values), len(values))
+			for i, value := range values {
+				if err := SetValue(elem.Index(i), value, opts); err != nil {
+					return fmt.Errorf("%s: %v", k, err)
+				}
+			}
+		} else if err := SetValue(elem, values[0], opts); err != nil {
+			return fmt.Errorf("%s: %v", k, err)
+		}
+
+		m.SetMapIndex(reflect.ValueOf(name).Convert(fv.Type().Key()), elem)
+	}
+
+	if m.Len() == 0 {
+		return d.missing(key, opts)
+	}
+
+	fv.Set(m)
+	return nil
+}
+
+func (d *decoder) missing(key string, opts *Options) error {
+	if opts.Required {
+		return fmt.Errorf("%s %w", key, ErrRequired)
+	}
+
+	if opts.constrained() && !opts.OmitEmpty {
+		return fmt.Errorf("%s must be %s, but empty", key, opts.constraint())
+	}
+
+	return nil
+}
+
+func (d *decoder) normalize(key string) string {
+	if d.src.Key != nil {
+		return d.src.Key(key)
+	}
+
+	return key
+}
+
+func (d *decoder) values(key string) []string {
+	return d.src.Values[d.normalize(key)]
+}
+
+func (d *decoder) files(key string) []*multipart.FileHeader {
+	if d.src.Files == nil {
+		return nil
+	}
+
+	return d.src.Files[key]
+}
+
+func (d *decoder) hasPrefix(prefix string) bool {
+	prefix = d.normalize(prefix)
+	for k := range d.src.Values {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	for k := range d.src.Files {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetValue parses the raw value into v, which supports the basic types, time.Time, time.Duration,
+// encoding.TextUnmarshaler and the pointers of them.
+func SetValue(v reflect.Value, raw string, opts *Options) error {
+	if fn, ok := lookupBinder(v.Type()); ok {
+		return setBound(v, fn, raw)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		if err := SetValue(ptr.Elem(), raw, opts); err != nil {
+			return err
+		}
+
+		v.Set(ptr)
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		layout := time.RFC3339
+		if opts != nil && opts.Layout != "" {
+			layout = opts.Layout
+		}
+
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(duration))
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerTyp) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(raw))
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func setBound(v reflect.Value, fn func(raw string) (any, error), raw string) error {
+	value, err := fn(raw)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || !rv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("binder of %s returns %T", v.Type(), value)
+	}
+
+	v.Set(rv)
+	return nil
+}
+
+// Check re-parses the non-zero fields of the registered types in obj with their binders,
+// so the values decoded by others (such as json) are validated and normalized as the same.
+// The values are formatted with fmt.Stringer or their string kind, others are skipped.
+//
+// With tag, the options of the tag (required, min, max, enum and regexp) are checked as Decode does,
+// such as `json:"limit,min=1,max=100"`, the options of the codec itself (omitempty, string, inline...) are allowed.
+func Check(obj any, tag ...string) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+
+	c := &checker{}
+	if len(tag) > 0 {
+		c.tag = tag[0]
+	}
+
+	return c.check(v.Elem(), "")
+}
+
+// codecOptions is the options of the codec tags (json, yaml, msgpack), which are not the binding options.
+var codecOptions = map[string]bool{
+	"string":   true,
+	"omitzero": true,
+	"inline":   true,
+	"noinline": true,
+	"flow":     true,
+	"as_array": true,
+}
+
+type checker struct {
+	tag string
+}
+
+func (c *checker) check(v reflect.Value, path string) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if fn, ok := lookupBinder(v.Type()); ok {
+		if v.IsZero() || !v.CanSet() {
+			return nil
+		}
+
+		raw, ok := stringOf(v)
+		if !ok {
+			return nil
+		}
+
+		if err := setBound(v, fn, raw); err != nil {
+			return fmt.Errorf("%s: %v", strings.TrimPrefix(path, "."), err)
+		}
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return c.check(v.Elem(), path)
+	case reflect.Struct:
+		if isValueType(v.Type()) {
+			return nil
+		}
+
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if c.tag != "" {
+				opts, skip, err := c.options(field)
+				if err != nil {
+					return fmt.Errorf("binding: tag of field %s: %v", field.Name, err)
+				}
+				if skip {
+					continue
+				}
+
+				if opts.Name != "" {
+					name = opts.Name
+				}
+
+				if err := c.checkOptions(v.Field(i), strings.TrimPrefix(path+"."+name, "."), opts); err != nil {
+					return err
+				}
+			}
+
+			if err := c.check(v.Field(i), path+"."+name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := c.check(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// map values are not settable, the elements are copied back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := c.check(elem, fmt.Sprintf("%s[%v]", path, iter.Key())); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+
+	return nil
+}
+
+// options parses the binding options of the field tag, without the codec options.
+func (c *checker) options(field reflect.StructField) (opts *Options, skip bool, err error) {
+	value, ok := field.Tag.Lookup(c.tag)
+	if !ok {
+		return &Options{}, false, nil
+	}
+
+	if value == "-" {
+		return nil, true, nil
+	}
+
+	parts := strings.Split(value, ",")
+	kept := parts[:1]
+	for i, part := range parts[1:] {
+		// the default consumes the rest, which may contain the commas
+		if strings.HasPrefix(strings.TrimSpace(part), "default=") {
+			kept = append(kept, parts[i+1:]...)
+			break
+		}
+
+		if !codecOptions[strings.TrimSpace(part)] {
+			kept = append(kept, part)
+		}
+	}
+
+	opts, err = ParseTag(strings.Join(kept, ","))
+	return opts, false, err
+}
+
+// checkOptions checks the value decoded by the codec with the constraints of the options.
+func (c *checker) checkOptions(v reflect.Value, key string, opts *Options) error {
+	if !opts.Required && !opts.constrained() {
+		return nil
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if (v.Kind() == reflect.Ptr && v.IsNil()) || v.IsZero() {
+		if opts.Required {
+			return fmt.Errorf("%s %w", key, ErrRequired)
+		}
+
+		// the zero values are not distinguished from the missing ones, such as 0
+		return nil
+	}
+
+	values := []reflect.Value{v}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && !isValueType(v.Type()) {
+		values = values[:0]
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i))
+		}
+	}
+
+	for _, value := range values {
+		raw, ok := formatValue(value)
+		if !ok {
+			continue
+		}
+
+		if err := opts.validate(key, raw, value.Type()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatValue formats the basic value for the validation, false for others.
+func formatValue(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+
+	return "", false
+}
+
+func stringOf(v reflect.Value) (string, bool) {
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String(), true
+	}
+
+	if v.Kind() == reflect.String {
+		return v.String(), true
+	}
+
+	return "", false
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "yes", "y":
+		return true, nil
+	case "off", "no", "n":
+		return false, nil
+	}
+
+	return strconv.ParseBool(s)
+}
+
+// isValueType reports the types bound from a single value, instead of fields or elements.
+func isValueType(t reflect.Type) bool {
+	if t == timeType || t == fileHeaderType.Elem() {
+		return true
+	}
+
+	if _, ok := lookupBinder(t); ok {
+		return true
+	}
+
+	return reflect.PointerTo(t).Implements(unmarshalerTyp)
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+
+	return t
+}
+
+// allocate returns the settable struct of the field, allocating the pointer if nil.
+func allocate(fv reflect.Value) reflect.Value {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+
+		return fv.Elem()
+	}
+
+	return fv
+}
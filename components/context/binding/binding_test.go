@@ -0,0 +1,525 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `form:"city,required"`
+	Zip  string `form:"zip"`
+}
+
+type Base struct {
+	ID int `form:"id"`
+}
+
+type signupForm struct {
+	Base
+	Name     string            `form:"name,required"`
+	Age      int               `form:"age,default=18"`
+	Tags     []string          `form:"tags"`
+	Scores   []int             `form:"scores"`
+	Address  address           `form:"address"`
+	Billing  *address          `form:"billing"`
+	Meta     map[string]string `form:"meta"`
+	Birthday time.Time         `form:"birthday,layout=2006-01-02"`
+	Joined   time.Time         `form:"joined"`
+	Timeout  time.Duration     `form:"timeout"`
+	Active   bool              `form:"active"`
+	Nickname *string           `form:"nickname"`
+	Ignored  string            `form:"-"`
+	Avatar   *multipart.FileHeader
+}
+
+func TestDecodeForm(t *testing.T) {
+	src := &Source{Values: map[string][]string{
+		"id":           {"7"},
+		"name":         {"zero"},
+		"tags[]":       {"a", "b"},
+		"scores":       {"1", "2"},
+		"address.city": {"Hangzhou"},
+		"meta[plan]":   {"pro"},
+		"meta.region":  {"cn"},
+		"birthday":     {"2000-01-02"},
+		"joined":       {"2024-06-01T08:00:00Z"},
+		"timeout":      {"1m30s"},
+		"active":       {"on"},
+		"nickname":     {"z"},
+		"-":            {"ignored"},
+		"Ignored":      {"ignored"},
+	}}
+
+	form := &signupForm{}
+	if err := Decode("form", src, form); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := &signupForm{
+		Base:     Base{ID: 7},
+		Name:     "zero",
+		Age:      18,
+		Tags:     []string{"a", "b"},
+		Scores:   []int{1, 2},
+		Address:  address{City: "Hangzhou"},
+		Meta:     map[string]string{"plan": "pro", "region": "cn"},
+		Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		Joined:   time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+		Timeout:  90 * time.Second,
+		Active:   true,
+	}
+	nickname := "z"
+	expected.Nickname = &nickname
+	if !reflect.DeepEqual(form, expected) {
+		t.Errorf("expected %+v, got %+v", expected, form)
+	}
+}
+
+func TestDecodeFormRejects(t *testing.T) {
+	cases := []struct {
+		name     string
+		values   map[string][]string
+		required bool
+	}{
+		{"missing required", map[string][]string{"address.city": {"Hangzhou"}}, true},
+		{"empty required", map[string][]string{"name": {""}, "address.city": {"Hangzhou"}}, true},
+		{"missing required of nested struct", map[string][]string{"name": {"zero"}, "address.zip": {"310000"}}, true},
+		{"missing required of nested pointer", map[string][]string{"name": {"zero"}, "address.city": {"Hangzhou"}, "billing.zip": {"310000"}}, true},
+		{"invalid int", map[string][]string{"name": {"zero"}, "age": {"eighteen"}}, false},
+		{"int overflow", map[string][]string{"name": {"zero"}, "id": {"99999999999999999999"}}, false},
+		{"invalid element", map[string][]string{"name": {"zero"}, "scores": {"1", "two"}}, false},
+		{"invalid time layout", map[string][]string{"name": {"zero"}, "birthday": {"02/01/2000"}}, false},
+		{"invalid duration", map[string][]string{"name": {"zero"}, "timeout": {"soon"}}, false},
+		{"invalid bool", map[string][]string{"name": {"zero"}, "active": {"maybe"}}, false},
+	}
+
+	for _, c := range cases {
+		err := Decode("form", &Source{Values: c.values}, &signupForm{})
+		if err == nil {
+			t.Errorf("%s: expected error", c.name)
+			continue
+		}
+
+		if errors.Is(err, ErrRequired) != c.required {
+			t.Errorf("%s: expected required error %v, got %v", c.name, c.required, err)
+		}
+	}
+}
+
+func TestDecodeRejectsNonStructPointer(t *testing.T) {
+	var form signupForm
+	var n int
+	for _, obj := range []any{form, &n, (*signupForm)(nil), nil} {
+		if err := Decode("form", &Source{}, obj); err == nil {
+			t.Errorf("%T: expected error", obj)
+		}
+	}
+}
+
+func TestDecodeDefaultKeepsBoundValue(t *testing.T) {
+	form := &signupForm{Age: 30}
+	if err := Decode("form", &Source{Values: map[string][]string{"name": {"zero"}}}, form); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if form.Age != 30 {
+		t.Errorf("expected the default not to override the bound value, got %d", form.Age)
+	}
+}
+
+func TestDecodeFiles(t *testing.T) {
+	type upload struct {
+		Avatar      *multipart.FileHeader   `form:"avatar,required"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+	}
+
+	avatar := &multipart.FileHeader{Filename: "avatar.png"}
+	attachments := []*multipart.FileHeader{{Filename: "a.pdf"}, {Filename: "b.pdf"}}
+
+	u := &upload{}
+	err := Decode("form", &Source{Files: map[string][]*multipart.FileHeader{
+		"avatar":      {avatar},
+		"attachments": attachments,
+	}}, u)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.Avatar != avatar || !reflect.DeepEqual(u.Attachments, attachments) {
+		t.Errorf("expected the files to be bound, got %+v", u)
+	}
+
+	if err := Decode("form", &Source{}, &upload{}); !errors.Is(err, ErrRequired) {
+		t.Errorf("expected the missing file to be required, got %v", err)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag      string
+		expected Options
+	}{
+		{"name", Options{Name: "name"}},
+		{"name,required", Options{Name: "name", Required: true}},
+		{"at,layout=2006-01-02", Options{Name: "at", Layout: "2006-01-02"}},
+		{"tags,default=a,b", Options{Name: "tags", Default: "a,b", HasDefault: true}},
+		{"page,default=", Options{Name: "page", Default: "", HasDefault: true}},
+		{",required", Options{Required: true}},
+		{"name,omitempty", Options{Name: "name", OmitEmpty: true}},
+		{"sort,enum=asc|desc", Options{Name: "sort", Enum: []string{"asc", "desc"}}},
+		{"ids,seperator=;", Options{Name: "ids", Separator: ";"}},
+		{"ids,separator=|", Options{Name: "ids", Separator: "|"}},
+		{"token,env=TOKEN", Options{Name: "token", Env: "TOKEN"}},
+	}
+
+	for _, c := range cases {
+		opts, err := ParseTag(c.tag)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(*opts, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.tag, c.expected, *opts)
+		}
+	}
+
+	opts, err := ParseTag("limit,min=1,max=100,regexp=/^[0-9]+$/")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *opts.Min != 1 || *opts.Max != 100 || opts.RegExp.String() != "^[0-9]+$" {
+		t.Errorf("expected the range and regexp, got %+v", opts)
+	}
+
+	for _, tag := range []string{"name,unknown", "name,requried", "limit,min=one", "name,regexp=/[/", "name,layout"} {
+		if _, err := ParseTag(tag); err == nil {
+			t.Errorf("%s: expected error", tag)
+		}
+	}
+}
+
+type listQuery struct {
+	Limit  int      `query:"limit,min=1,max=100"`
+	Sort   string   `query:"sort,enum=asc|desc,default=asc"`
+	Name   string   `query:"name,omitempty,min=2,max=4"`
+	Code   string   `query:"code,omitempty,regexp=/^[a-z]+$/"`
+	IDs    []int    `query:"ids,seperator=;,omitempty,min=1"`
+	Region string   `query:"region,env=BINDING_TEST_REGION"`
+	Labels []string `query:"labels,omitempty,enum=a|b"`
+	Page   int
+}
+
+func TestDecodeConstraints(t *testing.T) {
+	t.Setenv("BINDING_TEST_REGION", "cn")
+
+	q := &listQuery{}
+	src := &Source{Values: map[string][]string{
+		"limit":  {"10"},
+		"name":   {"张三"},
+		"code":   {"abc"},
+		"ids":    {"1;2", "3"},
+		"labels": {"a", "b"},
+		"Page":   {"2"},
+	}}
+	if err := Decode("query", src, q); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := &listQuery{Limit: 10, Sort: "asc", Name: "张三", Code: "abc", IDs: []int{1, 2, 3}, Region: "cn", Labels: []string{"a", "b"}, Page: 2}
+	if !reflect.DeepEqual(q, expected) {
+		t.Errorf("expected %+v, got %+v", expected, q)
+	}
+
+	cases := []struct {
+		name   string
+		values map[string][]string
+		err    string
+	}{
+		{"max", map[string][]string{"limit": {"1000"}}, "limit must be in range(1, 100), but 1000"},
+		{"min", map[string][]string{"limit": {"0"}}, "limit must be in range(1, 100), but 0"},
+		{"missing with range", map[string][]string{}, "limit must be in range(1, 100), but empty"},
+		{"enum", map[string][]string{"limit": {"1"}, "sort": {"evil"}}, "sort must be in enum(asc|desc), but evil"},
+		{"length", map[string][]string{"limit": {"1"}, "name": {"abcde"}}, "length of name must be in range(2, 4), but 5"},
+		{"regexp", map[string][]string{"limit": {"1"}, "code": {"ABC"}}, "code is invalid with regexp(^[a-z]+$)"},
+		{"element min", map[string][]string{"limit": {"1"}, "ids": {"1;0"}}, "ids must be at least 1, but 0"},
+		{"element enum", map[string][]string{"limit": {"1"}, "labels": {"a", "c"}}, "labels must be in enum(a|b), but c"},
+	}
+
+	for _, c := range cases {
+		err := Decode("query", &Source{Values: c.values}, &listQuery{})
+		if err == nil || err.Error() != c.err {
+			t.Errorf("%s: expected error %q, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestDecodeUnknownOption(t *testing.T) {
+	var q struct {
+		Name string `query:"name,requried"`
+	}
+
+	err := Decode("query", &Source{Values: map[string][]string{"name": {"zero"}}}, &q)
+	if err == nil || !strings.Contains(err.Error(), `unknown option "requried"`) {
+		t.Errorf("expected the unknown option error, got %v", err)
+	}
+}
+
+type inRequest struct {
+	ID    string `in:"path=id"`
+	Page  int    `in:"query=page,default=1"`
+	Token string `in:"header=x-token,required"`
+	Name  string `form:"name" in:"query=name"`
+}
+
+func TestDecodeInTagSources(t *testing.T) {
+	cases := []struct {
+		name     string
+		tag      string
+		src      *Source
+		expected inRequest
+		err      error
+	}{
+		{
+			name:     "path",
+			tag:      "param",
+			src:      &Source{Values: map[string][]string{"id": {"1"}, "page": {"2"}, "x-token": {"t"}}},
+			expected: inRequest{ID: "1"},
+		},
+		{
+			name:     "the path field is not bound from the query",
+			tag:      "query",
+			src:      &Source{Values: map[string][]string{"id": {"evil"}, "page": {"2"}}},
+			expected: inRequest{Page: 2},
+		},
+		{
+			name: "header with the normalized key",
+			tag:  "header",
+			src: &Source{
+				Values: map[string][]string{"X-Token": {"t"}, "Id": {"evil"}},
+				Key:    func(key string) string { return map[string]string{"x-token": "X-Token", "id": "Id"}[key] },
+			},
+			expected: inRequest{Token: "t"},
+		},
+		{
+			name: "the header field is required",
+			tag:  "header",
+			src:  &Source{Values: map[string][]string{}},
+			err:  ErrRequired,
+		},
+		{
+			name:     "the source tag wins over the in tag",
+			tag:      "form",
+			src:      &Source{Values: map[string][]string{"name": {"zero"}, "id": {"evil"}}},
+			expected: inRequest{Name: "zero"},
+		},
+	}
+
+	for _, c := range cases {
+		req := inRequest{}
+		err := Decode(c.tag, c.src, &req)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.name, err)
+			continue
+		}
+		if req != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, req)
+		}
+	}
+}
+
+type testStatus string
+
+type testCode struct {
+	value string
+}
+
+func (c testCode) String() string {
+	return c.value
+}
+
+func init() {
+	Register(reflect.TypeOf(testStatus("")), func(raw string) (any, error) {
+		switch s := testStatus(strings.ToLower(raw)); s {
+		case "active", "disabled":
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("invalid status: %s", raw)
+	})
+
+	// a broken binder returning another type
+	Register(reflect.TypeOf(testCode{}), func(raw string) (any, error) {
+		return raw, nil
+	})
+}
+
+type statusRequest struct {
+	Status   testStatus            `query:"status"`
+	Statuses []testStatus          `query:"statuses"`
+	Previous *testStatus           `query:"previous"`
+	ByRegion map[string]testStatus `query:"by_region"`
+}
+
+func TestDecodeRegisteredType(t *testing.T) {
+	cases := []struct {
+		name     string
+		values   map[string][]string
+		expected statusRequest
+		err      bool
+	}{
+		{
+			name:     "value is parsed and normalized by the binder",
+			values:   map[string][]string{"status": {"Active"}, "statuses": {"active", "DISABLED"}, "by_region[cn]": {"disabled"}},
+			expected: statusRequest{Status: "active", Statuses: []testStatus{"active", "disabled"}, ByRegion: map[string]testStatus{"cn": "disabled"}},
+		},
+		{name: "invalid value", values: map[string][]string{"status": {"hacked"}}, err: true},
+		{name: "invalid element", values: map[string][]string{"statuses": {"active", "hacked"}}, err: true},
+		{name: "invalid pointer", values: map[string][]string{"previous": {"hacked"}}, err: true},
+		{name: "invalid map value", values: map[string][]string{"by_region.cn": {"hacked"}}, err: true},
+	}
+
+	for _, c := range cases {
+		req := statusRequest{}
+		err := Decode("query", &Source{Values: c.values}, &req)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected error", c.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(req, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, req)
+		}
+	}
+
+	previous := statusRequest{}
+	if err := Decode("query", &Source{Values: map[string][]string{"previous": {"disabled"}}}, &previous); err != nil || previous.Previous == nil || *previous.Previous != "disabled" {
+		t.Errorf("expected the pointer to be bound, got %v %v", previous.Previous, err)
+	}
+}
+
+func TestDecodeRegisteredTypeOfWrongBinder(t *testing.T) {
+	req := struct {
+		Code testCode `query:"code"`
+	}{}
+	if err := Decode("query", &Source{Values: map[string][]string{"code": {"x"}}}, &req); err == nil {
+		t.Errorf("expected the binder returning another type to fail")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	type nested struct {
+		Status testStatus
+	}
+	type body struct {
+		Status   testStatus
+		Statuses []testStatus
+		Nested   *nested
+		ByRegion map[string]testStatus
+		Empty    testStatus
+	}
+
+	cases := []struct {
+		name     string
+		body     *body
+		expected *body
+		err      bool
+	}{
+		{
+			name:     "normalized",
+			body:     &body{Status: "ACTIVE", Statuses: []testStatus{"Disabled"}, Nested: &nested{Status: "active"}, ByRegion: map[string]testStatus{"cn": "DISABLED"}},
+			expected: &body{Status: "active", Statuses: []testStatus{"disabled"}, Nested: &nested{Status: "active"}, ByRegion: map[string]testStatus{"cn": "disabled"}},
+		},
+		{name: "invalid field", body: &body{Status: "hacked"}, err: true},
+		{name: "invalid element", body: &body{Statuses: []testStatus{"active", "hacked"}}, err: true},
+		{name: "invalid nested field", body: &body{Nested: &nested{Status: "hacked"}}, err: true},
+		{name: "invalid map value", body: &body{ByRegion: map[string]testStatus{"cn": "hacked"}}, err: true},
+	}
+
+	for _, c := range cases {
+		err := Check(c.body)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected error", c.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.body, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, c.body)
+		}
+	}
+}
+
+func TestCheckTag(t *testing.T) {
+	type item struct {
+		Kind string `json:"kind,enum=a|b"`
+	}
+	type body struct {
+		Limit   int      `json:"limit,omitempty,min=1,max=100"`
+		Sort    string   `json:"sort,omitempty,enum=asc|desc"`
+		Name    string   `json:"name,required"`
+		Code    *string  `json:"code,regexp=/^[a-z]+$/"`
+		Tags    []string `json:"tags,max=3"`
+		Items   []item   `json:"items"`
+		Count   int64    `json:"count,string,min=1"`
+		Ignored string   `json:"-"`
+	}
+
+	valid := "abc"
+	invalid := "ABC"
+	cases := []struct {
+		name string
+		body *body
+		err  string
+	}{
+		{"valid", &body{Limit: 10, Sort: "asc", Name: "zero", Code: &valid, Tags: []string{"abc"}, Items: []item{{Kind: "a"}}}, ""},
+		{"zero values", &body{Name: "zero"}, ""},
+		{"required", &body{}, "name is required"},
+		{"max", &body{Name: "zero", Limit: 1000}, "limit must be in range(1, 100), but 1000"},
+		{"enum", &body{Name: "zero", Sort: "evil"}, "sort must be in enum(asc|desc), but evil"},
+		{"regexp", &body{Name: "zero", Code: &invalid}, "code is invalid with regexp(^[a-z]+$)"},
+		{"element length", &body{Name: "zero", Tags: []string{"abcd"}}, "length of tags must be at most 3, but 4"},
+		{"nested", &body{Name: "zero", Items: []item{{Kind: "c"}}}, "items[0].kind must be in enum(a|b), but c"},
+		{"codec option", &body{Name: "zero", Count: -1}, "count must be at least 1, but -1"},
+	}
+
+	for _, c := range cases {
+		err := Check(c.body, "json")
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", c.name, err)
+			}
+			continue
+		}
+
+		if err == nil || err.Error() != c.err {
+			t.Errorf("%s: expected error %q, got %v", c.name, c.err, err)
+		}
+	}
+
+	// without the tag, the options are not checked
+	if err := Check(&body{}); err != nil {
+		t.Errorf("expected no error without the tag, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package form
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/go-zoox/zoox/components/context/typed"
+)
 
 // Form ...
 type Form interface {
@@ -8,6 +12,7 @@ type Form interface {
 }
 
 type form struct {
+	typed.Values
 	request *http.Request
 	//
 	params map[string]string
@@ -16,11 +21,34 @@ type form struct {
 // New creates a form.
 func New(request *http.Request) Form {
 	return &form{
+		Values: typed.New(func(key string) []string {
+			// FormValue parses the form
+			request.FormValue(key)
+			return request.Form[key]
+		}),
 		request: request,
 		params:  make(map[string]string),
 	}
 }
 
+// Typed returns the typed getters of f, such as Int64E("age"):
+// f itself if it implements typed.Values (the form of New does), otherwise the getters of f.Get.
+//
+//	age := form.Typed(ctx.Form()).Int("age")
+func Typed(f Form) typed.Values {
+	if tv, ok := f.(typed.Values); ok {
+		return tv
+	}
+
+	return typed.New(func(key string) []string {
+		if value := f.Get(key); value != "" {
+			return []string{value}
+		}
+
+		return nil
+	})
+}
+
 // Get gets request form with the given name.
 func (f *form) Get(key string, defaultValue ...string) string {
 	value := f.request.FormValue(key)
@@ -12,12 +12,28 @@ type MQ interface {
 	Consume(ctx context.Context, topic string, group string, consumer string, start string, batchSize int, h gomq.Handler) error
 }
 
+// ContextBinder is implemented by the mq which can be bound to another context, such as the one created by New.
+type ContextBinder interface {
+	// WithContext returns the mq at the derived context, such as ctx.WithTimeout.
+	WithContext(ctx context.Context) MQ
+}
+
+// WithContext returns the mq at the derived context, such as ctx.WithTimeout,
+// or m itself if it does not implement ContextBinder.
+func WithContext(m MQ, ctx context.Context) MQ {
+	if binder, ok := m.(ContextBinder); ok {
+		return binder.WithContext(ctx)
+	}
+
+	return m
+}
+
 type mq struct {
 	ctx context.Context
 	ps  gomq.MQ
 }
 
-// New creates a mq at the given context.
+// New creates a mq at the given context, sending stops when the context is done.
 func New(ctx context.Context, ps gomq.MQ) MQ {
 	return &mq{
 		ctx: ctx,
@@ -30,5 +46,20 @@ func (p *mq) Send(topic string, message *gomq.Message) error {
 }
 
 func (p *mq) Consume(ctx context.Context, topic string, group string, consumer string, start string, batchSize int, h gomq.Handler) error {
-	return p.ps.Consume(p.ctx, topic, group, consumer, start, batchSize, h)
+	// consuming stops when either context is done
+	if ctx == nil {
+		ctx = p.ctx
+	} else if ctx != p.ctx {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(p.ctx, cancel)
+		defer stop()
+	}
+
+	return p.ps.Consume(ctx, topic, group, consumer, start, batchSize, h)
+}
+
+func (p *mq) WithContext(ctx context.Context) MQ {
+	return New(ctx, p.ps)
 }
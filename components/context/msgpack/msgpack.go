@@ -0,0 +1,514 @@
+// Package msgpack is the builtin msgpack codec, without dependencies.
+//
+// The app uses github.com/vmihailenco/msgpack/v5 by default, this codec is the fallback
+// for the builds that avoid the dependency, set by app.SetMsgpackCodec(msgpack.Codec{}).
+//
+// The values are encoded as their json representation, so the json tags and json.Marshaler apply,
+// and decoded into the values with json semantics, such as numbers into int or float fields.
+// It trades speed for no dependencies, the values are converted through encoding/json.
+// The bin and timestamp (ext -1) types are decoded as base64 strings and time.Time.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrUnexpectedEOF is returned when the data is truncated.
+var ErrUnexpectedEOF = errors.New("msgpack: unexpected end of data")
+
+// ErrMaxDepth is returned when the arrays and maps are nested deeper than MaxDepth.
+var ErrMaxDepth = errors.New("msgpack: max nesting depth exceeded")
+
+// MaxDepth is the max nesting depth of arrays and maps,
+// deeper data is rejected instead of overflowing the stack.
+const MaxDepth = 64
+
+// Codec is the msgpack codec.
+type Codec struct{}
+
+// Marshal returns the msgpack encoding of v.
+func (Codec) Marshal(v any) ([]byte, error) {
+	return Marshal(v)
+}
+
+// Unmarshal decodes the msgpack data into v.
+func (Codec) Unmarshal(data []byte, v any) error {
+	return Unmarshal(data, v)
+}
+
+// Marshal returns the msgpack encoding of v.
+func Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := encode(buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CheckDepth checks the data is one msgpack value with arrays and maps nested at most max levels,
+// without decoding it, so that the decoders without depth limit can be guarded.
+func CheckDepth(data []byte, max int) error {
+	d := &decoder{data: data, maxDepth: max}
+	if err := d.skip(); err != nil {
+		return err
+	}
+	if d.offset != len(data) {
+		return fmt.Errorf("msgpack: %d bytes remain after the value", len(data)-d.offset)
+	}
+
+	return nil
+}
+
+// Unmarshal decodes the msgpack data into v.
+func Unmarshal(data []byte, v any) error {
+	d := &decoder{data: data, maxDepth: MaxDepth}
+	value, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if d.offset != len(data) {
+		return fmt.Errorf("msgpack: %d bytes remain after the value", len(data)-d.offset)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+func encode(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			encodeInt(buf, i)
+		} else if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			buf.WriteByte(0xcf)
+			binary.Write(buf, binary.BigEndian, u)
+		} else if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+			buf.WriteByte(0xcb)
+			binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+		} else {
+			return fmt.Errorf("msgpack: invalid number %s", v)
+		}
+	case string:
+		encodeLength(buf, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(v)
+	case []any:
+		encodeLength(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		encodeLength(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			encode(buf, key)
+			if err := encode(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", value)
+	}
+
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	case i >= 0 && i <= math.MaxUint8:
+		buf.WriteByte(0xcc)
+		buf.WriteByte(byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		binary.Write(buf, binary.BigEndian, uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		binary.Write(buf, binary.BigEndian, uint32(i))
+	case i >= 0:
+		buf.WriteByte(0xcf)
+		binary.Write(buf, binary.BigEndian, uint64(i))
+	case i >= math.MinInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(i))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(i))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, i)
+	}
+}
+
+// encodeLength writes the header of str, array or map, the 8 bits format is only available for str.
+func encodeLength(buf *bytes.Buffer, n int, fix byte, fixMax int, f8, f16, f32 byte) {
+	switch {
+	case n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(f8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(f16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(f32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+type decoder struct {
+	data     []byte
+	offset   int
+	depth    int
+	maxDepth int
+}
+
+// enter increases the nesting depth, the caller must call leave after the container is decoded.
+func (d *decoder) enter() error {
+	d.depth++
+	if d.depth > d.maxDepth {
+		return ErrMaxDepth
+	}
+
+	return nil
+}
+
+func (d *decoder) leave() {
+	d.depth--
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || d.offset+n > len(d.data) {
+		return nil, ErrUnexpectedEOF
+	}
+
+	b := d.data[d.offset : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (d *decoder) decode() (any, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		bin, err := d.read(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, bin...), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(int(n))
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0:
+		u, err := d.uint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := d.uint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := d.uint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := d.uint(8)
+		return int64(u), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n))
+	}
+
+	return nil, fmt.Errorf("msgpack: invalid format 0x%x", c)
+}
+
+func (d *decoder) decodeString(n int) (any, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (d *decoder) decodeArray(n int) (any, error) {
+	// each item takes 1 byte at least
+	if n > len(d.data)-d.offset {
+		return nil, ErrUnexpectedEOF
+	}
+
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+
+	items := make([]any, n)
+	for i := range items {
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+func (d *decoder) decodeMap(n int) (any, error) {
+	// each entry takes 2 bytes at least
+	if n > (len(d.data)-d.offset)/2 {
+		return nil, ErrUnexpectedEOF
+	}
+
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		if s, ok := key.(string); ok {
+			m[s] = value
+		} else {
+			m[fmt.Sprint(key)] = value
+		}
+	}
+
+	return m, nil
+}
+
+// skip reads over one value, the arrays and maps are not allocated.
+func (d *decoder) skip() error {
+	b, err := d.read(1)
+	if err != nil {
+		return err
+	}
+
+	c := b[0]
+	switch {
+	case c <= 0x7f, c >= 0xe0:
+		return nil
+	case c&0xf0 == 0x80:
+		return d.skipValues(2 * int(c&0x0f))
+	case c&0xf0 == 0x90:
+		return d.skipValues(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		_, err := d.read(int(c & 0x1f))
+		return err
+	}
+
+	var n uint64
+	switch c {
+	case 0xc0, 0xc2, 0xc3:
+		return nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err = d.uint(1 << (c - 0xc4))
+	case 0xc7, 0xc8, 0xc9:
+		// the ext type byte follows the length
+		n, err = d.uint(1 << (c - 0xc7))
+		n++
+	case 0xca:
+		n = 4
+	case 0xcb:
+		n = 8
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n = 1 << (c - 0xcc)
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n = 1 << (c - 0xd0)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		n = 1 + 1<<(c-0xd4)
+	case 0xd9, 0xda, 0xdb:
+		n, err = d.uint(1 << (c - 0xd9))
+	case 0xdc, 0xdd:
+		if n, err = d.uint(2 << (c - 0xdc)); err != nil {
+			return err
+		}
+		return d.skipValues(int(n))
+	case 0xde, 0xdf:
+		if n, err = d.uint(2 << (c - 0xde)); err != nil {
+			return err
+		}
+		return d.skipValues(2 * int(n))
+	default:
+		return fmt.Errorf("msgpack: invalid format 0x%x", c)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n > uint64(len(d.data)-d.offset) {
+		return ErrUnexpectedEOF
+	}
+	d.offset += int(n)
+	return nil
+}
+
+// skipValues reads over n values of an array or map.
+func (d *decoder) skipValues(n int) error {
+	// each value takes 1 byte at least
+	if n > len(d.data)-d.offset {
+		return ErrUnexpectedEOF
+	}
+
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer d.leave()
+
+	for i := 0; i < n; i++ {
+		if err := d.skip(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeExt decodes the timestamp extension (-1), the other extensions are not supported.
+func (d *decoder) decodeExt(n int) (any, error) {
+	t, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t[0]) != -1 {
+		return nil, fmt.Errorf("msgpack: unsupported ext type %d", int8(t[0]))
+	}
+
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), nil
+	case 8:
+		u := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(u&0x3ffffffff), int64(u>>34)).UTC(), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(binary.BigEndian.Uint32(b))).UTC(), nil
+	}
+
+	return nil, fmt.Errorf("msgpack: invalid timestamp length %d", n)
+}
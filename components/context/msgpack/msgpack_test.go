@@ -0,0 +1,163 @@
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+type user struct {
+	ID     int64             `json:"id"`
+	Name   string            `json:"name"`
+	Score  float64           `json:"score"`
+	Admin  bool              `json:"admin"`
+	Tags   []string          `json:"tags"`
+	Extra  map[string]string `json:"extra,omitempty"`
+	Parent *user             `json:"parent"`
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(map[string]any{"a": 1, "b": []any{-1, "x", nil, true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x94, 0xff, 0xa1, 'x', 0xc0, 0xc3}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected % x, got % x", expected, data)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := &user{
+		ID:    1 << 40,
+		Name:  string(bytes.Repeat([]byte("z"), 300)),
+		Score: 99.5,
+		Admin: true,
+		Tags:  []string{"a", "b"},
+		Extra: map[string]string{"k": "v"},
+		Parent: &user{
+			ID:   -129,
+			Name: "root",
+		},
+	}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &user{}
+	if err := Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.ID != in.ID || out.Name != in.Name || out.Score != in.Score || !out.Admin || len(out.Tags) != 2 || out.Extra["k"] != "v" {
+		t.Errorf("unexpected user: %+v", out)
+	}
+	if out.Parent == nil || out.Parent.ID != -129 || out.Parent.Name != "root" {
+		t.Errorf("unexpected parent: %+v", out.Parent)
+	}
+}
+
+func TestUnmarshalBinaryTypes(t *testing.T) {
+	// {"bin": bin8(0x01 0x02), "f": float32(1.5), "at": timestamp32(1700000000), "n": uint16(300)}
+	data := []byte{
+		0x84,
+		0xa3, 'b', 'i', 'n', 0xc4, 0x02, 0x01, 0x02,
+		0xa1, 'f', 0xca, 0x3f, 0xc0, 0x00, 0x00,
+		0xa2, 'a', 't', 0xd6, 0xff, 0x65, 0x53, 0xf1, 0x00,
+		0xa1, 'n', 0xcd, 0x01, 0x2c,
+	}
+
+	var out struct {
+		Bin []byte    `json:"bin"`
+		F   float32   `json:"f"`
+		At  time.Time `json:"at"`
+		N   int       `json:"n"`
+	}
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.Bin, []byte{1, 2}) || out.F != 1.5 || out.At.Unix() != 1700000000 || out.N != 300 {
+		t.Errorf("unexpected value: %+v", out)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var v any
+	for _, data := range [][]byte{
+		{},
+		{0xa5, 'a'},
+		{0xdd, 0xff, 0xff, 0xff, 0xff},
+		{0xc1},
+		{0x01, 0x02},
+	} {
+		if err := Unmarshal(data, &v); err == nil {
+			t.Errorf("expected error for % x", data)
+		}
+	}
+}
+
+func nested(depth int) []byte {
+	return append(bytes.Repeat([]byte{0x91}, depth), 0xc0)
+}
+
+func TestMaxDepth(t *testing.T) {
+	var v any
+	for _, c := range []struct {
+		data []byte
+		err  error
+	}{
+		{nested(MaxDepth), nil},
+		{nested(MaxDepth + 1), ErrMaxDepth},
+		// would overflow the stack without the limit
+		{nested(1 << 20), ErrMaxDepth},
+		{append(bytes.Repeat([]byte{0x81, 0xa1, 'k'}, MaxDepth+1), 0xc0), ErrMaxDepth},
+	} {
+		if err := Unmarshal(c.data, &v); !errors.Is(err, c.err) {
+			t.Errorf("Unmarshal(%d bytes): expected %v, got %v", len(c.data), c.err, err)
+		}
+		if err := CheckDepth(c.data, MaxDepth); !errors.Is(err, c.err) {
+			t.Errorf("CheckDepth(%d bytes): expected %v, got %v", len(c.data), c.err, err)
+		}
+	}
+}
+
+func TestCheckDepthSkipsAllFormats(t *testing.T) {
+	data, err := Marshal(map[string]any{"a": []any{1, 300, 1 << 40, -1, -200, 1.5, "x", nil, true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckDepth(data, MaxDepth); err != nil {
+		t.Fatal(err)
+	}
+
+	// bin8, float32, timestamp32, uint16 and ext8
+	data = []byte{0x95, 0xc4, 0x02, 0x01, 0x02, 0xca, 0x3f, 0xc0, 0x00, 0x00, 0xd6, 0xff, 0x65, 0x53, 0xf1, 0x00, 0xcd, 0x01, 0x2c, 0xc7, 0x01, 0x05, 0x00}
+	if err := CheckDepth(data, MaxDepth); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{{}, {0xa5, 'a'}, {0xdd, 0xff, 0xff, 0xff, 0xff}, {0xc1}, {0x01, 0x02}, {0xc7, 0x01}} {
+		if err := CheckDepth(data, MaxDepth); err == nil {
+			t.Errorf("expected error for % x", data)
+		}
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	f.Add([]byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x94, 0xff, 0xa1, 'x', 0xc0, 0xc3})
+	f.Add(nested(MaxDepth + 1))
+	f.Add([]byte{0xdf, 0xff, 0xff, 0xff, 0xff, 0xdc})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v any
+		err := Unmarshal(data, &v)
+		if checkErr := CheckDepth(data, MaxDepth); err == nil && checkErr != nil {
+			t.Errorf("CheckDepth rejects the data Unmarshal accepts: %v", checkErr)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package param
 
-import "github.com/go-zoox/core-utils/strings"
+import (
+	"github.com/go-zoox/core-utils/strings"
+	"github.com/go-zoox/zoox/components/context/typed"
+)
 
 // Param ...
 type Param interface {
@@ -9,16 +12,42 @@ type Param interface {
 }
 
 type param struct {
+	typed.Values
 	params map[string]string
 }
 
 // New creates a param.
 func New(value map[string]string) Param {
 	return &param{
+		Values: typed.New(func(key string) []string {
+			if v, ok := value[key]; ok {
+				return []string{v}
+			}
+
+			return nil
+		}),
 		params: value,
 	}
 }
 
+// Typed returns the typed getters of p, such as Int64E("id"):
+// p itself if it implements typed.Values (the param of New does), otherwise the getters of p.Get.
+//
+//	id, err := param.Typed(ctx.Param()).Int64E("id")
+func Typed(p Param) typed.Values {
+	if tv, ok := p.(typed.Values); ok {
+		return tv
+	}
+
+	return typed.New(func(key string) []string {
+		if value := p.Get(key).String(); value != "" {
+			return []string{value}
+		}
+
+		return nil
+	})
+}
+
 // Get gets request param with the given name.
 func (q *param) Get(key string, defaultValue ...string) strings.Value {
 	value, ok := q.params[key]
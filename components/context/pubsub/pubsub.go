@@ -3,7 +3,7 @@ package pubsub
 import (
 	"context"
 
-	gopubsub "github.com/go-zoox/pubsub"
+	gopubsub "github.com/go-zoox/zoox/components/application/pubsub"
 )
 
 // PubSub ...
@@ -12,12 +12,29 @@ type PubSub interface {
 	Subscribe(topic string, handler gopubsub.Handler) error
 }
 
+// ContextBinder is implemented by the pubsub which can be bound to another context, such as the one created by New.
+type ContextBinder interface {
+	// WithContext returns the pubsub at the derived context, such as ctx.WithTimeout.
+	WithContext(ctx context.Context) PubSub
+}
+
+// WithContext returns the pubsub at the derived context, such as ctx.WithTimeout,
+// or ps itself if it does not implement ContextBinder.
+func WithContext(ps PubSub, ctx context.Context) PubSub {
+	if binder, ok := ps.(ContextBinder); ok {
+		return binder.WithContext(ctx)
+	}
+
+	return ps
+}
+
 type pubsub struct {
 	ctx context.Context
 	ps  gopubsub.PubSub
 }
 
-// New creates a pubsub at the given context.
+// New creates a pubsub at the given context, publishing stops when the context is done,
+// and the subscriptions are removed.
 func New(ctx context.Context, ps gopubsub.PubSub) PubSub {
 	return &pubsub{
 		ctx: ctx,
@@ -32,3 +49,7 @@ func (p *pubsub) Publish(topic string, message *gopubsub.Message) error {
 func (p *pubsub) Subscribe(topic string, handler gopubsub.Handler) error {
 	return p.ps.Subscribe(p.ctx, topic, handler)
 }
+
+func (p *pubsub) WithContext(ctx context.Context) PubSub {
+	return New(ctx, p.ps)
+}
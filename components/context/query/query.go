@@ -2,8 +2,11 @@ package query
 
 import (
 	"net/http"
+	"net/url"
+	"sync"
 
 	"github.com/go-zoox/core-utils/strings"
+	"github.com/go-zoox/zoox/components/context/typed"
 )
 
 // Query ...
@@ -17,19 +20,55 @@ type Query interface {
 }
 
 type query struct {
+	typed.Values
 	request *http.Request
+	//
+	once   sync.Once
+	values url.Values
 }
 
 // New creates a query.
 func New(request *http.Request) Query {
-	return &query{
+	q := &query{
 		request: request,
 	}
+	q.Values = typed.New(func(key string) []string {
+		return q.all()[key]
+	})
+
+	return q
+}
+
+// Typed returns the typed getters of q, such as Int64E("limit") and Ints("ids"):
+// q itself if it implements typed.Values (the query of New does), otherwise the getters of q.Get.
+//
+//	limit := query.Typed(ctx.Query()).Int("limit", 20)
+func Typed(q Query) typed.Values {
+	if tv, ok := q.(typed.Values); ok {
+		return tv
+	}
+
+	return typed.New(func(key string) []string {
+		if value := q.Get(key).String(); value != "" {
+			return []string{value}
+		}
+
+		return nil
+	})
+}
+
+// all returns the query values, parsed once.
+func (q *query) all() url.Values {
+	q.once.Do(func() {
+		q.values = q.request.URL.Query()
+	})
+
+	return q.values
 }
 
 // Get gets request query with the given name.
 func (q *query) Get(key string, defaultValue ...string) strings.Value {
-	value := q.request.URL.Query().Get(key)
+	value := q.all().Get(key)
 	if value == "" && len(defaultValue) > 0 {
 		value = defaultValue[0]
 	}
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/core-utils/strings"
+	"github.com/stretchr/testify/assert"
+)
+
+// getter is a Query implemented outside of the package, such as a mock.
+type getter struct {
+	Query
+	values map[string]string
+}
+
+func (g *getter) Get(key string, defaultValue ...string) strings.Value {
+	return strings.Value(g.values[key])
+}
+
+func TestQueryTyped(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/?limit=20&ids=1,2&ids=3&enabled=true", nil)
+	q := New(request)
+
+	values := Typed(q)
+	assert.Equal(t, 20, values.Int("limit"))
+	assert.Equal(t, []int{1, 2, 3}, values.Ints("ids"))
+	assert.True(t, values.Bool("enabled"))
+	assert.Equal(t, 10, values.Int("missing", 10))
+
+	// parsed once per request
+	request.URL.RawQuery = "limit=50"
+	assert.Equal(t, 20, values.Int("limit"))
+	assert.Equal(t, "20", q.Get("limit").String())
+}
+
+func TestQueryTypedFallback(t *testing.T) {
+	values := Typed(&getter{values: map[string]string{"limit": "20", "ids": "1,2"}})
+	assert.Equal(t, 20, values.Int("limit"))
+	assert.Equal(t, []int{1, 2}, values.Ints("ids"))
+
+	_, err := values.IntE("missing")
+	assert.Error(t, err)
+}
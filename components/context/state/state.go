@@ -1,12 +1,17 @@
 package state
 
+import "sync"
+
 // State is the state for request context.
 type State interface {
 	Get(key string) interface{}
 	Set(key string, value interface{})
+	Has(key string) bool
+	Del(key string)
 }
 
 type state struct {
+	sync.RWMutex
 	data map[string]interface{}
 }
 
@@ -19,10 +24,33 @@ func New() State {
 
 // Get gets the value from context state with the given key.
 func (s *state) Get(key string) interface{} {
+	s.RLock()
+	defer s.RUnlock()
+
 	return s.data[key]
 }
 
 // Set sets the value to context state with the given key.
 func (s *state) Set(key string, value interface{}) {
+	s.Lock()
+	defer s.Unlock()
+
 	s.data[key] = value
 }
+
+// Has returns true if the key is set in context state.
+func (s *state) Has(key string) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	_, ok := s.data[key]
+	return ok
+}
+
+// Del deletes the key from context state.
+func (s *state) Del(key string) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.data, key)
+}
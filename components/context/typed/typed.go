@@ -0,0 +1,245 @@
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by the E getters when the key is missing.
+var ErrNotFound = errors.New("not found")
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Values is the typed getters of request values, such as query, param and form.
+//
+//	the getters return the default value (or zero) if the key is missing or invalid,
+//	the E getters return the error instead.
+type Values interface {
+	Int(key string, defaultValue ...int) int
+	IntE(key string) (int, error)
+	Int64(key string, defaultValue ...int64) int64
+	Int64E(key string) (int64, error)
+	Bool(key string, defaultValue ...bool) bool
+	BoolE(key string) (bool, error)
+	Float(key string, defaultValue ...float64) float64
+	FloatE(key string) (float64, error)
+	// Time parses the value with layout, default: time.RFC3339
+	Time(key string, layout string, defaultValue ...time.Time) time.Time
+	TimeE(key string, layout string) (time.Time, error)
+	Duration(key string, defaultValue ...time.Duration) time.Duration
+	DurationE(key string) (time.Duration, error)
+	UUID(key string, defaultValue ...string) string
+	UUIDE(key string) (string, error)
+	// Strings returns the values of repeated keys and comma separated values, such as ?ids=1,2&ids=3
+	Strings(key string) []string
+	Ints(key string) []int
+	IntsE(key string) ([]int, error)
+	Int64s(key string) []int64
+	Int64sE(key string) ([]int64, error)
+}
+
+type values struct {
+	lookup func(key string) []string
+}
+
+// New creates the typed getters with the lookup of raw values.
+func New(lookup func(key string) []string) Values {
+	return &values{
+		lookup: lookup,
+	}
+}
+
+func (v *values) raw(key string) (string, error) {
+	values := v.lookup(key)
+	if len(values) == 0 || values[0] == "" {
+		return "", fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+
+	return values[0], nil
+}
+
+func parse[T any](v *values, key string, fn func(s string) (T, error)) (T, error) {
+	var zero T
+	raw, err := v.raw(key)
+	if err != nil {
+		return zero, err
+	}
+
+	value, err := fn(raw)
+	if err != nil {
+		return zero, fmt.Errorf("%s: invalid value %q: %v", key, raw, err)
+	}
+
+	return value, nil
+}
+
+func orDefault[T any](value T, err error, defaultValue []T) T {
+	if err != nil {
+		var zero T
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+
+		return zero
+	}
+
+	return value
+}
+
+// IntE ...
+func (v *values) IntE(key string) (int, error) {
+	return parse(v, key, strconv.Atoi)
+}
+
+// Int ...
+func (v *values) Int(key string, defaultValue ...int) int {
+	value, err := v.IntE(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// Int64E ...
+func (v *values) Int64E(key string) (int64, error) {
+	return parse(v, key, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+}
+
+// Int64 ...
+func (v *values) Int64(key string, defaultValue ...int64) int64 {
+	value, err := v.Int64E(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// BoolE ...
+func (v *values) BoolE(key string) (bool, error) {
+	return parse(v, key, func(s string) (bool, error) {
+		switch strings.ToLower(s) {
+		case "on", "yes", "y":
+			return true, nil
+		case "off", "no", "n":
+			return false, nil
+		}
+
+		return strconv.ParseBool(s)
+	})
+}
+
+// Bool ...
+func (v *values) Bool(key string, defaultValue ...bool) bool {
+	value, err := v.BoolE(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// FloatE ...
+func (v *values) FloatE(key string) (float64, error) {
+	return parse(v, key, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Float ...
+func (v *values) Float(key string, defaultValue ...float64) float64 {
+	value, err := v.FloatE(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// TimeE ...
+func (v *values) TimeE(key string, layout string) (time.Time, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	return parse(v, key, func(s string) (time.Time, error) {
+		return time.Parse(layout, s)
+	})
+}
+
+// Time ...
+func (v *values) Time(key string, layout string, defaultValue ...time.Time) time.Time {
+	value, err := v.TimeE(key, layout)
+	return orDefault(value, err, defaultValue)
+}
+
+// DurationE ...
+func (v *values) DurationE(key string) (time.Duration, error) {
+	return parse(v, key, time.ParseDuration)
+}
+
+// Duration ...
+func (v *values) Duration(key string, defaultValue ...time.Duration) time.Duration {
+	value, err := v.DurationE(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// UUIDE ...
+func (v *values) UUIDE(key string) (string, error) {
+	return parse(v, key, func(s string) (string, error) {
+		if !uuidRe.MatchString(s) {
+			return "", errors.New("not a uuid")
+		}
+
+		return strings.ToLower(s), nil
+	})
+}
+
+// UUID ...
+func (v *values) UUID(key string, defaultValue ...string) string {
+	value, err := v.UUIDE(key)
+	return orDefault(value, err, defaultValue)
+}
+
+// Strings ...
+func (v *values) Strings(key string) []string {
+	result := []string{}
+	for _, value := range v.lookup(key) {
+		for _, one := range strings.Split(value, ",") {
+			if one = strings.TrimSpace(one); one != "" {
+				result = append(result, one)
+			}
+		}
+	}
+
+	return result
+}
+
+func parseSlice[T any](v *values, key string, fn func(s string) (T, error)) ([]T, error) {
+	result := []T{}
+	for _, one := range v.Strings(key) {
+		value, err := fn(one)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q: %v", key, one, err)
+		}
+
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// IntsE ...
+func (v *values) IntsE(key string) ([]int, error) {
+	return parseSlice(v, key, strconv.Atoi)
+}
+
+// Ints ...
+func (v *values) Ints(key string) []int {
+	value, err := v.IntsE(key)
+	return orDefault(value, err, [][]int{{}})
+}
+
+// Int64sE ...
+func (v *values) Int64sE(key string) ([]int64, error) {
+	return parseSlice(v, key, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+}
+
+// Int64s ...
+func (v *values) Int64s(key string) []int64 {
+	value, err := v.Int64sE(key)
+	return orDefault(value, err, [][]int64{{}})
+}
@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/go-zoox/zoox/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis checks the connectivity of redis.
+func Redis(cfg *config.Redis) Check {
+	return Check{
+		Name: "redis",
+		Run: func(ctx context.Context) error {
+			client := redis.NewClient(&redis.Options{
+				Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+				Username: cfg.Username,
+				Password: cfg.Password,
+				DB:       cfg.DB,
+			})
+			defer client.Close()
+
+			if err := client.Ping(ctx).Err(); err != nil {
+				return Failf(
+					"check redis.host/redis.port/redis.password in config, and that redis is running and reachable",
+					"failed to connect redis %s:%d: %v", cfg.Host, cfg.Port, err,
+				)
+			}
+
+			return nil
+		},
+	}
+}
+
+// TLSCertificate checks the certificate and key files are valid and match,
+// it warns if the certificate expires in warnBefore.
+func TLSCertificate(certFile, keyFile string, warnBefore time.Duration) Check {
+	return Check{
+		Name: "tls",
+		Run: func(ctx context.Context) error {
+			pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return Failf(
+					"check tls_cert_file and tls_key_file are PEM encoded and the key belongs to the certificate",
+					"invalid tls certificate or key: %v", err,
+				)
+			}
+
+			cert, err := x509.ParseCertificate(pair.Certificate[0])
+			if err != nil {
+				return Failf("regenerate the certificate", "failed to parse tls certificate: %v", err)
+			}
+
+			now := time.Now()
+			switch {
+			case now.Before(cert.NotBefore):
+				return Failf("check the system clock, or wait until the certificate is valid", "tls certificate is not valid until %s", cert.NotBefore.Format(time.RFC3339))
+			case now.After(cert.NotAfter):
+				return Failf("renew the certificate", "tls certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+			case now.Add(warnBefore).After(cert.NotAfter):
+				return Warnf("renew the certificate soon", "tls certificate expires at %s (in %s)", cert.NotAfter.Format(time.RFC3339), cert.NotAfter.Sub(now).Round(time.Hour))
+			}
+
+			return nil
+		},
+	}
+}
+
+// Port checks the port is available to listen.
+func Port(host string, port int) Check {
+	return Check{
+		Name: fmt.Sprintf("port %d", port),
+		Run: func(ctx context.Context) error {
+			addr := fmt.Sprintf("%s:%d", host, port)
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return Failf(
+					fmt.Sprintf("stop the process using the port (lsof -i :%d), or change the port with PORT env or config", port),
+					"port %s is not available: %v", addr, err,
+				)
+			}
+
+			return listener.Close()
+		},
+	}
+}
+
+// File checks the file exists and is readable.
+func File(name, path string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			f, err := os.Open(path)
+			if err != nil {
+				return Failf(fmt.Sprintf("check the path %s exists and is readable", path), "failed to open %s: %v", path, err)
+			}
+
+			return f.Close()
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig loads the yaml or json config file into cfg (pointer to struct) by the config tags,
+// unknown keys and mismatched types are returned as problems.
+func LoadConfig(path string, cfg any) (problems []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	problems = decodeConfig(values, reflect.ValueOf(cfg).Elem(), "")
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// ConfigFile checks the config file is valid against cfg, cfg is filled with the values.
+func ConfigFile(path string, cfg any) Check {
+	return Check{
+		Name: "config",
+		Run: func(ctx context.Context) error {
+			problems, err := LoadConfig(path, cfg)
+			if err != nil {
+				return Failf("fix the syntax of the config file (yaml or json)", "%v", err)
+			}
+
+			if len(problems) > 0 {
+				return Warnf("remove or rename the keys, see the config struct for available keys", "%s", strings.Join(problems, "; "))
+			}
+
+			return nil
+		},
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func decodeConfig(values map[string]any, v reflect.Value, prefix string) (problems []string) {
+	fields := map[string]reflect.Value{}
+	collectFields(v, fields)
+
+	for key, value := range values {
+		name := prefix + key
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			problem := fmt.Sprintf("unknown key %s", name)
+			if suggestion := closest(strings.ToLower(key), fields); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %s%s?)", prefix, suggestion)
+			}
+			problems = append(problems, problem)
+			continue
+		}
+
+		problems = append(problems, decodeValue(value, field, name)...)
+	}
+
+	return problems
+}
+
+// collectFields collects the settable fields by config tag or snake case name, embedded structs are flattened.
+func collectFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Tag.Get("config")
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), fields)
+			continue
+		}
+
+		if name == "" {
+			name = snakeCase(f.Name)
+		}
+		fields[strings.ToLower(name)] = v.Field(i)
+	}
+}
+
+func decodeValue(value any, field reflect.Value, name string) (problems []string) {
+	mismatch := func() []string {
+		return []string{fmt.Sprintf("invalid %s: expected %s, got %T", name, field.Type(), value)}
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	if field.Type() == durationType {
+		switch x := value.(type) {
+		case string:
+			d, err := time.ParseDuration(x)
+			if err != nil {
+				return []string{fmt.Sprintf("invalid %s: %v (such as 30s, 5m)", name, err)}
+			}
+			field.SetInt(int64(d))
+		case int:
+			// seconds
+			field.SetInt(int64(time.Duration(x) * time.Second))
+		default:
+			return mismatch()
+		}
+
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		x, ok := value.(string)
+		if !ok {
+			return mismatch()
+		}
+		field.SetString(x)
+	case reflect.Bool:
+		x, ok := value.(bool)
+		if !ok {
+			return mismatch()
+		}
+		field.SetBool(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, ok := value.(int)
+		if !ok {
+			return mismatch()
+		}
+		field.SetInt(int64(x))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, ok := value.(int)
+		if !ok || x < 0 {
+			return mismatch()
+		}
+		field.SetUint(uint64(x))
+	case reflect.Float32, reflect.Float64:
+		switch x := value.(type) {
+		case float64:
+			field.SetFloat(x)
+		case int:
+			field.SetFloat(float64(x))
+		default:
+			return mismatch()
+		}
+	case reflect.Struct:
+		x, ok := value.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		return decodeConfig(x, field, name+".")
+	case reflect.Ptr:
+		if field.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return decodeValue(value, field.Elem(), name)
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return mismatch()
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			problems = append(problems, decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", name, i))...)
+		}
+		field.Set(slice)
+	}
+
+	return problems
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	buf := &strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			buf.WriteRune('_')
+		}
+		buf.WriteRune(unicode.ToLower(r))
+	}
+
+	return buf.String()
+}
+
+// closest returns the field name with the smallest edit distance (at most 3) to key.
+func closest(key string, fields map[string]reflect.Value) string {
+	best, bestDistance := "", 4
+	for name := range fields {
+		if d := distance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+
+	return best
+}
+
+// distance is the levenshtein distance of a and b.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,140 @@
+// Package health provides the checks of the application dependencies and environment,
+// such as redis connectivity, tls certificate expiry and port availability.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the status of a check.
+type Status string
+
+// Statuses of checks.
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// DefaultTimeout is the default timeout of a check.
+var DefaultTimeout = 5 * time.Second
+
+// Check is a named health check.
+type Check struct {
+	Name string
+	// Run returns nil if healthy, *Problem for warnings or actionable fixes.
+	Run func(ctx context.Context) error
+	// Timeout is the timeout of Run, default: DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Problem is the error of a check with the actionable fix.
+type Problem struct {
+	Status  Status
+	Message string
+	Fix     string
+}
+
+func (p *Problem) Error() string {
+	return p.Message
+}
+
+// Warnf returns the warning problem with the fix.
+func Warnf(fix string, format string, args ...any) error {
+	return &Problem{Status: StatusWarn, Message: fmt.Sprintf(format, args...), Fix: fix}
+}
+
+// Failf returns the failure problem with the fix.
+func Failf(fix string, format string, args ...any) error {
+	return &Problem{Status: StatusFail, Message: fmt.Sprintf(format, args...), Fix: fix}
+}
+
+// Result is the result of a check.
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Fix      string        `json:"fix,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Run runs the checks concurrently, results are in the order of checks.
+func Run(ctx context.Context, checks ...Check) []Result {
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Healthy returns true if no check failed, warnings are healthy.
+func Healthy(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+
+	return true
+}
+
+func runCheck(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = Failf("", "timeout after %s", timeout)
+	}
+
+	result := Result{
+		Name:     check.Name,
+		Status:   StatusOK,
+		Duration: time.Since(start),
+	}
+	if err == nil {
+		return result
+	}
+
+	result.Status = StatusFail
+	result.Message = err.Error()
+
+	var problem *Problem
+	if errors.As(err, &problem) {
+		result.Status = problem.Status
+		result.Fix = problem.Fix
+	}
+
+	return result
+}
@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/textproto"
+	"strings"
+)
+
+// GenerateClient generates the typed go client of the document,
+// including the request/response structs, auth options and the typed Error of failed responses.
+func GenerateClient(doc *Document, cfg ...*GenerateConfig) ([]byte, error) {
+	cfgX := &GenerateConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Package == "" {
+		cfgX.Package = "api"
+	}
+
+	g := newGenerator(doc)
+	for _, path := range []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "reflect", "strings"} {
+		g.imports[path] = true
+	}
+	g.generateSchemas()
+
+	methods := &bytes.Buffer{}
+	for _, m := range doc.Methods() {
+		name := OperationName(m)
+		requestType := g.generateRequest(name, m)
+		_, responseType := g.responseType(name, m.Operation)
+
+		summary := m.Operation.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("%s %s", m.Method, m.Path)
+		}
+		fmt.Fprintf(methods, "// %s %s\n", name, oneLine(summary))
+		if responseType == "" {
+			fmt.Fprintf(methods, "func (c *Client) %s(ctx context.Context, req *%s) error {\n", name, requestType)
+		} else {
+			fmt.Fprintf(methods, "func (c *Client) %s(ctx context.Context, req *%s) (%s, error) {\n", name, requestType, responseType)
+		}
+		fmt.Fprintf(methods, "\tif req == nil {\n\t\treq = &%s{}\n\t}\n\n", requestType)
+
+		fmt.Fprintf(methods, "\tpath := %s\n", clientPath(m))
+		methods.WriteString("\tquery := url.Values{}\n")
+		methods.WriteString("\theader := http.Header{}\n")
+		for _, p := range m.Parameters {
+			switch p.In {
+			case "query":
+				fmt.Fprintf(methods, "\taddValue(query, %q, req.Query.%s)\n", p.Name, GoName(p.Name))
+			case "header":
+				fmt.Fprintf(methods, "\taddValue(url.Values(header), %q, req.Header.%s)\n", textproto.CanonicalMIMEHeaderKey(p.Name), GoName(p.Name))
+			}
+		}
+
+		body := "nil"
+		if m.Operation.RequestBody != nil && JSONSchema(m.Operation.RequestBody.Content) != nil {
+			body = "req.Body"
+		}
+
+		if responseType == "" {
+			fmt.Fprintf(methods, "\n\treturn c.do(ctx, %q, path, query, header, %s, nil)\n", m.Method, body)
+		} else {
+			fmt.Fprintf(methods, "\n\tvar res %s\n", responseType)
+			fmt.Fprintf(methods, "\tif err := c.do(ctx, %q, path, query, header, %s, &res); err != nil {\n\t\treturn res, err\n\t}\n\n", m.Method, body)
+			methods.WriteString("\treturn res, nil\n")
+		}
+		methods.WriteString("}\n\n")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by zoox gen client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", cfgX.Package)
+	writeImports(buf, g.imports)
+	for _, t := range g.types {
+		buf.WriteString(t + "\n")
+	}
+	buf.WriteString(clientRuntime + "\n")
+	buf.WriteString(methods.String())
+
+	return formatSource(buf.Bytes())
+}
+
+// clientPath returns the go expression of the request path, path parameters are escaped.
+func clientPath(m *Method) string {
+	declared := map[string]bool{}
+	for _, p := range m.Parameters {
+		if p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+
+	parts := []string{}
+	literal := ""
+	for _, segment := range strings.Split(m.Path, "/") {
+		if segment == "" {
+			continue
+		}
+
+		literal += "/"
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && declared[segment[1:len(segment)-1]] {
+			parts = append(parts, fmt.Sprintf("%q", literal))
+			parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(req.Params.%s))", GoName(segment[1:len(segment)-1])))
+			literal = ""
+			continue
+		}
+
+		literal += segment
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+const clientRuntime = `// Client is the api client of the openapi document.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// ClientOption is the option of Client.
+type ClientOption func(c *Client)
+
+// WithHTTPClient sets the http client, default: http.DefaultClient.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithHeader sets the header of all requests.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithBearerToken sets the bearer token of all requests.
+func WithBearerToken(token string) ClientOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithBasicAuth sets the basic auth of all requests.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(c *Client) {
+		r := &http.Request{Header: http.Header{}}
+		r.SetBasicAuth(username, password)
+		c.header.Set("Authorization", r.Header.Get("Authorization"))
+	}
+}
+
+// WithAPIKey sets the api key header of all requests, such as X-API-Key.
+func WithAPIKey(header, key string) ClientOption {
+	return WithHeader(header, key)
+}
+
+// NewClient creates the api client with the base url, such as https://api.example.com.
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is the error of non-2xx responses, with the code and message of zoox.Fail.
+type Error struct {
+	Status  int    ` + "`json:\"-\"`" + `
+	Code    int    ` + "`json:\"code\"`" + `
+	Message string ` + "`json:\"message\"`" + `
+	Body    []byte ` + "`json:\"-\"`" + `
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api error: status %d", e.Status)
+	}
+
+	return fmt.Sprintf("api error: status %d, code %d: %s", e.Status, e.Code, e.Message)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body any, res any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		e := &Error{Status: response.StatusCode, Body: data}
+		json.Unmarshal(data, e)
+		return e
+	}
+
+	if res == nil || len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("failed to decode response body: %v", err)
+	}
+
+	return nil
+}
+
+// addValue adds the non-zero value, slices are added as multiple values.
+func addValue(values url.Values, key string, value any) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.IsZero() {
+		return
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			values.Add(key, fmt.Sprint(v.Index(i).Interface()))
+		}
+		return
+	}
+
+	values.Add(key, fmt.Sprint(value))
+}
+`
@@ -0,0 +1,206 @@
+package openapi
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Faker generates the example values of schemas, used by the mock server.
+type Faker struct {
+	doc  *Document
+	rand *rand.Rand
+	// Dynamic generates random values instead of the examples of the document.
+	Dynamic bool
+}
+
+// NewFaker creates the faker of the document with the seed.
+func NewFaker(doc *Document, seed int64) *Faker {
+	return &Faker{
+		doc:  doc,
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Example returns the example of the media, the example of the media first, then the schema.
+func (f *Faker) Example(media *MediaType) any {
+	if media == nil {
+		return nil
+	}
+
+	if media.Example != nil && !f.Dynamic {
+		return normalizeExample(media.Example)
+	}
+
+	return f.Value(media.Schema, "", 0)
+}
+
+// Value returns the example value of the schema, name is the property name used as hint, such as email.
+func (f *Faker) Value(s *Schema, name string, depth int) any {
+	s = f.doc.Resolve(s)
+	if s == nil || depth > 8 {
+		return nil
+	}
+
+	if !f.Dynamic {
+		if s.Example != nil {
+			return normalizeExample(s.Example)
+		}
+		if s.Default != nil {
+			return normalizeExample(s.Default)
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		return normalizeExample(s.Enum[f.rand.Intn(len(s.Enum))])
+	}
+
+	switch s.Type {
+	case "string":
+		return f.stringValue(s, name)
+	case "integer":
+		return f.numberValue(s, true)
+	case "number":
+		return f.numberValue(s, false)
+	case "boolean":
+		return f.rand.Intn(2) == 1
+	case "array":
+		n := 1 + f.rand.Intn(3)
+		if s.MinItems != nil && n < *s.MinItems {
+			n = *s.MinItems
+		}
+		if s.MaxItems != nil && n > *s.MaxItems {
+			n = *s.MaxItems
+		}
+
+		items := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			items = append(items, f.Value(s.Items, name, depth+1))
+		}
+		return items
+	case "object", "":
+		object := map[string]any{}
+
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			object[key] = f.Value(s.Properties[key], key, depth+1)
+		}
+		return object
+	}
+
+	return nil
+}
+
+func (f *Faker) stringValue(s *Schema, name string) string {
+	switch s.Format {
+	case "date-time":
+		return time.Now().Add(-time.Duration(f.rand.Intn(30*24)) * time.Hour).UTC().Format(time.RFC3339)
+	case "date":
+		return time.Now().AddDate(0, 0, -f.rand.Intn(365)).Format("2006-01-02")
+	case "email":
+		return f.email()
+	case "uuid":
+		return f.uuid()
+	case "uri", "url":
+		return fmt.Sprintf("https://example.com/%s", f.word())
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "email"):
+		return f.email()
+	case lower == "id" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(name, "Id"):
+		return f.uuid()
+	case strings.Contains(lower, "url") || strings.Contains(lower, "avatar"):
+		return fmt.Sprintf("https://example.com/%s", f.word())
+	case strings.Contains(lower, "name"):
+		return fakeNames[f.rand.Intn(len(fakeNames))]
+	case strings.Contains(lower, "phone"):
+		return fmt.Sprintf("+1-555-%04d", f.rand.Intn(10000))
+	}
+
+	value := f.word()
+	if s.MinLength != nil {
+		for len(value) < *s.MinLength {
+			value += f.word()
+		}
+	}
+	if s.MaxLength != nil && len(value) > *s.MaxLength {
+		value = value[:*s.MaxLength]
+	}
+
+	return value
+}
+
+func (f *Faker) numberValue(s *Schema, integer bool) any {
+	min, max := 1.0, 1000.0
+	if s.Minimum != nil {
+		min = *s.Minimum
+	}
+	if s.Maximum != nil {
+		max = *s.Maximum
+	}
+	if max < min {
+		max = min
+	}
+
+	n := min + f.rand.Float64()*(max-min)
+	if integer {
+		return int64(n)
+	}
+
+	return float64(int64(n*100)) / 100
+}
+
+func (f *Faker) word() string {
+	return fakeWords[f.rand.Intn(len(fakeWords))]
+}
+
+func (f *Faker) email() string {
+	return fmt.Sprintf("%s%d@example.com", strings.ToLower(strings.Fields(fakeNames[f.rand.Intn(len(fakeNames))])[0]), f.rand.Intn(100))
+}
+
+func (f *Faker) uuid() string {
+	b := make([]byte, 16)
+	f.rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// normalizeExample copies the example into json compatible values, yaml may decode nested maps as map[any]any.
+func normalizeExample(v any) any {
+	switch x := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for key, value := range x {
+			m[fmt.Sprint(key)] = normalizeExample(value)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for key, value := range x {
+			m[key] = normalizeExample(value)
+		}
+		return m
+	case []any:
+		items := make([]any, len(x))
+		for i, value := range x {
+			items[i] = normalizeExample(value)
+		}
+		return items
+	}
+
+	return v
+}
+
+var fakeNames = []string{"Alice Smith", "Bob Johnson", "Carol Williams", "David Brown", "Eve Davis", "Frank Miller", "Grace Wilson", "Henry Moore"}
+
+var fakeWords = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima"}
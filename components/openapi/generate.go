@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// GenerateConfig is the config of the code generators.
+type GenerateConfig struct {
+	// Package is the go package name of the generated code, default: api.
+	Package string
+}
+
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "RPC": true, "SQL": true, "TLS": true, "UI": true, "URI": true,
+	"URL": true, "UUID": true, "XML": true,
+}
+
+// GoName converts the name to an exported go identifier, such as user_id => UserID.
+func GoName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	buf := &strings.Builder{}
+	for _, word := range words {
+		// split camelCase words
+		start := 0
+		runes := []rune(word)
+		for i := 1; i <= len(runes); i++ {
+			if i == len(runes) || (unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1])) {
+				part := string(runes[start:i])
+				if upper := strings.ToUpper(part); commonInitialisms[upper] {
+					buf.WriteString(upper)
+				} else {
+					// the first rune, not byte, for non-ascii names
+					r := []rune(part)
+					r[0] = unicode.ToUpper(r[0])
+					buf.WriteString(string(r))
+				}
+				start = i
+			}
+		}
+	}
+
+	name = buf.String()
+	if name == "" {
+		return "X"
+	}
+	// not exported, such as digits or the letters without case
+	if first := []rune(name)[0]; !unicode.IsUpper(first) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// OperationName returns the go name of the operation, using operationId first.
+func OperationName(m *Method) string {
+	if m.Operation.OperationID != "" {
+		return GoName(m.Operation.OperationID)
+	}
+
+	name := GoName(strings.ToLower(m.Method))
+	for _, part := range strings.Split(m.Path, "/") {
+		if part == "" {
+			continue
+		}
+
+		if strings.HasPrefix(part, "{") {
+			name += "By" + GoName(part[1:len(part)-1])
+		} else {
+			name += GoName(part)
+		}
+	}
+
+	return name
+}
+
+type generator struct {
+	doc *Document
+	// types are the named types generated in order
+	types []string
+	seen  map[string]bool
+	//
+	imports map[string]bool
+}
+
+func newGenerator(doc *Document) *generator {
+	return &generator{
+		doc:     doc,
+		seen:    map[string]bool{},
+		imports: map[string]bool{},
+	}
+}
+
+// goType returns the go type of the schema, named types are generated for inline objects with nameHint.
+func (g *generator) goType(s *Schema, nameHint string) string {
+	if s == nil {
+		return "any"
+	}
+
+	if s.Ref != "" {
+		return GoName(RefName(s.Ref))
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items, nameHint+"Item")
+	case "object", "":
+		if len(s.Properties) == 0 {
+			if s.AdditionalProperties != nil {
+				return "map[string]" + g.goType(s.AdditionalProperties, nameHint+"Value")
+			}
+
+			return "map[string]any"
+		}
+
+		g.generateStruct(nameHint, s)
+		return nameHint
+	}
+
+	return "any"
+}
+
+// generateStruct generates the named struct type of the object schema.
+func (g *generator) generateStruct(name string, s *Schema) {
+	if g.seen[name] {
+		return
+	}
+	g.seen[name] = true
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+	writeComment(buf, name, s.Description)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, key := range keys {
+		prop := s.Properties[key]
+		fieldName := GoName(key)
+		typ := g.goType(prop, name+fieldName)
+		tag := key
+		if !required[key] {
+			tag += ",omitempty"
+			if prop != nil && prop.Ref != "" {
+				typ = "*" + typ
+			}
+		}
+
+		if prop != nil && prop.Description != "" {
+			fmt.Fprintf(buf, "\t// %s\n", oneLine(prop.Description))
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", fieldName, typ, tag)
+	}
+	buf.WriteString("}\n")
+
+	g.types = append(g.types, buf.String())
+}
+
+// generateSchemas generates the types of components.schemas.
+func (g *generator) generateSchemas() {
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := g.doc.Components.Schemas[name]
+		goName := GoName(name)
+		if s.Type == "object" || (s.Type == "" && len(s.Properties) > 0) {
+			g.generateStruct(goName, s)
+			continue
+		}
+
+		if g.seen[goName] {
+			continue
+		}
+		g.seen[goName] = true
+
+		buf := &bytes.Buffer{}
+		writeComment(buf, goName, s.Description)
+		fmt.Fprintf(buf, "type %s %s\n", goName, g.goType(s, goName+"Value"))
+		g.types = append(g.types, buf.String())
+	}
+}
+
+func writeComment(buf *bytes.Buffer, name, description string) {
+	if description == "" {
+		fmt.Fprintf(buf, "// %s is generated from the openapi document.\n", name)
+		return
+	}
+
+	fmt.Fprintf(buf, "// %s %s\n", name, oneLine(description))
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func formatSource(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, fmt.Errorf("failed to format generated code: %v", err)
+	}
+
+	return formatted, nil
+}
+
+func writeImports(buf *bytes.Buffer, imports map[string]bool) {
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	// standard library first
+	sort.Slice(paths, func(i, j int) bool {
+		iStd := !strings.Contains(strings.Split(paths[i], "/")[0], ".")
+		jStd := !strings.Contains(strings.Split(paths[j], "/")[0], ".")
+		if iStd != jStd {
+			return iStd
+		}
+
+		return paths[i] < paths[j]
+	})
+
+	buf.WriteString("import (\n")
+	thirdParty := false
+	for _, path := range paths {
+		isStd := !strings.Contains(strings.Split(path, "/")[0], ".")
+		if !isStd && !thirdParty {
+			thirdParty = true
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n\n")
+}
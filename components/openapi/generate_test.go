@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"user_id":      "UserID",
+		"userId":       "UserID",
+		"api-url":      "APIURL",
+		"X-Request-ID": "XRequestID",
+		"2fa_enabled":  "X2faEnabled",
+		"ürün_id":      "ÜrünID",
+		"ölçü":         "Ölçü",
+		"用户":           "X用户",
+		"":             "X",
+		"-":            "X",
+	}
+
+	for name, expected := range cases {
+		assert.Equal(t, expected, GoName(name), name)
+	}
+}
+
+func TestSuccessResponse(t *testing.T) {
+	ok, created, fallback := &Response{}, &Response{}, &Response{}
+
+	status, response := (&Operation{Responses: map[string]*Response{"404": {}, "201": created, "200": ok}}).SuccessResponse()
+	assert.Equal(t, "200", status)
+	assert.Same(t, ok, response)
+
+	status, response = (&Operation{Responses: map[string]*Response{"default": fallback, "2xx": created}}).SuccessResponse()
+	assert.Equal(t, "2xx", status)
+	assert.Same(t, created, response)
+
+	status, response = (&Operation{Responses: map[string]*Response{"default": fallback, "400": {}}}).SuccessResponse()
+	assert.Equal(t, "default", status)
+	assert.Same(t, fallback, response)
+
+	status, response = (&Operation{Responses: map[string]*Response{"400": {}}}).SuccessResponse()
+	assert.Equal(t, "", status)
+	assert.Nil(t, response)
+}
+
+// TestGenerateGolden compares the generated code of testdata/*.yaml with the golden files,
+// run `go test ./components/openapi -update` to update them.
+func TestGenerateGolden(t *testing.T) {
+	specs, err := filepath.Glob("testdata/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generators := []struct {
+		ext      string
+		generate func(doc *Document) ([]byte, error)
+	}{
+		{".server.go.golden", func(doc *Document) ([]byte, error) { return GenerateServer(doc) }},
+		{".client.go.golden", func(doc *Document) ([]byte, error) { return GenerateClient(doc) }},
+		{".ts.golden", GenerateTypeScriptClient},
+	}
+
+	for _, spec := range specs {
+		doc, err := Load(spec)
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+
+		for _, g := range generators {
+			golden := strings.TrimSuffix(spec, ".yaml") + g.ext
+			t.Run(filepath.Base(golden), func(t *testing.T) {
+				code, err := g.generate(doc)
+				if err != nil {
+					t.Fatalf("failed to generate: %v\n%s", err, code)
+				}
+
+				if *update {
+					if err := os.WriteFile(golden, code, 0644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+
+				expected, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, string(expected), string(code))
+			})
+		}
+	}
+}
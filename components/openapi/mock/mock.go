@@ -0,0 +1,205 @@
+// Package mock serves the mock responses of an openapi document,
+// with the examples of the document or generated fake values, used by frontend development.
+package mock
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/openapi"
+)
+
+// Config is the config of the mock server.
+type Config struct {
+	// Latency is the delay of each response.
+	Latency time.Duration
+	// Jitter is the max random delay added to Latency.
+	Jitter time.Duration
+	// ErrorRate is the ratio (0-1) of injected error responses.
+	ErrorRate float64
+	// ErrorStatus is the status of injected errors, default: 500.
+	ErrorStatus int
+	// Dynamic generates random values instead of the examples of the document.
+	Dynamic bool
+	// Seed is the seed of fake values, default: current time.
+	Seed int64
+}
+
+type mock struct {
+	doc *openapi.Document
+	cfg *Config
+	//
+	sync.Mutex
+	faker *openapi.Faker
+	rand  *rand.Rand
+}
+
+// New creates the zoox app serving the mock responses of the document.
+func New(doc *openapi.Document, cfg ...*Config) *zoox.Application {
+	app := zoox.New()
+	Register(app.RouterGroup, doc, cfg...)
+	return app
+}
+
+// Register registers the mock routes of the document into the router group.
+//
+// The response can be selected with the Prefer header:
+//
+//	Prefer: code=404       responds the documented 404 response
+//	Prefer: dynamic=true   responds random values instead of examples
+func Register(g *zoox.RouterGroup, doc *openapi.Document, cfg ...*Config) {
+	cfgX := &Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.ErrorStatus == 0 {
+		cfgX.ErrorStatus = http.StatusInternalServerError
+	}
+	if cfgX.Seed == 0 {
+		cfgX.Seed = time.Now().UnixNano()
+	}
+
+	m := &mock{
+		doc:   doc,
+		cfg:   cfgX,
+		faker: openapi.NewFaker(doc, cfgX.Seed),
+		rand:  rand.New(rand.NewSource(cfgX.Seed)),
+	}
+	m.faker.Dynamic = cfgX.Dynamic
+
+	for _, method := range doc.Methods() {
+		operation := method.Operation
+		path := openapi.ZooxPath(method.Path)
+		switch method.Method {
+		case http.MethodGet:
+			g.Get(path, m.handler(operation))
+		case http.MethodPost:
+			g.Post(path, m.handler(operation))
+		case http.MethodPut:
+			g.Put(path, m.handler(operation))
+		case http.MethodPatch:
+			g.Patch(path, m.handler(operation))
+		case http.MethodDelete:
+			g.Delete(path, m.handler(operation))
+		case http.MethodHead:
+			g.Head(path, m.handler(operation))
+		case http.MethodOptions:
+			g.Options(path, m.handler(operation))
+		}
+	}
+}
+
+func (m *mock) handler(operation *openapi.Operation) zoox.HandlerFunc {
+	return func(ctx *zoox.Context) {
+		prefer := parsePrefer(ctx.Header().Get("Prefer"))
+
+		if delay := m.delay(); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Request.Context().Done():
+				return
+			}
+		}
+
+		ctx.SetHeader("X-Mock", "true")
+
+		code, response := operation.SuccessResponse()
+		if preferred, ok := prefer["code"]; ok {
+			if r, exists := operation.Responses[preferred]; exists {
+				code, response = preferred, r
+			}
+		} else if m.injectError() {
+			code = strconv.Itoa(m.cfg.ErrorStatus)
+			response = operation.Responses[code]
+			if response == nil {
+				ctx.JSON(m.cfg.ErrorStatus, zoox.H{
+					"code":    m.cfg.ErrorStatus,
+					"message": "mock: injected error",
+				})
+				return
+			}
+		}
+
+		status := statusOf(code)
+		if response == nil {
+			ctx.Status(status)
+			return
+		}
+
+		var media *openapi.MediaType
+		for contentType, one := range response.Content {
+			if strings.Contains(contentType, "json") {
+				media = one
+				break
+			}
+		}
+		if media == nil {
+			ctx.Status(status)
+			return
+		}
+
+		m.Lock()
+		dynamic := m.faker.Dynamic
+		if prefer["dynamic"] == "true" {
+			m.faker.Dynamic = true
+		}
+		body := m.faker.Example(media)
+		m.faker.Dynamic = dynamic
+		m.Unlock()
+
+		ctx.JSON(status, body)
+	}
+}
+
+func (m *mock) delay() time.Duration {
+	delay := m.cfg.Latency
+	if m.cfg.Jitter > 0 {
+		m.Lock()
+		delay += time.Duration(m.rand.Int63n(int64(m.cfg.Jitter)))
+		m.Unlock()
+	}
+
+	return delay
+}
+
+func (m *mock) injectError() bool {
+	if m.cfg.ErrorRate <= 0 {
+		return false
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	return m.rand.Float64() < m.cfg.ErrorRate
+}
+
+// statusOf returns the status of the response code, such as 2XX or default => 200.
+func statusOf(code string) int {
+	if status, err := strconv.Atoi(code); err == nil {
+		return status
+	}
+
+	if len(code) == 3 && strings.HasSuffix(strings.ToUpper(code), "XX") {
+		status, _ := strconv.Atoi(code[:1] + "00")
+		return status
+	}
+
+	return http.StatusOK
+}
+
+// parsePrefer parses the Prefer header, such as code=404, dynamic=true.
+func parsePrefer(value string) map[string]string {
+	prefer := map[string]string{}
+	for _, part := range strings.Split(value, ",") {
+		key, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if key != "" {
+			prefer[strings.ToLower(key)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return prefer
+}
@@ -0,0 +1,295 @@
+package openapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi" json:"openapi"`
+	Info       Info                 `yaml:"info" json:"info"`
+	Servers    []Server             `yaml:"servers,omitempty" json:"servers,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths" json:"paths"`
+	Components Components           `yaml:"components,omitempty" json:"components,omitempty"`
+}
+
+// Info is the metadata of the document.
+type Info struct {
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Version     string `yaml:"version" json:"version"`
+}
+
+// Server is the server of the document.
+type Server struct {
+	URL         string `yaml:"url" json:"url"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// Components holds the reusable objects of the document.
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas,omitempty" json:"schemas,omitempty"`
+}
+
+// PathItem holds the operations of a path.
+type PathItem struct {
+	Get     *Operation `yaml:"get,omitempty" json:"get,omitempty"`
+	Post    *Operation `yaml:"post,omitempty" json:"post,omitempty"`
+	Put     *Operation `yaml:"put,omitempty" json:"put,omitempty"`
+	Patch   *Operation `yaml:"patch,omitempty" json:"patch,omitempty"`
+	Delete  *Operation `yaml:"delete,omitempty" json:"delete,omitempty"`
+	Head    *Operation `yaml:"head,omitempty" json:"head,omitempty"`
+	Options *Operation `yaml:"options,omitempty" json:"options,omitempty"`
+	//
+	Parameters []*Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// Operation is a single API operation on a path.
+type Operation struct {
+	OperationID string               `yaml:"operationId,omitempty" json:"operationId,omitempty"`
+	Summary     string               `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string             `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	RequestBody *RequestBody         `yaml:"requestBody,omitempty" json:"requestBody,omitempty"`
+	Responses   map[string]*Response `yaml:"responses,omitempty" json:"responses,omitempty"`
+}
+
+// Parameter is an operation parameter.
+type Parameter struct {
+	Name        string  `yaml:"name" json:"name"`
+	In          string  `yaml:"in" json:"in"`
+	Description string  `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty" json:"required,omitempty"`
+	Schema      *Schema `yaml:"schema,omitempty" json:"schema,omitempty"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Description string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool                  `yaml:"required,omitempty" json:"required,omitempty"`
+	Content     map[string]*MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string                `yaml:"description" json:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+// MediaType is the content of a request body or response.
+type MediaType struct {
+	Schema  *Schema `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Example any     `yaml:"example,omitempty" json:"example,omitempty"`
+}
+
+// Schema is the JSON schema subset used by OpenAPI.
+type Schema struct {
+	Ref         string             `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Type        string             `yaml:"type,omitempty" json:"type,omitempty"`
+	Format      string             `yaml:"format,omitempty" json:"format,omitempty"`
+	Description string             `yaml:"description,omitempty" json:"description,omitempty"`
+	Properties  map[string]*Schema `yaml:"properties,omitempty" json:"properties,omitempty"`
+	Required    []string           `yaml:"required,omitempty" json:"required,omitempty"`
+	Items       *Schema            `yaml:"items,omitempty" json:"items,omitempty"`
+	Enum        []any              `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Nullable    bool               `yaml:"nullable,omitempty" json:"nullable,omitempty"`
+	Example     any                `yaml:"example,omitempty" json:"example,omitempty"`
+	Default     any                `yaml:"default,omitempty" json:"default,omitempty"`
+	//
+	Minimum   *float64 `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Maximum   *float64 `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	MinLength *int     `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength *int     `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	Pattern   string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	MinItems  *int     `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems  *int     `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	//
+	AdditionalProperties *Schema `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+}
+
+// Method is an http method with its operation.
+type Method struct {
+	Method    string
+	Path      string
+	Operation *Operation
+	// Parameters are the path level and operation level parameters merged.
+	Parameters []*Parameter
+}
+
+// Load loads the document from a yaml or json file, or the url of a running app, such as http://127.0.0.1:8080/openapi.json.
+func Load(filepath string) (*Document, error) {
+	if strings.HasPrefix(filepath, "http://") || strings.HasPrefix(filepath, "https://") {
+		return loadURL(filepath)
+	}
+
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi document(%s): %v", filepath, err)
+	}
+
+	return Parse(data)
+}
+
+func loadURL(url string) (*Document, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch openapi document(%s): %v", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch openapi document(%s): status %d", url, response.StatusCode)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi document(%s): %v", url, err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses the document from yaml or json bytes.
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %v", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported openapi version: %s (only 3.x is supported)", doc.OpenAPI)
+	}
+
+	return doc, nil
+}
+
+// Methods returns all operations of the document, sorted by path and method.
+func (d *Document) Methods() []*Method {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	methods := []*Method{}
+	for _, path := range paths {
+		item := d.Paths[path]
+		if item == nil {
+			continue
+		}
+
+		for _, m := range []struct {
+			method    string
+			operation *Operation
+		}{
+			{"GET", item.Get},
+			{"POST", item.Post},
+			{"PUT", item.Put},
+			{"PATCH", item.Patch},
+			{"DELETE", item.Delete},
+			{"HEAD", item.Head},
+			{"OPTIONS", item.Options},
+		} {
+			if m.operation == nil {
+				continue
+			}
+
+			methods = append(methods, &Method{
+				Method:     m.method,
+				Path:       path,
+				Operation:  m.operation,
+				Parameters: mergeParameters(item.Parameters, m.operation.Parameters),
+			})
+		}
+	}
+
+	return methods
+}
+
+// Resolve returns the schema referenced by $ref, or the schema itself.
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		name := RefName(s.Ref)
+		next, ok := d.Components.Schemas[name]
+		if !ok {
+			return s
+		}
+		s = next
+	}
+
+	return s
+}
+
+// RefName returns the schema name of $ref, such as #/components/schemas/User => User.
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// ZooxPath converts the openapi path to the zoox route path, such as /users/{id} => /users/:id.
+func ZooxPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			parts[i] = ":" + part[1:len(part)-1]
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// JSONSchema returns the json schema of the content, if exists.
+func JSONSchema(content map[string]*MediaType) *Schema {
+	for contentType, media := range content {
+		if strings.Contains(contentType, "json") && media != nil {
+			return media.Schema
+		}
+	}
+
+	return nil
+}
+
+// SuccessResponse returns the first 2xx response with its status, such as 200, 201 or 2XX,
+// or the default response if there is no 2xx response.
+func (o *Operation) SuccessResponse() (status string, response *Response) {
+	codes := make([]string, 0, len(o.Responses))
+	for code := range o.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code, o.Responses[code]
+		}
+	}
+
+	if response, ok := o.Responses["default"]; ok {
+		return "default", response
+	}
+
+	return "", nil
+}
+
+func mergeParameters(pathLevel, operationLevel []*Parameter) []*Parameter {
+	parameters := []*Parameter{}
+	overridden := map[string]bool{}
+	for _, p := range operationLevel {
+		overridden[p.In+":"+p.Name] = true
+	}
+
+	for _, p := range pathLevel {
+		if !overridden[p.In+":"+p.Name] {
+			parameters = append(parameters, p)
+		}
+	}
+
+	return append(parameters, operationLevel...)
+}
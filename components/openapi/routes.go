@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-zoox/zoox/components/router"
+)
+
+// RouteSpec is a route of the application to build the document, see FromRoutes.
+type RouteSpec struct {
+	Method  string
+	Path    string
+	Summary string
+	// RequestExample is the example of the json request body.
+	RequestExample any
+	// ResponseExample is the example of the json response body.
+	ResponseExample any
+}
+
+// FromRoutes builds the document of the routes, the schemas are inferred from the examples,
+// so the mock server responds the examples.
+func FromRoutes(info Info, routes []RouteSpec) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
+	})
+
+	for _, route := range routes {
+		path := OpenAPIPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		operation := &Operation{
+			Summary:   route.Summary,
+			Responses: map[string]*Response{},
+		}
+
+		for _, part := range strings.Split(route.Path, "/") {
+			if name, ok := pathParam(part); ok {
+				operation.Parameters = append(operation.Parameters, &Parameter{
+					Name:     name,
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+			}
+		}
+
+		if route.RequestExample != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content: map[string]*MediaType{
+					"application/json": mediaOf(route.RequestExample),
+				},
+			}
+		}
+
+		response := &Response{Description: "OK"}
+		if route.ResponseExample != nil {
+			response.Content = map[string]*MediaType{
+				"application/json": mediaOf(route.ResponseExample),
+			}
+		}
+		operation.Responses["200"] = response
+
+		switch strings.ToUpper(route.Method) {
+		case http.MethodGet:
+			item.Get = operation
+		case http.MethodPost:
+			item.Post = operation
+		case http.MethodPut:
+			item.Put = operation
+		case http.MethodPatch:
+			item.Patch = operation
+		case http.MethodDelete:
+			item.Delete = operation
+		case http.MethodHead:
+			item.Head = operation
+		case http.MethodOptions:
+			item.Options = operation
+		}
+	}
+
+	return doc
+}
+
+// OpenAPIPath converts the zoox route path to the openapi path, such as /users/:id => /users/{id}.
+func OpenAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if name, ok := pathParam(part); ok {
+			parts[i] = "{" + name + "}"
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func pathParam(part string) (string, bool) {
+	name, _, ok := router.ParseParam(part)
+	if !ok || name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+// mediaOf returns the json media of the example, with the schema inferred from it.
+func mediaOf(example any) *MediaType {
+	// the json form of the example, so that structs follow their json tags
+	var value any
+	if data, err := json.Marshal(example); err == nil {
+		if err := json.Unmarshal(data, &value); err != nil {
+			value = nil
+		}
+	}
+
+	return &MediaType{
+		Schema:  SchemaOf(value),
+		Example: value,
+	}
+}
+
+// SchemaOf infers the schema of the json value, such as decoded by encoding/json.
+func SchemaOf(value any) *Schema {
+	switch v := value.(type) {
+	case nil:
+		return &Schema{Nullable: true}
+	case bool:
+		return &Schema{Type: "boolean"}
+	case float64:
+		if v == float64(int64(v)) {
+			return &Schema{Type: "integer"}
+		}
+		return &Schema{Type: "number"}
+	case string:
+		return &Schema{Type: "string"}
+	case []any:
+		schema := &Schema{Type: "array", Items: &Schema{}}
+		if len(v) > 0 {
+			schema.Items = SchemaOf(v[0])
+		}
+		return schema
+	case map[string]any:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for key, one := range v {
+			schema.Properties[key] = SchemaOf(one)
+		}
+		return schema
+	}
+
+	return &Schema{}
+}
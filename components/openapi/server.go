@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GenerateServer generates the zoox server stub of the document,
+// including the typed request/response structs, the Handler interface to implement
+// and the Register function to mount the routes on a zoox router group.
+func GenerateServer(doc *Document, cfg ...*GenerateConfig) ([]byte, error) {
+	cfgX := &GenerateConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Package == "" {
+		cfgX.Package = "api"
+	}
+
+	g := newGenerator(doc)
+	g.imports["net/http"] = true
+	g.imports["github.com/go-zoox/zoox"] = true
+	g.generateSchemas()
+
+	handler := &bytes.Buffer{}
+	register := &bytes.Buffer{}
+
+	handler.WriteString("// Handler is the interface to implement the operations of the openapi document.\n")
+	handler.WriteString("type Handler interface {\n")
+
+	register.WriteString("// Register registers the routes of the openapi document into the router group.\n")
+	register.WriteString("func Register(g *zoox.RouterGroup, h Handler) {\n")
+
+	for i, m := range doc.Methods() {
+		if i > 0 {
+			register.WriteString("\n")
+		}
+
+		name := OperationName(m)
+		requestType := g.generateRequest(name, m)
+		status, responseType := g.responseType(name, m.Operation)
+
+		summary := m.Operation.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("%s %s", m.Method, m.Path)
+		}
+		fmt.Fprintf(handler, "\t// %s %s\n", name, oneLine(summary))
+		if responseType == "" {
+			fmt.Fprintf(handler, "\t%s(ctx *zoox.Context, req *%s) error\n", name, requestType)
+		} else {
+			fmt.Fprintf(handler, "\t%s(ctx *zoox.Context, req *%s) (%s, error)\n", name, requestType, responseType)
+		}
+
+		fmt.Fprintf(register, "\tg.%s(%q, func(ctx *zoox.Context) {\n", routeMethod(m.Method), ZooxPath(m.Path))
+		fmt.Fprintf(register, "\t\treq := &%s{}\n", requestType)
+		g.writeBind(register, m)
+		if responseType == "" {
+			fmt.Fprintf(register, "\t\tif err := h.%s(ctx, req); err != nil {\n\t\t\tfail(ctx, err)\n\t\t\treturn\n\t\t}\n\n", name)
+			fmt.Fprintf(register, "\t\tctx.Status(%s)\n", status)
+		} else {
+			fmt.Fprintf(register, "\t\tres, err := h.%s(ctx, req)\n\t\tif err != nil {\n\t\t\tfail(ctx, err)\n\t\t\treturn\n\t\t}\n\n", name)
+			fmt.Fprintf(register, "\t\tctx.JSON(%s, res)\n", status)
+		}
+		register.WriteString("\t})\n")
+	}
+
+	handler.WriteString("}\n")
+	register.WriteString("}\n")
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by zoox gen server. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", cfgX.Package)
+	writeImports(buf, g.imports)
+	for _, t := range g.types {
+		buf.WriteString(t + "\n")
+	}
+	buf.WriteString(handler.String() + "\n")
+	buf.WriteString(register.String() + "\n")
+	buf.WriteString(serverFail)
+
+	return formatSource(buf.Bytes())
+}
+
+// generateRequest generates the request struct of the operation, grouped by parameter location.
+func (g *generator) generateRequest(name string, m *Method) string {
+	requestType := name + "Request"
+
+	sections := map[string]*bytes.Buffer{}
+	for _, p := range m.Parameters {
+		tag := ""
+		switch p.In {
+		case "path":
+			tag = "param"
+		case "query":
+			tag = "query"
+		case "header":
+			tag = "header"
+		default:
+			continue
+		}
+
+		if sections[p.In] == nil {
+			sections[p.In] = &bytes.Buffer{}
+		}
+
+		typ := g.goType(p.Schema, requestType+GoName(p.Name))
+		if p.Description != "" {
+			fmt.Fprintf(sections[p.In], "\t\t// %s\n", oneLine(p.Description))
+		}
+		fmt.Fprintf(sections[p.In], "\t\t%s %s `%s:\"%s\"`\n", GoName(p.Name), typ, tag, p.Name)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// %s is the request of %s.\n", requestType, name)
+	fmt.Fprintf(buf, "type %s struct {\n", requestType)
+	for _, section := range []struct{ in, field string }{
+		{"path", "Params"},
+		{"query", "Query"},
+		{"header", "Header"},
+	} {
+		if fields, ok := sections[section.in]; ok {
+			fmt.Fprintf(buf, "\t%s struct {\n%s\t}\n", section.field, fields.String())
+		}
+	}
+	if m.Operation.RequestBody != nil {
+		if schema := JSONSchema(m.Operation.RequestBody.Content); schema != nil {
+			fmt.Fprintf(buf, "\tBody %s\n", g.goType(schema, name+"Body"))
+		}
+	}
+	buf.WriteString("}\n")
+
+	g.types = append(g.types, buf.String())
+	return requestType
+}
+
+// writeBind writes the binding code of the request sections.
+func (g *generator) writeBind(buf *bytes.Buffer, m *Method) {
+	in := map[string]bool{}
+	for _, p := range m.Parameters {
+		in[p.In] = true
+	}
+
+	for _, b := range []struct{ in, field, fn string }{
+		{"path", "Params", "BindParams"},
+		{"query", "Query", "BindQuery"},
+		{"header", "Header", "BindHeader"},
+	} {
+		if in[b.in] {
+			fmt.Fprintf(buf, "\t\tif err := ctx.%s(&req.%s); err != nil {\n", b.fn, b.field)
+			buf.WriteString("\t\t\tctx.Fail(err, http.StatusBadRequest, err.Error())\n\t\t\treturn\n\t\t}\n")
+		}
+	}
+
+	if m.Operation.RequestBody != nil && JSONSchema(m.Operation.RequestBody.Content) != nil {
+		buf.WriteString("\t\tif err := ctx.BindJSON(&req.Body); err != nil {\n")
+		buf.WriteString("\t\t\tctx.Fail(err, http.StatusBadRequest, err.Error())\n\t\t\treturn\n\t\t}\n")
+	}
+
+	buf.WriteString("\n")
+}
+
+// responseType returns the status expression and the go type of the success response.
+func (g *generator) responseType(name string, o *Operation) (status string, typ string) {
+	code, response := o.SuccessResponse()
+	// the ranges (2XX, 2xx) and default are responded with 200
+	status = "http.StatusOK"
+	if n, err := strconv.Atoi(code); err == nil && n >= 200 && n < 300 {
+		status = strconv.Itoa(n)
+	}
+
+	if response == nil {
+		return status, ""
+	}
+
+	schema := JSONSchema(response.Content)
+	if schema == nil {
+		return status, ""
+	}
+
+	typ = g.goType(schema, name+"Response")
+	if schema.Ref != "" || (len(schema.Properties) > 0 && !strings.HasPrefix(typ, "[]")) {
+		typ = "*" + typ
+	}
+
+	return status, typ
+}
+
+func routeMethod(method string) string {
+	return strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+}
+
+const serverFail = `func fail(ctx *zoox.Context, err error) {
+	if e, ok := err.(zoox.HTTPError); ok {
+		ctx.FailWithError(e)
+		return
+	}
+
+	ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+}
+`
@@ -0,0 +1,329 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// GenerateTypeScriptClient generates the typed typescript client (fetch based) of the document,
+// including the interfaces, auth options and the typed ApiError of failed responses.
+func GenerateTypeScriptClient(doc *Document) ([]byte, error) {
+	g := &tsGenerator{doc: doc}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by zoox gen client. DO NOT EDIT.\n\n")
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := doc.Components.Schemas[name]
+		if s.Description != "" {
+			fmt.Fprintf(buf, "/** %s */\n", oneLine(s.Description))
+		}
+		if s.Type == "object" || (s.Type == "" && len(s.Properties) > 0) {
+			fmt.Fprintf(buf, "export interface %s %s\n\n", GoName(name), g.tsObject(s, ""))
+			continue
+		}
+
+		fmt.Fprintf(buf, "export type %s = %s;\n\n", GoName(name), g.tsType(s, ""))
+	}
+
+	methods := &bytes.Buffer{}
+	for _, m := range doc.Methods() {
+		name := OperationName(m)
+		requestType := name + "Request"
+
+		// request
+		sections := map[string][]*Parameter{}
+		for _, p := range m.Parameters {
+			sections[p.In] = append(sections[p.In], p)
+		}
+
+		fmt.Fprintf(buf, "export interface %s {\n", requestType)
+		optionalRequest := true
+		for _, section := range []struct{ in, field string }{
+			{"path", "params"},
+			{"query", "query"},
+			{"header", "headers"},
+		} {
+			parameters, ok := sections[section.in]
+			if !ok {
+				continue
+			}
+
+			required := false
+			fields := &bytes.Buffer{}
+			for _, p := range parameters {
+				optional := "?"
+				if p.Required || p.In == "path" {
+					optional = ""
+					required = true
+				}
+				fmt.Fprintf(fields, "    %s%s: %s;\n", tsKey(p.Name), optional, g.tsType(p.Schema, "    "))
+			}
+
+			if required {
+				optionalRequest = false
+				fmt.Fprintf(buf, "  %s: {\n%s  };\n", section.field, fields.String())
+			} else {
+				fmt.Fprintf(buf, "  %s?: {\n%s  };\n", section.field, fields.String())
+			}
+		}
+
+		hasBody := false
+		if m.Operation.RequestBody != nil {
+			if schema := JSONSchema(m.Operation.RequestBody.Content); schema != nil {
+				hasBody = true
+				if m.Operation.RequestBody.Required {
+					optionalRequest = false
+					fmt.Fprintf(buf, "  body: %s;\n", g.tsType(schema, "  "))
+				} else {
+					fmt.Fprintf(buf, "  body?: %s;\n", g.tsType(schema, "  "))
+				}
+			}
+		}
+		buf.WriteString("}\n\n")
+
+		// response
+		responseType := "void"
+		if _, response := m.Operation.SuccessResponse(); response != nil {
+			if schema := JSONSchema(response.Content); schema != nil {
+				responseType = g.tsType(schema, "  ")
+			}
+		}
+
+		summary := m.Operation.Summary
+		if summary == "" {
+			summary = fmt.Sprintf("%s %s", m.Method, m.Path)
+		}
+
+		argument := "req: " + requestType
+		if optionalRequest {
+			argument = fmt.Sprintf("req: %s = {}", requestType)
+		}
+
+		body := "undefined"
+		if hasBody {
+			body = "req.body"
+		}
+
+		fmt.Fprintf(methods, "  /** %s */\n", oneLine(summary))
+		fmt.Fprintf(methods, "  %s(%s): Promise<%s> {\n", tsMethodName(name), argument, responseType)
+		fmt.Fprintf(methods, "    return this.request<%s>(%q, %s, (req as any).query, (req as any).headers, %s);\n", responseType, m.Method, tsPath(m), body)
+		methods.WriteString("  }\n\n")
+	}
+
+	buf.WriteString(tsRuntime)
+	buf.WriteString(strings.TrimRight(methods.String(), "\n") + "\n}\n")
+
+	return buf.Bytes(), nil
+}
+
+type tsGenerator struct {
+	doc *Document
+}
+
+func (g *tsGenerator) tsType(s *Schema, indent string) string {
+	if s == nil {
+		return "any"
+	}
+
+	if s.Ref != "" {
+		return GoName(RefName(s.Ref))
+	}
+
+	typ := "any"
+	switch {
+	case len(s.Enum) > 0:
+		values := []string{}
+		for _, v := range s.Enum {
+			values = append(values, fmt.Sprintf("%#v", v))
+		}
+		typ = strings.Join(values, " | ")
+	case s.Type == "string":
+		typ = "string"
+	case s.Type == "integer" || s.Type == "number":
+		typ = "number"
+	case s.Type == "boolean":
+		typ = "boolean"
+	case s.Type == "array":
+		item := g.tsType(s.Items, indent)
+		if strings.Contains(item, "|") {
+			item = "(" + item + ")"
+		}
+		typ = item + "[]"
+	case s.Type == "object" || s.Type == "":
+		if len(s.Properties) > 0 {
+			typ = g.tsObject(s, indent)
+		} else if s.AdditionalProperties != nil {
+			typ = fmt.Sprintf("Record<string, %s>", g.tsType(s.AdditionalProperties, indent))
+		} else {
+			typ = "Record<string, any>"
+		}
+	}
+
+	if s.Nullable {
+		typ += " | null"
+	}
+
+	return typ
+}
+
+func (g *tsGenerator) tsObject(s *Schema, indent string) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("{\n")
+	for _, key := range keys {
+		prop := s.Properties[key]
+		if prop != nil && prop.Description != "" {
+			fmt.Fprintf(buf, "%s  /** %s */\n", indent, oneLine(prop.Description))
+		}
+
+		optional := "?"
+		if required[key] {
+			optional = ""
+		}
+		fmt.Fprintf(buf, "%s  %s%s: %s;\n", indent, tsKey(key), optional, g.tsType(prop, indent+"  "))
+	}
+	buf.WriteString(indent + "}")
+
+	return buf.String()
+}
+
+// tsPath returns the typescript template literal of the request path.
+func tsPath(m *Method) string {
+	parts := strings.Split(m.Path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			parts[i] = fmt.Sprintf("${encodeURIComponent(String(req.params[%q]))}", part[1:len(part)-1])
+		}
+	}
+
+	return "`" + strings.Join(parts, "/") + "`"
+}
+
+func tsKey(key string) string {
+	for i, r := range key {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return fmt.Sprintf("%q", key)
+		}
+	}
+
+	return key
+}
+
+func tsMethodName(name string) string {
+	runes := []rune(name)
+	// GetUserByID => getUserByID, URLList => urlList
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+		i++
+	}
+
+	return string(runes)
+}
+
+const tsRuntime = `/** ApiError is the error of non-2xx responses, with the code and message of zoox.Fail. */
+export class ApiError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly code: number,
+    message: string,
+    public readonly body: string,
+  ) {
+    super(message || ` + "`api error: status ${status}`" + `);
+    this.name = 'ApiError';
+  }
+}
+
+export interface ClientOptions {
+  /** the fetch implementation, default: globalThis.fetch */
+  fetch?: typeof fetch;
+  /** the headers of all requests */
+  headers?: Record<string, string>;
+  /** the bearer token, or the function returning it */
+  token?: string | (() => string | Promise<string>);
+  /** the basic auth */
+  basicAuth?: { username: string; password: string };
+  /** the api key header, such as { header: 'X-API-Key', key: '...' } */
+  apiKey?: { header: string; key: string };
+}
+
+export class Client {
+  private readonly baseURL: string;
+
+  constructor(baseURL: string, private readonly options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, '');
+  }
+
+  private async request<T>(method: string, path: string, query?: Record<string, any>, headers?: Record<string, any>, body?: any): Promise<T> {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(query || {})) {
+      if (value === undefined || value === null) continue;
+      for (const one of Array.isArray(value) ? value : [value]) {
+        search.append(key, String(one));
+      }
+    }
+
+    const h: Record<string, string> = { Accept: 'application/json', ...this.options.headers };
+    for (const [key, value] of Object.entries(headers || {})) {
+      if (value !== undefined && value !== null) h[key] = String(value);
+    }
+    if (this.options.token) {
+      const token = typeof this.options.token === 'function' ? await this.options.token() : this.options.token;
+      h.Authorization = ` + "`Bearer ${token}`" + `;
+    }
+    if (this.options.basicAuth) {
+      h.Authorization = ` + "`Basic ${btoa(`${this.options.basicAuth.username}:${this.options.basicAuth.password}`)}`" + `;
+    }
+    if (this.options.apiKey) {
+      h[this.options.apiKey.header] = this.options.apiKey.key;
+    }
+    if (body !== undefined) {
+      h['Content-Type'] = 'application/json';
+    }
+
+    const qs = search.toString();
+    const response = await (this.options.fetch || fetch)(this.baseURL + path + (qs ? ` + "`?${qs}`" + ` : ''), {
+      method,
+      headers: h,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+
+    const text = await response.text();
+    if (!response.ok) {
+      let code = 0;
+      let message = '';
+      try {
+        ({ code = 0, message = '' } = JSON.parse(text));
+      } catch {
+        // not json
+      }
+      throw new ApiError(response.status, code, message, text);
+    }
+
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+
+`
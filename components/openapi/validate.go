@@ -0,0 +1,407 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError is a validation error located by the json pointer,
+// such as /body/items/0/name, /query/limit or /path/id.
+type ValidationError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Message)
+}
+
+// Route is the compiled matcher of an operation path.
+type Route struct {
+	*Method
+	re     *regexp.Regexp
+	params []string
+}
+
+// Router matches the request path to the operations of the document.
+type Router struct {
+	routes []*Route
+}
+
+// NewRouter compiles the operation paths of the document,
+// static paths are matched before templated paths.
+func NewRouter(doc *Document) *Router {
+	r := &Router{}
+	for _, m := range doc.Methods() {
+		route := &Route{Method: m}
+		parts := strings.Split(m.Path, "/")
+		for i, part := range parts {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				route.params = append(route.params, part[1:len(part)-1])
+				parts[i] = "([^/]+)"
+			} else {
+				parts[i] = regexp.QuoteMeta(part)
+			}
+		}
+		route.re = regexp.MustCompile("^" + strings.Join(parts, "/") + "$")
+
+		r.routes = append(r.routes, route)
+	}
+
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return len(r.routes[i].params) < len(r.routes[j].params)
+	})
+
+	return r
+}
+
+// Find returns the operation of the method and path with the path parameters, nil if not found.
+func (r *Router) Find(method, path string) (*Route, map[string]string) {
+	for _, route := range r.routes {
+		if route.Method.Method != method {
+			continue
+		}
+
+		matches := route.re.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+
+		params := map[string]string{}
+		for i, name := range route.params {
+			params[name], _ = url.PathUnescape(matches[i+1])
+		}
+
+		return route, params
+	}
+
+	return nil, nil
+}
+
+// ValidateRequest validates the parameters and the decoded json body (nil if none) of the request.
+func (d *Document) ValidateRequest(m *Method, pathParams map[string]string, query url.Values, header http.Header, body any, hasBody bool) []*ValidationError {
+	errs := []*ValidationError{}
+	for _, p := range m.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = header.Values(p.Name)
+		default:
+			continue
+		}
+
+		pointer := "/" + p.In + "/" + escapePointer(p.Name)
+		if len(values) == 0 {
+			if p.Required || p.In == "path" {
+				errs = append(errs, &ValidationError{Pointer: pointer, Message: "is required"})
+			}
+			continue
+		}
+
+		value, err := d.parseParameter(p.Schema, values)
+		if err != nil {
+			errs = append(errs, &ValidationError{Pointer: pointer, Message: err.Error()})
+			continue
+		}
+
+		errs = append(errs, d.Validate(p.Schema, value, pointer)...)
+	}
+
+	if m.Operation.RequestBody != nil {
+		schema := JSONSchema(m.Operation.RequestBody.Content)
+		if !hasBody {
+			if m.Operation.RequestBody.Required {
+				errs = append(errs, &ValidationError{Pointer: "/body", Message: "is required"})
+			}
+		} else if schema != nil {
+			errs = append(errs, d.Validate(schema, body, "/body")...)
+		}
+	}
+
+	return errs
+}
+
+// ResponseSchema returns the json schema of the response status, falling back to 2XX and default.
+func (o *Operation) ResponseSchema(status int) (schema *Schema, ok bool) {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if response, exists := o.Responses[key]; exists {
+			if response == nil {
+				return nil, true
+			}
+
+			return JSONSchema(response.Content), true
+		}
+	}
+
+	return nil, false
+}
+
+// Validate validates the decoded json value against the schema.
+func (d *Document) Validate(s *Schema, value any, pointer string) []*ValidationError {
+	s = d.Resolve(s)
+	if s == nil {
+		return nil
+	}
+
+	fail := func(format string, args ...any) []*ValidationError {
+		return []*ValidationError{{Pointer: pointer, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+
+		return fail("must be %s, got null", s.Type)
+	}
+
+	if len(s.Enum) > 0 && !enumContains(s.Enum, value) {
+		return fail("must be one of %v", s.Enum)
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fail("must be string, got %s", jsonType(value))
+		}
+
+		length := len([]rune(str))
+		if s.MinLength != nil && length < *s.MinLength {
+			return fail("length must be >= %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fail("length must be <= %d", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := compilePattern(s.Pattern); err == nil && !re.MatchString(str) {
+				return fail("must match pattern %s", s.Pattern)
+			}
+		}
+		if err := validateFormat(s.Format, str); err != nil {
+			return fail("%v", err)
+		}
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok {
+			return fail("must be %s, got %s", s.Type, jsonType(value))
+		}
+		if s.Type == "integer" && n != float64(int64(n)) {
+			return fail("must be integer, got %v", n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fail("must be >= %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fail("must be <= %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be boolean, got %s", jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fail("must be array, got %s", jsonType(value))
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			return fail("must have >= %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			return fail("must have <= %d items", *s.MaxItems)
+		}
+
+		errs := []*ValidationError{}
+		for i, item := range items {
+			errs = append(errs, d.Validate(s.Items, item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+		return errs
+	case "object", "":
+		object, ok := value.(map[string]any)
+		if !ok {
+			if s.Type == "" {
+				return nil
+			}
+
+			return fail("must be object, got %s", jsonType(value))
+		}
+
+		errs := []*ValidationError{}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				errs = append(errs, &ValidationError{Pointer: pointer + "/" + escapePointer(name), Message: "is required"})
+			}
+		}
+
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := pointer + "/" + escapePointer(key)
+			if prop, ok := s.Properties[key]; ok {
+				errs = append(errs, d.Validate(prop, object[key], child)...)
+			} else if s.AdditionalProperties != nil {
+				errs = append(errs, d.Validate(s.AdditionalProperties, object[key], child)...)
+			}
+		}
+		return errs
+	}
+
+	return nil
+}
+
+// parseParameter converts the string values of a parameter into the json value of the schema.
+func (d *Document) parseParameter(s *Schema, values []string) (any, error) {
+	s = d.Resolve(s)
+	if s == nil {
+		return values[0], nil
+	}
+
+	if s.Type == "array" {
+		// ?tags=a&tags=b or ?tags=a,b
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+
+		items := make([]any, 0, len(values))
+		for _, v := range values {
+			item, err := d.parseParameter(s.Items, []string{v})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		return items, nil
+	}
+
+	value := values[0]
+	switch s.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be integer, got %q", value)
+		}
+		return float64(n), nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be number, got %q", value)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("must be boolean, got %q", value)
+		}
+		return b, nil
+	}
+
+	return value, nil
+}
+
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
+
+var emailRe = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validateFormat(format, value string) error {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("must be date-time (RFC 3339)")
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("must be date (YYYY-MM-DD)")
+		}
+	case "email":
+		if !emailRe.MatchString(value) {
+			return fmt.Errorf("must be email")
+		}
+	case "uuid":
+		if !uuidRe.MatchString(value) {
+			return fmt.Errorf("must be uuid")
+		}
+	}
+
+	return nil
+}
+
+func enumContains(enum []any, value any) bool {
+	for _, one := range enum {
+		if fmt.Sprint(one) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, float32, int, int64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+// escapePointer escapes the json pointer token, ~ => ~0, / => ~1.
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -1,15 +1,66 @@
 package router
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 )
 
-// Node ...
+// Node is the node of the route trie.
+//
+// The trailing slash is the empty last part, such as [docs, ""] of /docs/, so that /docs and /docs/ are different routes,
+// params do not match the empty part. If only one of them is registered, it matches both, see terminal.
 type Node struct {
 	Path     string
 	part     string
 	children []*Node
 	isWild   bool
+	// constraint validates the param, such as :id<uuid>
+	constraint *regexp.Regexp
+}
+
+// Constraints are the builtin param constraints, such as :id<uuid>, others are regular expressions, such as :slug<[a-z-]+>,
+// which match one path segment, so they must not contain /.
+var Constraints = map[string]string{
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"alpha": `[a-zA-Z]+`,
+	"alnum": `[a-zA-Z0-9]+`,
+	"slug":  `[a-z0-9]+(?:-[a-z0-9]+)*`,
+}
+
+// ParseParam parses the param part, such as :id, {id}, *path or :id<uuid>,
+// returns the name and the constraint (empty if not constrained), ok false if it is not a param.
+func ParseParam(part string) (name string, constraint string, ok bool) {
+	if part == "" {
+		return "", "", false
+	}
+
+	switch {
+	case part[0] == ':' || part[0] == '*':
+		name = part[1:]
+	case part[0] == '{' && part[len(part)-1] == '}':
+		name = part[1 : len(part)-1]
+	default:
+		return "", "", false
+	}
+
+	if i := strings.IndexByte(name, '<'); i >= 0 && strings.HasSuffix(name, ">") {
+		constraint = name[i+1 : len(name)-1]
+		name = name[:i]
+	}
+
+	return name, constraint, true
+}
+
+// compileConstraint compiles the constraint, the builtin name or the regular expression, anchored.
+func compileConstraint(constraint string) (*regexp.Regexp, error) {
+	if expr, ok := Constraints[constraint]; ok {
+		constraint = expr
+	}
+
+	return regexp.Compile("^(?:" + constraint + ")$")
 }
 
 // MatchChild ...
@@ -23,11 +74,36 @@ func (n *Node) MatchChild(part string) *Node {
 	return nil
 }
 
+// insertChild returns the child to insert the part into,
+// constrained params are different nodes, unconstrained params share the node as before.
+func (n *Node) insertChild(part string) *Node {
+	_, constraint, isParam := ParseParam(part)
+	for _, child := range n.children {
+		if child.part == part {
+			return child
+		}
+
+		if isParam && constraint == "" && child.isWild && child.constraint == nil {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// matchChildren returns the children matching the part, in order of registration as the unconstrained routes,
+// so the constrained params are registered before the unconstrained param of the same segment to be tried first.
 func (n *Node) matchChildren(part string) []*Node {
 	nodes := []*Node{}
 
 	for _, child := range n.children {
-		if child.part == part || child.isWild {
+		switch {
+		case child.isWild && part == "":
+		case child.constraint != nil:
+			if child.constraint.MatchString(part) {
+				nodes = append(nodes, child)
+			}
+		case child.isWild || child.part == part:
 			nodes = append(nodes, child)
 		}
 	}
@@ -43,30 +119,59 @@ func (n *Node) Insert(pattern string, parts []string, height int) {
 	}
 
 	part := parts[height]
-	child := n.MatchChild(part)
+	child := n.insertChild(part)
 	if child == nil {
-		isWild := false
-		if part[0] == ':' {
-			isWild = true
-		} else if part[0] == '{' && part[len(part)-1] == '}' {
-			isWild = true
-		} else if part[0] == '*' {
-			isWild = true
+		child = &Node{
+			part: part,
 		}
 
-		child = &Node{
-			part:   part,
-			isWild: isWild,
+		if _, constraint, ok := ParseParam(part); ok {
+			child.isWild = true
+
+			if constraint != "" {
+				re, err := compileConstraint(constraint)
+				if err != nil {
+					panic(fmt.Sprintf("[router] invalid constraint of %s in route %s: %s", part, pattern, err))
+				}
+				child.constraint = re
+			}
 		}
+
 		n.children = append(n.children, child)
 	}
 
 	child.Insert(pattern, parts, height+1)
 }
 
+// terminal returns the route ending at the node, with the trailing slash or not,
+// or the other one if only it is registered, which the router redirects or matches as configured.
+func (n *Node) terminal(slash bool) *Node {
+	var exact, other *Node = n, nil
+	for _, child := range n.children {
+		if child.part == "" {
+			other = child
+			break
+		}
+	}
+
+	if slash {
+		exact, other = other, exact
+	}
+
+	if exact != nil && exact.Path != "" {
+		return exact
+	}
+
+	if other != nil && other.Path != "" {
+		return other
+	}
+
+	return nil
+}
+
 // Search ...
 func (n *Node) Search(parts []string, height int) *Node {
-	if len(parts) == height || strings.HasPrefix(n.part, "*") {
+	if strings.HasPrefix(n.part, "*") {
 		if n.Path == "" {
 			return nil
 		}
@@ -74,6 +179,14 @@ func (n *Node) Search(parts []string, height int) *Node {
 		return n
 	}
 
+	if len(parts) == height {
+		return n.terminal(false)
+	}
+
+	if len(parts) == height+1 && parts[height] == "" {
+		return n.terminal(true)
+	}
+
 	part := parts[height]
 	children := n.matchChildren(part)
 
@@ -91,3 +204,41 @@ func (n *Node) Search(parts []string, height int) *Node {
 func (n *Node) IsWild() bool {
 	return n.isWild
 }
+
+// SearchFold is Search with the static parts matched case-insensitively.
+func (n *Node) SearchFold(parts []string, height int) *Node {
+	if strings.HasPrefix(n.part, "*") {
+		if n.Path == "" {
+			return nil
+		}
+
+		return n
+	}
+
+	if len(parts) == height {
+		return n.terminal(false)
+	}
+
+	if len(parts) == height+1 && parts[height] == "" {
+		return n.terminal(true)
+	}
+
+	part := parts[height]
+	for _, child := range n.children {
+		if child.isWild && part == "" {
+			continue
+		}
+
+		if child.constraint != nil && !child.constraint.MatchString(part) {
+			continue
+		}
+
+		if child.isWild || strings.EqualFold(child.part, part) {
+			if result := child.SearchFold(parts, height+1); result != nil {
+				return result
+			}
+		}
+	}
+
+	return nil
+}
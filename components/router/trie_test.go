@@ -0,0 +1,180 @@
+package router
+
+import (
+	"strings"
+	"testing"
+)
+
+func split(path string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+func newTestTrie() *Node {
+	root := &Node{}
+	for _, pattern := range []string{
+		"/users/:id<uuid>",
+		"/users/:id<int>",
+		"/users/me",
+		"/users/:name",
+		"/posts/:slug<slug>",
+		"/files/{name<[a-z]+\\.txt>}",
+		"/orders/:id<uint>/items",
+		"/orders/:ref/history",
+	} {
+		root.Insert(pattern, split(pattern), 0)
+	}
+
+	return root
+}
+
+func TestParseParam(t *testing.T) {
+	cases := []struct {
+		part       string
+		name       string
+		constraint string
+		ok         bool
+	}{
+		{":id", "id", "", true},
+		{"{id}", "id", "", true},
+		{"*path", "path", "", true},
+		{":id<uuid>", "id", "uuid", true},
+		{"{name<[a-z]+>}", "name", "[a-z]+", true},
+		{"users", "", "", false},
+		{"{id", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, c := range cases {
+		name, constraint, ok := ParseParam(c.part)
+		if name != c.name || constraint != c.constraint || ok != c.ok {
+			t.Errorf("%s: expected %s %s %v, got %s %s %v", c.part, c.name, c.constraint, c.ok, name, constraint, ok)
+		}
+	}
+}
+
+func TestSearchConstraints(t *testing.T) {
+	root := newTestTrie()
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/3f2b8c1e-9a4d-4e2b-8c1e-9a4d4e2b8c1e", "/users/:id<uuid>"},
+		{"/users/3F2B8C1E-9A4D-4E2B-8C1E-9A4D4E2B8C1E", "/users/:id<uuid>"},
+		{"/users/42", "/users/:id<int>"},
+		{"/users/-42", "/users/:id<int>"},
+		{"/users/me", "/users/me"},
+		// not matched by the constraints, falls through to the unconstrained param
+		{"/users/zero", "/users/:name"},
+		{"/users/3f2b8c1e-9a4d-4e2b-8c1e", "/users/:name"},
+		{"/users/42abc", "/users/:name"},
+		{"/posts/hello-world", "/posts/:slug<slug>"},
+		{"/posts/Hello_World", ""},
+		{"/posts/hello--world", ""},
+		{"/files/notes.txt", "/files/{name<[a-z]+\\.txt>}"},
+		// the constraints are anchored
+		{"/files/notes.txt.exe", ""},
+		{"/files/../notes.txt", ""},
+		{"/files/notesXtxt", ""},
+		{"/orders/42/items", "/orders/:id<uint>/items"},
+		// the constrained param matches, but not the rest of the path
+		{"/orders/42/history", "/orders/:ref/history"},
+		{"/orders/-42/items", ""},
+	}
+
+	for _, c := range cases {
+		n := root.Search(split(c.path), 0)
+		if c.expected == "" {
+			if n != nil {
+				t.Errorf("%s: expected no match, got %s", c.path, n.Path)
+			}
+			continue
+		}
+
+		if n == nil {
+			t.Errorf("%s: expected %s, got no match", c.path, c.expected)
+			continue
+		}
+		if n.Path != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.path, c.expected, n.Path)
+		}
+	}
+}
+
+func TestSearchFoldConstraints(t *testing.T) {
+	root := newTestTrie()
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/USERS/42", "/users/:id<int>"},
+		{"/Users/Me", "/users/me"},
+		{"/Posts/hello-world", "/posts/:slug<slug>"},
+		// the params are not folded
+		{"/Posts/Hello-World", ""},
+	}
+
+	for _, c := range cases {
+		n := root.SearchFold(split(c.path), 0)
+		if c.expected == "" {
+			if n != nil {
+				t.Errorf("%s: expected no match, got %s", c.path, n.Path)
+			}
+			continue
+		}
+
+		if n == nil || n.Path != c.expected {
+			t.Errorf("%s: expected %s, got %v", c.path, c.expected, n)
+		}
+	}
+}
+
+func TestInsertInvalidConstraint(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+
+	root := &Node{}
+	root.Insert("/users/:id<[0-9>", split("/users/:id<[0-9>"), 0)
+}
+
+func TestSearchRegistrationOrder(t *testing.T) {
+	root := &Node{}
+	for _, pattern := range []string{
+		"/:name",
+		"/static",
+		"/users/:id<int>",
+		"/users/:name",
+		"/users/me",
+	} {
+		root.Insert(pattern, split(pattern), 0)
+	}
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		// the param registered before the static route matches first
+		{"/static", "/:name"},
+		{"/zero", "/:name"},
+		{"/users/42", "/users/:id<int>"},
+		{"/users/me", "/users/:name"},
+	}
+
+	for _, c := range cases {
+		n := root.Search(split(c.path), 0)
+		if n == nil || n.Path != c.expected {
+			t.Errorf("%s: expected %s, got %v", c.path, c.expected, n)
+		}
+	}
+}
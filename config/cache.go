@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// LocalCache defines the config of the process-local cache tier.
+type LocalCache struct {
+	Enabled bool `config:"enabled"`
+	// Size is the max keys, default: 10000.
+	Size int `config:"size"`
+	// TTL is the max ttl of keys, default: 10s.
+	TTL time.Duration `config:"ttl"`
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"io"
+	"time"
+
 	"github.com/go-zoox/cache"
 	"github.com/go-zoox/session"
 )
@@ -15,6 +18,23 @@ type Config struct {
 	// BodySizeLimit is the limit of the request body size.
 	BodySizeLimit int64
 
+	// ReadTimeout is the max duration of reading the entire request, including the body, default: 300s.
+	ReadTimeout time.Duration `config:"read_timeout"`
+	// ReadHeaderTimeout is the max duration of reading the request headers, against slowloris, default: 10s.
+	ReadHeaderTimeout time.Duration `config:"read_header_timeout"`
+	// WriteTimeout is the max duration before timing out writes of the response, default: 300s.
+	WriteTimeout time.Duration `config:"write_timeout"`
+	// IdleTimeout is the max duration to wait for the next request when keep-alives are enabled, default: 300s.
+	IdleTimeout time.Duration `config:"idle_timeout"`
+	// MaxHeaderBytes is the max bytes of the request headers, default: 1MB.
+	MaxHeaderBytes int `config:"max_header_bytes"`
+	// DisableKeepAlives disables the http keep-alives, each connection serves one request.
+	DisableKeepAlives bool `config:"disable_keep_alives"`
+	// MaxConnsPerIP caps the concurrent connections per client ip, 0 means unlimited.
+	MaxConnsPerIP int `config:"max_conns_per_ip"`
+	// ShutdownTimeout is the max duration of stopping the components, such as draining the job queue, default: 30s.
+	ShutdownTimeout time.Duration `config:"shutdown_timeout"`
+
 	//
 	NetworkType      string
 	UnixDomainSocket string
@@ -30,6 +50,15 @@ type Config struct {
 	TLSCert string
 	TLSKey  string
 
+	// Name is the application name.
+	Name string `config:"name"`
+	// Namespace is the key prefix of shared storages (cache, idempotency, ...),
+	//	and of the session cookie name, so that multiple apps can share one redis and domain.
+	//	Without it, the cache prefix (eunomia) and the session cookie name are unchanged from the versions
+	//	before namespaces, and the new components use <name>:<mode>.
+	//	Setting it on an existing deployment resets the sessions and the cached entries once.
+	Namespace string `config:"namespace"`
+
 	//
 	LogLevel string `config:"log_level"`
 	//
@@ -39,9 +68,51 @@ type Config struct {
 	//
 	Cache cache.Config `config:"cache"`
 	//
+	// LocalCache enables the process-local tier in front of the cache (redis).
+	LocalCache LocalCache `config:"local_cache"`
+	//
 	Redis Redis `config:"redis"`
 	//
+	Database Database `config:"database"`
+	//
+	// Secrets resolves the secret references of config values, such as secret://vault/app#api_key.
+	Secrets Secrets `config:"secrets"`
+	//
+	// Encryption encrypts the sessions and cache namespaces at rest.
+	Encryption Encryption `config:"encryption"`
+	//
+	// UploadScan scans the uploaded files, such as with ClamAV.
+	UploadScan UploadScan `config:"upload_scan"`
+	//
+	// Storage stores the uploaded files, such as in S3.
+	Storage Storage `config:"storage"`
+	//
+	// Redirects are the redirect and rewrite rules evaluated before routing.
+	Redirects []Redirect `config:"redirects"`
+	//
+	// Router is the path matching options of the router.
+	Router Router `config:"router"`
+	//
+	// TrustedProxies are the ips or cidrs of the reverse proxies, such as 10.0.0.0/8,
+	//	whose X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Port and Forwarded headers are honored by ctx.Scheme, ctx.BaseURL.
+	TrustedProxies []string `config:"trusted_proxies"`
+	//
+	// RequestLog writes the request logs to sinks (stdout, file, syslog, OTLP) without blocking on log I/O.
+	RequestLog RequestLog `config:"request_log"`
+	//
+	// ErrorReport reports the panics and server errors to Sentry, Rollbar or Bugsnag.
+	ErrorReport ErrorReport `config:"error_report"`
+	// Banner is the startup banner, a text/template with the variables: {{.Name}}, {{.Version}}, {{.Addr}}, {{.Env}}, {{.Website}}.
 	Banner string
+	// DisableBanner suppresses the startup banner and runtime info.
+	DisableBanner bool `config:"disable_banner"`
+	// BannerWriter is where the banner is written, default: os.Stderr.
+	BannerWriter io.Writer
 	//
 	Monitor Monitor `config:"monitor"`
+	//
+	JSON JSON `config:"json"`
+	//
+	// MiddlewareOrder forces the order of named middlewares, such as ["recovery", "request_id", "logger"].
+	MiddlewareOrder []string `config:"middleware_order"`
 }
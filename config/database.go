@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// Database defines the config of the sql database, the driver is registered by importing it in the application,
+// such as _ "github.com/lib/pq".
+type Database struct {
+	// Driver is the database/sql driver name, such as postgres, mysql, sqlite3.
+	Driver string `config:"driver"`
+	// DSN is the data source name of the driver.
+	DSN string `config:"dsn"`
+	//
+	MaxOpenConns    int           `config:"max_open_conns"`
+	MaxIdleConns    int           `config:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `config:"conn_max_lifetime"`
+	//
+	// Migrations is the directory of sql migrations, default: migrations.
+	Migrations string `config:"migrations"`
+	// AutoMigrate applies the pending migrations on app startup.
+	AutoMigrate bool `config:"auto_migrate"`
+}
@@ -0,0 +1,13 @@
+package config
+
+// Encryption defines the config of encryption at rest for sessions and cache namespaces.
+type Encryption struct {
+	// Keys are the encryption (and url signing) keys, the first is the primary key used to encrypt, the others only decrypt,
+	//	such as secret://vault/app#session_key, prepend the new key to rotate.
+	//	Defaults to secret_key.
+	Keys []string `config:"keys"`
+	// Session encrypts the session cookies.
+	Session bool `config:"session"`
+	// CacheNamespaces are the cache key prefixes encrypted, such as user:.
+	CacheNamespaces []string `config:"cache_namespaces"`
+}
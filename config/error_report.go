@@ -0,0 +1,17 @@
+package config
+
+// ErrorReport defines the config of reporting the panics and server errors to the error tracking service.
+type ErrorReport struct {
+	// Provider is sentry, rollbar or bugsnag, empty means disabled.
+	Provider string `config:"provider"`
+	// DSN is the Sentry DSN.
+	DSN string `config:"dsn"`
+	// Token is the Rollbar access token or Bugsnag api key.
+	Token string `config:"token"`
+	// Environment is the environment of the events, default: app mode.
+	Environment string `config:"environment"`
+	// Release is the release of the events, such as api@1.2.3.
+	Release string `config:"release"`
+	// SensitiveKeys are the extra header and query keys filtered from the events.
+	SensitiveKeys []string `config:"sensitive_keys"`
+}
@@ -0,0 +1,13 @@
+package config
+
+// JSON defines the config of json responses.
+type JSON struct {
+	// PrettyInDebug enables indented ctx.JSON output in debug mode.
+	PrettyInDebug bool `config:"pretty_in_debug"`
+	// SecurePrefix is the prefix of ctx.SecureJSON, default: while(1);
+	SecurePrefix string `config:"secure_prefix"`
+	// DisableHTMLEscape disables escaping <, > and & of ctx.JSON.
+	DisableHTMLEscape bool `config:"disable_html_escape"`
+	// DisallowUnknownFields makes ctx.BindJSON fail on unknown fields.
+	DisallowUnknownFields bool `config:"disallow_unknown_fields"`
+}
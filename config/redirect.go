@@ -0,0 +1,19 @@
+package config
+
+// Redirect defines a redirect (or rewrite) rule, evaluated in order before routing.
+type Redirect struct {
+	// From is the path pattern, such as /old/:id or /docs/*path, or the regular expression if regex is true.
+	From string `config:"from"`
+	// To is the target, such as /new/:id, https://docs.example.com/*path or /posts/$1.
+	To string `config:"to"`
+	// Regex matches the path with from as regular expression.
+	Regex bool `config:"regex"`
+	// Status of the redirect, default 301.
+	Status int `config:"status"`
+	// Rewrite rewrites the request path internally instead of redirecting.
+	Rewrite bool `config:"rewrite"`
+	// PreserveQuery appends the query of the request to the target.
+	PreserveQuery bool `config:"preserve_query"`
+	// Host matches the request host only if set.
+	Host string `config:"host"`
+}
@@ -0,0 +1,21 @@
+package config
+
+// RequestLog defines the config of the request log sinks, written by an async buffered writer.
+type RequestLog struct {
+	// Stdout writes the request logs as json lines to stdout.
+	Stdout bool `config:"stdout"`
+	// File writes the request logs as json lines to the file, rotated by size.
+	File string `config:"file"`
+	// MaxSize is the size in bytes to rotate the file, default: 100MB.
+	MaxSize int64 `config:"max_size"`
+	// MaxBackups is the count of rotated files kept, default: 5.
+	MaxBackups int `config:"max_backups"`
+	// Syslog is the syslog address, such as udp://127.0.0.1:514, tcp://127.0.0.1:514 or unix:///dev/log.
+	Syslog string `config:"syslog"`
+	// OTLP is the OTLP/HTTP logs endpoint, such as http://127.0.0.1:4318.
+	OTLP string `config:"otlp"`
+	// BufferSize is the size of the buffered records, default: 8192.
+	BufferSize int `config:"buffer_size"`
+	// DropPolicy is the policy when the buffer is full: drop_newest (default), drop_oldest or block.
+	DropPolicy string `config:"drop_policy"`
+}
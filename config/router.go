@@ -0,0 +1,13 @@
+package config
+
+// Router defines the path matching options of the router.
+type Router struct {
+	// RedirectTrailingSlash redirects /users/ to /users (or the reverse) if only the other one is registered.
+	RedirectTrailingSlash bool `config:"redirect_trailing_slash"`
+	// RedirectFixedPath redirects the unclean (such as //users/../users), percent-encoded or case-mismatched paths
+	//	(such as /USERS) to the registered path.
+	RedirectFixedPath bool `config:"redirect_fixed_path"`
+	// StrictSlash does not match /users/ with /users (404), unless RedirectTrailingSlash is enabled,
+	//	groups can override it with g.StrictSlash.
+	StrictSlash bool `config:"strict_slash"`
+}
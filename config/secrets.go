@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// Secrets defines the config of the secret references (secret://, env://) in config values.
+type Secrets struct {
+	// CacheTTL is the cache ttl of the resolved values, 0 means cached until refreshed.
+	CacheTTL time.Duration `config:"cache_ttl"`
+	// RefreshInterval refreshes the resolved values and calls the rotation callbacks, 0 means disabled.
+	RefreshInterval time.Duration `config:"refresh_interval"`
+}
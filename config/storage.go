@@ -0,0 +1,30 @@
+package config
+
+// Storage defines the config of the file storage (uploads).
+type Storage struct {
+	// Driver is local or s3, default local.
+	Driver string `config:"driver"`
+	// Local is the config of the local disk storage.
+	Local LocalStorage `config:"local"`
+	// S3 is the config of the S3 compatible storage, such as AWS S3, MinIO, R2 or GCS.
+	S3 S3Storage `config:"s3"`
+}
+
+// LocalStorage defines the config of the local disk storage.
+type LocalStorage struct {
+	// Root is the directory of the files, default uploads.
+	Root string `config:"root"`
+	// BaseURL is the url serving the files, for signed urls.
+	BaseURL string `config:"base_url"`
+}
+
+// S3Storage defines the config of the S3 compatible storage.
+type S3Storage struct {
+	Endpoint        string `config:"endpoint"`
+	Region          string `config:"region"`
+	Bucket          string `config:"bucket"`
+	AccessKeyID     string `config:"access_key_id"`
+	SecretAccessKey string `config:"secret_access_key"`
+	PathStyle       bool   `config:"path_style"`
+	Prefix          string `config:"prefix"`
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// UploadScan defines the config of scanning the uploaded files before they are persisted by ctx.SaveFile.
+type UploadScan struct {
+	// ClamAV is the clamd address, such as 127.0.0.1:3310 or /var/run/clamav/clamd.ctl, empty means disabled.
+	ClamAV string `config:"clamav"`
+	// Async saves the files in background after scanned, the status is sent by the upload.scanned webhook.
+	Async bool `config:"async"`
+	// QuarantineDir keeps the infected files, they are deleted if empty.
+	QuarantineDir string `config:"quarantine_dir"`
+	// Timeout of a scan, default 1m.
+	Timeout time.Duration `config:"timeout"`
+}
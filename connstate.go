@@ -0,0 +1,173 @@
+package zoox
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ConnStats is the connection stats of the server.
+type ConnStats struct {
+	// New is the connections which are accepted but not read yet.
+	New int64 `json:"new"`
+	// Active is the connections which are serving requests.
+	Active int64 `json:"active"`
+	// Idle is the keep-alive connections waiting for the next request.
+	Idle int64 `json:"idle"`
+	// Hijacked is the total hijacked connections, such as websocket.
+	Hijacked uint64 `json:"hijacked"`
+	// Closed is the total closed connections.
+	Closed uint64 `json:"closed"`
+	// Accepted is the total accepted connections.
+	Accepted uint64 `json:"accepted"`
+}
+
+// connTracker tracks the connection states of the server.
+type connTracker struct {
+	states sync.Map
+	//
+	new    atomic.Int64
+	active atomic.Int64
+	idle   atomic.Int64
+	//
+	hijacked atomic.Uint64
+	closed   atomic.Uint64
+	accepted atomic.Uint64
+	//
+	sync.RWMutex
+	hooks []func(conn net.Conn, state http.ConnState)
+}
+
+func (t *connTracker) gauge(state http.ConnState) *atomic.Int64 {
+	switch state {
+	case http.StateNew:
+		return &t.new
+	case http.StateActive:
+		return &t.active
+	case http.StateIdle:
+		return &t.idle
+	}
+
+	return nil
+}
+
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	if previous, ok := t.states.Load(conn); ok {
+		if gauge := t.gauge(previous.(http.ConnState)); gauge != nil {
+			gauge.Add(-1)
+		}
+	}
+
+	switch state {
+	case http.StateNew:
+		t.accepted.Add(1)
+	case http.StateHijacked:
+		t.hijacked.Add(1)
+	case http.StateClosed:
+		t.closed.Add(1)
+	}
+
+	if gauge := t.gauge(state); gauge != nil {
+		gauge.Add(1)
+		t.states.Store(conn, state)
+	} else {
+		// hijacked and closed are terminal states
+		t.states.Delete(conn)
+	}
+
+	t.RLock()
+	hooks := t.hooks
+	t.RUnlock()
+	for _, hook := range hooks {
+		hook(conn, state)
+	}
+}
+
+// OnConnState adds the callback of server connection state changes (new, active, idle, hijacked, closed).
+func (app *Application) OnConnState(fn func(conn net.Conn, state http.ConnState)) {
+	app.conns.Lock()
+	defer app.conns.Unlock()
+
+	app.conns.hooks = append(app.conns.hooks, fn)
+}
+
+// ConnStats returns the connection stats of the server.
+func (app *Application) ConnStats() ConnStats {
+	return ConnStats{
+		New:      app.conns.new.Load(),
+		Active:   app.conns.active.Load(),
+		Idle:     app.conns.idle.Load(),
+		Hijacked: app.conns.hijacked.Load(),
+		Closed:   app.conns.closed.Load(),
+		Accepted: app.conns.accepted.Load(),
+	}
+}
+
+// perIPLimitListener closes the connections exceeding the max connections per client ip.
+type perIPLimitListener struct {
+	net.Listener
+	max int
+	//
+	sync.Mutex
+	conns map[string]int
+}
+
+func newPerIPLimitListener(listener net.Listener, max int) net.Listener {
+	return &perIPLimitListener{
+		Listener: listener,
+		max:      max,
+		conns:    map[string]int{},
+	}
+}
+
+func (l *perIPLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil {
+			// such as unix domain socket
+			return conn, nil
+		}
+
+		l.Lock()
+		if l.conns[ip] >= l.max {
+			l.Unlock()
+			conn.Close()
+			continue
+		}
+		l.conns[ip]++
+		l.Unlock()
+
+		return &perIPLimitConn{Conn: conn, listener: l, ip: ip}, nil
+	}
+}
+
+func (l *perIPLimitListener) release(ip string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.conns[ip]--
+	if l.conns[ip] <= 0 {
+		delete(l.conns, ip)
+	}
+}
+
+type perIPLimitConn struct {
+	net.Conn
+	listener *perIPLimitListener
+	ip       string
+	once     sync.Once
+}
+
+func (c *perIPLimitConn) Close() error {
+	c.once.Do(func() {
+		c.listener.release(c.ip)
+	})
+
+	return c.Conn.Close()
+}
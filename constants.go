@@ -1,37 +1,21 @@
 package zoox
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/go-zoox/random"
 )
 
-// DefaultMiddlewares is the default global middleware
-var DefaultMiddlewares = map[string]func(app *Application){
-	// Logger,
-}
-
-// DefaultMiddleware ...
-func DefaultMiddleware(name string, fn func(app *Application)) {
-	DefaultMiddlewares[name] = fn
-}
-
-// DefaultGroupsFns ...
-var DefaultGroupsFns = map[string]func(r *RouterGroup){}
-
-// DefaultGroup ...
-func DefaultGroup(prefix string, fn func(r *RouterGroup)) {
-	if _, ok := DefaultGroupsFns[prefix]; ok {
-		panic(fmt.Errorf("zoox: default group (%s) already registered", prefix))
-	}
-
-	DefaultGroupsFns[prefix] = fn
-}
-
 // DefaultSecretKey uses for session encryption and decryption.
 var DefaultSecretKey = random.String(16)
 
+// DefaultName is the default application name, used by the default namespace.
+var DefaultName = "zoox"
+
+// LegacyCachePrefix is the redis key prefix of the cache if app.Config.Namespace is not configured,
+// which is the prefix of the versions before namespaces.
+var LegacyCachePrefix = "eunomia"
+
 // DefaultSessionMaxAge is the default session max age.
 var DefaultSessionMaxAge = 1 * 24 * time.Hour
 
@@ -40,9 +24,14 @@ var (
 	BuiltInEnvPort      = "PORT"
 	BuiltInEnvHTTPSPort = "HTTPS_PORT"
 	BuiltInEnvMode      = "MODE"
+	//
+	BuiltInEnvName      = "APP_NAME"
+	BuiltInEnvNamespace = "NAMESPACE"
 
 	BuiltInEnvLogLevel = "LOG_LEVEL"
 
+	BuiltInEnvDisableBanner = "DISABLE_BANNER"
+
 	BuiltInEnvSecretKey = "SECRET_KEY"
 
 	BuiltInEnvSessionMaxAge = "SESSION_MAX_AGE"
@@ -53,6 +42,10 @@ var (
 	BuiltInEnvRedisPass = "REDIS_PASS"
 	BuiltInEnvRedisDB   = "REDIS_DB"
 
+	BuiltInEnvDatabaseDriver      = "DATABASE_DRIVER"
+	BuiltInEnvDatabaseDSN         = "DATABASE_DSN"
+	BuiltInEnvDatabaseAutoMigrate = "DATABASE_AUTO_MIGRATE"
+
 	BuiltInEnvMonitorPrometheusEnabled = "MONITOR_PROMETHEUS_ENABLED"
 	BuiltInEnvMonitorPrometheusPath    = "MONITOR_PROMETHEUS_PATH"
 
@@ -63,3 +56,21 @@ var (
 	BuiltInEnvMonitorSentryWaitForDelivery = "MONITOR_SENTRY_WAIT_FOR_DELIVERY"
 	BuiltInEnvMonitorSentryTimeout         = "MONITOR_SENTRY_TIMEOUT"
 )
+
+// DefaultGroupMatchCacheSize is the size of LRU cache of path to matched groups.
+var DefaultGroupMatchCacheSize = 4096
+
+// DefaultReadTimeout is the default read timeout of server.
+var DefaultReadTimeout = 300 * time.Second
+
+// DefaultReadHeaderTimeout is the default read header timeout of server.
+var DefaultReadHeaderTimeout = 10 * time.Second
+
+// DefaultWriteTimeout is the default write timeout of server.
+var DefaultWriteTimeout = 300 * time.Second
+
+// DefaultIdleTimeout is the default idle timeout of server.
+var DefaultIdleTimeout = 300 * time.Second
+
+// DefaultMaxHeaderBytes is the default max header bytes of server.
+var DefaultMaxHeaderBytes = 1 << 20
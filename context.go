@@ -1,9 +1,9 @@
 package zoox
 
 import (
+	"bufio"
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,12 +22,17 @@ import (
 	"github.com/go-zoox/cache"
 	"github.com/go-zoox/fs"
 	"github.com/go-zoox/i18n"
+	"github.com/go-zoox/logger"
 	"github.com/go-zoox/proxy"
 	"github.com/go-zoox/zoox/components/application/cmd"
 	"github.com/go-zoox/zoox/components/application/cron"
 	"github.com/go-zoox/zoox/components/application/debug"
 	"github.com/go-zoox/zoox/components/application/env"
 	"github.com/go-zoox/zoox/components/application/jobqueue"
+	"github.com/go-zoox/zoox/components/application/lock"
+	"github.com/go-zoox/zoox/components/application/tenancy"
+	"github.com/go-zoox/zoox/components/application/tiered"
+	"github.com/go-zoox/zoox/components/context/binding"
 	"github.com/go-zoox/zoox/components/context/body"
 	"github.com/go-zoox/zoox/components/context/form"
 	"github.com/go-zoox/zoox/components/context/mq"
@@ -47,7 +52,6 @@ import (
 	"github.com/go-zoox/fetch"
 	"github.com/go-zoox/headers"
 	"github.com/go-zoox/jwt"
-	"github.com/go-zoox/logger"
 	"github.com/go-zoox/random"
 	"github.com/go-zoox/session"
 	"github.com/go-zoox/tag"
@@ -85,7 +89,7 @@ type Context struct {
 	session session.Session
 	jwt     jwt.Jwt
 	//
-	cache cache.Cache
+	cache tiered.Cache
 	cron  cron.Cron
 	queue jobqueue.JobQueue
 	//
@@ -99,14 +103,22 @@ type Context struct {
 	// middleware
 	handlers []HandlerFunc
 	index    int
+	aborted  bool
+	// timings is enabled by ServerTiming middleware
+	timings *serverTimings
+	// writer is the reused response writer of pooled context
+	writer responseWriter
 	//
 	App *Application
-	//
+	// Logger is the logger of the app, use ctx.RequestLogger() for the request fields
 	Logger *logger.Logger
+	// requestLogger is the reused logger of pooled context, see ctx.RequestLogger
+	requestLogger RequestLogger
 	//
 	//
-	state state.State
-	user  user.User
+	state  state.State
+	user   user.User
+	tenant *tenancy.Tenant
 	//
 	cmd cmd.Cmd
 	// request id
@@ -116,8 +128,19 @@ type Context struct {
 	isUpgrade    bool
 	isUpgradeSet bool
 
+	// panicked is set by ReportPanic
+	panicked bool
+	// route is the matched route, METHOD pattern
+	route string
+	// failure is the error of ctx.Fail
+	failure error
+	// rawResponse is set by ctx.SkipEnvelope
+	rawResponse bool
+
 	// bodyBytes is used to copy body
 	bodyBytes []byte
+	// rawBody retains the body read, see ctx.EnableRawBody
+	rawBody *rawBodyReader
 
 	// once
 	once struct {
@@ -143,57 +166,143 @@ type Context struct {
 		form  sync.Once
 		body  sync.Once
 		//
-		state sync.Once
-		user  sync.Once
+		state  sync.Once
+		user   sync.Once
+		tenant sync.Once
 		//
 		cmd sync.Once
 	}
 }
 
 func newContext(app *Application, w http.ResponseWriter, req *http.Request) *Context {
-	// path := strings.TrimSuffix(req.URL.Path, "/")
-	path := req.URL.Path
-	// path := req.URL.Path
-	// if !strings.HasSuffix(path, "/") {
-	// 	path += "/"
-	// }
+	ctx := &Context{}
+	ctx.reset(app, w, req)
+	return ctx
+}
+
+// maxPooledHandlers is the max capacity of the handlers slice kept by the pooled context.
+const maxPooledHandlers = 64
 
-	writer := newResponseWriter(w)
-	ctx := &Context{
+// reset resets the context for the request, the handlers slice and response writer are reused.
+func (ctx *Context) reset(app *Application, w http.ResponseWriter, req *http.Request) {
+	// the oversized handlers slice is dropped, in order not to pin it in the pool
+	var handlers []HandlerFunc
+	if cap(ctx.handlers) <= maxPooledHandlers {
+		handlers = ctx.handlers[:0]
+	}
+
+	*ctx = Context{
 		App: app,
 		//
-		Writer: writer,
-		//
-		Request:  req,
-		Response: writer,
+		Request: req,
 		//
 		Method: req.Method,
-		Path:   path,
+		Path:   req.URL.Path,
 		//
-		index: -1,
+		handlers: handlers,
+		index:    -1,
 	}
-	//
+
+	ctx.writer = responseWriter{
+		ResponseWriter: w,
+		size:           noWritten,
+		status:         defaultStatus, // default status 200
+	}
+	ctx.Writer = &ctx.writer
+	ctx.Response = &ctx.writer
 
 	ctx.requestID = ctx.Header().Get(utils.RequestIDHeader)
 	if ctx.requestID == "" {
 		ctx.requestID = utils.GenerateRequestID()
 	}
 
-	ctx.Logger = logger.New(func(opt *logger.Option) {
-		// fmt.Println("ctx.Logger:", app.Config.LogLevel)
-		opt.Level = app.Config.LogLevel
-	})
+	// the app logger is shared, creating logger per request is expensive,
+	// the request fields are resolved when logging
+	ctx.Logger = app.Logger()
+	ctx.requestLogger = RequestLogger{logger: ctx.Logger, ctx: ctx}
+}
 
+// NewContext creates a context with the given handlers chain, run it with ctx.Next().
+// It is used to test middlewares without the router, see zooxtest.NewContext.
+func NewContext(app *Application, w http.ResponseWriter, req *http.Request, handlers ...HandlerFunc) *Context {
+	ctx := newContext(app, w, req)
+	ctx.handlers = handlers
 	return ctx
 }
 
+// Copy returns the copy of the context for the goroutines outliving the handler, such as the background jobs,
+// because the context is recycled after the request is handled. The copy is not recycled,
+// the request, params, user, tenant, state and the bound log fields are kept, the response writes are discarded.
+//
+// Example:
+//
+//	app.Post("/orders", func(ctx *zoox.Context) {
+//		c := ctx.Copy()
+//		go func() {
+//			c.RequestLogger().Infof("processing order %s", c.Param().Get("id"))
+//		}()
+//	})
+func (ctx *Context) Copy() *Context {
+	cp := newContext(ctx.App, &discardResponseWriter{header: ctx.Writer.Header().Clone()}, ctx.Request)
+	cp.param = ctx.param
+	cp.requestID = ctx.requestID
+	cp.route = ctx.route
+	cp.user = ctx.User()
+	cp.once.user.Do(func() {})
+	cp.state = ctx.State()
+	cp.once.state.Do(func() {})
+	if ctx.tenant != nil {
+		cp.tenant = ctx.tenant
+		cp.once.tenant.Do(func() {})
+	}
+	cp.requestLogger.fields = append([]logField{}, ctx.requestLogger.fields...)
+	// the handlers are not run by the copy
+	cp.aborted = true
+
+	return cp
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(code int) {}
+
 // Context returns the context
 func (ctx *Context) Context() context.Context {
 	return ctx.Request.Context()
 }
 
+// WithTimeout returns the context derived from the request with the timeout, carrying the propagation of
+// ctx.OutgoingContext, for the downstream work to stop when it is timed out or the request is cancelled.
+//
+// Example:
+//
+//	c, cancel := ctx.WithTimeout(time.Second)
+//	defer cancel()
+//
+//	err := tiered.WithContext(ctx.Cache(), c).GetOrSet(key, &value, time.Minute, produce)
+//	err = pubsub.WithContext(ctx.PubSub(), c).Publish(topic, msg)
+//	err = ctx.App.JSONRPCClient(url).Call(c, "Add", params, &sum)
+func (ctx *Context) WithTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx.OutgoingContext(), d)
+}
+
 // Next runs the next handler in the middleware stack
 func (ctx *Context) Next() {
+	// the rest handlers are skipped after abort
+	if ctx.aborted {
+		return
+	}
+
 	ctx.index++
 	s := len(ctx.handlers)
 	// for ; ctx.index < s; ctx.index ++ {
@@ -204,9 +313,43 @@ func (ctx *Context) Next() {
 		panic("Handler cannot call ctx.Next")
 	}
 
+	if done := ctx.handlerTiming(); done != nil {
+		defer done()
+	}
+
 	ctx.handlers[ctx.index](ctx)
 }
 
+// Abort prevents the pending handlers from being called, ctx.Next() becomes no-op.
+// The current handler is not stopped, and the middlewares before can check it with IsAborted after ctx.Next().
+func (ctx *Context) Abort() {
+	ctx.aborted = true
+}
+
+// IsAborted returns true if the context was aborted.
+func (ctx *Context) IsAborted() bool {
+	return ctx.aborted
+}
+
+// AbortWithStatus aborts and writes the status code.
+func (ctx *Context) AbortWithStatus(status int) {
+	ctx.Status(status)
+	ctx.Writer.WriteHeaderNow()
+	ctx.Abort()
+}
+
+// AbortWithStatusJSON aborts and writes the status code with the json body.
+func (ctx *Context) AbortWithStatusJSON(status int, obj interface{}) {
+	ctx.Abort()
+	ctx.JSON(status, obj)
+}
+
+// AbortWithError aborts and writes the error with code-message-result specification.
+func (ctx *Context) AbortWithError(status int, err error) {
+	ctx.Abort()
+	ctx.Fail(err, status, err.Error(), status)
+}
+
 // Query returns the query string parameter with the given name.
 func (ctx *Context) Query() query.Query {
 	ctx.once.query.Do(func() {
@@ -255,15 +398,43 @@ func (ctx *Context) StatusCode() int {
 }
 
 // Get alias for ctx.Header.
+//
+// Deprecated: it reads the request header, which is confusing, use ctx.Header().Get instead,
+// or ctx.Value for the per-request values.
 func (ctx *Context) Get(key string) string {
 	return ctx.Header().Get(key)
 }
 
 // Set alias for ctx.SetHeader.
+//
+// Deprecated: it sets the response header, which is confusing, use ctx.SetHeader instead,
+// or ctx.SetValue for the per-request values.
 func (ctx *Context) Set(key string, value string) {
 	ctx.SetHeader(key, value)
 }
 
+// Value returns the per-request value of the key, backed by ctx.State().
+func (ctx *Context) Value(key string) any {
+	return ctx.State().Get(key)
+}
+
+// SetValue sets the per-request value of the key, backed by ctx.State().
+func (ctx *Context) SetValue(key string, value any) {
+	ctx.State().Set(key, value)
+}
+
+// CtxValue returns the per-request value of the key with type T,
+// ok is false if the key is not set or the value is not a T.
+//
+// Example:
+//
+//	ctx.SetValue("user", &User{})
+//	user, ok := zoox.CtxValue[*User](ctx, "user")
+func CtxValue[T any](ctx *Context, key string) (value T, ok bool) {
+	value, ok = ctx.Value(key).(T)
+	return
+}
+
 // SetHeader sets a header in the response.
 func (ctx *Context) SetHeader(key string, value string) {
 	ctx.Writer.Header().Set(key, value)
@@ -274,6 +445,33 @@ func (ctx *Context) AddHeader(key string, value string) {
 	ctx.Writer.Header().Add(key, value)
 }
 
+// Hijack lets the handler take over the connection safely,
+// it returns an error instead of panic if the connection cannot be hijacked or the response (header or body) was written.
+func (ctx *Context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if u, ok := ctx.Writer.(rwUnwrapper); ok {
+		if _, ok := u.Unwrap().(http.Hijacker); !ok {
+			return nil, nil, errors.New("the response writer does not support hijacking")
+		}
+	}
+
+	// the header is committed by WriteHeaderNow without the body
+	if ctx.Writer.Written() {
+		return nil, nil, errors.New("cannot hijack the connection after the response was written")
+	}
+
+	return ctx.Writer.Hijack()
+}
+
+// Push initiates an HTTP/2 server push of the target.
+func (ctx *Context) Push(target string, opts ...*http.PushOptions) error {
+	var opt *http.PushOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	return push(ctx.Writer, target, opt)
+}
+
 // SSE sets the response header for server-sent events.
 func (ctx *Context) SSE() sse.SSE {
 	ctx.once.sse.Do(func() {
@@ -395,16 +593,38 @@ func (ctx *Context) String(status int, format string, values ...interface{}) {
 }
 
 // JSON serializes the given struct as JSON into the response body.
-func (ctx *Context) JSON(status int, obj interface{}) {
-	ctx.Status(status)
-	ctx.SetHeader(headers.ContentType, "application/json")
-	encoder := json.NewEncoder(ctx.Writer)
+// It is indented in debug mode if app.Config.JSON.PrettyInDebug is enabled.
+func (ctx *Context) JSON(status int, obj interface{}, opts ...func(opt *JSONOption)) {
+	opt := &JSONOption{
+		EscapeHTML: true,
+	}
+	if ctx.App != nil {
+		opt.Indent = ctx.App.Config.JSON.PrettyInDebug && ctx.Debug().IsDebugMode()
+		opt.EscapeHTML = !ctx.App.Config.JSON.DisableHTMLEscape
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	defer ctx.renderTiming()()
+
+	buf := getJSONBuffer()
+	defer putJSONBuffer(buf)
+
+	encoder := ctx.jsonCodec().NewEncoder(buf)
+	encoder.SetEscapeHTML(opt.EscapeHTML)
+	if opt.Indent {
+		encoder.SetIndent("", "  ")
+	}
 	if err := encoder.Encode(obj); err != nil {
 		// ctx.Error(http.StatusInternalServerError, err.Error())
 
-		ctx.Logger.Errorf("[ctx.JSON] encode error: %s", err)
+		ctx.RequestLogger().Errorf("[ctx.JSON] encode error: %s", err)
 		ctx.String(http.StatusInternalServerError, err.Error())
+		return
 	}
+
+	ctx.Data(status, "application/json", buf.Bytes())
 }
 
 // Data writes some data into the body stream and updates the HTTP code.
@@ -510,9 +730,10 @@ func (ctx *Context) Fail(err error, code int, message string, status ...int) {
 	// httprequest, _ := httputil.DumpRequest(ctx.Request, false)
 	// goErr := errors.Wrap(err, 3)
 	// reset := string([]byte{27, 91, 48, 109})
-	// ctx.Logger.Errorf("[Nice ctx.Fail] error:\n\n%s%s\n\n%s%s", httprequest, goErr.Error(), goErr.Stack(), reset)
+	// ctx.RequestLogger().Errorf("[Nice ctx.Fail] error:\n\n%s%s\n\n%s%s", httprequest, goErr.Error(), goErr.Stack(), reset)
 
-	ctx.Logger.Infof("[ctx.Fail] error: %s", err)
+	ctx.RequestLogger().Infof("[ctx.Fail] error: %s", err)
+	ctx.failure = err
 
 	if ok := ctx.Debug().IsDebugMode(); ok {
 		fmt.Println("[ctx.Fail] error stack: \n", string(rd.Stack())+"\n")
@@ -692,7 +913,7 @@ func (ctx *Context) Bodies() map[string]any {
 	var bodies map[string]any
 
 	if bytes, err := ctx.BodyBytes(); err == nil {
-		if err := json.Unmarshal(bytes, &bodies); err == nil {
+		if err := ctx.jsonCodec().Unmarshal(bytes, &bodies); err == nil {
 			return bodies
 		}
 	}
@@ -747,7 +968,8 @@ func (ctx *Context) GetRawData() ([]byte, error) {
 	return ioutil.ReadAll(ctx.Request.Body)
 }
 
-// BindJSON binds the request body into the given struct.
+// BindJSON binds the request body into the given struct,
+// the options of the json tags are checked as BindForm, such as `json:"limit,omitempty,min=1,max=100"`.
 func (ctx *Context) BindJSON(obj interface{}) (err error) {
 	if !strings.Contains(ctx.Header().Get("Content-Type"), "application/json") {
 		return errors.New("[BindJSON] content-type is not json")
@@ -764,10 +986,15 @@ func (ctx *Context) BindJSON(obj interface{}) (err error) {
 			return fmt.Errorf("failed to read request body: %v", err)
 		}
 
-		ctx.Logger.Infof("[debug][ctx.BindJSON] body: %s", ctx.bodyBytes)
+		ctx.RequestLogger().Infof("[debug][ctx.BindJSON] body: %s", ctx.bodyBytes)
+	}
+
+	decoder := ctx.jsonCodec().NewDecoder(ctx.Request.Body)
+	if ctx.App != nil && ctx.App.Config.JSON.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
 	}
 
-	if err := json.NewDecoder(ctx.Request.Body).Decode(obj); err != nil {
+	if err := decoder.Decode(obj); err != nil {
 		// @TODO allow empty body
 		if err == io.EOF {
 			return nil
@@ -781,7 +1008,8 @@ func (ctx *Context) BindJSON(obj interface{}) (err error) {
 		return err
 	}
 
-	return nil
+	// the registered types (RegisterBinder) and the options of the json tags are validated as the other sources
+	return binding.Check(obj, "json")
 }
 
 // BindYAML binds the request body into the given struct.
@@ -797,83 +1025,129 @@ func (ctx *Context) BindYAML(obj interface{}) (err error) {
 			return fmt.Errorf("failed to read request body: %v", err)
 		}
 
-		ctx.Logger.Infof("[debug][ctx.BindYAML] body: %v", ctx.bodyBytes)
+		ctx.RequestLogger().Infof("[debug][ctx.BindYAML] body: %v", ctx.bodyBytes)
 	}
 
-	return yaml.NewDecoder(ctx.Request.Body).Decode(obj)
-}
+	if err := yaml.NewDecoder(ctx.Request.Body).Decode(obj); err != nil {
+		return err
+	}
 
-// BindForm binds the query into the given struct.
+	// the options are declared in the json tags, yaml rejects the unknown options of its tags
+	return binding.Check(obj, "json")
+}
+
+// BindForm binds the form (urlencoded or multipart) into the given struct,
+// which supports slices, nested structs (address.city), maps (meta[key]), time layouts, defaults and files.
+//
+// Example:
+//
+//	type SignupForm struct {
+//		Name     string                `form:"name,required"`
+//		Tags     []string              `form:"tags"`
+//		Birthday time.Time             `form:"birthday,layout=2006-01-02"`
+//		Page     int                   `form:"page,default=1"`
+//		Plan     string                `form:"plan,enum=free|pro"`
+//		Age      int                   `form:"age,omitempty,min=18,max=120"`
+//		Address  Address               `form:"address"` // address.city
+//		Meta     map[string]string     `form:"meta"`    // meta[key]
+//		Avatar   *multipart.FileHeader `form:"avatar"`
+//	}
 func (ctx *Context) BindForm(obj interface{}) error {
-	forms, err := ctx.Forms()
-	if err != nil {
+	if _, err := ctx.Forms(); err != nil {
 		return err
 	}
 
+	src := &binding.Source{
+		Values: ctx.Request.Form,
+	}
+
+	if strings.Contains(ctx.Header().Get("Content-Type"), "multipart/form-data") {
+		if err := ctx.Request.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		}
+
+		if ctx.Request.MultipartForm != nil {
+			src.Values = ctx.Request.Form
+			src.Files = ctx.Request.MultipartForm.File
+		}
+	}
+
 	if ctx.Debug().IsDebugMode() {
-		ctx.Logger.Infof("[debug][ctx.BindForm]")
-		for k, v := range forms.ToMap() {
-			ctx.Logger.Infof("[debug][ctx.BindForm][detail] %s = %s", k, v)
+		ctx.RequestLogger().Infof("[debug][ctx.BindForm]")
+		for k, v := range src.Values {
+			ctx.RequestLogger().Infof("[debug][ctx.BindForm][detail] %s = %s", k, v)
 		}
 	}
 
-	return tag.New("form", datasource.GetterToDataSource(forms)).Decode(obj)
+	return binding.Decode("form", src, obj)
 }
 
 // BindParams binds the params into the given struct.
 func (ctx *Context) BindParams(obj interface{}) error {
-	params := ctx.Params()
+	params := map[string][]string{}
+	if ctx.param != nil {
+		for k, v := range ctx.param.Iterator() {
+			params[k] = []string{v}
+		}
+	}
+
 	if ctx.Debug().IsDebugMode() {
-		ctx.Logger.Infof("[debug][ctx.BindParams]")
-		for k, v := range params.ToMap() {
-			ctx.Logger.Infof("[debug][ctx.BindParams][detail] %s = %s", k, v)
+		ctx.RequestLogger().Infof("[debug][ctx.BindParams]")
+		for k, v := range params {
+			ctx.RequestLogger().Infof("[debug][ctx.BindParams][detail] %s = %s", k, v)
 		}
 	}
 
-	return tag.New("param", datasource.GetterToDataSource(params)).Decode(obj)
+	return binding.Decode("param", &binding.Source{Values: params}, obj)
 }
 
 // BindHeader binds the header into the given struct.
 func (ctx *Context) BindHeader(obj interface{}) error {
-	headers := ctx.Headers()
+	headers := ctx.Request.Header
 	if ctx.Debug().IsDebugMode() {
-		ctx.Logger.Infof("[debug][ctx.BindHeader]")
-		for k, v := range headers.ToMap() {
-			ctx.Logger.Infof("[debug][ctx.BindHeader][detail] %s = %s", k, v)
+		ctx.RequestLogger().Infof("[debug][ctx.BindHeader]")
+		for k, v := range headers {
+			ctx.RequestLogger().Infof("[debug][ctx.BindHeader][detail] %s = %s", k, v)
 		}
 	}
 
-	return tag.New("header", datasource.GetterToDataSource(headers)).Decode(obj)
+	return binding.Decode("header", &binding.Source{Values: headers, Key: http.CanonicalHeaderKey}, obj)
 }
 
 // BindQuery binds the query into the given struct.
 func (ctx *Context) BindQuery(obj interface{}) error {
-	queries := ctx.Queries()
+	queries := ctx.Request.URL.Query()
 	if ctx.Debug().IsDebugMode() {
-		ctx.Logger.Infof("[debug][ctx.BindQuery]")
-		for k, v := range queries.ToMap() {
-			ctx.Logger.Infof("[debug][ctx.BindQuery][detail] %s = %s", k, v)
+		ctx.RequestLogger().Infof("[debug][ctx.BindQuery]")
+		for k, v := range queries {
+			ctx.RequestLogger().Infof("[debug][ctx.BindQuery][detail] %s = %s", k, v)
 		}
 	}
 
-	return tag.New("query", datasource.GetterToDataSource(queries)).Decode(obj)
+	return binding.Decode("query", &binding.Source{Values: queries}, obj)
 }
 
 // BindBody binds the body into the given struct.
 func (ctx *Context) BindBody(obj interface{}) error {
 	data := ctx.Bodies()
 	if ctx.Debug().IsDebugMode() {
-		ctx.Logger.Infof("[debug][ctx.BindBody]")
+		ctx.RequestLogger().Infof("[debug][ctx.BindBody]")
 		for k, v := range data {
-			ctx.Logger.Infof("[debug][ctx.BindBody][detail] %s = %v", k, v)
+			ctx.RequestLogger().Infof("[debug][ctx.BindBody][detail] %s = %v", k, v)
 		}
 	}
 
 	return tag.New("body", datasource.NewMapDataSource(data)).Decode(obj)
 }
 
-// SaveFile saves the file to the given path.
+// SaveFile saves the file to the given path,
+// the file is scanned before saved if the upload scanner is configured, see ctx.SaveScannedFile.
 func (ctx *Context) SaveFile(key, path string) error {
+	if ctx.App.UploadScanner() != nil {
+		_, err := ctx.SaveScannedFile(key, path)
+		return err
+	}
+
 	src, _, err := ctx.Request.FormFile(key)
 	if err != nil {
 		return err
@@ -909,10 +1183,23 @@ func (ctx *Context) AcceptHTML() bool {
 	return strings.Contains(ctx.Header().Get(headers.Accept), "text/html")
 }
 
-// Cache returns the cache of the application.
+// Lock acquires the distributed lock, blocks until acquired or the request is cancelled,
+// the lock is renewed until released, see app.Lock.
+func (ctx *Context) Lock(name string, ttl time.Duration) (lock.Lock, error) {
+	return ctx.App.Locker().Acquire(ctx.Context(), name, ttl)
+}
+
+// Cache returns the cache of the application, keys are scoped with the tenant if resolved.
+// The cache is bound to ctx.Context(), so it stops when the request is cancelled, see tiered.WithContext.
+//
+// The cache implements tiered.Cache, the tags and GetOrSet are used with tiered.From(ctx.Cache()).
 func (ctx *Context) Cache() cache.Cache {
 	ctx.once.cache.Do(func() {
-		ctx.cache = ctx.App.Cache()
+		ctx.cache = ctx.App.tieredCache()
+		if tenant := ctx.Tenant(); tenant != nil {
+			ctx.cache = tenancy.Cache(ctx.cache, tenant)
+		}
+		ctx.cache = tiered.WithContext(ctx.cache, ctx.Context())
 	})
 
 	return ctx.cache
@@ -972,6 +1259,15 @@ func (ctx *Context) State() state.State {
 	return ctx.state
 }
 
+// Tenant returns the tenant of the request resolved by app.Tenancy(), nil if not resolved.
+func (ctx *Context) Tenant() *tenancy.Tenant {
+	ctx.once.tenant.Do(func() {
+		ctx.tenant = ctx.App.Tenancy().Resolve(ctx.Request)
+	})
+
+	return ctx.tenant
+}
+
 // User returns the user of the
 func (ctx *Context) User() user.User {
 	ctx.once.user.Do(func() {
@@ -1002,7 +1298,8 @@ func (ctx *Context) Cookie() cookie.Cookie {
 	return ctx.cookie
 }
 
-// Session returns the session of the request.
+// Session returns the session of the request, scoped with the tenant if resolved,
+// the session cookie is named with app.Config.Namespace if configured, such as myapp.production.gsession.
 func (ctx *Context) Session() session.Session {
 	ctx.once.session.Do(func() {
 		secretKey := ctx.App.Config.SecretKey
@@ -1010,12 +1307,47 @@ func (ctx *Context) Session() session.Session {
 			secretKey = "go-zoox_" + random.String(24)
 		}
 
-		ctx.session = session.New(ctx.Cookie(), secretKey, &ctx.App.Config.Session)
+		// sessions of one tenant are invalid in other tenants
+		if tenant := ctx.Tenant(); tenant != nil {
+			secretKey = tenant.Key(secretKey)
+		}
+
+		// the cookie is not renamed without the configured namespace, so that the sessions survive the upgrade
+		cookie := ctx.Cookie()
+		if ctx.App.Config.Namespace != "" {
+			cookie = &namespacedCookie{
+				Cookie: cookie,
+				prefix: strings.ReplaceAll(ctx.App.Config.Namespace, ":", ".") + ".",
+			}
+		}
+		if ctx.App.Config.Encryption.Session {
+			cookie = &encryptedCookie{Cookie: cookie, keyring: &appKeyring{app: ctx.App}, logger: ctx.RequestLogger()}
+		}
+
+		ctx.session = session.New(cookie, secretKey, &ctx.App.Config.Session)
 	})
 
 	return ctx.session
 }
 
+// namespacedCookie prefixes the cookie names, so that the apps on the same domain do not share the cookies.
+type namespacedCookie struct {
+	cookie.Cookie
+	prefix string
+}
+
+func (c *namespacedCookie) Set(name string, value string, cfg ...*cookie.Config) {
+	c.Cookie.Set(c.prefix+name, value, cfg...)
+}
+
+func (c *namespacedCookie) Get(name string) string {
+	return c.Cookie.Get(c.prefix + name)
+}
+
+func (c *namespacedCookie) Del(name string) {
+	c.Cookie.Del(c.prefix + name)
+}
+
 // Jwt returns the jwt of the request.
 func (ctx *Context) Jwt() jwt.Jwt {
 	ctx.once.jwt.Do(func() {
@@ -1093,3 +1425,32 @@ func (ctx *Context) MQ() mq.MQ {
 func (ctx *Context) Concurrency(limit int) *concurrency.Concurrency {
 	return concurrency.New(limit)
 }
+
+// ErrorHandler returns the error handler of the request path (group or app), nil if not set.
+func (ctx *Context) ErrorHandler() ErrorHandlerFunc {
+	if ctx.App == nil {
+		return nil
+	}
+
+	return ctx.App.errorHandlerOf(ctx.Path)
+}
+
+// HandleError handles the error with the group or app error handler,
+// by default, HTTPError is written with FailWithError, others are 500.
+// The server errors (not HTTPError or 5xx) are reported to the error reporter (config error_report).
+func (ctx *Context) HandleError(err error) {
+	ctx.reportServerError(err)
+
+	if handler := ctx.ErrorHandler(); handler != nil {
+		handler(ctx, err)
+		return
+	}
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		ctx.FailWithError(httpErr)
+		return
+	}
+
+	ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+}
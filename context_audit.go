@@ -0,0 +1,31 @@
+package zoox
+
+import (
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/audit"
+)
+
+// Audit writes the audit entry of the action on the target,
+// with the actor (ctx.User), client ip and request id of the request.
+//
+// Example:
+//
+//	ctx.Audit("user.delete", "user:"+id, map[string]any{"reason": reason})
+func (ctx *Context) Audit(action, target string, metadata ...map[string]any) error {
+	entry := &audit.Entry{
+		Action:    action,
+		Target:    target,
+		Actor:     ctx.User().Get(),
+		IP:        ctx.ClientIP(),
+		RequestID: ctx.RequestID(),
+		Method:    ctx.Method,
+		Path:      ctx.Path,
+		Timestamp: time.Now(),
+	}
+	if len(metadata) > 0 {
+		entry.Metadata = metadata[0]
+	}
+
+	return ctx.App.Audit().Log(entry)
+}
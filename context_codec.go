@@ -0,0 +1,185 @@
+package zoox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox/components/context/binding"
+	"github.com/go-zoox/zoox/components/context/msgpack"
+	vmsgpack "github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content types of the builtin renderers and binders.
+const (
+	MIMEJSON      = "application/json"
+	MIMEYAML      = "application/yaml"
+	MIMEForm      = "application/x-www-form-urlencoded"
+	MIMEMultipart = "multipart/form-data"
+	MIMEMsgpack   = "application/msgpack"
+	MIMEProtoBuf  = "application/x-protobuf"
+)
+
+// Codec is the marshaler of a content type, such as msgpack.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// SetMsgpackCodec replaces the msgpack codec used by ctx.Msgpack, ctx.BindMsgpack and JSONRPCWebSocket,
+// such as the dependency free fallback msgpack.Codec{} (components/context/msgpack).
+func (app *Application) SetMsgpackCodec(codec Codec) {
+	app.msgpackCodec = codec
+}
+
+// MsgpackCodec returns the msgpack codec, default: github.com/vmihailenco/msgpack/v5 with json tags.
+// The data nested deeper than msgpack.MaxDepth is rejected before it reaches the codec.
+func (app *Application) MsgpackCodec() Codec {
+	if app.msgpackCodec == nil {
+		return &depthLimitedCodec{Codec: defaultMsgpackCodec{}}
+	}
+
+	return &depthLimitedCodec{Codec: app.msgpackCodec}
+}
+
+// defaultMsgpackCodec is github.com/vmihailenco/msgpack/v5, the json tags apply as the builtin codec.
+type defaultMsgpackCodec struct{}
+
+func (defaultMsgpackCodec) Marshal(v any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := vmsgpack.NewEncoder(buf)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (defaultMsgpackCodec) Unmarshal(data []byte, v any) error {
+	decoder := vmsgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+// depthLimitedCodec rejects deeply nested data, the decoders recurse per level and overflow the stack,
+// which crashes the process and can't be recovered.
+type depthLimitedCodec struct {
+	Codec
+}
+
+func (c *depthLimitedCodec) Unmarshal(data []byte, v any) error {
+	if err := msgpack.CheckDepth(data, msgpack.MaxDepth); err != nil {
+		return err
+	}
+
+	return c.Codec.Unmarshal(data, v)
+}
+
+// SetCBORCodec sets the cbor codec, such as github.com/fxamacker/cbor/v2,
+// which enables the cbor subprotocol of JSONRPCWebSocket.
+func (app *Application) SetCBORCodec(codec Codec) {
+	app.cborCodec = codec
+}
+
+// CBORCodec returns the cbor codec, nil if not set.
+func (app *Application) CBORCodec() Codec {
+	return app.cborCodec
+}
+
+// Msgpack serializes the given struct as msgpack into the response body.
+func (ctx *Context) Msgpack(status int, obj interface{}) {
+	data, err := ctx.App.MsgpackCodec().Marshal(obj)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[ctx.Msgpack] encode error: %s", err)
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(status, MIMEMsgpack, data)
+}
+
+// BindMsgpack binds the msgpack request body into the given struct.
+func (ctx *Context) BindMsgpack(obj interface{}) error {
+	data, err := ctx.readBody()
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.App.MsgpackCodec().Unmarshal(data, obj); err != nil {
+		return err
+	}
+
+	// the default codec decodes with the json tags
+	return binding.Check(obj, "json")
+}
+
+// ProtoBuf serializes the given message as protobuf into the response body.
+func (ctx *Context) ProtoBuf(status int, m proto.Message) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[ctx.ProtoBuf] encode error: %s", err)
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(status, MIMEProtoBuf, data)
+}
+
+// BindProto binds the protobuf request body into the given message.
+func (ctx *Context) BindProto(m proto.Message) error {
+	data, err := ctx.readBody()
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, m)
+}
+
+// Bind binds the request body into obj by the content type,
+// supports json, yaml, form, multipart, msgpack and protobuf.
+func (ctx *Context) Bind(obj interface{}) error {
+	contentType := ctx.ContentType()
+	switch {
+	case strings.Contains(contentType, "json"):
+		return ctx.BindJSON(obj)
+	case strings.Contains(contentType, "yaml"):
+		return ctx.BindYAML(obj)
+	case strings.Contains(contentType, MIMEForm), strings.Contains(contentType, MIMEMultipart):
+		return ctx.BindForm(obj)
+	case strings.Contains(contentType, "msgpack"):
+		return ctx.BindMsgpack(obj)
+	case strings.Contains(contentType, "protobuf"):
+		m, ok := obj.(proto.Message)
+		if !ok {
+			return fmt.Errorf("[Bind] %T is not a proto.Message", obj)
+		}
+
+		return ctx.BindProto(m)
+	}
+
+	return fmt.Errorf("[Bind] unsupported content-type: %s", ctx.Header().Get(headers.ContentType))
+}
+
+func (ctx *Context) readBody() ([]byte, error) {
+	if ctx.Request.Body == nil {
+		return nil, errors.New("invalid request")
+	}
+
+	data, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		if err.Error() == "http: request body too large" {
+			return nil, errors.New("request body too large")
+		}
+
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+
+	return data, nil
+}
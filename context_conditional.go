@@ -0,0 +1,147 @@
+package zoox
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/headers"
+)
+
+// ValidatorFunc computes the validators of the response, empty etag or zero lastModified are not used.
+type ValidatorFunc func() (etag string, lastModified time.Time)
+
+// SetETag sets the response ETag header, the tag is quoted if not, weak adds the W/ prefix.
+func (ctx *Context) SetETag(tag string, weak ...bool) {
+	if !strings.HasPrefix(tag, `"`) && !strings.HasPrefix(tag, `W/"`) {
+		tag = `"` + tag + `"`
+	}
+	if len(weak) > 0 && weak[0] && !strings.HasPrefix(tag, "W/") {
+		tag = "W/" + tag
+	}
+
+	ctx.SetHeader("ETag", tag)
+}
+
+// SetLastModified sets the response Last-Modified header, in http date format (seconds precision).
+func (ctx *Context) SetLastModified(t time.Time) {
+	ctx.SetHeader("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// NotModified evaluates the request preconditions (If-None-Match, If-Modified-Since, If-Match, If-Unmodified-Since)
+// against the response ETag and Last-Modified, set by ctx.SetETag / ctx.SetLastModified or the checker,
+// responds 304 Not Modified (GET, HEAD) or 412 Precondition Failed and returns true if the handler should stop.
+//
+//	app.Get("/articles/:id", func(ctx *zoox.Context) {
+//		article := getArticle(ctx.Param().Get("id").String())
+//		if ctx.NotModified(func() (string, time.Time) {
+//			return article.Version, article.UpdatedAt
+//		}) {
+//			return
+//		}
+//
+//		ctx.JSON(http.StatusOK, article)
+//	})
+func (ctx *Context) NotModified(checker ...ValidatorFunc) bool {
+	if len(checker) > 0 && checker[0] != nil {
+		etag, lastModified := checker[0]()
+		if etag != "" {
+			ctx.SetETag(etag)
+		}
+		if !lastModified.IsZero() {
+			ctx.SetLastModified(lastModified)
+		}
+	}
+
+	header := ctx.Writer.Header()
+	etag := header.Get("ETag")
+	lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+	safe := ctx.Method == http.MethodGet || ctx.Method == http.MethodHead
+
+	// If-Match and If-Unmodified-Since protect updates of changed resources (lost updates)
+	if ifMatch := ctx.Request.Header.Get("If-Match"); ifMatch != "" {
+		if !matchETag(ifMatch, etag, false) {
+			ctx.preconditionFailed()
+			return true
+		}
+	} else if since, err := http.ParseTime(ctx.Request.Header.Get("If-Unmodified-Since")); err == nil && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(since) {
+			ctx.preconditionFailed()
+			return true
+		}
+	}
+
+	// If-Modified-Since is ignored if If-None-Match is present (RFC 9110 13.1.3)
+	if ifNoneMatch := ctx.Request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if !matchETag(ifNoneMatch, etag, true) {
+			return false
+		}
+
+		if safe {
+			ctx.writeNotModified()
+		} else {
+			ctx.preconditionFailed()
+		}
+		return true
+	}
+
+	if !safe || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ctx.Request.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+
+	ctx.writeNotModified()
+	return true
+}
+
+func (ctx *Context) writeNotModified() {
+	header := ctx.Writer.Header()
+	// RFC 9110 15.4.5: the representation headers are not sent
+	header.Del(headers.ContentType)
+	header.Del(headers.ContentLength)
+	header.Del("Content-Encoding")
+	if header.Get("ETag") != "" {
+		header.Del("Last-Modified")
+	}
+
+	ctx.Status(http.StatusNotModified)
+	ctx.Writer.WriteHeaderNow()
+}
+
+func (ctx *Context) preconditionFailed() {
+	ctx.Status(http.StatusPreconditionFailed)
+	ctx.Writer.WriteHeaderNow()
+}
+
+// matchETag matches the etag against the If-Match / If-None-Match list,
+// weak comparison ignores the W/ prefix, * matches any existing etag.
+func matchETag(list string, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+			continue
+		}
+
+		// strong comparison, weak tags never match
+		if !strings.HasPrefix(candidate, "W/") && !strings.HasPrefix(etag, "W/") && candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
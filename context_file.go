@@ -0,0 +1,198 @@
+package zoox
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileOption is the option of ctx.ServeFile and ctx.ServeContent.
+type FileOption struct {
+	// Name is the file name of Content-Disposition, default the base name of the file.
+	Name string
+	// Attachment downloads the file (Content-Disposition: attachment) instead of displaying it inline.
+	Attachment bool
+	// RateLimit limits the throughput of the response in bytes per second, 0 means no limit.
+	RateLimit int64
+}
+
+// ServeFile serves the file with HTTP Range (single and multi-range) and If-Range support,
+// so large file downloads can resume.
+//
+//	app.Get("/files/:name", func(ctx *zoox.Context) {
+//		ctx.ServeFile(path.Join(dir, ctx.Param().Get("name").String()), &zoox.FileOption{
+//			Attachment: true,
+//			RateLimit:  1024 * 1024,
+//		})
+//	})
+func (ctx *Context) ServeFile(path string, opts ...*FileOption) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			ctx.Fail(err, http.StatusNotFound, "file not found", http.StatusNotFound)
+			return
+		}
+
+		ctx.Fail(err, http.StatusInternalServerError, "failed to open file", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		ctx.Fail(err, http.StatusInternalServerError, "failed to stat file", http.StatusInternalServerError)
+		return
+	}
+	if stat.IsDir() {
+		ctx.Fail(fmt.Errorf("%s is a directory", path), http.StatusNotFound, "file not found", http.StatusNotFound)
+		return
+	}
+
+	opt := &FileOption{}
+	if len(opts) > 0 && opts[0] != nil {
+		*opt = *opts[0]
+	}
+	if opt.Name == "" {
+		opt.Name = stat.Name()
+	}
+
+	ctx.ServeContent(opt.Name, stat.ModTime(), f, opt)
+}
+
+// Download serves the file as attachment with the name, such as report.pdf.
+func (ctx *Context) Download(path string, name ...string) {
+	opt := &FileOption{Attachment: true}
+	if len(name) > 0 {
+		opt.Name = name[0]
+	}
+
+	ctx.ServeFile(path, opt)
+}
+
+// ServeContent serves the content with HTTP Range and If-Range support, the content type is detected by the name.
+// Set the ETag header before to validate If-Range and If-None-Match with it instead of the modtime.
+func (ctx *Context) ServeContent(name string, modtime time.Time, content io.ReadSeeker, opts ...*FileOption) {
+	opt := &FileOption{}
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+
+	if name != "" {
+		if opt.Attachment {
+			ctx.SetContentDisposition(name)
+		} else {
+			ctx.SetContentDispositionInline(name)
+		}
+	}
+
+	var w http.ResponseWriter = ctx.Writer
+	if opt.RateLimit > 0 {
+		w = newThrottledWriter(ctx.Writer, opt.RateLimit, ctx.Request.Context().Done())
+	}
+
+	http.ServeContent(w, ctx.Request, name, modtime, content)
+}
+
+// ContentDisposition returns the Content-Disposition header value (RFC 6266) of the file name,
+// with the UTF-8 encoded filename* for non-ASCII names.
+func ContentDisposition(attachment bool, filename string) string {
+	disposition := "inline"
+	if attachment {
+		disposition = "attachment"
+	}
+
+	filename = filepath.Base(filename)
+	if filename == "." || filename == "/" {
+		return disposition
+	}
+
+	ascii := true
+	for _, r := range filename {
+		if r > 127 || r < 32 {
+			ascii = false
+			break
+		}
+	}
+
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(filename)
+	if ascii {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, quoted)
+	}
+
+	// the fallback filename of old clients, non-ASCII characters are replaced
+	fallback := strings.Map(func(r rune) rune {
+		if r > 127 || r < 32 {
+			return '_'
+		}
+		return r
+	}, quoted)
+
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, strings.ReplaceAll(url.PathEscape(filename), "+", "%2B"))
+}
+
+// throttledWriter limits the write throughput in bytes per second.
+type throttledWriter struct {
+	ResponseWriter
+	rate      int64
+	done      <-chan struct{}
+	startedAt time.Time
+	written   int64
+}
+
+func newThrottledWriter(w ResponseWriter, rate int64, done <-chan struct{}) *throttledWriter {
+	return &throttledWriter{
+		ResponseWriter: w,
+		rate:           rate,
+		done:           done,
+	}
+}
+
+func (w *throttledWriter) Write(b []byte) (int, error) {
+	if w.startedAt.IsZero() {
+		w.startedAt = time.Now()
+	}
+
+	// chunks of at most 1/10 second, so the throughput is smooth
+	chunk := w.rate / 10
+	if chunk < 1 {
+		chunk = 1
+	}
+
+	total := 0
+	for len(b) > 0 {
+		size := int64(len(b))
+		if size > chunk {
+			size = chunk
+		}
+
+		n, err := w.ResponseWriter.Write(b[:size])
+		total += n
+		w.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+		b = b[size:]
+
+		expected := time.Duration(float64(w.written) / float64(w.rate) * float64(time.Second))
+		if wait := expected - time.Since(w.startedAt); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-w.done:
+				timer.Stop()
+				return total, http.ErrAbortHandler
+			}
+		}
+	}
+
+	return total, nil
+}
+
+func (w *throttledWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
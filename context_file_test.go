@@ -0,0 +1,159 @@
+package zoox
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fileContent = "0123456789abcdefghij"
+
+var fileModTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newFileApp(etag string) *Application {
+	app := New()
+	app.Get("/file", func(ctx *Context) {
+		if etag != "" {
+			ctx.SetHeader("ETag", etag)
+		}
+
+		ctx.ServeContent("file.txt", fileModTime, strings.NewReader(fileContent))
+	})
+
+	return app
+}
+
+func serveFile(app *Application, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/file", nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestServeContentRange(t *testing.T) {
+	app := newFileApp("")
+
+	res := serveFile(app)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "bytes", res.Header().Get("Accept-Ranges"))
+	assert.Equal(t, fileContent, res.Body.String())
+	assert.Equal(t, `inline; filename="file.txt"`, res.Header().Get("Content-Disposition"))
+
+	res = serveFile(app, "Range", "bytes=0-4")
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+	assert.Equal(t, "bytes 0-4/20", res.Header().Get("Content-Range"))
+	assert.Equal(t, "01234", res.Body.String())
+
+	// resume from the offset
+	res = serveFile(app, "Range", "bytes=15-")
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+	assert.Equal(t, "bytes 15-19/20", res.Header().Get("Content-Range"))
+	assert.Equal(t, "fghij", res.Body.String())
+
+	// the suffix
+	res = serveFile(app, "Range", "bytes=-3")
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+	assert.Equal(t, "hij", res.Body.String())
+}
+
+func TestServeContentMultiRange(t *testing.T) {
+	res := serveFile(newFileApp(""), "Range", "bytes=0-1,10-12")
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+
+	mediaType, params, err := mime.ParseMediaType(res.Header().Get("Content-Type"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "multipart/byteranges", mediaType)
+
+	reader := multipart.NewReader(res.Body, params["boundary"])
+	expected := []struct{ contentRange, body string }{
+		{"bytes 0-1/20", "01"},
+		{"bytes 10-12/20", "abc"},
+	}
+	for _, e := range expected {
+		part, err := reader.NextPart()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		body, _ := io.ReadAll(part)
+		assert.Equal(t, e.contentRange, part.Header.Get("Content-Range"))
+		assert.Equal(t, e.body, string(body))
+	}
+
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestServeContentUnsatisfiableRange(t *testing.T) {
+	res := serveFile(newFileApp(""), "Range", "bytes=100-200")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, res.Code)
+	assert.Equal(t, "bytes */20", res.Header().Get("Content-Range"))
+}
+
+func TestServeContentIfRange(t *testing.T) {
+	// the etag is validated if set
+	app := newFileApp(`"v2"`)
+
+	res := serveFile(app, "Range", "bytes=0-4", "If-Range", `"v2"`)
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+	assert.Equal(t, "01234", res.Body.String())
+
+	// changed, the full content is responded
+	res = serveFile(app, "Range", "bytes=0-4", "If-Range", `"v1"`)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, fileContent, res.Body.String())
+
+	// the modtime is validated without etag
+	app = newFileApp("")
+
+	res = serveFile(app, "Range", "bytes=0-4", "If-Range", fileModTime.Format(http.TimeFormat))
+	assert.Equal(t, http.StatusPartialContent, res.Code)
+	assert.Equal(t, "01234", res.Body.String())
+
+	res = serveFile(app, "Range", "bytes=0-4", "If-Range", fileModTime.Add(-time.Hour).Format(http.TimeFormat))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, fileContent, res.Body.String())
+}
+
+func TestServeFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(path, []byte(fileContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := New()
+	app.Get("/download", func(ctx *Context) {
+		ctx.Download(path, "报告.txt")
+	})
+	app.Get("/missing", func(ctx *Context) {
+		ctx.ServeFile(filepath.Join(dir, "missing.txt"))
+	})
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/download", nil)
+	req.Header.Set("Range", "bytes=10-")
+	app.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusPartialContent, recorder.Code)
+	assert.Equal(t, "abcdefghij", recorder.Body.String())
+	assert.Equal(t, `attachment; filename="__.txt"; filename*=UTF-8''%E6%8A%A5%E5%91%8A.txt`, recorder.Header().Get("Content-Disposition"))
+
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,25 @@
+package zoox
+
+import "github.com/go-zoox/zoox/components/application/flags"
+
+// Flag returns true if the feature flag is on for the request target.
+//
+// Example:
+//
+//	if ctx.Flag("new-checkout") {
+//		return newCheckout(ctx)
+//	}
+func (ctx *Context) Flag(key string) bool {
+	return ctx.App.Flags().IsEnabled(key, ctx.FlagTarget())
+}
+
+// FlagTarget returns the flag target of the request, see app.SetFlagTarget.
+func (ctx *Context) FlagTarget() *flags.Target {
+	if ctx.App.flagTarget != nil {
+		return ctx.App.flagTarget(ctx)
+	}
+
+	return &flags.Target{
+		ID: ctx.ClientIP(),
+	}
+}
@@ -99,12 +99,12 @@ func (ctx *Context) SetPoweredBy(value string) {
 
 // SetContentDisposition sets the request content-disposition header with attachment.
 func (ctx *Context) SetContentDisposition(filename string) {
-	ctx.SetHeader(headers.ContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.SetHeader(headers.ContentDisposition, ContentDisposition(true, filename))
 }
 
 // SetContentDispositionInline sets the request content-disposition header with inline.
 func (ctx *Context) SetContentDispositionInline(filename string) {
-	ctx.SetHeader(headers.ContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", filename))
+	ctx.SetHeader(headers.ContentDisposition, ContentDisposition(false, filename))
 }
 
 // SetDownloadFilename sets the request content-disposition header with attachment and filename.
@@ -0,0 +1,85 @@
+package zoox
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-zoox/headers"
+)
+
+// DefaultSecureJSONPrefix is the default prefix of ctx.SecureJSON.
+const DefaultSecureJSONPrefix = "while(1);"
+
+var jsonpCallbackRe = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$.]*$`)
+
+// IndentedJSON serializes the given struct as pretty JSON into the response body.
+func (ctx *Context) IndentedJSON(status int, obj interface{}) {
+	data, err := ctx.marshalIndentJSON(obj)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[ctx.IndentedJSON] encode error: %s", err)
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(status, "application/json", data)
+}
+
+// SecureJSON serializes the given struct as JSON with the anti-hijacking prefix,
+// the prefix is app.Config.JSON.SecurePrefix, default: while(1);
+func (ctx *Context) SecureJSON(status int, obj interface{}) {
+	data, err := ctx.jsonCodec().Marshal(obj)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[ctx.SecureJSON] encode error: %s", err)
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// only json arrays can be hijacked
+	if bytes.HasPrefix(data, []byte("[")) {
+		prefix := DefaultSecureJSONPrefix
+		if ctx.App != nil && ctx.App.Config.JSON.SecurePrefix != "" {
+			prefix = ctx.App.Config.JSON.SecurePrefix
+		}
+
+		data = append([]byte(prefix), data...)
+	}
+
+	ctx.Data(status, "application/json", data)
+}
+
+// JSONP serializes the given struct as JSON wrapped with the callback,
+// it falls back to JSON if the callback is empty.
+func (ctx *Context) JSONP(status int, callback string, obj interface{}) {
+	if callback == "" {
+		ctx.JSON(status, obj)
+		return
+	}
+
+	if !jsonpCallbackRe.MatchString(callback) {
+		ctx.Error(http.StatusBadRequest, "invalid jsonp callback")
+		return
+	}
+
+	data, err := ctx.jsonCodec().Marshal(obj)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[ctx.JSONP] encode error: %s", err)
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.SetHeader(headers.XContentTypeOptions, "nosniff")
+	ctx.Data(status, "application/javascript; charset=utf-8", []byte(fmt.Sprintf("/**/ typeof %s === 'function' && %s(%s);", callback, callback, data)))
+}
+
+func (ctx *Context) marshalIndentJSON(obj interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := ctx.jsonCodec().NewEncoder(buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(obj); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,237 @@
+package zoox
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-zoox/logger"
+)
+
+// RequestLogger is the logger of the request (ctx.RequestLogger()), the request id, method, route, client ip
+// and user id (when present) are appended to the log lines, with the fields bound by Bind.
+//
+//	[ctx.Fail] error: not found request_id=6c2f... method=GET route=/users/:id ip=10.0.0.1 user_id=42
+//
+// ctx.Logger is the plain logger of the app, which logs without the request fields.
+type RequestLogger struct {
+	logger *logger.Logger
+	ctx    *Context
+	fields []logField
+}
+
+// RequestLogger returns the logger of the request, with the request fields and the fields bound by middlewares.
+//
+// Example:
+//
+//	app.Use(func(ctx *zoox.Context) {
+//		ctx.RequestLogger().Bind("tenant", ctx.Header().Get("X-Tenant"))
+//		ctx.Next()
+//	})
+func (ctx *Context) RequestLogger() *RequestLogger {
+	return &ctx.requestLogger
+}
+
+type logField struct {
+	key   string
+	value any
+}
+
+// Bind adds the field to all later log lines of the request, the field of the same key is replaced.
+//
+// Example:
+//
+//	ctx.RequestLogger().Bind("order_id", order.ID)
+func (l *RequestLogger) Bind(key string, value any) {
+	for i := range l.fields {
+		if l.fields[i].key == key {
+			l.fields[i].value = value
+			return
+		}
+	}
+
+	l.fields = append(l.fields, logField{key, value})
+}
+
+// Fields returns the request fields and the bound fields.
+func (l *RequestLogger) Fields() map[string]any {
+	fields := map[string]any{}
+	l.each(func(key string, value any) {
+		fields[key] = value
+	})
+
+	return fields
+}
+
+func (l *RequestLogger) each(fn func(key string, value any)) {
+	if l.ctx != nil {
+		ctx := l.ctx
+		fn("request_id", ctx.RequestID())
+		fn("method", ctx.Method)
+		if ctx.route != "" {
+			fn("route", strings.TrimPrefix(ctx.route, ctx.Method+" "))
+		}
+		fn("ip", ctx.ClientIP())
+		if ctx.user != nil {
+			if id := userID(ctx.user.Get()); id != "" {
+				fn("user_id", id)
+			}
+		}
+	}
+
+	for _, field := range l.fields {
+		fn(field.key, field.value)
+	}
+}
+
+// enabled reports whether the level is printed, so that the fields are not formatted for the dropped logs.
+func (l *RequestLogger) enabled(level string) bool {
+	if l.ctx == nil || l.ctx.App == nil {
+		return true
+	}
+
+	return l.ctx.App.logLevelEnabled(level)
+}
+
+// suffix formats the fields as logfmt.
+func (l *RequestLogger) suffix() string {
+	var b strings.Builder
+	l.each(func(key string, value any) {
+		v := fmt.Sprint(value)
+		if v == "" || strings.ContainsAny(v, " \"=") {
+			v = strconv.Quote(v)
+		}
+
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(v)
+	})
+
+	return b.String()
+}
+
+// Debugf logs the debug message with the fields.
+func (l *RequestLogger) Debugf(format string, args ...any) {
+	if !l.enabled("debug") {
+		return
+	}
+
+	l.logger.Debugf("%s%s", fmt.Sprintf(format, args...), l.suffix())
+}
+
+// Infof logs the info message with the fields.
+func (l *RequestLogger) Infof(format string, args ...any) {
+	if !l.enabled("info") {
+		return
+	}
+
+	l.logger.Infof("%s%s", fmt.Sprintf(format, args...), l.suffix())
+}
+
+// Warnf logs the warn message with the fields.
+func (l *RequestLogger) Warnf(format string, args ...any) {
+	if !l.enabled("warn") {
+		return
+	}
+
+	l.logger.Warnf("%s%s", fmt.Sprintf(format, args...), l.suffix())
+}
+
+// Errorf logs the error message with the fields.
+func (l *RequestLogger) Errorf(format string, args ...any) {
+	if !l.enabled("error") {
+		return
+	}
+
+	l.logger.Errorf("%s%s", fmt.Sprintf(format, args...), l.suffix())
+}
+
+// Debug logs the debug message with the fields.
+func (l *RequestLogger) Debug(format string, args ...any) {
+	l.Debugf(format, args...)
+}
+
+// Info logs the info message with the fields.
+func (l *RequestLogger) Info(format string, args ...any) {
+	l.Infof(format, args...)
+}
+
+// Warn logs the warn message with the fields.
+func (l *RequestLogger) Warn(format string, args ...any) {
+	l.Warnf(format, args...)
+}
+
+// Error logs the error message with the fields.
+func (l *RequestLogger) Error(format string, args ...any) {
+	l.Errorf(format, args...)
+}
+
+// userID returns the id of ctx.User, which has GetID() / ID() methods, an id key or an ID field.
+func userID(u any) string {
+	switch v := u.(type) {
+	case nil:
+		return ""
+	case interface{ GetID() string }:
+		return v.GetID()
+	case interface{ ID() string }:
+		return v.ID()
+	case map[string]any:
+		if id, ok := v["id"]; ok {
+			return fmt.Sprint(id)
+		}
+		return ""
+	}
+
+	rv := reflect.ValueOf(u)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+
+	if id := rv.FieldByName("ID"); id.IsValid() && id.CanInterface() {
+		return fmt.Sprint(id.Interface())
+	}
+
+	return ""
+}
+
+// userName returns the username of ctx.User, which has GetUsername() / Username() methods, a username key or a Username field.
+func userName(u any) string {
+	switch v := u.(type) {
+	case nil:
+		return ""
+	case interface{ GetUsername() string }:
+		return v.GetUsername()
+	case interface{ Username() string }:
+		return v.Username()
+	case map[string]any:
+		if name, ok := v["username"].(string); ok {
+			return name
+		}
+		return ""
+	}
+
+	rv := reflect.ValueOf(u)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+
+	if name := rv.FieldByName("Username"); name.IsValid() && name.Kind() == reflect.String {
+		return name.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,113 @@
+package zoox
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+)
+
+// LongPollCursorHeader is the header of long polling cursor, in request and response.
+const LongPollCursorHeader = "Last-Event-ID"
+
+// LongPoll waits on the pubsub topic until fn returns a result or the timeout,
+// the result is written as JSON, or 204 No Content on timeout.
+//
+// fn is called once with the cursor of the request (Last-Event-ID header or ?cursor=),
+// it should return the pending result after the cursor immediately, or read the notify channel for new values.
+// The notify channel is closed on timeout or client disconnect, then fn should return nil.
+// The topic is subscribed in background before fn is called, as the redis subscriber blocks until the poll is done.
+//
+// Example:
+//
+//	app.Get("/messages", func(ctx *zoox.Context) {
+//		zoox.LongPoll(ctx, "messages", 30*time.Second, func(cursor string, notify <-chan Message) (any, string, error) {
+//			if messages := store.After(cursor); len(messages) > 0 {
+//				return messages, messages[len(messages)-1].ID, nil
+//			}
+//
+//			message, ok := <-notify
+//			if !ok {
+//				return nil, cursor, nil
+//			}
+//
+//			return []Message{message}, message.ID, nil
+//		})
+//	})
+func LongPoll[T any](ctx *Context, topic string, timeout time.Duration, fn func(cursor string, notify <-chan T) (result any, nextCursor string, err error)) {
+	pollCtx, cancel := context.WithTimeout(ctx.Context(), timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	closed := false
+	notify := make(chan T, 16)
+	go func() {
+		<-pollCtx.Done()
+
+		mu.Lock()
+		closed = true
+		close(notify)
+		mu.Unlock()
+	}()
+
+	var subscribeErr error
+	t := pubsub.NewTopic[T](ctx.App.PubSub(), topic)
+	go func() {
+		err := t.Subscribe(pollCtx, func(_ context.Context, value T) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if closed {
+				return nil
+			}
+
+			select {
+			case notify <- value:
+			default:
+				// fn is not reading, the cursor catches up on the next poll
+			}
+
+			return nil
+		})
+		if err != nil && pollCtx.Err() == nil {
+			mu.Lock()
+			subscribeErr = err
+			mu.Unlock()
+
+			// closes notify, so that fn returns
+			cancel()
+		}
+	}()
+
+	cursor := ctx.Header().Get(LongPollCursorHeader)
+	if cursor == "" {
+		cursor = ctx.Query().Get("cursor").String()
+	}
+
+	result, nextCursor, err := fn(cursor, notify)
+
+	mu.Lock()
+	if subscribeErr != nil {
+		err = subscribeErr
+	}
+	mu.Unlock()
+
+	if err != nil {
+		ctx.HandleError(err)
+		return
+	}
+
+	if nextCursor != "" {
+		ctx.SetHeader(LongPollCursorHeader, nextCursor)
+	}
+	ctx.SetCacheControlWithNoCache()
+
+	if result == nil {
+		ctx.Status(http.StatusNoContent)
+		ctx.Writer.WriteHeaderNow()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
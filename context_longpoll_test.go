@@ -0,0 +1,99 @@
+package zoox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingPubSub blocks in Subscribe until ctx is done, as the redis backend.
+type blockingPubSub struct {
+	sync.Mutex
+	handlers map[string][]pubsub.Handler
+	err      error
+}
+
+func (b *blockingPubSub) Publish(ctx context.Context, msg *pubsub.Message) error {
+	b.Lock()
+	handlers := b.handlers[msg.Topic]
+	b.Unlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+
+	return nil
+}
+
+func (b *blockingPubSub) Subscribe(ctx context.Context, topic string, handler pubsub.Handler) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	b.Lock()
+	if b.handlers == nil {
+		b.handlers = map[string][]pubsub.Handler{}
+	}
+	b.handlers[topic] = append(b.handlers[topic], handler)
+	b.Unlock()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestLongPollBlockingSubscribe(t *testing.T) {
+	app := New()
+	ps := &blockingPubSub{}
+	app.SetPubSub(ps)
+
+	app.Get("/messages", func(ctx *Context) {
+		LongPoll(ctx, "messages", 5*time.Second, func(cursor string, notify <-chan string) (any, string, error) {
+			message, ok := <-notify
+			if !ok {
+				return nil, cursor, nil
+			}
+
+			return []string{message}, "1", nil
+		})
+	})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		pubsub.NewTopic[string](ps, "messages").Publish(context.Background(), "hello")
+	}()
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/messages", nil))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `["hello"]`, recorder.Body.String())
+	assert.Equal(t, "1", recorder.Header().Get(LongPollCursorHeader))
+}
+
+func TestLongPollSubscribeError(t *testing.T) {
+	app := New()
+	app.SetPubSub(&blockingPubSub{err: errors.New("connection refused")})
+
+	app.Get("/messages", func(ctx *Context) {
+		LongPoll(ctx, "messages", 5*time.Second, func(cursor string, notify <-chan string) (any, string, error) {
+			<-notify
+			return nil, cursor, nil
+		})
+	})
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/messages", nil))
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
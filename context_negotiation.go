@@ -0,0 +1,159 @@
+package zoox
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-zoox/headers"
+)
+
+// FormatDefault is the key of the fallback handler of ctx.Format.
+const FormatDefault = "default"
+
+type acceptRange struct {
+	typ     string
+	subtype string
+	q       float64
+	index   int
+}
+
+// parseAccept parses the accept header into media ranges.
+func parseAccept(accept string) []acceptRange {
+	ranges := []acceptRange{}
+	for index, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			// such as: *
+			typ, subtype = mediaType, "*"
+		}
+
+		r := acceptRange{
+			typ:     typ,
+			subtype: subtype,
+			q:       1,
+			index:   index,
+		}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.ToLower(key) == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges
+}
+
+// quality returns the q of the offer and the index of the matched range, -1 if not acceptable.
+func quality(ranges []acceptRange, offer string) (q float64, index int) {
+	typ, subtype, _ := strings.Cut(strings.ToLower(offer), "/")
+
+	specificity := -1
+	index = -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*" && r.subtype == "*":
+			s = 0
+		}
+
+		// the most specific range decides the q
+		if s > specificity {
+			specificity = s
+			q = r.q
+			index = r.index
+		}
+	}
+
+	if specificity == -1 || q <= 0 {
+		return 0, -1
+	}
+
+	return q, index
+}
+
+// Negotiate returns the best offered content type of the Accept header, empty if none is acceptable.
+//
+// Ties are broken by the order in the Accept header, then the order of offers.
+func (ctx *Context) Negotiate(offers ...string) string {
+	accept := ctx.Header().Get(headers.Accept)
+	if accept == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best := ""
+	bestQ := 0.0
+	bestIndex := -1
+	for _, offer := range offers {
+		q, index := quality(ranges, offer)
+		if index == -1 {
+			continue
+		}
+
+		if q > bestQ || (q == bestQ && index < bestIndex) {
+			best, bestQ, bestIndex = offer, q, index
+		}
+	}
+
+	return best
+}
+
+// Format selects the handler of the content type negotiated with the Accept header,
+// the "default" handler is used if none is acceptable, otherwise 406 Not Acceptable.
+//
+// Example:
+//
+//	ctx.Format(map[string]zoox.HandlerFunc{
+//		"application/json": func(ctx *zoox.Context) { ctx.JSON(200, user) },
+//		"text/html":        func(ctx *zoox.Context) { ctx.Render(200, "user.html", user) },
+//		"default":          func(ctx *zoox.Context) { ctx.String(200, "%s", user.Name) },
+//	})
+func (ctx *Context) Format(handlers map[string]HandlerFunc) {
+	offers := make([]string, 0, len(handlers))
+	for offer := range handlers {
+		if offer != FormatDefault {
+			offers = append(offers, offer)
+		}
+	}
+	sort.Strings(offers)
+
+	selected := ""
+	if ctx.Header().Get(headers.Accept) != "" || handlers[FormatDefault] == nil {
+		selected = ctx.Negotiate(offers...)
+	}
+
+	if selected != "" {
+		ctx.SetHeader(headers.Vary, headers.Accept)
+		handlers[selected](ctx)
+		return
+	}
+
+	if handler, ok := handlers[FormatDefault]; ok {
+		handler(ctx)
+		return
+	}
+
+	ctx.Error(http.StatusNotAcceptable, http.StatusText(http.StatusNotAcceptable))
+}
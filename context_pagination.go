@@ -0,0 +1,223 @@
+package zoox
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the default page size of ctx.Pagination.
+var DefaultPageSize = 10
+
+// MaxPageSize is the max page size of ctx.Pagination.
+var MaxPageSize = 100
+
+// Pagination is the pagination of the request.
+type Pagination struct {
+	Page   int `json:"page"`
+	Limit  int `json:"page_size"`
+	Offset int `json:"-"`
+}
+
+// SortField is a sort field of the request.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// Filter operators
+const (
+	FilterOpEq   = "eq"
+	FilterOpNe   = "ne"
+	FilterOpGt   = "gt"
+	FilterOpGte  = "gte"
+	FilterOpLt   = "lt"
+	FilterOpLte  = "lte"
+	FilterOpLike = "like"
+	FilterOpIn   = "in"
+)
+
+var filterOps = map[string]bool{
+	FilterOpEq: true, FilterOpNe: true, FilterOpGt: true, FilterOpGte: true,
+	FilterOpLt: true, FilterOpLte: true, FilterOpLike: true, FilterOpIn: true,
+}
+
+// Filter is a filter of the request, such as ?filter[age][gte]=18.
+type Filter struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// Values returns the comma separated values, used by the in operator.
+func (f Filter) Values() []string {
+	return strings.Split(f.Value, ",")
+}
+
+// PaginatedResult is the standard paginated response.
+type PaginatedResult struct {
+	Items      any   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+var filterKeyRe = regexp.MustCompile(`^filter\[([^\]]+)\](?:\[([^\]]+)\])?$`)
+
+// fieldNameRe is the field name of sort and filters, such as created_at or user.name.
+var fieldNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// maxOffset bounds the offset of the huge page, which overflows otherwise.
+const maxOffset = math.MaxInt32
+
+// Pagination returns the page, limit and offset of the request, bounded by MaxPageSize,
+// the offset is bounded by math.MaxInt32.
+//
+//	?page=2&page_size=20 => {Page: 2, Limit: 20, Offset: 20}
+//	?limit=20&offset=40  => {Page: 3, Limit: 20, Offset: 40}
+func (ctx *Context) Pagination() Pagination {
+	query := ctx.Request.URL.Query()
+	intOf := func(keys ...string) int {
+		for _, key := range keys {
+			if v, err := strconv.Atoi(query.Get(key)); err == nil {
+				return v
+			}
+		}
+
+		return 0
+	}
+
+	limit := intOf("page_size", "pageSize", "limit")
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	p := Pagination{
+		Page:  intOf("page"),
+		Limit: limit,
+	}
+
+	if offset := intOf("offset"); offset > 0 && p.Page == 0 {
+		p.Offset = min(offset, maxOffset)
+		p.Page = p.Offset/limit + 1
+		return p
+	}
+
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if maxPage := maxOffset/limit + 1; p.Page > maxPage {
+		p.Page = maxPage
+	}
+	p.Offset = (p.Page - 1) * limit
+
+	return p
+}
+
+// Sort returns the sort fields of the request, fields not in allowedFields are ignored,
+// nothing is sortable without allowedFields, because the fields are usually built into ORDER BY.
+//
+//	?sort=-created_at,name  => [{created_at desc} {name asc}]
+//	?sort=created_at:desc   => [{created_at desc}]
+func (ctx *Context) Sort(allowedFields ...string) []SortField {
+	allowed := map[string]bool{}
+	for _, field := range allowedFields {
+		allowed[field] = true
+	}
+
+	value := ctx.Request.URL.Query().Get("sort")
+	if value == "" {
+		value = ctx.Request.URL.Query().Get("order_by")
+	}
+
+	fields := []SortField{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: part[1:], Desc: true}
+		} else if name, direction, ok := strings.Cut(part, ":"); ok {
+			field = SortField{Field: name, Desc: strings.EqualFold(direction, "desc")}
+		}
+
+		if !allowed[field.Field] || !fieldNameRe.MatchString(field.Field) {
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Filters returns the filters of the request, fields not in allowedFields (if given),
+// invalid field names and unknown operators are ignored.
+//
+//	?filter[name]=foo&filter[age][gte]=18 => [{age gte 18} {name eq foo}]
+func (ctx *Context) Filters(allowedFields ...string) []Filter {
+	allowed := map[string]bool{}
+	for _, field := range allowedFields {
+		allowed[field] = true
+	}
+
+	filters := []Filter{}
+	for key, values := range ctx.Request.URL.Query() {
+		matches := filterKeyRe.FindStringSubmatch(key)
+		if matches == nil || len(values) == 0 {
+			continue
+		}
+
+		field, op := matches[1], strings.ToLower(matches[2])
+		if op == "" {
+			op = FilterOpEq
+		}
+
+		if !filterOps[op] || !fieldNameRe.MatchString(field) || (len(allowed) > 0 && !allowed[field]) {
+			continue
+		}
+
+		filters = append(filters, Filter{
+			Field: field,
+			Op:    op,
+			Value: values[0],
+		})
+	}
+
+	sort.Slice(filters, func(i, j int) bool {
+		if filters[i].Field != filters[j].Field {
+			return filters[i].Field < filters[j].Field
+		}
+
+		return filters[i].Op < filters[j].Op
+	})
+
+	return filters
+}
+
+// SuccessWithPagination writes the paginated items with code-message-result specification.
+func (ctx *Context) SuccessWithPagination(items any, total int64) {
+	p := ctx.Pagination()
+
+	totalPages := total / int64(p.Limit)
+	if total%int64(p.Limit) != 0 {
+		totalPages++
+	}
+
+	ctx.Success(&PaginatedResult{
+		Items:      items,
+		Total:      total,
+		Page:       p.Page,
+		PageSize:   p.Limit,
+		TotalPages: totalPages,
+	})
+}
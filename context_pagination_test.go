@@ -0,0 +1,73 @@
+package zoox
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryContext(query string) *Context {
+	return NewContext(New(), httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+}
+
+func TestContextPagination(t *testing.T) {
+	for query, expected := range map[string]Pagination{
+		"":                      {Page: 1, Limit: DefaultPageSize, Offset: 0},
+		"page=2&page_size=20":   {Page: 2, Limit: 20, Offset: 20},
+		"page=3&pageSize=5":     {Page: 3, Limit: 5, Offset: 10},
+		"limit=20&offset=40":    {Page: 3, Limit: 20, Offset: 40},
+		"page=0&limit=-1":       {Page: 1, Limit: DefaultPageSize, Offset: 0},
+		"page_size=1000":        {Page: 1, Limit: MaxPageSize, Offset: 0},
+		"page=2&offset=100":     {Page: 2, Limit: DefaultPageSize, Offset: 10},
+		"page=abc&page_size=xy": {Page: 1, Limit: DefaultPageSize, Offset: 0},
+	} {
+		assert.Equal(t, expected, newQueryContext(query).Pagination(), query)
+	}
+}
+
+func TestContextPaginationHugePage(t *testing.T) {
+	p := newQueryContext("page=" + strconv.Itoa(math.MaxInt) + "&page_size=100").Pagination()
+	assert.True(t, p.Offset >= 0)
+	assert.LessOrEqual(t, p.Offset, math.MaxInt32)
+	assert.Equal(t, p.Offset, (p.Page-1)*p.Limit)
+
+	p = newQueryContext("offset=" + strconv.Itoa(math.MaxInt)).Pagination()
+	assert.Equal(t, math.MaxInt32, p.Offset)
+	assert.True(t, p.Page > 0)
+}
+
+func TestContextSort(t *testing.T) {
+	ctx := newQueryContext("sort=-created_at,name,email:desc,password")
+	assert.Equal(t, []SortField{
+		{Field: "created_at", Desc: true},
+		{Field: "name"},
+		{Field: "email", Desc: true},
+	}, ctx.Sort("created_at", "name", "email"))
+
+	// nothing is sortable without the allowed fields
+	assert.Equal(t, []SortField{}, ctx.Sort())
+
+	ctx = newQueryContext("order_by=name:asc")
+	assert.Equal(t, []SortField{{Field: "name"}}, ctx.Sort("name"))
+
+	// the allowed fields are still checked as identifiers
+	ctx = newQueryContext("sort=name%3Bdrop%20table%20users")
+	assert.Equal(t, []SortField{}, ctx.Sort("name;drop table users"))
+}
+
+func TestContextFilters(t *testing.T) {
+	ctx := newQueryContext("filter[name]=foo&filter[age][gte]=18&filter[age][bad]=1&filter[role][in]=admin,owner&filter[name%3B--]=x")
+	filters := ctx.Filters()
+	assert.Equal(t, []Filter{
+		{Field: "age", Op: FilterOpGte, Value: "18"},
+		{Field: "name", Op: FilterOpEq, Value: "foo"},
+		{Field: "role", Op: FilterOpIn, Value: "admin,owner"},
+	}, filters)
+	assert.Equal(t, []string{"admin", "owner"}, filters[2].Values())
+
+	assert.Equal(t, []Filter{{Field: "name", Op: FilterOpEq, Value: "foo"}}, ctx.Filters("name"))
+}
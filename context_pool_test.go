@@ -0,0 +1,162 @@
+package zoox
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWebSocketUpgradeRequest(path string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	return req
+}
+
+func TestContextPoolWebSocketMiddlewares(t *testing.T) {
+	var chain []string
+	app := New()
+	app.Use(func(ctx *Context) {
+		chain = append(chain, "app")
+		ctx.Next()
+	})
+
+	// the spare capacity must not be shared with the pooled context
+	middlewares := make([]HandlerFunc, 0, 16)
+	middlewares = append(middlewares, func(ctx *Context) {
+		chain = append(chain, "ws")
+		ctx.Status(http.StatusUnauthorized)
+	})
+	app.WebSocket("/ws", func(opt *WebSocketOption) {
+		opt.Middlewares = middlewares
+	})
+
+	app.Get("/ping", func(ctx *Context) {
+		chain = append(chain, "ping")
+		ctx.String(http.StatusOK, "pong")
+	})
+
+	for i := 0; i < 3; i++ {
+		chain = nil
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, newWebSocketUpgradeRequest("/ws"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, []string{"app", "ws"}, chain)
+
+		chain = nil
+		recorder = httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "pong", recorder.Body.String())
+		assert.Equal(t, []string{"app", "ping"}, chain)
+	}
+
+	assert.Len(t, middlewares, 1)
+	assert.Equal(t, 16, cap(middlewares))
+}
+
+func TestContextPoolDropsOversizedHandlers(t *testing.T) {
+	ctx := &Context{handlers: make([]HandlerFunc, 0, maxPooledHandlers+1)}
+	ctx.reset(New(), httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Nil(t, ctx.handlers)
+
+	ctx = &Context{handlers: make([]HandlerFunc, 3, maxPooledHandlers)}
+	ctx.reset(New(), httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Len(t, ctx.handlers, 0)
+	assert.Equal(t, maxPooledHandlers, cap(ctx.handlers))
+}
+
+func TestContextPoolSSE(t *testing.T) {
+	app := New()
+	app.Get("/events/:id", func(ctx *Context) {
+		id := ctx.Param().Get("id").String()
+		ctx.SSE().Event("message", id)
+	})
+
+	first := httptest.NewRecorder()
+	app.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/events/alice", nil))
+	second := httptest.NewRecorder()
+	app.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/events/bob", nil))
+
+	// the sse writer of the recycled context is not reused
+	assert.Contains(t, first.Body.String(), "alice")
+	assert.NotContains(t, first.Body.String(), "bob")
+	assert.Contains(t, second.Body.String(), "bob")
+	assert.NotContains(t, second.Body.String(), "alice")
+}
+
+func TestContextPoolBridge(t *testing.T) {
+	app := New()
+	app.Bridge().TopicToSSE("users", "/events/users/:id", func(opt *BridgeOption) {
+		// the context is held by the connection while the other requests are served
+		opt.Filter = func(ctx *Context, msg *pubsub.Message) bool {
+			return ctx.Param().Get("id").String() == string(msg.Body)
+		}
+	})
+	app.Get("/users/:id", func(ctx *Context) {
+		ctx.String(http.StatusOK, ctx.Param().Get("id").String())
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	// the other requests are served from the pool while the connection holds the context,
+	// the subscription is asynchronous, so publish until delivered
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if r, err := http.Get(server.URL + "/users/2"); err == nil {
+					r.Body.Close()
+				}
+				app.PubSub().Publish(context.Background(), &pubsub.Message{Topic: "users", Body: []byte("2")})
+				app.PubSub().Publish(context.Background(), &pubsub.Message{Topic: "users", Body: []byte("1")})
+			}
+		}
+	}()
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lines := make(chan string, 3)
+	go func() {
+		req, _ := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL+"/events/users/1", nil)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer res.Body.Close()
+
+		scanner := bufio.NewScanner(res.Body)
+		for n := 0; n < cap(lines) && scanner.Scan(); {
+			if strings.HasPrefix(scanner.Text(), "data:") {
+				lines <- strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "data:"))
+				n++
+			}
+		}
+	}()
+
+	for i := 0; i < cap(lines); i++ {
+		select {
+		case l := <-lines:
+			assert.Equal(t, "1", l)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the bridged message")
+		}
+	}
+}
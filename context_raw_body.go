@@ -0,0 +1,89 @@
+package zoox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// DefaultRawBodyMaxSize is the default max size of the retained raw body.
+const DefaultRawBodyMaxSize = 1024 * 1024
+
+// ErrRawBodyNotEnabled is returned by ctx.RawBody when it is not enabled for the route.
+var ErrRawBodyNotEnabled = errors.New("raw body is not enabled, use ctx.EnableRawBody or middleware.RawBody")
+
+// ErrRawBodyTooLarge is returned by ctx.RawBody when the body exceeds the max size.
+var ErrRawBodyTooLarge = errors.New("raw body is too large")
+
+// rawBodyReader retains the bytes read from the body up to max,
+// so the body can be read once by Bind* and kept for ctx.RawBody.
+type rawBodyReader struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	max      int64
+	overflow bool
+}
+
+func (r *rawBodyReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 && !r.overflow {
+		if int64(r.buf.Len()+n) > r.max {
+			r.overflow = true
+			r.buf = bytes.Buffer{}
+		} else {
+			r.buf.Write(p[:n])
+		}
+	}
+
+	return n, err
+}
+
+// EnableRawBody retains the raw body (up to maxSize bytes) while it is read,
+// it should be called before the body is read, usually by middleware.RawBody on the route or group.
+func (ctx *Context) EnableRawBody(maxSize ...int64) {
+	if ctx.rawBody != nil || ctx.Request.Body == nil {
+		return
+	}
+
+	max := int64(DefaultRawBodyMaxSize)
+	if len(maxSize) > 0 && maxSize[0] > 0 {
+		max = maxSize[0]
+	}
+
+	ctx.rawBody = &rawBodyReader{
+		ReadCloser: ctx.Request.Body,
+		max:        max,
+	}
+	ctx.Request.Body = ctx.rawBody
+}
+
+// RawBody returns the raw body of the request, such as for HMAC signature verification,
+// it works before or after Bind*, and the body can still be bound after it.
+//
+// It must be enabled with ctx.EnableRawBody (or middleware.RawBody) first.
+func (ctx *Context) RawBody() ([]byte, error) {
+	if ctx.rawBody == nil {
+		return nil, ErrRawBodyNotEnabled
+	}
+
+	if ctx.bodyBytes == nil {
+		// read the rest, which is retained by the reader
+		if _, err := io.Copy(io.Discard, ctx.rawBody); err != nil {
+			return nil, err
+		}
+
+		if ctx.rawBody.overflow {
+			return nil, ErrRawBodyTooLarge
+		}
+
+		ctx.bodyBytes = ctx.rawBody.buf.Bytes()
+		if ctx.bodyBytes == nil {
+			ctx.bodyBytes = []byte{}
+		}
+
+		// recovery to request body, for the later Bind*
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(ctx.bodyBytes))
+	}
+
+	return ctx.bodyBytes, nil
+}
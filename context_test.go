@@ -0,0 +1,303 @@
+package zoox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/context/msgpack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextAbort(t *testing.T) {
+	calls := []string{}
+	aborted := false
+
+	recorder := httptest.NewRecorder()
+	ctx := NewContext(New(), recorder, httptest.NewRequest(http.MethodGet, "/", nil), func(ctx *Context) {
+		calls = append(calls, "logger")
+		ctx.Next()
+		aborted = ctx.IsAborted()
+	}, func(ctx *Context) {
+		calls = append(calls, "auth")
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		ctx.Next()
+	}, func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+	ctx.Next()
+
+	assert.Equal(t, []string{"logger", "auth"}, calls)
+	assert.True(t, aborted)
+	assert.True(t, ctx.IsAborted())
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestContextAbortWithStatusJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ctx := NewContext(New(), recorder, httptest.NewRequest(http.MethodGet, "/", nil), func(ctx *Context) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, H{"message": "forbidden"})
+		ctx.Next()
+	}, func(ctx *Context) {
+		t.Fatal("handler should not be called after abort")
+	})
+	ctx.Next()
+
+	assert.True(t, ctx.IsAborted())
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.JSONEq(t, `{"message":"forbidden"}`, recorder.Body.String())
+}
+
+func TestContextNotAborted(t *testing.T) {
+	called := false
+	ctx := NewContext(New(), httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), func(ctx *Context) {
+		ctx.Next()
+	}, func(ctx *Context) {
+		called = true
+	})
+	ctx.Next()
+
+	assert.True(t, called)
+	assert.False(t, ctx.IsAborted())
+}
+
+func TestContextCopy(t *testing.T) {
+	app := New()
+
+	results := make(chan string, 100)
+	var wg sync.WaitGroup
+	app.Get("/users/:id", func(ctx *Context) {
+		ctx.RequestLogger().Bind("tenant", "acme")
+		c := ctx.Copy()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// the pooled context is reused by the next requests meanwhile
+			time.Sleep(time.Millisecond)
+			c.Writer.Write([]byte("discarded"))
+			results <- c.Param().Get("id").String() + ":" + c.RequestID() + ":" + c.RequestLogger().Fields()["tenant"].(string)
+		}()
+
+		ctx.String(http.StatusOK, ctx.RequestID())
+	})
+
+	expected := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d", i), nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		expected[fmt.Sprintf("%d:%s:acme", i, recorder.Body.String())] = true
+	}
+
+	wg.Wait()
+	close(results)
+	for result := range results {
+		assert.True(t, expected[result], result)
+	}
+}
+
+func TestContextSessionLegacyCookie(t *testing.T) {
+	app := New()
+	app.Get("/login", func(ctx *Context) {
+		ctx.Session().Set("user", "42")
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Set-Cookie"), "gsession="), recorder.Header().Get("Set-Cookie"))
+}
+
+func TestContextSessionNamespace(t *testing.T) {
+	app := New()
+	app.Config.Namespace = "shop:production"
+	app.Get("/login", func(ctx *Context) {
+		ctx.Session().Set("user", "42")
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Set-Cookie"), "shop.production.gsession="), recorder.Header().Get("Set-Cookie"))
+}
+
+func TestContextBindMsgpackMaxDepth(t *testing.T) {
+	for _, codec := range []Codec{nil, msgpack.Codec{}} {
+		app := New()
+		app.SetMsgpackCodec(codec)
+
+		body := append(bytes.Repeat([]byte{0x91}, 1<<16), 0xc0)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("Content-Type", MIMEMsgpack)
+		ctx := NewContext(app, httptest.NewRecorder(), req)
+
+		var v any
+		assert.ErrorIs(t, ctx.BindMsgpack(&v), msgpack.ErrMaxDepth)
+	}
+
+	data, err := New().MsgpackCodec().Marshal(map[string]any{"name": "zoox"})
+	assert.NoError(t, err)
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	assert.NoError(t, New().MsgpackCodec().Unmarshal(data, &v))
+	assert.Equal(t, "zoox", v.Name)
+}
+
+type countingStringer struct {
+	calls *int
+}
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "42"
+}
+
+func TestRequestLoggerSkipsDisabledLevels(t *testing.T) {
+	app := New()
+	app.Config.LogLevel = "info"
+	ctx := NewContext(app, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	calls := 0
+	ctx.RequestLogger().Bind("order", countingStringer{&calls})
+
+	// the fields are not formatted for the dropped logs
+	ctx.RequestLogger().Debugf("processing order")
+	assert.Equal(t, 0, calls)
+
+	ctx.RequestLogger().Infof("processing order")
+	assert.Equal(t, 1, calls)
+}
+
+func TestContextHijack(t *testing.T) {
+	app := New()
+	app.Get("/hijack", func(ctx *Context) {
+		conn, _, err := ctx.Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked"))
+	})
+	app.Get("/header-written", func(ctx *Context) {
+		ctx.Writer.WriteHeader(http.StatusAccepted)
+		ctx.Writer.WriteHeaderNow()
+
+		// the header is committed, the connection must not be taken over
+		_, _, err := ctx.Hijack()
+		assert.Error(t, err)
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/hijack")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, "hijacked", string(body))
+	}
+
+	res, err = http.Get(server.URL + "/header-written")
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	}
+}
+
+func TestContextBindQueryConstraints(t *testing.T) {
+	type listQuery struct {
+		Limit int    `query:"limit,min=1,max=100"`
+		Sort  string `query:"sort,enum=asc|desc"`
+		Page  int
+	}
+
+	bind := func(target string) (*listQuery, error) {
+		ctx := NewContext(New(), httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		q := &listQuery{}
+		return q, ctx.BindQuery(q)
+	}
+
+	q, err := bind("/?limit=10&sort=asc&Page=2")
+	assert.NoError(t, err)
+	assert.Equal(t, &listQuery{Limit: 10, Sort: "asc", Page: 2}, q)
+
+	_, err = bind("/?limit=1000&sort=evil")
+	assert.ErrorContains(t, err, "limit must be in range(1, 100)")
+
+	_, err = bind("/?limit=10&sort=evil")
+	assert.ErrorContains(t, err, "sort must be in enum(asc|desc)")
+}
+
+func TestContextBindFormConstraints(t *testing.T) {
+	type signupForm struct {
+		Name string `form:"name,required,regexp=/^[a-z]+$/"`
+		Plan string `form:"plan,enum=free|pro"`
+		Age  int    `form:"age,omitempty,min=18,max=120"`
+	}
+
+	bind := func(body string) (*signupForm, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		ctx := NewContext(New(), httptest.NewRecorder(), req)
+		form := &signupForm{}
+		return form, ctx.BindForm(form)
+	}
+
+	form, err := bind("name=zero&plan=pro&age=20")
+	assert.NoError(t, err)
+	assert.Equal(t, &signupForm{Name: "zero", Plan: "pro", Age: 20}, form)
+
+	_, err = bind("name=zero&plan=enterprise")
+	assert.ErrorContains(t, err, "plan must be in enum(free|pro)")
+
+	_, err = bind("name=zero&plan=free&age=10")
+	assert.ErrorContains(t, err, "age must be in range(18, 120)")
+
+	_, err = bind("name=Zero&plan=free")
+	assert.ErrorContains(t, err, "name is invalid with regexp(^[a-z]+$)")
+}
+
+func TestContextBindJSONConstraints(t *testing.T) {
+	type listBody struct {
+		Limit int    `json:"limit,omitempty,min=1,max=100"`
+		Sort  string `json:"sort,omitempty,enum=asc|desc"`
+		Name  string `json:"name,omitempty,min=2"`
+		Code  string `json:"code,omitempty,regexp=/^[a-z]+$/"`
+	}
+
+	bind := func(body string) (*listBody, error) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := NewContext(New(), httptest.NewRecorder(), req)
+		v := &listBody{}
+		return v, ctx.BindJSON(v)
+	}
+
+	v, err := bind(`{"limit":10,"sort":"asc"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, &listBody{Limit: 10, Sort: "asc"}, v)
+
+	_, err = bind(`{"limit":1000}`)
+	assert.ErrorContains(t, err, "limit must be in range(1, 100), but 1000")
+
+	_, err = bind(`{"sort":"evil"}`)
+	assert.ErrorContains(t, err, "sort must be in enum(asc|desc), but evil")
+
+	_, err = bind(`{"name":"z"}`)
+	assert.ErrorContains(t, err, "length of name must be at least 2, but 1")
+
+	_, err = bind(`{"code":"ABC"}`)
+	assert.ErrorContains(t, err, "code is invalid with regexp(^[a-z]+$)")
+}
@@ -0,0 +1,146 @@
+package zoox
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerTiming is a metric of the Server-Timing header.
+type ServerTiming struct {
+	Name        string        `json:"name"`
+	Duration    time.Duration `json:"duration"`
+	Description string        `json:"description,omitempty"`
+}
+
+// String returns the metric in Server-Timing format, such as: db;dur=12.5;desc="query users".
+func (t ServerTiming) String() string {
+	s := fmt.Sprintf("%s;dur=%.1f", t.Name, float64(t.Duration.Microseconds())/1000)
+	if t.Description != "" {
+		s += fmt.Sprintf(";desc=%q", t.Description)
+	}
+
+	return s
+}
+
+// serverTimings collects the timings of the request.
+type serverTimings struct {
+	sync.Mutex
+	metrics []ServerTiming
+	//
+	handlerStartedAt time.Time
+	handlerEndedAt   time.Time
+}
+
+// EnableServerTiming enables the phase timings (handler, render) of the request,
+// it is called by the ServerTiming middleware.
+func (ctx *Context) EnableServerTiming() {
+	if ctx.timings == nil {
+		ctx.timings = &serverTimings{}
+	}
+}
+
+// AddServerTiming adds a metric to the Server-Timing header, metrics with the same name are accumulated.
+//
+// Example:
+//
+//	start := time.Now()
+//	users := db.FindUsers()
+//	ctx.AddServerTiming("db", time.Since(start), "query users")
+func (ctx *Context) AddServerTiming(name string, duration time.Duration, description ...string) {
+	ctx.EnableServerTiming()
+
+	ctx.timings.Lock()
+	defer ctx.timings.Unlock()
+
+	for i, metric := range ctx.timings.metrics {
+		if metric.Name == name {
+			ctx.timings.metrics[i].Duration += duration
+			return
+		}
+	}
+
+	metric := ServerTiming{
+		Name:     name,
+		Duration: duration,
+	}
+	if len(description) > 0 {
+		metric.Description = description[0]
+	}
+	ctx.timings.metrics = append(ctx.timings.metrics, metric)
+}
+
+// StartServerTiming starts a metric, call the returned function to stop it.
+//
+// Example:
+//
+//	defer ctx.StartServerTiming("db")()
+func (ctx *Context) StartServerTiming(name string, description ...string) func() {
+	start := time.Now()
+	return func() {
+		ctx.AddServerTiming(name, time.Since(start), description...)
+	}
+}
+
+// ServerTimings returns the metrics of the request.
+func (ctx *Context) ServerTimings() []ServerTiming {
+	if ctx.timings == nil {
+		return nil
+	}
+
+	ctx.timings.Lock()
+	defer ctx.timings.Unlock()
+
+	metrics := make([]ServerTiming, len(ctx.timings.metrics))
+	copy(metrics, ctx.timings.metrics)
+	return metrics
+}
+
+// HandlerTiming returns the start and end time of the route handler, zero if not enabled or not run.
+func (ctx *Context) HandlerTiming() (startedAt, endedAt time.Time) {
+	if ctx.timings == nil {
+		return
+	}
+
+	ctx.timings.Lock()
+	defer ctx.timings.Unlock()
+
+	return ctx.timings.handlerStartedAt, ctx.timings.handlerEndedAt
+}
+
+// FormatServerTiming formats the metrics into the Server-Timing header value.
+func FormatServerTiming(metrics []ServerTiming) string {
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		parts = append(parts, metric.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// renderTiming starts the render metric if server timing is enabled.
+func (ctx *Context) renderTiming() func() {
+	if ctx.timings == nil {
+		return func() {}
+	}
+
+	return ctx.StartServerTiming("render")
+}
+
+// handlerTiming records the route handler (the last one) if server timing is enabled.
+func (ctx *Context) handlerTiming() func() {
+	if ctx.timings == nil || ctx.index != len(ctx.handlers)-1 {
+		return nil
+	}
+
+	ctx.timings.Lock()
+	ctx.timings.handlerStartedAt = time.Now()
+	ctx.timings.Unlock()
+
+	return func() {
+		ctx.timings.Lock()
+		ctx.timings.handlerEndedAt = time.Now()
+		ctx.timings.Unlock()
+	}
+}
@@ -0,0 +1,87 @@
+package zoox
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/headers"
+)
+
+// DeclareTrailer declares the response trailers before writing the body,
+// which is required by some clients, such as gRPC-web.
+func (ctx *Context) DeclareTrailer(keys ...string) {
+	for _, key := range keys {
+		ctx.AddHeader(headers.Trailer, http.CanonicalHeaderKey(key))
+	}
+}
+
+// SetTrailer sets a response trailer, which is sent after the body.
+// It can be called at any time before the handler returns, even after the body was written.
+func (ctx *Context) SetTrailer(key string, value string) {
+	if ctx.isDeclaredTrailer(key) {
+		ctx.Writer.Header().Set(key, value)
+		return
+	}
+
+	ctx.Writer.Header().Set(http.TrailerPrefix+key, value)
+}
+
+// AddTrailer adds a response trailer, which is sent after the body.
+func (ctx *Context) AddTrailer(key string, value string) {
+	if ctx.isDeclaredTrailer(key) {
+		ctx.Writer.Header().Add(key, value)
+		return
+	}
+
+	ctx.Writer.Header().Add(http.TrailerPrefix+key, value)
+}
+
+// RequestTrailer returns the request trailers.
+// Trailers are only available after the request body is fully read.
+func (ctx *Context) RequestTrailer() http.Header {
+	if ctx.Request.Trailer == nil {
+		return http.Header{}
+	}
+
+	return ctx.Request.Trailer
+}
+
+// WriteInformational sends a 1xx informational response, such as 103 Early Hints,
+// http.ErrNotSupported if ctx.Writer does not implement InformationalWriter.
+func (ctx *Context) WriteInformational(status int) error {
+	return writeInformational(ctx.Writer, status)
+}
+
+// EarlyHints sends a 103 Early Hints response with the given Link headers,
+// such as </style.css>; rel=preload; as=style.
+// The links are only sent with the 103, the Link headers of the final response are kept.
+func (ctx *Context) EarlyHints(links ...string) error {
+	header := ctx.Writer.Header()
+	key := http.CanonicalHeaderKey(headers.Link)
+	origin, ok := header[key]
+
+	// net/http sends the current header with 1xx, restored for the final response
+	header[key] = append([]string{}, links...)
+	defer func() {
+		if ok {
+			header[key] = origin
+		} else {
+			delete(header, key)
+		}
+	}()
+
+	return ctx.WriteInformational(http.StatusEarlyHints)
+}
+
+func (ctx *Context) isDeclaredTrailer(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+	for _, declared := range ctx.Writer.Header().Values(headers.Trailer) {
+		for _, one := range strings.Split(declared, ",") {
+			if http.CanonicalHeaderKey(strings.TrimSpace(one)) == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package zoox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextEarlyHints(t *testing.T) {
+	app := New()
+	app.Get("/", func(ctx *Context) {
+		ctx.SetHeader("Link", "</final.js>; rel=preload; as=script")
+
+		assert.NoError(t, ctx.EarlyHints("</style.css>; rel=preload; as=style"))
+		assert.NoError(t, ctx.EarlyHints("</app.js>; rel=preload; as=script"))
+
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var hints [][]string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				mu.Lock()
+				hints = append(hints, header.Values("Link"))
+				mu.Unlock()
+			}
+			return nil
+		},
+	}
+
+	req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	// the links of the hints are not leaked into the final response
+	assert.Equal(t, []string{"</final.js>; rel=preload; as=script"}, res.Header.Values("Link"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, [][]string{
+		{"</style.css>; rel=preload; as=style"},
+		{"</app.js>; rel=preload; as=script"},
+	}, hints)
+}
+
+func TestContextEarlyHintsWithoutLink(t *testing.T) {
+	app := New()
+	app.Get("/", func(ctx *Context) {
+		assert.NoError(t, ctx.EarlyHints("</style.css>; rel=preload; as=style"))
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Header.Values("Link"))
+}
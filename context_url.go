@@ -0,0 +1,167 @@
+package zoox
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// parseTrustedProxies parses the ips or cidrs.
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+			}
+
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// IsFromTrustedProxy returns true if the request is sent by a trusted proxy (config trusted_proxies).
+func (ctx *Context) IsFromTrustedProxy() bool {
+	if ctx.App == nil || len(ctx.App.trustedProxies) == 0 {
+		return false
+	}
+
+	remote := strings.TrimSpace(ctx.Request.RemoteAddr)
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	ip := net.ParseIP(remote)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range ctx.App.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwarded returns the forwarded value of the trusted proxy, such as proto, host, port,
+// from X-Forwarded-<Key> or Forwarded (RFC 7239), the first (client side) value wins.
+func (ctx *Context) forwarded(key string) string {
+	if !ctx.IsFromTrustedProxy() {
+		return ""
+	}
+
+	if value := ctx.Request.Header.Get("X-Forwarded-" + key); value != "" {
+		value, _, _ = strings.Cut(value, ",")
+		return strings.TrimSpace(value)
+	}
+
+	// Forwarded: for=192.0.2.60;proto=https;host=example.com, for=...
+	forwarded := ctx.Request.Header.Get("Forwarded")
+	if forwarded == "" {
+		return ""
+	}
+
+	first, _, _ := strings.Cut(forwarded, ",")
+	for _, pair := range strings.Split(first, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(name, key) {
+			return strings.Trim(value, `"`)
+		}
+	}
+
+	return ""
+}
+
+// IsTLS returns true if the request is over https, directly or via a trusted proxy (X-Forwarded-Proto: https).
+func (ctx *Context) IsTLS() bool {
+	if ctx.Request.TLS != nil {
+		return true
+	}
+
+	return strings.EqualFold(ctx.forwarded("Proto"), "https")
+}
+
+// Scheme returns the scheme of the request, https or http, honoring X-Forwarded-Proto from trusted proxies.
+func (ctx *Context) Scheme() string {
+	if ctx.IsTLS() {
+		return "https"
+	}
+
+	return "http"
+}
+
+// ForwardedHost returns the host (host:port) of the request, honoring X-Forwarded-Host from trusted proxies,
+// the forwarded host is ignored if it is not a plain host, such as evil.com/reset or user@evil.com.
+func (ctx *Context) ForwardedHost() string {
+	if host := ctx.forwarded("Host"); host != "" && isValidHost(host) {
+		return host
+	}
+
+	return ctx.Request.Host
+}
+
+// Port returns the port of the request the client connected to,
+// from X-Forwarded-Port (trusted proxies), the host, or the default port of the scheme.
+func (ctx *Context) Port() int {
+	if port, err := strconv.Atoi(ctx.forwarded("Port")); err == nil && port > 0 {
+		return port
+	}
+
+	if _, port, err := net.SplitHostPort(ctx.ForwardedHost()); err == nil {
+		if p, err := strconv.Atoi(port); err == nil {
+			return p
+		}
+	}
+
+	if ctx.IsTLS() {
+		return 443
+	}
+
+	return 80
+}
+
+// BaseURL returns the base url of the request, such as https://example.com or http://127.0.0.1:8080,
+// for building absolute urls (emails, redirects, OpenAPI servers), the default port of the scheme is omitted.
+func (ctx *Context) BaseURL() string {
+	scheme := ctx.Scheme()
+
+	host := ctx.ForwardedHost()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	// ipv6 literal
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]"
+	}
+
+	port := ctx.Port()
+	if (scheme == "https" && port == 443) || (scheme == "http" && port == 80) {
+		return scheme + "://" + host
+	}
+
+	return fmt.Sprintf("%s://%s:%d", scheme, host, port)
+}
+
+// isValidHost returns true if the host (host:port) has no userinfo, path, query or fragment.
+func isValidHost(host string) bool {
+	u, err := url.Parse("http://" + host)
+	return err == nil && u.Host == host
+}
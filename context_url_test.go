@@ -0,0 +1,202 @@
+package zoox
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextURL(t *testing.T) {
+	app := New()
+	trustedProxies, err := parseTrustedProxies([]string{"10.0.0.0/8", "::1"})
+	assert.NoError(t, err)
+	app.trustedProxies = trustedProxies
+
+	cases := []struct {
+		name    string
+		remote  string
+		host    string
+		tls     bool
+		headers map[string]string
+		scheme  string
+		port    int
+		baseURL string
+	}{
+		{
+			name:    "direct",
+			remote:  "203.0.113.1:1234",
+			host:    "example.com",
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:    "direct with port",
+			remote:  "203.0.113.1:1234",
+			host:    "example.com:8080",
+			scheme:  "http",
+			port:    8080,
+			baseURL: "http://example.com:8080",
+		},
+		{
+			name:    "direct tls",
+			remote:  "203.0.113.1:1234",
+			host:    "example.com",
+			tls:     true,
+			scheme:  "https",
+			port:    443,
+			baseURL: "https://example.com",
+		},
+		{
+			name:    "ipv6 host",
+			remote:  "203.0.113.1:1234",
+			host:    "[2001:db8::1]:8080",
+			scheme:  "http",
+			port:    8080,
+			baseURL: "http://[2001:db8::1]:8080",
+		},
+		{
+			name:   "spoofed by untrusted client",
+			remote: "203.0.113.1:1234",
+			host:   "example.com",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "evil.com",
+				"X-Forwarded-Port":  "8443",
+			},
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:    "spoofed forwarded by untrusted client",
+			remote:  "203.0.113.1:1234",
+			host:    "example.com",
+			headers: map[string]string{"Forwarded": "proto=https;host=evil.com"},
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:    "spoofed by client next to the trusted network",
+			remote:  "11.0.0.1:1234",
+			host:    "example.com",
+			headers: map[string]string{"X-Forwarded-Host": "evil.com"},
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:   "trusted proxy",
+			remote: "10.1.2.3:1234",
+			host:   "backend:8080",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "api.example.com",
+			},
+			scheme:  "https",
+			port:    443,
+			baseURL: "https://api.example.com",
+		},
+		{
+			name:   "trusted proxy with port",
+			remote: "[::1]:1234",
+			host:   "backend:8080",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "api.example.com",
+				"X-Forwarded-Port":  "8443",
+			},
+			scheme:  "https",
+			port:    8443,
+			baseURL: "https://api.example.com:8443",
+		},
+		{
+			name:    "trusted proxy chain, the first value wins",
+			remote:  "10.1.2.3:1234",
+			host:    "backend",
+			headers: map[string]string{"X-Forwarded-Proto": "https, http"},
+			scheme:  "https",
+			port:    443,
+			baseURL: "https://backend",
+		},
+		{
+			name:    "trusted proxy forwarded",
+			remote:  "10.1.2.3:1234",
+			host:    "backend",
+			headers: map[string]string{"Forwarded": `for=203.0.113.1;proto=https;host="api.example.com:8443", for=10.0.0.2`},
+			scheme:  "https",
+			port:    8443,
+			baseURL: "https://api.example.com:8443",
+		},
+		{
+			name:    "trusted proxy forwarding a host with path",
+			remote:  "10.1.2.3:1234",
+			host:    "example.com",
+			headers: map[string]string{"X-Forwarded-Host": "evil.com/reset?"},
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:    "trusted proxy forwarding a host with userinfo",
+			remote:  "10.1.2.3:1234",
+			host:    "example.com",
+			headers: map[string]string{"X-Forwarded-Host": "example.com@evil.com"},
+			scheme:  "http",
+			port:    80,
+			baseURL: "http://example.com",
+		},
+		{
+			name:    "trusted proxy forwarding an invalid port",
+			remote:  "10.1.2.3:1234",
+			host:    "example.com",
+			headers: map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Port": "https"},
+			scheme:  "https",
+			port:    443,
+			baseURL: "https://example.com",
+		},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		req.Host = c.host
+		if c.tls {
+			req.TLS = &tls.ConnectionState{}
+		}
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		ctx := NewContext(app, httptest.NewRecorder(), req)
+
+		assert.Equal(t, c.scheme, ctx.Scheme(), c.name)
+		assert.Equal(t, c.scheme == "https", ctx.IsTLS(), c.name)
+		assert.Equal(t, c.port, ctx.Port(), c.name)
+		assert.Equal(t, c.baseURL, ctx.BaseURL(), c.name)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	cases := []struct {
+		proxies []string
+		valid   bool
+	}{
+		{[]string{"10.0.0.1", "10.0.0.0/8", "::1", "fd00::/8"}, true},
+		{[]string{"proxy"}, false},
+		{[]string{"10.0.0.0/33"}, false},
+		{[]string{""}, false},
+	}
+
+	for _, c := range cases {
+		_, err := parseTrustedProxies(c.proxies)
+		if c.valid {
+			assert.NoError(t, err, c.proxies)
+		} else {
+			assert.Error(t, err, c.proxies)
+		}
+	}
+}
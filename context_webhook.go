@@ -0,0 +1,162 @@
+package zoox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/webhook"
+)
+
+// Webhook providers of ctx.VerifyWebhook.
+const (
+	// WebhookProviderGitHub verifies X-Hub-Signature-256.
+	WebhookProviderGitHub = "github"
+	// WebhookProviderStripe verifies Stripe-Signature.
+	WebhookProviderStripe = "stripe"
+	// WebhookProviderSlack verifies X-Slack-Signature with X-Slack-Request-Timestamp.
+	WebhookProviderSlack = "slack"
+	// WebhookProviderZoox verifies X-Webhook-Signature sent by app.Webhooks().
+	WebhookProviderZoox = "zoox"
+)
+
+// ErrWebhookSignature is returned when the webhook signature is missing or invalid.
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookTolerance is the max age of timestamped webhook signatures, against replay attacks.
+var WebhookTolerance = 5 * time.Minute
+
+// VerifyWebhook verifies the inbound webhook signature of the provider from the raw body,
+// the body is kept for binding after verification.
+//
+// Example:
+//
+//	app.Post("/webhooks/github", func(ctx *zoox.Context) {
+//		if err := ctx.VerifyWebhook(zoox.WebhookProviderGitHub, secret); err != nil {
+//			ctx.Fail(err, 401, err.Error(), 401)
+//			return
+//		}
+//
+//		var event PushEvent
+//		ctx.BindJSON(&event)
+//	})
+func (ctx *Context) VerifyWebhook(provider, secret string) error {
+	if secret == "" {
+		return errors.New("webhook secret is required")
+	}
+
+	body, err := ctx.webhookBody()
+	if err != nil {
+		return err
+	}
+
+	switch provider {
+	case WebhookProviderGitHub:
+		signature := ctx.Header().Get("X-Hub-Signature-256")
+		expected := "sha256=" + hmacSHA256Hex(secret, body)
+		return compareWebhookSignature(expected, signature)
+	case WebhookProviderStripe:
+		return verifyStripeSignature(ctx.Header().Get("Stripe-Signature"), secret, body)
+	case WebhookProviderSlack:
+		timestamp := ctx.Header().Get("X-Slack-Request-Timestamp")
+		if err := checkWebhookTimestamp(timestamp); err != nil {
+			return err
+		}
+
+		expected := "v0=" + hmacSHA256Hex(secret, []byte("v0:"+timestamp+":"), body)
+		return compareWebhookSignature(expected, ctx.Header().Get("X-Slack-Signature"))
+	case WebhookProviderZoox:
+		timestamp := ctx.Header().Get(webhook.HeaderTimestamp)
+		if err := checkWebhookTimestamp(timestamp); err != nil {
+			return err
+		}
+
+		ts, _ := strconv.ParseInt(timestamp, 10, 64)
+		if !webhook.Verify(secret, ts, body, ctx.Header().Get(webhook.HeaderSignature)) {
+			return ErrWebhookSignature
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported webhook provider: %s", provider)
+	}
+}
+
+// webhookBody uses ctx.RawBody if enabled (with its size cap), otherwise clones the body.
+func (ctx *Context) webhookBody() ([]byte, error) {
+	if ctx.rawBody != nil {
+		return ctx.RawBody()
+	}
+
+	reader, err := ctx.CloneBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
+
+// verifyStripeSignature verifies the header: t=<timestamp>,v1=<signature>[,v1=<signature>].
+func verifyStripeSignature(header, secret string, body []byte) error {
+	timestamp := ""
+	signatures := []string{}
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if err := checkWebhookTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	expected := hmacSHA256Hex(secret, []byte(timestamp+"."), body)
+	for _, signature := range signatures {
+		if compareWebhookSignature(expected, signature) == nil {
+			return nil
+		}
+	}
+
+	return ErrWebhookSignature
+}
+
+func checkWebhookTimestamp(timestamp string) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrWebhookSignature)
+	}
+
+	age := time.Since(time.Unix(ts, 0))
+	if age > WebhookTolerance || age < -WebhookTolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrWebhookSignature)
+	}
+
+	return nil
+}
+
+func compareWebhookSignature(expected, signature string) error {
+	if signature == "" || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrWebhookSignature
+	}
+
+	return nil
+}
+
+func hmacSHA256Hex(secret string, parts ...[]byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, part := range parts {
+		mac.Write(part)
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,191 @@
+package zoox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func signWebhook(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestContextVerifyWebhook(t *testing.T) {
+	const secret = "whsec"
+	const body = `{"event":"push"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-2*WebhookTolerance).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(2*WebhookTolerance).Unix(), 10)
+
+	cases := []struct {
+		name     string
+		provider string
+		secret   string
+		body     string
+		headers  map[string]string
+		valid    bool
+	}{
+		{
+			name:     "github",
+			provider: WebhookProviderGitHub,
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=" + signWebhook(secret, body)},
+			valid:    true,
+		},
+		{
+			name:     "github missing signature",
+			provider: WebhookProviderGitHub,
+		},
+		{
+			name:     "github tampered body",
+			provider: WebhookProviderGitHub,
+			body:     `{"event":"delete"}`,
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=" + signWebhook(secret, body)},
+		},
+		{
+			name:     "github other secret",
+			provider: WebhookProviderGitHub,
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=" + signWebhook("other", body)},
+		},
+		{
+			name:     "github without prefix",
+			provider: WebhookProviderGitHub,
+			headers:  map[string]string{"X-Hub-Signature-256": signWebhook(secret, body)},
+		},
+		{
+			name:     "stripe",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "t=" + now + ",v1=" + signWebhook(secret, now+"."+body)},
+			valid:    true,
+		},
+		{
+			name:     "stripe rotated secret, one of the signatures matches",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "t=" + now + ",v1=" + signWebhook("old", now+"."+body) + ",v1=" + signWebhook(secret, now+"."+body)},
+			valid:    true,
+		},
+		{
+			name:     "stripe replayed",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "t=" + stale + ",v1=" + signWebhook(secret, stale+"."+body)},
+		},
+		{
+			name:     "stripe timestamp swapped",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "t=" + now + ",v1=" + signWebhook(secret, stale+"."+body)},
+		},
+		{
+			name:     "stripe v0 signature only",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "t=" + now + ",v0=" + signWebhook(secret, now+"."+body)},
+		},
+		{
+			name:     "stripe missing timestamp",
+			provider: WebhookProviderStripe,
+			headers:  map[string]string{"Stripe-Signature": "v1=" + signWebhook(secret, "."+body)},
+		},
+		{
+			name:     "slack",
+			provider: WebhookProviderSlack,
+			headers: map[string]string{
+				"X-Slack-Request-Timestamp": now,
+				"X-Slack-Signature":         "v0=" + signWebhook(secret, "v0:"+now+":"+body),
+			},
+			valid: true,
+		},
+		{
+			name:     "slack from the future",
+			provider: WebhookProviderSlack,
+			headers: map[string]string{
+				"X-Slack-Request-Timestamp": future,
+				"X-Slack-Signature":         "v0=" + signWebhook(secret, "v0:"+future+":"+body),
+			},
+		},
+		{
+			name:     "slack tampered body",
+			provider: WebhookProviderSlack,
+			body:     `{"event":"delete"}`,
+			headers: map[string]string{
+				"X-Slack-Request-Timestamp": now,
+				"X-Slack-Signature":         "v0=" + signWebhook(secret, "v0:"+now+":"+body),
+			},
+		},
+		{
+			name:     "zoox",
+			provider: WebhookProviderZoox,
+			headers: map[string]string{
+				webhook.HeaderTimestamp: now,
+				webhook.HeaderSignature: "sha256=" + signWebhook(secret, now+"."+body),
+			},
+			valid: true,
+		},
+		{
+			name:     "zoox replayed",
+			provider: WebhookProviderZoox,
+			headers: map[string]string{
+				webhook.HeaderTimestamp: stale,
+				webhook.HeaderSignature: "sha256=" + signWebhook(secret, stale+"."+body),
+			},
+		},
+		{
+			name:     "zoox invalid timestamp",
+			provider: WebhookProviderZoox,
+			headers: map[string]string{
+				webhook.HeaderTimestamp: "now",
+				webhook.HeaderSignature: "sha256=" + signWebhook(secret, "now."+body),
+			},
+		},
+		{
+			name:     "empty secret is rejected",
+			provider: WebhookProviderGitHub,
+			secret:   "-",
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=" + signWebhook("", body)},
+		},
+		{
+			name:     "unsupported provider",
+			provider: "gitlab",
+			headers:  map[string]string{"X-Gitlab-Token": secret},
+		},
+	}
+
+	for _, c := range cases {
+		payload := body
+		if c.body != "" {
+			payload = c.body
+		}
+		key := secret
+		if c.secret == "-" {
+			key = ""
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		ctx := NewContext(New(), httptest.NewRecorder(), req)
+
+		err := ctx.VerifyWebhook(c.provider, key)
+		if c.valid {
+			assert.NoError(t, err, c.name)
+		} else {
+			assert.Error(t, err, c.name)
+		}
+
+		// the body is kept for binding
+		kept, _ := io.ReadAll(ctx.Request.Body)
+		assert.Equal(t, payload, string(kept), fmt.Sprintf("%s: body", c.name))
+	}
+}
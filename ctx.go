@@ -0,0 +1,71 @@
+package zoox
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-zoox/zoox/components/context/body"
+	"github.com/go-zoox/zoox/components/context/form"
+	"github.com/go-zoox/zoox/components/context/param"
+	"github.com/go-zoox/zoox/components/context/query"
+	"github.com/go-zoox/zoox/components/context/state"
+	"github.com/go-zoox/zoox/components/context/user"
+)
+
+// Ctx is the interface of the commonly used Context capabilities,
+// libraries and tests can depend on it and provide mock implementations.
+//
+// *Context always satisfies Ctx.
+type Ctx interface {
+	// Context returns the request context.Context.
+	Context() context.Context
+	// Next runs the next handler.
+	Next()
+	// Abort prevents the pending handlers from being called.
+	Abort()
+	// IsAborted returns true if the context was aborted.
+	IsAborted() bool
+
+	// request
+	Param() param.Param
+	Query() query.Query
+	Header() http.Header
+	Form() form.Form
+	Body() body.Body
+	RequestID() string
+	IP() string
+	ContentType() string
+	BearerToken() (token string, ok bool)
+
+	// binding
+	BindJSON(obj interface{}) error
+	BindForm(obj interface{}) error
+	BindParams(obj interface{}) error
+	BindHeader(obj interface{}) error
+	BindQuery(obj interface{}) error
+	BindBody(obj interface{}) error
+
+	// response
+	Status(status int)
+	StatusCode() int
+	SetHeader(key string, value string)
+	AddHeader(key string, value string)
+	Write(b []byte)
+	String(status int, format string, values ...interface{})
+	JSON(status int, obj interface{}, opts ...func(opt *JSONOption))
+	Data(status int, contentType string, data []byte)
+	HTML(status int, html string, data ...any)
+	Error(status int, message string)
+	Success(result interface{})
+	Fail(err error, code int, message string, status ...int)
+	FailWithError(err HTTPError)
+	Redirect(url string, status ...int)
+
+	// state
+	Value(key string) any
+	SetValue(key string, value any)
+	State() state.State
+	User() user.User
+}
+
+var _ Ctx = (*Context)(nil)
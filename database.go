@@ -0,0 +1,144 @@
+package zoox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-zoox/zoox/components/application/migrate"
+)
+
+// EnvMigrate is the env of the migrate command (up[:steps], down[:steps], status),
+// if set, Run runs the migrations and returns without serving, used by zoox migrate.
+const EnvMigrate = "ZOOX_MIGRATE"
+
+// EnvMigrateOutput is the env of the file to write the migration status as json after EnvMigrate.
+const EnvMigrateOutput = "ZOOX_MIGRATE_OUTPUT"
+
+// DefaultMigrationsDir is the default directory of sql migrations.
+const DefaultMigrationsDir = "migrations"
+
+// Database returns the sql database opened with the database config,
+// the driver must be registered by importing it, such as _ "github.com/lib/pq".
+func (app *Application) Database() *sql.DB {
+	app.once.database.Do(func() {
+		if app.database != nil {
+			return
+		}
+
+		cfg := app.Config.Database
+		if cfg.Driver == "" || cfg.DSN == "" {
+			panic("database config (driver, dsn) is required for database in application")
+		}
+
+		db, err := sql.Open(cfg.Driver, cfg.DSN)
+		if err != nil {
+			panic(fmt.Errorf("failed to open database: %v", err))
+		}
+
+		if cfg.MaxOpenConns > 0 {
+			db.SetMaxOpenConns(cfg.MaxOpenConns)
+		}
+		if cfg.MaxIdleConns > 0 {
+			db.SetMaxIdleConns(cfg.MaxIdleConns)
+		}
+		if cfg.ConnMaxLifetime > 0 {
+			db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		}
+
+		app.database = db
+	})
+
+	return app.database
+}
+
+// SetDatabase sets the sql database, such as a connection opened with custom options.
+func (app *Application) SetDatabase(db *sql.DB) {
+	app.database = db
+}
+
+// Migrations returns the migrator sharing the app database,
+// sql migrations in the migrations directory (database.migrations) are registered if it exists,
+// go migrations can be registered with app.Migrations().Register.
+func (app *Application) Migrations() *migrate.Migrator {
+	app.once.migrations.Do(func() {
+		app.migrations = migrate.New(app.Database(), &migrate.Config{
+			Logf: app.Logger().Infof,
+		})
+
+		dir := app.Config.Database.Migrations
+		if dir == "" {
+			dir = DefaultMigrationsDir
+		}
+
+		if _, err := os.Stat(dir); err == nil {
+			if err := app.migrations.RegisterFS(os.DirFS(dir), "."); err != nil {
+				panic(fmt.Errorf("failed to load migrations in %s: %v", dir, err))
+			}
+		}
+	})
+
+	return app.migrations
+}
+
+// AutoMigrate applies the pending migrations if database.auto_migrate is enabled, it is called by Run.
+func (app *Application) AutoMigrate() error {
+	if !app.Config.Database.AutoMigrate {
+		return nil
+	}
+
+	applied, err := app.Migrations().Up(context.Background(), 0)
+	if err != nil {
+		return fmt.Errorf("failed to auto migrate: %v", err)
+	}
+
+	app.Logger().Infof("[migrate] %d migrations applied", len(applied))
+	return nil
+}
+
+// runMigrateCommand runs the migrate command of EnvMigrate, the status is written to the output file if set.
+func (app *Application) runMigrateCommand(command, output string) error {
+	action, steps, _ := strings.Cut(command, ":")
+	n := 0
+	if steps != "" {
+		var err error
+		if n, err = strconv.Atoi(steps); err != nil {
+			return fmt.Errorf("invalid migrate steps %s: %v", steps, err)
+		}
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "up":
+		if _, err := app.Migrations().Up(ctx, n); err != nil {
+			return err
+		}
+	case "down":
+		if _, err := app.Migrations().Down(ctx, n); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %s, available: up, down, status", action)
+	}
+
+	if output == "" {
+		return nil
+	}
+
+	statuses, err := app.Migrations().Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, data, 0644)
+}
@@ -0,0 +1,237 @@
+package zoox
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultMiddlewares is the default global middleware,
+// prefer RegisterDefault, which supports the priority and is safe for concurrent use.
+var DefaultMiddlewares = map[string]func(app *Application){
+	// Logger,
+}
+
+// DefaultGroupsFns are the default groups, key: prefix, see DefaultGroup.
+var DefaultGroupsFns = map[string]func(r *RouterGroup){}
+
+// defaultRegistry keeps the priority and registration order of DefaultMiddlewares.
+var defaultRegistry = struct {
+	sync.RWMutex
+	entries map[string]*defaultEntry
+	seq     int
+}{
+	entries: map[string]*defaultEntry{},
+}
+
+type defaultEntry struct {
+	priority int
+	seq      int
+}
+
+// DefaultOption is the option of RegisterDefault.
+type DefaultOption func(e *defaultEntry)
+
+// Priority sets the priority of the default middleware, lower runs first, default 0.
+// The defaults of the same priority run in registration order.
+func Priority(priority int) DefaultOption {
+	return func(e *defaultEntry) {
+		e.priority = priority
+	}
+}
+
+// DefaultInfo is the registered default middleware.
+type DefaultInfo struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// RegisterDefault registers the default middleware applied to all the apps created by New later,
+// the default of the same name is replaced, keeping its registration order.
+//
+// Example:
+//
+//	zoox.RegisterDefault("recovery", func(app *zoox.Application) {
+//		app.Use(middleware.Recovery())
+//	}, zoox.Priority(-100))
+func RegisterDefault(name string, fn func(app *Application), opts ...DefaultOption) {
+	if name == "" {
+		panic("zoox: default middleware name is required")
+	}
+
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+
+	e, ok := defaultRegistry.entries[name]
+	if !ok {
+		defaultRegistry.seq++
+		e = &defaultEntry{seq: defaultRegistry.seq}
+		defaultRegistry.entries[name] = e
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	DefaultMiddlewares[name] = fn
+}
+
+// UnregisterDefault removes the default middleware, the apps created already are not affected.
+func UnregisterDefault(name string) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+
+	delete(defaultRegistry.entries, name)
+	delete(DefaultMiddlewares, name)
+}
+
+// DefaultMiddleware registers the default middleware, see RegisterDefault.
+func DefaultMiddleware(name string, fn func(app *Application)) {
+	RegisterDefault(name, fn)
+}
+
+// DefaultGroup registers the default group applied to all the apps created by New later.
+func DefaultGroup(prefix string, fn func(r *RouterGroup)) {
+	defaultRegistry.Lock()
+	defer defaultRegistry.Unlock()
+
+	if _, ok := DefaultGroupsFns[prefix]; ok {
+		panic(fmt.Errorf("zoox: default group (%s) already registered", prefix))
+	}
+
+	DefaultGroupsFns[prefix] = fn
+}
+
+// RegisteredDefaults returns the registered default middlewares in order.
+func RegisteredDefaults() []DefaultInfo {
+	defaultRegistry.RLock()
+	defer defaultRegistry.RUnlock()
+
+	infos := []DefaultInfo{}
+	for _, name := range defaultMiddlewaresInOrder() {
+		info := DefaultInfo{Name: name}
+		if e, ok := defaultRegistry.entries[name]; ok {
+			info.Priority = e.priority
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// defaultMiddlewaresInOrder returns the names of default middlewares by priority and registration order,
+// the ones set to DefaultMiddlewares directly are sorted by name after the registered ones of the same priority.
+func defaultMiddlewaresInOrder() []string {
+	names := []string{}
+	for name := range DefaultMiddlewares {
+		names = append(names, name)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		a, aok := defaultRegistry.entries[names[i]]
+		b, bok := defaultRegistry.entries[names[j]]
+
+		pa, pb := 0, 0
+		if aok {
+			pa = a.priority
+		}
+		if bok {
+			pb = b.priority
+		}
+		if pa != pb {
+			return pa < pb
+		}
+
+		switch {
+		case aok && bok:
+			return a.seq < b.seq
+		case aok != bok:
+			return aok
+		default:
+			return names[i] < names[j]
+		}
+	})
+
+	return names
+}
+
+// defaultGroupsInOrder returns the prefixes of default groups, sorted for deterministic order.
+func defaultGroupsInOrder() []string {
+	prefixes := []string{}
+	for prefix := range DefaultGroupsFns {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	return prefixes
+}
+
+// WithoutDefaults opts the app out of the default middlewares and groups,
+// or only the given names (middleware names or group prefixes).
+//
+// Example:
+//
+//	app := zoox.New(zoox.WithoutDefaults())
+//	app := zoox.New(zoox.WithoutDefaults("logger", "pprof"))
+func WithoutDefaults(names ...string) Option {
+	return func(app *Application) {
+		if len(names) == 0 {
+			app.defaults.disabled = true
+			return
+		}
+
+		if app.defaults.skip == nil {
+			app.defaults.skip = map[string]bool{}
+		}
+		for _, name := range names {
+			app.defaults.skip[name] = true
+		}
+	}
+}
+
+// ActiveDefaults returns the default middlewares and groups applied to the app.
+func (app *Application) ActiveDefaults() (middlewares []string, groups []string) {
+	return append([]string{}, app.defaults.middlewares...), append([]string{}, app.defaults.groups...)
+}
+
+// applyDefaults applies the default middlewares and groups, except the opted out ones.
+func (app *Application) applyDefaults() {
+	if app.defaults.disabled {
+		return
+	}
+
+	defaultRegistry.RLock()
+	names := defaultMiddlewaresInOrder()
+	middlewares := map[string]func(app *Application){}
+	for _, name := range names {
+		middlewares[name] = DefaultMiddlewares[name]
+	}
+	prefixes := defaultGroupsInOrder()
+	groups := map[string]func(r *RouterGroup){}
+	for _, prefix := range prefixes {
+		groups[prefix] = DefaultGroupsFns[prefix]
+	}
+	defaultRegistry.RUnlock()
+
+	// global middlewares, named by the default middleware name
+	for _, name := range names {
+		if app.defaults.skip[name] {
+			continue
+		}
+
+		app.RouterGroup.naming = name
+		middlewares[name](app)
+		app.defaults.middlewares = append(app.defaults.middlewares, name)
+	}
+	app.RouterGroup.naming = ""
+
+	// global groups
+	for _, prefix := range prefixes {
+		if app.defaults.skip[prefix] {
+			continue
+		}
+
+		groups[prefix](app.Group(prefix))
+		app.defaults.groups = append(app.defaults.groups, prefix)
+	}
+}
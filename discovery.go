@@ -0,0 +1,123 @@
+package zoox
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-zoox/proxy"
+	"github.com/go-zoox/zoox/components/application/discovery"
+	"github.com/go-zoox/zoox/components/application/jsonrpcclient"
+)
+
+// DiscoveryScheme is the scheme of the targets resolved by the registered services,
+// such as discovery://orders (http) or discovery+https://orders, see app.RegisterService.
+const DiscoveryScheme = "discovery"
+
+// RegisterService registers the resolver of the service, the endpoints are watched until the app is shut down.
+// The service is used by the targets discovery://<name> of g.Proxy and app.JSONRPCClient.
+//
+// Example:
+//
+//	app.RegisterService("orders", discovery.NewConsul(&discovery.ConsulConfig{Service: "orders"}))
+//
+//	app.Proxy("/api/orders", "discovery://orders")
+func (app *Application) RegisterService(name string, resolver discovery.Resolver, cfg ...*discovery.Config) *discovery.Target {
+	cfgX := &discovery.Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.OnError == nil {
+		cfgX.OnError = func(err error) {
+			app.Logger().Warnf("[discovery][%s] failed to watch: %v", name, err)
+		}
+	}
+
+	target := discovery.NewTarget(app.lifecycleContext(), resolver, cfgX)
+	if previous, ok := app.services.Swap(name, target); ok {
+		previous.(*discovery.Target).Close()
+	}
+
+	return target
+}
+
+// Service returns the target of the registered service, nil if not registered.
+func (app *Application) Service(name string) *discovery.Target {
+	if target, ok := app.services.Load(name); ok {
+		return target.(*discovery.Target)
+	}
+
+	return nil
+}
+
+// parseDiscoveryTarget parses the target discovery://<name>/<path> into the service name and http(s)://<path>.
+func parseDiscoveryTarget(target string) (name string, scheme string, path string, ok bool) {
+	switch {
+	case strings.HasPrefix(target, DiscoveryScheme+"://"):
+		scheme = "http"
+	case strings.HasPrefix(target, DiscoveryScheme+"+https://"):
+		scheme = "https"
+	default:
+		return "", "", "", false
+	}
+
+	name = target[strings.Index(target, "://")+3:]
+	if i := strings.IndexAny(name, "/?"); i != -1 {
+		name, path = name[:i], name[i:]
+	}
+
+	return name, scheme, path, true
+}
+
+// discoveryProxy returns the proxy handler of the service, the endpoint is picked for each request,
+// and skipped for a while if it responds 502 (unreachable).
+func (app *Application) discoveryProxy(target string, cfg *ProxyConfig) HandlerFunc {
+	name, scheme, path, _ := parseDiscoveryTarget(target)
+
+	// handlers are the proxies of the endpoints, key: host:port
+	handlers := sync.Map{}
+
+	return func(ctx *Context) {
+		service := app.Service(name)
+		if service == nil {
+			ctx.Fail(fmt.Errorf("service %s is not registered", name), http.StatusBadGateway, "service not found", http.StatusBadGateway)
+			return
+		}
+
+		endpoint, err := service.Next(ctx.Context())
+		if err != nil {
+			ctx.Fail(err, http.StatusServiceUnavailable, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		handler, ok := handlers.Load(endpoint.Address)
+		if !ok {
+			handler, _ = handlers.LoadOrStore(endpoint.Address, WrapH(proxy.NewSingleHost(scheme+"://"+endpoint.Address+path, &cfg.SingleHostConfig)))
+		}
+
+		handler.(HandlerFunc)(ctx)
+
+		if ctx.Writer.Status() == http.StatusBadGateway {
+			service.MarkFailed(endpoint.Address)
+		}
+	}
+}
+
+// discoveryJSONRPCConfig returns the config of the JSON-RPC client of the target discovery://<name>/<path>.
+func (app *Application) discoveryJSONRPCConfig(target string, cfg ...*jsonrpcclient.Config) (string, []*jsonrpcclient.Config) {
+	name, scheme, path, ok := parseDiscoveryTarget(target)
+	if !ok {
+		return target, cfg
+	}
+
+	cfgX := &jsonrpcclient.Config{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Discovery == nil {
+		cfgX.Discovery = app.Service(name)
+	}
+
+	return scheme + "://" + name + path, []*jsonrpcclient.Config{cfgX}
+}
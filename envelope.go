@@ -0,0 +1,34 @@
+package zoox
+
+import "fmt"
+
+// RawResponse opts the route out of the response envelope (middleware.Envelope),
+// such as file streams and webhooks, the response is not buffered.
+//
+//	app.Route(http.MethodGet, "/files/:name", download).RawResponse()
+func (r *Route) RawResponse() *Route {
+	if r.app.rawResponses == nil {
+		r.app.rawResponses = map[string]bool{}
+	}
+
+	for _, method := range r.methods {
+		r.app.rawResponses[fmt.Sprintf("%s %s", method, r.path)] = true
+	}
+
+	return r
+}
+
+// SkipEnvelope opts the request out of the response envelope in the handler, the response is written as is.
+func (ctx *Context) SkipEnvelope() {
+	ctx.rawResponse = true
+}
+
+// IsRawResponse returns true if the response is opted out of the envelope, by Route.RawResponse or ctx.SkipEnvelope.
+func (ctx *Context) IsRawResponse() bool {
+	return ctx.rawResponse || (ctx.route != "" && ctx.App.rawResponses[ctx.route])
+}
+
+// Failure returns the error of ctx.Fail, nil if not failed.
+func (ctx *Context) Failure() error {
+	return ctx.failure
+}
@@ -1,5 +1,7 @@
 package zoox
 
+import "errors"
+
 // HTTPError is a custom error type for HTTP errors.
 type HTTPError interface {
 	Status() int
@@ -8,3 +10,54 @@ type HTTPError interface {
 	Error() string
 	Raw() error
 }
+
+// ErrorHandlerFunc handles the error of the request, see app.OnError and g.OnError.
+type ErrorHandlerFunc func(ctx *Context, err error)
+
+type httpError struct {
+	status  int
+	code    int
+	message string
+	raw     error
+}
+
+// NewHTTPError creates a HTTPError with the status, the code is the same as status.
+func NewHTTPError(status int, message string, raw ...error) HTTPError {
+	e := &httpError{
+		status:  status,
+		code:    status,
+		message: message,
+	}
+	if len(raw) > 0 && raw[0] != nil {
+		e.raw = raw[0]
+	} else {
+		e.raw = errors.New(message)
+	}
+
+	return e
+}
+
+func (e *httpError) Status() int {
+	return e.status
+}
+
+func (e *httpError) Code() int {
+	return e.code
+}
+
+func (e *httpError) Message() string {
+	return e.message
+}
+
+func (e *httpError) Error() string {
+	return e.raw.Error()
+}
+
+func (e *httpError) Raw() error {
+	return e.raw
+}
+
+// Unwrap returns the raw error.
+func (e *httpError) Unwrap() error {
+	return e.raw
+}
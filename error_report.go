@@ -0,0 +1,176 @@
+package zoox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/errreport"
+)
+
+// ErrorReporter returns the error reporter by config error_report.provider (sentry, rollbar or bugsnag), nil if disabled.
+// The panics (ctx.ReportPanic) and server errors (ctx.HandleError) are reported in background.
+//
+//	# config
+//	error_report:
+//	  provider: sentry
+//	  dsn: https://key@o0.ingest.sentry.io/0
+//	  release: api@1.2.3
+func (app *Application) ErrorReporter() errreport.Reporter {
+	app.once.errorReporter.Do(func() {
+		if app.errorReporter != nil {
+			return
+		}
+
+		cfg := app.Config.ErrorReport
+		var reporter errreport.Reporter
+		switch cfg.Provider {
+		case "":
+			return
+		case "sentry":
+			r, err := errreport.NewSentry(&errreport.SentryConfig{DSN: cfg.DSN})
+			if err != nil {
+				panic(fmt.Errorf("failed to create sentry error reporter: %v", err))
+			}
+
+			reporter = r
+		case "rollbar":
+			reporter = errreport.NewRollbar(&errreport.RollbarConfig{Token: cfg.Token})
+		case "bugsnag":
+			reporter = errreport.NewBugsnag(&errreport.BugsnagConfig{APIKey: cfg.Token})
+		default:
+			panic(fmt.Errorf("unsupported error report provider: %s", cfg.Provider))
+		}
+
+		app.errorReporter = errreport.Async(reporter, func(event *errreport.Event, err error) {
+			app.Logger().Errorf("[error_report] failed to report %s: %s", event.Error, err)
+		})
+	})
+
+	return app.errorReporter
+}
+
+// SetErrorReporter sets the error reporter, such as:
+//
+//	app.SetErrorReporter(errreport.Async(errreport.NewBugsnag(&errreport.BugsnagConfig{APIKey: key}), nil))
+func (app *Application) SetErrorReporter(r errreport.Reporter) {
+	app.errorReporter = r
+}
+
+// SetErrorReportScrubber sets the scrubber of the error events, it is called before every event is reported,
+// returns nil to drop the event. The event user only has the ID, username and IP by default,
+// the scrubber can add the fields safe to report or remove more, such as:
+//
+//	app.SetErrorReportScrubber(func(ctx *zoox.Context, event *errreport.Event) *errreport.Event {
+//		event.User.Username = ""
+//		delete(event.Tags, "route")
+//		return event
+//	})
+func (app *Application) SetErrorReportScrubber(fn func(ctx *Context, event *errreport.Event) *errreport.Event) {
+	app.errorReportScrubber = fn
+}
+
+// ReportError reports the error with the request, user and release info to the error reporter,
+// it does nothing if the reporter is not configured.
+func (ctx *Context) ReportError(err error, extra ...map[string]any) {
+	event := ctx.newErrorEvent(err, false)
+	if event == nil {
+		return
+	}
+
+	if len(extra) > 0 {
+		event.Extra = extra[0]
+	}
+
+	ctx.report(event)
+}
+
+// reportPanic reports the recovered panic, called by ctx.ReportPanic.
+func (ctx *Context) reportPanic(value any) {
+	event := ctx.newErrorEvent(&errreport.PanicError{Value: value}, true)
+	if event == nil {
+		return
+	}
+
+	ctx.report(event)
+}
+
+// report scrubs the event by app.SetErrorReportScrubber and reports it.
+func (ctx *Context) report(event *errreport.Event) {
+	if ctx.App.errorReportScrubber != nil {
+		if event = ctx.App.errorReportScrubber(ctx, event); event == nil {
+			return
+		}
+	}
+
+	ctx.App.errorReporter.Report(event)
+}
+
+// reportServerError reports the error handled by ctx.HandleError if it is responded with 5xx.
+func (ctx *Context) reportServerError(err error) {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status() < http.StatusInternalServerError {
+		return
+	}
+
+	ctx.ReportError(err)
+}
+
+func (ctx *Context) newErrorEvent(err error, unhandled bool) *errreport.Event {
+	if ctx.App == nil || ctx.App.ErrorReporter() == nil {
+		return nil
+	}
+
+	app := ctx.App
+	cfg := app.Config.ErrorReport
+
+	environment := cfg.Environment
+	if environment == "" {
+		environment = app.Env().Get(BuiltInEnvMode)
+	}
+	if environment == "" {
+		environment = "development"
+	}
+
+	level := errreport.LevelError
+	if unhandled {
+		level = errreport.LevelFatal
+	}
+
+	event := &errreport.Event{
+		Timestamp: time.Now(),
+		Level:     level,
+		Error:     err,
+		Unhandled: unhandled,
+		// skips newErrorEvent and the report method
+		Stack:       errreport.Callers(2),
+		Request:     errreport.NewRequest(ctx.Request, ctx.ClientIP(), cfg.SensitiveKeys...),
+		Release:     cfg.Release,
+		Environment: environment,
+		Tags: map[string]string{
+			"app":          app.Config.Name,
+			"request_id":   ctx.RequestID(),
+			"go_version":   app.Runtime().GoVersion(),
+			"os":           app.Runtime().OS(),
+			"arch":         app.Runtime().Arch(),
+			"zoox_version": Version,
+		},
+	}
+
+	if ctx.route != "" {
+		event.Tags["route"] = strings.TrimPrefix(ctx.route, ctx.Method+" ")
+	}
+
+	// the user object may carry emails, tokens or password hashes, only its identifiers are reported
+	if ctx.user != nil && ctx.user.Get() != nil {
+		event.User = &errreport.User{
+			ID:       userID(ctx.user.Get()),
+			Username: userName(ctx.user.Get()),
+			IP:       ctx.ClientIP(),
+		}
+	}
+
+	return event
+}
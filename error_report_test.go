@@ -0,0 +1,116 @@
+package zoox
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/errreport"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingReporter struct {
+	events []*errreport.Event
+}
+
+func (r *recordingReporter) Report(event *errreport.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingReporter) Flush(timeout time.Duration) bool {
+	return true
+}
+
+type reportedUser struct {
+	ID           string
+	Username     string
+	Email        string
+	PasswordHash string
+	Token        string
+}
+
+func TestReportErrorScrubsUser(t *testing.T) {
+	cases := []struct {
+		name     string
+		user     any
+		scrubber func(ctx *Context, event *errreport.Event) *errreport.Event
+		expected *errreport.User
+		dropped  bool
+	}{
+		{
+			name:     "no user",
+			expected: nil,
+		},
+		{
+			name:     "struct user only reports identifiers",
+			user:     &reportedUser{ID: "1", Username: "zero", Email: "zero@example.com", PasswordHash: "$2a$10$hash", Token: "tok"},
+			expected: &errreport.User{ID: "1", Username: "zero", IP: "192.0.2.1"},
+		},
+		{
+			name:     "map user only reports identifiers",
+			user:     map[string]any{"id": 2, "username": "two", "email": "two@example.com", "token": "tok"},
+			expected: &errreport.User{ID: "2", Username: "two", IP: "192.0.2.1"},
+		},
+		{
+			name: "scrubber removes the username",
+			user: &reportedUser{ID: "1", Username: "zero"},
+			scrubber: func(ctx *Context, event *errreport.Event) *errreport.Event {
+				event.User.Username = ""
+				return event
+			},
+			expected: &errreport.User{ID: "1", IP: "192.0.2.1"},
+		},
+		{
+			name: "scrubber drops the event",
+			user: &reportedUser{ID: "1", Username: "zero"},
+			scrubber: func(ctx *Context, event *errreport.Event) *errreport.Event {
+				return nil
+			},
+			dropped: true,
+		},
+	}
+
+	for _, c := range cases {
+		reporter := &recordingReporter{}
+		app := New()
+		app.SetErrorReporter(reporter)
+		app.SetErrorReportScrubber(c.scrubber)
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		ctx := NewContext(app, httptest.NewRecorder(), req)
+		if c.user != nil {
+			ctx.User().Set(c.user)
+		}
+
+		ctx.ReportError(errors.New("boom"))
+
+		if c.dropped {
+			assert.Empty(t, reporter.events, c.name)
+			continue
+		}
+
+		if assert.Len(t, reporter.events, 1, c.name) {
+			assert.Equal(t, c.expected, reporter.events[0].User, c.name)
+		}
+	}
+}
+
+func TestReportErrorScrubberSeesExtra(t *testing.T) {
+	reporter := &recordingReporter{}
+	app := New()
+	app.SetErrorReporter(reporter)
+	app.SetErrorReportScrubber(func(ctx *Context, event *errreport.Event) *errreport.Event {
+		delete(event.Extra, "card")
+		return event
+	})
+
+	ctx := NewContext(app, httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.ReportError(errors.New("boom"), map[string]any{"order": "o-1", "card": "4242424242424242"})
+
+	if assert.Len(t, reporter.events, 1) {
+		assert.Equal(t, map[string]any{"order": "o-1"}, reporter.events[0].Extra)
+	}
+}
@@ -0,0 +1,44 @@
+package zoox
+
+import (
+	"github.com/go-zoox/zoox/components/application/events"
+)
+
+// Events returns the in-process event bus, errors of async handlers are logged if not set by app.SetEvents.
+//
+//	zoox.On(app, func(ctx context.Context, event UserCreated) error {
+//		return sendWelcomeEmail(ctx, event.Email)
+//	}, events.Async())
+//
+//	app.Events().Emit(UserCreated{ID: 1, Email: "zero@example.com"})
+func (app *Application) Events() events.Bus {
+	app.once.events.Do(func() {
+		if app.events != nil {
+			return
+		}
+
+		app.events = events.New(&events.Config{
+			OnError: func(event any, err error) {
+				app.Logger().Errorf("[events] failed to handle %s: %s", events.Name(event), err)
+			},
+		})
+	})
+
+	return app.events
+}
+
+// SetEvents sets the event bus, such as with a custom error policy.
+func (app *Application) SetEvents(bus events.Bus) {
+	app.events = bus
+}
+
+// On subscribes the handler to the events of type T on app.Events(), returns the function to unsubscribe.
+func On[T any](app *Application, handler events.Handler[T], opts ...events.Option) (unsubscribe func()) {
+	return events.On(app.Events(), handler, opts...)
+}
+
+// ForwardEvents forwards the events of type T to app.PubSub() on topic <namespace>:events:<name>,
+// so that other services can subscribe to them.
+func ForwardEvents[T any](app *Application) (unsubscribe func()) {
+	return events.Forward[T](app.Events(), events.PubSubSink(app.PubSub(), app.Namespace("events")+":"))
+}
@@ -0,0 +1,121 @@
+package zoox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GraphQLOption is the option of GraphQL route.
+type GraphQLOption struct {
+	// Playground enables the GraphiQL playground in non-debug mode, it is always enabled in debug mode.
+	Playground bool
+	// DisablePlayground disables the GraphiQL playground, even in debug mode.
+	DisablePlayground bool
+	// PersistedQuery returns the query of the persisted query hash (sha256),
+	//	used by GET requests with extensions={"persistedQuery":{"sha256Hash":"..."}} or id=<hash>.
+	PersistedQuery func(hash string) (query string, ok bool)
+	// Subscriptions is the websocket handler of subscriptions, default: the schema handler,
+	//	such as gqlgen transport.Websocket.
+	Subscriptions http.Handler
+	// Middlewares are the middlewares of the route, after the group middlewares.
+	Middlewares []HandlerFunc
+}
+
+// GraphQL defines the method to serve the GraphQL schema handler (graphql-go, gqlgen, etc.) on GET and POST,
+// the group middlewares are applied.
+//
+// Example:
+//
+//	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
+//	api.GraphQL("/graphql", srv)
+func (g *RouterGroup) GraphQL(path string, schemaHandler http.Handler, opts ...func(opt *GraphQLOption)) *RouterGroup {
+	opt := &GraphQLOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	subscriptions := opt.Subscriptions
+	if subscriptions == nil {
+		subscriptions = schemaHandler
+	}
+
+	endpoint := g.prefix + path
+	handler := func(ctx *Context) {
+		if ctx.Method == http.MethodGet {
+			if ctx.IsConnectionUpgrade() {
+				subscriptions.ServeHTTP(ctx.Writer, ctx.Request)
+				return
+			}
+
+			query := ctx.Request.URL.Query()
+			if query.Get("query") == "" {
+				if hash := graphqlPersistedQueryHash(query.Get("extensions"), query.Get("id")); hash != "" && opt.PersistedQuery != nil {
+					if persisted, ok := opt.PersistedQuery(hash); ok {
+						query.Set("query", persisted)
+						ctx.Request.URL.RawQuery = query.Encode()
+					}
+				} else if graphqlPlaygroundEnabled(ctx, opt) && strings.Contains(ctx.Header().Get("Accept"), "text/html") {
+					ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(graphiqlHTML, endpoint)))
+					return
+				}
+			}
+		}
+
+		schemaHandler.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+
+	handlers := append(append([]HandlerFunc{}, opt.Middlewares...), handler)
+	g.Get(path, handlers...)
+	g.Post(path, handlers...)
+
+	return g
+}
+
+func graphqlPlaygroundEnabled(ctx *Context, opt *GraphQLOption) bool {
+	if opt.DisablePlayground {
+		return false
+	}
+
+	return opt.Playground || ctx.Debug().IsDebugMode()
+}
+
+// graphqlPersistedQueryHash returns the hash of automatic persisted query (apollo) or the query id.
+func graphqlPersistedQueryHash(extensions, id string) string {
+	if extensions != "" {
+		var ext struct {
+			PersistedQuery struct {
+				Sha256Hash string `json:"sha256Hash"`
+			} `json:"persistedQuery"`
+		}
+		if err := json.Unmarshal([]byte(extensions), &ext); err == nil && ext.PersistedQuery.Sha256Hash != "" {
+			return ext.PersistedQuery.Sha256Hash
+		}
+	}
+
+	return id
+}
+
+const graphiqlHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <title>GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql/graphiql.min.css" />
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql"></div>
+  <script crossorigin src="https://unpkg.com/react/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql/graphiql.min.js"></script>
+  <script>
+    var url = new URL(%[1]q, window.location.href);
+    var wsUrl = url.href.replace(/^http/, 'ws');
+    var fetcher = GraphiQL.createFetcher({ url: url.href, subscriptionUrl: wsUrl });
+    ReactDOM.render(React.createElement(GraphiQL, { fetcher: fetcher }), document.getElementById('graphiql'));
+  </script>
+</body>
+</html>
+`
@@ -1,18 +1,21 @@
 package zoox
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"path"
+	"regexp"
+	"sync"
 	"time"
 
-	"github.com/go-zoox/core-utils/regexp"
 	"github.com/go-zoox/core-utils/strings"
 	"github.com/go-zoox/fs"
 	"github.com/go-zoox/headers"
 	"github.com/go-zoox/proxy"
+	"github.com/go-zoox/zoox/components/application/jsonrpcclient"
 )
 
 var anyMethods = []string{
@@ -27,13 +30,28 @@ type RouterGroup struct {
 	middlewares []HandlerFunc
 	parent      *RouterGroup
 	app         *Application
+	// entries are the registered middlewares, resolved into middlewares
+	entries []*middlewareEntry
+	// naming is the name of middlewares added by Use, used by default middlewares
+	naming string
+	// notfound and errorHandler override the app-level handlers for the group prefix
+	notfound     HandlerFunc
+	errorHandler ErrorHandlerFunc
+	// strictSlash overrides the trailing slash matching for the group prefix, nil means inherited
+	strictSlash *bool
+	// pattern is the compiled prefix of dynamic matching
+	pattern     *regexp.Regexp
+	patternOnce sync.Once
 }
 
 func newRouterGroup(app *Application, prefix string) *RouterGroup {
-	return &RouterGroup{
+	g := &RouterGroup{
 		app:    app,
 		prefix: prefix,
 	}
+	g.compilePattern()
+
+	return g
 }
 
 // Group defines a new router group
@@ -41,6 +59,8 @@ func (g *RouterGroup) Group(prefix string, cb ...GroupFunc) *RouterGroup {
 	newGroup := newRouterGroup(g.app, g.prefix+prefix)
 	newGroup.parent = g
 	g.app.groups = append(g.app.groups, newGroup)
+	// matched groups are changed
+	g.app.groupMatchCache.Clear()
 
 	for _, fn := range cb {
 		fn(newGroup)
@@ -49,6 +69,33 @@ func (g *RouterGroup) Group(prefix string, cb ...GroupFunc) *RouterGroup {
 	return newGroup
 }
 
+// NotFound sets the 404 handler of the group prefix, overriding the app-level handler.
+//
+// Example:
+//
+//	api := app.Group("/api")
+//	api.NotFound(func(ctx *zoox.Context) {
+//		ctx.JSON(404, zoox.H{"message": "not found"})
+//	})
+func (g *RouterGroup) NotFound(h HandlerFunc) {
+	g.notfound = h
+}
+
+// OnError sets the error handler of the group prefix, overriding the app-level handler.
+func (g *RouterGroup) OnError(h ErrorHandlerFunc) {
+	g.errorHandler = h
+}
+
+// hasPathPrefix returns true if the path is under the group prefix by path segment.
+func (g *RouterGroup) hasPathPrefix(path string) bool {
+	prefix := strings.TrimSuffix(g.prefix, "/")
+	if prefix == "" {
+		return true
+	}
+
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
 func (g *RouterGroup) matchPath(path string) (ok bool) {
 	// /v1 	=> /v1
 	// /v1/ => /v1
@@ -56,24 +103,52 @@ func (g *RouterGroup) matchPath(path string) (ok bool) {
 		return ok
 	}
 
-	// @TODO /v1/containers/123456/terminal => /v1/containers/:id
-	re := g.prefix
-	if strings.Contains(re, ":") {
-		re = strings.ReplaceAllFunc(re, ":\\w+", func(b []byte) []byte {
-			return []byte("\\w+")
-		})
-	} else if strings.Contains(re, "{") {
-		re = strings.ReplaceAllFunc(re, "{.*}", func(b []byte) []byte {
-			return []byte("\\w+")
-		})
+	// /v1/containers/123456/terminal => /v1/containers/:id
+	g.compilePattern()
+	if g.pattern == nil {
+		return false
 	}
 
-	return regexp.Match(re, path)
+	return g.pattern.MatchString(path)
+}
+
+// compilePattern compiles the prefix once, it is invalid pattern if nil.
+func (g *RouterGroup) compilePattern() {
+	g.patternOnce.Do(func() {
+		re := g.prefix
+		if strings.Contains(re, ":") {
+			// constrained params, such as :id<uuid>
+			re = strings.ReplaceAllFunc(re, ":\\w+<[^/]*>", func(b []byte) []byte {
+				return []byte("[^/]+")
+			})
+			re = strings.ReplaceAllFunc(re, ":\\w+", func(b []byte) []byte {
+				return []byte("\\w+")
+			})
+		} else if strings.Contains(re, "{") {
+			re = strings.ReplaceAllFunc(re, "{.*}", func(b []byte) []byte {
+				return []byte("\\w+")
+			})
+		}
+
+		if pattern, err := regexp.Compile(re); err == nil {
+			g.pattern = pattern
+		}
+	})
 }
 
 func (g *RouterGroup) addRoute(method string, path string, handler ...HandlerFunc) {
+	g.app.router.addRoute(method, g.routePath(path), handler...)
+}
+
+// routePath returns the full path of the route in the group,
+// the trailing slash is kept for the strict slash and the trailing slash redirect, except the root of group.
+func (g *RouterGroup) routePath(path string) string {
 	pathX := fs.JoinPath(g.prefix, path)
-	g.app.router.addRoute(method, pathX, handler...)
+	if path != "/" && strings.HasSuffix(path, "/") && !strings.HasSuffix(pathX, "/") {
+		pathX += "/"
+	}
+
+	return pathX
 }
 
 // Get defines the method to add GET request
@@ -132,6 +207,15 @@ func (g *RouterGroup) Any(path string, handler ...HandlerFunc) *RouterGroup {
 	return g
 }
 
+// Route defines the method (such as http.MethodGet) to add the request as g.Get, g.Post, ...,
+// and returns the route to set the route options, such as:
+//
+//	g.Route(http.MethodGet, "/users/:id", getUser).BindModel("id", &User{})
+func (g *RouterGroup) Route(method string, path string, handler ...HandlerFunc) *Route {
+	g.addRoute(method, path, handler...)
+	return g.newRoute(path, method)
+}
+
 // ProxyConfig defines the proxy config
 type ProxyConfig struct {
 	// internal proxy config
@@ -155,19 +239,27 @@ type ProxyConfig struct {
 //	    {From: "/api/v1/tasks/(.*)", To: "/$1"},
 //	  }
 //	}))
+//
+//	// service discovery, see app.RegisterService
+//	app.Proxy("/api/v1/orders", "discovery://orders")
 func (g *RouterGroup) Proxy(path, target string, options ...func(cfg *ProxyConfig)) *RouterGroup {
 	cfg := &ProxyConfig{}
 	for _, option := range options {
 		option(cfg)
 	}
 
-	handler := WrapH(proxy.NewSingleHost(target, &cfg.SingleHostConfig))
+	var handler HandlerFunc
+	if _, _, _, ok := parseDiscoveryTarget(target); ok {
+		handler = g.app.discoveryProxy(target, cfg)
+	} else {
+		handler = WrapH(proxy.NewSingleHost(target, &cfg.SingleHostConfig))
+	}
 
 	g.Use(func(ctx *Context) {
 		if strings.StartsWith(ctx.Path, path) {
 			if cfg.OnRequestWithContext != nil {
 				if err := cfg.OnRequestWithContext(ctx); err != nil {
-					ctx.Logger.Errorf("proxy error: %s", err)
+					ctx.RequestLogger().Errorf("proxy error: %s", err)
 					ctx.Fail(err, 500, "proxy on request with context error")
 					return
 				}
@@ -177,7 +269,7 @@ func (g *RouterGroup) Proxy(path, target string, options ...func(cfg *ProxyConfi
 
 			if cfg.OnResponseWithContext != nil {
 				if err := cfg.OnResponseWithContext(ctx); err != nil {
-					ctx.Logger.Errorf("proxy error: %s", err)
+					ctx.RequestLogger().Errorf("proxy error: %s", err)
 					ctx.Fail(err, 500, "proxy on response with context error")
 					return
 				}
@@ -203,7 +295,15 @@ func (g *RouterGroup) JSONRPC(path string, handler JSONRPCHandlerFunc) *RouterGr
 		}
 		defer ctx.Request.Body.Close()
 
-		response, err := ctx.App.JSONRPCRegistry().Invoke(ctx.Context(), request)
+		// the deadline of the client call, see jsonrpcclient.TimeoutHeader
+		c := ctx.Context()
+		if timeout, ok := jsonrpcclient.ParseTimeout(ctx.Request.Header); ok {
+			var cancel context.CancelFunc
+			c, cancel = context.WithTimeout(c, timeout)
+			defer cancel()
+		}
+
+		response, err := ctx.App.JSONRPCRegistry().Invoke(c, request)
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, err.Error())
 			return
@@ -218,7 +318,17 @@ func (g *RouterGroup) JSONRPC(path string, handler JSONRPCHandlerFunc) *RouterGr
 
 // Use adds a middleware to the group
 func (g *RouterGroup) Use(middlewares ...HandlerFunc) {
-	g.middlewares = append(g.middlewares, middlewares...)
+	for i, middleware := range middlewares {
+		name := g.naming
+		if name != "" && i > 0 {
+			name = fmt.Sprintf("%s#%d", g.naming, i)
+		}
+
+		g.addMiddlewareEntry(&middlewareEntry{
+			name:    name,
+			handler: middleware,
+		})
+	}
 }
 
 func (g *RouterGroup) createStaticHandler(absolutePath string, fs http.FileSystem) HandlerFunc {
@@ -340,9 +450,12 @@ type StaticOptions struct {
 	MaxAge       time.Duration
 	Index        bool
 	Suffix       string
+	// RateLimit limits the throughput of each response in bytes per second, 0 means no limit.
+	RateLimit int64
 }
 
-// Static defines the method to serve static files
+// Static defines the method to serve static files,
+// Range (single and multi-range) and If-Range requests are supported for resumable downloads.
 func (g *RouterGroup) Static(basePath string, rootDir string, options ...*StaticOptions) {
 	var opts *StaticOptions
 	if len(options) > 0 {
@@ -382,7 +495,11 @@ func (g *RouterGroup) Static(basePath string, rootDir string, options ...*Static
 			}
 
 			if opts.MaxAge > 0 {
-				ctx.Set(headers.CacheControl, fmt.Sprintf("max-age=%d", int64(opts.MaxAge.Seconds())))
+				ctx.SetHeader(headers.CacheControl, fmt.Sprintf("max-age=%d", int64(opts.MaxAge.Seconds())))
+			}
+
+			if opts.RateLimit > 0 {
+				ctx.Writer = newThrottledWriter(ctx.Writer, opts.RateLimit, ctx.Request.Context().Done())
 			}
 		}
 
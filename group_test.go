@@ -1,6 +1,13 @@
 package zoox
 
-import "testing"
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestGroupMatchPath(t *testing.T) {
 	testcases := []map[string]any{
@@ -36,3 +43,101 @@ func TestGroupMatchPath(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupMatchCacheInvalidation(t *testing.T) {
+	var calls []string
+	app := New()
+	app.Get("/api/users", func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+
+	serve := func() {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	}
+
+	serve()
+	assert.Equal(t, []string{"handler"}, calls)
+
+	// the matched groups of the served path are cached, the group added later must be matched
+	api := app.Group("/api")
+	api.Use(func(ctx *Context) {
+		calls = append(calls, "api")
+		ctx.Next()
+	})
+
+	calls = nil
+	serve()
+	assert.Equal(t, []string{"api", "handler"}, calls)
+
+	// the middlewares added later to the matched group
+	api.Use(func(ctx *Context) {
+		calls = append(calls, "api2")
+		ctx.Next()
+	})
+
+	calls = nil
+	serve()
+	assert.Equal(t, []string{"api", "api2", "handler"}, calls)
+}
+
+func TestGroupMiddlewaresWithoutCache(t *testing.T) {
+	var calls []string
+	app := New()
+	app.Use(func(ctx *Context) {
+		calls = append(calls, "app")
+		ctx.Next()
+	})
+
+	// more groups than the bits of the cache mask
+	for i := 0; i < 70; i++ {
+		name := fmt.Sprintf("g%02d", i)
+		app.Group("/" + name).Use(func(ctx *Context) {
+			calls = append(calls, name)
+			ctx.Next()
+		})
+	}
+	assert.Greater(t, len(app.groups), 64)
+
+	app.Get("/g69/users", func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+	app.Get("/g01/users", func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+
+	for _, c := range []struct {
+		path     string
+		expected []string
+	}{
+		{"/g69/users", []string{"app", "g69", "handler"}},
+		{"/g01/users", []string{"app", "g01", "handler"}},
+		{"/g69/users", []string{"app", "g69", "handler"}},
+	} {
+		calls = nil
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		assert.Equal(t, c.expected, calls, c.path)
+	}
+
+	// the paths are not cached
+	_, ok := app.groupMatchCache.Get("/g69/users")
+	assert.False(t, ok)
+}
+
+func TestGroupRouteMethodsChain(t *testing.T) {
+	ok := func(ctx *Context) {
+		ctx.String(http.StatusOK, ctx.Method)
+	}
+
+	app := New()
+	var api *RouterGroup = app.Group("/api").Get("/users", ok).Post("/users", ok)
+	api.Route(http.MethodPut, "/users", ok).Example(nil, "PUT")
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut} {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(method, "/api/users", nil))
+		assert.Equal(t, method, recorder.Body.String())
+	}
+
+	assert.Equal(t, "PUT", app.examples["PUT /api/users"].Response)
+}
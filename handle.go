@@ -0,0 +1,131 @@
+package zoox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-zoox/headers"
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is implemented by the request types of Handle, called after binding.
+type Validator interface {
+	Validate() error
+}
+
+// ErrBind is the error of binding the request in Handle.
+var ErrBind = errors.New("failed to bind request")
+
+// Handle creates a typed handler, which binds the params, query, header and body into Req,
+// validates it (if Req implements Validator), invokes fn and encodes the Res.
+//
+// The sources of a field are declared by the tags param, query, header and the body tags (json, yaml, form),
+// or by the combined tag `in:"path=id"` (path, query, header, form).
+// A field bound from multiple sources takes the value of the highest precedence:
+// path > query > header > body.
+//
+// Example:
+//
+//	type UpdateUserRequest struct {
+//		ID     string `in:"path=id"`
+//		Fields string `query:"fields"`
+//		Token  string `header:"X-Token"`
+//		Name   string `json:"name"`
+//	}
+//
+//	app.Get("/users/:id", zoox.Handle(func(ctx *zoox.Context, req *GetUserRequest) (*User, error) {
+//		return findUser(req.ID)
+//	}))
+//
+// Errors implementing HTTPError are written with FailWithError,
+// binding and validation errors are 400, others are 500.
+func Handle[Req any, Res any](fn func(ctx *Context, req Req) (Res, error)) HandlerFunc {
+	return func(ctx *Context) {
+		req, err := bindRequest[Req](ctx)
+		if err != nil {
+			var httpErr HTTPError
+			if !errors.As(err, &httpErr) {
+				err = NewHTTPError(http.StatusBadRequest, err.Error(), err)
+			}
+
+			ctx.HandleError(err)
+			return
+		}
+
+		res, err := fn(ctx, req)
+		if err != nil {
+			ctx.HandleError(err)
+			return
+		}
+
+		ctx.encodeResponse(res)
+	}
+}
+
+// bindRequest creates the Req and binds the request into it.
+func bindRequest[Req any](ctx *Context) (req Req, err error) {
+	// Req can be a struct or a pointer to struct
+	var target any
+	typ := reflect.TypeOf((*Req)(nil)).Elem()
+	if typ.Kind() == reflect.Ptr {
+		v := reflect.New(typ.Elem())
+		reflect.ValueOf(&req).Elem().Set(v)
+		target = v.Interface()
+	} else {
+		target = &req
+	}
+
+	// bound from the lowest precedence, so the higher ones override: body < header < query < path
+	if ctx.Request.Body != nil && ctx.Request.ContentLength != 0 && ctx.ContentType() != "" {
+		if err := ctx.Bind(target); err != nil {
+			return req, fmt.Errorf("%w: body: %v", ErrBind, err)
+		}
+	}
+
+	if reflect.TypeOf(target).Elem().Kind() == reflect.Struct {
+		if err := ctx.BindHeader(target); err != nil {
+			return req, fmt.Errorf("%w: header: %v", ErrBind, err)
+		}
+
+		if err := ctx.BindQuery(target); err != nil {
+			return req, fmt.Errorf("%w: query: %v", ErrBind, err)
+		}
+
+		if err := ctx.BindParams(target); err != nil {
+			return req, fmt.Errorf("%w: params: %v", ErrBind, err)
+		}
+	}
+
+	if v, ok := target.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+// encodeResponse writes the result of typed handler with the negotiated format.
+func (ctx *Context) encodeResponse(res any) {
+	status := ctx.StatusCode()
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// json is preferred on ties and is the fallback if neither is acceptable
+	ctx.SetHeader(headers.Vary, headers.Accept)
+	if ctx.Negotiate(MIMEJSON, MIMEYAML) == MIMEYAML {
+		data, err := yaml.Marshal(res)
+		if err != nil {
+			ctx.Fail(err, http.StatusInternalServerError, "failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+		ctx.Data(status, MIMEYAML, data)
+		return
+	}
+
+	ctx.JSON(status, res)
+}
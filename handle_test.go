@@ -0,0 +1,180 @@
+package zoox
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type handleResponse struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+func TestHandleNegotiatesResponse(t *testing.T) {
+	app := New()
+	app.Get("/", Handle(func(ctx *Context, req *struct{}) (*handleResponse, error) {
+		return &handleResponse{Name: "zero"}, nil
+	}))
+
+	cases := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", MIMEJSON, `{"name":"zero"}`},
+		{"*/*", MIMEJSON, `{"name":"zero"}`},
+		{"application/yaml", MIMEYAML, "name: zero\n"},
+		{"application/json;q=0.5, application/yaml", MIMEYAML, "name: zero\n"},
+		{"application/yaml;q=0.5, application/json", MIMEJSON, `{"name":"zero"}`},
+		// not a yaml media type, only contains the word
+		{"application/x-notyaml", MIMEJSON, `{"name":"zero"}`},
+		{"application/yaml;q=0", MIMEJSON, `{"name":"zero"}`},
+		{"text/html", MIMEJSON, `{"name":"zero"}`},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code, c.accept)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), c.contentType, c.accept)
+		assert.Equal(t, "Accept", recorder.Header().Get("Vary"), c.accept)
+		if c.contentType == MIMEJSON {
+			assert.JSONEq(t, c.body, recorder.Body.String(), c.accept)
+		} else {
+			assert.Equal(t, c.body, recorder.Body.String(), c.accept)
+		}
+	}
+}
+
+type handleBindRequest struct {
+	ID     int    `in:"path=id" json:"id"`
+	Fields string `query:"fields" header:"X-Fields" json:"fields"`
+	Token  string `header:"X-Token" json:"token"`
+	Name   string `json:"name"`
+}
+
+func TestHandleBindsSourcesByPrecedence(t *testing.T) {
+	app := New()
+	app.Post("/users/:id", Handle(func(ctx *Context, req *handleBindRequest) (*handleBindRequest, error) {
+		return req, nil
+	}))
+
+	cases := []struct {
+		name     string
+		path     string
+		header   map[string]string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "path wins over query, header and body",
+			path:     "/users/1?id=2",
+			header:   map[string]string{"Id": "3"},
+			body:     `{"id":4,"name":"zero"}`,
+			status:   http.StatusOK,
+			expected: `{"id":1,"fields":"","token":"","name":"zero"}`,
+		},
+		{
+			name:     "query wins over header and body",
+			path:     "/users/1?fields=query",
+			header:   map[string]string{"X-Fields": "header"},
+			body:     `{"fields":"body"}`,
+			status:   http.StatusOK,
+			expected: `{"id":1,"fields":"query","token":"","name":""}`,
+		},
+		{
+			name:     "header wins over body",
+			path:     "/users/1",
+			header:   map[string]string{"X-Fields": "header", "X-Token": "header"},
+			body:     `{"fields":"body","token":"body"}`,
+			status:   http.StatusOK,
+			expected: `{"id":1,"fields":"header","token":"header","name":""}`,
+		},
+		{
+			name:     "body only",
+			path:     "/users/1",
+			body:     `{"fields":"body","token":"body"}`,
+			status:   http.StatusOK,
+			expected: `{"id":1,"fields":"body","token":"body","name":""}`,
+		},
+		{
+			name:   "invalid path value",
+			path:   "/users/abc",
+			body:   `{"name":"zero"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid body",
+			path:   "/users/1",
+			body:   `{"id":`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+		if c.expected != "" {
+			assert.JSONEq(t, c.expected, recorder.Body.String(), c.name)
+		}
+	}
+}
+
+type handlePlan string
+
+func TestHandleRegisteredBinder(t *testing.T) {
+	RegisterBinder(func(raw string) (handlePlan, error) {
+		switch raw {
+		case "free", "pro":
+			return handlePlan(raw), nil
+		}
+
+		return "", fmt.Errorf("invalid plan: %s", raw)
+	})
+
+	type request struct {
+		Plan handlePlan `json:"plan" query:"plan"`
+	}
+
+	app := New()
+	app.Post("/plans", Handle(func(ctx *Context, req *request) (*request, error) {
+		return req, nil
+	}))
+
+	cases := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"query", "/plans?plan=pro", "", http.StatusOK},
+		{"json", "/plans", `{"plan":"free"}`, http.StatusOK},
+		{"invalid query", "/plans?plan=enterprise", "", http.StatusBadRequest},
+		{"invalid json", "/plans", `{"plan":"enterprise"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+		if c.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+	}
+}
@@ -0,0 +1,98 @@
+package zoox
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseInfo is the final state of the request, passed to OnResponse hooks.
+type ResponseInfo struct {
+	Status int
+	// Size is the bytes written into the response body.
+	Size      int
+	StartedAt time.Time
+	Duration  time.Duration
+	// Panicked is true if the handler chain panicked.
+	Panicked bool
+}
+
+type requestHooks struct {
+	request  []func(ctx *Context)
+	response []func(ctx *Context, info *ResponseInfo)
+	panic    []func(ctx *Context, err any)
+}
+
+func (h *requestHooks) empty() bool {
+	return len(h.request) == 0 && len(h.response) == 0 && len(h.panic) == 0
+}
+
+// OnRequest adds the hook running before routing and middlewares, such as counting in-flight requests,
+// the request is not routed if the hook writes the response.
+// Hooks must be added before the app is running.
+func (app *Application) OnRequest(fn func(ctx *Context)) {
+	app.hooks.request = append(app.hooks.request, fn)
+}
+
+// OnResponse adds the hook running after the response is fully written, with the final status, size and duration,
+// it runs even if the handler chain panicked, such as global metrics and access logs.
+// Hooks must be added before the app is running.
+func (app *Application) OnResponse(fn func(ctx *Context, info *ResponseInfo)) {
+	app.hooks.response = append(app.hooks.response, fn)
+}
+
+// OnPanic adds the hook running on panics of the handler chain,
+// including the panics recovered by the Recovery middleware,
+// the panics escaping the chain are not recovered by the hooks, same as without hooks.
+// Hooks must be added before the app is running.
+func (app *Application) OnPanic(fn func(ctx *Context, err any)) {
+	app.hooks.panic = append(app.hooks.panic, fn)
+}
+
+// ReportPanic runs the OnPanic hooks and reports the panic to the error reporter (config error_report),
+// it is called by middlewares recovering panics.
+func (ctx *Context) ReportPanic(err any) {
+	ctx.panicked = true
+	ctx.reportPanic(err)
+	for _, fn := range ctx.App.hooks.panic {
+		fn(ctx, err)
+	}
+}
+
+// runRequestHooks runs the OnRequest hooks, returns false if a hook wrote the response.
+func (app *Application) runRequestHooks(ctx *Context) bool {
+	for _, fn := range app.hooks.request {
+		fn(ctx)
+	}
+
+	return !ctx.Writer.Written()
+}
+
+// finishHooks runs the OnPanic and OnResponse hooks, it is deferred by ServeHTTP if any hook is added.
+// The panics are not recovered, they are handled as without hooks (by net/http, or the Recovery middleware before).
+func (app *Application) finishHooks(ctx *Context, startedAt time.Time) {
+	err := recover()
+	if err != nil && err != http.ErrAbortHandler {
+		ctx.ReportPanic(err)
+	}
+
+	info := &ResponseInfo{
+		Status:    ctx.Writer.Status(),
+		StartedAt: startedAt,
+		Duration:  time.Since(startedAt),
+		Panicked:  ctx.panicked || err != nil,
+	}
+	if ctx.Writer.Written() {
+		info.Size = ctx.Writer.Size()
+	} else if err != nil {
+		// the connection is closed without response
+		info.Status = http.StatusInternalServerError
+	}
+
+	for _, fn := range app.hooks.response {
+		fn(ctx, info)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
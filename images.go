@@ -0,0 +1,76 @@
+package zoox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox/components/application/images"
+)
+
+// Images returns the images processing the images on the fly, with the derived images cached in the app cache.
+func (app *Application) Images() images.Images {
+	app.once.images.Do(func() {
+		if app.images != nil {
+			return
+		}
+
+		app.images = images.New(&images.Config{
+			Cache:  app.tieredCache(),
+			Prefix: app.Namespace("images") + ":",
+		})
+	})
+
+	return app.images
+}
+
+// SetImages sets the images.
+func (app *Application) SetImages(i images.Images) {
+	app.images = i
+}
+
+// SendImage sends the image file transformed on the fly, the derived image is cached by the file version (modtime and size).
+//
+//	app.Get("/avatars/:id", func(ctx *zoox.Context) {
+//		ctx.SendImage(path.Join(dir, ctx.Param().Get("id").String()+".png"), images.Resize(300, 0), images.Format("webp"))
+//	})
+func (ctx *Context) SendImage(path string, opts ...images.Option) {
+	stat, err := os.Stat(path)
+	if err != nil || stat.IsDir() {
+		ctx.Fail(fmt.Errorf("image %s not found", path), http.StatusNotFound, "image not found", http.StatusNotFound)
+		return
+	}
+
+	source := fmt.Sprintf("%s@%d-%d", path, stat.ModTime().UnixNano(), stat.Size())
+	img, err := ctx.App.Images().Get(source, func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}, opts...)
+	if err != nil {
+		ctx.Fail(err, http.StatusInternalServerError, "failed to process image", http.StatusInternalServerError)
+		return
+	}
+
+	ctx.SetHeader(headers.ContentType, img.ContentType)
+	ctx.SetHeader("ETag", fmt.Sprintf(`"%s"`, images.Key(source, opts...)))
+	ctx.ServeContent("", stat.ModTime(), bytes.NewReader(img.Data))
+}
+
+// DeriveImages generates the variants of the uploaded image (form file of the key), such as thumbnails,
+// the metadata (such as EXIF) of the variants is stripped.
+//
+//	variants, err := ctx.DeriveImages("avatar", map[string][]images.Option{
+//		"thumbnail": {images.Thumbnail(200, 200), images.Format("jpeg")},
+//		"original":  {images.Resize(2048, 2048)},
+//	})
+func (ctx *Context) DeriveImages(key string, variants map[string][]images.Option) (map[string]*images.Image, error) {
+	f, _, err := ctx.File(key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return images.Derive(f, variants)
+}
@@ -0,0 +1,37 @@
+package zoox
+
+import (
+	"github.com/go-zoox/random"
+)
+
+// NewIsolated creates the app sharing no global state with other apps, for parallel test suites:
+//   - the default middlewares and groups (RegisterDefault) are not applied
+//   - the config is not read from the environment variables, so the cache, pubsub and other components are in memory
+//   - the secret key is generated per app instead of DefaultSecretKey
+//
+// The components (cache, jobqueue, cron, ...) are created per app as usual.
+//
+// Example:
+//
+//	func TestAPI(t *testing.T) {
+//		t.Parallel()
+//
+//		app := zoox.NewIsolated(zoox.WithName("test"))
+//		app.Get("/", handler)
+//		...
+//	}
+func NewIsolated(opts ...Option) *Application {
+	return New(append([]Option{WithoutDefaults(), isolated()}, opts...)...)
+}
+
+func isolated() Option {
+	return func(app *Application) {
+		app.isolated = true
+		app.Config.SecretKey = random.String(32)
+	}
+}
+
+// IsIsolated returns true if the app is created by NewIsolated.
+func (app *Application) IsIsolated() bool {
+	return app.isolated
+}
@@ -0,0 +1,112 @@
+package zoox
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONCodec is the json implementation, which can be replaced by faster libraries, such as sonic or go-json.
+//
+// Example:
+//
+//	app.SetJSONCodec(mySonicCodec{})
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONEncoder is the streaming json encoder.
+type JSONEncoder interface {
+	Encode(v any) error
+	SetIndent(prefix, indent string)
+	SetEscapeHTML(on bool)
+}
+
+// JSONDecoder is the streaming json decoder.
+type JSONDecoder interface {
+	Decode(v any) error
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// JSONOption is the option of ctx.JSON.
+type JSONOption struct {
+	// Indent enables the pretty output.
+	Indent bool
+	// EscapeHTML escapes <, > and & in strings, default: true unless app.Config.JSON.DisableHTMLEscape.
+	EscapeHTML bool
+}
+
+// StdJSONCodec is the JSONCodec of encoding/json.
+type StdJSONCodec struct{}
+
+// Marshal implements JSONCodec.
+func (StdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements JSONCodec.
+func (StdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewEncoder implements JSONCodec.
+func (StdJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder implements JSONCodec.
+func (StdJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+// jsonBufferPool reuses the encoding buffers of ctx.JSON.
+var jsonBufferPool = sync.Pool{
+	New: func() any {
+		return &bytes.Buffer{}
+	},
+}
+
+// maxPooledJSONBufferSize avoids keeping huge buffers in pool.
+const maxPooledJSONBufferSize = 64 * 1024
+
+func getJSONBuffer() *bytes.Buffer {
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putJSONBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledJSONBufferSize {
+		return
+	}
+
+	jsonBufferPool.Put(buf)
+}
+
+// SetJSONCodec sets the json codec used by ctx.JSON, ctx.BindJSON and so on.
+func (app *Application) SetJSONCodec(codec JSONCodec) {
+	app.jsonCodec = codec
+}
+
+// JSONCodec returns the json codec, default: encoding/json.
+func (app *Application) JSONCodec() JSONCodec {
+	if app.jsonCodec == nil {
+		return StdJSONCodec{}
+	}
+
+	return app.jsonCodec
+}
+
+// jsonCodec returns the json codec of the app.
+func (ctx *Context) jsonCodec() JSONCodec {
+	if ctx.App == nil {
+		return StdJSONCodec{}
+	}
+
+	return ctx.App.JSONCodec()
+}
@@ -0,0 +1,54 @@
+package zoox
+
+import (
+	"context"
+
+	"github.com/go-zoox/zoox/components/application/jsonrpcclient"
+	"github.com/go-zoox/zoox/utils"
+)
+
+// JSONRPCClient returns the client of the zoox JSON-RPC service, the clients are reused by url,
+// so the connections are pooled. The url discovery://<name>/<path> picks the endpoint of each call
+// from the service registered by app.RegisterService before.
+//
+// Example:
+//
+//	var sum int
+//	err := ctx.App.JSONRPCClient("http://calc.internal/rpc").Call(ctx.OutgoingContext(), "Add", params, &sum)
+func (app *Application) JSONRPCClient(url string, cfg ...*jsonrpcclient.Config) *jsonrpcclient.Client {
+	if client, ok := app.jsonrpcClients.Load(url); ok {
+		return client.(*jsonrpcclient.Client)
+	}
+
+	target, cfgX := app.discoveryJSONRPCConfig(url, cfg...)
+	client, _ := app.jsonrpcClients.LoadOrStore(url, jsonrpcclient.New(target, cfgX...))
+	return client.(*jsonrpcclient.Client)
+}
+
+// DescribeJSONRPC describes the params and result types of the registered method,
+// for generating the typed client with app.GenerateJSONRPCClient.
+//
+// Example:
+//
+//	app.DescribeJSONRPC("Add", AddParams{}, 0)
+func (app *Application) DescribeJSONRPC(method string, params any, result any) {
+	app.jsonrpcMethods = append(app.jsonrpcMethods, jsonrpcclient.Method{
+		Name:   method,
+		Params: params,
+		Result: result,
+	})
+}
+
+// GenerateJSONRPCClient generates the typed client code of the methods described by app.DescribeJSONRPC.
+func (app *Application) GenerateJSONRPCClient(cfg *jsonrpcclient.GenerateConfig) ([]byte, error) {
+	return jsonrpcclient.Generate(cfg, app.jsonrpcMethods...)
+}
+
+// OutgoingContext returns the context of the request for the outbound calls, such as app.JSONRPCClient,
+// the trace context (traceparent, tracestate, baggage) and request id are propagated.
+func (ctx *Context) OutgoingContext() context.Context {
+	header := ctx.Request.Header.Clone()
+	header.Set(utils.RequestIDHeader, ctx.RequestID())
+
+	return jsonrpcclient.WithPropagation(ctx.Context(), header)
+}
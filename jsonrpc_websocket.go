@@ -0,0 +1,192 @@
+package zoox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-zoox/zoox/components/application/wslimit"
+	"github.com/gorilla/websocket"
+)
+
+// Subprotocols of JSONRPCWebSocket.
+const (
+	// JSONRPCSubprotocolJSON is the default json text frames.
+	JSONRPCSubprotocolJSON = "jsonrpc.json"
+	// JSONRPCSubprotocolMsgpack is the msgpack binary frames.
+	JSONRPCSubprotocolMsgpack = "jsonrpc.msgpack"
+	// JSONRPCSubprotocolCBOR is the cbor binary frames, enabled by app.SetCBORCodec.
+	JSONRPCSubprotocolCBOR = "jsonrpc.cbor"
+)
+
+// DefaultJSONRPCWebSocketConcurrency is the default max concurrent calls of one connection.
+const DefaultJSONRPCWebSocketConcurrency = 16
+
+// DefaultJSONRPCWebSocketRateLimit is the default inbound message limit of one connection,
+// the messages over the limit are dropped.
+var DefaultJSONRPCWebSocketRateLimit = &wslimit.Config{
+	MessagesPerSecond: 100,
+	Burst:             2,
+}
+
+// JSONRPCWebSocketOption is the option of JSONRPCWebSocket.
+type JSONRPCWebSocketOption struct {
+	// RateLimit limits the inbound messages of each connection, such as 1008 close for abusive clients,
+	//	default: DefaultJSONRPCWebSocketRateLimit, &wslimit.Config{} disables the limit.
+	RateLimit *wslimit.Config
+	// Concurrency is the max concurrent calls of each connection, default: 16.
+	//	The connection is not read until a call completes, so the client is slowed down.
+	//	1 invokes the calls one by one, so the responses are in the request order.
+	Concurrency int
+}
+
+// JSONRPCWebSocket defines the jsonrpc route over websocket, the messages are invoked by app.JSONRPCRegistry().
+//
+// The encoding is negotiated by the websocket subprotocol (Sec-WebSocket-Protocol),
+// msgpack and cbor (if its codec is set) are preferred, for high-frequency calls like terminals and games:
+//
+//	jsonrpc.msgpack  binary frames, github.com/vmihailenco/msgpack/v5 or app.SetMsgpackCodec
+//	jsonrpc.cbor     binary frames, app.SetCBORCodec
+//	jsonrpc.json     text frames (default, also used without subprotocol)
+//
+// The binary payloads are translated to json for the registry, and the responses are encoded back.
+//
+// The calls of one connection run concurrently (see JSONRPCWebSocketOption.Concurrency),
+// the responses are written as the calls complete, not in the request order, match them by id.
+func (g *RouterGroup) JSONRPCWebSocket(path string, handler JSONRPCHandlerFunc, opts ...func(opt *JSONRPCWebSocketOption)) *RouterGroup {
+	opt := &JSONRPCWebSocketOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+	if opt.RateLimit == nil {
+		opt.RateLimit = DefaultJSONRPCWebSocketRateLimit
+	}
+	if opt.Concurrency <= 0 {
+		opt.Concurrency = DefaultJSONRPCWebSocketConcurrency
+	}
+
+	handler(g.app.JSONRPCRegistry())
+
+	g.addRoute(http.MethodGet, path, func(ctx *Context) {
+		codecs := map[string]Codec{}
+		subprotocols := []string{}
+		codecs[JSONRPCSubprotocolMsgpack] = ctx.App.MsgpackCodec()
+		subprotocols = append(subprotocols, JSONRPCSubprotocolMsgpack)
+		if codec := ctx.App.CBORCodec(); codec != nil {
+			codecs[JSONRPCSubprotocolCBOR] = codec
+			subprotocols = append(subprotocols, JSONRPCSubprotocolCBOR)
+		}
+		subprotocols = append(subprotocols, JSONRPCSubprotocolJSON)
+
+		// the origin is checked to be the same host by default
+		upgrader := &websocket.Upgrader{
+			Subprotocols: subprotocols,
+		}
+
+		conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+		if err != nil {
+			ctx.RequestLogger().Errorf("[jsonrpc][websocket] failed to upgrade: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		codec := codecs[conn.Subprotocol()]
+		reader := wslimit.Wrap(conn, opt.RateLimit)
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		// bounds the concurrent calls, the next message is read when a slot is released
+		slots := make(chan struct{}, opt.Concurrency)
+
+		for {
+			typ, message, err := reader.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+
+				response, err := invokeJSONRPCFrame(ctx, codec, message)
+				if err != nil {
+					ctx.RequestLogger().Errorf("[jsonrpc][websocket] failed to invoke: %s", err)
+					return
+				}
+
+				// notifications have no response
+				if len(response) == 0 {
+					return
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err := conn.WriteMessage(typ, response); err != nil {
+					ctx.RequestLogger().Errorf("[jsonrpc][websocket] failed to write: %s", err)
+				}
+			}()
+		}
+	})
+
+	return g
+}
+
+// invokeJSONRPCFrame invokes the frame, which is json, or encoded by the codec of subprotocol.
+func invokeJSONRPCFrame(ctx *Context, codec Codec, message []byte) ([]byte, error) {
+	if codec == nil {
+		return ctx.App.JSONRPCRegistry().Invoke(ctx.Context(), message)
+	}
+
+	var request any
+	if err := codec.Unmarshal(message, &request); err != nil {
+		return nil, fmt.Errorf("failed to decode request: %v", err)
+	}
+
+	data, err := json.Marshal(normalizeCodecValue(request))
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate request: %v", err)
+	}
+
+	response, err := ctx.App.JSONRPCRegistry().Invoke(ctx.Context(), data)
+	if err != nil || len(response) == 0 {
+		return response, err
+	}
+
+	var value any
+	if err := json.Unmarshal(response, &value); err != nil {
+		return nil, fmt.Errorf("failed to translate response: %v", err)
+	}
+
+	return codec.Marshal(value)
+}
+
+// normalizeCodecValue converts map[any]any decoded by msgpack or cbor into map[string]any for json.
+func normalizeCodecValue(v any) any {
+	switch x := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for key, value := range x {
+			m[fmt.Sprint(key)] = normalizeCodecValue(value)
+		}
+		return m
+	case map[string]any:
+		for key, value := range x {
+			x[key] = normalizeCodecValue(value)
+		}
+		return x
+	case []any:
+		for i, value := range x {
+			x[i] = normalizeCodecValue(value)
+		}
+		return x
+	}
+
+	return v
+}
@@ -0,0 +1,148 @@
+package zoox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-zoox/cookie"
+	"github.com/go-zoox/zoox/components/application/keyring"
+)
+
+// ErrKeyringNotConfigured is returned by app.Keyring if neither config encryption.keys nor secret_key is set,
+// the random DefaultSecretKey is not used, the other instances and restarts could not decrypt or verify.
+var ErrKeyringNotConfigured = errors.New("keyring requires config encryption.keys or secret_key")
+
+// Keyring returns the keyring encrypting the sessions and cache namespaces at rest and signing the urls,
+// with the keys of config encryption.keys (resolved by the secrets), defaults to secret_key.
+//
+//	// config:
+//	// encryption:
+//	//   keys: [secret://vault/app#key_2024_06, secret://vault/app#key_2024_01]
+//	//   session: true
+//	//   cache_namespaces: ["user:", "token:"]
+func (app *Application) Keyring() (keyring.Keyring, error) {
+	app.keyringMu.Lock()
+	defer app.keyringMu.Unlock()
+
+	// the errors are not cached, the config may be applied later, such as by app.Run
+	if app.keyring != nil {
+		return app.keyring, nil
+	}
+
+	keys := app.Config.Encryption.Keys
+	if len(keys) == 0 && app.Config.SecretKey != "" && app.Config.SecretKey != DefaultSecretKey {
+		keys = []string{app.Config.SecretKey}
+	}
+	if len(keys) == 0 {
+		return nil, ErrKeyringNotConfigured
+	}
+
+	kr, err := keyring.New(keys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keyring: %v", err)
+	}
+
+	app.keyring = kr
+	return kr, nil
+}
+
+// SetKeyring sets the keyring.
+func (app *Application) SetKeyring(kr keyring.Keyring) {
+	app.keyringMu.Lock()
+	defer app.keyringMu.Unlock()
+
+	app.keyring = kr
+}
+
+// checkEncryptionConfig refuses encryption at rest without an explicit key, it is checked on start.
+func (app *Application) checkEncryptionConfig() error {
+	if !app.Config.Encryption.Session && len(app.Config.Encryption.CacheNamespaces) == 0 {
+		return nil
+	}
+
+	if _, err := app.Keyring(); err != nil {
+		return fmt.Errorf("encryption at rest is enabled: %w", err)
+	}
+
+	return nil
+}
+
+// appKeyring resolves app.Keyring on each operation, the operations fail if it is not configured,
+// so that the encrypted data is never written in plaintext.
+type appKeyring struct {
+	app *Application
+}
+
+func (k *appKeyring) Encrypt(plaintext []byte) (string, error) {
+	kr, err := k.app.Keyring()
+	if err != nil {
+		return "", err
+	}
+
+	return kr.Encrypt(plaintext)
+}
+
+func (k *appKeyring) Decrypt(payload string) ([]byte, error) {
+	kr, err := k.app.Keyring()
+	if err != nil {
+		return nil, err
+	}
+
+	return kr.Decrypt(payload)
+}
+
+func (k *appKeyring) IsEncrypted(payload string) bool {
+	return strings.HasPrefix(payload, keyring.Prefix)
+}
+
+func (k *appKeyring) Sign(data []byte) string {
+	kr, err := k.app.Keyring()
+	if err != nil {
+		return ""
+	}
+
+	return kr.Sign(data)
+}
+
+func (k *appKeyring) Verify(data []byte, signature string) bool {
+	kr, err := k.app.Keyring()
+	if err != nil {
+		return false
+	}
+
+	return kr.Verify(data, signature)
+}
+
+// encryptedCookie encrypts the cookie values, values failed to decrypt
+// (such as plaintext before encryption enabled, or keys removed) are ignored.
+type encryptedCookie struct {
+	cookie.Cookie
+	keyring keyring.Keyring
+	logger  *RequestLogger
+}
+
+// Set sets the encrypted value, the cookie is not set if the encryption fails.
+func (c *encryptedCookie) Set(name string, value string, cfg ...*cookie.Config) {
+	encrypted, err := c.keyring.Encrypt([]byte(value))
+	if err != nil {
+		c.logger.Errorf("[session] failed to encrypt cookie %s: %v", name, err)
+		return
+	}
+
+	c.Cookie.Set(name, encrypted, cfg...)
+}
+
+func (c *encryptedCookie) Get(name string) string {
+	value := c.Cookie.Get(name)
+	if value == "" {
+		return ""
+	}
+
+	decrypted, err := c.keyring.Decrypt(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(decrypted)
+}
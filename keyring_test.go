@@ -0,0 +1,124 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/zoox/components/application/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringRequiresExplicitKey(t *testing.T) {
+	cases := []struct {
+		name      string
+		secretKey string
+		keys      []string
+		err       error
+	}{
+		{"no key", "", nil, ErrKeyringNotConfigured},
+		{"random default secret key", DefaultSecretKey, nil, ErrKeyringNotConfigured},
+		{"secret key", "secret", nil, nil},
+		{"encryption keys", DefaultSecretKey, []string{"key_2024_06", "key_2024_01"}, nil},
+	}
+
+	for _, c := range cases {
+		app := New()
+		app.Config.SecretKey = c.secretKey
+		app.Config.Encryption.Keys = c.keys
+
+		kr, err := app.Keyring()
+		assert.ErrorIs(t, err, c.err, c.name)
+		assert.Equal(t, c.err == nil, kr != nil, c.name)
+	}
+}
+
+func TestKeyringErrorIsNotCached(t *testing.T) {
+	app := New()
+	_, err := app.Keyring()
+	assert.ErrorIs(t, err, ErrKeyringNotConfigured)
+
+	app.Config.SecretKey = "secret"
+	_, err = app.Keyring()
+	assert.NoError(t, err)
+}
+
+func TestEncryptionAtRestRequiresKey(t *testing.T) {
+	app := New()
+	app.Config.Encryption.Session = true
+	assert.ErrorIs(t, app.applyDefaultConfig(), ErrKeyringNotConfigured)
+
+	app = New()
+	app.Config.Encryption.CacheNamespaces = []string{"user:"}
+	assert.ErrorIs(t, app.applyDefaultConfig(), ErrKeyringNotConfigured)
+}
+
+func TestEncryptedSessionWithoutKey(t *testing.T) {
+	app := New()
+	app.Config.Encryption.Session = true
+	app.Get("/login", func(ctx *Context) {
+		ctx.Session().Set("user", "42")
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	recorder := httptest.NewRecorder()
+	assert.NotPanics(t, func() {
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login", nil))
+	})
+
+	// the session is never written in plaintext
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Set-Cookie"))
+}
+
+func TestEncryptedSessionRotation(t *testing.T) {
+	newApp := func(keys ...string) *Application {
+		app := New()
+		app.Config.SecretKey = "secret"
+		app.Config.Encryption.Keys = keys
+		app.Config.Encryption.Session = true
+		app.Get("/login", func(ctx *Context) {
+			ctx.Session().Set("user", "42")
+			ctx.String(http.StatusOK, "ok")
+		})
+		app.Get("/me", func(ctx *Context) {
+			ctx.String(http.StatusOK, ctx.Session().Get("user"))
+		})
+		return app
+	}
+
+	recorder := httptest.NewRecorder()
+	newApp("key_2024_01").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/login", nil))
+	cookies := recorder.Result().Cookies()
+	if !assert.NotEmpty(t, cookies) {
+		return
+	}
+	session := cookies[0]
+	assert.True(t, strings.HasPrefix(session.Value, keyring.Prefix), "the session is encrypted")
+
+	tampered := []byte(session.Value)
+	// the last character may only carry the padding bits
+	tampered[len(tampered)/2] ^= 1
+
+	cases := []struct {
+		name   string
+		keys   []string
+		value  string
+		expect string
+	}{
+		{"same key", []string{"key_2024_01"}, session.Value, "42"},
+		{"rotated, the old key is kept", []string{"key_2024_06", "key_2024_01"}, session.Value, "42"},
+		{"rotated, the old key is removed", []string{"key_2024_06"}, session.Value, ""},
+		{"tampered", []string{"key_2024_01"}, string(tampered), ""},
+		{"plaintext", []string{"key_2024_01"}, "42", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(&http.Cookie{Name: session.Name, Value: c.value})
+		recorder := httptest.NewRecorder()
+		newApp(c.keys...).ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.expect, recorder.Body.String(), c.name)
+	}
+}
@@ -0,0 +1,161 @@
+package zoox
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-zoox/logger"
+)
+
+// LogLevels are the supported log levels.
+var LogLevels = []string{"debug", "info", "warn", "error", "fatal"}
+
+// LogLevelState is the log levels of the app and modules.
+type LogLevelState struct {
+	// Level is the level of app.Logger, and the modules without their own levels.
+	Level string `json:"level"`
+	// Modules are the levels of module loggers, key: module.
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+type logLevels struct {
+	// mu serializes the updates, the state is read without locks.
+	mu      sync.Mutex
+	loggers sync.Map
+}
+
+// SetLogLevel changes the level of app.Logger at runtime without restart,
+// or the level of the module logger if module is given, see app.ModuleLogger.
+//
+// Example:
+//
+//	app.SetLogLevel("debug")
+//	app.SetLogLevel("debug", "database")
+//	// reset the module to follow the app level
+//	app.SetLogLevel("", "database")
+func (app *Application) SetLogLevel(level string, module ...string) error {
+	level = strings.ToLower(level)
+	if level != "" && !isLogLevel(level) {
+		return fmt.Errorf("invalid log level: %s (supported: %s)", level, strings.Join(LogLevels, ", "))
+	}
+
+	app.logLevels.mu.Lock()
+	defer app.logLevels.mu.Unlock()
+
+	current := app.LogLevel()
+	next := &LogLevelState{Level: current.Level, Modules: map[string]string{}}
+	for name, l := range current.Modules {
+		next.Modules[name] = l
+	}
+
+	if len(module) > 0 && module[0] != "" {
+		if level == "" {
+			delete(next.Modules, module[0])
+		} else {
+			next.Modules[module[0]] = level
+		}
+	} else {
+		if level == "" {
+			return fmt.Errorf("log level is required")
+		}
+
+		next.Level = level
+		app.Logger().SetLevel(level)
+	}
+
+	app.logLevel.Store(next)
+
+	// apply to the created module loggers
+	app.logLevels.loggers.Range(func(key, value any) bool {
+		value.(*logger.Logger).SetLevel(next.level(key.(string)))
+		return true
+	})
+
+	app.Logger().Infof("[log_level] %s", next)
+	return nil
+}
+
+// LogLevel returns the current log levels of the app and modules.
+func (app *Application) LogLevel() *LogLevelState {
+	if state := app.logLevel.Load(); state != nil {
+		return state
+	}
+
+	level := strings.ToLower(app.Config.LogLevel)
+	if level == "" {
+		level = "info"
+	}
+
+	return &LogLevelState{Level: level}
+}
+
+// ModuleLogger returns the logger of the module, whose level can be changed separately by app.SetLogLevel(level, module),
+// it follows the app level if not set.
+func (app *Application) ModuleLogger(module string) *logger.Logger {
+	if l, ok := app.logLevels.loggers.Load(module); ok {
+		return l.(*logger.Logger)
+	}
+
+	app.logLevels.mu.Lock()
+	defer app.logLevels.mu.Unlock()
+
+	if l, ok := app.logLevels.loggers.Load(module); ok {
+		return l.(*logger.Logger)
+	}
+
+	l := logger.New(func(opt *logger.Option) {
+		opt.Level = app.LogLevel().level(module)
+	})
+	app.logLevels.loggers.Store(module, l)
+	return l
+}
+
+func (s *LogLevelState) level(module string) string {
+	if l, ok := s.Modules[module]; ok {
+		return l
+	}
+
+	return s.Level
+}
+
+func (s *LogLevelState) String() string {
+	if len(s.Modules) == 0 {
+		return fmt.Sprintf("level: %s", s.Level)
+	}
+
+	return fmt.Sprintf("level: %s, modules: %v", s.Level, s.Modules)
+}
+
+// logLevelEnabled reports whether the logs of the level are printed by app.Logger, without allocations.
+func (app *Application) logLevelEnabled(level string) bool {
+	current := "info"
+	if state := app.logLevel.Load(); state != nil {
+		current = state.Level
+	} else if app.Config.LogLevel != "" {
+		current = strings.ToLower(app.Config.LogLevel)
+	}
+
+	return logLevelIndex(level) >= logLevelIndex(current)
+}
+
+// logLevelIndex returns the index of the level in LogLevels, 0 (debug) if unknown.
+func logLevelIndex(level string) int {
+	for i, l := range LogLevels {
+		if l == level {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func isLogLevel(level string) bool {
+	for _, l := range LogLevels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
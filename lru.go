@@ -0,0 +1,66 @@
+package zoox
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a concurrency-safe least recently used cache.
+type lru[K comparable, V any] struct {
+	sync.Mutex
+	size  int
+	items map[K]*list.Element
+	order *list.List
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](size int) *lru[K, V] {
+	return &lru[K, V]{
+		size:  size,
+		items: map[K]*list.Element{},
+		order: list.New(),
+	}
+}
+
+func (c *lru[K, V]) Get(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+func (c *lru[K, V]) Set(key K, value V) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+func (c *lru[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.items = map[K]*list.Element{}
+	c.order.Init()
+}
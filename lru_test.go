@@ -0,0 +1,45 @@
+package zoox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := newLRU[string, int](2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// a is used recently, b is evicted
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Set("c", 3)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	// updating moves to front, a is evicted
+	c.Set("c", 30)
+	c.Set("d", 4)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 30, v)
+	v, ok = c.Get("d")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	c.Clear()
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.order.Len())
+
+	c.Set("e", 5)
+	v, ok = c.Get("e")
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+}
@@ -0,0 +1,138 @@
+package zoox
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/go-zoox/headers"
+)
+
+// DefaultMaintenanceRetryAfter is the default Retry-After of maintenance mode.
+var DefaultMaintenanceRetryAfter = 5 * time.Minute
+
+// DefaultMaintenanceMessage is the default message of maintenance mode.
+var DefaultMaintenanceMessage = "Service is under maintenance, please try again later."
+
+// MaintenanceOption is the option of maintenance mode.
+type MaintenanceOption struct {
+	// RetryAfter is the Retry-After header, default: 5m.
+	RetryAfter time.Duration `json:"retry_after"`
+	// Message is the message of the response.
+	Message string `json:"message"`
+	// Allowlist are the path patterns served as usual, see zoox.Skip for patterns.
+	Allowlist []string `json:"allowlist,omitempty"`
+	// AllowIPs are the client ips served as usual, such as the office network.
+	AllowIPs []string `json:"allow_ips,omitempty"`
+	// HTML is the page for browsers, the message is used if empty.
+	HTML string `json:"-"`
+	// Handler customizes the response, overriding the message and page.
+	Handler HandlerFunc `json:"-"`
+}
+
+type maintenance struct {
+	option  *MaintenanceOption
+	matcher *pathMatcher
+}
+
+// SetMaintenance toggles the maintenance mode at runtime, requests are responded with 503 and Retry-After,
+// except the allowlisted paths and ips. Middlewares still run, so health checks and admin endpoints served by middlewares are available.
+//
+// Example:
+//
+//	app.SetMaintenance(true, func(opt *zoox.MaintenanceOption) {
+//		opt.RetryAfter = 10 * time.Minute
+//		opt.Allowlist = []string{"/healthz", "/api/status/**"}
+//	})
+func (app *Application) SetMaintenance(enabled bool, opts ...func(opt *MaintenanceOption)) {
+	if !enabled {
+		app.maintenance.Store(nil)
+		app.Logger().Infof("[maintenance] disabled")
+		return
+	}
+
+	opt := &MaintenanceOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+	if opt.RetryAfter == 0 {
+		opt.RetryAfter = DefaultMaintenanceRetryAfter
+	}
+	if opt.Message == "" {
+		opt.Message = DefaultMaintenanceMessage
+	}
+
+	app.maintenance.Store(&maintenance{
+		option:  opt,
+		matcher: &pathMatcher{patterns: opt.Allowlist},
+	})
+	app.Logger().Infof("[maintenance] enabled (retry after: %s)", opt.RetryAfter)
+}
+
+// IsMaintenance returns true if the app is in maintenance mode.
+func (app *Application) IsMaintenance() bool {
+	return app.maintenance.Load() != nil
+}
+
+// MaintenanceOption returns the option of maintenance mode, nil if disabled.
+func (app *Application) MaintenanceOption() *MaintenanceOption {
+	m := app.maintenance.Load()
+	if m == nil {
+		return nil
+	}
+
+	return m.option
+}
+
+// ToggleMaintenanceOnSignal toggles the maintenance mode when the signal is received, such as syscall.SIGUSR2.
+func (app *Application) ToggleMaintenanceOnSignal(sig os.Signal, opts ...func(opt *MaintenanceOption)) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+
+	go func() {
+		for range ch {
+			app.SetMaintenance(!app.IsMaintenance(), opts...)
+		}
+	}()
+}
+
+// maintenanceHandler is the handler after middlewares, responds 503 in maintenance mode.
+func (app *Application) maintenanceHandler(ctx *Context) {
+	m := app.maintenance.Load()
+	if m == nil || m.matcher.match(ctx.Path) {
+		ctx.Next()
+		return
+	}
+
+	if len(m.option.AllowIPs) > 0 {
+		ip := ctx.ClientIP()
+		for _, allowed := range m.option.AllowIPs {
+			if allowed == ip {
+				ctx.Next()
+				return
+			}
+		}
+	}
+
+	ctx.SetHeader(headers.RetryAfter, strconv.Itoa(int(m.option.RetryAfter.Seconds())))
+	ctx.SetCacheControlWithNoStore()
+
+	if m.option.Handler != nil {
+		ctx.Status(http.StatusServiceUnavailable)
+		m.option.Handler(ctx)
+		return
+	}
+
+	if ctx.AcceptJSON() || m.option.HTML == "" {
+		ctx.JSON(http.StatusServiceUnavailable, H{
+			"code":        http.StatusServiceUnavailable,
+			"message":     m.option.Message,
+			"retry_after": int(m.option.RetryAfter.Seconds()),
+		})
+		return
+	}
+
+	ctx.Data(http.StatusServiceUnavailable, "text/html; charset=utf-8", []byte(m.option.HTML))
+}
@@ -22,7 +22,7 @@ func AuthServer(cfg *AuthServerConfig) zoox.Middleware {
 		// 1. Bear Token
 		if token, ok := ctx.BearerToken(); ok {
 			if status, code, message, err := handleAuthServerTypeBearerToken(ctx, cfg.Server, token); err != nil {
-				ctx.Logger.Errorf("[auth-server: bearer token] failed to authenticate with auth server: %s", err)
+				ctx.RequestLogger().Errorf("[auth-server: bearer token] failed to authenticate with auth server: %s", err)
 
 				ctx.JSON(status, zoox.H{
 					"code":    code,
@@ -38,9 +38,9 @@ func AuthServer(cfg *AuthServerConfig) zoox.Middleware {
 		// 2. Basic Auth
 		if username, password, ok := ctx.Request.BasicAuth(); ok {
 			if _, _, _, err := handleAuthServerTypeBasicAuth(ctx, cfg.Server, username, password); err != nil {
-				ctx.Logger.Errorf("[auth-server: bearer token] failed to authenticate with auth server: %s", err)
+				ctx.RequestLogger().Errorf("[auth-server: bearer token] failed to authenticate with auth server: %s", err)
 
-				ctx.Set("WWW-Authenticate", `Basic realm="Go-Zoox"`)
+				ctx.SetHeader("WWW-Authenticate", `Basic realm="Go-Zoox"`)
 				ctx.Status(401)
 				return
 			}
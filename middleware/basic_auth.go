@@ -11,7 +11,7 @@ func BasicAuth(realm string, credentials map[string]string) zoox.Middleware {
 	return func(ctx *zoox.Context) {
 		user, pass, ok := ctx.Request.BasicAuth()
 		if !ok {
-			ctx.Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+			ctx.SetHeader("WWW-Authenticate", `Basic realm="`+realm+`"`)
 			ctx.Status(401)
 			return
 		}
@@ -47,8 +47,8 @@ func CacheControl(cfg *CacheControlConfig) zoox.Middleware {
 			for _, item := range cfg.Items {
 				if item.Path.Match(ctx.Path) {
 					maxAge := cfg.MaxAge / time.Second
-					// ctx.Logger.Infof("[middleware][cache-control] hit path: %s, max-age: %d", ctx.Path, maxAge)
-					ctx.Set(headers.CacheControl, fmt.Sprintf("public, max-age=%d", maxAge))
+					// ctx.RequestLogger().Infof("[middleware][cache-control] hit path: %s, max-age: %d", ctx.Path, maxAge)
+					ctx.SetHeader(headers.CacheControl, fmt.Sprintf("public, max-age=%d", maxAge))
 					break
 				}
 			}
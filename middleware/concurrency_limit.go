@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"golang.org/x/sync/semaphore"
+)
+
+// ConcurrencyLimitConfig ...
+type ConcurrencyLimitConfig struct {
+	// MaxInFlight is the max weight of in-flight requests, required.
+	MaxInFlight int64
+	// Weight returns the weight of the request, default: 1.
+	Weight func(ctx *zoox.Context) int64
+	// QueueSize is the max waiting requests, 0 means rejecting immediately when full.
+	QueueSize int64
+	// QueueTimeout is the max duration of waiting, default: 10s.
+	QueueTimeout time.Duration
+	// Status is the status of rejected requests, 429 (default) or 503.
+	Status int
+}
+
+// ConcurrencyLimit limits the in-flight requests with a weighted semaphore,
+// requests wait in queue until timeout when full, then they are rejected with 429 or 503.
+//
+// Example:
+//
+//	app.Post("/reports", middleware.ConcurrencyLimit(&middleware.ConcurrencyLimitConfig{
+//		MaxInFlight:  4,
+//		QueueSize:    16,
+//		QueueTimeout: 30 * time.Second,
+//	}), generateReport)
+func ConcurrencyLimit(cfg *ConcurrencyLimitConfig) zoox.Middleware {
+	if cfg.MaxInFlight <= 0 {
+		panic("concurrency limit max in-flight must be positive")
+	}
+
+	queueTimeout := cfg.QueueTimeout
+	if queueTimeout == 0 {
+		queueTimeout = 10 * time.Second
+	}
+
+	status := cfg.Status
+	if status == 0 {
+		status = http.StatusTooManyRequests
+	}
+
+	sem := semaphore.NewWeighted(cfg.MaxInFlight)
+	var waiting atomic.Int64
+
+	reject := func(ctx *zoox.Context) {
+		ctx.SetHeader("Retry-After", "1")
+		ctx.Fail(nil, status, http.StatusText(status), status)
+	}
+
+	return func(ctx *zoox.Context) {
+		weight := int64(1)
+		if cfg.Weight != nil {
+			weight = cfg.Weight(ctx)
+		}
+		if weight > cfg.MaxInFlight {
+			weight = cfg.MaxInFlight
+		}
+
+		if !sem.TryAcquire(weight) {
+			if waiting.Add(1) > cfg.QueueSize {
+				waiting.Add(-1)
+				reject(ctx)
+				return
+			}
+
+			waitCtx, cancel := context.WithTimeout(ctx.Context(), queueTimeout)
+			err := sem.Acquire(waitCtx, weight)
+			cancel()
+			waiting.Add(-1)
+
+			if err != nil {
+				reject(ctx)
+				return
+			}
+		}
+		defer sem.Release(weight)
+
+		ctx.Next()
+	}
+}
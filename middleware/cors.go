@@ -81,7 +81,7 @@ func CORS(cfg ...*CorsConfig) zoox.Middleware {
 			}
 		}
 
-		ctx.Set("Access-Control-Allow-Origin", origin)
+		ctx.SetHeader("Access-Control-Allow-Origin", origin)
 
 		isPreflight := ctx.Method == http.MethodOptions
 		// not preflight
@@ -89,11 +89,11 @@ func CORS(cfg ...*CorsConfig) zoox.Middleware {
 			// Note that simple GET requests are not preflighted, and so if a request is made for a resource with credentials,
 			//	if this header is not returned with the resource, the response is ignored by the browser and not returned to web content.
 			if ctx.Method == http.MethodGet && cfgX.AllowCredentials {
-				ctx.Set("Access-Control-Allow-Credentials", "true")
+				ctx.SetHeader("Access-Control-Allow-Credentials", "true")
 			}
 
 			if len(cfgX.ExposeHeaders) > 0 {
-				ctx.Set("Access-Control-Expose-Headers", strings.Join(cfgX.ExposeHeaders, ","))
+				ctx.SetHeader("Access-Control-Expose-Headers", strings.Join(cfgX.ExposeHeaders, ","))
 			}
 
 			ctx.Next()
@@ -101,19 +101,19 @@ func CORS(cfg ...*CorsConfig) zoox.Middleware {
 		}
 
 		if len(cfgX.AllowMethods) > 0 {
-			ctx.Set("Access-Control-Allow-Methods", strings.Join(cfgX.AllowMethods, ","))
+			ctx.SetHeader("Access-Control-Allow-Methods", strings.Join(cfgX.AllowMethods, ","))
 		}
 
 		if len(cfgX.AllowHeaders) > 0 {
-			ctx.Set("Access-Control-Allow-Headers", strings.Join(cfgX.AllowHeaders, ","))
+			ctx.SetHeader("Access-Control-Allow-Headers", strings.Join(cfgX.AllowHeaders, ","))
 		}
 
 		if cfgX.MaxAge != 0 {
-			ctx.Set("Access-Control-Max-Age", fmt.Sprintf("%d", cfgX.MaxAge))
+			ctx.SetHeader("Access-Control-Max-Age", fmt.Sprintf("%d", cfgX.MaxAge))
 		}
 
 		if cfgX.AllowCredentials {
-			ctx.Set("Access-Control-Allow-Credentials", "true")
+			ctx.SetHeader("Access-Control-Allow-Credentials", "true")
 		}
 
 		ctx.String(200, "OK")
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/zoox"
+)
+
+// ErrDecompressedBodyTooLarge is returned by the request body reader when the decompressed body exceeds the limits.
+var ErrDecompressedBodyTooLarge = errors.New("decompressed request body too large")
+
+// Decoder creates the decompressing reader of the encoding.
+type Decoder func(r io.Reader) (io.ReadCloser, error)
+
+// DecompressConfig ...
+type DecompressConfig struct {
+	// MaxSize is the maximum size of the decompressed body, default 10MB.
+	MaxSize int64
+	// MaxRatio is the maximum ratio of decompressed to compressed size, default 100, -1 means no limit.
+	//	It is checked after the first 64KB decompressed, so small payloads are not rejected.
+	MaxRatio int64
+	// Decoders are the additional decoders by encoding, such as br:
+	//
+	//	"br": func(r io.Reader) (io.ReadCloser, error) {
+	//		return io.NopCloser(brotli.NewReader(r)), nil
+	//	},
+	//
+	// gzip and deflate are builtin.
+	Decoders map[string]Decoder
+}
+
+// Decompress decompresses the request body by the Content-Encoding (gzip, deflate, or the decoders) before binding,
+// requests with unsupported encodings are rejected with 415.
+//
+// The decompressed body is limited by MaxSize and MaxRatio against zip bombs,
+// reads beyond the limits fail with ErrDecompressedBodyTooLarge.
+//
+// Example:
+//
+//	api := app.Group("/api")
+//	api.Use(middleware.Decompress())
+func Decompress(cfg ...*DecompressConfig) zoox.Middleware {
+	cfgX := &DecompressConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.MaxSize == 0 {
+		cfgX.MaxSize = 10 * 1024 * 1024
+	}
+	if cfgX.MaxRatio == 0 {
+		cfgX.MaxRatio = 100
+	}
+
+	decoders := map[string]Decoder{
+		"gzip": func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		"x-gzip": func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		// deflate of http is zlib format (RFC 9110)
+		"deflate": func(r io.Reader) (io.ReadCloser, error) {
+			return zlib.NewReader(r)
+		},
+	}
+	for encoding, decoder := range cfgX.Decoders {
+		decoders[strings.ToLower(encoding)] = decoder
+	}
+
+	return func(ctx *zoox.Context) {
+		encoding := ctx.Request.Header.Get("Content-Encoding")
+		if encoding == "" || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+			ctx.Next()
+			return
+		}
+
+		// encodings are listed in the order applied, so decoded in reverse
+		encodings := strings.Split(encoding, ",")
+		compressed := &countingReader{Reader: ctx.Request.Body}
+		var body io.Reader = compressed
+		closers := []io.Closer{ctx.Request.Body}
+		for i := len(encodings) - 1; i >= 0; i-- {
+			name := strings.ToLower(strings.TrimSpace(encodings[i]))
+			if name == "identity" || name == "" {
+				continue
+			}
+
+			decoder, ok := decoders[name]
+			if !ok {
+				ctx.Fail(fmt.Errorf("unsupported content encoding: %s", name), http.StatusUnsupportedMediaType, "unsupported content encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			reader, err := decoder(body)
+			if err != nil {
+				ctx.Fail(fmt.Errorf("invalid %s body: %v", name, err), http.StatusBadRequest, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			body = reader
+			closers = append(closers, reader)
+		}
+
+		ctx.Request.Body = &decompressReader{
+			Reader:     body,
+			closers:    closers,
+			compressed: compressed,
+			cfg:        cfgX,
+		}
+		ctx.Request.Header.Del("Content-Encoding")
+		ctx.Request.Header.Del("Content-Length")
+		ctx.Request.ContentLength = -1
+
+		ctx.Next()
+	}
+}
+
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type decompressReader struct {
+	io.Reader
+	closers    []io.Closer
+	compressed *countingReader
+	cfg        *DecompressConfig
+
+	n        int64
+	exceeded bool
+}
+
+func (r *decompressReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, ErrDecompressedBodyTooLarge
+	}
+
+	// read at most one byte beyond the limit to tell exceeded from exactly MaxSize
+	if remaining := r.cfg.MaxSize - r.n + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+
+	if r.n > r.cfg.MaxSize {
+		r.exceeded = true
+		return n - int(r.n-r.cfg.MaxSize), ErrDecompressedBodyTooLarge
+	}
+
+	if r.cfg.MaxRatio > 0 && r.n > 64*1024 && r.n > r.compressed.n*r.cfg.MaxRatio {
+		r.exceeded = true
+		return n, ErrDecompressedBodyTooLarge
+	}
+
+	return n, err
+}
+
+// Close closes the decoders and the original body.
+func (r *decompressReader) Close() error {
+	var errs []error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipBody(data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func deflateBody(data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := zlib.NewWriter(buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+// incompressible returns n bytes which are barely compressed.
+func incompressible(n int) []byte {
+	data := make([]byte, n)
+	x := uint32(1)
+	for i := range data {
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		data[i] = byte(x)
+	}
+	return data
+}
+
+func newDecompressApp(cfg *DecompressConfig) *zoox.Application {
+	app := zoox.New()
+	app.Use(Decompress(cfg))
+	app.Post("/", func(ctx *zoox.Context) {
+		data, err := io.ReadAll(ctx.Request.Body)
+		if errors.Is(err, ErrDecompressedBodyTooLarge) {
+			ctx.Status(http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		ctx.String(http.StatusOK, strconv.Itoa(len(data)))
+	})
+
+	return app
+}
+
+func TestDecompress(t *testing.T) {
+	app := newDecompressApp(nil)
+	data := []byte(`{"hello":"world"}`)
+
+	res := serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(data)), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, strconv.Itoa(len(data)), res.Body.String())
+
+	// applied in order deflate then gzip, decoded in reverse
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(deflateBody(data))), "Content-Encoding", "deflate, gzip")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, strconv.Itoa(len(data)), res.Body.String())
+
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(data), "Content-Encoding", "br")
+	assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(data), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestDecompressMaxSize(t *testing.T) {
+	app := newDecompressApp(&DecompressConfig{MaxSize: 1000, MaxRatio: -1})
+
+	res := serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(incompressible(1000))), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "1000", res.Body.String())
+
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(incompressible(1001))), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+}
+
+func TestDecompressMaxRatio(t *testing.T) {
+	app := newDecompressApp(&DecompressConfig{MaxRatio: 100})
+
+	// small payloads are not checked by ratio
+	res := serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(make([]byte, 32*1024))), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	// 1MB of zeros compresses to about 1KB
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(make([]byte, 1024*1024))), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+
+	// the ratio limit can be disabled, still bounded by MaxSize
+	app = newDecompressApp(&DecompressConfig{MaxRatio: -1})
+	res = serve(app, http.MethodPost, "/", bytes.NewReader(gzipBody(make([]byte, 1024*1024))), "Content-Encoding", "gzip")
+	assert.Equal(t, http.StatusOK, res.Code)
+}
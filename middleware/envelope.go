@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox"
+)
+
+// EnvelopeConfig ...
+type EnvelopeConfig struct {
+	// SuccessCode is the code of the successful responses, default 200.
+	SuccessCode int
+	// SuccessMessage is the message of the successful responses, default success.
+	SuccessMessage string
+	// MapError maps the error of ctx.Fail to the code and message, ok false keeps the original ones.
+	//	zoox.HTTPError is mapped to its code and message by default.
+	MapError func(err error) (code int, message string, ok bool)
+}
+
+// Envelope wraps the JSON responses in the code-message-result format as ctx.Success and ctx.Fail:
+//
+//	{"code": 200, "message": "success", "result": <response>}
+//	{"code": 40001, "message": "invalid name"}
+//
+// Responses already in the format (such as of ctx.Success and ctx.Fail) and non-JSON responses are kept,
+// routes can be opted out with Route.RawResponse (such as file streams and webhooks) or ctx.SkipEnvelope.
+//
+// Example:
+//
+//	api := app.Group("/api")
+//	api.Use(middleware.Envelope())
+//
+//	api.Get("/users/:id", getUser)
+//	api.Route(http.MethodPost, "/hooks/github", githubHook).RawResponse()
+func Envelope(cfg ...*EnvelopeConfig) zoox.Middleware {
+	cfgX := &EnvelopeConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.SuccessCode == 0 {
+		cfgX.SuccessCode = http.StatusOK
+	}
+	if cfgX.SuccessMessage == "" {
+		cfgX.SuccessMessage = "success"
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.IsRawResponse() {
+			ctx.Next()
+			return
+		}
+
+		ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+			if ctx.IsRawResponse() || len(body) == 0 || !strings.Contains(ctx.Writer.Header().Get(headers.ContentType), "application/json") {
+				return status, body
+			}
+
+			// numbers are kept as is, such as int64 ids
+			var result any
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			if err := decoder.Decode(&result); err != nil {
+				return status, body
+			}
+
+			var enveloped map[string]any
+			if isEnveloped(result) {
+				enveloped = result.(map[string]any)
+			}
+
+			if status < http.StatusBadRequest && ctx.Failure() == nil {
+				if enveloped != nil {
+					return status, body
+				}
+
+				return status, encodeEnvelope(map[string]any{
+					"code":    cfgX.SuccessCode,
+					"message": cfgX.SuccessMessage,
+					"result":  result,
+				}, body)
+			}
+
+			if enveloped == nil {
+				enveloped = map[string]any{
+					"code":    status,
+					"message": http.StatusText(status),
+				}
+
+				// such as ctx.Error
+				if object, ok := result.(map[string]any); ok {
+					if message, ok := object["message"].(string); ok {
+						enveloped["message"] = message
+					}
+				}
+			}
+
+			if err := ctx.Failure(); err != nil {
+				if cfgX.MapError != nil {
+					if code, message, ok := cfgX.MapError(err); ok {
+						enveloped["code"] = code
+						enveloped["message"] = message
+					}
+				} else {
+					var httpErr zoox.HTTPError
+					if errors.As(err, &httpErr) {
+						enveloped["code"] = httpErr.Code()
+						enveloped["message"] = httpErr.Message()
+					}
+				}
+			}
+
+			return status, encodeEnvelope(enveloped, body)
+		})
+	}
+}
+
+// isEnveloped returns true if the response is an object of code, message and (optional) result.
+func isEnveloped(result any) bool {
+	object, ok := result.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	if _, ok := object["code"]; !ok {
+		return false
+	}
+	if _, ok := object["message"]; !ok {
+		return false
+	}
+
+	for key := range object {
+		if key != "code" && key != "message" && key != "result" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func encodeEnvelope(envelope map[string]any, fallback []byte) []byte {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fallback
+	}
+
+	return data
+}
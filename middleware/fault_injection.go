@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"math/rand"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox"
+)
+
+// DefaultFaultInjectionAdminPath ...
+const DefaultFaultInjectionAdminPath = "/_/faults"
+
+// FaultRule is a fault injected on a percentage of the matched requests.
+type FaultRule struct {
+	Name string `json:"name"`
+	// Paths are the matched paths, supports exact path, glob (/users/*) and prefix (/api/**), empty matches all.
+	Paths []string `json:"paths,omitempty"`
+	// Methods are the matched methods, empty matches all.
+	Methods []string `json:"methods,omitempty"`
+	// Headers are the matched headers, * matches any value.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Percentage is the percentage (0-100) of matched requests to inject.
+	Percentage float64 `json:"percentage"`
+
+	// LatencyMS delays the request.
+	LatencyMS int `json:"latency_ms,omitempty"`
+	// JitterMS is the max random delay added to LatencyMS.
+	JitterMS int `json:"jitter_ms,omitempty"`
+	// Status responds the error status instead of calling the handler.
+	Status int `json:"status,omitempty"`
+	// Drop closes the connection without response.
+	Drop bool `json:"drop,omitempty"`
+	// TruncateBytes sends the response headers with the full content length but only the first bytes of body.
+	TruncateBytes int `json:"truncate_bytes,omitempty"`
+}
+
+// FaultInjectionConfig ...
+type FaultInjectionConfig struct {
+	// Rules are the initial rules, the first matched rule is injected.
+	Rules []*FaultRule
+	// AdminPath is the path of admin api, default: /_/faults.
+	AdminPath string
+	// Token is the bearer token to access the admin api, required unless DisableAdmin.
+	Token string
+	// DisableAdmin disables the admin api, the rules can't be changed at runtime.
+	DisableAdmin bool
+}
+
+// FaultInjection injects latency, error status, dropped connections and truncated bodies
+// on a percentage of requests for resilience testing, never use it without care in production.
+//
+// Rules can be changed at runtime with the admin api (unless DisableAdmin):
+//
+//	GET    /_/faults  => list rules
+//	PUT    /_/faults  => replace rules, [{"paths": ["/api/**"], "percentage": 10, "status": 503}]
+//	DELETE /_/faults  => clear rules
+func FaultInjection(cfg ...*FaultInjectionConfig) zoox.Middleware {
+	cfgX := &FaultInjectionConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	if cfgX.Token == "" && !cfgX.DisableAdmin {
+		panic("fault injection admin token is required, or set DisableAdmin")
+	}
+
+	adminPath := cfgX.AdminPath
+	if adminPath == "" {
+		adminPath = DefaultFaultInjectionAdminPath
+	}
+
+	var rules atomic.Pointer[[]*FaultRule]
+	initial := append([]*FaultRule{}, cfgX.Rules...)
+	rules.Store(&initial)
+
+	return func(ctx *zoox.Context) {
+		if !cfgX.DisableAdmin && ctx.Path == adminPath {
+			faultInjectionAdmin(ctx, cfgX.Token, &rules)
+			return
+		}
+
+		rule := matchFaultRule(ctx, *rules.Load())
+		if rule == nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.SetHeader("X-Fault-Injected", rule.Name)
+
+		if delay := time.Duration(rule.LatencyMS) * time.Millisecond; delay > 0 || rule.JitterMS > 0 {
+			if rule.JitterMS > 0 {
+				delay += time.Duration(rand.Intn(rule.JitterMS)) * time.Millisecond
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Request.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case rule.Drop:
+			conn, _, err := ctx.Writer.Hijack()
+			if err != nil {
+				ctx.RequestLogger().Warnf("[middleware][fault_injection] failed to drop connection: %s", err)
+				return
+			}
+			conn.Close()
+		case rule.Status != 0:
+			ctx.Fail(nil, rule.Status, "fault injected", rule.Status)
+		case rule.TruncateBytes > 0:
+			origin := ctx.Writer
+			ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+				if len(body) <= rule.TruncateBytes {
+					return status, body
+				}
+
+				// the client sees an unexpected eof
+				origin.Header().Set(headers.ContentLength, strconv.Itoa(len(body)))
+				origin.WriteHeader(status)
+				origin.Write(body[:rule.TruncateBytes])
+				origin.Flush()
+				if conn, _, err := origin.Hijack(); err == nil {
+					conn.Close()
+				}
+
+				return status, nil
+			})
+		default:
+			ctx.Next()
+		}
+	}
+}
+
+func matchFaultRule(ctx *zoox.Context, rules []*FaultRule) *FaultRule {
+	for _, rule := range rules {
+		if rule.Percentage <= 0 || !rule.match(ctx) {
+			continue
+		}
+
+		if rule.Percentage >= 100 || rand.Float64()*100 < rule.Percentage {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+func (r *FaultRule) match(ctx *zoox.Context) bool {
+	if len(r.Methods) > 0 {
+		matched := false
+		for _, method := range r.Methods {
+			if strings.EqualFold(method, ctx.Method) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.Paths) > 0 {
+		matched := false
+		for _, pattern := range r.Paths {
+			if matchPathPattern(pattern, ctx.Path) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for key, value := range r.Headers {
+		got := ctx.Header().Get(key)
+		if got == "" || (value != "*" && got != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchPathPattern(pattern, p string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return p == prefix || strings.HasPrefix(p, prefix+"/")
+	}
+
+	if pattern == p {
+		return true
+	}
+
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+func faultInjectionAdmin(ctx *zoox.Context, token string, rules *atomic.Pointer[[]*FaultRule]) {
+	if got, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch ctx.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		list := []*FaultRule{}
+		if err := ctx.BindJSON(&list); err != nil {
+			ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for i, rule := range list {
+			if rule.Percentage < 0 || rule.Percentage > 100 {
+				ctx.Fail(nil, http.StatusBadRequest, "percentage must be 0-100", http.StatusBadRequest)
+				return
+			}
+			if rule.Name == "" {
+				rule.Name = "rule#" + strconv.Itoa(i)
+			}
+		}
+
+		rules.Store(&list)
+		ctx.RequestLogger().Warnf("[middleware][fault_injection] rules updated: %d rules", len(list))
+	case http.MethodDelete:
+		rules.Store(&[]*FaultRule{})
+		ctx.RequestLogger().Infof("[middleware][fault_injection] rules cleared")
+	default:
+		ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx.Success(*rules.Load())
+}
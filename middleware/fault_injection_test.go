@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultInjectionRequiresAdminToken(t *testing.T) {
+	assert.Panics(t, func() {
+		FaultInjection()
+	})
+}
+
+func TestFaultInjectionAdmin(t *testing.T) {
+	cases := []struct {
+		name   string
+		cfg    *FaultInjectionConfig
+		auth   string
+		status int
+	}{
+		{"no token", &FaultInjectionConfig{Token: "secret"}, "", http.StatusUnauthorized},
+		{"wrong token", &FaultInjectionConfig{Token: "secret"}, "Bearer secreT", http.StatusUnauthorized},
+		{"token", &FaultInjectionConfig{Token: "secret"}, "Bearer secret", http.StatusOK},
+		{"disabled admin", &FaultInjectionConfig{DisableAdmin: true}, "Bearer secret", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		app := zoox.New()
+		app.Use(FaultInjection(c.cfg))
+
+		req := httptest.NewRequest(http.MethodGet, DefaultFaultInjectionAdminPath, nil)
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+	}
+}
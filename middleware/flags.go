@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/flags"
+)
+
+// DefaultFlagsAdminPath ...
+const DefaultFlagsAdminPath = "/_/flags"
+
+// FlagsAdminConfig ...
+type FlagsAdminConfig struct {
+	// Path is the prefix of admin api, default: /_/flags.
+	Path string
+	// Token is the bearer token to access the admin api, required.
+	Token string
+}
+
+// FlagsAdmin serves the admin api of feature flags for toggling at runtime:
+//
+//	GET    /_/flags       => list flags
+//	GET    /_/flags/:key  => get flag
+//	PUT    /_/flags/:key  => create or update flag
+//	DELETE /_/flags/:key  => delete flag
+func FlagsAdmin(cfg *FlagsAdminConfig) zoox.Middleware {
+	if cfg.Token == "" {
+		panic("flags admin token is required")
+	}
+
+	prefix := cfg.Path
+	if prefix == "" {
+		prefix = DefaultFlagsAdminPath
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return func(ctx *zoox.Context) {
+		if ctx.Path != prefix && !strings.HasPrefix(ctx.Path, prefix+"/") {
+			ctx.Next()
+			return
+		}
+
+		if token, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+			ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		f := ctx.App.Flags()
+		key := strings.TrimPrefix(strings.TrimPrefix(ctx.Path, prefix), "/")
+		if key == "" {
+			if ctx.Method != http.MethodGet {
+				ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			list, err := f.List()
+			if err != nil {
+				ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			ctx.Success(list)
+			return
+		}
+
+		switch ctx.Method {
+		case http.MethodGet:
+			flag, err := f.Get(key)
+			if err != nil {
+				if errors.Is(err, flags.ErrNotFound) {
+					ctx.Fail(err, http.StatusNotFound, err.Error(), http.StatusNotFound)
+					return
+				}
+
+				ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			ctx.Success(flag)
+		case http.MethodPut, http.MethodPost:
+			flag := &flags.Flag{}
+			if err := ctx.BindJSON(flag); err != nil {
+				ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+				return
+			}
+			flag.Key = key
+
+			if err := f.Set(flag); err != nil {
+				ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx.RequestLogger().Infof("[middleware][flags] flag(%s) updated: enabled=%v type=%s", flag.Key, flag.Enabled, flag.Type)
+			ctx.Success(flag)
+		case http.MethodDelete:
+			if err := f.Del(key); err != nil {
+				ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			ctx.RequestLogger().Infof("[middleware][flags] flag(%s) deleted", key)
+			ctx.Success(nil)
+		default:
+			ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/tiered"
+)
+
+// DefaultIdempotencyHeader is the default header of idempotency key.
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is the default ttl of stored responses.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// DefaultIdempotencyMaxBodySize is the default max size of the request body buffered for the fingerprint.
+const DefaultIdempotencyMaxBodySize = 1 << 20
+
+// IdempotencyConfig ...
+type IdempotencyConfig struct {
+	// Header is the header of idempotency key, default: Idempotency-Key.
+	Header string
+	// TTL is the duration the completed response is stored, default: 24h.
+	TTL time.Duration
+	// Methods are the methods to deduplicate, default: POST, PUT.
+	Methods []string
+	// Required responds 400 if the key is missing.
+	Required bool
+	// MaxBodySize is the max size of the request body with the key, which is buffered for the fingerprint,
+	// the larger body is responded 413, default: 1MB.
+	MaxBodySize int64
+	// ScopeFunc returns the caller of the request, so that the same key of other callers is not replayed,
+	// default: the Authorization and Cookie headers.
+	ScopeFunc func(ctx *zoox.Context) string
+}
+
+type idempotencyRecord struct {
+	Completed bool                `json:"completed"`
+	Status    int                 `json:"status"`
+	Header    map[string][]string `json:"header"`
+	Body      []byte              `json:"body"`
+	// Fingerprint is the hash of the request body, the same key with another body is rejected
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Idempotency stores responses of completed requests keyed by the Idempotency-Key header in the app cache,
+// and replays them on retries, concurrent duplicates get 409 Conflict,
+// the key reused with a different request body gets 422 Unprocessable Entity.
+// The keys are scoped by the caller (see IdempotencyConfig.ScopeFunc), method and path.
+//
+// It can be used globally with app.Use or per route:
+//
+//	app.Post("/payments", middleware.Idempotency(), createPayment)
+func Idempotency(cfg ...*IdempotencyConfig) zoox.Middleware {
+	cfgX := &IdempotencyConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	header := cfgX.Header
+	if header == "" {
+		header = DefaultIdempotencyHeader
+	}
+
+	ttl := cfgX.TTL
+	if ttl == 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	maxBodySize := cfgX.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultIdempotencyMaxBodySize
+	}
+
+	methods := map[string]bool{}
+	for _, method := range cfgX.Methods {
+		methods[method] = true
+	}
+	if len(methods) == 0 {
+		methods[http.MethodPost] = true
+		methods[http.MethodPut] = true
+	}
+
+	scopeFunc := cfgX.ScopeFunc
+	if scopeFunc == nil {
+		scopeFunc = func(ctx *zoox.Context) string {
+			return ctx.Header().Get(headers.Authorization) + "\n" + ctx.Header().Get(headers.Cookie)
+		}
+	}
+
+	// guards check-and-set of the same instance, the cache guards across instances
+	var mu sync.Mutex
+
+	return func(ctx *zoox.Context) {
+		if !methods[ctx.Method] {
+			ctx.Next()
+			return
+		}
+
+		key := ctx.Header().Get(header)
+		if key == "" {
+			if cfgX.Required {
+				ctx.Fail(nil, http.StatusBadRequest, header+" header is required", http.StatusBadRequest)
+				return
+			}
+
+			ctx.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				ctx.Fail(err, http.StatusRequestEntityTooLarge, "request body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			ctx.Fail(err, http.StatusBadRequest, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := sha256.Sum256(body)
+		cacheKey := idempotencyCacheKey(ctx, scopeFunc(ctx), key)
+		// the records are stored even if the client is gone, so that the retry is replayed
+		cache := tiered.WithContext(ctx.Cache(), context.WithoutCancel(ctx.Context()))
+
+		mu.Lock()
+		record := &idempotencyRecord{}
+		if err := cache.Get(cacheKey, record); err == nil {
+			mu.Unlock()
+
+			if record.Fingerprint != hex.EncodeToString(fingerprint[:]) {
+				ctx.Fail(nil, http.StatusUnprocessableEntity, "idempotency key is reused with a different request", http.StatusUnprocessableEntity)
+				return
+			}
+
+			if !record.Completed {
+				ctx.Fail(nil, http.StatusConflict, "request with the same idempotency key is in progress", http.StatusConflict)
+				return
+			}
+
+			for k, values := range record.Header {
+				for _, v := range values {
+					ctx.Writer.Header().Add(k, v)
+				}
+			}
+			ctx.SetHeader("Idempotent-Replayed", "true")
+			ctx.Data(record.Status, ctx.Writer.Header().Get(headers.ContentType), record.Body)
+			return
+		}
+
+		// mark in progress, expired by ttl if the process crashes
+		if err := cache.Set(cacheKey, &idempotencyRecord{Fingerprint: hex.EncodeToString(fingerprint[:])}, ttl); err != nil {
+			mu.Unlock()
+			ctx.RequestLogger().Errorf("[middleware][idempotency] failed to lock key(%s): %s", key, err)
+			ctx.Next()
+			return
+		}
+		mu.Unlock()
+
+		completed := false
+		defer func() {
+			// panic or not completed, allow retry
+			if !completed {
+				cache.Del(cacheKey)
+			}
+		}()
+
+		ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+			// server errors are not stored, so the client can retry
+			if status >= http.StatusInternalServerError {
+				return status, body
+			}
+
+			record := &idempotencyRecord{
+				Completed:   true,
+				Fingerprint: hex.EncodeToString(fingerprint[:]),
+				Status:      status,
+				Header:      map[string][]string{},
+				Body:        body,
+			}
+			for _, k := range []string{headers.ContentType, headers.Location, "ETag"} {
+				if values := ctx.Writer.Header().Values(k); len(values) > 0 {
+					record.Header[k] = values
+				}
+			}
+
+			if err := cache.Set(cacheKey, record, ttl); err != nil {
+				ctx.RequestLogger().Errorf("[middleware][idempotency] failed to store response of key(%s): %s", key, err)
+				return status, body
+			}
+
+			completed = true
+			return status, body
+		})
+	}
+}
+
+// idempotencyCacheKey scopes the key by caller, method and path, so the same key of other callers
+// or on different routes does not collide, and by the app namespace, so that the apps can share the cache.
+func idempotencyCacheKey(ctx *zoox.Context, scope string, key string) string {
+	hash := sha256.Sum256([]byte(scope + "\n" + ctx.Method + " " + ctx.Path + " " + key))
+	return ctx.App.Namespace("idempotency", hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIdempotencyApp(calls *atomic.Int32) *zoox.Application {
+	app := zoox.New()
+	app.Post("/payments", Idempotency(), func(ctx *zoox.Context) {
+		n := calls.Add(1)
+		ctx.JSON(http.StatusCreated, zoox.H{"call": n, "user": ctx.Header().Get("Authorization")})
+	})
+
+	return app
+}
+
+func postPayment(app *zoox.Application, authorization, key, body string) *httptest.ResponseRecorder {
+	return serve(app, http.MethodPost, "/payments", strings.NewReader(body),
+		"Content-Type", "application/json",
+		"Authorization", authorization,
+		DefaultIdempotencyHeader, key,
+	)
+}
+
+func TestIdempotencyReplaysRetry(t *testing.T) {
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls)
+
+	first := postPayment(app, "Bearer alice", "key-1", `{"amount":1}`)
+	retry := postPayment(app, "Bearer alice", "key-1", `{"amount":1}`)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyScopesByCaller(t *testing.T) {
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls)
+
+	postPayment(app, "Bearer alice", "key-1", `{"amount":1}`)
+	other := postPayment(app, "Bearer bob", "key-1", `{"amount":1}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Empty(t, other.Header().Get("Idempotent-Replayed"))
+	assert.Contains(t, other.Body.String(), "Bearer bob")
+}
+
+func TestIdempotencyRejectsDifferentBody(t *testing.T) {
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls)
+
+	postPayment(app, "Bearer alice", "key-1", `{"amount":1}`)
+	reused := postPayment(app, "Bearer alice", "key-1", `{"amount":100}`)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+}
+
+func TestIdempotencyLimitsBody(t *testing.T) {
+	var calls atomic.Int32
+	app := zoox.New()
+	app.Post("/payments", Idempotency(&IdempotencyConfig{MaxBodySize: 16}), func(ctx *zoox.Context) {
+		calls.Add(1)
+		ctx.Status(http.StatusCreated)
+	})
+
+	res := postPayment(app, "Bearer alice", "key-1", `{"amount":1}`)
+	assert.Equal(t, http.StatusCreated, res.Code)
+
+	res = postPayment(app, "Bearer alice", "key-2", `{"amount":1,"note":"too large"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// the body without the key is not buffered
+	res = serve(app, http.MethodPost, "/payments", strings.NewReader(`{"amount":1,"note":"too large"}`))
+	assert.Equal(t, http.StatusCreated, res.Code)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/go-zoox/zoox"
+)
+
+// DefaultLogLevelAdminPath ...
+const DefaultLogLevelAdminPath = "/_/log-level"
+
+// LogLevelAdminConfig ...
+type LogLevelAdminConfig struct {
+	// Path is the path of admin api, default: /_/log-level.
+	Path string
+	// Token is the bearer token to access the admin api, required.
+	Token string
+}
+
+// LogLevelAdmin serves the admin api to change the log levels at runtime:
+//
+//	GET /_/log-level  => {"level": "info", "modules": {"database": "debug"}}
+//	PUT /_/log-level  => {"level": "debug", "modules": {"database": "warn", "cache": ""}}
+//
+// An empty module level resets the module to follow the app level.
+func LogLevelAdmin(cfg *LogLevelAdminConfig) zoox.Middleware {
+	if cfg.Token == "" {
+		panic("log level admin token is required")
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = DefaultLogLevelAdminPath
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Path != path {
+			ctx.Next()
+			return
+		}
+
+		if token, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+			ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch ctx.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var body struct {
+				Level   string            `json:"level"`
+				Modules map[string]string `json:"modules"`
+			}
+			if err := ctx.BindJSON(&body); err != nil {
+				ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if body.Level != "" {
+				if err := ctx.App.SetLogLevel(body.Level); err != nil {
+					ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+
+			for module, level := range body.Modules {
+				if err := ctx.App.SetLogLevel(level, module); err != nil {
+					ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		default:
+			ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		ctx.Success(ctx.App.LogLevel())
+	}
+}
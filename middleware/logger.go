@@ -20,7 +20,21 @@ func Logger() zoox.Middleware {
 		ctx.Next()
 
 		if !ctx.IsConnectionUpgrade() {
-			logger.Info("[%s][<=] %s %s %d +%dms", ctx.Request.RemoteAddr, ctx.Method, ctx.Path, ctx.StatusCode(), time.Since(t)/time.Millisecond)
+			if tenant := defaultTenantFunc(ctx); tenant != "" {
+				logger.Info("[%s][<=] %s %s %d %dB +%dms (tenant: %s)", ctx.Request.RemoteAddr, ctx.Method, ctx.Path, ctx.Writer.Status(), responseSize(ctx), time.Since(t)/time.Millisecond, tenant)
+				return
+			}
+
+			logger.Info("[%s][<=] %s %s %d %dB +%dms", ctx.Request.RemoteAddr, ctx.Method, ctx.Path, ctx.Writer.Status(), responseSize(ctx), time.Since(t)/time.Millisecond)
 		}
 	}
 }
+
+// responseSize returns the bytes written into the response body.
+func responseSize(ctx *zoox.Context) int {
+	if !ctx.Writer.Written() {
+		return 0
+	}
+
+	return ctx.Writer.Size()
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/go-zoox/zoox"
+)
+
+// DefaultMaintenanceAdminPath ...
+const DefaultMaintenanceAdminPath = "/_/maintenance"
+
+// MaintenanceAdminConfig ...
+type MaintenanceAdminConfig struct {
+	// Path is the path of admin api, default: /_/maintenance.
+	Path string
+	// Token is the bearer token to access the admin api, required.
+	Token string
+}
+
+// MaintenanceAdmin serves the admin api to toggle maintenance mode at runtime,
+// it is a middleware, so it is available in maintenance mode:
+//
+//	GET /_/maintenance  => status
+//	PUT /_/maintenance  => {"enabled": true, "retry_after": 600, "message": "...", "allowlist": ["/healthz"]}
+func MaintenanceAdmin(cfg *MaintenanceAdminConfig) zoox.Middleware {
+	if cfg.Token == "" {
+		panic("maintenance admin token is required")
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = DefaultMaintenanceAdminPath
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Path != path {
+			ctx.Next()
+			return
+		}
+
+		if token, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+			ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch ctx.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var body struct {
+				Enabled bool `json:"enabled"`
+				// RetryAfter is in seconds
+				RetryAfter int      `json:"retry_after"`
+				Message    string   `json:"message"`
+				Allowlist  []string `json:"allowlist"`
+				AllowIPs   []string `json:"allow_ips"`
+			}
+			if err := ctx.BindJSON(&body); err != nil {
+				ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx.App.SetMaintenance(body.Enabled, func(opt *zoox.MaintenanceOption) {
+				opt.RetryAfter = time.Duration(body.RetryAfter) * time.Second
+				opt.Message = body.Message
+				opt.Allowlist = body.Allowlist
+				opt.AllowIPs = body.AllowIPs
+			})
+		default:
+			ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		ctx.Success(zoox.H{
+			"enabled": ctx.App.IsMaintenance(),
+			"option":  ctx.App.MaintenanceOption(),
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+
+	"github.com/go-zoox/zoox"
+)
+
+// serve serves the request with the app, the headers are given as key-value pairs.
+func serve(app *zoox.Application, method, target string, body io.Reader, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+	return recorder
+}
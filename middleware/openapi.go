@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/openapi"
+)
+
+// DefaultOpenAPIPath is the default path of the openapi document.
+const DefaultOpenAPIPath = "/openapi.json"
+
+// OpenAPIConfig ...
+type OpenAPIConfig struct {
+	// Path is the path of the document, default: /openapi.json
+	Path string
+}
+
+// OpenAPI serves the openapi document of the app routes (app.OpenAPI()),
+// with the route examples and the server of the request base url, used by zoox gen client and zoox mock.
+func OpenAPI(cfg ...*OpenAPIConfig) zoox.Middleware {
+	cfgX := &OpenAPIConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.Path == "" {
+		cfgX.Path = DefaultOpenAPIPath
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Method != http.MethodGet || ctx.Path != cfgX.Path {
+			ctx.Next()
+			return
+		}
+
+		doc := ctx.App.OpenAPI()
+		if len(doc.Servers) == 0 {
+			doc.Servers = []openapi.Server{{URL: ctx.BaseURL()}}
+		}
+
+		ctx.JSON(http.StatusOK, doc)
+	}
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/openapi"
+)
+
+// OpenAPIValidatorConfig ...
+type OpenAPIValidatorConfig struct {
+	// Document is the openapi document to validate against.
+	Document *openapi.Document
+	// ValidateResponse validates the json responses, invalid responses are replaced with 500.
+	ValidateResponse bool
+	// LogOnlyResponse logs the invalid responses instead of replacing them, used to roll out safely.
+	LogOnlyResponse bool
+	// AllowUnknownRoutes passes the requests not in the document, otherwise 404.
+	AllowUnknownRoutes bool
+}
+
+// OpenAPIValidator validates the requests (path, query, header and json body) against the openapi document,
+// invalid requests get 400 with json pointers of the errors:
+//
+//	{"code": 400, "message": "request validation failed", "errors": [{"pointer": "/body/name", "message": "is required"}]}
+//
+// Example:
+//
+//	doc, _ := openapi.Load("openapi.yaml")
+//	app.Use(middleware.OpenAPIValidator(&middleware.OpenAPIValidatorConfig{Document: doc}))
+func OpenAPIValidator(cfg *OpenAPIValidatorConfig) zoox.Middleware {
+	if cfg == nil || cfg.Document == nil {
+		panic("openapi validator: document is required")
+	}
+
+	doc := cfg.Document
+	router := openapi.NewRouter(doc)
+
+	return func(ctx *zoox.Context) {
+		route, params := router.Find(ctx.Method, ctx.Path)
+		if route == nil {
+			if cfg.AllowUnknownRoutes {
+				ctx.Next()
+				return
+			}
+
+			ctx.JSON(http.StatusNotFound, zoox.H{
+				"code":    http.StatusNotFound,
+				"message": "route is not defined in the openapi document",
+			})
+			return
+		}
+
+		var body any
+		hasBody := false
+		if route.Operation.RequestBody != nil && ctx.Request.Body != nil && ctx.Request.ContentLength != 0 {
+			if !strings.Contains(ctx.Header().Get(headers.ContentType), "json") {
+				ctx.JSON(http.StatusUnsupportedMediaType, zoox.H{
+					"code":    http.StatusUnsupportedMediaType,
+					"message": "request body must be json",
+				})
+				return
+			}
+
+			data, err := io.ReadAll(ctx.Request.Body)
+			if err != nil {
+				ctx.Fail(err, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			// restore for the handlers
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+			if len(data) > 0 {
+				hasBody = true
+				if err := json.Unmarshal(data, &body); err != nil {
+					respondValidationErrors(ctx, http.StatusBadRequest, "request validation failed", []*openapi.ValidationError{
+						{Pointer: "/body", Message: "invalid json: " + err.Error()},
+					})
+					return
+				}
+			}
+		}
+
+		if errs := doc.ValidateRequest(route.Method, params, ctx.Request.URL.Query(), ctx.Request.Header, body, hasBody); len(errs) > 0 {
+			respondValidationErrors(ctx, http.StatusBadRequest, "request validation failed", errs)
+			return
+		}
+
+		if !cfg.ValidateResponse {
+			ctx.Next()
+			return
+		}
+
+		ctx.CaptureResponse(func(status int, data []byte) (int, []byte) {
+			if !strings.Contains(ctx.Writer.Header().Get(headers.ContentType), "json") {
+				return status, data
+			}
+
+			schema, ok := route.Operation.ResponseSchema(status)
+			if !ok {
+				return responseValidationFailed(ctx, cfg, status, data, []*openapi.ValidationError{
+					{Pointer: "/status", Message: "status is not defined in the openapi document"},
+				})
+			}
+			if schema == nil || len(data) == 0 {
+				return status, data
+			}
+
+			var value any
+			if err := json.Unmarshal(data, &value); err != nil {
+				return responseValidationFailed(ctx, cfg, status, data, []*openapi.ValidationError{
+					{Pointer: "/body", Message: "invalid json: " + err.Error()},
+				})
+			}
+
+			if errs := doc.Validate(schema, value, "/body"); len(errs) > 0 {
+				return responseValidationFailed(ctx, cfg, status, data, errs)
+			}
+
+			return status, data
+		})
+	}
+}
+
+func respondValidationErrors(ctx *zoox.Context, status int, message string, errs []*openapi.ValidationError) {
+	ctx.JSON(status, zoox.H{
+		"code":    status,
+		"message": message,
+		"errors":  errs,
+	})
+}
+
+func responseValidationFailed(ctx *zoox.Context, cfg *OpenAPIValidatorConfig, status int, data []byte, errs []*openapi.ValidationError) (int, []byte) {
+	ctx.RequestLogger().Errorf("[middleware][openapi_validator] invalid response of %s %s (status: %d): %v", ctx.Method, ctx.Path, status, errs)
+	if cfg.LogOnlyResponse {
+		return status, data
+	}
+
+	body, _ := json.Marshal(zoox.H{
+		"code":    http.StatusInternalServerError,
+		"message": "response validation failed",
+		"errors":  errs,
+	})
+	return http.StatusInternalServerError, body
+}
@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/go-zoox/zoox"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -11,8 +15,17 @@ const DefaultPrometheus = "/metrics"
 // PrometheusOption ...
 type PrometheusOption struct {
 	Path string
+	// DisableConnectionMetrics disables the server connection state metrics.
+	DisableConnectionMetrics bool
+	// Registry registers the connection metrics and is served at Path, default prometheus.DefaultRegisterer.
+	// The connection metrics of the default registry are registered once in the process, by the first app serving.
+	// Set a registry for each app to collect the metrics of multiple apps.
+	Registry *prometheus.Registry
 }
 
+// defaultConnStatsOnce registers the connection metrics into the default registry once.
+var defaultConnStatsOnce sync.Once
+
 // Prometheus ...
 func Prometheus(opts ...func(opt *PrometheusOption)) zoox.Middleware {
 	opt := &PrometheusOption{
@@ -22,12 +35,79 @@ func Prometheus(opts ...func(opt *PrometheusOption)) zoox.Middleware {
 		o(opt)
 	}
 
+	var registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	registerOnce := &defaultConnStatsOnce
+	handler := promhttp.Handler()
+	if opt.Registry != nil {
+		registerer = opt.Registry
+		registerOnce = &sync.Once{}
+		handler = promhttp.HandlerFor(opt.Registry, promhttp.HandlerOpts{})
+	}
+
 	return func(ctx *zoox.Context) {
+		if !opt.DisableConnectionMetrics {
+			registerOnce.Do(func() {
+				registerConnStats(ctx, registerer)
+			})
+		}
+
 		if ctx.Path == opt.Path {
-			promhttp.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+			handler.ServeHTTP(ctx.Writer, ctx.Request)
 			return
 		}
 
 		ctx.Next()
 	}
 }
+
+// registerConnStats registers the connection metrics of the app,
+// the registry shared by the middlewares of the app is registered once.
+func registerConnStats(ctx *zoox.Context, registerer prometheus.Registerer) {
+	err := registerer.Register(newConnStatsCollector(ctx.App))
+	if err == nil || errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return
+	}
+
+	ctx.RequestLogger().Warnf("[middleware][prometheus] failed to register connection metrics: %s", err)
+}
+
+// connStatsCollector collects the server connection stats of the app.
+type connStatsCollector struct {
+	app *zoox.Application
+	//
+	connections *prometheus.Desc
+	total       *prometheus.Desc
+}
+
+func newConnStatsCollector(app *zoox.Application) *connStatsCollector {
+	return &connStatsCollector{
+		app: app,
+		connections: prometheus.NewDesc(
+			"zoox_connections",
+			"Current server connections by state.",
+			[]string{"state"}, nil,
+		),
+		total: prometheus.NewDesc(
+			"zoox_connections_total",
+			"Total server connections by state transition.",
+			[]string{"state"}, nil,
+		),
+	}
+}
+
+func (c *connStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connections
+	ch <- c.total
+}
+
+func (c *connStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.app.ConnStats()
+
+	ch <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue, float64(stats.New), "new")
+	ch <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue, float64(stats.Active), "active")
+	ch <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue, float64(stats.Idle), "idle")
+
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.CounterValue, float64(stats.Accepted), "accepted")
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.CounterValue, float64(stats.Hijacked), "hijacked")
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.CounterValue, float64(stats.Closed), "closed")
+}
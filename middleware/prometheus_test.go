@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusRegistryPerApp(t *testing.T) {
+	newApp := func(registry *prometheus.Registry) *zoox.Application {
+		app := zoox.New()
+		app.Use(Prometheus(func(opt *PrometheusOption) {
+			opt.Registry = registry
+		}))
+		return app
+	}
+
+	registry := prometheus.NewRegistry()
+	first := newApp(registry)
+	// the second instance on the same registry is not registered twice
+	second := newApp(registry)
+	other := newApp(prometheus.NewRegistry())
+
+	for _, app := range []*zoox.Application{first, second, other} {
+		res := serve(app, http.MethodGet, DefaultPrometheus, nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, strings.Count(res.Body.String(), `zoox_connections{state="active"}`), res.Body.String())
+	}
+}
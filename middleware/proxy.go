@@ -31,7 +31,7 @@ func Proxy(fn func(ctx *zoox.Context, cfg *ProxyConfig) (next, stop bool, err er
 		cfg := &ProxyConfig{}
 		next, stop, err := fn(ctx, cfg)
 		if err != nil {
-			ctx.Logger.Errorf("[middleware.proxy] proxy error: %#v", err)
+			ctx.RequestLogger().Errorf("[middleware.proxy] proxy error: %#v", err)
 			if v, ok := err.(*proxy.HTTPError); ok {
 				html := v.Error()
 				switch v.Status() {
@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/quota"
+)
+
+// DefaultQuotaAdminPath ...
+const DefaultQuotaAdminPath = "/_/quotas"
+
+// DefaultQuotaKeyHeader is the default header of the api key.
+const DefaultQuotaKeyHeader = "X-API-Key"
+
+// DefaultQuotaExhaustedEvent is the webhook event emitted when a quota is exhausted.
+const DefaultQuotaExhaustedEvent = "quota.exhausted"
+
+// QuotaConfig ...
+type QuotaConfig struct {
+	// Limits are the default daily/monthly limits of one api key, overridden by the admin api.
+	Limits quota.Limits
+
+	// Cost is the cost of the requests, default: 1.
+	//	Declare it per route with a dedicated middleware sharing the same Namespace:
+	//	app.Post("/reports", middleware.Quota(&middleware.QuotaConfig{Limits: limits, Cost: 10, DisableAdmin: true}), handler)
+	Cost int64
+	// Costs are the costs of routes, keyed by "METHOD /path" or "/path", supports glob (/users/*) and prefix (/api/**).
+	Costs map[string]int64
+
+	// KeyHeader is the header of the api key, default: X-API-Key.
+	KeyHeader string
+	// KeyFunc resolves the api key of the request, default: the KeyHeader header.
+	KeyFunc func(ctx *zoox.Context) string
+	// Required responds 401 if the api key is missing, otherwise the request is not accounted.
+	Required bool
+
+	// Namespace is the key prefix of counters, default: app.Namespace("quota").
+	Namespace string
+	// Store is the counter store, default: redis if app redis is configured, otherwise memory.
+	Store quota.Store
+
+	// ExhaustedEvent is the webhook event emitted (with app.Webhooks) the first time a quota is exhausted in the window,
+	//	default: quota.exhausted.
+	ExhaustedEvent string
+	// OnExhausted is called the first time a quota is exhausted in the window.
+	OnExhausted func(ctx *zoox.Context, usage *quota.Usage)
+
+	// AdminPath is the prefix of the admin api, default: /_/quotas.
+	AdminPath string
+	// Token is the bearer token to access the admin api, required unless DisableAdmin.
+	Token string
+	// DisableAdmin disables the admin api, such as on the middlewares declaring route costs.
+	DisableAdmin bool
+}
+
+// quotaStores shares the default memory store of the namespace across the middlewares declaring route costs.
+var quotaStores sync.Map
+
+// Quota accounts the cost of requests per api key in daily and monthly windows,
+// the remaining quota is responded in X-Quota-Limit / X-Quota-Remaining / X-Quota-Reset,
+// 429 is responded once the quota is exhausted.
+//
+// Quotas of api keys can be adjusted at runtime with the admin api (unless DisableAdmin):
+//
+//	GET    /_/quotas/:key         => usage and limits
+//	PUT    /_/quotas/:key         => override limits, {"daily": 1000, "monthly": 20000}
+//	DELETE /_/quotas/:key/limits  => restore the default limits
+//	DELETE /_/quotas/:key/usage   => reset the usage of the current windows
+func Quota(cfg *QuotaConfig) zoox.Middleware {
+	if cfg.Token == "" && !cfg.DisableAdmin {
+		panic("quota admin token is required, or set DisableAdmin")
+	}
+
+	var once sync.Once
+	var q *quota.Quota
+
+	keyHeader := cfg.KeyHeader
+	if keyHeader == "" {
+		keyHeader = DefaultQuotaKeyHeader
+	}
+
+	adminPath := strings.TrimSuffix(cfg.AdminPath, "/")
+	if adminPath == "" {
+		adminPath = DefaultQuotaAdminPath
+	}
+
+	event := cfg.ExhaustedEvent
+	if event == "" {
+		event = DefaultQuotaExhaustedEvent
+	}
+
+	return func(ctx *zoox.Context) {
+		// namespace and redis are derived from the app, which is known on the first request
+		once.Do(func() {
+			q = newQuota(ctx.App, cfg)
+		})
+
+		if !cfg.DisableAdmin && strings.HasPrefix(ctx.Path, adminPath+"/") {
+			quotaAdmin(ctx, cfg.Token, adminPath, q)
+			return
+		}
+
+		key := ""
+		if cfg.KeyFunc != nil {
+			key = cfg.KeyFunc(ctx)
+		} else {
+			key = ctx.Header().Get(keyHeader)
+		}
+		if key == "" {
+			if cfg.Required {
+				ctx.Fail(nil, http.StatusUnauthorized, keyHeader+" header is required", http.StatusUnauthorized)
+				return
+			}
+
+			ctx.Next()
+			return
+		}
+
+		cost := quotaCost(ctx, cfg)
+		if cost <= 0 {
+			ctx.Next()
+			return
+		}
+
+		usage, ok, err := q.Consume(key, cost)
+		if err != nil {
+			// fail open, the quota store should not take down the api
+			ctx.RequestLogger().Errorf("[middleware][quota] failed to consume quota of key(%s): %s", key, err)
+			ctx.Next()
+			return
+		}
+
+		ctx.SetHeader("X-Quota-Cost", strconv.FormatInt(cost, 10))
+		setQuotaHeaders(ctx, usage)
+
+		if usage.Exhausted != "" {
+			ctx.RequestLogger().Warnf("[middleware][quota] %s quota of key(%s) exhausted", usage.Exhausted, key)
+
+			if cfg.OnExhausted != nil {
+				cfg.OnExhausted(ctx, usage)
+			}
+
+			if err := ctx.App.Webhooks().Emit(event, usage); err != nil {
+				ctx.RequestLogger().Errorf("[middleware][quota] failed to emit %s: %s", event, err)
+			}
+		}
+
+		if !ok {
+			ctx.Fail(errors.New("quota exceeded"), http.StatusTooManyRequests, "Quota Exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// setQuotaHeaders responds the tightest period of the usage in X-Quota-Limit / X-Quota-Remaining / X-Quota-Reset.
+func setQuotaHeaders(ctx *zoox.Context, usage *quota.Usage) {
+	if tightest := usage.Tightest(); tightest != nil {
+		ctx.SetHeader("X-Quota-Limit", strconv.FormatInt(tightest.Limit, 10))
+		ctx.SetHeader("X-Quota-Remaining", strconv.FormatInt(tightest.Remaining(), 10))
+		ctx.SetHeader("X-Quota-Reset", strconv.FormatInt(tightest.ResetAt.Unix(), 10))
+	}
+}
+
+func newQuota(app *zoox.Application, cfg *QuotaConfig) *quota.Quota {
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = app.Namespace("quota")
+	}
+
+	store := cfg.Store
+	if store == nil {
+		if app.Config.Redis.Host != "" {
+			store = quota.NewRedis(&quota.RedisConfig{
+				Host:     app.Config.Redis.Host,
+				Port:     app.Config.Redis.Port,
+				DB:       app.Config.Redis.DB,
+				Username: app.Config.Redis.Username,
+				Password: app.Config.Redis.Password,
+			})
+		} else {
+			shared, _ := quotaStores.LoadOrStore(namespace, quota.NewMemory())
+			store = shared.(quota.Store)
+		}
+	}
+
+	return quota.New(&quota.Config{
+		Namespace: namespace,
+		Limits:    cfg.Limits,
+		Store:     store,
+	})
+}
+
+// quotaCost returns the cost of the request, the longest matched pattern in Costs wins.
+func quotaCost(ctx *zoox.Context, cfg *QuotaConfig) int64 {
+	matched := ""
+	cost := cfg.Cost
+	if cost == 0 {
+		cost = 1
+	}
+
+	for pattern, c := range cfg.Costs {
+		method, p, ok := strings.Cut(pattern, " ")
+		if !ok {
+			method, p = "", pattern
+		}
+
+		if (method == "" || strings.EqualFold(method, ctx.Method)) && matchPathPattern(strings.TrimSpace(p), ctx.Path) && len(pattern) > len(matched) {
+			matched, cost = pattern, c
+		}
+	}
+
+	return cost
+}
+
+func quotaAdmin(ctx *zoox.Context, token, prefix string, q *quota.Quota) {
+	if got, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(ctx.Path, prefix), "/"), "/")
+	key := parts[0]
+	if key == "" {
+		ctx.Fail(nil, http.StatusNotFound, "not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && ctx.Method == http.MethodGet:
+	case len(parts) == 1 && ctx.Method == http.MethodPut:
+		limits := &quota.Limits{}
+		if err := ctx.BindJSON(limits); err != nil {
+			ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := q.SetLimits(key, limits); err != nil {
+			ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx.RequestLogger().Infof("[middleware][quota] limits of key(%s) set to daily=%d monthly=%d", key, limits.Daily, limits.Monthly)
+	case len(parts) == 2 && parts[1] == "limits" && ctx.Method == http.MethodDelete:
+		if err := q.SetLimits(key, nil); err != nil {
+			ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx.RequestLogger().Infof("[middleware][quota] limits of key(%s) restored", key)
+	case len(parts) == 2 && parts[1] == "usage" && ctx.Method == http.MethodDelete:
+		if err := q.Reset(key); err != nil {
+			ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx.RequestLogger().Infof("[middleware][quota] usage of key(%s) reset", key)
+	default:
+		ctx.Fail(nil, http.StatusNotFound, "not found", http.StatusNotFound)
+		return
+	}
+
+	usage, err := q.Usage(key)
+	if err != nil {
+		ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Success(usage)
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaRequiresAdminToken(t *testing.T) {
+	assert.Panics(t, func() {
+		Quota(&QuotaConfig{Limits: quota.Limits{Daily: 10}})
+	})
+}
+
+func TestQuotaAdmin(t *testing.T) {
+	cases := []struct {
+		name   string
+		cfg    *QuotaConfig
+		auth   string
+		status int
+	}{
+		{"no token", &QuotaConfig{Namespace: "admin-1", Token: "secret"}, "", http.StatusUnauthorized},
+		{"wrong token", &QuotaConfig{Namespace: "admin-2", Token: "secret"}, "Bearer secreT", http.StatusUnauthorized},
+		{"token", &QuotaConfig{Namespace: "admin-3", Token: "secret"}, "Bearer secret", http.StatusOK},
+		{"disabled admin", &QuotaConfig{Namespace: "admin-4", DisableAdmin: true}, "Bearer secret", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		c.cfg.Limits = quota.Limits{Daily: 10}
+
+		app := zoox.New()
+		app.Use(Quota(c.cfg))
+
+		req := httptest.NewRequest(http.MethodGet, DefaultQuotaAdminPath+"/k1", nil)
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+	}
+}
@@ -17,12 +17,17 @@ type RateLimitConfig struct {
 	Period time.Duration
 	Limit  int64
 	//
+	// Namespace is the key prefix of counters, such as app.Namespace("ratelimit"), default: go-zoox.
 	Namespace string
 	//
 	RedisHost     string
 	RedisPort     int
 	RedisDB       int
 	RedisPassword string
+	//
+	// Gossip enables the distributed rate limit without redis,
+	//	instances exchange counter deltas via pubsub to approximate the global limit.
+	Gossip *RateLimitGossipConfig
 }
 
 // RateLimit middleware for zoox
@@ -32,6 +37,10 @@ func RateLimit(cfg *RateLimitConfig) zoox.Middleware {
 		namespace = "go-zoox"
 	}
 
+	if cfg.RedisHost == "" && cfg.Gossip != nil {
+		return rateLimitWithGossip(namespace, cfg)
+	}
+
 	var limiter *ratelimit.RateLimit
 	var err error
 	if cfg.RedisHost != "" {
@@ -50,16 +59,16 @@ func RateLimit(cfg *RateLimitConfig) zoox.Middleware {
 	}
 
 	return func(ctx *zoox.Context) {
-		ip := ctx.Request.RemoteAddr
+		ip := rateLimitKey(ctx)
 		limiter.Inc(ip)
 
 		// GitHub Standard
-		ctx.Set(headers.XRateLimitRemaining, fmt.Sprintf("%d", limiter.Remaining(ip)))
-		ctx.Set(headers.XRateLimitReset, fmt.Sprintf("%d", limiter.ResetAt(ip)/1000))
-		ctx.Set(headers.XRateLimitLimit, fmt.Sprintf("%d", limiter.Total(ip)))
+		ctx.SetHeader(headers.XRateLimitRemaining, fmt.Sprintf("%d", limiter.Remaining(ip)))
+		ctx.SetHeader(headers.XRateLimitReset, fmt.Sprintf("%d", limiter.ResetAt(ip)/1000))
+		ctx.SetHeader(headers.XRateLimitLimit, fmt.Sprintf("%d", limiter.Total(ip)))
 
 		// MDN
-		ctx.Set(headers.RetryAfter, fmt.Sprintf("%d", limiter.ResetAfter(ip)))
+		ctx.SetHeader(headers.RetryAfter, fmt.Sprintf("%d", limiter.ResetAfter(ip)))
 
 		if limiter.IsExceeded(ip) {
 			ctx.Fail(errors.New("too many requests"), http.StatusTooManyRequests, "Too Many Requests", http.StatusTooManyRequests)
@@ -69,3 +78,13 @@ func RateLimit(cfg *RateLimitConfig) zoox.Middleware {
 		ctx.Next()
 	}
 }
+
+// rateLimitKey returns the client ip, scoped with the tenant if verified by the tenancy loader,
+// the unverified tenant ids are supplied by the client, which would get a fresh counter for each id.
+func rateLimitKey(ctx *zoox.Context) string {
+	if tenant := ctx.Tenant(); tenant != nil && tenant.Verified() {
+		return tenant.Key(ctx.Request.RemoteAddr)
+	}
+
+	return ctx.Request.RemoteAddr
+}
@@ -0,0 +1,288 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/random"
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/pubsub"
+)
+
+// RateLimitGossipConfig is the config of gossip mode rate limit.
+//
+// In gossip mode, every instance counts locally and periodically publishes its counter deltas
+// via PubSub, the other instances merge them to approximate the global limit.
+// The error is bounded by the requests the peers accept in one SyncInterval.
+//
+// When publishing fails or the known peers stop talking (network partition),
+// it falls back to local limiting with a fair share of the limit (Limit / instances).
+type RateLimitGossipConfig struct {
+	// PubSub is used to exchange the counter deltas between instances.
+	PubSub pubsub.PubSub
+	// Topic is the pubsub topic, default: go-zoox:ratelimit:<namespace>.
+	Topic string
+	// SyncInterval is the interval to publish local deltas, default: 1s.
+	SyncInterval time.Duration
+	// PeerTimeout is the duration after which a silent peer is considered lost, default: 3 * SyncInterval.
+	PeerTimeout time.Duration
+}
+
+type rateLimitGossipMessage struct {
+	ID     string           `json:"id"`
+	Window int64            `json:"window"`
+	Deltas map[string]int64 `json:"deltas"`
+}
+
+type rateLimitGossip struct {
+	sync.Mutex
+	//
+	id     string
+	topic  string
+	period time.Duration
+	limit  int64
+	cfg    *RateLimitGossipConfig
+	//
+	window  int64
+	local   map[string]int64
+	pending map[string]int64
+	remote  map[string]int64
+	//
+	peers         map[string]time.Time
+	publishFailed bool
+	//
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newRateLimitGossip(namespace string, period time.Duration, limit int64, cfg *RateLimitGossipConfig) *rateLimitGossip {
+	if cfg.PubSub == nil {
+		panic(errors.New("failed to create ratelimit middleware: pubsub is required for gossip mode"))
+	}
+
+	// the defaults are not written back to the config of the caller, which may be shared
+	cfgX := *cfg
+
+	topic := cfgX.Topic
+	if topic == "" {
+		topic = fmt.Sprintf("go-zoox:ratelimit:%s", namespace)
+	}
+
+	if cfgX.SyncInterval == 0 {
+		cfgX.SyncInterval = time.Second
+	}
+
+	if cfgX.PeerTimeout == 0 {
+		cfgX.PeerTimeout = 3 * cfgX.SyncInterval
+	}
+
+	g := &rateLimitGossip{
+		id:      random.String(16),
+		topic:   topic,
+		period:  period,
+		limit:   limit,
+		cfg:     &cfgX,
+		local:   map[string]int64{},
+		pending: map[string]int64{},
+		remote:  map[string]int64{},
+		peers:   map[string]time.Time{},
+	}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+
+	return g
+}
+
+// start subscribes the gossip topic and publishes the deltas until stop.
+func (g *rateLimitGossip) start() {
+	go func() {
+		if err := g.cfg.PubSub.Subscribe(g.ctx, g.topic, func(msg *pubsub.Message) error {
+			// a malformed message must not end the subscription
+			if err := g.receive(msg.Body); err != nil {
+				logger.Warnf("[middleware][ratelimit] skip gossip message: %s", err)
+			}
+
+			return nil
+		}); err != nil && g.ctx.Err() == nil {
+			logger.Errorf("[middleware][ratelimit] failed to subscribe gossip topic(%s): %s", g.topic, err)
+		}
+	}()
+
+	go g.loop()
+}
+
+func (g *rateLimitGossip) stop(ctx context.Context) error {
+	g.cancel()
+	return nil
+}
+
+func (g *rateLimitGossip) loop() {
+	ticker := time.NewTicker(g.cfg.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-ticker.C:
+			g.publish()
+		}
+	}
+}
+
+func (g *rateLimitGossip) publish() {
+	g.Lock()
+	g.rotate(time.Now())
+	message := &rateLimitGossipMessage{
+		ID:     g.id,
+		Window: g.window,
+		Deltas: g.pending,
+	}
+	g.pending = map[string]int64{}
+	g.Unlock()
+
+	body, err := json.Marshal(message)
+	if err == nil {
+		err = g.cfg.PubSub.Publish(g.ctx, &pubsub.Message{
+			Topic: g.topic,
+			Body:  body,
+		})
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	g.publishFailed = err != nil
+	if err != nil {
+		logger.Warnf("[middleware][ratelimit] failed to publish gossip deltas, fallback to local limit: %s", err)
+
+		// keep the deltas for next round
+		if message.Window == g.window {
+			for key, delta := range message.Deltas {
+				g.pending[key] += delta
+			}
+		}
+	}
+}
+
+func (g *rateLimitGossip) receive(body []byte) error {
+	message := &rateLimitGossipMessage{}
+	if err := json.Unmarshal(body, message); err != nil {
+		return fmt.Errorf("failed to decode ratelimit gossip message: %v", err)
+	}
+
+	if message.ID == g.id {
+		return nil
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	now := time.Now()
+	g.peers[message.ID] = now
+	g.rotate(now)
+	if message.Window != g.window {
+		return nil
+	}
+
+	for key, delta := range message.Deltas {
+		g.remote[key] += delta
+	}
+
+	return nil
+}
+
+// rotate resets the counters when entering a new window, must be called with lock.
+func (g *rateLimitGossip) rotate(now time.Time) {
+	window := now.Truncate(g.period).UnixMilli()
+	if window == g.window {
+		return
+	}
+
+	g.window = window
+	g.local = map[string]int64{}
+	g.pending = map[string]int64{}
+	g.remote = map[string]int64{}
+
+	// forget the peers lost for a long time
+	for id, seen := range g.peers {
+		if now.Sub(seen) > 10*g.cfg.PeerTimeout {
+			delete(g.peers, id)
+		}
+	}
+}
+
+// degraded returns whether the gossip is unreliable, must be called with lock.
+func (g *rateLimitGossip) degraded(now time.Time) bool {
+	if g.publishFailed {
+		return true
+	}
+
+	for _, seen := range g.peers {
+		if now.Sub(seen) > g.cfg.PeerTimeout {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Inc increases the counter of key, returns the used count and the effective limit.
+func (g *rateLimitGossip) Inc(key string) (used, limit int64, resetAt time.Time) {
+	g.Lock()
+	defer g.Unlock()
+
+	now := time.Now()
+	g.rotate(now)
+
+	g.local[key]++
+	g.pending[key]++
+
+	resetAt = time.UnixMilli(g.window).Add(g.period)
+	if g.degraded(now) {
+		// fair share of the limit
+		return g.local[key], g.limit / int64(len(g.peers)+1), resetAt
+	}
+
+	return g.local[key] + g.remote[key], g.limit, resetAt
+}
+
+func rateLimitWithGossip(namespace string, cfg *RateLimitConfig) zoox.Middleware {
+	limiter := newRateLimitGossip(namespace, cfg.Period, cfg.Limit, cfg.Gossip)
+
+	// the gossip runs with the app, which is known on the first request
+	var once sync.Once
+	return func(ctx *zoox.Context) {
+		once.Do(func() {
+			limiter.start()
+			ctx.App.OnShutdown("ratelimit", limiter.stop)
+		})
+
+		used, limit, resetAt := limiter.Inc(rateLimitKey(ctx))
+
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		// GitHub Standard
+		ctx.SetHeader(headers.XRateLimitRemaining, fmt.Sprintf("%d", remaining))
+		ctx.SetHeader(headers.XRateLimitReset, fmt.Sprintf("%d", resetAt.Unix()))
+		ctx.SetHeader(headers.XRateLimitLimit, fmt.Sprintf("%d", limit))
+
+		// MDN
+		ctx.SetHeader(headers.RetryAfter, fmt.Sprintf("%d", int64(time.Until(resetAt).Seconds())))
+
+		if used > limit {
+			ctx.Fail(errors.New("too many requests"), http.StatusTooManyRequests, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+// gossipPubSub blocks in Subscribe like redis, and exposes the handler.
+type gossipPubSub struct {
+	handler chan pubsub.Handler
+	stopped chan struct{}
+}
+
+func (p *gossipPubSub) Publish(ctx context.Context, msg *pubsub.Message) error {
+	return nil
+}
+
+func (p *gossipPubSub) Subscribe(ctx context.Context, topic string, handler pubsub.Handler) error {
+	p.handler <- handler
+	<-ctx.Done()
+	close(p.stopped)
+	return ctx.Err()
+}
+
+func TestRateLimitGossip(t *testing.T) {
+	ps := &gossipPubSub{handler: make(chan pubsub.Handler, 1), stopped: make(chan struct{})}
+
+	app := zoox.New()
+	app.Use(RateLimit(&RateLimitConfig{
+		Namespace: "test",
+		Period:    time.Minute,
+		Limit:     2,
+		Gossip:    &RateLimitGossipConfig{PubSub: ps, SyncInterval: time.Hour},
+	}))
+	app.Get("/", func(ctx *zoox.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		app.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	var handler pubsub.Handler
+	select {
+	case handler = <-ps.handler:
+	case <-time.After(time.Second):
+		t.Fatal("gossip is not subscribed")
+	}
+
+	// a malformed message is skipped instead of ending the subscription
+	assert.Nil(t, handler(&pubsub.Message{Body: []byte("{")}))
+
+	body, _ := json.Marshal(&rateLimitGossipMessage{
+		ID:     "peer",
+		Window: time.Now().Truncate(time.Minute).UnixMilli(),
+		Deltas: map[string]int64{"10.0.0.1:1234": 5},
+	})
+	assert.Nil(t, handler(&pubsub.Message{Body: body}))
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+
+	// the gossip stops with the app
+	assert.Nil(t, app.Shutdown(context.Background()))
+	select {
+	case <-ps.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("gossip is not stopped on shutdown")
+	}
+}
+
+func TestRateLimitGossipKeepsConfig(t *testing.T) {
+	cfg := &RateLimitGossipConfig{PubSub: &gossipPubSub{}}
+	g := newRateLimitGossip("test", time.Minute, 2, cfg)
+
+	assert.Equal(t, time.Second, g.cfg.SyncInterval)
+	assert.Equal(t, 3*time.Second, g.cfg.PeerTimeout)
+	assert.Equal(t, &RateLimitGossipConfig{PubSub: cfg.PubSub}, cfg)
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/tenancy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitIgnoresSpoofedTenants(t *testing.T) {
+	app := zoox.New()
+	app.SetTenancy(tenancy.New(&tenancy.Config{
+		Resolvers: []tenancy.Resolver{tenancy.FromHeader(tenancy.DefaultHeader)},
+		Loader: func(id string) (any, error) {
+			if id != "acme" {
+				return nil, tenancy.ErrNotFound
+			}
+
+			return nil, nil
+		},
+	}))
+	app.Use(RateLimit(&RateLimitConfig{
+		Namespace: "test",
+		Period:    time.Minute,
+		Limit:     2,
+	}))
+	app.Get("/", func(ctx *zoox.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	serve := func(tenant string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(tenancy.DefaultHeader, tenant)
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	// a fresh tenant id per request must not reset the counter
+	cases := []struct {
+		tenant string
+		status int
+	}{
+		{"spoofed-1", http.StatusOK},
+		{"spoofed-2", http.StatusOK},
+		{"spoofed-3", http.StatusTooManyRequests},
+		{"a:b", http.StatusTooManyRequests},
+		// the verified tenant has its own counter
+		{"acme", http.StatusOK},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.status, serve(c.tenant), c.tenant)
+	}
+}
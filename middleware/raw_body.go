@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/go-zoox/zoox"
+)
+
+// RawBodyConfig ...
+type RawBodyConfig struct {
+	// MaxSize is the max size of the retained raw body, default zoox.DefaultRawBodyMaxSize (1MB).
+	MaxSize int64
+}
+
+// RawBody enables ctx.RawBody for the routes, such as webhook signature verification,
+// the body is retained while it is read, so ctx.RawBody and ctx.Bind* work on the same request.
+//
+// Example:
+//
+//	webhooks := app.Group("/webhooks")
+//	webhooks.Use(middleware.RawBody())
+func RawBody(cfg ...*RawBodyConfig) zoox.Middleware {
+	cfgX := &RawBodyConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	return func(ctx *zoox.Context) {
+		ctx.EnableRawBody(cfgX.MaxSize)
+
+		ctx.Next()
+	}
+}
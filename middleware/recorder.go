@@ -0,0 +1,363 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/random"
+	"github.com/go-zoox/zoox"
+)
+
+// DefaultRecorderPath is the default path of recorder api.
+const DefaultRecorderPath = "/_/recorder"
+
+// DefaultRecorderCapacity is the default capacity of recorder ring buffer.
+const DefaultRecorderCapacity = 100
+
+// DefaultRecorderMaxBodySize is the default max body size to record.
+const DefaultRecorderMaxBodySize = 64 * 1024
+
+// recorderReplayKey marks the context of the replayed request, which is not recorded,
+// it is internal so that clients can't disable the recording.
+type recorderReplayKey struct{}
+
+var defaultRecorderRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// RecorderConfig ...
+type RecorderConfig struct {
+	// Path is the prefix of recorder api, default: /_/recorder.
+	Path string
+	// Token is the bearer token to access the recorder api, required.
+	Token string
+	// Capacity is the size of ring buffer, default: 100.
+	Capacity int
+	// File appends the records as json lines, optional.
+	File string
+	// MaxBodySize is the max request/response body size to record, default: 64KB.
+	MaxBodySize int
+	// RedactHeaders are the headers to redact, default: Authorization, Cookie, Set-Cookie, Proxy-Authorization.
+	RedactHeaders []string
+	// RedactFields are the json body fields to redact, such as password.
+	RedactFields []string
+	// Always records in non-debug mode, default only in debug mode.
+	Always bool
+}
+
+// RecordedRequest is a recorded request/response pair.
+type RecordedRequest struct {
+	ID        string        `json:"id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Duration  time.Duration `json:"duration"`
+	//
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header"`
+	RequestBody   string      `json:"request_body,omitempty"`
+	//
+	Status         int         `json:"status"`
+	ResponseHeader http.Header `json:"response_header"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+	//
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type recorder struct {
+	sync.Mutex
+	cfg     *RecorderConfig
+	records []*RecordedRequest
+	next    int
+	//
+	file *os.File
+	//
+	redactHeaders map[string]bool
+	redactFields  map[string]bool
+}
+
+// Recorder captures full request/response pairs in debug mode into a ring buffer (and file),
+// with body size caps and redaction rules, for troubleshooting.
+//
+// The api is served under the path:
+//
+//	GET  /_/recorder             => list records
+//	GET  /_/recorder/:id         => get record
+//	POST /_/recorder/:id/replay  => replay the request against the current handler chain
+//
+// The api requires the bearer token (RecorderConfig.Token), as the records contain the request and response bodies.
+// Redacted values are replayed as is, so replaying authorized requests requires passing them again.
+func Recorder(cfg ...*RecorderConfig) zoox.Middleware {
+	cfgX := &RecorderConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	if cfgX.Token == "" {
+		panic("recorder token is required")
+	}
+
+	if cfgX.Path == "" {
+		cfgX.Path = DefaultRecorderPath
+	}
+	cfgX.Path = strings.TrimSuffix(cfgX.Path, "/")
+	if cfgX.Capacity <= 0 {
+		cfgX.Capacity = DefaultRecorderCapacity
+	}
+	if cfgX.MaxBodySize <= 0 {
+		cfgX.MaxBodySize = DefaultRecorderMaxBodySize
+	}
+	if cfgX.RedactHeaders == nil {
+		cfgX.RedactHeaders = defaultRecorderRedactHeaders
+	}
+
+	r := &recorder{
+		cfg:           cfgX,
+		records:       make([]*RecordedRequest, cfgX.Capacity),
+		redactHeaders: map[string]bool{},
+		redactFields:  map[string]bool{},
+	}
+	for _, h := range cfgX.RedactHeaders {
+		r.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, f := range cfgX.RedactFields {
+		r.redactFields[strings.ToLower(f)] = true
+	}
+
+	if cfgX.File != "" {
+		f, err := os.OpenFile(cfgX.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			panic(fmt.Errorf("failed to open recorder file: %s", err))
+		}
+		r.file = f
+	}
+
+	return func(ctx *zoox.Context) {
+		if !cfgX.Always && !ctx.Debug().IsDebugMode() {
+			ctx.Next()
+			return
+		}
+
+		if ctx.Path == cfgX.Path || strings.HasPrefix(ctx.Path, cfgX.Path+"/") {
+			if token, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfgX.Token)) != 1 {
+				ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			r.serve(ctx)
+			return
+		}
+
+		if ctx.Context().Value(recorderReplayKey{}) != nil || ctx.IsConnectionUpgrade() {
+			ctx.Next()
+			return
+		}
+
+		r.record(ctx)
+	}
+}
+
+func (r *recorder) record(ctx *zoox.Context) {
+	start := time.Now()
+	record := &RecordedRequest{
+		ID:            random.String(16),
+		Timestamp:     start,
+		Method:        ctx.Method,
+		URL:           ctx.Request.URL.String(),
+		RequestHeader: r.redactHeader(ctx.Request.Header),
+	}
+
+	// read the capped body, and restore it for handlers
+	if ctx.Request.Body != nil {
+		limited, _ := io.ReadAll(io.LimitReader(ctx.Request.Body, int64(r.cfg.MaxBodySize)+1))
+		ctx.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(limited), ctx.Request.Body), ctx.Request.Body}
+
+		body, truncated := r.capBody(limited)
+		record.RequestBody = r.redactBody(body, truncated)
+		record.Truncated = truncated
+	}
+
+	ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+		capped, truncated := r.capBody(body)
+		record.Status = status
+		record.ResponseHeader = r.redactHeader(ctx.Writer.Header())
+		record.ResponseBody = r.redactBody(capped, truncated)
+		record.Truncated = record.Truncated || truncated
+		return status, body
+	})
+
+	// streaming response
+	if record.Status == 0 {
+		record.Status = ctx.Writer.Status()
+		record.ResponseHeader = r.redactHeader(ctx.Writer.Header())
+	}
+	record.Duration = time.Since(start)
+
+	r.add(ctx, record)
+}
+
+func (r *recorder) add(ctx *zoox.Context, record *RecordedRequest) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+
+	if r.file != nil {
+		line, err := json.Marshal(record)
+		if err == nil {
+			_, err = r.file.Write(append(line, '\n'))
+		}
+		if err != nil {
+			ctx.RequestLogger().Errorf("[middleware][recorder] failed to write record: %s", err)
+		}
+	}
+}
+
+// list returns the records, newest first.
+func (r *recorder) list() []*RecordedRequest {
+	r.Lock()
+	defer r.Unlock()
+
+	records := []*RecordedRequest{}
+	for i := 1; i <= len(r.records); i++ {
+		record := r.records[(r.next-i+len(r.records))%len(r.records)]
+		if record != nil {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+func (r *recorder) get(id string) *RecordedRequest {
+	for _, record := range r.list() {
+		if record.ID == id {
+			return record
+		}
+	}
+
+	return nil
+}
+
+func (r *recorder) serve(ctx *zoox.Context) {
+	rest := strings.Trim(strings.TrimPrefix(ctx.Path, r.cfg.Path), "/")
+	if rest == "" {
+		ctx.Success(r.list())
+		return
+	}
+
+	id, action, _ := strings.Cut(rest, "/")
+	record := r.get(id)
+	if record == nil {
+		ctx.Fail(nil, http.StatusNotFound, "record not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && ctx.Method == http.MethodGet:
+		ctx.Success(record)
+	case action == "replay" && ctx.Method == http.MethodPost:
+		if record.Truncated {
+			ctx.Fail(nil, http.StatusBadRequest, "record is truncated, cannot replay", http.StatusBadRequest)
+			return
+		}
+
+		ctx.Success(r.replay(ctx.App, record))
+	default:
+		ctx.Fail(nil, http.StatusMethodNotAllowed, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// replay serves the recorded request with the app, returns the new record.
+func (r *recorder) replay(app *zoox.Application, record *RecordedRequest) *RecordedRequest {
+	req := httptest.NewRequest(record.Method, record.URL, strings.NewReader(record.RequestBody))
+	req.Header = record.RequestHeader.Clone()
+	req = req.WithContext(context.WithValue(req.Context(), recorderReplayKey{}, record.ID))
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	body, truncated := r.capBody(w.Body.Bytes())
+	return &RecordedRequest{
+		ID:             random.String(16),
+		Timestamp:      start,
+		Duration:       time.Since(start),
+		Method:         record.Method,
+		URL:            record.URL,
+		RequestHeader:  record.RequestHeader,
+		RequestBody:    record.RequestBody,
+		Status:         w.Code,
+		ResponseHeader: r.redactHeader(w.Header()),
+		ResponseBody:   r.redactBody(body, truncated),
+		Truncated:      truncated,
+	}
+}
+
+func (r *recorder) capBody(body []byte) ([]byte, bool) {
+	if len(body) > r.cfg.MaxBodySize {
+		return body[:r.cfg.MaxBodySize], true
+	}
+
+	return body, false
+}
+
+func (r *recorder) redactHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	for k := range redacted {
+		if r.redactHeaders[http.CanonicalHeaderKey(k)] {
+			redacted[k] = []string{"[REDACTED]"}
+		}
+	}
+
+	return redacted
+}
+
+// redactBody redacts the fields of json body, other bodies are kept as is.
+func (r *recorder) redactBody(body []byte, truncated bool) string {
+	if len(r.redactFields) == 0 || truncated || len(body) == 0 {
+		return string(body)
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+func (r *recorder) redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, one := range value {
+			if r.redactFields[strings.ToLower(k)] {
+				value[k] = "[REDACTED]"
+			} else {
+				value[k] = r.redactValue(one)
+			}
+		}
+	case []any:
+		for i, one := range value {
+			value[i] = r.redactValue(one)
+		}
+	}
+
+	return v
+}
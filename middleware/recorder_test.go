@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderRequiresToken(t *testing.T) {
+	assert.Panics(t, func() {
+		Recorder(&RecorderConfig{Always: true})
+	})
+}
+
+func TestRecorderAPI(t *testing.T) {
+	app := zoox.New()
+	app.Use(Recorder(&RecorderConfig{Always: true, Token: "secret"}))
+	app.Get("/hello", func(ctx *zoox.Context) {
+		ctx.String(http.StatusOK, "hello")
+	})
+
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		// clients can't disable the recording
+		req.Header.Set("X-Recorder-Replay", "1")
+
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	list := func() []RecordedRequest {
+		var res struct {
+			Result []RecordedRequest `json:"result"`
+		}
+		recorder := serve(http.MethodGet, DefaultRecorderPath, "secret")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+		return res.Result
+	}
+
+	serve(http.MethodGet, "/hello", "")
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, DefaultRecorderPath, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, DefaultRecorderPath, "wrong").Code)
+
+	records := list()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "hello", records[0].ResponseBody)
+
+	// the replayed request is not recorded
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, DefaultRecorderPath+"/"+records[0].ID+"/replay", "secret").Code)
+	assert.Len(t, list(), 1)
+}
@@ -20,6 +20,8 @@ func Recovery() zoox.Middleware {
 			// issue: https://github.com/golang/go/issues/28239
 			//	code: v1.22.2/src/net/http/server.go#1895
 			if err := recover(); err != nil && err != http.ErrAbortHandler {
+				ctx.ReportPanic(err)
+
 				// stackoverflow: https://stackoverflow.com/questions/52103182/how-to-get-the-stacktrace-of-a-panic-and-store-as-a-variable
 				if ctx.Debug().IsDebugMode() {
 					fmt.Println("stacktrace from panic: \n" + string(debug.Stack()))
@@ -28,7 +30,13 @@ func Recovery() zoox.Middleware {
 				httprequest, _ := httputil.DumpRequest(ctx.Request, false)
 				goErr := errors.Wrap(err, 3)
 				reset := string([]byte{27, 91, 48, 109})
-				ctx.Logger.Errorf("[Nice Recovery] panic recovered:\n\n%s%s\n\n%s%s", httprequest, goErr.Error(), goErr.Stack(), reset)
+				ctx.RequestLogger().Errorf("[Nice Recovery] panic recovered:\n\n%s%s\n\n%s%s", httprequest, goErr.Error(), goErr.Stack(), reset)
+
+				// group or app error handler
+				if handler := ctx.ErrorHandler(); handler != nil {
+					handler(ctx, fmt.Errorf("panic: %v", err))
+					return
+				}
 
 				switch err.(type) {
 				case error:
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/logsink"
+)
+
+// RequestLogConfig ...
+type RequestLogConfig struct {
+	// Skip skips the requests, such as health checks.
+	Skip func(ctx *zoox.Context) bool
+	// Fields adds the custom fields of the record, such as the user id.
+	Fields func(ctx *zoox.Context) map[string]any
+}
+
+// RequestLog writes the structured request logs to app.RequestLog (async, never blocks on log I/O by default).
+//
+// Example:
+//
+//	app.Use(middleware.RequestLog(&middleware.RequestLogConfig{
+//		Skip: func(ctx *zoox.Context) bool { return ctx.Path == "/healthz" },
+//	}))
+func RequestLog(cfg ...*RequestLogConfig) zoox.Middleware {
+	cfgX := &RequestLogConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	return func(ctx *zoox.Context) {
+		if cfgX.Skip != nil && cfgX.Skip(ctx) {
+			ctx.Next()
+			return
+		}
+
+		t := time.Now()
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := "info"
+		if status >= 500 {
+			level = "error"
+		} else if status >= 400 {
+			level = "warn"
+		}
+
+		fields := map[string]any{
+			"method":      ctx.Method,
+			"path":        ctx.Path,
+			"status":      status,
+			"size":        responseSize(ctx),
+			"duration_ms": time.Since(t).Milliseconds(),
+			"ip":          ctx.IP(),
+			"request_id":  ctx.RequestID(),
+			"user_agent":  ctx.Request.UserAgent(),
+		}
+		if tenant := defaultTenantFunc(ctx); tenant != "" {
+			fields["tenant"] = tenant
+		}
+		// route, user id and the fields bound by ctx.RequestLogger().Bind
+		for key, value := range ctx.RequestLogger().Fields() {
+			if _, ok := fields[key]; !ok {
+				fields[key] = value
+			}
+		}
+		if cfgX.Fields != nil {
+			for key, value := range cfgX.Fields(ctx) {
+				fields[key] = value
+			}
+		}
+
+		if err := ctx.App.RequestLog().Write(&logsink.Record{
+			Time:    t,
+			Level:   level,
+			Message: ctx.Method + " " + ctx.Path,
+			Fields:  fields,
+		}); err != nil {
+			ctx.RequestLogger().Warnf("[middleware][request_log] failed to write: %s", err)
+		}
+	}
+}
@@ -15,7 +15,7 @@ func RequestID() zoox.Middleware {
 			ctx.Request.Header.Set(utils.RequestIDHeader, requestID)
 
 			// set to response
-			ctx.Set(utils.RequestIDHeader, requestID)
+			ctx.SetHeader(utils.RequestIDHeader, requestID)
 		}
 
 		ctx.Next()
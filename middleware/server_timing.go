@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/go-zoox/zoox"
+)
+
+// ServerTimingConfig ...
+type ServerTimingConfig struct {
+	// SlowThreshold is the duration that a request is considered slow, 0 means disabled.
+	SlowThreshold time.Duration
+	// OnSlow is called with the trace of slow request, such as exporting to tracing system.
+	OnSlow func(ctx *zoox.Context, trace *SlowRequestTrace)
+	// DisableHeader disables the Server-Timing header, only slow detection works.
+	DisableHeader bool
+}
+
+// SlowRequestTrace is the trace of a slow request.
+type SlowRequestTrace struct {
+	Method   string              `json:"method"`
+	Path     string              `json:"path"`
+	Status   int                 `json:"status"`
+	Duration time.Duration       `json:"duration"`
+	Timings  []zoox.ServerTiming `json:"timings"`
+}
+
+// ServerTiming emits the Server-Timing header with per-phase durations (middleware, handler, render, total),
+// and custom metrics added by ctx.AddServerTiming. Requests exceeding SlowThreshold are logged and exported by OnSlow.
+//
+// Streaming responses are not buffered, so the header is not emitted for them.
+func ServerTiming(cfg ...*ServerTimingConfig) zoox.Middleware {
+	cfgX := &ServerTimingConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	return func(ctx *zoox.Context) {
+		start := time.Now()
+		ctx.EnableServerTiming()
+
+		timings := func() []zoox.ServerTiming {
+			metrics := []zoox.ServerTiming{}
+			if handlerStartedAt, handlerEndedAt := ctx.HandlerTiming(); !handlerStartedAt.IsZero() {
+				metrics = append(metrics, zoox.ServerTiming{Name: "middleware", Duration: handlerStartedAt.Sub(start)})
+				if !handlerEndedAt.IsZero() {
+					metrics = append(metrics, zoox.ServerTiming{Name: "handler", Duration: handlerEndedAt.Sub(handlerStartedAt)})
+				}
+			}
+
+			metrics = append(metrics, ctx.ServerTimings()...)
+			return append(metrics, zoox.ServerTiming{Name: "total", Duration: time.Since(start)})
+		}
+
+		if cfgX.DisableHeader {
+			ctx.Next()
+		} else {
+			ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+				ctx.SetHeader("Server-Timing", zoox.FormatServerTiming(timings()))
+				return status, body
+			})
+		}
+
+		duration := time.Since(start)
+		if cfgX.SlowThreshold == 0 || duration < cfgX.SlowThreshold {
+			return
+		}
+
+		trace := &SlowRequestTrace{
+			Method:   ctx.Method,
+			Path:     ctx.Path,
+			Status:   ctx.Writer.Status(),
+			Duration: duration,
+			Timings:  timings(),
+		}
+
+		ctx.RequestLogger().Warnf("[middleware][server_timing] slow request: %s %s %d %s (%s)", trace.Method, trace.Path, trace.Status, trace.Duration, zoox.FormatServerTiming(trace.Timings))
+
+		if cfgX.OnSlow != nil {
+			cfgX.OnSlow(ctx, trace)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-zoox/zoox"
+)
+
+// SignedURLClaimsStateKey is the ctx.State key of the claims (map[string]any) of the signed url.
+const SignedURLClaimsStateKey = "signed_url_claims"
+
+// RequireSignedURLConfig ...
+type RequireSignedURLConfig struct {
+	// OnInvalid handles the invalid or expired urls, default responds 403 (invalid) or 410 (expired).
+	OnInvalid func(ctx *zoox.Context, err error)
+}
+
+// RequireSignedURL requires the request url is signed by app.SignURL and not expired,
+// the claims are stored in ctx.State with SignedURLClaimsStateKey.
+//
+// Example:
+//
+//	app.Get("/downloads/:name", middleware.RequireSignedURL(), func(ctx *zoox.Context) {
+//		claims := ctx.State().Get(middleware.SignedURLClaimsStateKey).(map[string]any)
+//		ctx.ServeFile(path.Join(dir, ctx.Param().Get("name").String()))
+//	})
+func RequireSignedURL(cfg ...*RequireSignedURLConfig) zoox.Middleware {
+	cfgX := &RequireSignedURLConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	return func(ctx *zoox.Context) {
+		claims, err := ctx.App.VerifySignedURL(ctx.Request.URL)
+		if err != nil {
+			if cfgX.OnInvalid != nil {
+				cfgX.OnInvalid(ctx, err)
+				return
+			}
+
+			if errors.Is(err, zoox.ErrKeyringNotConfigured) {
+				ctx.Fail(err, http.StatusInternalServerError, "signed urls are not configured", http.StatusInternalServerError)
+				return
+			}
+
+			if errors.Is(err, zoox.ErrSignedURLExpired) {
+				ctx.Fail(err, http.StatusGone, err.Error(), http.StatusGone)
+				return
+			}
+
+			ctx.Fail(err, http.StatusForbidden, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		ctx.State().Set(SignedURLClaimsStateKey, claims)
+
+		ctx.Next()
+	}
+}
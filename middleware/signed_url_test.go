@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedURLApp(t *testing.T, secrets ...string) *zoox.Application {
+	kr, err := keyring.New(secrets...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := zoox.New()
+	app.SetKeyring(kr)
+	app.Get("/downloads/:name", RequireSignedURL(), func(ctx *zoox.Context) {
+		claims := ctx.State().Get(SignedURLClaimsStateKey).(map[string]any)
+		ctx.String(http.StatusOK, "%s:%v", ctx.Param().Get("name").String(), claims["user"])
+	})
+
+	return app
+}
+
+func TestRequireSignedURL(t *testing.T) {
+	app := newSignedURLApp(t, "secret")
+
+	link, err := app.SignURL("/downloads/report.pdf", time.Minute, map[string]any{"user": "u1"})
+	assert.Nil(t, err)
+
+	res := serve(app, http.MethodGet, link, nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "report.pdf:u1", res.Body.String())
+
+	// the path and the claims are signed
+	res = serve(app, http.MethodGet, strings.Replace(link, "report.pdf", "secret.pdf", 1), nil)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	other, _ := app.SignURL("/downloads/report.pdf", time.Minute, map[string]any{"user": "u2"})
+	claims := other[strings.Index(other, "claims="):strings.Index(other, "&")]
+	res = serve(app, http.MethodGet, strings.Replace(link, link[strings.Index(link, "claims="):strings.Index(link, "&")], claims, 1), nil)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = serve(app, http.MethodGet, "/downloads/report.pdf", nil)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+}
+
+func TestRequireSignedURLExpired(t *testing.T) {
+	app := newSignedURLApp(t, "secret")
+
+	link, err := app.SignURL("/downloads/report.pdf", -2*time.Second)
+	assert.Nil(t, err)
+
+	res := serve(app, http.MethodGet, link, nil)
+	assert.Equal(t, http.StatusGone, res.Code)
+
+	// the expiry is signed, it can't be extended
+	res = serve(app, http.MethodGet, strings.Replace(link, "expires=", "expires=9", 1), nil)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+}
+
+func TestRequireSignedURLKeyRotation(t *testing.T) {
+	link, err := newSignedURLApp(t, "old").SignURL("/downloads/report.pdf", time.Minute, map[string]any{"user": "u1"})
+	assert.Nil(t, err)
+
+	// the old key is kept after rotation
+	res := serve(newSignedURLApp(t, "new", "old"), http.MethodGet, link, nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	// the old key is removed
+	res = serve(newSignedURLApp(t, "new"), http.MethodGet, link, nil)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/headers"
+	"github.com/go-zoox/zoox"
+)
+
+// SingleFlightConfig ...
+type SingleFlightConfig struct {
+	// KeyFunc returns the key of identical requests, default: method + url + caller (Authorization and Cookie),
+	// so that personalized responses are not shared by users. Return empty string to skip coalescing.
+	KeyFunc func(ctx *zoox.Context) string
+	// MaxWait is the max duration waiters wait for the shared response,
+	//	the request is handled by itself on timeout, default: 0 (wait until done).
+	MaxWait time.Duration
+}
+
+type singleFlightCall struct {
+	done     chan struct{}
+	response *singleFlightResponse
+}
+
+type singleFlightResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// SingleFlight collapses concurrent identical GET requests into a single handler execution,
+// and fans the response out to all waiters.
+//
+// Example:
+//
+//	app.Get("/reports/summary", middleware.SingleFlight(), summary)
+func SingleFlight(cfg ...*SingleFlightConfig) zoox.Middleware {
+	cfgX := &SingleFlightConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	keyFunc := cfgX.KeyFunc
+	if keyFunc == nil {
+		keyFunc = singleFlightKey
+	}
+
+	var mu sync.Mutex
+	calls := map[string]*singleFlightCall{}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Method != http.MethodGet && ctx.Method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		key := keyFunc(ctx)
+		if key == "" || ctx.IsConnectionUpgrade() {
+			ctx.Next()
+			return
+		}
+
+		mu.Lock()
+		if call, ok := calls[key]; ok {
+			mu.Unlock()
+			waitSingleFlight(ctx, call, cfgX.MaxWait)
+			return
+		}
+
+		call := &singleFlightCall{
+			done: make(chan struct{}),
+		}
+		calls[key] = call
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			delete(calls, key)
+			mu.Unlock()
+
+			close(call.done)
+		}()
+
+		ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+			call.response = &singleFlightResponse{
+				status: status,
+				header: ctx.Writer.Header().Clone(),
+				body:   body,
+			}
+			return status, body
+		})
+	}
+}
+
+// waitSingleFlight waits for the response of the leader, the request is handled by itself on timeout.
+func waitSingleFlight(ctx *zoox.Context, call *singleFlightCall, maxWait time.Duration) {
+	var timeout <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-call.done:
+	case <-timeout:
+		ctx.Next()
+		return
+	}
+
+	// streaming response or panic is not shared
+	response := call.response
+	if response == nil {
+		ctx.Next()
+		return
+	}
+
+	for k, values := range response.header {
+		// the session cookies of the leader are never shared
+		if k == headers.SetCookie {
+			continue
+		}
+
+		ctx.Writer.Header()[k] = values
+	}
+	ctx.SetHeader("X-Single-Flight", "shared")
+	ctx.Data(response.status, response.header.Get(headers.ContentType), response.body)
+}
+
+// singleFlightKey is the default key, the caller credentials are hashed.
+func singleFlightKey(ctx *zoox.Context) string {
+	key := ctx.Method + " " + ctx.Request.URL.String()
+
+	authorization := ctx.Header().Get(headers.Authorization)
+	cookie := ctx.Header().Get(headers.Cookie)
+	if authorization == "" && cookie == "" {
+		return key
+	}
+
+	h := sha256.Sum256([]byte(authorization + "\n" + cookie))
+	return key + " " + hex.EncodeToString(h[:])
+}
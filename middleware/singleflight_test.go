@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSingleFlightApp(calls *atomic.Int32) *zoox.Application {
+	app := zoox.New()
+	app.Get("/profile", SingleFlight(), func(ctx *zoox.Context) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		ctx.SetHeader("Set-Cookie", "session=leader")
+		ctx.String(http.StatusOK, ctx.Header().Get("Authorization"))
+	})
+
+	return app
+}
+
+func serveConcurrently(app *zoox.Application, authorizations ...string) []*httptest.ResponseRecorder {
+	recorders := make([]*httptest.ResponseRecorder, len(authorizations))
+
+	var wg sync.WaitGroup
+	for i, authorization := range authorizations {
+		i, authorization := i, authorization
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// the first is the leader
+			time.Sleep(time.Duration(i) * 20 * time.Millisecond)
+			recorders[i] = serve(app, http.MethodGet, "/profile", nil, "Authorization", authorization)
+		}()
+	}
+	wg.Wait()
+
+	return recorders
+}
+
+func TestSingleFlightSharesIdenticalRequests(t *testing.T) {
+	var calls atomic.Int32
+	recorders := serveConcurrently(newSingleFlightApp(&calls), "Bearer alice", "Bearer alice")
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, "Bearer alice", recorders[1].Body.String())
+	assert.Equal(t, "shared", recorders[1].Header().Get("X-Single-Flight"))
+	// the session cookie of the leader is never shared
+	assert.Equal(t, "session=leader", recorders[0].Header().Get("Set-Cookie"))
+	assert.Empty(t, recorders[1].Header().Get("Set-Cookie"))
+}
+
+func TestSingleFlightScopesByCaller(t *testing.T) {
+	var calls atomic.Int32
+	recorders := serveConcurrently(newSingleFlightApp(&calls), "Bearer alice", "Bearer bob")
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "Bearer alice", recorders[0].Body.String())
+	assert.Equal(t, "Bearer bob", recorders[1].Body.String())
+	assert.Empty(t, recorders[1].Header().Get("X-Single-Flight"))
+}
@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-zoox/zoox"
+)
+
+// ErrSlowBody is returned by the request body reader when the client sends the body too slowly.
+var ErrSlowBody = errors.New("request body is sent too slowly")
+
+// BodyStatsStateKey is the ctx.State key of the *BodyStats of the request.
+const BodyStatsStateKey = "body_stats"
+
+// BodyStats is the request body accounting, updated while the body is read.
+type BodyStats struct {
+	// Size is the bytes read from the body.
+	Size int64
+	// Duration is the time spent from the first read to the last read.
+	Duration time.Duration
+	// TimedOut is true if the body is rejected as too slow.
+	TimedOut bool
+}
+
+// SlowBodyConfig ...
+type SlowBodyConfig struct {
+	// MinRate is the minimum transfer rate in bytes per second, 0 means no limit.
+	MinRate int64
+	// GracePeriod is the time allowed before MinRate is enforced, default 5s.
+	GracePeriod time.Duration
+	// MaxDuration is the maximum time for reading the whole body, 0 means no limit.
+	MaxDuration time.Duration
+}
+
+// SlowBody protects against clients trickling request bodies,
+// the body must be read at least MinRate bytes per second (after GracePeriod) and within MaxDuration.
+//
+// The read deadline of the connection is moved along with the body reads,
+// so a blocked read fails on time, and the request is responded with 408 and the connection closed,
+// whatever the handler does with the read error.
+func SlowBody(cfg ...*SlowBodyConfig) zoox.Middleware {
+	cfgX := &SlowBodyConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+	if cfgX.GracePeriod == 0 {
+		cfgX.GracePeriod = 5 * time.Second
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody || (cfgX.MinRate <= 0 && cfgX.MaxDuration <= 0) {
+			ctx.Next()
+			return
+		}
+
+		stats := &BodyStats{}
+		ctx.State().Set(BodyStatsStateKey, stats)
+
+		writer := &slowBodyWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+
+		body := &slowBodyReader{
+			ReadCloser: ctx.Request.Body,
+			cfg:        cfgX,
+			stats:      stats,
+			rc:         http.NewResponseController(writer.ResponseWriter),
+			onTimeout: func() {
+				ctx.RequestLogger().Warnf("[middleware][slow_body] %s %s: %d bytes in %s", ctx.Method, ctx.Path, stats.Size, stats.Duration)
+
+				if !writer.Written() {
+					writer.Header().Set("Connection", "close")
+					ctx.Fail(ErrSlowBody, http.StatusRequestTimeout, "request body timeout", http.StatusRequestTimeout)
+				}
+
+				// the response is committed, writes of the handler are discarded
+				writer.discard = true
+				ctx.Abort()
+			},
+		}
+		ctx.Request.Body = body
+
+		ctx.Next()
+
+		body.clearDeadline()
+	}
+}
+
+type slowBodyReader struct {
+	io.ReadCloser
+	cfg       *SlowBodyConfig
+	stats     *BodyStats
+	rc        *http.ResponseController
+	onTimeout func()
+
+	startedAt   time.Time
+	noDeadline  bool
+	hasDeadline bool
+}
+
+func (r *slowBodyReader) Read(p []byte) (int, error) {
+	if r.stats.TimedOut {
+		return 0, ErrSlowBody
+	}
+
+	if r.startedAt.IsZero() {
+		r.startedAt = time.Now()
+	}
+
+	r.setDeadline()
+
+	n, err := r.ReadCloser.Read(p)
+	r.stats.Size += int64(n)
+	r.stats.Duration = time.Since(r.startedAt)
+
+	if err == io.EOF {
+		r.clearDeadline()
+		return n, err
+	}
+
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return n, r.timeout()
+		}
+
+		return n, err
+	}
+
+	// the read deadline is not supported, check after read
+	if r.exceeded() {
+		return n, r.timeout()
+	}
+
+	return n, nil
+}
+
+func (r *slowBodyReader) timeout() error {
+	r.stats.TimedOut = true
+	r.clearDeadline()
+	r.onTimeout()
+	return ErrSlowBody
+}
+
+// deadline is the latest time for the next byte,
+// so the transfer rate is kept above MinRate after GracePeriod.
+func (r *slowBodyReader) deadline() time.Time {
+	var deadline time.Time
+
+	if r.cfg.MinRate > 0 {
+		expected := time.Duration(float64(r.stats.Size+1) / float64(r.cfg.MinRate) * float64(time.Second))
+		deadline = r.startedAt.Add(r.cfg.GracePeriod + expected)
+	}
+
+	if r.cfg.MaxDuration > 0 {
+		if max := r.startedAt.Add(r.cfg.MaxDuration); deadline.IsZero() || max.Before(deadline) {
+			deadline = max
+		}
+	}
+
+	return deadline
+}
+
+func (r *slowBodyReader) exceeded() bool {
+	return time.Now().After(r.deadline())
+}
+
+func (r *slowBodyReader) setDeadline() {
+	if r.noDeadline {
+		return
+	}
+
+	if err := r.rc.SetReadDeadline(r.deadline()); err != nil {
+		r.noDeadline = true
+		return
+	}
+	r.hasDeadline = true
+}
+
+func (r *slowBodyReader) clearDeadline() {
+	if !r.hasDeadline {
+		return
+	}
+
+	r.hasDeadline = false
+	r.rc.SetReadDeadline(time.Time{})
+}
+
+// slowBodyWriter discards the writes after the 408 response.
+type slowBodyWriter struct {
+	zoox.ResponseWriter
+	discard bool
+}
+
+func (w *slowBodyWriter) WriteHeader(code int) {
+	if w.discard {
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *slowBodyWriter) Write(b []byte) (int, error) {
+	if w.discard {
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *slowBodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *slowBodyWriter) WriteInformational(code int) error {
+	if w.discard {
+		return nil
+	}
+
+	if iw, ok := w.ResponseWriter.(zoox.InformationalWriter); ok {
+		return iw.WriteInformational(code)
+	}
+
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the wrapped writer, used by http.ResponseController.
+func (w *slowBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
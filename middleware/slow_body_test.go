@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/stretchr/testify/assert"
+)
+
+// trickleReader sends one byte per interval, such as a slow client.
+type trickleReader struct {
+	remaining int
+	interval  time.Duration
+}
+
+func (r *trickleReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+
+	time.Sleep(r.interval)
+	r.remaining--
+	p[0] = 'x'
+	return 1, nil
+}
+
+func newSlowBodyApp(cfg *SlowBodyConfig, readErr chan<- error) *zoox.Application {
+	app := zoox.New()
+	app.Use(SlowBody(cfg))
+	app.Post("/", func(ctx *zoox.Context) {
+		data, err := io.ReadAll(ctx.Request.Body)
+		if readErr != nil {
+			readErr <- err
+		}
+
+		// the handler ignores the read error, the writes after the timeout are discarded
+		ctx.SetHeader("X-Handler", "late")
+		ctx.String(http.StatusOK, "late: "+string(data))
+	})
+
+	return app
+}
+
+func TestSlowBodyTrickleReader(t *testing.T) {
+	readErr := make(chan error, 1)
+	app := newSlowBodyApp(&SlowBodyConfig{
+		MinRate:     100,
+		GracePeriod: 20 * time.Millisecond,
+	}, readErr)
+
+	// the recorder does not support the read deadline, the rate is checked after each read
+	res := serve(app, http.MethodPost, "/", &trickleReader{remaining: 100, interval: 30 * time.Millisecond})
+	assert.ErrorIs(t, <-readErr, ErrSlowBody)
+	assert.Equal(t, http.StatusRequestTimeout, res.Code)
+	assert.Equal(t, "close", res.Header().Get("Connection"))
+	assert.Empty(t, res.Result().Header.Get("X-Handler"))
+	assert.Contains(t, res.Body.String(), "request body timeout")
+	assert.NotContains(t, res.Body.String(), "late")
+}
+
+func TestSlowBodyFastBody(t *testing.T) {
+	app := newSlowBodyApp(&SlowBodyConfig{
+		MinRate:     100,
+		GracePeriod: 20 * time.Millisecond,
+		MaxDuration: time.Second,
+	}, nil)
+
+	res := serve(app, http.MethodPost, "/", strings.NewReader("hello"))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "late: hello", res.Body.String())
+	assert.Empty(t, res.Header().Get("Connection"))
+}
+
+func TestSlowBodyReadDeadline(t *testing.T) {
+	readErr := make(chan error, 1)
+	app := newSlowBodyApp(&SlowBodyConfig{
+		MaxDuration: 100 * time.Millisecond,
+	}, readErr)
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	// the client sends a few bytes and then stalls, the blocked read fails on the deadline
+	body, writer := io.Pipe()
+	defer writer.Close()
+	go writer.Write([]byte("abc"))
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, body)
+	req.ContentLength = 1024
+
+	// the request hangs if the deadline is not moved along with the reads
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+
+	select {
+	case err := <-readErr:
+		assert.ErrorIs(t, err, ErrSlowBody)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the handler")
+	}
+
+	data, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusRequestTimeout, res.StatusCode)
+	assert.True(t, res.Close, "expected the connection to be closed")
+	assert.Empty(t, res.Header.Get("X-Handler"))
+	assert.Contains(t, string(data), "request body timeout")
+	assert.NotContains(t, string(data), "late")
+}
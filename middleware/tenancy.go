@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/tenancy"
+)
+
+// TenancyConfig ...
+type TenancyConfig struct {
+	// Required responds 400 if the tenant is not resolved.
+	Required bool
+	// Preload loads the tenant config before the handler, unknown tenants (tenancy.ErrNotFound) get 404.
+	Preload bool
+}
+
+// Tenancy resolves the tenant of the request with app.Tenancy(),
+// the tenant id is put into ctx.State (TenantStateKey) for logger and metrics.
+//
+// Cache keys, sessions and rate limits are scoped with ctx.Tenant() once resolved.
+func Tenancy(cfg ...*TenancyConfig) zoox.Middleware {
+	cfgX := &TenancyConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	return func(ctx *zoox.Context) {
+		tenant := ctx.Tenant()
+		if tenant == nil {
+			if cfgX.Required {
+				ctx.Fail(nil, http.StatusBadRequest, "tenant is required", http.StatusBadRequest)
+				return
+			}
+
+			ctx.Next()
+			return
+		}
+
+		if cfgX.Preload {
+			if _, err := tenant.Config(); err != nil {
+				if errors.Is(err, tenancy.ErrNotFound) {
+					ctx.Fail(err, http.StatusNotFound, "tenant not found", http.StatusNotFound)
+					return
+				}
+
+				ctx.RequestLogger().Errorf("[middleware][tenancy] failed to load config of tenant(%s): %s", tenant.ID, err)
+				ctx.Fail(err, http.StatusInternalServerError, "failed to load tenant", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		ctx.State().Set(TenantStateKey, tenant.ID)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,281 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/quota"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultTenantUsagePath is the path of the per-tenant usage report.
+const DefaultTenantUsagePath = "/_/tenants/usage"
+
+// TenantStateKey is the ctx.State key of the tenant id, used by logger and metrics.
+const TenantStateKey = "tenant_id"
+
+// TenantOther is the tenant of the requests out of the Tenants allowlist or over MaxTenants.
+const TenantOther = "_other"
+
+// DefaultMaxTenants is the default max tenants tracked by TenantMetrics.
+const DefaultMaxTenants = 1000
+
+// TenantMetricsConfig is the config of TenantMetrics middleware.
+type TenantMetricsConfig struct {
+	// TenantFunc resolves the tenant id of the request from a trusted source, such as the authenticated user.
+	// Default: ctx.Tenant() (resolved by app.SetTenancy) once it is verified by the tenancy Loader,
+	// the unverified tenants (such as a client-supplied X-Tenant-ID header) are not tracked.
+	TenantFunc func(ctx *zoox.Context) string
+	// Tenants is the allowlist of tenants, the others are counted as TenantOther.
+	Tenants []string
+	// MaxTenants is the max tenants tracked, the others are counted as TenantOther, default: 1000.
+	MaxTenants int
+
+	// Quota enforces the daily/monthly quotas keyed by tenant id, nil means unlimited, such as:
+	//	quota.New(&quota.Config{Namespace: app.Namespace("tenant_quota"), Limits: quota.Limits{Daily: 100000}})
+	// The limits of one tenant are overridden with Quota.SetLimits.
+	// For per-tenant rate limits, use RateLimit, which is scoped by the verified tenant.
+	Quota *quota.Quota
+
+	// ReportPath is the path of the usage report endpoint, default: /_/tenants/usage.
+	ReportPath string
+	// ReportAuth authorizes the usage report endpoint, default: deny all.
+	ReportAuth func(ctx *zoox.Context) bool
+
+	// Registerer is the prometheus registerer, default: prometheus.DefaultRegisterer.
+	Registerer prometheus.Registerer
+}
+
+// TenantUsage is the usage of one tenant.
+type TenantUsage struct {
+	Tenant        string    `json:"tenant"`
+	Requests      int64     `json:"requests"`
+	Errors        int64     `json:"errors"`
+	BytesWritten  int64     `json:"bytes_written"`
+	TotalDuration string    `json:"total_duration"`
+	Rejected      int64     `json:"rejected"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	// Quota is the quota usage of the tenant, nil without TenantMetricsConfig.Quota.
+	Quota *quota.Usage `json:"quota,omitempty"`
+	//
+	totalDuration time.Duration
+}
+
+type tenantUsages struct {
+	sync.Mutex
+	data map[string]*TenantUsage
+	//
+	allowlist  map[string]bool
+	maxTenants int
+}
+
+// TenantMetrics partitions metrics, logs and quotas by tenant.
+//
+//   - prometheus metrics zoox_tenant_requests_total / zoox_tenant_request_duration_seconds are labeled with tenant
+//   - the tenant id is put into ctx.State (TenantStateKey), the Logger middleware prints it
+//   - per-tenant quotas are enforced with Quota, responded in X-Quota-Limit / X-Quota-Remaining / X-Quota-Reset
+//   - the usage report is exposed at ReportPath, authorized by ReportAuth
+//
+// The tenants are bounded by Tenants or MaxTenants, so that clients can't blow up the metric labels.
+func TenantMetrics(cfg ...*TenantMetricsConfig) zoox.Middleware {
+	cfgX := &TenantMetricsConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		cfgX = cfg[0]
+	}
+
+	if cfgX.TenantFunc == nil {
+		cfgX.TenantFunc = defaultTenantFunc
+	}
+	if cfgX.MaxTenants == 0 {
+		cfgX.MaxTenants = DefaultMaxTenants
+	}
+	if cfgX.ReportPath == "" {
+		cfgX.ReportPath = DefaultTenantUsagePath
+	}
+	if cfgX.Registerer == nil {
+		cfgX.Registerer = prometheus.DefaultRegisterer
+	}
+
+	requests := registerCollector(cfgX.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zoox_tenant_requests_total",
+		Help: "The total requests partitioned by tenant.",
+	}, []string{"tenant", "method", "status"})).(*prometheus.CounterVec)
+
+	durations := registerCollector(cfgX.Registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zoox_tenant_request_duration_seconds",
+		Help:    "The request durations partitioned by tenant.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tenant", "method"})).(*prometheus.HistogramVec)
+
+	usages := &tenantUsages{
+		data:       map[string]*TenantUsage{},
+		maxTenants: cfgX.MaxTenants,
+	}
+	if len(cfgX.Tenants) != 0 {
+		usages.allowlist = map[string]bool{}
+		for _, tenant := range cfgX.Tenants {
+			usages.allowlist[tenant] = true
+		}
+	}
+
+	return func(ctx *zoox.Context) {
+		if ctx.Path == cfgX.ReportPath {
+			if cfgX.ReportAuth == nil || !cfgX.ReportAuth(ctx) {
+				ctx.Status(http.StatusUnauthorized)
+				return
+			}
+
+			ctx.JSON(http.StatusOK, usages.Report(cfgX.Quota))
+			return
+		}
+
+		id := cfgX.TenantFunc(ctx)
+		if id == "" {
+			ctx.Next()
+			return
+		}
+		ctx.State().Set(TenantStateKey, id)
+		tenant := usages.Bucket(id)
+
+		// the quota is charged to the tenant itself, not to the TenantOther label
+		if cfgX.Quota != nil {
+			usage, ok, err := cfgX.Quota.Consume(id, 1)
+			if err != nil {
+				// fail open, the quota store should not take down the api
+				ctx.RequestLogger().Errorf("[middleware][tenant_metrics] failed to consume quota of tenant(%s): %s", id, err)
+			} else {
+				setQuotaHeaders(ctx, usage)
+
+				if !ok {
+					usages.Reject(tenant)
+					requests.WithLabelValues(tenant, ctx.Method, fmt.Sprintf("%d", http.StatusTooManyRequests)).Inc()
+					ctx.Fail(errors.New("tenant quota exceeded"), http.StatusTooManyRequests, "Quota Exceeded", http.StatusTooManyRequests)
+					return
+				}
+			}
+		}
+
+		start := time.Now()
+
+		ctx.Next()
+
+		duration := time.Since(start)
+		status := ctx.StatusCode()
+		requests.WithLabelValues(tenant, ctx.Method, fmt.Sprintf("%d", status)).Inc()
+		durations.WithLabelValues(tenant, ctx.Method).Observe(duration.Seconds())
+		usages.Record(tenant, status, ctx.Writer.Size(), duration)
+	}
+}
+
+func defaultTenantFunc(ctx *zoox.Context) string {
+	if tenant := ctx.Tenant(); tenant != nil && tenant.Verified() {
+		return tenant.ID
+	}
+
+	return ""
+}
+
+// registerCollector registers the collector, reuses the registered one if exists.
+func registerCollector(registerer prometheus.Registerer, collector prometheus.Collector) prometheus.Collector {
+	if err := registerer.Register(collector); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector
+		}
+
+		panic(fmt.Errorf("failed to register prometheus collector: %s", err))
+	}
+
+	return collector
+}
+
+// Bucket returns TenantOther for the tenant out of the allowlist or over the max tenants.
+func (u *tenantUsages) Bucket(tenant string) string {
+	if u.allowlist != nil && !u.allowlist[tenant] {
+		return TenantOther
+	}
+
+	u.Lock()
+	defer u.Unlock()
+
+	if _, ok := u.data[tenant]; !ok && len(u.data) >= u.maxTenants {
+		return TenantOther
+	}
+
+	return tenant
+}
+
+func (u *tenantUsages) get(tenant string) *TenantUsage {
+	usage, ok := u.data[tenant]
+	if !ok {
+		usage = &TenantUsage{
+			Tenant: tenant,
+		}
+		u.data[tenant] = usage
+	}
+
+	return usage
+}
+
+// Reject counts the request rejected by the quota.
+func (u *tenantUsages) Reject(tenant string) {
+	u.Lock()
+	defer u.Unlock()
+
+	usage := u.get(tenant)
+	usage.LastSeenAt = time.Now()
+	usage.Rejected++
+}
+
+// Record records the finished request.
+func (u *tenantUsages) Record(tenant string, status int, size int, duration time.Duration) {
+	u.Lock()
+	defer u.Unlock()
+
+	usage := u.get(tenant)
+	usage.LastSeenAt = time.Now()
+	usage.Requests++
+	if status >= http.StatusInternalServerError {
+		usage.Errors++
+	}
+	if size > 0 {
+		usage.BytesWritten += int64(size)
+	}
+	usage.totalDuration += duration
+}
+
+// Report returns the usages of all tenants, with the quota usages if q is not nil.
+func (u *tenantUsages) Report(q *quota.Quota) []TenantUsage {
+	u.Lock()
+	report := make([]TenantUsage, 0, len(u.data))
+	for _, usage := range u.data {
+		one := *usage
+		one.TotalDuration = usage.totalDuration.String()
+		report = append(report, one)
+	}
+	u.Unlock()
+
+	// the quota store may be remote, it is read without holding the lock
+	if q != nil {
+		for i := range report {
+			if report[i].Tenant == TenantOther {
+				continue
+			}
+
+			if usage, err := q.Usage(report[i].Tenant); err == nil {
+				report[i].Quota = usage
+			}
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Tenant < report[j].Tenant
+	})
+
+	return report
+}
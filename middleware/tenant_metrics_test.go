@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/quota"
+	"github.com/go-zoox/zoox/components/application/tenancy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantMetricsQuotaOfVerifiedTenants(t *testing.T) {
+	app := zoox.New()
+	app.SetTenancy(tenancy.New(&tenancy.Config{
+		Resolvers: []tenancy.Resolver{tenancy.FromHeader(tenancy.DefaultHeader)},
+		Loader: func(id string) (any, error) {
+			if id != "acme" && id != "globex" {
+				return nil, tenancy.ErrNotFound
+			}
+
+			return nil, nil
+		},
+	}))
+	q := quota.New(&quota.Config{Namespace: "test", Limits: quota.Limits{Daily: 2}})
+	app.Use(TenantMetrics(&TenantMetricsConfig{
+		Quota: q,
+		ReportAuth: func(ctx *zoox.Context) bool {
+			return ctx.Header().Get("Authorization") == "Bearer admin"
+		},
+		Registerer: prometheus.NewRegistry(),
+	}))
+	app.Get("/", func(ctx *zoox.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	cases := []struct {
+		name      string
+		tenant    string
+		status    int
+		remaining string
+	}{
+		{"verified", "acme", http.StatusOK, "1"},
+		{"verified, last one", "acme", http.StatusOK, "0"},
+		{"verified, exhausted", "acme", http.StatusTooManyRequests, "0"},
+		{"other verified tenant has its own quota", "globex", http.StatusOK, "1"},
+		{"spoofed tenant is not tracked", "spoofed", http.StatusOK, ""},
+		{"no tenant", "", http.StatusOK, ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.tenant != "" {
+			req.Header.Set(tenancy.DefaultHeader, c.tenant)
+		}
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, req)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+		assert.Equal(t, c.remaining, recorder.Header().Get("X-Quota-Remaining"), c.name)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, DefaultTenantUsagePath, nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var report []TenantUsage
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	if assert.Len(t, report, 2) {
+		assert.Equal(t, "acme", report[0].Tenant)
+		assert.Equal(t, int64(2), report[0].Requests)
+		assert.Equal(t, int64(1), report[0].Rejected)
+		assert.Equal(t, int64(2), report[0].Quota.Tightest().Used)
+		assert.Equal(t, "globex", report[1].Tenant)
+	}
+}
+
+func TestTenantMetricsBoundsTenants(t *testing.T) {
+	app := zoox.New()
+	app.Use(TenantMetrics(&TenantMetricsConfig{
+		TenantFunc: func(ctx *zoox.Context) string {
+			return ctx.Header().Get("X-Tenant")
+		},
+		MaxTenants: 2,
+		ReportAuth: func(ctx *zoox.Context) bool {
+			return ctx.Header().Get("Authorization") == "Bearer admin"
+		},
+		Registerer: prometheus.NewRegistry(),
+	}))
+	app.Get("/", func(ctx *zoox.Context) {
+		ctx.String(http.StatusOK, "ok")
+	})
+
+	for _, tenant := range []string{"a", "b", "c", "d", "a"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DefaultTenantUsagePath, nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	req := httptest.NewRequest(http.MethodGet, DefaultTenantUsagePath, nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var report []TenantUsage
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+
+	requests := map[string]int64{}
+	for _, usage := range report {
+		requests[usage.Tenant] = usage.Requests
+	}
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1, TenantOther: 2}, requests)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/zoox"
+	"github.com/go-zoox/zoox/components/application/webhook"
+)
+
+// DefaultWebhooksAdminPath ...
+const DefaultWebhooksAdminPath = "/_/webhooks"
+
+// WebhooksAdminConfig ...
+type WebhooksAdminConfig struct {
+	// Path is the prefix of admin api, default: /_/webhooks.
+	Path string
+	// Token is the bearer token to access the admin api, required.
+	Token string
+}
+
+// WebhooksAdmin serves the admin api of outbound webhooks:
+//
+//	GET  /_/webhooks/deliveries?status=failed  => list deliveries
+//	POST /_/webhooks/deliveries/:id/redrive    => redrive the failed delivery
+func WebhooksAdmin(cfg *WebhooksAdminConfig) zoox.Middleware {
+	if cfg.Token == "" {
+		panic("webhooks admin token is required")
+	}
+
+	prefix := cfg.Path
+	if prefix == "" {
+		prefix = DefaultWebhooksAdminPath
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return func(ctx *zoox.Context) {
+		if ctx.Path != prefix && !strings.HasPrefix(ctx.Path, prefix+"/") {
+			ctx.Next()
+			return
+		}
+
+		if token, ok := ctx.BearerToken(); !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+			ctx.Fail(nil, http.StatusUnauthorized, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(ctx.Path, prefix), "/"), "/")
+		switch {
+		case len(parts) == 1 && parts[0] == "deliveries" && ctx.Method == http.MethodGet:
+			deliveries, err := ctx.App.Webhooks().Deliveries(ctx.Query().Get("status").String())
+			if err != nil {
+				ctx.Fail(err, http.StatusInternalServerError, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			ctx.Success(deliveries)
+		case len(parts) == 3 && parts[0] == "deliveries" && parts[2] == "redrive" && ctx.Method == http.MethodPost:
+			if err := ctx.App.Webhooks().Redrive(parts[1]); err != nil {
+				if errors.Is(err, webhook.ErrNotFound) {
+					ctx.Fail(err, http.StatusNotFound, err.Error(), http.StatusNotFound)
+					return
+				}
+
+				ctx.Fail(err, http.StatusBadRequest, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx.RequestLogger().Infof("[middleware][webhooks] delivery(%s) redriven", parts[1])
+			ctx.Success(nil)
+		default:
+			ctx.Fail(nil, http.StatusNotFound, "not found", http.StatusNotFound)
+		}
+	}
+}
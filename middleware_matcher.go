@@ -0,0 +1,71 @@
+package zoox
+
+import (
+	"path"
+	"strings"
+)
+
+// pathMatcher is the path filter of the middleware, created by Skip or Only.
+type pathMatcher struct {
+	patterns []string
+	only     bool
+}
+
+// Skip returns the middleware which is skipped for the paths matching the glob patterns.
+//
+// Example:
+//
+//	app.Use(zoox.Skip(middleware.Logger(), "/healthz", "/metrics"))
+//
+// Patterns:
+//
+//	/healthz    => exact path
+//	/static/*   => one path segment, see path.Match
+//	/assets/**  => the prefix and all sub paths
+func Skip(middleware HandlerFunc, patterns ...string) HandlerFunc {
+	return (&pathMatcher{patterns: patterns}).wrap(middleware)
+}
+
+// Only returns the middleware which runs only for the paths matching the glob patterns, see Skip for patterns.
+//
+// Example:
+//
+//	app.Use(zoox.Only(middleware.BasicAuth("admin", users), "/admin/**"))
+func Only(middleware HandlerFunc, patterns ...string) HandlerFunc {
+	return (&pathMatcher{patterns: patterns, only: true}).wrap(middleware)
+}
+
+// match returns true if the path matches any of the patterns.
+func (m *pathMatcher) match(p string) bool {
+	for _, pattern := range m.patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if p == prefix || strings.HasPrefix(p, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if pattern == p {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wrap returns the middleware which runs only when the path is not filtered.
+func (m *pathMatcher) wrap(middleware HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		if m.match(ctx.Path) != m.only {
+			ctx.Next()
+			return
+		}
+
+		middleware(ctx)
+	}
+}
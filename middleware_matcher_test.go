@@ -0,0 +1,57 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipAndOnly(t *testing.T) {
+	app := New()
+
+	var calls []string
+	app.Use(
+		Skip(func(ctx *Context) {
+			calls = append(calls, "logger")
+			ctx.Next()
+		}, "/healthz", "/static/*"),
+		Only(func(ctx *Context) {
+			calls = append(calls, "auth")
+			ctx.Next()
+		}, "/admin/**"),
+	)
+	// used in a separate call
+	app.Use(Skip(func(ctx *Context) {
+		calls = append(calls, "metrics")
+		ctx.Next()
+	}, "/healthz"))
+
+	for _, path := range []string{"/healthz", "/static/app.js", "/static/js/app.js", "/admin", "/admin/users/1", "/administrator"} {
+		app.Get(path, func(ctx *Context) {
+			ctx.String(http.StatusOK, "ok")
+		})
+	}
+
+	testcases := []struct {
+		path  string
+		calls []string
+	}{
+		{"/healthz", nil},
+		{"/static/app.js", []string{"metrics"}},
+		{"/static/js/app.js", []string{"logger", "metrics"}},
+		{"/admin", []string{"logger", "auth", "metrics"}},
+		{"/admin/users/1", []string{"logger", "auth", "metrics"}},
+		{"/administrator", []string{"logger", "metrics"}},
+	}
+
+	for _, testcase := range testcases {
+		calls = nil
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testcase.path, nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code, testcase.path)
+		assert.Equal(t, testcase.calls, calls, testcase.path)
+	}
+}
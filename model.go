@@ -0,0 +1,139 @@
+package zoox
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ErrModelNotFound is returned by the model loader if the record does not exist, responded as 404.
+var ErrModelNotFound = errors.New("model not found")
+
+// ModelLoader loads the record of the route param (such as id=1) into the model (pointer to a new struct).
+// It returns ErrModelNotFound (or sql.ErrNoRows) if the record does not exist.
+type ModelLoader func(ctx *Context, model any, param, value string) error
+
+// SetModelLoader sets the default loader of route.BindModel, default: query app.Database() by the param column.
+func (app *Application) SetModelLoader(loader ModelLoader) {
+	app.modelLoader = loader
+}
+
+// BindModel loads the record of the route param before the handler runs, responds 404 if it does not exist,
+// the loaded model (a new instance of the type of model per request) is available with ctx.Model(param):
+//
+//	app.Route(http.MethodGet, "/users/:id", func(ctx *zoox.Context) {
+//		user := ctx.Model("id").(*User)
+//		ctx.JSON(http.StatusOK, user)
+//	}).BindModel("id", &User{})
+//
+// The loader is the given one, app.SetModelLoader, or the database loader, which queries app.Database() with
+// SELECT <columns> FROM <table> WHERE <param> = ?, columns are the db tags (or snake_case field names),
+// table is the TableName() method of model (or snake_case type name + s).
+func (r *Route) BindModel(param string, model any, loader ...ModelLoader) *Route {
+	typ := reflect.TypeOf(model)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("[router] failed to bind model of route(%s): model must be a pointer to struct, got %T", r.path, model))
+	}
+
+	r.prepend(func(ctx *Context) {
+		value := ctx.Param().Get(param).String()
+		instance := reflect.New(typ.Elem()).Interface()
+
+		load := ctx.App.modelLoader
+		if len(loader) > 0 && loader[0] != nil {
+			load = loader[0]
+		}
+		if load == nil {
+			load = databaseModelLoader
+		}
+
+		if err := load(ctx, instance, param, value); err != nil {
+			if errors.Is(err, ErrModelNotFound) || errors.Is(err, sql.ErrNoRows) {
+				ctx.Fail(err, http.StatusNotFound, fmt.Sprintf("%s not found", typ.Elem().Name()), http.StatusNotFound)
+				return
+			}
+
+			ctx.RequestLogger().Errorf("[router] failed to load model %s(%s=%s): %s", typ.Elem().Name(), param, value, err)
+			ctx.Fail(err, http.StatusInternalServerError, "failed to load model", http.StatusInternalServerError)
+			return
+		}
+
+		ctx.State().Set(ModelStateKey(param), instance)
+		ctx.Next()
+	})
+
+	return r
+}
+
+// ModelStateKey returns the ctx.State key of the model bound to the route param.
+func ModelStateKey(param string) string {
+	return "model:" + param
+}
+
+// Model returns the model bound to the route param by route.BindModel, nil if not bound.
+func (ctx *Context) Model(param string) any {
+	return ctx.State().Get(ModelStateKey(param))
+}
+
+// databaseModelLoader queries the record from app.Database() by the param column.
+func databaseModelLoader(ctx *Context, model any, param, value string) error {
+	v := reflect.ValueOf(model).Elem()
+	t := v.Type()
+
+	table := snakeCase(t.Name()) + "s"
+	if named, ok := model.(interface{ TableName() string }); ok {
+		table = named.TableName()
+	}
+
+	columns := []string{}
+	fields := []any{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		column := snakeCase(f.Name)
+		if tag, ok := f.Tag.Lookup("db"); ok {
+			if tag == "-" {
+				continue
+			}
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				column = name
+			}
+		}
+
+		columns = append(columns, column)
+		fields = append(fields, v.Field(i).Addr().Interface())
+	}
+
+	placeholder := "?"
+	if driver := ctx.App.Config.Database.Driver; strings.Contains(driver, "postgres") || strings.Contains(driver, "pgx") {
+		placeholder = "$1"
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s", strings.Join(columns, ", "), table, snakeCase(param), placeholder)
+	return ctx.App.Database().QueryRowContext(ctx.Context(), query, value).Scan(fields...)
+}
+
+// snakeCase converts the go name to snake case, such as UserID => user_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	b := &strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package zoox
+
+import (
+	"github.com/go-zoox/zoox/components/openapi"
+)
+
+// OpenAPI returns the openapi document of the registered routes,
+// the request and response schemas are inferred from the route examples (Route.Example).
+//
+// It is served by middleware.OpenAPI, and can be used by the mock server:
+//
+//	zoox mock http://127.0.0.1:8080/openapi.json
+func (app *Application) OpenAPI() *openapi.Document {
+	title := app.Config.Name
+	if title == "" {
+		title = DefaultName
+	}
+
+	routes := []openapi.RouteSpec{}
+	for _, route := range app.Routes() {
+		one := openapi.RouteSpec{
+			Method:  route.Method,
+			Path:    route.Path,
+			Summary: route.Handler,
+		}
+
+		if route.Example != nil {
+			one.RequestExample = route.Example.Request
+			one.ResponseExample = route.Example.Response
+		}
+
+		routes = append(routes, one)
+	}
+
+	return openapi.FromRoutes(openapi.Info{Title: title, Version: "1.0.0"}, routes)
+}
@@ -0,0 +1,106 @@
+package zoox
+
+import (
+	"database/sql"
+
+	"github.com/go-zoox/cache"
+	"github.com/go-zoox/logger"
+	"github.com/go-zoox/zoox/components/application/tiered"
+	"github.com/go-zoox/zoox/config"
+)
+
+// Option is the option of New, so that the app is created fully configured in one call,
+// the options are applied in order before the default middlewares.
+//
+// Example:
+//
+//	app := zoox.New(
+//		zoox.WithName("api"),
+//		zoox.WithPort(8080),
+//		zoox.WithRedis(config.Redis{Host: "127.0.0.1", Port: 6379}),
+//	)
+type Option func(app *Application)
+
+// WithConfig replaces the config, the options after it are applied on the config.
+func WithConfig(cfg config.Config) Option {
+	return func(app *Application) {
+		app.Config = cfg
+	}
+}
+
+// WithConfigFunc changes the config, such as the fields without dedicated options.
+func WithConfigFunc(fn func(cfg *config.Config)) Option {
+	return func(app *Application) {
+		fn(&app.Config)
+	}
+}
+
+// WithName sets the app name.
+func WithName(name string) Option {
+	return func(app *Application) {
+		app.Config.Name = name
+	}
+}
+
+// WithHost sets the host of the http server.
+func WithHost(host string) Option {
+	return func(app *Application) {
+		app.Config.Host = host
+	}
+}
+
+// WithPort sets the port of the http server.
+func WithPort(port int) Option {
+	return func(app *Application) {
+		app.Config.Port = port
+	}
+}
+
+// WithHTTPSPort sets the port of the https server.
+func WithHTTPSPort(port int) Option {
+	return func(app *Application) {
+		app.Config.HTTPSPort = port
+	}
+}
+
+// WithLogLevel sets the log level.
+func WithLogLevel(level string) Option {
+	return func(app *Application) {
+		app.Config.LogLevel = level
+	}
+}
+
+// WithSecretKey sets the secret key of the sessions, signed urls and encryption.
+func WithSecretKey(secretKey string) Option {
+	return func(app *Application) {
+		app.Config.SecretKey = secretKey
+	}
+}
+
+// WithRedis sets the redis, used by the cache, pubsub and other shared storages.
+func WithRedis(cfg config.Redis) Option {
+	return func(app *Application) {
+		app.Config.Redis = cfg
+	}
+}
+
+// WithLogger sets the logger of app.Logger.
+func WithLogger(l *logger.Logger) Option {
+	return func(app *Application) {
+		app.logger = l
+	}
+}
+
+// WithCache sets the cache of app.Cache, such as an in-memory cache for tests.
+func WithCache(c cache.Cache) Option {
+	return func(app *Application) {
+		app.cache = tiered.From(c)
+	}
+}
+
+// WithDatabase sets the sql database of app.Database.
+func WithDatabase(db *sql.DB) Option {
+	return func(app *Application) {
+		app.SetDatabase(db)
+	}
+}
@@ -0,0 +1,229 @@
+package zoox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// middlewareEntry is a registered middleware with its ordering constraints.
+type middlewareEntry struct {
+	name    string
+	handler HandlerFunc
+	before  []string
+	after   []string
+	//
+	seq int
+}
+
+// MiddlewareOption is the option of named middleware, such as the ordering constraints.
+type MiddlewareOption func(e *middlewareEntry)
+
+// Before places the middleware before the given named middlewares.
+func Before(names ...string) MiddlewareOption {
+	return func(e *middlewareEntry) {
+		e.before = append(e.before, names...)
+	}
+}
+
+// After places the middleware after the given named middlewares.
+func After(names ...string) MiddlewareOption {
+	return func(e *middlewareEntry) {
+		e.after = append(e.after, names...)
+	}
+}
+
+// UseNamed adds a named middleware to the group with ordering constraints.
+//
+// Example:
+//
+//	app.UseNamed("auth", middleware.Jwt(), zoox.After("logger"), zoox.Before("proxy"))
+//
+// The pipeline is resolved into a deterministic chain: constraints first, then registration order.
+// The constraints only apply within the group, cycles panic on registration,
+// and the names unknown in the group are reported by app.Run.
+func (g *RouterGroup) UseNamed(name string, middleware HandlerFunc, opts ...MiddlewareOption) {
+	if name == "" {
+		panic("zoox: middleware name is required")
+	}
+
+	for _, e := range g.entries {
+		if e.name == name {
+			panic(fmt.Errorf("zoox: middleware(%s) has been already registered", name))
+		}
+	}
+
+	e := &middlewareEntry{
+		name:    name,
+		handler: middleware,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	g.addMiddlewareEntry(e)
+}
+
+// Middlewares returns the names of the resolved middleware pipeline,
+// anonymous middlewares (added by Use) are named as anonymous#<index>.
+// The registration order is returned if the pipeline can not be resolved.
+func (g *RouterGroup) Middlewares() []string {
+	entries, err := g.resolvedEntries()
+	if err != nil {
+		entries = g.entries
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.name)
+	}
+
+	return names
+}
+
+func (g *RouterGroup) addMiddlewareEntry(e *middlewareEntry) {
+	e.seq = len(g.entries)
+	if e.name == "" {
+		e.name = fmt.Sprintf("anonymous#%d", e.seq)
+	}
+
+	g.entries = append(g.entries, e)
+	if err := g.resolveMiddlewares(); err != nil {
+		g.entries = g.entries[:len(g.entries)-1]
+		panic(err)
+	}
+}
+
+// resolveMiddlewares resolves the pipeline into g.middlewares.
+func (g *RouterGroup) resolveMiddlewares() error {
+	entries, err := g.resolvedEntries()
+	if err != nil {
+		return err
+	}
+
+	middlewares := make([]HandlerFunc, 0, len(entries))
+	for _, e := range entries {
+		middlewares = append(middlewares, e.handler)
+	}
+
+	g.middlewares = middlewares
+
+	if g.app != nil {
+		g.app.resetGroupMiddlewareCache()
+	}
+
+	return nil
+}
+
+// checkMiddlewareNames returns the error of the Before/After names not registered in the group,
+// the names registered later are allowed until the app runs.
+func (g *RouterGroup) checkMiddlewareNames() error {
+	registered := map[string]bool{}
+	for _, e := range g.entries {
+		registered[e.name] = true
+	}
+
+	for _, e := range g.entries {
+		for _, name := range append(append([]string{}, e.before...), e.after...) {
+			if registered[name] {
+				continue
+			}
+
+			if other := g.app.middlewareGroup(name); other != nil {
+				return fmt.Errorf("zoox: middleware(%s) of group(%s) is ordered against middleware(%s) of another group(%s), the constraints only apply within the group", e.name, g.displayPrefix(), name, other.displayPrefix())
+			}
+
+			return fmt.Errorf("zoox: middleware(%s) of group(%s) is ordered against the unknown middleware(%s)", e.name, g.displayPrefix(), name)
+		}
+	}
+
+	return nil
+}
+
+// displayPrefix returns the prefix of the group in messages, / for the app.
+func (g *RouterGroup) displayPrefix() string {
+	if g.prefix == "" {
+		return "/"
+	}
+
+	return g.prefix
+}
+
+// resolvedEntries sorts the entries topologically, ties are broken by registration order.
+func (g *RouterGroup) resolvedEntries() ([]*middlewareEntry, error) {
+	index := map[string]*middlewareEntry{}
+	for _, e := range g.entries {
+		index[e.name] = e
+	}
+
+	edges := map[*middlewareEntry]map[*middlewareEntry]bool{}
+	indegree := map[*middlewareEntry]int{}
+	addEdge := func(from, to *middlewareEntry) {
+		if from == nil || to == nil || from == to {
+			return
+		}
+
+		if edges[from] == nil {
+			edges[from] = map[*middlewareEntry]bool{}
+		}
+		if !edges[from][to] {
+			edges[from][to] = true
+			indegree[to]++
+		}
+	}
+
+	for _, e := range g.entries {
+		for _, name := range e.before {
+			addEdge(e, index[name])
+		}
+		for _, name := range e.after {
+			addEdge(index[name], e)
+		}
+	}
+
+	// config driven order: app.Config.MiddlewareOrder
+	if g.app != nil {
+		order := g.app.Config.MiddlewareOrder
+		for i := 1; i < len(order); i++ {
+			addEdge(index[order[i-1]], index[order[i]])
+		}
+	}
+
+	available := []*middlewareEntry{}
+	for _, e := range g.entries {
+		if indegree[e] == 0 {
+			available = append(available, e)
+		}
+	}
+
+	resolved := make([]*middlewareEntry, 0, len(g.entries))
+	for len(available) > 0 {
+		sort.Slice(available, func(i, j int) bool {
+			return available[i].seq < available[j].seq
+		})
+
+		e := available[0]
+		available = available[1:]
+		resolved = append(resolved, e)
+
+		for to := range edges[e] {
+			indegree[to]--
+			if indegree[to] == 0 {
+				available = append(available, to)
+			}
+		}
+	}
+
+	if len(resolved) != len(g.entries) {
+		cycle := []string{}
+		for _, e := range g.entries {
+			if indegree[e] > 0 {
+				cycle = append(cycle, e.name)
+			}
+		}
+
+		return nil, fmt.Errorf("zoox: middleware ordering cycle detected among: %s", strings.Join(cycle, ", "))
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,103 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func namedMiddleware(calls *[]string, name string) HandlerFunc {
+	return func(ctx *Context) {
+		*calls = append(*calls, name)
+		ctx.Next()
+	}
+}
+
+func TestMiddlewarePipelineOrder(t *testing.T) {
+	var calls []string
+	app := New()
+	app.UseNamed("proxy", namedMiddleware(&calls, "proxy"))
+	app.UseNamed("auth", namedMiddleware(&calls, "auth"), After("logger"), Before("proxy"))
+	app.UseNamed("logger", namedMiddleware(&calls, "logger"))
+	app.Use(namedMiddleware(&calls, "anonymous"))
+	app.UseNamed("recovery", namedMiddleware(&calls, "recovery"), Before("logger"))
+	app.Get("/", func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+
+	// the unconstrained middlewares are kept in registration order
+	assert.Equal(t, []string{"anonymous#3", "recovery", "logger", "auth", "proxy"}, app.Middlewares())
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"anonymous", "recovery", "logger", "auth", "proxy", "handler"}, calls)
+}
+
+func TestMiddlewarePipelineConfigOrder(t *testing.T) {
+	var calls []string
+	app := New()
+	app.Config.MiddlewareOrder = []string{"request_id", "logger", "unknown"}
+	app.UseNamed("logger", namedMiddleware(&calls, "logger"))
+	app.UseNamed("auth", namedMiddleware(&calls, "auth"))
+	app.UseNamed("request_id", namedMiddleware(&calls, "request_id"))
+	app.Get("/", func(ctx *Context) {})
+
+	// the unknown names of the config order are ignored, they may be registered by other groups
+	assert.NoError(t, app.resolveMiddlewares())
+	assert.Equal(t, []string{"auth", "request_id", "logger"}, app.Middlewares())
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"auth", "request_id", "logger"}, calls)
+}
+
+func TestMiddlewarePipelineCycle(t *testing.T) {
+	app := New()
+	app.UseNamed("a", func(ctx *Context) {}, Before("b"))
+
+	assert.PanicsWithError(t, "zoox: middleware ordering cycle detected among: a, b", func() {
+		app.UseNamed("b", func(ctx *Context) {}, Before("a"))
+	})
+	// the failed registration is dropped
+	assert.Equal(t, []string{"a"}, app.Middlewares())
+}
+
+func TestMiddlewarePipelineConfigCycle(t *testing.T) {
+	app := New()
+	app.UseNamed("a", func(ctx *Context) {}, Before("b"))
+	app.UseNamed("b", func(ctx *Context) {})
+
+	// the config is loaded after the middlewares are registered
+	app.Config.MiddlewareOrder = []string{"b", "a"}
+	assert.EqualError(t, app.resolveMiddlewares(), "zoox: middleware ordering cycle detected among: a, b")
+	// the registration order is reported
+	assert.Equal(t, []string{"a", "b"}, app.Middlewares())
+}
+
+func TestMiddlewarePipelineUnknownNames(t *testing.T) {
+	app := New()
+	app.UseNamed("auth", func(ctx *Context) {}, After("loger"))
+	assert.EqualError(t, app.resolveMiddlewares(), "zoox: middleware(auth) of group(/) is ordered against the unknown middleware(loger)")
+
+	app = New()
+	app.UseNamed("logger", func(ctx *Context) {})
+	api := app.Group("/api")
+	api.UseNamed("auth", func(ctx *Context) {}, After("logger"))
+	assert.EqualError(t, app.resolveMiddlewares(), "zoox: middleware(auth) of group(/api) is ordered against middleware(logger) of another group(/), the constraints only apply within the group")
+
+	// registered later in the group
+	app = New()
+	app.UseNamed("auth", func(ctx *Context) {}, After("logger"))
+	app.UseNamed("logger", func(ctx *Context) {})
+	assert.NoError(t, app.resolveMiddlewares())
+	assert.Equal(t, []string{"logger", "auth"}, app.Middlewares())
+}
+
+func TestMiddlewarePipelineDuplicateName(t *testing.T) {
+	app := New()
+	app.UseNamed("auth", func(ctx *Context) {})
+
+	assert.Panics(t, func() {
+		app.UseNamed("auth", func(ctx *Context) {})
+	})
+}
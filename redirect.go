@@ -0,0 +1,55 @@
+package zoox
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-zoox/zoox/components/application/redirect"
+)
+
+// AddRedirect adds the redirect (or rewrite) rule, evaluated in order before routing, the first matched rule wins.
+// Rules can also be loaded from config redirects:
+//
+//	app.AddRedirect(&redirect.Rule{From: "/blog/:slug", To: "/posts/:slug", Status: http.StatusPermanentRedirect})
+//	app.AddRedirect(&redirect.Rule{From: `^/p/(\d+)$`, To: "/products/$1", Regex: true, Rewrite: true})
+//
+//	# config
+//	redirects:
+//	  - from: /docs/*path
+//	    to: https://docs.example.com/*path
+//	    status: 302
+//	    preserve_query: true
+func (app *Application) AddRedirect(rule *redirect.Rule) error {
+	if app.redirects == nil {
+		app.redirects = &redirect.Table{}
+	}
+
+	return app.redirects.Add(rule)
+}
+
+// handleRedirect redirects or rewrites the request by the matched rule, returns true if redirected.
+func (app *Application) handleRedirect(ctx *Context) bool {
+	result, ok := app.redirects.Match(ctx.Request.Host, ctx.Request.URL)
+	if !ok {
+		return false
+	}
+
+	if !result.Rewrite {
+		http.Redirect(ctx.Writer, ctx.Request, result.Target, result.Status)
+		return true
+	}
+
+	target, err := url.Parse(result.Target)
+	if err != nil {
+		ctx.RequestLogger().Errorf("[redirect] invalid rewrite target %s: %s", result.Target, err)
+		return false
+	}
+
+	ctx.Request.URL.Path = target.Path
+	ctx.Request.URL.RawPath = ""
+	if target.RawQuery != "" {
+		ctx.Request.URL.RawQuery = target.RawQuery
+	}
+	ctx.Path = target.Path
+	return false
+}
@@ -0,0 +1,45 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox/components/application/redirect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectRules(t *testing.T) {
+	app := New()
+	assert.NoError(t, app.AddRedirect(&redirect.Rule{From: "/blog/:slug", To: "/posts/:slug", Status: http.StatusPermanentRedirect}))
+	assert.NoError(t, app.AddRedirect(&redirect.Rule{From: `^/p/(\d+)$`, To: "/products/$1", Regex: true, Rewrite: true}))
+	assert.NoError(t, app.AddRedirect(&redirect.Rule{From: "/go/*path", To: "/*path", Status: http.StatusFound}))
+	assert.Error(t, app.AddRedirect(&redirect.Rule{From: "/old", To: "/new", Status: http.StatusOK}))
+	app.Get("/products/:id", func(ctx *Context) {
+		ctx.String(http.StatusOK, "product %s", ctx.Param().Get("id"))
+	})
+
+	cases := []struct {
+		name     string
+		path     string
+		status   int
+		location string
+		body     string
+	}{
+		{"redirect", "/blog/hello", http.StatusPermanentRedirect, "/posts/hello", ""},
+		{"rewrite", "/p/42", http.StatusOK, "", "product 42"},
+		{"relative redirect", "/go/products/1", http.StatusFound, "/products/1", ""},
+		{"open redirect is refused", "/go//evil.com", http.StatusNotFound, "", ""},
+		{"invalid rule is not added", "/old", http.StatusNotFound, "", ""},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+		assert.Equal(t, c.location, recorder.Header().Get("Location"), c.name)
+		if c.body != "" {
+			assert.Equal(t, c.body, recorder.Body.String(), c.name)
+		}
+	}
+}
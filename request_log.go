@@ -0,0 +1,103 @@
+package zoox
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/go-zoox/zoox/components/application/logsink"
+)
+
+// RequestLog returns the async writer of the request logs, the sinks are created by config request_log,
+// default stdout json lines.
+//
+//	# config
+//	request_log:
+//	  file: /var/log/app/access.log
+//	  max_size: 104857600
+//	  syslog: udp://127.0.0.1:514
+//	  otlp: http://127.0.0.1:4318
+//	  drop_policy: drop_oldest
+func (app *Application) RequestLog() logsink.Writer {
+	app.once.logsink.Do(func() {
+		if app.logsink != nil {
+			return
+		}
+
+		cfg := app.Config.RequestLog
+		sinks := []logsink.Sink{}
+		if cfg.File != "" {
+			sink, err := logsink.NewFile(&logsink.FileConfig{
+				Path:       cfg.File,
+				MaxSize:    cfg.MaxSize,
+				MaxBackups: cfg.MaxBackups,
+			})
+			if err != nil {
+				panic(fmt.Errorf("failed to create request log file: %v", err))
+			}
+
+			sinks = append(sinks, sink)
+		}
+
+		if cfg.Syslog != "" {
+			u, err := url.Parse(cfg.Syslog)
+			if err != nil {
+				panic(fmt.Errorf("invalid request log syslog address: %v", err))
+			}
+
+			address := u.Host
+			if u.Scheme == "unix" || u.Scheme == "unixgram" {
+				address = u.Path
+			}
+
+			sinks = append(sinks, logsink.NewSyslog(&logsink.SyslogConfig{
+				Network: u.Scheme,
+				Address: address,
+				Tag:     app.Config.Name,
+			}))
+		}
+
+		if cfg.OTLP != "" {
+			sinks = append(sinks, logsink.NewOTLP(&logsink.OTLPConfig{
+				Endpoint:    cfg.OTLP,
+				ServiceName: app.Config.Name,
+			}))
+		}
+
+		if cfg.Stdout || len(sinks) == 0 {
+			sinks = append(sinks, logsink.NewStdout())
+		}
+
+		var policy logsink.Policy
+		switch cfg.DropPolicy {
+		case "", "drop_newest":
+			policy = logsink.DropNewest
+		case "drop_oldest":
+			policy = logsink.DropOldest
+		case "block":
+			policy = logsink.Block
+		default:
+			panic(fmt.Errorf("unsupported request log drop policy: %s", cfg.DropPolicy))
+		}
+
+		app.logsink = logsink.New(&logsink.Config{
+			Sinks:      sinks,
+			BufferSize: cfg.BufferSize,
+			Policy:     policy,
+			OnError: func(sink logsink.Sink, records []*logsink.Record, err error) {
+				app.Logger().Errorf("[request_log] failed to write %d records: %s", len(records), err)
+			},
+		})
+	})
+
+	return app.logsink
+}
+
+// SetRequestLog sets the async writer of the request logs, such as:
+//
+//	app.SetRequestLog(logsink.New(&logsink.Config{
+//		Sinks:  []logsink.Sink{logsink.NewOTLP(&logsink.OTLPConfig{Endpoint: "http://127.0.0.1:4318"})},
+//		Policy: logsink.DropOldest,
+//	}))
+func (app *Application) SetRequestLog(w logsink.Writer) {
+	app.logsink = w
+}
@@ -0,0 +1,201 @@
+package zoox
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ResourceIndexer handles GET /<resources>.
+type ResourceIndexer interface {
+	Index(ctx *Context)
+}
+
+// ResourceShower handles GET /<resources>/:id.
+type ResourceShower interface {
+	Show(ctx *Context)
+}
+
+// ResourceCreator handles POST /<resources>.
+type ResourceCreator interface {
+	Create(ctx *Context)
+}
+
+// ResourceUpdater handles PUT /<resources>/:id and PATCH /<resources>/:id.
+type ResourceUpdater interface {
+	Update(ctx *Context)
+}
+
+// ResourceDeleter handles DELETE /<resources>/:id.
+type ResourceDeleter interface {
+	Delete(ctx *Context)
+}
+
+// ResourceController is the full RESTful controller,
+// a controller can also implement only some of the actions.
+type ResourceController interface {
+	ResourceIndexer
+	ResourceShower
+	ResourceCreator
+	ResourceUpdater
+	ResourceDeleter
+}
+
+// Resource actions
+const (
+	ResourceActionIndex  = "index"
+	ResourceActionShow   = "show"
+	ResourceActionCreate = "create"
+	ResourceActionUpdate = "update"
+	ResourceActionDelete = "delete"
+)
+
+// ResourceOption is the option of Resource.
+type ResourceOption struct {
+	// Param is the member param name, default: id.
+	Param string
+	// NestedParam is the param name used by nested resources, default: <singular resource>_id,
+	// such as user_id of /users and category_id of /categories, set it for the irregular plurals, such as person_id of /people.
+	NestedParam string
+	// Only registers the given actions only.
+	Only []string
+	// Except skips the given actions.
+	Except []string
+	// Middlewares are the per-action middlewares, such as {"create": {auth}}.
+	Middlewares map[string][]HandlerFunc
+}
+
+// Resource is a registered RESTful resource.
+type Resource struct {
+	group *RouterGroup
+	path  string
+	opt   *ResourceOption
+}
+
+// Resource registers RESTful CRUD routes of the controller.
+//
+// Example:
+//
+//	users := app.Resource("/users", &UserController{})
+//	// GET    /users          => Index
+//	// POST   /users          => Create
+//	// GET    /users/:id      => Show
+//	// PUT    /users/:id      => Update
+//	// PATCH  /users/:id      => Update
+//	// DELETE /users/:id      => Delete
+//
+//	users.Resource("/posts", &PostController{})
+//	// GET    /users/:user_id/posts
+//	// GET    /users/:user_id/posts/:id
+//	// ...
+func (g *RouterGroup) Resource(pathX string, controller any, opts ...func(opt *ResourceOption)) *Resource {
+	opt := &ResourceOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	if opt.Param == "" {
+		opt.Param = "id"
+	}
+	if opt.NestedParam == "" {
+		opt.NestedParam = fmt.Sprintf("%s_id", singularize(strings.ReplaceAll(path.Base(pathX), "-", "_")))
+	}
+
+	r := &Resource{
+		group: g,
+		path:  pathX,
+		opt:   opt,
+	}
+
+	member := path.Join(pathX, ":"+opt.Param)
+	registered := 0
+	if c, ok := controller.(ResourceIndexer); ok && r.enabled(ResourceActionIndex) {
+		g.addRoute(http.MethodGet, pathX, r.handlers(ResourceActionIndex, c.Index)...)
+		registered++
+	}
+	if c, ok := controller.(ResourceCreator); ok && r.enabled(ResourceActionCreate) {
+		g.addRoute(http.MethodPost, pathX, r.handlers(ResourceActionCreate, c.Create)...)
+		registered++
+	}
+	if c, ok := controller.(ResourceShower); ok && r.enabled(ResourceActionShow) {
+		g.addRoute(http.MethodGet, member, r.handlers(ResourceActionShow, c.Show)...)
+		registered++
+	}
+	if c, ok := controller.(ResourceUpdater); ok && r.enabled(ResourceActionUpdate) {
+		g.addRoute(http.MethodPut, member, r.handlers(ResourceActionUpdate, c.Update)...)
+		g.addRoute(http.MethodPatch, member, r.handlers(ResourceActionUpdate, c.Update)...)
+		registered++
+	}
+	if c, ok := controller.(ResourceDeleter); ok && r.enabled(ResourceActionDelete) {
+		g.addRoute(http.MethodDelete, member, r.handlers(ResourceActionDelete, c.Delete)...)
+		registered++
+	}
+
+	if registered == 0 && len(opt.Only) == 0 && len(opt.Except) == 0 {
+		panic(fmt.Errorf("zoox: resource(%s) controller %T implements no actions (Index/Show/Create/Update/Delete)", pathX, controller))
+	}
+
+	return r
+}
+
+// Resource registers a nested resource, such as /users/:user_id/posts.
+func (r *Resource) Resource(pathX string, controller any, opts ...func(opt *ResourceOption)) *Resource {
+	return r.group.Resource(path.Join(r.path, ":"+r.opt.NestedParam, pathX), controller, opts...)
+}
+
+// Path returns the collection path of the resource.
+func (r *Resource) Path() string {
+	return path.Join(r.group.prefix, r.path)
+}
+
+func (r *Resource) enabled(action string) bool {
+	if len(r.opt.Only) > 0 {
+		found := false
+		for _, one := range r.opt.Only {
+			if one == action {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	for _, one := range r.opt.Except {
+		if one == action {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *Resource) handlers(action string, handler HandlerFunc) []HandlerFunc {
+	handlers := append([]HandlerFunc{}, r.opt.Middlewares[action]...)
+	return append(handlers, handler)
+}
+
+// singularize returns the singular of the regular english plural,
+// such as categories => category, addresses => address and statuses => status.
+func singularize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"),
+		strings.HasSuffix(name, "shes"),
+		strings.HasSuffix(name, "ches") && !strings.HasSuffix(name, "aches"),
+		strings.HasSuffix(name, "xes"),
+		// not houses, causes
+		strings.HasSuffix(name, "uses") && len(name) > 4 && !strings.ContainsRune("aeiou", rune(name[len(name)-5])):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"),
+		strings.HasSuffix(name, "us"),
+		strings.HasSuffix(name, "is"):
+		return name
+	}
+
+	return strings.TrimSuffix(name, "s")
+}
@@ -0,0 +1,134 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userController struct{}
+
+func (c *userController) Index(ctx *Context) {
+	ctx.String(http.StatusOK, "index")
+}
+
+func (c *userController) Show(ctx *Context) {
+	ctx.String(http.StatusOK, "show "+ctx.Param().Get("id").String())
+}
+
+func (c *userController) Create(ctx *Context) {
+	ctx.String(http.StatusCreated, "create")
+}
+
+func (c *userController) Update(ctx *Context) {
+	ctx.String(http.StatusOK, "update "+ctx.Param().Get("id").String())
+}
+
+func (c *userController) Delete(ctx *Context) {
+	ctx.String(http.StatusOK, "delete "+ctx.Param().Get("id").String())
+}
+
+type addressController struct{}
+
+func (c *addressController) Show(ctx *Context) {
+	ctx.String(http.StatusOK, ctx.Param().Get("user_id").String()+"/"+ctx.Param().Get("id").String())
+}
+
+type readOnlyController struct{}
+
+func (c *readOnlyController) Index(ctx *Context) {
+	ctx.String(http.StatusOK, "index")
+}
+
+func serveResource(app *Application, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestResourceRoutes(t *testing.T) {
+	app := New()
+	app.Resource("/users", &userController{})
+
+	for _, c := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{http.MethodGet, "/users", http.StatusOK, "index"},
+		{http.MethodPost, "/users", http.StatusCreated, "create"},
+		{http.MethodGet, "/users/1", http.StatusOK, "show 1"},
+		{http.MethodPut, "/users/1", http.StatusOK, "update 1"},
+		{http.MethodPatch, "/users/1", http.StatusOK, "update 1"},
+		{http.MethodDelete, "/users/1", http.StatusOK, "delete 1"},
+	} {
+		recorder := serveResource(app, c.method, c.path)
+		assert.Equal(t, c.status, recorder.Code, c.method+" "+c.path)
+		assert.Equal(t, c.body, recorder.Body.String(), c.method+" "+c.path)
+	}
+}
+
+func TestResourceOnlyExceptAndMiddlewares(t *testing.T) {
+	app := New()
+	app.Resource("/users", &userController{}, func(opt *ResourceOption) {
+		opt.Only = []string{ResourceActionIndex, ResourceActionShow, ResourceActionDelete}
+		opt.Except = []string{ResourceActionDelete}
+		opt.Middlewares = map[string][]HandlerFunc{
+			ResourceActionShow: {func(ctx *Context) {
+				ctx.Status(http.StatusUnauthorized)
+			}},
+		}
+	})
+
+	assert.Equal(t, http.StatusOK, serveResource(app, http.MethodGet, "/users").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveResource(app, http.MethodGet, "/users/1").Code)
+	assert.Equal(t, http.StatusNotFound, serveResource(app, http.MethodPost, "/users").Code)
+	assert.Equal(t, http.StatusNotFound, serveResource(app, http.MethodDelete, "/users/1").Code)
+}
+
+func TestResourceNested(t *testing.T) {
+	app := New()
+	app.Resource("/users", &userController{}).Resource("/addresses", &addressController{})
+
+	recorder := serveResource(app, http.MethodGet, "/users/1/addresses/2")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "1/2", recorder.Body.String())
+
+	app = New()
+	app.Resource("/people", &userController{}, func(opt *ResourceOption) {
+		opt.NestedParam = "user_id"
+	}).Resource("/addresses", &addressController{})
+
+	recorder = serveResource(app, http.MethodGet, "/people/3/addresses/4")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "3/4", recorder.Body.String())
+}
+
+func TestResourceNestedParam(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/users":       "user_id",
+		"/categories":  "category_id",
+		"/addresses":   "address_id",
+		"/statuses":    "status_id",
+		"/boxes":       "box_id",
+		"/matches":     "match_id",
+		"/caches":      "cache_id",
+		"/houses":      "house_id",
+		"/dishes":      "dish_id",
+		"/blog-posts":  "blog_post_id",
+		"/status":      "status_id",
+		"/data":        "data_id",
+		"/v1/accounts": "account_id",
+	} {
+		r := New().Resource(path, &readOnlyController{})
+		assert.Equal(t, expected, r.opt.NestedParam, path)
+	}
+}
+
+func TestResourceWithoutActions(t *testing.T) {
+	assert.Panics(t, func() {
+		New().Resource("/users", struct{}{})
+	})
+}
@@ -2,6 +2,7 @@ package zoox
 
 import (
 	"bufio"
+	"fmt"
 	"net"
 	"net/http"
 
@@ -41,6 +42,18 @@ type ResponseWriter interface {
 	WriteHeaderNow()
 }
 
+// InformationalWriter is implemented by the ResponseWriter supporting 1xx informational responses,
+// such as 103 Early Hints. WriteInformational can be called multiple times before the final response.
+type InformationalWriter interface {
+	WriteInformational(code int) error
+}
+
+// rwUnwrapper is implemented by the ResponseWriter wrapping the original http.ResponseWriter,
+// also used by http.ResponseController.
+type rwUnwrapper interface {
+	Unwrap() http.ResponseWriter
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	//
@@ -53,7 +66,7 @@ func newResponseWriter(origin http.ResponseWriter) ResponseWriter {
 	return &responseWriter{
 		ResponseWriter: origin,
 		size:           noWritten,
-		status:         defaultStatus, // default status 404
+		status:         defaultStatus, // default status 200
 	}
 }
 
@@ -143,3 +156,56 @@ func (w *responseWriter) Pusher() (pusher http.Pusher) {
 	}
 	return nil
 }
+
+// WriteInformational writes a 1xx informational response with the current headers.
+func (w *responseWriter) WriteInformational(code int) error {
+	if code < 100 || code > 199 || code == http.StatusSwitchingProtocols {
+		return fmt.Errorf("invalid informational status code: %d", code)
+	}
+
+	if w.Written() {
+		return fmt.Errorf("cannot write informational response %d after the final response", code)
+	}
+
+	// the final status is kept, net/http sends 1xx without committing the response
+	w.ResponseWriter.WriteHeader(code)
+	return nil
+}
+
+// Unwrap returns the original http.ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Push implements the http.Pusher interface.
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	pusher := w.Pusher()
+	if pusher == nil {
+		return http.ErrNotSupported
+	}
+
+	return pusher.Push(target, opts)
+}
+
+// writeInformational writes a 1xx informational response if the writer implements InformationalWriter.
+func writeInformational(w ResponseWriter, code int) error {
+	if iw, ok := w.(InformationalWriter); ok {
+		return iw.WriteInformational(code)
+	}
+
+	return http.ErrNotSupported
+}
+
+// push initiates an HTTP/2 server push with the writer, which implements http.Pusher optionally,
+// http.ErrNotSupported if push is not supported.
+func push(w ResponseWriter, target string, opts *http.PushOptions) error {
+	if pusher, ok := w.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+
+	if pusher := w.Pusher(); pusher != nil {
+		return pusher.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
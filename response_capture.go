@@ -0,0 +1,207 @@
+package zoox
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-zoox/headers"
+)
+
+// CaptureFunc rewrites the captured response status and body.
+type CaptureFunc func(status int, body []byte) (int, []byte)
+
+// CaptureResponse runs the next handlers with the response buffered,
+// then writes the status and body rewritten by fn, such as html minification, json envelope or PII scrubbing.
+// It is used instead of ctx.Next() in middleware.
+//
+// Streaming responses are not captured:
+//   - websocket (connection upgrade) requests
+//   - server-sent events (content-type: text/event-stream)
+//   - the handler flushes or hijacks the connection
+//
+// Example:
+//
+//	app.Use(func(ctx *zoox.Context) {
+//		ctx.CaptureResponse(func(status int, body []byte) (int, []byte) {
+//			return status, bytes.ReplaceAll(body, []byte("secret"), []byte("******"))
+//		})
+//	})
+func (ctx *Context) CaptureResponse(fn CaptureFunc) {
+	if ctx.IsConnectionUpgrade() {
+		ctx.Next()
+		return
+	}
+
+	origin := ctx.Writer
+	writer := newCaptureWriter(origin)
+	ctx.Writer = writer
+	ctx.Response = writer
+	defer func() {
+		ctx.Writer = origin
+		ctx.Response = origin
+	}()
+
+	ctx.Next()
+
+	if writer.streaming {
+		return
+	}
+
+	status, body := fn(writer.status, writer.buf.Bytes())
+
+	header := origin.Header()
+	if header.Get(headers.ContentLength) != "" {
+		header.Set(headers.ContentLength, fmt.Sprintf("%d", len(body)))
+	}
+
+	origin.WriteHeader(status)
+	if len(body) > 0 {
+		origin.Write(body)
+	} else {
+		origin.WriteHeaderNow()
+	}
+}
+
+// captureWriter buffers the response until it is detected as streaming.
+type captureWriter struct {
+	origin ResponseWriter
+	//
+	buf    bytes.Buffer
+	status int
+	// written is true if the handler wrote header or body
+	written bool
+	// streaming means the response is passed through to origin
+	streaming bool
+}
+
+func newCaptureWriter(origin ResponseWriter) *captureWriter {
+	return &captureWriter{
+		origin: origin,
+		status: origin.Status(),
+	}
+}
+
+// stream switches to pass-through mode, the buffered data is written first.
+func (w *captureWriter) stream() {
+	if w.streaming {
+		return
+	}
+	w.streaming = true
+
+	w.origin.WriteHeader(w.status)
+	if w.written {
+		w.origin.WriteHeaderNow()
+	}
+	if w.buf.Len() > 0 {
+		w.origin.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+func (w *captureWriter) isEventStream() bool {
+	return strings.HasPrefix(w.origin.Header().Get(headers.ContentType), "text/event-stream")
+}
+
+func (w *captureWriter) Header() http.Header {
+	return w.origin.Header()
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	if w.streaming {
+		w.origin.WriteHeader(code)
+		return
+	}
+
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if !w.streaming && w.isEventStream() {
+		w.stream()
+	}
+
+	if w.streaming {
+		return w.origin.Write(b)
+	}
+
+	w.written = true
+	return w.buf.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *captureWriter) WriteHeaderNow() {
+	if w.streaming {
+		w.origin.WriteHeaderNow()
+		return
+	}
+
+	w.written = true
+}
+
+func (w *captureWriter) WriteInformational(code int) error {
+	return writeInformational(w.origin, code)
+}
+
+func (w *captureWriter) Status() int {
+	if w.streaming {
+		return w.origin.Status()
+	}
+
+	return w.status
+}
+
+func (w *captureWriter) Size() int {
+	if w.streaming {
+		return w.origin.Size()
+	}
+
+	if !w.written {
+		return noWritten
+	}
+
+	return w.buf.Len()
+}
+
+func (w *captureWriter) Written() bool {
+	if w.streaming {
+		return w.origin.Written()
+	}
+
+	return w.written
+}
+
+func (w *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// the connection is taken over, nothing should be written
+	w.streaming = true
+	return w.origin.Hijack()
+}
+
+func (w *captureWriter) CloseNotify() <-chan bool {
+	return w.origin.CloseNotify()
+}
+
+func (w *captureWriter) Flush() {
+	w.stream()
+	w.origin.Flush()
+}
+
+func (w *captureWriter) Pusher() http.Pusher {
+	return w.origin.Pusher()
+}
+
+func (w *captureWriter) Push(target string, opts *http.PushOptions) error {
+	return push(w.origin, target, opts)
+}
+
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.origin
+}
@@ -110,3 +110,50 @@ func TestResponseStatusCode(t *testing.T) {
 	// status must be 200 although we tried to change it
 	assert.Equal(t, http.StatusOK, w.Status())
 }
+
+func TestResponsePush(t *testing.T) {
+	testWriter := httptest.NewRecorder()
+	writer := &responseWriter{}
+	writer.reset(testWriter)
+	w := ResponseWriter(writer)
+
+	assert.Nil(t, w.Pusher())
+
+	pusher, ok := w.(http.Pusher)
+	assert.True(t, ok)
+	assert.Equal(t, http.ErrNotSupported, pusher.Push("/style.css", nil))
+	assert.Equal(t, http.ErrNotSupported, push(w, "/style.css", nil))
+}
+
+// legacyWriter only implements the ResponseWriter interface, such as the writers of other packages.
+type legacyWriter struct {
+	ResponseWriter
+}
+
+func TestResponseOptionalInterfaces(t *testing.T) {
+	writer := &responseWriter{}
+	writer.reset(httptest.NewRecorder())
+
+	cases := []struct {
+		name          string
+		writer        ResponseWriter
+		informational bool
+	}{
+		{"response writer", writer, true},
+		{"legacy writer", legacyWriter{writer}, false},
+	}
+	for _, c := range cases {
+		_, ok := c.writer.(InformationalWriter)
+		assert.Equal(t, c.informational, ok, c.name)
+		_, ok = c.writer.(rwUnwrapper)
+		assert.Equal(t, c.informational, ok, c.name)
+
+		if !c.informational {
+			assert.Equal(t, http.ErrNotSupported, writeInformational(c.writer, http.StatusEarlyHints), c.name)
+		} else {
+			assert.Error(t, writeInformational(c.writer, http.StatusOK), c.name)
+			assert.Error(t, writeInformational(c.writer, http.StatusSwitchingProtocols), c.name)
+		}
+		assert.Equal(t, http.ErrNotSupported, push(c.writer, "/style.css", nil), c.name)
+	}
+}
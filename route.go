@@ -0,0 +1,70 @@
+package zoox
+
+import (
+	"fmt"
+)
+
+// Route is the registered route, returned by g.Route to set route options, such as:
+//
+//	g.Route(http.MethodGet, "/users/:id", getUser).BindModel("id", &User{})
+//
+// The methods of the group are available, so that routes can still be chained.
+type Route struct {
+	*RouterGroup
+	methods []string
+	path    string
+}
+
+func (g *RouterGroup) newRoute(path string, methods ...string) *Route {
+	return &Route{
+		RouterGroup: g,
+		methods:     methods,
+		path:        g.routePath(path),
+	}
+}
+
+// RouteExample is the example payloads of the route, see Route.Example.
+type RouteExample struct {
+	Request  any `json:"request,omitempty"`
+	Response any `json:"response,omitempty"`
+}
+
+// Example attaches the example request and response bodies to the route,
+// which are surfaced in app.OpenAPI() and responded by the mock server (zoox mock).
+//
+// Example:
+//
+//	app.Route(http.MethodPost, "/users", createUser).Example(
+//		CreateUserRequest{Name: "Alice"},
+//		User{ID: "1", Name: "Alice"},
+//	)
+func (r *Route) Example(req, res any) *Route {
+	if r.app.examples == nil {
+		r.app.examples = map[string]*RouteExample{}
+	}
+
+	for _, method := range r.methods {
+		r.app.examples[fmt.Sprintf("%s %s", method, r.path)] = &RouteExample{
+			Request:  req,
+			Response: res,
+		}
+	}
+
+	return r
+}
+
+// prepend inserts the handlers before the route handlers, after the group middlewares.
+func (r *Route) prepend(handlers ...HandlerFunc) {
+	for _, method := range r.methods {
+		key := fmt.Sprintf("%s %s", method, r.path)
+		existed, ok := r.app.router.handlers.Get(key).([]HandlerFunc)
+		if !ok {
+			panic(fmt.Sprintf("[router] route(%s) is not registered", key))
+		}
+
+		chain := make([]HandlerFunc, 0, len(handlers)+len(existed))
+		chain = append(chain, handlers...)
+		chain = append(chain, existed...)
+		r.app.router.handlers.Set(key, chain)
+	}
+}
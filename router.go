@@ -39,8 +39,19 @@ func parsePath(path string) []string {
 	return parts
 }
 
-func (r *router) addRoute(method string, path string, handler ...HandlerFunc) {
+// routeParts returns the parts of the path in the trie, the trailing slash is the empty last part,
+// except the root and the wildcards, which match the trailing slash as the param.
+func routeParts(path string) []string {
 	parts := parsePath(path)
+	if len(parts) > 0 && parts[len(parts)-1][0] != '*' && strings.HasSuffix(path, "/") {
+		parts = append(parts, "")
+	}
+
+	return parts
+}
+
+func (r *router) addRoute(method string, path string, handler ...HandlerFunc) {
+	parts := routeParts(path)
 
 	key := fmt.Sprintf("%s %s", method, path)
 	if ok := r.roots.Has(method); !ok {
@@ -64,21 +75,25 @@ func (r *router) getRoute(method string, path string) (*route.Node, map[string]s
 	}
 
 	root := r.roots.Get(method).(*route.Node)
-	if n := root.Search(searchParts, 0); n != nil {
+	if n := root.Search(routeParts(path), 0); n != nil {
 		params := make(map[string]string)
 		parts := parsePath(n.Path)
 		for i, part := range parts {
-			if part[0] == ':' {
-				// pattern: /user/:name
-				params[part[1:]] = searchParts[i]
-			} else if part[0] == '{' && part[len(part)-1] == '}' {
-				// pattern: /user/{name}
-				params[part[1:len(part)-1]] = searchParts[i]
-			} else if part[0] == '*' && len(part) > 1 {
+			name, _, ok := route.ParseParam(part)
+			if !ok {
+				continue
+			}
+
+			if part[0] == '*' {
 				// pattern: /file/*filepath
-				params[part[1:]] = strings.Join(searchParts[i:], "/")
+				if name != "" {
+					params[name] = strings.Join(searchParts[i:], "/")
+				}
 				break
 			}
+
+			// pattern: /user/:name, /user/{name}, /user/:id<uuid>
+			params[name] = searchParts[i]
 		}
 
 		return n, params
@@ -89,22 +104,31 @@ func (r *router) getRoute(method string, path string) (*route.Node, map[string]s
 
 func (r *router) handle(ctx *Context) {
 	n, params := r.getRoute(ctx.Method, ctx.Path)
+	if ctx.App != nil {
+		handled, matched := r.normalize(ctx, n)
+		if handled {
+			return
+		}
+		n = matched
+	}
+
 	if n != nil {
 		ctx.param = param.New(params)
 
 		key := fmt.Sprintf("%s %s", ctx.Method, n.Path)
+		ctx.route = key
 		if ok := r.handlers.Has(key); ok {
 			handler, ok := r.handlers.Get(key).([]HandlerFunc)
 			if ok {
 				ctx.handlers = append(ctx.handlers, handler...)
 			} else {
-				ctx.handlers = append(ctx.handlers, ctx.App.notfound)
+				ctx.handlers = append(ctx.handlers, ctx.App.notFoundHandler(ctx.Path))
 			}
 		} else {
-			ctx.handlers = append(ctx.handlers, ctx.App.notfound)
+			ctx.handlers = append(ctx.handlers, ctx.App.notFoundHandler(ctx.Path))
 		}
 	} else {
-		ctx.handlers = append(ctx.handlers, ctx.App.notfound)
+		ctx.handlers = append(ctx.handlers, ctx.App.notFoundHandler(ctx.Path))
 	}
 
 	ctx.Next()
@@ -0,0 +1,148 @@
+package zoox
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	route "github.com/go-zoox/zoox/components/router"
+)
+
+// StrictSlash sets whether the trailing slash must match for the routes of the group, overriding config router.strict_slash,
+// for example, /users/ is 404 for the route /users, unless router.redirect_trailing_slash is enabled.
+func (g *RouterGroup) StrictSlash(strict bool) {
+	g.strictSlash = &strict
+}
+
+// isStrictSlash returns the trailing slash matching of the path, the most specific group wins.
+func (app *Application) isStrictSlash(path string) bool {
+	var matched *RouterGroup
+	for _, g := range app.groups {
+		if g.strictSlash != nil && g.hasPathPrefix(path) && (matched == nil || len(g.prefix) > len(matched.prefix)) {
+			matched = g
+		}
+	}
+
+	if matched != nil {
+		return *matched.strictSlash
+	}
+
+	return app.Config.Router.StrictSlash
+}
+
+// normalize checks the trailing slash and the fixed path of the request with the matched route (nil if not found),
+// returns true if the request is handled (redirected), or the route is not matched by the strict slash.
+func (r *router) normalize(ctx *Context, n *route.Node) (handled bool, matched *route.Node) {
+	cfg := ctx.App.Config.Router
+
+	if n == nil {
+		if !cfg.RedirectFixedPath {
+			return false, nil
+		}
+
+		// the unclean path (such as /a/../users) may not match before it is cleaned
+		cleaned := cleanPath(ctx.Path)
+		root, ok := r.roots.Get(ctx.Method).(*route.Node)
+		if !ok {
+			return false, nil
+		}
+
+		if n := root.SearchFold(routeParts(cleaned), 0); n != nil {
+			redirectFixedPath(ctx, fixedPath(n.Path, parsePath(cleaned)))
+			return true, nil
+		}
+
+		return false, nil
+	}
+
+	if cfg.RedirectFixedPath {
+		// unclean (//users, /a/../users) or non-canonical percent-encoded (/%75sers) paths
+		cleaned := cleanPath(ctx.Path)
+		raw := ctx.Request.URL.RawPath
+		encodedSlash := strings.Contains(strings.ToLower(raw), "%2f")
+		if cleaned != ctx.Path || (raw != "" && !encodedSlash && raw != (&url.URL{Path: ctx.Path}).EscapedPath()) {
+			redirectFixedPath(ctx, cleaned)
+			return true, nil
+		}
+	}
+
+	// wildcard routes match the trailing slash as the param
+	if ctx.Path == "/" || strings.Contains(n.Path, "*") || strings.HasSuffix(ctx.Path, "/") == strings.HasSuffix(n.Path, "/") {
+		return false, n
+	}
+
+	if cfg.RedirectTrailingSlash {
+		if strings.HasSuffix(ctx.Path, "/") {
+			redirectFixedPath(ctx, strings.TrimSuffix(ctx.Path, "/"))
+		} else {
+			redirectFixedPath(ctx, ctx.Path+"/")
+		}
+		return true, nil
+	}
+
+	if ctx.App.isStrictSlash(ctx.Path) {
+		return false, nil
+	}
+
+	return false, n
+}
+
+// fixedPath builds the path with the static parts of the pattern and the params of the request parts,
+// the trailing slash follows the pattern.
+func fixedPath(pattern string, parts []string) string {
+	patternParts := parsePath(pattern)
+	fixed := make([]string, 0, len(parts))
+	for i, part := range patternParts {
+		if part[0] == '*' {
+			fixed = append(fixed, parts[i:]...)
+			break
+		}
+
+		if part[0] == ':' || (part[0] == '{' && part[len(part)-1] == '}') {
+			fixed = append(fixed, parts[i])
+			continue
+		}
+
+		fixed = append(fixed, part)
+	}
+
+	p := "/" + strings.Join(fixed, "/")
+	if p != "/" && strings.HasSuffix(pattern, "/") {
+		p += "/"
+	}
+
+	return p
+}
+
+// cleanPath cleans the path, the trailing slash is kept.
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+// redirectFixedPath redirects with 301 for GET and HEAD, or 308 so the method and body are kept.
+func redirectFixedPath(ctx *Context, p string) {
+	status := http.StatusPermanentRedirect
+	if ctx.Method == http.MethodGet || ctx.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+
+	// //evil.com is a protocol-relative url for browsers, the leading slashes are collapsed
+	if strings.HasPrefix(p, "//") {
+		p = "/" + strings.TrimLeft(p, "/")
+	}
+
+	target := (&url.URL{Path: p}).EscapedPath()
+	if ctx.Request.URL.RawQuery != "" {
+		target += "?" + ctx.Request.URL.RawQuery
+	}
+
+	http.Redirect(ctx.Writer, ctx.Request, target, status)
+	// the redirects of other methods have no body to write the status
+	ctx.Writer.WriteHeaderNow()
+}
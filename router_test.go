@@ -1,8 +1,13 @@
 package zoox
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+
+	"github.com/go-zoox/zoox/config"
 )
 
 func newTestRouter() *router {
@@ -87,3 +92,167 @@ func TestGetRouteWithBrackets(t *testing.T) {
 		t.Errorf("Expected 1, got %v", ps["id"])
 	}
 }
+
+func TestRouterNormalize(t *testing.T) {
+	fixed := config.Router{RedirectFixedPath: true}
+	trailing := config.Router{RedirectTrailingSlash: true}
+	strict := config.Router{StrictSlash: true}
+
+	cases := []struct {
+		name     string
+		router   config.Router
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{"matched", config.Router{}, http.MethodGet, "/users", http.StatusOK, ""},
+		{"trailing slash is matched by default", config.Router{}, http.MethodGet, "/users/", http.StatusOK, ""},
+		{"strict slash", strict, http.MethodGet, "/users/", http.StatusNotFound, ""},
+		{"strict slash of group", config.Router{}, http.MethodGet, "/api/items/", http.StatusNotFound, ""},
+		{"group overrides strict slash", strict, http.MethodGet, "/legacy/items/", http.StatusOK, ""},
+		{"trailing slash removed", trailing, http.MethodGet, "/users/", http.StatusMovedPermanently, "/users"},
+		{"trailing slash added", trailing, http.MethodGet, "/docs", http.StatusMovedPermanently, "/docs/"},
+		{"trailing slash keeps the method", trailing, http.MethodPost, "/users/", http.StatusPermanentRedirect, "/users"},
+		{"trailing slash keeps the query", trailing, http.MethodGet, "/users/?page=2", http.StatusMovedPermanently, "/users?page=2"},
+		{"trailing slash open redirect", trailing, http.MethodGet, "//evil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{"double slash", fixed, http.MethodGet, "//users", http.StatusMovedPermanently, "/users"},
+		{"dot segments", fixed, http.MethodGet, "/a/../users", http.StatusMovedPermanently, "/users"},
+		{"case mismatch", fixed, http.MethodGet, "/Users/42", http.StatusMovedPermanently, "/users/42"},
+		{"percent-encoded", fixed, http.MethodGet, "/%75sers", http.StatusMovedPermanently, "/users"},
+		{"traversal out of the wildcard", fixed, http.MethodGet, "/files/../../etc/passwd", http.StatusMovedPermanently, "/etc/passwd"},
+		{"fixed path open redirect", fixed, http.MethodGet, "//evil.com", http.StatusMovedPermanently, "/evil.com"},
+		{"not found", fixed, http.MethodGet, "/unknown/path", http.StatusNotFound, ""},
+	}
+
+	for _, c := range cases {
+		app := New()
+		app.Config.Router = c.router
+		ok := func(ctx *Context) {
+			ctx.String(http.StatusOK, "ok")
+		}
+		app.Get("/users", ok)
+		app.Post("/users", ok)
+		app.Get("/users/:id", ok)
+		app.Get("/docs/", ok)
+		app.Get("/files/*path", ok)
+		app.Get("/:name", ok)
+		api := app.Group("/api")
+		api.StrictSlash(true)
+		api.Get("/items", ok)
+		legacy := app.Group("/legacy")
+		legacy.StrictSlash(false)
+		legacy.Get("/items", ok)
+
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(c.method, c.path, nil))
+
+		if recorder.Code != c.status {
+			t.Errorf("%s: expected %d, got %d", c.name, c.status, recorder.Code)
+		}
+		if location := recorder.Header().Get("Location"); location != c.location {
+			t.Errorf("%s: expected location %q, got %q", c.name, c.location, location)
+		}
+	}
+}
+
+func TestGetRouteConstraints(t *testing.T) {
+	r := newRouter()
+	r.addRoute("GET", "/users/:id<uuid>", nil)
+	r.addRoute("GET", "/users/:name", nil)
+
+	cases := []struct {
+		path     string
+		expected string
+		params   map[string]string
+	}{
+		{"/users/3f2b8c1e-9a4d-4e2b-8c1e-9a4d4e2b8c1e", "/users/:id<uuid>", map[string]string{"id": "3f2b8c1e-9a4d-4e2b-8c1e-9a4d4e2b8c1e"}},
+		{"/users/zero", "/users/:name", map[string]string{"name": "zero"}},
+	}
+
+	for _, c := range cases {
+		n, ps := r.getRoute("GET", c.path)
+		if n == nil {
+			t.Errorf("%s: expected %s, got nil", c.path, c.expected)
+			continue
+		}
+
+		if n.Path != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.path, c.expected, n.Path)
+		}
+		if !reflect.DeepEqual(ps, c.params) {
+			t.Errorf("%s: expected %v, got %v", c.path, c.params, ps)
+		}
+	}
+}
+
+func TestRouterTrailingSlashRoutes(t *testing.T) {
+	handler := func(body string) HandlerFunc {
+		return func(ctx *Context) {
+			ctx.String(http.StatusOK, body)
+		}
+	}
+
+	app := New()
+	app.Get("/docs", handler("docs"))
+	app.Get("/docs/", handler("docs index"))
+	app.Get("/users/", handler("users index"))
+	api := app.Group("/api")
+	api.Get("/items", handler("items"))
+	api.Get("/items/", handler("items index"))
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/docs", "docs"},
+		{"/docs/", "docs index"},
+		// the only one of them matches both
+		{"/users", "users index"},
+		{"/users/", "users index"},
+		{"/api/items", "items"},
+		{"/api/items/", "items index"},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if body, _ := io.ReadAll(recorder.Body); string(body) != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.path, c.expected, body)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the duplicated route with the trailing slash to panic")
+		}
+	}()
+	app.Get("/docs/", handler("again"))
+}
+
+func TestRouteOptionsWithTrailingSlash(t *testing.T) {
+	type user struct {
+		ID string
+	}
+
+	app := New()
+	app.Group("/api").Route(http.MethodGet, "/users/:id/", func(ctx *Context) {
+		ctx.String(http.StatusOK, ctx.Model("id").(*user).ID)
+	}).BindModel("id", &user{}, func(ctx *Context, model any, param, value string) error {
+		model.(*user).ID = "loaded " + value
+		return nil
+	})
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/users/42/", nil))
+	if body := recorder.Body.String(); body != "loaded 42" {
+		t.Errorf("expected the model to be bound, got %q", body)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the options of the unregistered route to panic")
+		}
+	}()
+	app.newRoute("/unregistered", http.MethodGet).BindModel("id", &user{})
+}
@@ -0,0 +1,86 @@
+package zoox
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// EnvRoutesOutput is the env of the file to write the route table,
+// if set, Run writes the routes as json and returns without serving, used by zoox routes.
+const EnvRoutesOutput = "ZOOX_ROUTES_OUTPUT"
+
+// RouteInfo is the information of a registered route.
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Handler is the function name of the route handler (the last one).
+	Handler string `json:"handler"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	// Middlewares are the names of the group middlewares applied to the route, in order.
+	Middlewares []string `json:"middlewares"`
+	// Example is the example payloads of the route, see Route.Example.
+	Example *RouteExample `json:"example,omitempty"`
+}
+
+// Routes returns the registered routes, sorted by path and method.
+func (app *Application) Routes() []RouteInfo {
+	routes := []RouteInfo{}
+	app.router.handlers.ForEach(func(key string, value any) (stop bool) {
+		method, path, _ := strings.Cut(key, " ")
+		route := RouteInfo{
+			Method:      method,
+			Path:        path,
+			Middlewares: []string{},
+		}
+
+		if handlers, ok := value.([]HandlerFunc); ok && len(handlers) > 0 {
+			route.Handler, route.File, route.Line = funcLocation(handlers[len(handlers)-1])
+		}
+
+		route.Example = app.examples[key]
+
+		for _, group := range app.groups {
+			if group.matchPath(path) {
+				route.Middlewares = append(route.Middlewares, group.Middlewares()...)
+			}
+		}
+
+		routes = append(routes, route)
+		return false
+	})
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// writeRoutes writes the routes as json to the file.
+func (app *Application) writeRoutes(file string) error {
+	data, err := json.MarshalIndent(app.Routes(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0644)
+}
+
+func funcLocation(fn any) (name, file string, line int) {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "", "", 0
+	}
+
+	file, line = f.FileLine(f.Entry())
+	return f.Name(), file, line
+}
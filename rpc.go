@@ -0,0 +1,161 @@
+package zoox
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RPC mounts the Connect handler on the service path, Connect, gRPC-Web and gRPC (HTTP/2) protocols are served by the handler,
+// the group middlewares are applied.
+//
+// Example:
+//
+//	// connectrpc.com/connect
+//	path, handler := greetv1connect.NewGreetServiceHandler(&GreetServer{})
+//	app.RPC(path, handler)
+//
+//	// google.golang.org/grpc, translated from gRPC-Web
+//	app.RPC("/greet.v1.GreetService/", zoox.GRPCWeb(grpcServer))
+func (g *RouterGroup) RPC(path string, handler http.Handler, middlewares ...HandlerFunc) *RouterGroup {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	handlers := append(append([]HandlerFunc{}, middlewares...), WrapH(handler))
+
+	// connect unary GET is supported for idempotent methods
+	g.Get(path+"*procedure", handlers...)
+	g.Post(path+"*procedure", handlers...)
+
+	return g
+}
+
+const (
+	contentTypeGRPC        = "application/grpc"
+	contentTypeGRPCWeb     = "application/grpc-web"
+	contentTypeGRPCWebText = "application/grpc-web-text"
+)
+
+// GRPCWeb translates gRPC-Web requests to gRPC for the handler which only speaks gRPC, such as *grpc.Server,
+// so browser clients can call the RPCs without a separate proxy like Envoy.
+// Content types are translated, and the gRPC trailers are written as the gRPC-Web trailer frame in body.
+// Other requests are passed through.
+func GRPCWeb(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if !strings.HasPrefix(contentType, contentTypeGRPCWeb) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		text := strings.HasPrefix(contentType, contentTypeGRPCWebText)
+		suffix := strings.TrimPrefix(contentType, contentTypeGRPCWeb)
+		if text {
+			suffix = strings.TrimPrefix(contentType, contentTypeGRPCWebText)
+		}
+
+		req := r.Clone(r.Context())
+		req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2"
+		req.Header.Set("Content-Type", contentTypeGRPC+suffix)
+		req.Header.Set("Te", "trailers")
+		req.Header.Del("Content-Length")
+		req.ContentLength = -1
+		if text {
+			req.Body = struct {
+				io.Reader
+				io.Closer
+			}{base64.NewDecoder(base64.StdEncoding, r.Body), r.Body}
+		}
+
+		writer := &grpcWebResponseWriter{
+			ResponseWriter: w,
+			text:           text,
+			contentType:    contentType,
+		}
+		handler.ServeHTTP(writer, req)
+		writer.finish()
+	})
+}
+
+type grpcWebResponseWriter struct {
+	http.ResponseWriter
+	text        bool
+	contentType string
+	//
+	wroteHeader bool
+	// headers sent before body, others are trailers
+	sent map[string]bool
+}
+
+func (w *grpcWebResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.ResponseWriter.Header()
+	header.Del("Trailer")
+	header.Set("Content-Type", w.contentType)
+
+	w.sent = map[string]bool{}
+	for k := range header {
+		w.sent[k] = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *grpcWebResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.text {
+		if _, err := w.ResponseWriter.Write([]byte(base64.StdEncoding.EncodeToString(b))); err != nil {
+			return 0, err
+		}
+
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *grpcWebResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the trailers as the gRPC-Web trailer frame.
+func (w *grpcWebResponseWriter) finish() {
+	// trailers-only response, grpc-status is in headers
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var trailers strings.Builder
+	for k, values := range w.ResponseWriter.Header() {
+		name := strings.TrimPrefix(k, http.TrailerPrefix)
+		if w.sent[k] && name == k {
+			continue
+		}
+
+		for _, v := range values {
+			trailers.WriteString(strings.ToLower(name) + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5+trailers.Len())
+	// the MSB of flag marks the trailer frame
+	frame[0] = 1 << 7
+	binary.BigEndian.PutUint32(frame[1:5], uint32(trailers.Len()))
+	copy(frame[5:], trailers.String())
+
+	w.Write(frame)
+	w.Flush()
+}
@@ -0,0 +1,43 @@
+package zoox
+
+import (
+	"os"
+
+	"github.com/go-zoox/zoox/components/application/secrets"
+)
+
+// Secrets returns the secrets resolving the references of config values at startup,
+// the providers env and file are builtin, vault is registered if VAULT_ADDR is set,
+// others (such as AWS Secrets Manager) can be registered before app.Run:
+//
+//	app.Secrets().Register("aws", secrets.ProviderFunc(func(ctx context.Context, path, key string) (string, error) {
+//		return getSecretValue(ctx, path, key)
+//	}))
+//
+//	// config: secret_key: secret://aws/prod/app#secret_key
+//
+//	app.Secrets().OnRotate("secret://vault/app/tls#key", func(value string) {
+//		reloadTLSKey(value)
+//	})
+func (app *Application) Secrets() secrets.Secrets {
+	app.once.secrets.Do(func() {
+		if app.secrets != nil {
+			return
+		}
+
+		app.secrets = secrets.New(&secrets.Config{
+			TTL: app.Config.Secrets.CacheTTL,
+		})
+
+		if os.Getenv("VAULT_ADDR") != "" {
+			app.secrets.Register("vault", secrets.NewVault())
+		}
+	})
+
+	return app.secrets
+}
+
+// SetSecrets sets the secrets, such as with custom providers.
+func (app *Application) SetSecrets(s secrets.Secrets) {
+	app.secrets = s
+}
@@ -0,0 +1,133 @@
+package zoox
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zoox/logger"
+)
+
+// EnvListenFD is the env of the http listener fd inherited from the parent process,
+// such as zoox dev keeps the socket open across restarts.
+const EnvListenFD = "ZOOX_LISTEN_FD"
+
+// EnvListenFDHTTPS is the env of the https listener fd inherited from the parent process.
+const EnvListenFDHTTPS = "ZOOX_LISTEN_FD_HTTPS"
+
+// inheritListener returns the listener of the fd in env, nil if the env is not set.
+func inheritListener(env string) (net.Listener, error) {
+	value := os.Getenv(env)
+	if value == "" {
+		return nil, nil
+	}
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", env, value)
+	}
+
+	file := os.NewFile(uintptr(fd), env)
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inherit listener from %s: %v", env, err)
+	}
+
+	return listener, nil
+}
+
+// sdNotify sends the state to systemd with the NOTIFY_SOCKET, such as READY=1,
+// it does nothing if not running under systemd (Type=notify).
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// sdWatchdogInterval returns the interval of systemd watchdog pings (half of WATCHDOG_USEC), 0 if disabled.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	// WATCHDOG_PID is set if the watchdog is for another process
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// notifyReady notifies the process manager that the servers are listening,
+// and keeps pinging the systemd watchdog until ctx is done.
+func (app *Application) notifyReady(ctx context.Context) {
+	if err := sdNotify("READY=1\nSTATUS=Server started at " + app.AddressForLog()); err != nil {
+		logger.Warn("[systemd] failed to notify ready: %s", err)
+	}
+
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := sdNotify("WATCHDOG=1"); err != nil {
+					logger.Warn("[systemd] failed to ping watchdog: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// notifyStopping notifies the process manager that the servers are stopping.
+func (app *Application) notifyStopping() {
+	if err := sdNotify("STOPPING=1"); err != nil {
+		logger.Warn("[systemd] failed to notify stopping: %s", err)
+	}
+}
+
+// readiness calls ready once all the n servers are listening, unless ctx is done before.
+func readiness(ctx context.Context, n int32, ready func()) (listening func()) {
+	pending := atomic.Int32{}
+	pending.Store(n)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-done:
+			ready()
+		case <-ctx.Done():
+		}
+	}()
+
+	return func() {
+		if pending.Add(-1) == 0 {
+			close(done)
+		}
+	}
+}
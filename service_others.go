@@ -0,0 +1,11 @@
+//go:build !windows
+
+package zoox
+
+import "context"
+
+// runService runs serve under the windows service control manager,
+// ok is always false on other platforms.
+func (app *Application) runService(serve func(ctx context.Context, ready func()) error) (ok bool, err error) {
+	return false, nil
+}
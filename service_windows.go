@@ -0,0 +1,70 @@
+//go:build windows
+
+package zoox
+
+import (
+	"context"
+
+	"github.com/go-zoox/logger"
+	"golang.org/x/sys/windows/svc"
+)
+
+// runService runs serve under the windows service control manager,
+// ok is false if the process is not started as a windows service.
+func (app *Application) runService(serve func(ctx context.Context, ready func()) error) (ok bool, err error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return false, err
+	}
+
+	handler := &windowsService{serve: serve}
+	if err := svc.Run(app.Config.Name, handler); err != nil {
+		return true, err
+	}
+
+	return true, handler.err
+}
+
+// windowsService is the handler of windows service control requests.
+type windowsService struct {
+	serve func(ctx context.Context, ready func()) error
+	err   error
+}
+
+func (s *windowsService) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepts = svc.AcceptStop | svc.AcceptShutdown
+
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.serve(ctx, func() {
+			status <- svc.Status{State: svc.Running, Accepts: accepts}
+		})
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			s.err = err
+			status <- svc.Status{State: svc.Stopped}
+			if err != nil {
+				return false, 1
+			}
+
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				logger.Info("[service] received windows service stop request")
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package zoox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/cron"
+	"github.com/go-zoox/zoox/components/application/jobqueue"
+)
+
+// DefaultShutdownTimeout is the default timeout of stopping the components, such as draining the job queue.
+var DefaultShutdownTimeout = 30 * time.Second
+
+// ErrShutdownTimeout is returned by app.Shutdown when the components are not stopped in time.
+var ErrShutdownTimeout = errors.New("shutdown timeout")
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type shutdownState struct {
+	sync.Mutex
+	// cancel stops the servers of Run
+	cancel context.CancelFunc
+	// done is closed when Run returns
+	done chan struct{}
+	// requested is true if app.Shutdown is called
+	requested bool
+	//
+	hooks []shutdownHook
+	// ctx is the context of the components, such as app.Cmd, cancelled on shutdown
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	stopOnce  sync.Once
+}
+
+// OnShutdown adds the shutdown hook of the component, the hooks run in reverse order after the servers are stopped,
+// sharing the shutdown timeout (config shutdown_timeout).
+//
+// Example:
+//
+//	app.OnShutdown("worker", func(ctx context.Context) error {
+//		return worker.Stop(ctx)
+//	})
+func (app *Application) OnShutdown(name string, fn func(ctx context.Context) error) {
+	app.shutdown.Lock()
+	defer app.shutdown.Unlock()
+
+	app.shutdown.hooks = append(app.shutdown.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Shutdown stops the servers of Run and the components: the cron jobs are removed, the job queue is drained,
+// the child processes of app.Cmd are killed and the OnShutdown hooks run.
+// It waits for Run to return until ctx is done, or stops the components directly if the app is not running.
+func (app *Application) Shutdown(ctx context.Context) error {
+	app.shutdown.Lock()
+	cancel, done := app.shutdown.cancel, app.shutdown.done
+	app.shutdown.requested = true
+	app.shutdown.Unlock()
+
+	if cancel == nil {
+		return app.stopComponents(ctx)
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lifecycleContext returns the context of the components, which is cancelled on shutdown.
+func (app *Application) lifecycleContext() context.Context {
+	app.shutdown.Lock()
+	defer app.shutdown.Unlock()
+
+	if app.shutdown.ctx == nil {
+		app.shutdown.ctx, app.shutdown.ctxCancel = context.WithCancel(context.Background())
+	}
+
+	return app.shutdown.ctx
+}
+
+// withShutdown returns the context of the servers cancelled by app.Shutdown.
+func (app *Application) withShutdown(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+
+	app.shutdown.Lock()
+	app.shutdown.cancel = cancel
+	app.shutdown.done = make(chan struct{})
+	app.shutdown.Unlock()
+
+	return ctx
+}
+
+// isShutdownRequested returns true if app.Shutdown is called.
+func (app *Application) isShutdownRequested() bool {
+	app.shutdown.Lock()
+	defer app.shutdown.Unlock()
+
+	return app.shutdown.requested
+}
+
+// stopped marks Run returned.
+func (app *Application) stopped() {
+	app.shutdown.Lock()
+	defer app.shutdown.Unlock()
+
+	if app.shutdown.done != nil {
+		close(app.shutdown.done)
+		app.shutdown.cancel = nil
+	}
+}
+
+// stopComponents stops the components once, within the shutdown timeout.
+func (app *Application) stopComponents(ctx context.Context) (err error) {
+	app.shutdown.stopOnce.Do(func() {
+		timeout := app.Config.ShutdownTimeout
+		if timeout == 0 {
+			timeout = DefaultShutdownTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		// no more scheduled jobs
+		if stopper, ok := app.cron.(cron.Stopper); ok {
+			if e := stopper.Stop(); e != nil {
+				app.Logger().Errorf("[shutdown] failed to stop cron: %s", e)
+			}
+		}
+
+		// drain the job queue
+		if shutdowner, ok := app.queue.(jobqueue.Shutdowner); ok {
+			if e := shutdowner.Shutdown(ctx); e != nil {
+				app.Logger().Errorf("[shutdown] failed to drain job queue: %s", e)
+				err = ErrShutdownTimeout
+			}
+		}
+
+		// kill the child processes
+		app.shutdown.Lock()
+		if app.shutdown.ctxCancel != nil {
+			app.shutdown.ctxCancel()
+		}
+		hooks := app.shutdown.hooks
+		app.shutdown.Unlock()
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if e := hooks[i].fn(ctx); e != nil {
+				app.Logger().Errorf("[shutdown] failed to stop %s: %s", hooks[i].name, e)
+				if errors.Is(e, context.DeadlineExceeded) {
+					err = ErrShutdownTimeout
+				}
+			}
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,107 @@
+package zoox
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-zoox/zoox/components/application/storage"
+)
+
+// Storage returns the file storage by config storage.driver (local or s3), default the local directory uploads.
+//
+//	# config: S3 compatible, such as MinIO
+//	storage:
+//	  driver: s3
+//	  s3:
+//	    endpoint: http://127.0.0.1:9000
+//	    bucket: uploads
+//	    access_key_id: secret://vault/minio#access_key
+//	    secret_access_key: secret://vault/minio#secret_key
+//	    path_style: true
+func (app *Application) Storage() storage.Storage {
+	app.once.storage.Do(func() {
+		if app.storage != nil {
+			return
+		}
+
+		cfg := app.Config.Storage
+		switch cfg.Driver {
+		case "s3":
+			s, err := storage.NewS3(&storage.S3Config{
+				Endpoint:        cfg.S3.Endpoint,
+				Region:          cfg.S3.Region,
+				Bucket:          cfg.S3.Bucket,
+				AccessKeyID:     cfg.S3.AccessKeyID,
+				SecretAccessKey: cfg.S3.SecretAccessKey,
+				PathStyle:       cfg.S3.PathStyle,
+				Prefix:          cfg.S3.Prefix,
+			})
+			if err != nil {
+				panic(fmt.Errorf("failed to create s3 storage: %v", err))
+			}
+
+			app.storage = s
+		case "", "local":
+			root := cfg.Local.Root
+			if root == "" {
+				root = "uploads"
+			}
+
+			app.storage = storage.NewLocal(&storage.LocalConfig{
+				Root:    root,
+				BaseURL: cfg.Local.BaseURL,
+				Keyring: &appKeyring{app: app},
+			})
+		default:
+			panic(fmt.Errorf("unsupported storage driver: %s", cfg.Driver))
+		}
+	})
+
+	return app.storage
+}
+
+// SetStorage sets the file storage.
+func (app *Application) SetStorage(s storage.Storage) {
+	app.storage = s
+}
+
+// SaveUploadedFile saves the form file of the key into the storage as the object key (such as avatars/1.png),
+// the file is scanned before saved if the upload scanner is configured, see ctx.SaveScannedFile.
+func (ctx *Context) SaveUploadedFile(key, objectKey string) error {
+	if ctx.App.UploadScanner() != nil {
+		_, err := ctx.saveScanned(key, "", ctx.storagePersister(objectKey))
+		return err
+	}
+
+	src, header, err := ctx.Request.FormFile(key)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return ctx.App.Storage().Put(ctx.Context(), objectKey, src, &storage.PutOption{
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	})
+}
+
+// SaveScannedUploadedFile is ctx.SaveUploadedFile returning the scan record, see ctx.SaveScannedFile.
+func (ctx *Context) SaveScannedUploadedFile(key, objectKey string) (*UploadScan, error) {
+	return ctx.saveScanned(key, "", ctx.storagePersister(objectKey))
+}
+
+func (ctx *Context) storagePersister(objectKey string) uploadPersister {
+	app := ctx.App
+	return func(c *uploadContext) (string, error) {
+		f, err := os.Open(c.staged)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		return objectKey, app.Storage().Put(c.ctx, objectKey, f, &storage.PutOption{
+			ContentType: c.contentType,
+			Size:        c.size,
+		})
+	}
+}
@@ -32,6 +32,8 @@ func (ctx *Context) Template(status int, opts ...TemplateOption) {
 		opt(cfg)
 	}
 
+	defer ctx.renderTiming()()
+
 	// if content is not empty, use content as template
 	if cfg.Content != "" {
 		tmpl, err := template.New("example").Parse(cfg.Content)
@@ -0,0 +1,79 @@
+package zoox
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-zoox/zoox/components/application/terminal"
+	"github.com/gorilla/websocket"
+)
+
+// TerminalOption is the option of terminal route.
+type TerminalOption struct {
+	// Middlewares run before the upgrade, such as the authentication, the terminal runs commands on the server.
+	// At least one is required, unless AllowUnauthenticated is set.
+	Middlewares []HandlerFunc
+	// AllowUnauthenticated registers the terminal without Middlewares,
+	//	such as the group middlewares authenticate already, which the caller is responsible for.
+	AllowUnauthenticated bool
+	// Config is the config of the session, such as IdleTimeout and Recorder.
+	Config *terminal.Config
+}
+
+// Terminal defines the websocket route attaching the terminal session opened by open,
+// such as a command of app.Cmd() (terminal.NewCommand), a local shell (terminal.NewPTY) or an ssh session (terminal.NewSSH).
+// The protocol is described in the terminal package, the client sends resize messages on window change.
+//
+// Example:
+//
+//	app.Terminal("/terminal", func(ctx *zoox.Context) (terminal.Session, error) {
+//		cmd, err := ctx.App.Cmd().Create(&command.Config{Command: "bash"})
+//		if err != nil {
+//			return nil, err
+//		}
+//
+//		q := query.Typed(ctx.Query())
+//		rows, cols := uint16(q.Int("rows", 24)), uint16(q.Int("cols", 80))
+//		return terminal.NewCommand(cmd, rows, cols)
+//	}, func(opt *zoox.TerminalOption) {
+//		opt.Middlewares = []zoox.HandlerFunc{middleware.BasicAuth("terminal", map[string]string{"admin": password})}
+//		opt.Config = &terminal.Config{IdleTimeout: 10 * time.Minute}
+//	})
+//
+// It panics without Middlewares or AllowUnauthenticated. It returns the group as g.Get does,
+// the route options (g.Route) do not apply to the websocket upgrade.
+func (g *RouterGroup) Terminal(path string, open func(ctx *Context) (terminal.Session, error), opts ...func(opt *TerminalOption)) *RouterGroup {
+	opt := &TerminalOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	if len(opt.Middlewares) == 0 && !opt.AllowUnauthenticated {
+		panic(fmt.Sprintf("[router] terminal(%s) requires the authentication middlewares, or AllowUnauthenticated", g.routePath(path)))
+	}
+
+	handlers := append(append([]HandlerFunc{}, opt.Middlewares...), func(ctx *Context) {
+		// the origin is checked to be the same host by default
+		upgrader := &websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+		if err != nil {
+			ctx.RequestLogger().Errorf("[terminal] failed to upgrade: %s", err)
+			return
+		}
+		defer conn.Close()
+
+		session, err := open(ctx)
+		if err != nil {
+			ctx.RequestLogger().Errorf("[terminal] failed to open session: %s", err)
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to open session"))
+			return
+		}
+
+		if err := terminal.Serve(conn, session, opt.Config); err != nil {
+			ctx.RequestLogger().Debugf("[terminal] session closed: %s", err)
+		}
+	})
+	g.addRoute(http.MethodGet, path, handlers...)
+
+	return g
+}
@@ -0,0 +1,62 @@
+package zoox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox/components/application/terminal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminalMiddlewares(t *testing.T) {
+	opened := false
+	app := New()
+	app.Terminal("/terminal", func(ctx *Context) (terminal.Session, error) {
+		opened = true
+		return nil, http.ErrNotSupported
+	}, func(opt *TerminalOption) {
+		opt.Middlewares = []HandlerFunc{func(ctx *Context) {
+			if token, ok := ctx.BearerToken(); !ok || token != "secret" {
+				ctx.Status(http.StatusUnauthorized)
+				return
+			}
+
+			ctx.Next()
+		}}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/terminal", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.False(t, opened)
+}
+
+func TestTerminalRequiresAuthentication(t *testing.T) {
+	open := func(ctx *Context) (terminal.Session, error) {
+		return nil, http.ErrNotSupported
+	}
+
+	app := New()
+	assert.Panics(t, func() {
+		app.Terminal("/terminal", open)
+	})
+
+	// authenticated by the group middlewares
+	api := app.Group("/api", func(g *RouterGroup) {
+		g.Use(func(ctx *Context) {
+			ctx.Status(http.StatusUnauthorized)
+		})
+	})
+	assert.NotPanics(t, func() {
+		api.Terminal("/terminal", open, func(opt *TerminalOption) {
+			opt.AllowUnauthenticated = true
+		})
+	})
+}
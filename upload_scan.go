@@ -0,0 +1,219 @@
+package zoox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-zoox/random"
+	"github.com/go-zoox/zoox/components/application/scan"
+)
+
+// EventUploadScanned is the webhook event of the scanned uploads, the payload is *UploadScan.
+const EventUploadScanned = "upload.scanned"
+
+// ErrNoUploadScanner is returned by ctx.ScanFile if no upload scanner is configured.
+var ErrNoUploadScanner = errors.New("upload scanner is not configured")
+
+// UploadScan is the scan record of the uploaded file.
+type UploadScan struct {
+	ID       string `json:"id"`
+	Field    string `json:"field"`
+	Filename string `json:"filename"`
+	// Path is the saved path (or storage key) of the clean file.
+	Path string `json:"path"`
+	// Status is pending, clean, infected or failed.
+	Status string `json:"status"`
+	Threat string `json:"threat,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Quarantine is the path of the infected file kept in the quarantine dir.
+	Quarantine string `json:"quarantine,omitempty"`
+}
+
+// UploadScanner returns the upload scanner, ClamAV if config upload_scan.clamav is set, nil means scanning is disabled.
+//
+// Custom scanners can be set with app.SetUploadScanner:
+//
+//	app.SetUploadScanner(scan.ScannerFunc(func(ctx context.Context, r io.Reader) (*scan.Result, error) {
+//		return callScanningService(ctx, r)
+//	}))
+func (app *Application) UploadScanner() scan.Scanner {
+	app.once.scanner.Do(func() {
+		if app.scanner != nil || app.Config.UploadScan.ClamAV == "" {
+			return
+		}
+
+		app.scanner = scan.NewClamAV(&scan.ClamAVConfig{
+			Address: app.Config.UploadScan.ClamAV,
+			Timeout: app.Config.UploadScan.Timeout,
+		})
+	})
+
+	return app.scanner
+}
+
+// SetUploadScanner sets the upload scanner.
+func (app *Application) SetUploadScanner(s scan.Scanner) {
+	app.scanner = s
+}
+
+// ScanFile scans the form file of the key with the upload scanner.
+func (ctx *Context) ScanFile(key string) (*scan.Result, error) {
+	scanner := ctx.App.UploadScanner()
+	if scanner == nil {
+		return nil, ErrNoUploadScanner
+	}
+
+	f, _, err := ctx.File(key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return scanner.Scan(ctx.Context(), f)
+}
+
+// SaveScannedFile saves the form file of the key to the path after it is scanned clean,
+// infected files are moved to the quarantine dir (or deleted), and *scan.InfectedError is returned.
+//
+// In async mode (config upload_scan.async), the file is scanned and saved in background,
+// the pending record is returned, and the result is sent by the upload.scanned webhook.
+func (ctx *Context) SaveScannedFile(key, path string) (*UploadScan, error) {
+	// the file is staged beside the path, so it is renamed atomically when clean
+	return ctx.saveScanned(key, filepath.Dir(path), func(c *uploadContext) (string, error) {
+		return path, os.Rename(c.staged, path)
+	})
+}
+
+// uploadContext is the staged upload to persist.
+type uploadContext struct {
+	ctx         context.Context
+	staged      string
+	contentType string
+	size        int64
+}
+
+// uploadPersister persists the clean upload, returns the saved location.
+type uploadPersister func(c *uploadContext) (string, error)
+
+// saveScanned stages the form file in the dir (temp dir if empty), then persists it after scanned clean.
+func (ctx *Context) saveScanned(key, stageDir string, persist uploadPersister) (*UploadScan, error) {
+	scanner := ctx.App.UploadScanner()
+	if scanner == nil {
+		return nil, ErrNoUploadScanner
+	}
+
+	src, header, err := ctx.Request.FormFile(key)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	record := &UploadScan{
+		ID:       random.String(16),
+		Field:    key,
+		Filename: header.Filename,
+		Status:   scan.StatusPending,
+	}
+
+	dst, err := os.CreateTemp(stageDir, ".scanning-"+record.ID+"-*")
+	if err != nil {
+		return nil, err
+	}
+	size, err := io.Copy(dst, src)
+	dst.Close()
+	if err != nil {
+		os.Remove(dst.Name())
+		return nil, err
+	}
+
+	upload := &uploadContext{
+		staged:      dst.Name(),
+		contentType: header.Header.Get("Content-Type"),
+		size:        size,
+	}
+
+	app := ctx.App
+	if app.Config.UploadScan.Async {
+		pending := *record
+		upload.ctx = context.Background()
+		go app.scanUpload(scanner, record, upload, persist)
+		return &pending, nil
+	}
+
+	upload.ctx = ctx.Context()
+	app.scanUpload(scanner, record, upload, persist)
+	switch record.Status {
+	case scan.StatusInfected:
+		return record, &scan.InfectedError{Threat: record.Threat}
+	case scan.StatusFailed:
+		return record, fmt.Errorf("failed to save scanned file: %s", record.Error)
+	}
+
+	return record, nil
+}
+
+// scanUpload scans the staged file, then persists it if clean, otherwise quarantines it.
+func (app *Application) scanUpload(scanner scan.Scanner, record *UploadScan, upload *uploadContext, persist uploadPersister) {
+	defer func() {
+		// removes the staged file if it is not moved
+		os.Remove(upload.staged)
+
+		if err := app.Webhooks().Emit(EventUploadScanned, record); err != nil {
+			app.Logger().Errorf("[upload_scan] failed to emit %s(%s): %s", EventUploadScanned, record.ID, err)
+		}
+	}()
+
+	timeout := app.Config.UploadScan.Timeout
+	if timeout == 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(upload.ctx, timeout)
+	defer cancel()
+
+	result, err := func() (*scan.Result, error) {
+		f, err := os.Open(upload.staged)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return scanner.Scan(ctx, f)
+	}()
+	if err != nil {
+		// fail closed, the file is not saved if it is not scanned
+		record.Status = scan.StatusFailed
+		record.Error = err.Error()
+		app.Logger().Errorf("[upload_scan] failed to scan %s(%s): %s", record.Filename, record.ID, err)
+		return
+	}
+
+	record.Status = result.Status()
+	if result.Clean {
+		location, err := persist(upload)
+		if err != nil {
+			record.Status = scan.StatusFailed
+			record.Error = err.Error()
+			return
+		}
+
+		record.Path = location
+		return
+	}
+
+	record.Threat = result.Threat
+	app.Logger().Warnf("[upload_scan] %s(%s) is infected: %s", record.Filename, record.ID, result.Threat)
+
+	if dir := app.Config.UploadScan.QuarantineDir; dir != "" {
+		quarantine := filepath.Join(dir, fmt.Sprintf("%s-%s", record.ID, filepath.Base(record.Filename)))
+		if err := os.MkdirAll(dir, 0700); err == nil {
+			if err := os.Rename(upload.staged, quarantine); err == nil {
+				record.Quarantine = quarantine
+			}
+		}
+	}
+}
@@ -97,7 +97,8 @@ func (g *RouterGroup) WebSocket(path string, opts ...func(opt *WebSocketOption))
 		//	=> ignore old handlers
 		//	=> only use websocket handlers
 		ctx.index = -1
-		ctx.handlers = append(opt.Middlewares, func(ctx *Context) {
+		//	=> copied, the handlers slice of the pooled context is reused by the next request
+		ctx.handlers = append(append([]HandlerFunc(nil), opt.Middlewares...), func(ctx *Context) {
 			opt.Server.ServeHTTP(ctx.Writer, ctx.Request)
 		})
 
@@ -0,0 +1,206 @@
+package zoox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-zoox/zoox/components/application/pubsub"
+)
+
+// DefaultWorkerRestartDelay is the delay of restarting the worker returned with error.
+var DefaultWorkerRestartDelay = time.Second
+
+type worker struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type schedule struct {
+	id   string
+	spec string
+	job  func() error
+}
+
+type subscriber struct {
+	topic   string
+	handler pubsub.Handler
+}
+
+type workers struct {
+	workers     []worker
+	schedules   []schedule
+	subscribers []subscriber
+	// running is true in app.RunWorker
+	running bool
+}
+
+// Worker registers the long-running worker started by app.RunWorker, such as the MQ consumer,
+// it is restarted if it returns with error, until the app is shut down (ctx is done).
+//
+// Example:
+//
+//	app.Worker("emails", func(ctx context.Context) error {
+//		return consumer.Run(ctx, handleEmail)
+//	})
+func (app *Application) Worker(name string, fn func(ctx context.Context) error) {
+	app.workers.workers = append(app.workers.workers, worker{name: name, fn: fn})
+}
+
+// Schedule registers the cron job started by app.RunWorker, so that it is not run by the web replicas.
+//
+// Example:
+//
+//	app.Schedule("cleanup", "0 * * * *", cleanup)
+func (app *Application) Schedule(id string, spec string, job func() error) {
+	app.workers.schedules = append(app.workers.schedules, schedule{id: id, spec: spec, job: job})
+}
+
+// Subscribe registers the pubsub subscriber started by app.RunWorker.
+func (app *Application) Subscribe(topic string, handler pubsub.Handler) {
+	app.workers.subscribers = append(app.workers.subscribers, subscriber{topic: topic, handler: handler})
+}
+
+// IsWorker returns true if the app is running by app.RunWorker.
+func (app *Application) IsWorker() bool {
+	return app.workers.running
+}
+
+// RunWorker runs the app without the http server, with the same config and components:
+// the registered workers (app.Worker), cron jobs (app.Schedule) and pubsub subscribers (app.Subscribe) are started,
+// the job queue is consumed, until SIGINT / SIGTERM or app.Shutdown.
+// So the same codebase can be deployed as web (app.Run) and worker (app.RunWorker) replicas.
+//
+// Example:
+//
+//	if os.Getenv("ROLE") == "worker" {
+//		return app.RunWorker()
+//	}
+//
+//	return app.Run()
+func (app *Application) RunWorker() error {
+	app.workers.running = true
+
+	// apply default config
+	if err := app.applyDefaultConfig(); err != nil {
+		return fmt.Errorf("failed to apply default config: %v", err)
+	}
+
+	// show runtime info
+	if !app.Config.DisableBanner {
+		app.showRuntimeInfo()
+	}
+
+	// before ready
+	if app.lifecycle.beforeReady != nil {
+		app.lifecycle.beforeReady()
+	}
+
+	// refresh the secrets for rotation
+	if app.Config.Secrets.RefreshInterval > 0 {
+		go app.Secrets().Watch(context.Background(), app.Config.Secrets.RefreshInterval)
+	}
+
+	// apply pending migrations if database.auto_migrate is enabled
+	if err := app.AutoMigrate(); err != nil {
+		return err
+	}
+
+	// before destroy
+	defer app.destroy()
+
+	ctx, stop := signal.NotifyContext(app.withShutdown(context.Background()), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, s := range app.workers.schedules {
+		if err := app.Cron().AddJob(s.id, s.spec, s.job); err != nil {
+			return fmt.Errorf("failed to schedule cron job(%s): %v", s.id, err)
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, s := range app.workers.subscribers {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// the redis subscriber blocks until ctx is done, the memory one returns at once
+			if err := app.PubSub().Subscribe(ctx, s.topic, s.handler); err != nil && ctx.Err() == nil {
+				app.Logger().Errorf("[worker] failed to subscribe topic(%s): %s", s.topic, err)
+			}
+		}()
+	}
+
+	for _, w := range app.workers.workers {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.runWorker(ctx, w)
+		}()
+	}
+
+	if err := sdNotify("READY=1\nSTATUS=Worker started"); err != nil {
+		app.Logger().Warnf("[systemd] failed to notify ready: %s", err)
+	}
+	app.Logger().Infof("[worker] started (workers: %d, schedules: %d, subscribers: %d)", len(app.workers.workers), len(app.workers.schedules), len(app.workers.subscribers))
+
+	<-ctx.Done()
+	app.notifyStopping()
+	app.Logger().Infof("[worker] stopping ...")
+
+	// wait for the workers to return, within the shutdown timeout
+	timeout := app.Config.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		app.Logger().Warnf("[worker] workers are not stopped in %s", timeout)
+	}
+
+	return nil
+}
+
+// runWorker runs the worker until ctx is done, restarting it on errors and panics.
+func (app *Application) runWorker(ctx context.Context, w worker) {
+	for {
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic: %v", r)
+				}
+			}()
+
+			return w.fn(ctx)
+		}()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			app.Logger().Errorf("[worker] %s failed, restart in %s: %s", w.name, DefaultWorkerRestartDelay, err)
+		} else {
+			app.Logger().Warnf("[worker] %s returned, restart in %s", w.name, DefaultWorkerRestartDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DefaultWorkerRestartDelay):
+		}
+	}
+}
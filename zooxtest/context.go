@@ -0,0 +1,34 @@
+package zooxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-zoox/zoox"
+)
+
+// NewContext creates a fake context of the request with the handlers chain of an isolated app (zoox.NewIsolated),
+// which is used to unit-test middlewares without httptest boilerplate.
+//
+// Example:
+//
+//	req := httptest.NewRequest("GET", "/", nil)
+//	ctx, recorder := zooxtest.NewContext(req, middleware.RequestID(), func(ctx *zoox.Context) {
+//		ctx.String(200, "ok")
+//	})
+//	ctx.Next()
+//
+//	// recorder.Code == 200
+func NewContext(req *http.Request, handlers ...zoox.HandlerFunc) (*zoox.Context, *httptest.ResponseRecorder) {
+	return NewContextWithApp(zoox.NewIsolated(), req, handlers...)
+}
+
+// NewContextWithApp creates a fake context of the app.
+func NewContextWithApp(app *zoox.Application, req *http.Request, handlers ...zoox.HandlerFunc) (*zoox.Context, *httptest.ResponseRecorder) {
+	if req == nil {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+	}
+
+	recorder := httptest.NewRecorder()
+	return zoox.NewContext(app, recorder, req, handlers...), recorder
+}
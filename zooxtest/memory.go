@@ -0,0 +1,83 @@
+package zooxtest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// memoryListener is an in-memory net.Listener, connections are created with net.Pipe.
+type memoryListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+type memoryAddr struct{}
+
+func (memoryAddr) Network() string { return "memory" }
+func (memoryAddr) String() string  { return "memory" }
+
+func newMemoryListener() *memoryListener {
+	return &memoryListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return memoryAddr{}
+}
+
+// DialContext creates a connection to the listener.
+func (l *memoryListener) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+		return nil, errors.New("memory listener is closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// memoryServer serves the app over the in-memory listener, no real network is used.
+type memoryServer struct {
+	listener *memoryListener
+	server   *http.Server
+}
+
+func newMemoryServer(handler http.Handler) *memoryServer {
+	s := &memoryServer{
+		listener: newMemoryListener(),
+		server:   &http.Server{Handler: handler},
+	}
+
+	go s.server.Serve(s.listener)
+
+	return s
+}
+
+func (s *memoryServer) Close() error {
+	return s.server.Close()
+}
@@ -0,0 +1,264 @@
+package zooxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-zoox/headers"
+)
+
+// File is a file of multipart request.
+type File struct {
+	// Field is the form field name.
+	Field string
+	// Name is the file name.
+	Name string
+	// Content is the file content.
+	Content []byte
+}
+
+// Request is the fluent test request, it is sent on the first expectation or Do.
+type Request struct {
+	client *Client
+	//
+	method  string
+	path    string
+	query   url.Values
+	headers http.Header
+	body    io.Reader
+	//
+	recorder *httptest.ResponseRecorder
+	errs     []error
+}
+
+func newRequest(c *Client, method, path string) *Request {
+	r := &Request{
+		client:  c,
+		method:  method,
+		path:    path,
+		query:   url.Values{},
+		headers: c.headers.Clone(),
+	}
+
+	return r
+}
+
+// WithHeader sets the request header.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.headers.Set(key, value)
+	return r
+}
+
+// WithQuery adds the request query.
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// WithBearerToken sets the authorization header with bearer token.
+func (r *Request) WithBearerToken(token string) *Request {
+	return r.WithHeader(headers.Authorization, "Bearer "+token)
+}
+
+// WithBody sets the raw request body.
+func (r *Request) WithBody(body io.Reader, contentType string) *Request {
+	r.body = body
+	if contentType != "" {
+		r.headers.Set(headers.ContentType, contentType)
+	}
+
+	return r
+}
+
+// WithJSON sets the json request body.
+func (r *Request) WithJSON(v any) *Request {
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.fail("failed to encode json body: %v", err)
+		return r
+	}
+
+	return r.WithBody(bytes.NewReader(data), "application/json")
+}
+
+// WithForm sets the urlencoded form request body.
+func (r *Request) WithForm(values map[string]string) *Request {
+	form := url.Values{}
+	for k, v := range values {
+		form.Set(k, v)
+	}
+
+	return r.WithBody(strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+}
+
+// WithMultipart sets the multipart request body with fields and files.
+func (r *Request) WithMultipart(fields map[string]string, files ...File) *Request {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			r.fail("failed to write multipart field(%s): %v", k, err)
+			return r
+		}
+	}
+
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.Field, file.Name)
+		if err != nil {
+			r.fail("failed to create multipart file(%s): %v", file.Name, err)
+			return r
+		}
+
+		if _, err := part.Write(file.Content); err != nil {
+			r.fail("failed to write multipart file(%s): %v", file.Name, err)
+			return r
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		r.fail("failed to close multipart writer: %v", err)
+		return r
+	}
+
+	return r.WithBody(buf, writer.FormDataContentType())
+}
+
+// Do sends the request once and returns the response recorder.
+func (r *Request) Do() *httptest.ResponseRecorder {
+	if r.recorder != nil {
+		return r.recorder
+	}
+
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+
+	req := httptest.NewRequest(r.method, target, r.body)
+	req.Header = r.headers
+
+	r.recorder = httptest.NewRecorder()
+	r.client.app.ServeHTTP(r.recorder, req)
+	return r.recorder
+}
+
+// Response returns the http response.
+func (r *Request) Response() *http.Response {
+	return r.Do().Result()
+}
+
+// Body returns the response body.
+func (r *Request) Body() []byte {
+	return r.Do().Body.Bytes()
+}
+
+// BindJSON decodes the json response body into v.
+func (r *Request) BindJSON(v any) error {
+	return json.Unmarshal(r.Body(), v)
+}
+
+// ExpectStatus expects the response status code.
+func (r *Request) ExpectStatus(status int) *Request {
+	if got := r.Do().Code; got != status {
+		r.fail("%s %s: expected status %d, got %d (body: %s)", r.method, r.path, status, got, r.Body())
+	}
+
+	return r
+}
+
+// ExpectHeader expects the response header.
+func (r *Request) ExpectHeader(key, value string) *Request {
+	if got := r.Do().Header().Get(key); got != value {
+		r.fail("%s %s: expected header %s=%q, got %q", r.method, r.path, key, value, got)
+	}
+
+	return r
+}
+
+// ExpectBody expects the response body.
+func (r *Request) ExpectBody(body string) *Request {
+	if got := string(r.Body()); got != body {
+		r.fail("%s %s: expected body %q, got %q", r.method, r.path, body, got)
+	}
+
+	return r
+}
+
+// ExpectBodyContains expects the response body contains the substring.
+func (r *Request) ExpectBodyContains(substr string) *Request {
+	if got := string(r.Body()); !strings.Contains(got, substr) {
+		r.fail("%s %s: expected body contains %q, got %q", r.method, r.path, substr, got)
+	}
+
+	return r
+}
+
+// ExpectJSON expects the json response body equals v, compared after json encoding.
+func (r *Request) ExpectJSON(v any) *Request {
+	expected, err := normalizeJSON(v)
+	if err != nil {
+		r.fail("%s %s: failed to encode expected json: %v", r.method, r.path, err)
+		return r
+	}
+
+	var got any
+	if err := json.Unmarshal(r.Body(), &got); err != nil {
+		r.fail("%s %s: expected json body, got %q: %v", r.method, r.path, r.Body(), err)
+		return r
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		r.fail("%s %s: expected json %s, got %s", r.method, r.path, mustJSON(expected), r.Body())
+	}
+
+	return r
+}
+
+// Err returns the failed expectations, nil if all passed.
+func (r *Request) Err() error {
+	return errors.Join(r.errs...)
+}
+
+func (r *Request) fail(format string, args ...any) {
+	if r.client.t != nil {
+		r.client.t.Helper()
+	}
+
+	r.errs = append(r.errs, r.client.errorf(format, args...))
+}
+
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+func mustJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(data)
+}
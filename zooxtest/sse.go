@@ -0,0 +1,87 @@
+package zooxtest
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// SSEEvent is a server-sent event.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry string
+}
+
+// ParseSSE parses the server-sent events of the body.
+func ParseSSE(body []byte) []SSEEvent {
+	events := []SSEEvent{}
+	current := SSEEvent{}
+	hasField := false
+	data := []string{}
+
+	flush := func() {
+		if hasField {
+			current.Data = strings.Join(data, "\n")
+			events = append(events, current)
+		}
+
+		current = SSEEvent{}
+		hasField = false
+		data = []string{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		// comment
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		hasField = true
+		switch field {
+		case "id":
+			current.ID = value
+		case "event":
+			current.Event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			current.Retry = value
+		}
+	}
+	flush()
+
+	return events
+}
+
+// SSE returns the server-sent events of the response.
+func (r *Request) SSE() []SSEEvent {
+	return ParseSSE(r.Body())
+}
+
+// ExpectSSE expects the response contains the server-sent events in order.
+func (r *Request) ExpectSSE(events ...SSEEvent) *Request {
+	got := r.SSE()
+	if len(got) < len(events) {
+		r.fail("%s %s: expected %d events, got %d", r.method, r.path, len(events), len(got))
+		return r
+	}
+
+	for i, event := range events {
+		if got[i] != event {
+			r.fail("%s %s: expected event[%d] %+v, got %+v", r.method, r.path, i, event, got[i])
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,352 @@
+package zooxtest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultWebSocketTimeout is the default timeout of websocket expectations.
+var DefaultWebSocketTimeout = 5 * time.Second
+
+// DialWebSocket dials the websocket route of the app over an in-memory connection.
+func (c *Client) DialWebSocket(path string, header ...http.Header) (*websocket.Conn, *http.Response, error) {
+	h := c.headers.Clone()
+	if len(header) > 0 {
+		for k, v := range header[0] {
+			h[k] = v
+		}
+	}
+
+	c.memoryOnce.Do(func() {
+		c.memory = newMemoryServer(c.app)
+		if c.t != nil {
+			c.t.Cleanup(func() {
+				c.memory.Close()
+			})
+		}
+	})
+
+	dialer := &websocket.Dialer{
+		NetDialContext:   c.memory.listener.DialContext,
+		HandshakeTimeout: DefaultWebSocketTimeout,
+	}
+
+	return dialer.Dial("ws://memory"+path, h)
+}
+
+// WebSocket is the scripted websocket test session.
+//
+// Example:
+//
+//	zooxtest.New(app, t).WebSocket("/ws").
+//		SendText("hello").
+//		ExpectText("hello").
+//		Ping("x").
+//		ExpectPong("x").
+//		Close(websocket.CloseNormalClosure, "bye").
+//		ExpectClose(websocket.CloseNormalClosure)
+type WebSocket struct {
+	client *Client
+	path   string
+	conn   *websocket.Conn
+	//
+	timeout time.Duration
+	//
+	messages chan webSocketMessage
+	pings    chan string
+	pongs    chan string
+	// done is closed when the connection is closed, with closeErr
+	done     chan struct{}
+	closeErr error
+	//
+	errs []error
+}
+
+type webSocketMessage struct {
+	typ  int
+	data []byte
+}
+
+// WebSocket dials the websocket route and returns the scripted session.
+func (c *Client) WebSocket(path string, header ...http.Header) *WebSocket {
+	ws := &WebSocket{
+		client:   c,
+		path:     path,
+		timeout:  DefaultWebSocketTimeout,
+		messages: make(chan webSocketMessage, 1024),
+		pings:    make(chan string, 64),
+		pongs:    make(chan string, 64),
+		done:     make(chan struct{}),
+	}
+
+	conn, _, err := c.DialWebSocket(path, header...)
+	if err != nil {
+		ws.fail("WS %s: failed to dial: %v", path, err)
+		return ws
+	}
+	ws.conn = conn
+
+	conn.SetPingHandler(func(data string) error {
+		select {
+		case ws.pings <- data:
+		default:
+		}
+
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(ws.timeout))
+	})
+	conn.SetPongHandler(func(data string) error {
+		select {
+		case ws.pongs <- data:
+		default:
+		}
+
+		return nil
+	})
+
+	// control messages are only handled while reading
+	go ws.readLoop()
+
+	return ws
+}
+
+// Conn returns the underlying websocket connection.
+func (ws *WebSocket) Conn() *websocket.Conn {
+	return ws.conn
+}
+
+// WithTimeout sets the timeout of expectations.
+func (ws *WebSocket) WithTimeout(timeout time.Duration) *WebSocket {
+	ws.timeout = timeout
+	return ws
+}
+
+// SendText sends a text message.
+func (ws *WebSocket) SendText(text string) *WebSocket {
+	return ws.send(websocket.TextMessage, []byte(text))
+}
+
+// SendBinary sends a binary message.
+func (ws *WebSocket) SendBinary(data []byte) *WebSocket {
+	return ws.send(websocket.BinaryMessage, data)
+}
+
+// SendJSON sends a json text message.
+func (ws *WebSocket) SendJSON(v any) *WebSocket {
+	data, err := json.Marshal(v)
+	if err != nil {
+		ws.fail("WS %s: failed to encode json: %v", ws.path, err)
+		return ws
+	}
+
+	return ws.send(websocket.TextMessage, data)
+}
+
+// Ping sends a ping control message, use ExpectPong to wait for the pong.
+func (ws *WebSocket) Ping(data string) *WebSocket {
+	if ws.conn == nil {
+		return ws
+	}
+
+	if err := ws.conn.WriteControl(websocket.PingMessage, []byte(data), time.Now().Add(ws.timeout)); err != nil {
+		ws.fail("WS %s: failed to ping: %v", ws.path, err)
+	}
+
+	return ws
+}
+
+// Close sends a close control message with the code and reason.
+func (ws *WebSocket) Close(code int, reason string) *WebSocket {
+	if ws.conn == nil {
+		return ws
+	}
+
+	message := websocket.FormatCloseMessage(code, reason)
+	if err := ws.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(ws.timeout)); err != nil {
+		ws.fail("WS %s: failed to close: %v", ws.path, err)
+	}
+
+	return ws
+}
+
+// ExpectText expects the next message is the text.
+func (ws *WebSocket) ExpectText(text string) *WebSocket {
+	if message, ok := ws.next(); ok {
+		if message.typ != websocket.TextMessage || string(message.data) != text {
+			ws.fail("WS %s: expected text %q, got %q (type: %d)", ws.path, text, message.data, message.typ)
+		}
+	}
+
+	return ws
+}
+
+// ExpectBinary expects the next message is the binary data.
+func (ws *WebSocket) ExpectBinary(data []byte) *WebSocket {
+	if message, ok := ws.next(); ok {
+		if message.typ != websocket.BinaryMessage || string(message.data) != string(data) {
+			ws.fail("WS %s: expected binary %v, got %v (type: %d)", ws.path, data, message.data, message.typ)
+		}
+	}
+
+	return ws
+}
+
+// ExpectJSON expects the next message is json and equals v, compared after json encoding.
+func (ws *WebSocket) ExpectJSON(v any) *WebSocket {
+	message, ok := ws.next()
+	if !ok {
+		return ws
+	}
+
+	expected, err := normalizeJSON(v)
+	if err != nil {
+		ws.fail("WS %s: failed to encode expected json: %v", ws.path, err)
+		return ws
+	}
+
+	var got any
+	if err := json.Unmarshal(message.data, &got); err != nil {
+		ws.fail("WS %s: expected json message, got %q: %v", ws.path, message.data, err)
+		return ws
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		ws.fail("WS %s: expected json %s, got %s", ws.path, mustJSON(expected), message.data)
+	}
+
+	return ws
+}
+
+// ExpectPong waits for the pong with the data.
+func (ws *WebSocket) ExpectPong(data string) *WebSocket {
+	ws.waitControl("pong", data, ws.pongs)
+	return ws
+}
+
+// ExpectPing waits for the ping from server with the data, the pong is replied automatically.
+func (ws *WebSocket) ExpectPing(data string) *WebSocket {
+	ws.waitControl("ping", data, ws.pings)
+	return ws
+}
+
+// ExpectClose waits for the close message from server with the code.
+func (ws *WebSocket) ExpectClose(code int) *WebSocket {
+	if ws.conn == nil {
+		return ws
+	}
+
+	select {
+	case <-ws.done:
+	case <-time.After(ws.timeout):
+		ws.fail("WS %s: expected close %d, got timeout", ws.path, code)
+		return ws
+	}
+
+	var closeErr *websocket.CloseError
+	if !errors.As(ws.closeErr, &closeErr) {
+		ws.fail("WS %s: expected close %d, got %v", ws.path, code, ws.closeErr)
+		return ws
+	}
+
+	if closeErr.Code != code {
+		ws.fail("WS %s: expected close %d, got %d (%s)", ws.path, code, closeErr.Code, closeErr.Text)
+	}
+
+	return ws
+}
+
+// Err returns the failed expectations, nil if all passed.
+func (ws *WebSocket) Err() error {
+	return errors.Join(ws.errs...)
+}
+
+func (ws *WebSocket) readLoop() {
+	defer close(ws.done)
+
+	for {
+		typ, data, err := ws.conn.ReadMessage()
+		if err != nil {
+			ws.closeErr = err
+			return
+		}
+
+		ws.messages <- webSocketMessage{typ: typ, data: data}
+	}
+}
+
+func (ws *WebSocket) send(typ int, data []byte) *WebSocket {
+	if ws.conn == nil {
+		return ws
+	}
+
+	ws.conn.SetWriteDeadline(time.Now().Add(ws.timeout))
+	if err := ws.conn.WriteMessage(typ, data); err != nil {
+		ws.fail("WS %s: failed to send message: %v", ws.path, err)
+	}
+
+	return ws
+}
+
+func (ws *WebSocket) next() (message webSocketMessage, ok bool) {
+	if ws.conn == nil {
+		return message, false
+	}
+
+	select {
+	case message = <-ws.messages:
+		return message, true
+	default:
+	}
+
+	select {
+	case message = <-ws.messages:
+		return message, true
+	case <-ws.done:
+		// drain the messages received before closed
+		select {
+		case message = <-ws.messages:
+			return message, true
+		default:
+		}
+
+		ws.fail("WS %s: expected message, got closed: %v", ws.path, ws.closeErr)
+	case <-time.After(ws.timeout):
+		ws.fail("WS %s: expected message, got timeout", ws.path)
+	}
+
+	return message, false
+}
+
+func (ws *WebSocket) waitControl(kind, data string, received chan string) {
+	if ws.conn == nil {
+		return
+	}
+
+	timeout := time.After(ws.timeout)
+	for {
+		select {
+		case one := <-received:
+			if one == data {
+				return
+			}
+		case <-ws.done:
+			ws.fail("WS %s: expected %s %q, got closed: %v", ws.path, kind, data, ws.closeErr)
+			return
+		case <-timeout:
+			ws.fail("WS %s: expected %s %q, got timeout", ws.path, kind, data)
+			return
+		}
+	}
+}
+
+func (ws *WebSocket) fail(format string, args ...any) {
+	if ws.client.t != nil {
+		ws.client.t.Helper()
+	}
+
+	ws.errs = append(ws.errs, ws.client.errorf(format, args...))
+}
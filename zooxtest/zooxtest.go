@@ -0,0 +1,135 @@
+// Package zooxtest provides utilities for testing zoox applications.
+//
+// Example:
+//
+//	func TestUsers(t *testing.T) {
+//		app := zoox.New()
+//		app.Get("/users", listUsers)
+//
+//		zooxtest.New(app, t).
+//			Get("/users").
+//			WithHeader("Authorization", "Bearer token").
+//			ExpectStatus(200).
+//			ExpectJSON([]User{{ID: 1}})
+//	}
+package zooxtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+)
+
+// Client is the test client of zoox application.
+type Client struct {
+	app *zoox.Application
+	t   testing.TB
+	//
+	headers http.Header
+	//
+	server     *httptest.Server
+	serverOnce sync.Once
+	//
+	memory     *memoryServer
+	memoryOnce sync.Once
+}
+
+// New creates a test client of the app.
+//
+//	if t is given, failed expectations are reported with t.Errorf,
+//	otherwise they are collected and returned by Request.Err().
+func New(app *zoox.Application, t ...testing.TB) *Client {
+	c := &Client{
+		app:     app,
+		headers: http.Header{},
+	}
+	if len(t) > 0 {
+		c.t = t[0]
+	}
+
+	return c
+}
+
+// WithHeader sets the default header of all requests.
+func (c *Client) WithHeader(key, value string) *Client {
+	c.headers.Set(key, value)
+	return c
+}
+
+// Request creates a request with the method and path.
+func (c *Client) Request(method, path string) *Request {
+	return newRequest(c, method, path)
+}
+
+// Get creates a GET request.
+func (c *Client) Get(path string) *Request {
+	return c.Request(http.MethodGet, path)
+}
+
+// Post creates a POST request.
+func (c *Client) Post(path string) *Request {
+	return c.Request(http.MethodPost, path)
+}
+
+// Put creates a PUT request.
+func (c *Client) Put(path string) *Request {
+	return c.Request(http.MethodPut, path)
+}
+
+// Patch creates a PATCH request.
+func (c *Client) Patch(path string) *Request {
+	return c.Request(http.MethodPatch, path)
+}
+
+// Delete creates a DELETE request.
+func (c *Client) Delete(path string) *Request {
+	return c.Request(http.MethodDelete, path)
+}
+
+// Head creates a HEAD request.
+func (c *Client) Head(path string) *Request {
+	return c.Request(http.MethodHead, path)
+}
+
+// Options creates an OPTIONS request.
+func (c *Client) Options(path string) *Request {
+	return c.Request(http.MethodOptions, path)
+}
+
+// Server returns a real http server of the app, started on first use.
+// It is used by the clients which require a network connection.
+func (c *Client) Server() *httptest.Server {
+	c.serverOnce.Do(func() {
+		c.server = httptest.NewServer(c.app)
+		if c.t != nil {
+			c.t.Cleanup(c.server.Close)
+		}
+	})
+
+	return c.server
+}
+
+// Close closes the servers if started.
+func (c *Client) Close() {
+	if c.server != nil {
+		c.server.Close()
+	}
+
+	if c.memory != nil {
+		c.memory.Close()
+	}
+}
+
+func (c *Client) errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if c.t != nil {
+		c.t.Helper()
+		c.t.Errorf("%s", err)
+	}
+
+	return err
+}
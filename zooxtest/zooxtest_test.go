@@ -0,0 +1,64 @@
+package zooxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-zoox/zoox"
+)
+
+func TestClient(t *testing.T) {
+	app := zoox.New()
+	app.Get("/users/:id", func(ctx *zoox.Context) {
+		ctx.JSON(http.StatusOK, zoox.H{
+			"id":   ctx.Param().Get("id").String(),
+			"role": ctx.Query().Get("role").String(),
+		})
+	})
+
+	New(app, t).
+		Get("/users/1").
+		WithQuery("role", "admin").
+		ExpectStatus(http.StatusOK).
+		ExpectJSON(map[string]string{"id": "1", "role": "admin"})
+
+	if err := New(app).Get("/users/1").ExpectStatus(http.StatusCreated).Err(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	called := false
+	ctx, recorder := NewContext(httptest.NewRequest(http.MethodGet, "/", nil), func(ctx *zoox.Context) {
+		ctx.Set("X-Middleware", "1")
+		ctx.Next()
+	}, func(ctx *zoox.Context) {
+		called = true
+		ctx.String(http.StatusOK, "ok")
+	})
+	ctx.Next()
+
+	if !called {
+		t.Fatal("expected next handler called")
+	}
+
+	if recorder.Header().Get("X-Middleware") != "1" || recorder.Body.String() != "ok" {
+		t.Fatalf("unexpected response: %v %s", recorder.Header(), recorder.Body.String())
+	}
+}
+
+func TestParseSSE(t *testing.T) {
+	events := ParseSSE([]byte("id: 1\nevent: message\ndata: hello\ndata: world\n\n: comment\ndata: done\n\n"))
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	if events[0] != (SSEEvent{ID: "1", Event: "message", Data: "hello\nworld"}) {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+
+	if events[1].Data != "done" {
+		t.Fatalf("unexpected event: %+v", events[1])
+	}
+}